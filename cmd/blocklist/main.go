@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+const blocklistTable = "hourstats-blocklist"
+
+// parseEntry splits "kind:value", e.g. "author:spammer.bsky.social"
+func parseEntry(spec string) (string, string, error) {
+	kind, value, ok := strings.Cut(spec, ":")
+	if !ok || value == "" {
+		return "", "", fmt.Errorf("invalid entry %q, want kind:value with kind %s, %s or %s", spec, state.BlockAuthor, state.BlockDomain, state.BlockKeyword)
+	}
+	return strings.ToLower(strings.TrimSpace(kind)), value, nil
+}
+
+func main() {
+	var (
+		list   = flag.Bool("list", false, "List every blocklist entry")
+		block  = flag.String("block", "", "Block an author, domain or keyword: kind:value")
+		allow  = flag.String("allow", "", "Keep an author's or domain's posts even when they match a block: kind:value")
+		remove = flag.String("remove", "", "Remove an entry: kind:value")
+		reason = flag.String("reason", "", "Why the entry was added, shown by -list")
+	)
+	flag.Parse()
+
+	ctx := context.Background()
+	manager, err := state.NewBlocklistManager(ctx, blocklistTable)
+	if err != nil {
+		log.Fatalf("Failed to create blocklist manager: %v", err)
+	}
+
+	switch {
+	case *list:
+		listEntries(ctx, manager)
+	case *block != "":
+		addEntry(ctx, manager, *block, false, *reason)
+	case *allow != "":
+		addEntry(ctx, manager, *allow, true, *reason)
+	case *remove != "":
+		kind, value, err := parseEntry(*remove)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := manager.RemoveEntry(ctx, kind, value); err != nil {
+			log.Fatalf("Failed to remove entry: %v", err)
+		}
+		fmt.Printf("✅ Removed %s %s\n", kind, value)
+	default:
+		fmt.Println("Usage:")
+		fmt.Println("  List entries:     go run ./cmd/blocklist -list")
+		fmt.Println("  Block an entry:   go run ./cmd/blocklist -block author:spammer.bsky.social -reason \"spam ring\"")
+		fmt.Println("  Allow an entry:   go run ./cmd/blocklist -allow domain:good.substack.com")
+		fmt.Println("  Remove an entry:  go run ./cmd/blocklist -remove keyword:\"free crypto\"")
+		fmt.Println()
+		fmt.Println("Kinds: author (a handle), domain (a link card's domain and its subdomains), keyword (whole words in the post's text)")
+		os.Exit(1)
+	}
+}
+
+func addEntry(ctx context.Context, manager *state.BlocklistManager, spec string, allow bool, reason string) {
+	kind, value, err := parseEntry(spec)
+	if err != nil {
+		log.Fatal(err)
+	}
+	entry, err := manager.AddEntry(ctx, state.BlocklistEntry{Kind: kind, Value: value, Allow: allow, Reason: reason})
+	if err != nil {
+		log.Fatalf("Failed to add entry: %v", err)
+	}
+	action := "Blocked"
+	if entry.Allow {
+		action = "Allowed"
+	}
+	fmt.Printf("✅ %s %s %s\n", action, entry.Kind, entry.Value)
+}
+
+func listEntries(ctx context.Context, manager *state.BlocklistManager) {
+	entries, err := manager.ListEntries(ctx)
+	if err != nil {
+		log.Fatalf("Failed to list blocklist: %v", err)
+	}
+	if len(entries) == 0 {
+		fmt.Println("The blocklist is empty.")
+		return
+	}
+
+	fmt.Printf("%-8s %-6s %-40s %-10s %s\n", "KIND", "ACTION", "VALUE", "ADDED", "REASON")
+	for _, entry := range entries {
+		action := "block"
+		if entry.Allow {
+			action = "allow"
+		}
+		fmt.Printf("%-8s %-6s %-40s %-10s %s\n", entry.Kind, action, entry.Value, entry.AddedAt.Format("2006-01-02"), entry.Reason)
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestParseEntry(t *testing.T) {
+	kind, value, err := parseEntry("Keyword:free crypto")
+	if err != nil || kind != "keyword" || value != "free crypto" {
+		t.Errorf("parseEntry() = %q, %q, %v", kind, value, err)
+	}
+	kind, value, err = parseEntry("domain:https://example.com/a")
+	if err != nil || kind != "domain" || value != "https://example.com/a" {
+		t.Errorf("Expected only the first colon to split, got %q, %q, %v", kind, value, err)
+	}
+	for _, spec := range []string{"spammer.bsky.social", "author:"} {
+		if _, _, err := parseEntry(spec); err == nil {
+			t.Errorf("Expected parseEntry(%q) to fail", spec)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/config"
+	"github.com/christophergentle/hourstats-bsky/internal/interval"
+	"github.com/christophergentle/hourstats-bsky/internal/pipeline"
+)
+
+// runEvery runs the pipeline immediately and then once per interval until ctx is cancelled.
+// A failed run is logged and the next one goes ahead on schedule.
+func runEvery(ctx context.Context, p *pipeline.Pipeline, intervalMinutes int) error {
+	ticker := time.NewTicker(interval.Duration(intervalMinutes))
+	defer ticker.Stop()
+
+	for {
+		if err := p.RunOnce(ctx); err != nil {
+			log.Printf("Run failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func main() {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Printf("Failed to load config file: %v", err)
+		log.Println("Falling back to environment variables...")
+		cfg = config.LoadConfigFromEnv()
+		if cfg.Bluesky.Handle == "" || cfg.Bluesky.Password == "" {
+			log.Fatal("Please set BLUESKY_HANDLE and BLUESKY_PASSWORD environment variables, or create a config.yaml file")
+		}
+	}
+
+	intervalMinutes := flag.Int("interval", cfg.Settings.AnalysisIntervalMinutes, "Minutes each run analyzes and between runs, from 1 to 1440")
+	queries := flag.String("queries", "", "Comma-separated search queries to analyze instead of all public posts")
+	historyPath := flag.String("history", os.Getenv("HOURSTATS_HISTORY_FILE"), "File that keeps sentiment history across restarts")
+	once := flag.Bool("once", false, "Run a single window and exit")
+	flag.Parse()
+
+	p, err := pipeline.New(pipeline.Options{
+		Handle:          cfg.Bluesky.Handle,
+		Password:        cfg.Bluesky.Password,
+		IntervalMinutes: *intervalMinutes,
+		Queries:         client.ParseSearchQueries(*queries),
+		TopPostsCount:   cfg.Settings.TopPostsCount,
+		MinPostCount:    cfg.Settings.MinPostCount,
+		Thresholds:      cfg.Settings.SentimentThresholds,
+		DryRun:          cfg.Settings.DryRun,
+		ShadowHandle:    cfg.Shadow.Handle,
+		ShadowPassword:  cfg.Shadow.Password,
+		HistoryPath:     *historyPath,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create pipeline: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Starting Bluesky HourStats daemon as %s, analyzing every %s (dry run: %s)",
+		cfg.Bluesky.Handle, interval.Describe(*intervalMinutes), cfg.Settings.DryRun)
+
+	if *once {
+		if err := p.RunOnce(ctx); err != nil {
+			log.Fatalf("Run failed: %v", err)
+		}
+		return
+	}
+	if err := runEvery(ctx, p, *intervalMinutes); err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatalf("Daemon stopped: %v", err)
+	}
+	log.Println("Daemon stopped")
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/backup"
+)
+
+// showBackups lists the backups available for restore, newest first, and marks
+// which ones the retention policy will expire on the next nightly run
+func showBackups(ctx context.Context, bucket, prefix string, limit int) error {
+	s3Client, err := backup.NewS3Client(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	backups, err := backup.ListBackups(ctx, s3Client, prefix)
+	if err != nil {
+		return fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════════")
+	fmt.Printf("💾 Backups in s3://%s/%s\n", bucket, prefix)
+	fmt.Println("═══════════════════════════════════════════════════════════════")
+	fmt.Println()
+
+	if len(backups) == 0 {
+		fmt.Println("⚠️  No backups found")
+		return nil
+	}
+
+	_, expire := backup.DefaultRetentionPolicy.Select(backups)
+	expiring := make(map[string]bool, len(expire))
+	for _, b := range expire {
+		expiring[b.Prefix] = true
+	}
+
+	newest := backups[0].Timestamp
+	fmt.Printf("Total backups: %d (newest %s ago)\n\n", len(backups), time.Since(newest).Round(time.Minute))
+
+	for i, b := range backups {
+		if i >= limit {
+			fmt.Printf("... and %d older backups (use -limit to show more)\n", len(backups)-limit)
+			break
+		}
+
+		marker := ""
+		if expiring[b.Prefix] {
+			marker = " (expires next run)"
+		}
+
+		manifest, err := backup.ReadRemoteManifest(ctx, s3Client, b)
+		if err != nil {
+			fmt.Printf("❌ %s%s - manifest unreadable: %v\n", b.Timestamp.Format("2006-01-02 15:04 UTC"), marker, err)
+			continue
+		}
+
+		tables := make([]string, 0, len(manifest.Tables))
+		for _, t := range manifest.Tables {
+			tables = append(tables, fmt.Sprintf("%s=%d", t.TableName, t.ItemCount))
+		}
+		fmt.Printf("✅ %s%s - %d items [%s]\n", b.Timestamp.Format("2006-01-02 15:04 UTC"), marker, manifest.TotalItems, strings.Join(tables, ", "))
+		fmt.Printf("   Restore: go run ./cmd/dynamodb-restore -s3-bucket %s -s3-prefix %s\n", bucket, b.Prefix)
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	eventbridgetypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/credentials"
+	lambdapkg "github.com/christophergentle/hourstats-bsky/internal/lambda"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// healthCheckTables are the DynamoDB tables the pipeline reads and writes
+var healthCheckTables = []string{
+	"hourstats-state",
+	"hourstats-sentiment-history",
+	"hourstats-daily-sentiment",
+	"hourstats-blocklist",
+}
+
+// healthCheckRules are the EventBridge schedules that drive the pipeline
+var healthCheckRules = []string{
+	"hourstats-schedule",
+	"hourstats-daily-aggregation-schedule",
+	"hourstats-yearly-posting-schedule",
+	"hourstats-backup-schedule",
+}
+
+// checkResult is the outcome of a single health check
+type checkResult struct {
+	Name   string
+	OK     bool
+	Detail string
+}
+
+// runHealthCheck exercises every dependency of the pipeline and prints one line per check
+// Returns false if any check failed, so the caller can exit non-zero for cron or monitoring
+func runHealthCheck(ctx context.Context, stateManager *state.StateManager, maxRunAge time.Duration) bool {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		fmt.Printf("❌ Failed to load AWS config: %v\n", err)
+		return false
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════════")
+	fmt.Println("🩺 HourStats Health Check")
+	fmt.Println("═══════════════════════════════════════════════════════════════")
+
+	var results []checkResult
+
+	ssmClient := ssm.NewFromConfig(cfg)
+	results = append(results, checkSSMParameters(ctx, ssmClient, requiredParameters())...)
+	results = append(results, checkDynamoDBTables(ctx, dynamodb.NewFromConfig(cfg))...)
+	results = append(results, checkBlueskyAuth(ctx, credentials.Default(cfg, ssmClient)))
+	results = append(results, checkEventBridgeRules(ctx, eventbridge.NewFromConfig(cfg))...)
+	results = append(results, checkLastRun(ctx, stateManager, maxRunAge))
+
+	healthy := true
+	failed := 0
+	for _, result := range results {
+		icon := "✅"
+		if !result.OK {
+			icon = "❌"
+			healthy = false
+			failed++
+		}
+		fmt.Printf("  %s %-45s %s\n", icon, result.Name, result.Detail)
+	}
+
+	fmt.Println()
+	if healthy {
+		fmt.Printf("✅ Healthy: all %d checks passed\n", len(results))
+	} else {
+		fmt.Printf("❌ Unhealthy: %d of %d checks failed\n", failed, len(results))
+	}
+	return healthy
+}
+
+// requiredParameters are the SSM parameters the Lambdas require; the credential parameters
+// only when BLUESKY_SECRET_ID doesn't name a secret holding the credentials instead
+func requiredParameters() []string {
+	if os.Getenv(credentials.SecretIDEnv) != "" {
+		return lambdapkg.SettingsParameterNames
+	}
+	return lambdapkg.ParameterNames
+}
+
+// checkSSMParameters verifies every named parameter exists and is non-empty
+func checkSSMParameters(ctx context.Context, ssmClient *ssm.Client, names []string) []checkResult {
+	result, err := ssmClient.GetParameters(ctx, &ssm.GetParametersInput{
+		Names:          names,
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return []checkResult{{Name: "SSM parameters", Detail: err.Error()}}
+	}
+
+	params := make(map[string]string)
+	for _, param := range result.Parameters {
+		params[aws.ToString(param.Name)] = aws.ToString(param.Value)
+	}
+
+	var results []checkResult
+	for _, name := range names {
+		value, ok := params[name]
+		switch {
+		case !ok:
+			results = append(results, checkResult{Name: "SSM " + name, Detail: "missing"})
+		case value == "":
+			results = append(results, checkResult{Name: "SSM " + name, Detail: "empty"})
+		default:
+			results = append(results, checkResult{Name: "SSM " + name, OK: true, Detail: "present"})
+		}
+	}
+	return results
+}
+
+// checkDynamoDBTables verifies each table exists and is ACTIVE
+func checkDynamoDBTables(ctx context.Context, dynamoClient *dynamodb.Client) []checkResult {
+	var results []checkResult
+	for _, table := range healthCheckTables {
+		name := "DynamoDB " + table
+		output, err := dynamoClient.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(table)})
+		if err != nil {
+			results = append(results, checkResult{Name: name, Detail: err.Error()})
+			continue
+		}
+		status := output.Table.TableStatus
+		results = append(results, checkResult{
+			Name:   name,
+			OK:     status == dynamodbtypes.TableStatusActive,
+			Detail: string(status),
+		})
+	}
+	return results
+}
+
+// checkBlueskyAuth logs in with the credentials the Lambdas use, from the secret named by
+// BLUESKY_SECRET_ID or the SSM parameters
+func checkBlueskyAuth(ctx context.Context, provider credentials.Provider) checkResult {
+	creds, err := provider.Retrieve(ctx)
+	if err != nil {
+		return checkResult{Name: "Bluesky authentication", Detail: "credentials unavailable: " + err.Error()}
+	}
+
+	if err := client.New(creds.Handle, creds.Password).Authenticate(); err != nil {
+		return checkResult{Name: "Bluesky authentication", Detail: err.Error()}
+	}
+	return checkResult{Name: "Bluesky authentication", OK: true, Detail: "authenticated as " + creds.Handle}
+}
+
+// checkEventBridgeRules verifies each schedule exists and is ENABLED
+func checkEventBridgeRules(ctx context.Context, eventsClient *eventbridge.Client) []checkResult {
+	var results []checkResult
+	for _, rule := range healthCheckRules {
+		name := "EventBridge " + rule
+		output, err := eventsClient.DescribeRule(ctx, &eventbridge.DescribeRuleInput{Name: aws.String(rule)})
+		if err != nil {
+			results = append(results, checkResult{Name: name, Detail: err.Error()})
+			continue
+		}
+		results = append(results, checkResult{
+			Name:   name,
+			OK:     output.State == eventbridgetypes.RuleStateEnabled,
+			Detail: fmt.Sprintf("%s (%s)", output.State, aws.ToString(output.ScheduleExpression)),
+		})
+	}
+	return results
+}
+
+// checkLastRun verifies a run was started within maxRunAge
+func checkLastRun(ctx context.Context, stateManager *state.StateManager, maxRunAge time.Duration) checkResult {
+	runs, err := stateManager.GetRecentRuns(ctx, maxRunAge)
+	if err != nil {
+		return checkResult{Name: "Last run", Detail: err.Error()}
+	}
+	if len(runs) == 0 {
+		return checkResult{Name: "Last run", Detail: fmt.Sprintf("no run in the last %s", maxRunAge)}
+	}
+
+	latest := runs[0]
+	age := time.Since(latest.CreatedAt).Round(time.Minute)
+	return checkResult{
+		Name:   "Last run",
+		OK:     true,
+		Detail: fmt.Sprintf("%s started %s ago (%s)", latest.RunID, age, latest.Status),
+	}
+}
@@ -6,11 +6,11 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/christophergentle/hourstats-bsky/internal/backup"
 	"github.com/christophergentle/hourstats-bsky/internal/state"
 )
 
@@ -21,10 +21,16 @@ const (
 
 func main() {
 	var (
-		command = flag.String("cmd", "status", "Command to run: status, runs, current, errors, validate, tail, all")
-		tailFunc = flag.String("function", "", "Lambda function name for tail command (orchestrator, fetcher, processor, sparkline-poster)")
-		filter   = flag.String("filter", "all", "Filter for tail command: all, errors, success")
+		command = flag.String("cmd", "status", "Command to run: status, runs, current, errors, validate, tail, healthcheck, backups, all")
+		tailFunc = flag.String("function", "", "Lambda function name for tail command ("+strings.Join(tailFunctions, ", ")+")")
+		filter   = flag.String("filter", "all", "Filter preset for tail command: all, errors, success, timeouts, reports")
+		since    = flag.Duration("since", 10*time.Minute, "How far back the tail command starts")
+		follow   = flag.Bool("follow", true, "Keep polling for new log events in the tail command")
+		noColor  = flag.Bool("no-color", false, "Disable colorized tail output")
 		limit    = flag.Int("limit", 10, "Number of recent runs to show")
+		maxAge   = flag.Duration("max-run-age", 45*time.Minute, "Oldest acceptable last run for the healthcheck command")
+		bucket   = flag.String("backup-bucket", "hourstats-backups", "S3 bucket for the backups command")
+		prefix   = flag.String("backup-prefix", backup.DefaultS3Prefix, "S3 prefix for the backups command")
 	)
 	flag.Parse()
 
@@ -49,10 +55,29 @@ func main() {
 		validateRunCount(ctx, stateManager)
 	case "tail":
 		if *tailFunc == "" {
-			fmt.Println("Usage: go run cmd/diagnostics/main.go -cmd tail -function <orchestrator|fetcher|processor|sparkline-poster> [-filter all|errors|success]")
+			fmt.Printf("Usage: go run ./cmd/diagnostics -cmd tail -function <%s> [-filter all|errors|success|timeouts|reports] [-since 10m] [-follow=false]\n", strings.Join(tailFunctions, "|"))
+			os.Exit(1)
+		}
+		err := tailCloudWatch(ctx, tailOptions{
+			Function: *tailFunc,
+			Filter:   *filter,
+			Since:    *since,
+			Follow:   *follow,
+			Color:    !*noColor && useColor(),
+		})
+		if err != nil {
+			fmt.Printf("\n❌ Error tailing logs: %v\n", err)
+			os.Exit(1)
+		}
+	case "healthcheck":
+		if !runHealthCheck(ctx, stateManager, *maxAge) {
+			os.Exit(1)
+		}
+	case "backups":
+		if err := showBackups(ctx, *bucket, *prefix, *limit); err != nil {
+			fmt.Printf("\n❌ Error listing backups: %v\n", err)
 			os.Exit(1)
 		}
-		tailCloudWatch(*tailFunc, *filter)
 	case "all":
 		showAllDiagnostics(ctx, stateManager, *limit)
 	default:
@@ -65,7 +90,7 @@ func showUsage() {
 	fmt.Println("HourStats Diagnostics Tool")
 	fmt.Println("")
 	fmt.Println("Usage:")
-	fmt.Println("  go run cmd/diagnostics/main.go -cmd <command> [options]")
+	fmt.Println("  go run ./cmd/diagnostics -cmd <command> [options]")
 	fmt.Println("")
 	fmt.Println("Commands:")
 	fmt.Println("  status    - Show overall system status (default)")
@@ -74,17 +99,26 @@ func showUsage() {
 	fmt.Println("  errors    - Show all errors")
 	fmt.Println("  validate  - Validate run count for last 24 hours")
 	fmt.Println("  tail      - Tail CloudWatch logs (requires -function)")
+	fmt.Println("  healthcheck - Check SSM, DynamoDB, Bluesky auth, schedules, and last run; exits 1 if unhealthy")
+	fmt.Println("  backups   - List S3 backups available for restore")
 	fmt.Println("  all       - Run all diagnostics")
 	fmt.Println("")
 	fmt.Println("Options:")
 	fmt.Println("  -limit <n>       Number of recent runs to show (default: 10)")
-	fmt.Println("  -function <name> Lambda function for tail (orchestrator, fetcher, processor, sparkline-poster)")
-	fmt.Println("  -filter <type>   Filter for tail (all, errors, success) (default: all)")
+	fmt.Printf("  -function <name> Lambda function for tail (%s)\n", strings.Join(tailFunctions, ", "))
+	fmt.Println("  -filter <type>   Filter preset for tail (all, errors, success, timeouts, reports) (default: all)")
+	fmt.Println("  -since <dur>     How far back tail starts (default: 10m)")
+	fmt.Println("  -follow=false    Print matching events once instead of following")
+	fmt.Println("  -no-color        Disable colorized tail output (also NO_COLOR)")
+	fmt.Println("  -max-run-age <dur> Oldest acceptable last run for healthcheck (default: 45m)")
+	fmt.Println("  -backup-bucket <name> S3 bucket for backups (default: hourstats-backups)")
 	fmt.Println("")
 	fmt.Println("Examples:")
-	fmt.Println("  go run cmd/diagnostics/main.go -cmd status")
-	fmt.Println("  go run cmd/diagnostics/main.go -cmd runs -limit 20")
-	fmt.Println("  go run cmd/diagnostics/main.go -cmd tail -function orchestrator -filter errors")
+	fmt.Println("  go run ./cmd/diagnostics -cmd status")
+	fmt.Println("  go run ./cmd/diagnostics -cmd runs -limit 20")
+	fmt.Println("  go run ./cmd/diagnostics -cmd tail -function orchestrator -filter errors")
+	fmt.Println("  go run ./cmd/diagnostics -cmd healthcheck || alert")
+	fmt.Println("  go run ./cmd/diagnostics -cmd backups -limit 14")
 }
 
 func showStatus(ctx context.Context, stateManager *state.StateManager, limit int) {
@@ -136,10 +170,16 @@ func showRecentRuns(ctx context.Context, stateManager *state.StateManager, limit
 		created time.Time
 	}
 
+	allStats, err := stateManager.BatchGetRunStats(ctx, runIDs)
+	if err != nil {
+		fmt.Printf("❌ Failed to get run stats: %v\n", err)
+		return
+	}
+
 	var runs []runInfo
 	for _, runID := range runIDs {
-		stats, err := stateManager.GetRunStats(ctx, runID)
-		if err != nil {
+		stats, ok := allStats[runID]
+		if !ok {
 			continue
 		}
 		runs = append(runs, runInfo{
@@ -199,7 +239,7 @@ func showCurrentRunState(ctx context.Context, stateManager *state.StateManager)
 	runID := runIDs[0]
 	
 	// Get stats for overview
-	stats, err := stateManager.GetRunStats(ctx, runID)
+	stats, err := stateManager.GetRunStats(ctx, runID, state.EventuallyConsistent)
 	if err != nil {
 		fmt.Printf("❌ Failed to get run stats: %v\n", err)
 		return
@@ -219,7 +259,7 @@ func showCurrentRunState(ctx context.Context, stateManager *state.StateManager)
 	fmt.Println("Step Status:")
 	fmt.Println("───────────────────────────────────────────────────────────────")
 	for _, step := range steps {
-		runState, err := stateManager.GetRun(ctx, runID, step)
+		runState, err := stateManager.GetRun(ctx, runID, step, state.EventuallyConsistent)
 		if err != nil {
 			fmt.Printf("  %-15s %s Not found\n", step+":", "❌")
 			continue
@@ -244,6 +284,30 @@ func showCurrentRunState(ctx context.Context, stateManager *state.StateManager)
 	}
 	fmt.Printf("Posts Retrieved: %d\n", stats.TotalPostsRetrieved)
 	fmt.Printf("Top Posts: %d\n", stats.TopPostsCount)
+
+	if len(stats.StepTimings) > 0 {
+		fmt.Println()
+		fmt.Println("Step Timeline:")
+		fmt.Println("───────────────────────────────────────────────────────────────")
+		slowest := stats.StepTimings[0]
+		for _, timing := range stats.StepTimings {
+			if timing.DurationMs > slowest.DurationMs {
+				slowest = timing
+			}
+		}
+		for _, timing := range stats.StepTimings {
+			fmt.Printf("  %-15s %s %s → %s (%s)",
+				timing.Step+":",
+				getStatusIcon(timing.Status),
+				timing.StartedAt.Local().Format("15:04:05"),
+				timing.EndedAt.Local().Format("15:04:05"),
+				timing.Duration().Round(time.Second))
+			if timing.Step == slowest.Step && len(stats.StepTimings) > 1 {
+				fmt.Printf(" ← slowest")
+			}
+			fmt.Println()
+		}
+	}
 }
 
 func detectErrors(ctx context.Context, stateManager *state.StateManager, limit int) {
@@ -266,7 +330,7 @@ func detectErrors(ctx context.Context, stateManager *state.StateManager, limit i
 
 	for _, runID := range runIDs {
 		for _, step := range steps {
-			runState, err := stateManager.GetRun(ctx, runID, step)
+			runState, err := stateManager.GetRun(ctx, runID, step, state.EventuallyConsistent)
 			if err != nil {
 				continue
 			}
@@ -330,11 +394,17 @@ func validateRunCount(ctx context.Context, stateManager *state.StateManager) {
 	}
 
 	// Filter runs from last 24 hours
+	allStats, err := stateManager.BatchGetRunStats(ctx, runIDs)
+	if err != nil {
+		fmt.Printf("❌ Failed to get run stats: %v\n", err)
+		return
+	}
+
 	var recentRuns []string
 	var runTimes []time.Time
 	for _, runID := range runIDs {
-		stats, err := stateManager.GetRunStats(ctx, runID)
-		if err != nil {
+		stats, ok := allStats[runID]
+		if !ok {
 			continue
 		}
 		if stats.CreatedAt.After(twentyFourHoursAgo) {
@@ -390,59 +460,12 @@ func validateRunCount(ctx context.Context, stateManager *state.StateManager) {
 	}
 }
 
-func tailCloudWatch(functionName, filter string) {
-	logGroup := fmt.Sprintf("/aws/lambda/hourstats-%s", functionName)
-	
-	// Validate function name
-	validFunctions := map[string]bool{
-		"orchestrator":     true,
-		"fetcher":          true,
-		"processor":        true,
-		"sparkline-poster": true,
-	}
-	
-	if !validFunctions[functionName] {
-		fmt.Printf("❌ Invalid function name: %s\n", functionName)
-		fmt.Println("Valid functions: orchestrator, fetcher, processor, sparkline-poster")
-		os.Exit(1)
-	}
-
-	// Build AWS CLI command
-	args := []string{
-		"logs", "tail", logGroup,
-		"--follow",
-		"--format", "short",
-		"--region", region,
-	}
-
-	// Add filter if specified
-	if filter == "errors" {
-		args = append(args, "--filter-pattern", "ERROR OR Failed OR error OR timeout")
-	} else if filter == "success" {
-		args = append(args, "--filter-pattern", "Successfully OR Posted OR Completed")
-	}
-
-	fmt.Printf("Tailing CloudWatch logs for: %s\n", logGroup)
-	fmt.Printf("Filter: %s\n", filter)
-	fmt.Println("Press Ctrl+C to stop")
-	fmt.Println("───────────────────────────────────────────────────────────────")
-	
-	cmd := exec.Command("aws", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	
-	if err := cmd.Run(); err != nil {
-		fmt.Printf("\n❌ Error tailing logs: %v\n", err)
-		os.Exit(1)
-	}
-}
-
 func showAllDiagnostics(ctx context.Context, stateManager *state.StateManager, limit int) {
 	showStatus(ctx, stateManager, limit)
 	fmt.Println()
 	fmt.Println("═══════════════════════════════════════════════════════════════")
 	fmt.Println("For detailed CloudWatch logs, use:")
-	fmt.Println("  go run cmd/diagnostics/main.go -cmd tail -function <name>")
+	fmt.Println("  go run ./cmd/diagnostics -cmd tail -function <name>")
 	fmt.Println("═══════════════════════════════════════════════════════════════")
 }
 
@@ -454,8 +477,10 @@ func getStatusIcon(status string) string {
 		return "✅"
 	case "failed", "error":
 		return "❌"
-	case "in-progress", "processing", "fetching":
+	case "in-progress", "processing", "fetching", "running":
 		return "🔄"
+	case "skipped":
+		return "⏭️"
 	default:
 		return "⏳"
 	}
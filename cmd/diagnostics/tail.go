@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// tailPollInterval is how often new events are requested while following
+const tailPollInterval = 2 * time.Second
+
+// tailPresets maps -filter names to CloudWatch Logs filter patterns
+var tailPresets = map[string]string{
+	"all":      "",
+	"errors":   `?ERROR ?Error ?error ?Failed ?failed ?timeout ?"Task timed out"`,
+	"success":  `?Successfully ?Posted ?Completed`,
+	"timeouts": `?timeout ?"Task timed out" ?"context deadline exceeded"`,
+	"reports":  `REPORT`,
+}
+
+// tailFunctions are the Lambda functions whose logs can be tailed, named without their
+// hourstats- prefix; every function Terraform deploys must be listed
+var tailFunctions = []string{
+	"orchestrator", "fetcher", "processor", "sparkline-poster",
+	"daily-aggregator", "yearly-poster", "selfstats", "api", "backup", "credential-rotation",
+	"staging-orchestrator", "staging-fetcher", "staging-processor", "staging-sparkline-poster",
+}
+
+// ANSI colors used for tail output
+const (
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorDim    = "\033[2m"
+)
+
+// tailOptions configures a log tail
+type tailOptions struct {
+	Function string
+	Filter   string // Preset name from tailPresets
+	Since    time.Duration
+	Follow   bool
+	Color    bool
+}
+
+// tailCloudWatch prints log events for a Lambda function using the CloudWatch Logs API,
+// polling for new events until interrupted when following
+func tailCloudWatch(ctx context.Context, opts tailOptions) error {
+	if !isTailFunction(opts.Function) {
+		return fmt.Errorf("invalid function name: %s (valid: %s)", opts.Function, strings.Join(tailFunctions, ", "))
+	}
+
+	pattern, ok := tailPresets[opts.Filter]
+	if !ok {
+		return fmt.Errorf("invalid filter: %s (valid: %s)", opts.Filter, strings.Join(presetNames(), ", "))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	logsClient := cloudwatchlogs.NewFromConfig(cfg)
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	logGroup := fmt.Sprintf("/aws/lambda/hourstats-%s", opts.Function)
+	fmt.Printf("Tailing CloudWatch logs for: %s\n", logGroup)
+	fmt.Printf("Filter: %s\n", opts.Filter)
+	if opts.Follow {
+		fmt.Println("Press Ctrl+C to stop")
+	}
+	fmt.Println("───────────────────────────────────────────────────────────────")
+
+	startTime := time.Now().Add(-opts.Since).UnixMilli()
+	seen := make(map[string]bool)
+
+	for {
+		events, err := filterLogEvents(ctx, logsClient, logGroup, pattern, startTime)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil // Interrupted
+			}
+			return err
+		}
+
+		for _, event := range events {
+			eventID := aws.ToString(event.EventId)
+			if seen[eventID] {
+				continue
+			}
+			seen[eventID] = true
+			fmt.Println(formatLogEvent(event, opts.Color))
+
+			// Events can share a millisecond, so the next poll starts at the last timestamp
+			// rather than after it and relies on seen to skip repeats
+			if ts := aws.ToInt64(event.Timestamp); ts > startTime {
+				startTime = ts
+				for id := range seen {
+					delete(seen, id)
+				}
+				seen[eventID] = true
+			}
+		}
+
+		if !opts.Follow {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(tailPollInterval):
+		}
+	}
+}
+
+// filterLogEvents returns every matching event since startTime, across all pages, oldest first
+func filterLogEvents(ctx context.Context, logsClient *cloudwatchlogs.Client, logGroup, pattern string, startTime int64) ([]types.FilteredLogEvent, error) {
+	input := &cloudwatchlogs.FilterLogEventsInput{
+		LogGroupName: aws.String(logGroup),
+		StartTime:    aws.Int64(startTime),
+	}
+	if pattern != "" {
+		input.FilterPattern = aws.String(pattern)
+	}
+
+	var events []types.FilteredLogEvent
+	paginator := cloudwatchlogs.NewFilterLogEventsPaginator(logsClient, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to filter log events: %w", err)
+		}
+		events = append(events, page.Events...)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return aws.ToInt64(events[i].Timestamp) < aws.ToInt64(events[j].Timestamp)
+	})
+	return events, nil
+}
+
+// formatLogEvent renders an event as "HH:MM:SS message", colored by what the message reports
+func formatLogEvent(event types.FilteredLogEvent, color bool) string {
+	timestamp := time.UnixMilli(aws.ToInt64(event.Timestamp)).Local().Format("15:04:05")
+	message := strings.TrimRight(aws.ToString(event.Message), "\n")
+	line := fmt.Sprintf("%s %s", timestamp, message)
+
+	if !color {
+		return line
+	}
+	if c := logColor(message); c != "" {
+		return c + line + colorReset
+	}
+	return line
+}
+
+// logColor picks a color for a log message: red for errors, yellow for warnings,
+// green for success, dim for Lambda runtime START/END/REPORT lines
+func logColor(message string) string {
+	switch {
+	case strings.HasPrefix(message, "START RequestId") || strings.HasPrefix(message, "END RequestId") || strings.HasPrefix(message, "REPORT RequestId"):
+		return colorDim
+	case containsAny(message, "ERROR", "Error", "error", "Failed", "failed", "panic", "Task timed out", "❌"):
+		return colorRed
+	case containsAny(message, "WARNING", "Warning", "⚠️"):
+		return colorYellow
+	case containsAny(message, "Successfully", "successfully", "Posted", "Completed", "✅"):
+		return colorGreen
+	default:
+		return ""
+	}
+}
+
+// useColor reports whether stdout is a terminal and NO_COLOR is unset
+func useColor() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func containsAny(s string, substrings ...string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+func isTailFunction(name string) bool {
+	for _, fn := range tailFunctions {
+		if fn == name {
+			return true
+		}
+	}
+	return false
+}
+
+func presetNames() []string {
+	names := make([]string, 0, len(tailPresets))
+	for name := range tailPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+func TestLogColor(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected string
+	}{
+		{"REPORT RequestId: abc Duration: 1200.00 ms", colorDim},
+		{"Failed to fetch posts: timeout", colorRed},
+		{"⚠️  WARNING: Post is close to Bluesky limit", colorYellow},
+		{"✅ FETCHER: Processor dispatched successfully", colorGreen},
+		{"Analyzing 4200 posts", ""},
+	}
+
+	for _, tt := range tests {
+		if got := logColor(tt.message); got != tt.expected {
+			t.Errorf("logColor(%q) = %q, expected %q", tt.message, got, tt.expected)
+		}
+	}
+}
+
+func TestFormatLogEvent(t *testing.T) {
+	ts := time.Date(2025, 1, 5, 12, 30, 15, 0, time.Local)
+	event := types.FilteredLogEvent{
+		Timestamp: aws.Int64(ts.UnixMilli()),
+		Message:   aws.String("Failed to authenticate\n"),
+	}
+
+	if got := formatLogEvent(event, false); got != "12:30:15 Failed to authenticate" {
+		t.Errorf("Unexpected plain output: %q", got)
+	}
+	if got := formatLogEvent(event, true); got != colorRed+"12:30:15 Failed to authenticate"+colorReset {
+		t.Errorf("Unexpected colored output: %q", got)
+	}
+}
+
+func TestTailPresetsCoverAdvertisedFilters(t *testing.T) {
+	for _, name := range []string{"all", "errors", "success", "timeouts", "reports"} {
+		if _, ok := tailPresets[name]; !ok {
+			t.Errorf("Missing tail preset %q", name)
+		}
+	}
+}
+
+func TestTailFunctionsCoverTerraform(t *testing.T) {
+	files, err := filepath.Glob("../../terraform/*.tf")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Failed to find the Terraform files: %v", err)
+	}
+
+	functionName := regexp.MustCompile(`function_name\s*=\s*"hourstats-([a-z-]+)"`)
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		for _, match := range functionName.FindAllStringSubmatch(string(content), -1) {
+			if !isTailFunction(match[1]) {
+				t.Errorf("%s deploys hourstats-%s, which tail doesn't list", filepath.Base(file), match[1])
+			}
+		}
+	}
+}
@@ -21,6 +21,8 @@ func main() {
 		clearFirst = flag.Bool("clear-first", false, "Clear table before restore (WARNING: this deletes existing data)")
 		dryRun     = flag.Bool("dry-run", false, "Dry run mode - show what would be restored without actually restoring")
 		verbose    = flag.Bool("verbose", false, "Enable verbose logging")
+		verify     = flag.Bool("verify", false, "Verify the backup against the live tables and report drift without writing anything")
+		target     = flag.String("target-table", "", "Table to verify against instead of the backed-up table (requires a single table in --tables)")
 	)
 	flag.Parse()
 
@@ -47,6 +49,17 @@ func main() {
 		}
 	}
 
+	if *verify {
+		runVerify(ctx, backup.VerifyOptions{
+			InputPath:   *inputPath,
+			S3Bucket:    *s3Bucket,
+			S3Prefix:    *s3Prefix,
+			Tables:      tables,
+			TargetTable: *target,
+		})
+		return
+	}
+
 	if *dryRun {
 		fmt.Println("DRY RUN MODE - No changes will be made")
 		fmt.Println()
@@ -115,3 +128,41 @@ func main() {
 	}
 }
 
+
+// runVerify prints a verification report and exits 1 if the backup is not safe to restore
+func runVerify(ctx context.Context, options backup.VerifyOptions) {
+	fmt.Println("VERIFY MODE - No changes will be made")
+	fmt.Println()
+
+	result, err := backup.Verify(ctx, options)
+	if err != nil {
+		log.Fatalf("Verify failed: %v", err)
+	}
+
+	fmt.Printf("Backup %s (format %s)\n\n", result.BackupTimestamp, result.BackupVersion)
+	for _, t := range result.Tables {
+		status := "OK"
+		if !t.Valid() {
+			status = "INVALID"
+		} else if t.Drifted() {
+			status = "DRIFTED"
+		}
+
+		fmt.Printf("%s -> %s: %s\n", t.TableName, t.TargetTable, status)
+		fmt.Printf("  Items: manifest %d, backup %d, live %d\n", t.ManifestItems, t.BackupItems, t.LiveItems)
+		if t.Drifted() {
+			fmt.Printf("  Drift: %d backed-up items missing from live, %d live items not in backup\n", t.MissingInLive, t.NewInLive)
+		}
+		for _, problem := range t.Problems {
+			fmt.Printf("  - %s\n", problem)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("Duration: %v\n", result.Duration.Round(time.Second))
+
+	if !result.Valid() {
+		fmt.Println("Backup failed verification - do not restore from it")
+		os.Exit(1)
+	}
+	fmt.Println("Backup verified")
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/analyzer"
+	"github.com/christophergentle/hourstats-bsky/internal/sparkline"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// postRow is one analyzed post before anonymization
+type postRow struct {
+	RunID         string
+	WindowStart   time.Time
+	WindowMinutes int
+	CreatedAt     time.Time
+	Language      string
+	Sentiment     string
+	CompoundScore float64
+	Likes         int
+	Reposts       int
+	Replies       int
+	Author        string // Only used to derive a pseudonym, never written as-is
+}
+
+func main() {
+	var (
+		level     = flag.String("level", "posts", "Dataset level: posts (one row per post, stored posts only last 48h) or runs (one row per run from sentiment history)")
+		since     = flag.Duration("since", 48*time.Hour, "Export runs created within this duration")
+		runID     = flag.String("run", "", "Export a single run (posts level only)")
+		rulesPath = flag.String("rules", "", "JSON file of anonymization rules (default: 1h timestamps, no authors, languages under 20 posts grouped)")
+		outPath   = flag.String("out", "", "Output CSV file (default: stdout)")
+
+		chartPath  = flag.String("chart", "", "Also write a PNG chart of daily average sentiment to this file")
+		chartFrom  = flag.String("chart-from", "", "First day of the chart, YYYY-MM-DD (default: -since ago)")
+		chartTo    = flag.String("chart-to", "", "Day after the last day of the chart, YYYY-MM-DD (default: tomorrow)")
+		resolution = flag.String("resolution", "daily", "Chart resolution: daily, or weekly to average each week")
+	)
+	flag.Parse()
+
+	rules, err := LoadRules(*rulesPath)
+	if err != nil {
+		log.Fatalf("Invalid anonymization rules: %v", err)
+	}
+
+	out := io.Writer(os.Stdout)
+	if *outPath != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			log.Fatalf("Failed to create output file: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	ctx := context.Background()
+
+	if *chartPath != "" {
+		chartRange, err := parseChartRange(*chartFrom, *chartTo, *resolution, *since, time.Now().UTC())
+		if err != nil {
+			log.Fatalf("Invalid chart range: %v", err)
+		}
+		if err := writeChart(ctx, *chartPath, chartRange); err != nil {
+			log.Fatalf("Failed to write chart: %v", err)
+		}
+		log.Printf("Wrote %s chart of %s to %s to %s", chartRange.Resolution,
+			chartRange.Start.Format(dateLayout), chartRange.End.AddDate(0, 0, -1).Format(dateLayout), *chartPath)
+	}
+
+	switch *level {
+	case "posts":
+		stateManager, err := state.NewStateManager(ctx, "hourstats-state")
+		if err != nil {
+			log.Fatalf("Failed to create state manager: %v", err)
+		}
+		rows, err := collectPostRows(ctx, stateManager, *runID, *since)
+		if err != nil {
+			log.Fatalf("Failed to collect posts: %v", err)
+		}
+		if err := writePostsCSV(out, rules, rows); err != nil {
+			log.Fatalf("Failed to write dataset: %v", err)
+		}
+		log.Printf("Exported %d posts", len(rows))
+	case "runs":
+		historyManager, err := state.NewSentimentHistoryManager(ctx, "hourstats-sentiment-history")
+		if err != nil {
+			log.Fatalf("Failed to create sentiment history manager: %v", err)
+		}
+		points, err := historyManager.GetSentimentHistory(ctx, *since)
+		if err != nil {
+			log.Fatalf("Failed to read sentiment history: %v", err)
+		}
+		if err := writeRunsCSV(out, rules, points); err != nil {
+			log.Fatalf("Failed to write dataset: %v", err)
+		}
+		log.Printf("Exported %d runs", len(points))
+	default:
+		fmt.Println("Usage: go run ./cmd/export-dataset [-level posts|runs] [-since 48h] [-run <runID>] [-rules rules.json] [-out dataset.csv]")
+		fmt.Println("         [-chart chart.png [-chart-from 2025-01-01] [-chart-to 2025-04-01] [-resolution daily|weekly]]")
+		os.Exit(1)
+	}
+}
+
+// dateLayout is the format of the chart's -chart-from and -chart-to days
+const dateLayout = "2006-01-02"
+
+// parseChartRange reads the chart flags into a range of whole UTC days, defaulting to the
+// days since since ago through today
+func parseChartRange(from, to, resolution string, since time.Duration, now time.Time) (sparkline.ChartRange, error) {
+	chartRange := sparkline.ChartRange{
+		Start:      truncateDay(now.Add(-since)),
+		End:        truncateDay(now).AddDate(0, 0, 1),
+		Resolution: sparkline.Resolution(resolution),
+	}
+	var err error
+	if from != "" {
+		if chartRange.Start, err = time.Parse(dateLayout, from); err != nil {
+			return chartRange, fmt.Errorf("invalid -chart-from: %w", err)
+		}
+	}
+	if to != "" {
+		if chartRange.End, err = time.Parse(dateLayout, to); err != nil {
+			return chartRange, fmt.Errorf("invalid -chart-to: %w", err)
+		}
+	}
+	return chartRange, chartRange.Validate()
+}
+
+// truncateDay returns midnight UTC on t's day
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// writeChart renders the daily sentiment within chartRange to a PNG file
+func writeChart(ctx context.Context, path string, chartRange sparkline.ChartRange) error {
+	dailyManager, err := state.NewDailySentimentManager(ctx, "hourstats-daily-sentiment")
+	if err != nil {
+		return fmt.Errorf("failed to create daily sentiment manager: %w", err)
+	}
+	days, err := dailyManager.GetSentimentDataBetween(ctx, chartRange.Start, chartRange.End)
+	if err != nil {
+		return err
+	}
+	image, err := sparkline.NewRangeChartGenerator(nil, "").GenerateRangeChart(days, chartRange)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, image, 0o644)
+}
+
+// collectPostRows loads and scores the stored posts of each run
+func collectPostRows(ctx context.Context, stateManager *state.StateManager, runID string, since time.Duration) ([]postRow, error) {
+	var runs []state.RunState
+	if runID != "" {
+		run, err := stateManager.GetLatestRun(ctx, runID)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, *run)
+	} else {
+		recent, err := stateManager.GetRecentRuns(ctx, since)
+		if err != nil {
+			return nil, err
+		}
+		runs = recent
+	}
+
+	sentimentAnalyzer := analyzer.New()
+	var rows []postRow
+	for _, run := range runs {
+		posts, err := stateManager.GetAllPosts(ctx, run.RunID, state.EventuallyConsistent)
+		if err != nil {
+			log.Printf("Skipping run %s: %v", run.RunID, err)
+			continue
+		}
+
+		// Same selection the processor scores: unique posts inside the window
+		seen := make(map[string]bool, len(posts))
+		var analyzerPosts []analyzer.Post
+		var langs [][]string
+		for _, post := range posts {
+			if seen[post.URI] {
+				continue
+			}
+			seen[post.URI] = true
+			if createdAt, err := time.Parse(time.RFC3339, post.CreatedAt); err == nil && createdAt.Before(run.CutoffTime) {
+				continue
+			}
+			analyzerPosts = append(analyzerPosts, analyzer.Post{
+				URI:       post.URI,
+				Text:      post.Text,
+				Author:    post.Author,
+				Likes:     post.Likes,
+				Reposts:   post.Reposts,
+				Replies:   post.Replies,
+				CreatedAt: post.CreatedAt,
+				Langs:     post.Langs,
+			})
+			langs = append(langs, post.Langs)
+		}
+
+		analyzed, err := sentimentAnalyzer.AnalyzePosts(analyzerPosts)
+		if err != nil {
+			log.Printf("Skipping run %s: %v", run.RunID, err)
+			continue
+		}
+
+		for i, post := range analyzed {
+			createdAt, _ := time.Parse(time.RFC3339, post.CreatedAt)
+			rows = append(rows, postRow{
+				RunID:         run.RunID,
+				WindowStart:   run.CutoffTime,
+				WindowMinutes: run.AnalysisIntervalMinutes,
+				CreatedAt:     createdAt,
+				Language:      primaryLanguage(langs[i]),
+				Sentiment:     post.Sentiment,
+				CompoundScore: post.SentimentScore,
+				Likes:         post.Likes,
+				Reposts:       post.Reposts,
+				Replies:       post.Replies,
+				Author:        post.Author,
+			})
+		}
+		log.Printf("Run %s: %d posts", run.RunID, len(analyzed))
+	}
+
+	return rows, nil
+}
+
+// writePostsCSV writes one anonymized row per post; text and handles are never written
+func writePostsCSV(w io.Writer, rules Rules, rows []postRow) error {
+	primary := make([]string, len(rows))
+	for i, row := range rows {
+		primary[i] = row.Language
+	}
+	languages := rules.Languages(primary)
+
+	header := []string{"window_start", "window_minutes", "created_at", "language", "sentiment", "compound_score", "likes", "reposts", "replies", "engagement"}
+	if rules.IncludeRunID {
+		header = append([]string{"run_id"}, header...)
+	}
+	if rules.AuthorMode == authorPseudonym {
+		header = append(header, "author_pseudonym")
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := []string{
+			rules.Timestamp(row.WindowStart),
+			strconv.Itoa(row.WindowMinutes),
+			rules.Timestamp(row.CreatedAt),
+			languages[row.Language],
+			row.Sentiment,
+			strconv.FormatFloat(row.CompoundScore, 'f', 4, 64),
+			rules.Count(row.Likes),
+			rules.Count(row.Reposts),
+			rules.Count(row.Replies),
+			rules.Count(row.Likes + row.Reposts + row.Replies),
+		}
+		if rules.IncludeRunID {
+			record = append([]string{row.RunID}, record...)
+		}
+		if rules.AuthorMode == authorPseudonym {
+			record = append(record, rules.Author(row.Author))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeRunsCSV writes one row per run from the long-lived sentiment history. Runs stored
+// before sentiment counts or weighted sentiment were recorded leave those columns empty.
+func writeRunsCSV(w io.Writer, rules Rules, points []state.SentimentDataPoint) error {
+	header := []string{"timestamp", "average_compound_score", "net_sentiment_percent", "sentiment_category", "total_posts",
+		"positive_posts", "neutral_posts", "negative_posts", "weighted_net_sentiment_percent"}
+	if rules.IncludeRunID {
+		header = append([]string{"run_id"}, header...)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, point := range points {
+		record := []string{
+			rules.Timestamp(point.Timestamp),
+			strconv.FormatFloat(point.AverageCompoundScore, 'f', 4, 64),
+			strconv.FormatFloat(point.NetSentimentPercent, 'f', 2, 64),
+			point.SentimentCategory,
+			strconv.Itoa(point.TotalPosts),
+		}
+		if counts := point.Counts; counts != nil {
+			record = append(record, strconv.Itoa(counts.Positive), strconv.Itoa(counts.Neutral), strconv.Itoa(counts.Negative))
+		} else {
+			record = append(record, "", "", "")
+		}
+		if weighted := point.WeightedNetSentimentPercent; weighted != nil {
+			record = append(record, strconv.FormatFloat(*weighted, 'f', 2, 64))
+		} else {
+			record = append(record, "")
+		}
+		if rules.IncludeRunID {
+			record = append([]string{point.RunID}, record...)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/sparkline"
+)
+
+func TestParseChartRange(t *testing.T) {
+	now := time.Date(2025, 9, 10, 15, 30, 0, 0, time.UTC)
+
+	chartRange, err := parseChartRange("", "", "weekly", 72*time.Hour, now)
+	if err != nil {
+		t.Fatalf("parseChartRange() error = %v", err)
+	}
+	if want := time.Date(2025, 9, 7, 0, 0, 0, 0, time.UTC); !chartRange.Start.Equal(want) {
+		t.Errorf("Start = %v, want %v", chartRange.Start, want)
+	}
+	if want := time.Date(2025, 9, 11, 0, 0, 0, 0, time.UTC); !chartRange.End.Equal(want) {
+		t.Errorf("End = %v, want %v", chartRange.End, want)
+	}
+	if chartRange.Resolution != sparkline.ResolutionWeekly {
+		t.Errorf("Resolution = %q, want weekly", chartRange.Resolution)
+	}
+
+	chartRange, err = parseChartRange("2025-01-01", "2025-04-01", "daily", 48*time.Hour, now)
+	if err != nil || chartRange.Start.Month() != time.January || chartRange.End.Month() != time.April {
+		t.Errorf("parseChartRange() = %+v, %v, want Q1 2025", chartRange, err)
+	}
+
+	for name, args := range map[string][3]string{
+		"bad date":       {"2025-13-01", "", "daily"},
+		"empty range":    {"2025-04-01", "2025-04-01", "daily"},
+		"bad resolution": {"", "", "hourly"},
+	} {
+		if _, err := parseChartRange(args[0], args[1], args[2], 48*time.Hour, now); err == nil {
+			t.Errorf("%s: parseChartRange(%q) succeeded, want error", name, args)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Author handling modes
+const (
+	authorDrop      = "drop"      // No author column at all
+	authorPseudonym = "pseudonym" // Salted HMAC of the handle, stable within one salt
+)
+
+// otherLanguage replaces language tags too rare to publish without singling out authors
+const otherLanguage = "other"
+
+// Rules configures how stored posts are anonymized before export
+// Post text and handles are never exported; these rules govern the remaining quasi-identifiers
+type Rules struct {
+	// TimePrecision truncates every timestamp, e.g. "1h" or "15m"
+	TimePrecision string `json:"timePrecision"`
+	// AuthorMode is "drop" (default) or "pseudonym"
+	AuthorMode string `json:"authorMode"`
+	// Salt keys author pseudonyms; read from EXPORT_SALT when empty so it stays out of rules files
+	Salt string `json:"salt,omitempty"`
+	// MinLanguageCount groups languages seen on fewer posts than this into "other"
+	MinLanguageCount int `json:"minLanguageCount"`
+	// BucketEngagement replaces exact like/repost/reply counts with ranges (0, 1-9, 10-99, ...)
+	BucketEngagement bool `json:"bucketEngagement"`
+	// IncludeRunID keeps the pipeline run ID so rows can be grouped by window
+	IncludeRunID bool `json:"includeRunId"`
+
+	precision time.Duration
+}
+
+// DefaultRules are used when no rules file is given
+var DefaultRules = Rules{
+	TimePrecision:    "1h",
+	AuthorMode:       authorDrop,
+	MinLanguageCount: 20,
+	IncludeRunID:     true,
+}
+
+// LoadRules reads rules from a JSON file, with unset fields taking their defaults
+func LoadRules(path string) (Rules, error) {
+	rules := DefaultRules
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Rules{}, fmt.Errorf("failed to read rules: %w", err)
+		}
+		if err := json.Unmarshal(data, &rules); err != nil {
+			return Rules{}, fmt.Errorf("failed to parse rules: %w", err)
+		}
+	}
+
+	if rules.Salt == "" {
+		rules.Salt = os.Getenv("EXPORT_SALT")
+	}
+	return rules, rules.validate()
+}
+
+// validate checks the rules and resolves derived settings
+func (r *Rules) validate() error {
+	precision, err := time.ParseDuration(r.TimePrecision)
+	if err != nil || precision <= 0 {
+		return fmt.Errorf("invalid timePrecision %q", r.TimePrecision)
+	}
+	r.precision = precision
+
+	switch r.AuthorMode {
+	case authorDrop:
+	case authorPseudonym:
+		if len(r.Salt) < 16 {
+			return fmt.Errorf("authorMode %q needs a salt of at least 16 characters (set EXPORT_SALT)", authorPseudonym)
+		}
+	default:
+		return fmt.Errorf("invalid authorMode %q (valid: %s, %s)", r.AuthorMode, authorDrop, authorPseudonym)
+	}
+
+	if r.MinLanguageCount < 0 {
+		return fmt.Errorf("minLanguageCount must not be negative")
+	}
+	return nil
+}
+
+// Timestamp truncates t to the configured precision
+func (r Rules) Timestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Truncate(r.precision).Format(time.RFC3339)
+}
+
+// Author returns the pseudonym for a handle
+func (r Rules) Author(handle string) string {
+	mac := hmac.New(sha256.New, []byte(r.Salt))
+	mac.Write([]byte(strings.ToLower(handle)))
+	return hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// Count renders an engagement count, bucketed when configured
+func (r Rules) Count(n int) string {
+	if !r.BucketEngagement {
+		return strconv.Itoa(n)
+	}
+	switch {
+	case n <= 0:
+		return "0"
+	case n < 10:
+		return "1-9"
+	case n < 100:
+		return "10-99"
+	case n < 1000:
+		return "100-999"
+	default:
+		return "1000+"
+	}
+}
+
+// Languages maps each post's primary language to itself or "other" when it appears
+// on fewer than MinLanguageCount posts in the export
+func (r Rules) Languages(primary []string) map[string]string {
+	counts := make(map[string]int)
+	for _, lang := range primary {
+		counts[lang]++
+	}
+
+	mapping := make(map[string]string, len(counts))
+	for lang, count := range counts {
+		if lang == "" || count >= r.MinLanguageCount {
+			mapping[lang] = lang
+		} else {
+			mapping[lang] = otherLanguage
+		}
+	}
+	return mapping
+}
+
+// primaryLanguage returns the first declared language, normalized to its base tag ("en-US" -> "en")
+func primaryLanguage(langs []string) string {
+	if len(langs) == 0 {
+		return ""
+	}
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(langs[0])), "-")
+	return base
+}
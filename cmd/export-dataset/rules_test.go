@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func testRules(t *testing.T, modify func(*Rules)) Rules {
+	t.Helper()
+	rules := DefaultRules
+	if modify != nil {
+		modify(&rules)
+	}
+	if err := rules.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	return rules
+}
+
+func TestRulesValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Rules)
+		wantErr bool
+	}{
+		{"defaults", nil, false},
+		{"bad precision", func(r *Rules) { r.TimePrecision = "hourly" }, true},
+		{"unknown author mode", func(r *Rules) { r.AuthorMode = "plain" }, true},
+		{"pseudonym without salt", func(r *Rules) { r.AuthorMode = authorPseudonym }, true},
+		{"pseudonym with salt", func(r *Rules) { r.AuthorMode = authorPseudonym; r.Salt = "0123456789abcdef" }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := DefaultRules
+			if tt.modify != nil {
+				tt.modify(&rules)
+			}
+			if err := rules.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRulesTimestampTruncates(t *testing.T) {
+	rules := testRules(t, func(r *Rules) { r.TimePrecision = "15m" })
+	got := rules.Timestamp(time.Date(2025, 3, 30, 14, 52, 31, 0, time.UTC))
+	if got != "2025-03-30T14:45:00Z" {
+		t.Errorf("Timestamp = %s, want 2025-03-30T14:45:00Z", got)
+	}
+}
+
+func TestRulesCountBuckets(t *testing.T) {
+	rules := testRules(t, func(r *Rules) { r.BucketEngagement = true })
+	for n, want := range map[int]string{0: "0", 7: "1-9", 10: "10-99", 999: "100-999", 25000: "1000+"} {
+		if got := rules.Count(n); got != want {
+			t.Errorf("Count(%d) = %s, want %s", n, got, want)
+		}
+	}
+	if got := testRules(t, nil).Count(25000); got != "25000" {
+		t.Errorf("unbucketed Count = %s, want 25000", got)
+	}
+}
+
+func TestRulesLanguagesGroupsRare(t *testing.T) {
+	rules := testRules(t, func(r *Rules) { r.MinLanguageCount = 2 })
+	mapping := rules.Languages([]string{"en", "en", "ja", "ja", "is", ""})
+
+	want := map[string]string{"en": "en", "ja": "ja", "is": otherLanguage, "": ""}
+	for lang, expected := range want {
+		if mapping[lang] != expected {
+			t.Errorf("Languages[%q] = %q, want %q", lang, mapping[lang], expected)
+		}
+	}
+}
+
+func TestRulesAuthorPseudonymIsStableAndSalted(t *testing.T) {
+	a := testRules(t, func(r *Rules) { r.AuthorMode = authorPseudonym; r.Salt = "salt-one-0123456789" })
+	b := testRules(t, func(r *Rules) { r.AuthorMode = authorPseudonym; r.Salt = "salt-two-0123456789" })
+
+	if a.Author("alice.bsky.social") != a.Author("Alice.bsky.social") {
+		t.Error("pseudonym should not depend on handle case")
+	}
+	if a.Author("alice.bsky.social") == b.Author("alice.bsky.social") {
+		t.Error("pseudonym should change with the salt")
+	}
+	if strings.Contains(a.Author("alice.bsky.social"), "alice") {
+		t.Error("pseudonym leaks the handle")
+	}
+}
+
+func TestPrimaryLanguage(t *testing.T) {
+	for langs, want := range map[string]string{"en-US": "en", "PT-br,en": "pt", "": ""} {
+		var input []string
+		if langs != "" {
+			input = strings.Split(langs, ",")
+		}
+		if got := primaryLanguage(input); got != want {
+			t.Errorf("primaryLanguage(%v) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestWritePostsCSVOmitsTextAndHandles(t *testing.T) {
+	rules := testRules(t, func(r *Rules) { r.MinLanguageCount = 0 })
+	rows := []postRow{{
+		RunID:         "run-1",
+		WindowStart:   time.Date(2025, 3, 30, 14, 0, 0, 0, time.UTC),
+		WindowMinutes: 60,
+		CreatedAt:     time.Date(2025, 3, 30, 14, 23, 5, 0, time.UTC),
+		Language:      "en",
+		Sentiment:     "positive",
+		CompoundScore: 0.61234,
+		Likes:         3,
+		Reposts:       1,
+		Replies:       2,
+		Author:        "alice.bsky.social",
+	}}
+
+	var buf bytes.Buffer
+	if err := writePostsCSV(&buf, rules, rows); err != nil {
+		t.Fatalf("writePostsCSV: %v", err)
+	}
+	if strings.Contains(buf.String(), "alice") {
+		t.Errorf("export contains the author handle:\n%s", buf.String())
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	want := []string{"run-1", "2025-03-30T14:00:00Z", "60", "2025-03-30T14:00:00Z", "en", "positive", "0.6123", "3", "1", "2", "6"}
+	if len(records) != 2 || strings.Join(records[1], ",") != strings.Join(want, ",") {
+		t.Errorf("records = %v, want header plus %v", records, want)
+	}
+}
+
+func TestWriteRunsCSVWeightedSentiment(t *testing.T) {
+	rules := testRules(t, func(r *Rules) { r.IncludeRunID = false })
+	weighted := 8.126
+	points := []state.SentimentDataPoint{
+		{Timestamp: time.Date(2025, 3, 30, 14, 0, 0, 0, time.UTC), NetSentimentPercent: 4, WeightedNetSentimentPercent: &weighted},
+		{Timestamp: time.Date(2025, 3, 30, 15, 0, 0, 0, time.UTC), NetSentimentPercent: 5},
+	}
+
+	var buf bytes.Buffer
+	if err := writeRunsCSV(&buf, rules, points); err != nil {
+		t.Fatalf("writeRunsCSV: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	last := len(records[0]) - 1
+	if records[0][last] != "weighted_net_sentiment_percent" || records[1][last] != "8.13" || records[2][last] != "" {
+		t.Errorf("weighted column = %q, %q, %q", records[0][last], records[1][last], records[2][last])
+	}
+}
+
+func TestWriteRunsCSVSentimentCounts(t *testing.T) {
+	rules := testRules(t, nil)
+	at := time.Date(2025, 3, 30, 14, 5, 0, 0, time.UTC)
+	points := []state.SentimentDataPoint{
+		{RunID: "run-1", Timestamp: at, NetSentimentPercent: 12.5, SentimentCategory: "positive", TotalPosts: 100},
+		{RunID: "run-2", Timestamp: at.Add(time.Hour), NetSentimentPercent: -5, SentimentCategory: "negative", TotalPosts: 100,
+			Counts: &state.SentimentCounts{Positive: 30, Neutral: 35, Negative: 35}},
+	}
+
+	var buf bytes.Buffer
+	if err := writeRunsCSV(&buf, rules, points); err != nil {
+		t.Fatalf("writeRunsCSV: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("records = %v, want header plus two runs", records)
+	}
+	if got := strings.Join(records[1][6:9], ","); got != ",," {
+		t.Errorf("counts of a run without them = %q, want empty", got)
+	}
+	if got := strings.Join(records[2][6:9], ","); got != "30,35,35" {
+		t.Errorf("counts = %q, want 30,35,35", got)
+	}
+}
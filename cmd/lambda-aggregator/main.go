@@ -8,16 +8,11 @@ import (
 	"time"
 
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/christophergentle/hourstats-bsky/internal/events"
 	"github.com/christophergentle/hourstats-bsky/internal/state"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
 )
 
-// StepFunctionsEvent represents the event from Step Functions
-type StepFunctionsEvent struct {
-	RunID                   string `json:"runId"`
-	AnalysisIntervalMinutes int    `json:"analysisIntervalMinutes"`
-	Status                  string `json:"status"`
-}
-
 // Response represents the Lambda response
 type Response struct {
 	StatusCode    int    `json:"statusCode"`
@@ -44,11 +39,11 @@ func NewAggregatorHandler(ctx context.Context) (*AggregatorHandler, error) {
 }
 
 // HandleRequest is the main Lambda handler
-func (h *AggregatorHandler) HandleRequest(ctx context.Context, event StepFunctionsEvent) (Response, error) {
+func (h *AggregatorHandler) HandleRequest(ctx context.Context, event events.RunEvent) (Response, error) {
 	log.Printf("Aggregator received event: %+v", event)
 
 	// Get current run state - specifically look for analyzer step which has the analyzed posts
-	runState, err := h.stateManager.GetRun(ctx, event.RunID, "analyzer")
+	runState, err := h.stateManager.GetRun(ctx, event.RunID, "analyzer", state.StronglyConsistent)
 	if err != nil {
 		log.Printf("Failed to get analyzer run state: %v", err)
 		return Response{
@@ -64,7 +59,7 @@ func (h *AggregatorHandler) HandleRequest(ctx context.Context, event StepFunctio
 		time.Now().Format("2006-01-02 15:04:05 UTC"))
 
 	// Get all posts from DynamoDB
-	allPosts, err := h.stateManager.GetAllPosts(ctx, event.RunID)
+	allPosts, err := h.stateManager.GetAllPosts(ctx, event.RunID, state.StronglyConsistent)
 	if err != nil {
 		log.Printf("Failed to get posts from DynamoDB: %v", err)
 		return Response{
@@ -148,11 +143,15 @@ func (h *AggregatorHandler) filterPostsByCutoffTime(posts []state.Post, cutoffTi
 }
 
 func main() {
+	if err := tracing.Init("hourstats-aggregator"); err != nil {
+		log.Printf("Tracing disabled: %v", err)
+	}
+
 	ctx := context.Background()
 	handler, err := NewAggregatorHandler(ctx)
 	if err != nil {
 		log.Fatalf("Failed to create aggregator handler: %v", err)
 	}
 
-	lambda.Start(handler.HandleRequest)
+	lambda.Start(tracing.Handler(events.Handler(handler.HandleRequest)))
 }
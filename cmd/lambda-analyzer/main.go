@@ -7,17 +7,15 @@ import (
 	"time"
 
 	"github.com/aws/aws-lambda-go/lambda"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
 	"github.com/christophergentle/hourstats-bsky/internal/analyzer"
+	"github.com/christophergentle/hourstats-bsky/internal/events"
 	"github.com/christophergentle/hourstats-bsky/internal/state"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
 )
 
-// StepFunctionsEvent represents the event from Step Functions
-type StepFunctionsEvent struct {
-	RunID                   string `json:"runId"`
-	AnalysisIntervalMinutes int    `json:"analysisIntervalMinutes"`
-	Status                  string `json:"status"`
-}
-
 // Response represents the Lambda response
 type Response struct {
 	StatusCode    int    `json:"statusCode"`
@@ -30,6 +28,9 @@ type AnalyzerHandler struct {
 	stateManager            *state.StateManager
 	sentimentAnalyzer       *analyzer.SentimentAnalyzer
 	sentimentHistoryManager *state.SentimentHistoryManager
+	ssmClient               *ssm.Client
+	// sentimentThresholds categorize the run's average score, loaded for each run
+	sentimentThresholds aggregation.Thresholds
 }
 
 // NewAnalyzerHandler creates a new analyzer handler
@@ -49,19 +50,32 @@ func NewAnalyzerHandler(ctx context.Context) (*AnalyzerHandler, error) {
 	// Initialize sentiment analyzer
 	sentimentAnalyzer := analyzer.New()
 
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, tracing.WithAWS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
 	return &AnalyzerHandler{
 		stateManager:            stateManager,
 		sentimentAnalyzer:       sentimentAnalyzer,
 		sentimentHistoryManager: sentimentHistoryManager,
+		ssmClient:               ssm.NewFromConfig(awsCfg),
+		sentimentThresholds:     aggregation.DefaultThresholds,
 	}, nil
 }
 
 // HandleRequest is the main Lambda handler
-func (h *AnalyzerHandler) HandleRequest(ctx context.Context, event StepFunctionsEvent) (Response, error) {
+func (h *AnalyzerHandler) HandleRequest(ctx context.Context, event events.RunEvent) (Response, error) {
 	log.Printf("Analyzer received event: %+v", event)
 
+	thresholds, err := aggregation.LoadThresholds(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("⚠️ ANALYZER: Failed to load sentiment thresholds, using the defaults: %v", err)
+	}
+	h.sentimentThresholds = thresholds
+
 	// Get current run state - specifically look for fetcher step which has the posts
-	runState, err := h.stateManager.GetRun(ctx, event.RunID, "fetcher")
+	runState, err := h.stateManager.GetRun(ctx, event.RunID, "fetcher", state.StronglyConsistent)
 	if err != nil {
 		log.Printf("Failed to get fetcher run state: %v", err)
 		return Response{
@@ -77,7 +91,7 @@ func (h *AnalyzerHandler) HandleRequest(ctx context.Context, event StepFunctions
 		time.Now().Format("2006-01-02 15:04:05 UTC"))
 
 	// Get all posts from DynamoDB
-	allPosts, err := h.stateManager.GetAllPosts(ctx, event.RunID)
+	allPosts, err := h.stateManager.GetAllPosts(ctx, event.RunID, state.StronglyConsistent)
 	if err != nil {
 		log.Printf("Failed to get posts from DynamoDB: %v", err)
 		return Response{
@@ -155,6 +169,7 @@ func (h *AnalyzerHandler) analyzePosts(posts []state.Post) ([]state.Post, string
 			Reposts:   post.Reposts,
 			Replies:   post.Replies,
 			CreatedAt: post.CreatedAt,
+			Langs:     post.Langs,
 		}
 	}
 
@@ -206,14 +221,7 @@ func (h *AnalyzerHandler) calculateOverallSentiment(posts []analyzer.AnalyzedPos
 	averageCompoundScore := totalCompoundScore / float64(len(posts))
 
 	// Map compound score to category for backward compatibility
-	var sentimentCategory string
-	if averageCompoundScore >= 0.3 {
-		sentimentCategory = "positive"
-	} else if averageCompoundScore <= -0.3 {
-		sentimentCategory = "negative"
-	} else {
-		sentimentCategory = "neutral"
-	}
+	sentimentCategory := h.sentimentThresholds.Categorize(averageCompoundScore, "")
 
 	// Scale to percentage range for 100-word system
 	netSentimentPercentage := averageCompoundScore * 100.0
@@ -246,11 +254,15 @@ func (h *AnalyzerHandler) filterPostsByCutoffTime(posts []state.Post, cutoffTime
 }
 
 func main() {
+	if err := tracing.Init("hourstats-analyzer"); err != nil {
+		log.Printf("Tracing disabled: %v", err)
+	}
+
 	ctx := context.Background()
 	handler, err := NewAnalyzerHandler(ctx)
 	if err != nil {
 		log.Fatalf("Failed to create analyzer handler: %v", err)
 	}
 
-	lambda.Start(handler.HandleRequest)
+	lambda.Start(tracing.Handler(events.Handler(handler.HandleRequest)))
 }
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/christophergentle/hourstats-bsky/internal/api"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
+)
+
+// cacheControl lets clients and API Gateway caches reuse responses for a minute; runs
+// only change every 30 minutes
+const cacheControl = "public, max-age=60"
+
+// APIHandler serves the read-only query API behind API Gateway
+type APIHandler struct {
+	api *api.Handler
+}
+
+// NewAPIHandler creates a new API handler over the hourstats tables
+func NewAPIHandler(ctx context.Context) (*APIHandler, error) {
+	stateManager, err := state.NewStateManager(ctx, "hourstats-state")
+	if err != nil {
+		return nil, err
+	}
+	historyManager, err := state.NewSentimentHistoryManager(ctx, "hourstats-sentiment-history")
+	if err != nil {
+		return nil, err
+	}
+	dailyManager, err := state.NewDailySentimentManager(ctx, "hourstats-daily-sentiment")
+	if err != nil {
+		return nil, err
+	}
+	selfStatsManager, err := state.NewSelfStatsManager(ctx, "hourstats-self-stats")
+	if err != nil {
+		return nil, err
+	}
+
+	return &APIHandler{api: api.NewHandler(stateManager, historyManager, dailyManager, selfStatsManager)}, nil
+}
+
+// HandleRequest is the main Lambda handler for HTTP API (payload v2) requests
+func (h *APIHandler) HandleRequest(ctx context.Context, request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	resp := h.api.Serve(ctx, api.Request{
+		Method: request.RequestContext.HTTP.Method,
+		Path:   request.RawPath,
+		Query:  request.QueryStringParameters,
+	})
+
+	body, err := json.Marshal(resp.Body)
+	if err != nil {
+		log.Printf("Failed to encode response for %s: %v", request.RawPath, err)
+		return events.APIGatewayV2HTTPResponse{StatusCode: http.StatusInternalServerError, Body: `{"error":"internal error"}`}, nil
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	if resp.StatusCode == http.StatusOK {
+		headers["Cache-Control"] = cacheControl
+	}
+	return events.APIGatewayV2HTTPResponse{StatusCode: resp.StatusCode, Headers: headers, Body: string(body)}, nil
+}
+
+func main() {
+	if err := tracing.Init("hourstats-api"); err != nil {
+		log.Printf("Tracing disabled: %v", err)
+	}
+
+	ctx := context.Background()
+	handler, err := NewAPIHandler(ctx)
+	if err != nil {
+		log.Fatalf("Failed to create API handler: %v", err)
+	}
+
+	lambda.Start(tracing.Handler(handler.HandleRequest))
+}
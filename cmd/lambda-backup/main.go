@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/christophergentle/hourstats-bsky/internal/backup"
+	"github.com/christophergentle/hourstats-bsky/internal/metrics"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
+)
+
+// defaultTables are the tables snapshotted when BACKUP_TABLES is not set
+var defaultTables = []string{"hourstats-state", "hourstats-sentiment-history", "hourstats-daily-sentiment", "hourstats-blocklist"}
+
+// Event represents the EventBridge event structure
+type Event struct {
+	Source string `json:"source"`
+	Time   string `json:"time"`
+	DryRun bool   `json:"dryRun,omitempty"` // Report what retention would delete without deleting
+}
+
+// Response represents the Lambda response
+type Response struct {
+	StatusCode     int    `json:"statusCode"`
+	Body           string `json:"body"`
+	BackupPath     string `json:"backupPath,omitempty"`
+	TotalItems     int    `json:"totalItems"`
+	BackupsExpired int    `json:"backupsExpired"`
+}
+
+// BackupHandler snapshots the hourstats tables to S3 and prunes old backups
+type BackupHandler struct {
+	bucket    string
+	prefix    string
+	tables    []string
+	retention backup.RetentionPolicy
+	s3Client  *backup.S3Client
+}
+
+// NewBackupHandler creates a new backup handler from the Lambda environment
+func NewBackupHandler(ctx context.Context) (*BackupHandler, error) {
+	bucket := os.Getenv("BACKUP_BUCKET")
+	if bucket == "" {
+		return nil, fmt.Errorf("BACKUP_BUCKET environment variable is required")
+	}
+
+	prefix := os.Getenv("BACKUP_PREFIX")
+	if prefix == "" {
+		prefix = backup.DefaultS3Prefix
+	}
+
+	tables := defaultTables
+	if value := os.Getenv("BACKUP_TABLES"); value != "" {
+		tables = strings.Split(value, ",")
+	}
+
+	retention := backup.DefaultRetentionPolicy
+	retention.KeepDaily = envInt("BACKUP_KEEP_DAILY", retention.KeepDaily)
+	retention.KeepWeekly = envInt("BACKUP_KEEP_WEEKLY", retention.KeepWeekly)
+
+	s3Client, err := backup.NewS3Client(ctx, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	return &BackupHandler{
+		bucket:    bucket,
+		prefix:    prefix,
+		tables:    tables,
+		retention: retention,
+		s3Client:  s3Client,
+	}, nil
+}
+
+// HandleRequest is the main Lambda handler
+func (h *BackupHandler) HandleRequest(ctx context.Context, event Event) (Response, error) {
+	log.Printf("Backup received event: %+v", event)
+	log.Printf("Backing up %d tables to s3://%s/%s", len(h.tables), h.bucket, h.prefix)
+
+	// Lambda reuses /tmp between invocations, so each snapshot gets its own directory that is always removed
+	outputDir, err := os.MkdirTemp("", "hourstats-backup-")
+	if err != nil {
+		return Response{StatusCode: 500, Body: fmt.Sprintf("Failed to create temp dir: %v", err)}, err
+	}
+	defer os.RemoveAll(outputDir)
+
+	result, err := backup.Backup(ctx, backup.BackupOptions{
+		Tables:    h.tables,
+		OutputDir: outputDir,
+		S3Bucket:  h.bucket,
+		S3Prefix:  h.prefix,
+		Compress:  true,
+	})
+	if err != nil {
+		log.Printf("Backup failed: %v", err)
+		emitMetrics(metrics.Metric{Name: "BackupSucceeded", Value: 0, Unit: metrics.UnitCount})
+		return Response{
+			StatusCode: 500,
+			Body:       fmt.Sprintf("Backup failed: %v", err),
+		}, err
+	}
+
+	log.Printf("Backup complete: %d tables, %d items in %v", result.TablesBackedUp, result.TotalItems, result.Duration)
+
+	// Only prune once the new backup is safely in S3
+	retention, err := backup.ApplyRetention(ctx, h.s3Client, h.prefix, h.retention, event.DryRun)
+	if err != nil {
+		log.Printf("Retention failed: %v", err)
+		emitMetrics(
+			metrics.Metric{Name: "BackupSucceeded", Value: 1, Unit: metrics.UnitCount},
+			metrics.Metric{Name: "RetentionSucceeded", Value: 0, Unit: metrics.UnitCount},
+		)
+		return Response{
+			StatusCode: 500,
+			Body:       fmt.Sprintf("Backup succeeded but retention failed: %v", err),
+			TotalItems: result.TotalItems,
+		}, err
+	}
+
+	log.Printf("Retention: kept %d backups, expired %d (%d objects deleted)",
+		len(retention.Kept), len(retention.Expired), retention.ObjectsDeleted)
+
+	emitMetrics(
+		metrics.Metric{Name: "BackupSucceeded", Value: 1, Unit: metrics.UnitCount},
+		metrics.Metric{Name: "RetentionSucceeded", Value: 1, Unit: metrics.UnitCount},
+		metrics.Metric{Name: "BackupItems", Value: float64(result.TotalItems), Unit: metrics.UnitCount},
+		metrics.Metric{Name: "BackupDurationMs", Value: float64(result.Duration.Milliseconds()), Unit: metrics.UnitMilliseconds},
+		metrics.Metric{Name: "BackupsRetained", Value: float64(len(retention.Kept)), Unit: metrics.UnitCount},
+		metrics.Metric{Name: "BackupsExpired", Value: float64(len(retention.Expired)), Unit: metrics.UnitCount},
+	)
+
+	return Response{
+		StatusCode:     200,
+		Body:           fmt.Sprintf("Backed up %d tables (%d items)", result.TablesBackedUp, result.TotalItems),
+		BackupPath:     fmt.Sprintf("s3://%s/%s/backup-%s", h.bucket, h.prefix, result.Manifest.BackupTimestamp),
+		TotalItems:     result.TotalItems,
+		BackupsExpired: len(retention.Expired),
+	}, nil
+}
+
+// emitMetrics publishes backup metrics, logging rather than failing on error
+func emitMetrics(m ...metrics.Metric) {
+	if err := metrics.Emit(map[string]string{"Function": "backup"}, m...); err != nil {
+		log.Printf("Failed to emit backup metrics: %v", err)
+	}
+}
+
+// envInt reads an integer environment variable, falling back to def when unset or invalid
+func envInt(name string, def int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Printf("Ignoring invalid %s=%q, using %d", name, value, def)
+		return def
+	}
+	return n
+}
+
+func main() {
+	if err := tracing.Init("hourstats-backup"); err != nil {
+		log.Printf("Tracing disabled: %v", err)
+	}
+
+	ctx := context.Background()
+	handler, err := NewBackupHandler(ctx)
+	if err != nil {
+		log.Fatalf("Failed to create backup handler: %v", err)
+	}
+
+	lambda.Start(tracing.Handler(handler.HandleRequest))
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/credentials"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
+)
+
+// Response represents the Lambda response; Secrets Manager only looks at whether the
+// step returned an error
+type Response struct {
+	StatusCode int    `json:"statusCode"`
+	Body       string `json:"body"`
+}
+
+// RotationHandler is the Secrets Manager rotation hook for the Bluesky credentials secret
+type RotationHandler struct {
+	rotator *credentials.Rotator
+}
+
+// NewRotationHandler creates a rotation handler that checks new app passwords against Bluesky
+func NewRotationHandler(ctx context.Context) (*RotationHandler, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, tracing.WithAWS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	authenticate := func(c credentials.Credentials) error {
+		return client.New(c.Handle, c.Password).Authenticate()
+	}
+	return &RotationHandler{
+		rotator: credentials.NewRotator(secretsmanager.NewFromConfig(cfg), authenticate),
+	}, nil
+}
+
+// HandleRequest runs one rotation step; an error fails the rotation and leaves the current
+// credentials in place
+func (h *RotationHandler) HandleRequest(ctx context.Context, event credentials.RotationEvent) (Response, error) {
+	log.Printf("🔑 ROTATION: %s for %s, version %s", event.Step, event.SecretID, event.ClientRequestToken)
+	if err := h.rotator.Rotate(ctx, event); err != nil {
+		log.Printf("❌ ROTATION: %s failed: %v", event.Step, err)
+		return Response{StatusCode: 500, Body: err.Error()}, err
+	}
+	return Response{StatusCode: 200, Body: event.Step + " completed"}, nil
+}
+
+func main() {
+	if err := tracing.Init("hourstats-credential-rotation"); err != nil {
+		log.Printf("Tracing disabled: %v", err)
+	}
+
+	ctx := context.Background()
+	handler, err := NewRotationHandler(ctx)
+	if err != nil {
+		log.Fatalf("Failed to create rotation handler: %v", err)
+	}
+
+	lambda.Start(tracing.Handler(handler.HandleRequest))
+}
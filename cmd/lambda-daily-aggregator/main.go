@@ -7,7 +7,11 @@ import (
 	"time"
 
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/christophergentle/hourstats-bsky/internal/retention"
 	"github.com/christophergentle/hourstats-bsky/internal/state"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
 )
 
 // Event represents the EventBridge event structure
@@ -20,16 +24,19 @@ type Event struct {
 
 // Response represents the Lambda response
 type Response struct {
-	StatusCode int    `json:"statusCode"`
-	Body       string `json:"body"`
-	Processed  bool   `json:"processed"`
-	Date       string `json:"date,omitempty"`
+	StatusCode   int    `json:"statusCode"`
+	Body         string `json:"body"`
+	Processed    bool   `json:"processed"`
+	Date         string `json:"date,omitempty"`
+	RunsArchived int    `json:"runsArchived,omitempty"`
 }
 
 // DailyAggregatorHandler handles the daily aggregator Lambda function
 type DailyAggregatorHandler struct {
 	dailySentimentManager   *state.DailySentimentManager
 	sentimentHistoryManager *state.SentimentHistoryManager
+	stateManager            *state.StateManager
+	ssmClient               *ssm.Client
 }
 
 // NewDailyAggregatorHandler creates a new daily aggregator handler
@@ -46,9 +53,22 @@ func NewDailyAggregatorHandler(ctx context.Context) (*DailyAggregatorHandler, er
 		return nil, fmt.Errorf("failed to create sentiment history manager: %w", err)
 	}
 
+	// Initialize state manager for archiving run summaries
+	stateManager, err := state.NewStateManager(ctx, "hourstats-state")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state manager: %w", err)
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, tracing.WithAWS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
 	return &DailyAggregatorHandler{
 		dailySentimentManager:   dailySentimentManager,
 		sentimentHistoryManager: sentimentHistoryManager,
+		stateManager:            stateManager,
+		ssmClient:               ssm.NewFromConfig(cfg),
 	}, nil
 }
 
@@ -56,6 +76,15 @@ func NewDailyAggregatorHandler(ctx context.Context) (*DailyAggregatorHandler, er
 func (h *DailyAggregatorHandler) HandleRequest(ctx context.Context, event Event) (Response, error) {
 	log.Printf("Daily aggregator received event: %+v", event)
 
+	policy, err := retention.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("Failed to load retention policy, using default TTLs: %v", err)
+	}
+	h.dailySentimentManager.SetTTL(policy.DailySentimentTTL)
+
+	// Archive run summaries before their TTL expires; archival problems never block aggregation
+	runsArchived := h.archiveExpiringRuns(ctx, policy.Archive)
+
 	// Determine target date
 	var targetDate string
 	if event.TargetDate != "" {
@@ -73,10 +102,11 @@ func (h *DailyAggregatorHandler) HandleRequest(ctx context.Context, event Event)
 	if err == nil && existing != nil {
 		log.Printf("Daily sentiment already exists for date %s, skipping aggregation", targetDate)
 		return Response{
-			StatusCode: 200,
-			Body:       fmt.Sprintf("Daily sentiment already exists for date: %s", targetDate),
-			Processed:  false,
-			Date:       targetDate,
+			StatusCode:   200,
+			Body:         fmt.Sprintf("Daily sentiment already exists for date: %s", targetDate),
+			Processed:    false,
+			Date:         targetDate,
+			RunsArchived: runsArchived,
 		}, nil
 	}
 
@@ -92,6 +122,13 @@ func (h *DailyAggregatorHandler) HandleRequest(ctx context.Context, event Event)
 		}, err
 	}
 
+	// Record run activity for the transparency report; runs older than the state TTL are gone
+	if runs, err := h.stateManager.GetRecentRuns(ctx, time.Since(startOfDay(targetDate))); err != nil {
+		log.Printf("Failed to get runs for %s, storing daily sentiment without run activity: %v", targetDate, err)
+	} else {
+		dailySentiment.AddRunActivity(runs)
+	}
+
 	// Store the daily sentiment
 	err = h.dailySentimentManager.StoreDailySentiment(ctx, *dailySentiment)
 	if err != nil {
@@ -104,28 +141,71 @@ func (h *DailyAggregatorHandler) HandleRequest(ctx context.Context, event Event)
 		}, err
 	}
 
-	log.Printf("Successfully processed daily sentiment for date %s: avg=%.2f%%, min=%.2f%%, max=%.2f%%, runs=%d, posts=%d",
+	log.Printf("Successfully processed daily sentiment for date %s: avg=%.2f%%, min=%.2f%%, max=%.2f%%, runs=%d (started %d), posts=%d, coverage=%.1f%%",
 		targetDate,
 		dailySentiment.AverageSentiment,
 		dailySentiment.MinSentiment,
 		dailySentiment.MaxSentiment,
 		dailySentiment.TotalRuns,
-		dailySentiment.TotalPosts)
+		dailySentiment.RunsStarted,
+		dailySentiment.TotalPosts,
+		dailySentiment.AverageCoveragePercent)
 
 	return Response{
-		StatusCode: 200,
-		Body:       fmt.Sprintf("Daily sentiment processed successfully for date: %s", targetDate),
-		Processed:  true,
-		Date:       targetDate,
+		StatusCode:   200,
+		Body:         fmt.Sprintf("Daily sentiment processed successfully for date: %s", targetDate),
+		Processed:    true,
+		Date:         targetDate,
+		RunsArchived: runsArchived,
 	}, nil
 }
 
+// archiveExpiringRuns copies the summaries of runs about to expire to the archive
+// Returns how many were archived; zero when archival is disabled or fails
+func (h *DailyAggregatorHandler) archiveExpiringRuns(ctx context.Context, archive retention.ArchiveConfig) int {
+	if !archive.Enabled() {
+		return 0
+	}
+
+	archiver, err := retention.NewArchiver(ctx, archive)
+	if err != nil {
+		log.Printf("Failed to create run archiver: %v", err)
+		return 0
+	}
+
+	runs, err := h.stateManager.GetExpiringRuns(ctx, retention.ArchiveLookahead)
+	if err != nil {
+		log.Printf("Failed to list expiring runs: %v", err)
+		return 0
+	}
+
+	archived, err := retention.ArchiveRuns(ctx, archiver, runs)
+	if err != nil {
+		log.Printf("Failed to archive some run summaries: %v", err)
+	}
+	log.Printf("Archived %d of %d expiring run summaries", archived, len(runs))
+	return archived
+}
+
+// startOfDay returns midnight UTC at the start of date ("2006-01-02"), or now if it is invalid
+func startOfDay(date string) time.Time {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return time.Now()
+	}
+	return day
+}
+
 func main() {
+	if err := tracing.Init("hourstats-daily-aggregator"); err != nil {
+		log.Printf("Tracing disabled: %v", err)
+	}
+
 	ctx := context.Background()
 	handler, err := NewDailyAggregatorHandler(ctx)
 	if err != nil {
 		log.Fatalf("Failed to create daily aggregator handler: %v", err)
 	}
 
-	lambda.Start(handler.HandleRequest)
+	lambda.Start(tracing.Handler(handler.HandleRequest))
 }
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+const (
+	// bufferFlushPosts is how many posts the write buffer holds before flushing: 25 batches
+	// of 100 posts fill exactly one BatchWriteItem request
+	bufferFlushPosts = 2500
+
+	// bufferFlushInterval bounds how long posts wait in the buffer, so a slow fetch still
+	// writes steadily instead of all at once at the end
+	bufferFlushInterval = 30 * time.Second
+)
+
+// postStore is the subset of the state manager the write buffer needs
+type postStore interface {
+	AddPosts(ctx context.Context, runID string, posts []state.Post) error
+}
+
+// postBuffer accumulates posts across fetch iterations and writes them in full batches,
+// smoothing the write load that storing every page as it arrives would cause.
+// Posts must be flushed before the fetch cursor is checkpointed past them.
+type postBuffer struct {
+	store     postStore
+	runID     string
+	posts     []state.Post
+	lastFlush time.Time
+	now       func() time.Time
+}
+
+// newPostBuffer creates an empty write buffer for a run
+func newPostBuffer(store postStore, runID string) *postBuffer {
+	return &postBuffer{
+		store:     store,
+		runID:     runID,
+		lastFlush: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Add buffers posts at an iteration boundary, flushing when the buffer is full or the
+// flush interval has passed since the last write
+func (b *postBuffer) Add(ctx context.Context, posts []state.Post) error {
+	b.posts = append(b.posts, posts...)
+	if len(b.posts) >= bufferFlushPosts || b.now().Sub(b.lastFlush) >= bufferFlushInterval {
+		return b.Flush(ctx)
+	}
+	return nil
+}
+
+// Flush writes every buffered post. Posts that fail to store are dropped from the buffer
+// and the error is returned, so callers must not checkpoint past them.
+func (b *postBuffer) Flush(ctx context.Context) error {
+	b.lastFlush = b.now()
+	if len(b.posts) == 0 {
+		return nil
+	}
+
+	posts := b.posts
+	b.posts = nil
+	log.Printf("💾 FETCHER: Flushing %d buffered posts", len(posts))
+	return b.store.AddPosts(ctx, b.runID, posts)
+}
+
+// Len is the number of posts waiting to be written
+func (b *postBuffer) Len() int {
+	return len(b.posts)
+}
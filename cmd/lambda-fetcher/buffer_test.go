@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+type recordingStore struct {
+	writes [][]state.Post
+	err    error
+}
+
+func (s *recordingStore) AddPosts(ctx context.Context, runID string, posts []state.Post) error {
+	s.writes = append(s.writes, posts)
+	return s.err
+}
+
+func TestPostBufferFlushesWhenFull(t *testing.T) {
+	store := &recordingStore{}
+	buffer := newPostBuffer(store, "run-1")
+	ctx := context.Background()
+
+	for i := 0; i < bufferFlushPosts/100-1; i++ {
+		if err := buffer.Add(ctx, make([]state.Post, 100)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(store.writes) != 0 {
+		t.Fatalf("expected no writes before the buffer fills, got %d", len(store.writes))
+	}
+
+	if err := buffer.Add(ctx, make([]state.Post, 100)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.writes) != 1 || len(store.writes[0]) != bufferFlushPosts {
+		t.Fatalf("expected one write of %d posts, got %d writes", bufferFlushPosts, len(store.writes))
+	}
+	if buffer.Len() != 0 {
+		t.Errorf("expected an empty buffer after flushing, got %d", buffer.Len())
+	}
+}
+
+func TestPostBufferFlushesAfterInterval(t *testing.T) {
+	store := &recordingStore{}
+	buffer := newPostBuffer(store, "run-1")
+	now := time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC)
+	buffer.now = func() time.Time { return now }
+	buffer.lastFlush = now
+	ctx := context.Background()
+
+	if err := buffer.Add(ctx, make([]state.Post, 10)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.writes) != 0 {
+		t.Fatal("expected posts to stay buffered within the flush interval")
+	}
+
+	now = now.Add(bufferFlushInterval)
+	if err := buffer.Add(ctx, make([]state.Post, 10)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.writes) != 1 || len(store.writes[0]) != 20 {
+		t.Fatalf("expected one write of 20 posts after the interval, got %v", store.writes)
+	}
+}
+
+func TestPostBufferFlush(t *testing.T) {
+	store := &recordingStore{}
+	buffer := newPostBuffer(store, "run-1")
+	ctx := context.Background()
+
+	if err := buffer.Flush(ctx); err != nil || len(store.writes) != 0 {
+		t.Fatalf("expected flushing an empty buffer to write nothing, got %d writes, err %v", len(store.writes), err)
+	}
+
+	store.err = errors.New("throttled")
+	_ = buffer.Add(ctx, make([]state.Post, 5))
+	if err := buffer.Flush(ctx); !errors.Is(err, store.err) {
+		t.Fatalf("expected the store error, got %v", err)
+	}
+	if buffer.Len() != 0 {
+		t.Errorf("expected failed posts to leave the buffer, got %d", buffer.Len())
+	}
+}
@@ -2,52 +2,113 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	awslambda "github.com/aws/aws-sdk-go-v2/service/lambda"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/christophergentle/hourstats-bsky/internal/analyzer"
+	"github.com/christophergentle/hourstats-bsky/internal/channel"
 	bskyclient "github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/comparison"
+	appconfig "github.com/christophergentle/hourstats-bsky/internal/config"
+	"github.com/christophergentle/hourstats-bsky/internal/credentials"
+	"github.com/christophergentle/hourstats-bsky/internal/dryrun"
+	"github.com/christophergentle/hourstats-bsky/internal/events"
+	"github.com/christophergentle/hourstats-bsky/internal/imagetext"
+	"github.com/christophergentle/hourstats-bsky/internal/retention"
 	"github.com/christophergentle/hourstats-bsky/internal/state"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
 )
 
-// FetcherEvent represents the event for the fetcher lambda
-type FetcherEvent struct {
-	RunID                   string `json:"runId"`
-	AnalysisIntervalMinutes int    `json:"analysisIntervalMinutes"`
-	Status                  string `json:"status"`
-}
-
 // Response represents the Lambda response
 type Response struct {
 	StatusCode     int    `json:"statusCode"`
 	Body           string `json:"body"`
 	PostsRetrieved int    `json:"postsRetrieved"`
+	HasMorePosts   bool   `json:"hasMorePosts"`
 }
 
+const (
+	// feedURIParameter optionally points the fetcher at a custom feed or list instead of the global search
+	feedURIParameter = "/hourstats/settings/feed_uri"
+
+	// searchQueriesParameter optionally scopes the search to comma-separated queries, merged as OR
+	searchQueriesParameter = "/hourstats/settings/search_queries"
+
+	// analyzeDuringFetchParameter analyzes each batch as it is stored when "true", so the
+	// processor only aggregates
+	analyzeDuringFetchParameter = "/hourstats/settings/analyze_during_fetch"
+
+	// imageTextParameter optionally adds image text to the analyzed text: "alt" for alt
+	// text, "ocr" to also read images without alt text with Textract
+	imageTextParameter = "/hourstats/settings/image_text"
+)
+
+// maxImageReadsPerInvocation caps the images read with Textract per fetcher invocation,
+// since each one is billed
+const maxImageReadsPerInvocation = 25
+
+const (
+	// checkpointAfter is how long a single invocation fetches before checkpointing its cursor
+	// when the context carries no deadline, e.g. when run outside Lambda
+	checkpointAfter = 13 * time.Minute
+
+	// shutdownReserve is the part of the Lambda's remaining time kept back from fetching,
+	// to flush buffered posts, checkpoint and dispatch the next step
+	shutdownReserve = 2 * time.Minute
+
+	// fetchInvocationsPerHour caps how many times the fetcher runs for each hour of a run's
+	// window, and for windows of an hour or less; see fetchInvocationBudget
+	fetchInvocationsPerHour = 4
+
+	// maxIterationsPerInvocation caps the pages one invocation fetches before it checkpoints
+	// and hands the rest of the window to the next invocation (100 pages * 100 posts)
+	maxIterationsPerInvocation = 100
+
+	// comparisonTimeout bounds sampling the comparison networks, which runs inside shutdownReserve
+	comparisonTimeout = time.Minute
+)
+
 // FetcherHandler handles the fetcher Lambda function
 type FetcherHandler struct {
-	stateManager *state.StateManager
-	ssmClient    *ssm.Client
-	lambdaClient *awslambda.Client
+	stateManager     *state.StateManager
+	ssmClient        *ssm.Client
+	lambdaClient     *awslambda.Client
+	newBlueskyClient bskyclient.Factory
+
+	// credentials finds the default account's credentials; channels with their own
+	// account keep them in SSM
+	credentials credentials.Provider
+
+	// sentimentAnalyzer analyzes batches during the fetch; it is created the first time
+	// analyzeDuringFetchParameter is enabled and kept while the Lambda stays warm
+	sentimentAnalyzer *analyzer.SentimentAnalyzer
+
+	// imageReader reads the text in images for imageTextParameter's "ocr" mode, and
+	// imageText collects image text for the current invocation
+	imageReader imagetext.Reader
+	imageText   *imagetext.Extractor
 }
 
 // NewFetcherHandler creates a new fetcher handler
 func NewFetcherHandler(ctx context.Context) (*FetcherHandler, error) {
 	// Initialize state manager
-	stateManager, err := state.NewStateManager(ctx, "hourstats-state")
+	stateManager, err := state.NewStateManager(ctx, appconfig.TablesFromEnv().State)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create state manager: %w", err)
 	}
 
 	// Initialize AWS SDK
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := config.LoadDefaultConfig(ctx, tracing.WithAWS)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
@@ -59,18 +120,27 @@ func NewFetcherHandler(ctx context.Context) (*FetcherHandler, error) {
 	lambdaClient := awslambda.NewFromConfig(cfg)
 
 	return &FetcherHandler{
-		stateManager: stateManager,
-		ssmClient:    ssmClient,
-		lambdaClient: lambdaClient,
+		stateManager:     stateManager,
+		ssmClient:        ssmClient,
+		lambdaClient:     lambdaClient,
+		newBlueskyClient: bskyclient.NewClient,
+		credentials:      credentials.Default(cfg, ssmClient),
+		imageReader:      imagetext.NewTextractReader(cfg),
 	}, nil
 }
 
 // Handle handles the Lambda function invocation
-func (h *FetcherHandler) Handle(ctx context.Context, event FetcherEvent) (Response, error) {
-	log.Printf("🚀 FETCHER: Starting fetcher for run: %s", event.RunID)
+func (h *FetcherHandler) Handle(ctx context.Context, event events.FetcherEvent) (Response, error) {
+	invocation := event.Invocation
+	if invocation < 1 {
+		invocation = 1
+	}
+	maxInvocations := fetchInvocationBudget(event.AnalysisIntervalMinutes)
+	log.Printf("🚀 FETCHER: Starting fetcher for run: %s (invocation %d/%d)", event.RunID, invocation, maxInvocations)
+	tracing.SetRunID(ctx, event.RunID)
 
 	// Get run state
-	runState, err := h.stateManager.GetRun(ctx, event.RunID, "orchestrator")
+	runState, err := h.stateManager.GetRun(ctx, event.RunID, "orchestrator", state.StronglyConsistent)
 	if err != nil {
 		log.Printf("Failed to get run state: %v", err)
 		return Response{
@@ -79,8 +149,25 @@ func (h *FetcherHandler) Handle(ctx context.Context, event FetcherEvent) (Respon
 		}, err
 	}
 
+	// Post batches follow the configured state table TTL
+	policy, err := retention.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("⚠️ FETCHER: Failed to load retention policy, using default TTLs: %v", err)
+	}
+	h.stateManager.SetTTL(policy.StateTTL)
+
+	// The run's channel chooses the account and the post source
+	runChannel, err := channel.Get(ctx, h.ssmClient, runState.ChannelID)
+	if err != nil {
+		log.Printf("Failed to get channel settings: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to get channel settings: " + err.Error(),
+		}, err
+	}
+
 	// Get Bluesky credentials
-	handle, password, err := h.getBlueskyCredentials(ctx)
+	handle, password, err := h.getBlueskyCredentials(ctx, runChannel)
 	if err != nil {
 		log.Printf("Failed to get credentials: %v", err)
 		return Response{
@@ -93,7 +180,7 @@ func (h *FetcherHandler) Handle(ctx context.Context, event FetcherEvent) (Respon
 	log.Printf("🔐 FETCHER: Retrieved credentials - Handle: %s, Password length: %d", handle, len(password))
 
 	// Create and authenticate Bluesky client
-	blueskyClient := bskyclient.New(handle, password)
+	blueskyClient := h.newBlueskyClient(handle, password)
 	if err := blueskyClient.Authenticate(); err != nil {
 		log.Printf("Failed to authenticate: %v", err)
 		return Response{
@@ -102,6 +189,24 @@ func (h *FetcherHandler) Handle(ctx context.Context, event FetcherEvent) (Respon
 		}, err
 	}
 
+	// Pick the post source once per run; resumed invocations keep the run's source
+	feedURI, queries := runState.FeedURI, runState.SearchQueries
+	if !event.Resume {
+		feedURI, queries, err = h.configurePostSource(ctx, blueskyClient, event.RunID, runChannel)
+		if err != nil {
+			log.Printf("Failed to configure post source: %v", err)
+			return Response{
+				StatusCode: 500,
+				Body:       "Failed to configure post source: " + err.Error(),
+			}, err
+		}
+	}
+
+	// A manual run covers a past window, so searches stop at its end rather than now
+	if runState.Manual {
+		blueskyClient.SetSearchUntil(runState.WindowEnd())
+	}
+
 	// Calculate time period details (use UTC to match API timestamps)
 	now := time.Now().UTC()
 	timeWindow := now.Sub(runState.CutoffTime)
@@ -115,16 +220,77 @@ func (h *FetcherHandler) Handle(ctx context.Context, event FetcherEvent) (Respon
 	log.Printf("   ⏱️  Time Window: %s", timeWindow.Round(time.Second))
 	log.Printf("   📊 Analysis Interval: %d minutes", runState.AnalysisIntervalMinutes)
 
+	// Resume from the checkpointed cursor when re-invoked for the same run
+	startCursor := ""
+	if event.Resume {
+		startCursor = runState.CurrentCursor
+		log.Printf("♻️ FETCHER: Resuming from checkpoint - Cursor: '%s', Posts so far: %d, Fetch time so far: %s",
+			startCursor, runState.TotalPostsRetrieved, (time.Duration(runState.FetchElapsedMs) * time.Millisecond).Round(time.Second))
+	}
+
+	// Analyze each batch as it is stored when enabled, spreading analysis over the fetch
+	analyze := h.isAnalyzeDuringFetchEnabled(ctx)
+	if analyze && h.sentimentAnalyzer == nil {
+		h.sentimentAnalyzer = analyzer.New()
+	}
+
+	// Collect image text when enabled, reading a limited number of images per invocation
+	h.imageText = imagetext.NewExtractor(h.getImageTextMode(ctx), h.imageReader, maxImageReadsPerInvocation)
+
 	// Run parallel fetch with internal loops
-	totalPosts, err := h.fetchAllPostsInParallel(ctx, blueskyClient, runState.CutoffTime, event.RunID)
+	fetchStart := time.Now()
+	var counter requestCounter
+	totalPosts, resumeCursor, err := h.fetchAllPostsInParallel(ctx, counter.wrap(newBatchFetcher(blueskyClient, feedURI, queries)), runState.CutoffTime, event.RunID, startCursor, analyze)
+	h.recordFetchRequests(ctx, event.RunID, &counter)
 	if err != nil {
 		log.Printf("Failed to fetch posts: %v", err)
+		h.recordFetchTiming(ctx, event.RunID, fetchStart, state.StepStatusFailed)
 		return Response{
 			StatusCode: 500,
 			Body:       "Failed to fetch posts: " + err.Error(),
 		}, err
 	}
 
+	// Checkpoint and hand off to a fresh invocation if the window isn't fully covered yet
+	if resumeCursor != "" && invocation < maxInvocations {
+		h.recordFetchTiming(ctx, event.RunID, fetchStart, state.StepStatusRunning)
+		if err := h.stateManager.CheckpointFetch(ctx, event.RunID, resumeCursor, time.Since(fetchStart)); err != nil {
+			log.Printf("Failed to checkpoint fetch: %v", err)
+			return Response{
+				StatusCode: 500,
+				Body:       "Failed to checkpoint fetch: " + err.Error(),
+			}, err
+		}
+
+		log.Printf("💾 FETCHER: Checkpointed at cursor '%s' after %d posts, re-invoking fetcher", resumeCursor, totalPosts)
+		if err := h.dispatchFetcherContinuation(ctx, event, invocation+1); err != nil {
+			log.Printf("Failed to dispatch fetcher continuation: %v", err)
+			return Response{
+				StatusCode: 500,
+				Body:       "Failed to dispatch fetcher continuation: " + err.Error(),
+			}, err
+		}
+
+		return Response{
+			StatusCode:     200,
+			Body:           "Fetch checkpointed and continuation dispatched",
+			PostsRetrieved: totalPosts,
+			HasMorePosts:   true,
+		}, nil
+	}
+
+	// Out of invocations before the cutoff: the posts are the window's newest, so the run is
+	// marked truncated and its summary says so rather than passing for the whole window
+	truncated := resumeCursor != ""
+	if truncated {
+		log.Printf("⚠️ FETCHER: Reached max fetch invocations (%d), processing with the posts collected so far", maxInvocations)
+		if err := h.stateManager.MarkFetchTruncated(ctx, event.RunID); err != nil {
+			log.Printf("Failed to mark fetch truncated: %v", err)
+		}
+	}
+
+	h.recordFetchTiming(ctx, event.RunID, fetchStart, state.StepStatusCompleted)
+
 	// Update state to indicate fetching is complete
 	if err := h.stateManager.UpdateCursor(ctx, event.RunID, "", false); err != nil {
 		log.Printf("Failed to update cursor: %v", err)
@@ -134,11 +300,27 @@ func (h *FetcherHandler) Handle(ctx context.Context, event FetcherEvent) (Respon
 		}, err
 	}
 
-	log.Printf("✅ FETCHER: All fetching complete - Run: %s, Total posts retrieved: %d", event.RunID, totalPosts)
+	if truncated {
+		log.Printf("⚠️ FETCHER: Fetching stopped before the cutoff - Run: %s, Total posts retrieved: %d", event.RunID, totalPosts)
+	} else {
+		log.Printf("✅ FETCHER: All fetching complete - Run: %s, Total posts retrieved: %d", event.RunID, totalPosts)
+	}
+
+	// Sample the comparison networks over the same window; they are compared with the
+	// default channel only, and their posts only feed the sentiment history, which a
+	// no-write dry run leaves alone. They sample the latest posts, so a manual run's past
+	// window can't be compared.
+	if runChannel == nil && !runState.Manual {
+		if level := h.getDryRunLevel(ctx); level.Writes() {
+			h.fetchComparisonNetworks(ctx, event.RunID, runState.CutoffTime)
+		} else {
+			log.Printf("🌐 FETCHER: Dry run (%s), skipping the comparison networks", level)
+		}
+	}
 
 	// Dispatch processor
 	log.Printf("🏁 FETCHER: Fetching complete, dispatching processor")
-	err = h.dispatchProcessor(ctx, event.RunID)
+	err = h.dispatchProcessor(ctx, event.RunID, runState.AnalysisIntervalMinutes)
 	if err != nil {
 		log.Printf("Failed to dispatch processor: %v", err)
 		return Response{
@@ -155,43 +337,310 @@ func (h *FetcherHandler) Handle(ctx context.Context, event FetcherEvent) (Respon
 	}, nil
 }
 
+// recordFetchTiming adds this invocation's fetch time to the run's audit trail
+// Resumed invocations merge into the same fetch entry; failures here are logged, not fatal
+func (h *FetcherHandler) recordFetchTiming(ctx context.Context, runID string, startedAt time.Time, status string) {
+	timing := state.NewStepTiming(state.StepFetch, startedAt, status)
+	if err := h.stateManager.RecordStepTimings(ctx, runID, timing); err != nil {
+		log.Printf("Failed to record fetch timing: %v", err)
+	}
+}
+
+// recordFetchRequests adds this invocation's request counts to the run; failures are
+// logged, not fatal
+func (h *FetcherHandler) recordFetchRequests(ctx context.Context, runID string, counter *requestCounter) {
+	requests, failed := counter.counts()
+	if requests == 0 {
+		return
+	}
+	log.Printf("📡 FETCHER: %d API requests, %d failed", requests, failed)
+	if err := h.stateManager.AddFetchRequests(ctx, runID, requests, failed); err != nil {
+		log.Printf("Failed to record fetch requests: %v", err)
+	}
+}
+
+// batchFetcher fetches one page of posts from a run's source
+type batchFetcher func(ctx context.Context, cursor string, cutoffTime time.Time) ([]bskyclient.Post, string, bool, error)
+
+// newBatchFetcher reads from feedURI when set, otherwise from the search queries,
+// falling back to the global search
+func newBatchFetcher(client bskyclient.BskyFetcher, feedURI string, queries []string) batchFetcher {
+	switch {
+	case feedURI != "":
+		return func(ctx context.Context, cursor string, cutoffTime time.Time) ([]bskyclient.Post, string, bool, error) {
+			return client.GetFeedPostsBatch(ctx, feedURI, cursor, cutoffTime)
+		}
+	case len(queries) > 0:
+		return bskyclient.NewMultiSearch(client, queries).Batch
+	default:
+		return client.GetTrendingPostsBatch
+	}
+}
+
+// configurePostSource reads the optional feed and search query settings, or a channel's own,
+// and records them on the run
+// Returns an empty URI and no queries when the global search should be used
+func (h *FetcherHandler) configurePostSource(ctx context.Context, client bskyclient.BskyFetcher, runID string, runChannel *channel.Channel) (string, []string, error) {
+	feedURI, queries, err := h.postSourceSettings(ctx, runChannel)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if feedURI != "" && len(queries) > 0 {
+		return "", nil, fmt.Errorf("%s and %s are both set; configure only one", feedURIParameter, searchQueriesParameter)
+	}
+
+	if len(queries) > 0 {
+		log.Printf("🔎 FETCHER: Fetching posts matching any of %q", queries)
+		if err := h.stateManager.SetSearchQueries(ctx, runID, queries); err != nil {
+			return "", nil, err
+		}
+		return "", queries, nil
+	}
+
+	if feedURI == "" {
+		return "", nil, nil
+	}
+
+	source, err := client.ResolveFeed(ctx, feedURI)
+	if err != nil {
+		return "", nil, err
+	}
+	log.Printf("📰 FETCHER: Fetching from %s (%s)", source.Label(), source.URI)
+
+	if err := h.stateManager.SetFeedSource(ctx, runID, source.URI, source.Label()); err != nil {
+		return "", nil, err
+	}
+	return source.URI, nil, nil
+}
+
+// postSourceSettings returns a channel's feed and queries, or for the default channel those
+// of the feed and search query settings
+func (h *FetcherHandler) postSourceSettings(ctx context.Context, runChannel *channel.Channel) (string, []string, error) {
+	if runChannel != nil {
+		return runChannel.FeedURI, runChannel.Queries, nil
+	}
+	feedURI, err := h.getOptionalParameter(ctx, feedURIParameter)
+	if err != nil {
+		return "", nil, err
+	}
+	queriesValue, err := h.getOptionalParameter(ctx, searchQueriesParameter)
+	if err != nil {
+		return "", nil, err
+	}
+	return feedURI, bskyclient.ParseSearchQueries(queriesValue), nil
+}
+
+// fetchComparisonNetworks stores a sample of each comparison network's posts from the run's
+// window under comparison.RunID. Failures are logged; the Bluesky analysis goes ahead regardless.
+func (h *FetcherHandler) fetchComparisonNetworks(ctx context.Context, runID string, cutoffTime time.Time) {
+	settings, err := comparison.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("⚠️ FETCHER: Skipping comparison networks: %v", err)
+		return
+	}
+	if settings == nil {
+		return
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, comparisonTimeout)
+	defer cancel()
+
+	for _, network := range settings.Networks {
+		posts, err := fetchNetworkSample(fetchCtx, batchFetcher(network.Batch()), cutoffTime, network.MaxPages)
+		if err != nil {
+			log.Printf("⚠️ FETCHER: Failed to fetch %s, keeping %d posts: %v", network.Label(), len(posts), err)
+		}
+		if len(posts) == 0 {
+			continue
+		}
+		if err := h.stateManager.AddPosts(ctx, comparison.RunID(runID, network.Name), h.convertToStatePosts(posts)); err != nil {
+			log.Printf("⚠️ FETCHER: Failed to store %s posts: %v", network.Label(), err)
+			continue
+		}
+		log.Printf("🌐 FETCHER: Stored %d %s posts for comparison", len(posts), network.Label())
+	}
+}
+
+// fetchNetworkSample pages back from the newest posts until the cutoff, maxPages or the end
+// of the timeline, returning the posts inside the window read so far alongside any error
+func fetchNetworkSample(ctx context.Context, fetchBatch batchFetcher, cutoffTime time.Time, maxPages int) ([]bskyclient.Post, error) {
+	var posts []bskyclient.Post
+	cursor := ""
+	for page := 0; page < maxPages; page++ {
+		batch, nextCursor, hasMore, err := fetchBatch(ctx, cursor, cutoffTime)
+		if err != nil {
+			return posts, err
+		}
+
+		reachedCutoff := false
+		for _, post := range batch {
+			if createdAt, err := time.Parse(time.RFC3339, post.CreatedAt); err == nil && createdAt.Before(cutoffTime) {
+				reachedCutoff = true
+				continue
+			}
+			posts = append(posts, post)
+		}
+
+		if reachedCutoff || !hasMore || nextCursor == "" {
+			break
+		}
+		cursor = nextCursor
+	}
+	return posts, nil
+}
+
+// getOptionalParameter reads an SSM setting, returning an empty string when it does not exist
+func (h *FetcherHandler) getOptionalParameter(ctx context.Context, name string) (string, error) {
+	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *ssmtypes.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get %s: %w", name, err)
+	}
+	return strings.TrimSpace(aws.ToString(result.Parameter.Value)), nil
+}
+
+// isAnalyzeDuringFetchEnabled checks the optional fetch-time analysis setting, defaulting to off
+func (h *FetcherHandler) isAnalyzeDuringFetchEnabled(ctx context.Context) bool {
+	value, err := h.getOptionalParameter(ctx, analyzeDuringFetchParameter)
+	if err != nil {
+		log.Printf("⚠️ FETCHER: %v, leaving analysis to the processor", err)
+		return false
+	}
+	return value == "true"
+}
+
+// getImageTextMode reads the optional image text mode, defaulting to off
+func (h *FetcherHandler) getImageTextMode(ctx context.Context) string {
+	value, err := h.getOptionalParameter(ctx, imageTextParameter)
+	if err != nil {
+		log.Printf("⚠️ FETCHER: %v, analyzing post text only", err)
+		return imagetext.ModeOff
+	}
+	mode, err := imagetext.ParseMode(value)
+	if err != nil {
+		log.Printf("⚠️ FETCHER: %v, analyzing post text only", err)
+	}
+	return mode
+}
+
+// getDryRunLevel reads the dry run level, defaulting to off; the processor decides what
+// is stored, so a level the fetcher can't read only costs a wasted comparison fetch
+func (h *FetcherHandler) getDryRunLevel(ctx context.Context) dryrun.Level {
+	level, err := dryrun.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("⚠️ FETCHER: %v, fetching as if not a dry run", err)
+		return dryrun.Off
+	}
+	return level
+}
+
+// addImageText stores the image text of each post with images, in the same order as posts
+func (h *FetcherHandler) addImageText(ctx context.Context, posts []bskyclient.Post, statePosts []state.Post) {
+	withText := 0
+	for i, post := range posts {
+		if len(post.Images) == 0 {
+			continue
+		}
+		statePosts[i].ImageText = h.imageText.Text(ctx, post.Images)
+		if statePosts[i].ImageText != "" {
+			withText++
+		}
+	}
+	log.Printf("🖼️ FETCHER: Added image text to %d of %d posts (%d image reads left)", withText, len(posts), h.imageText.Reads())
+}
+
+// analyzeBatch analyzes posts in place before they are stored. A failure only logs: the
+// posts are stored unanalyzed and the processor analyzes them instead.
+func (h *FetcherHandler) analyzeBatch(posts []state.Post) {
+	analyzerPosts := make([]analyzer.Post, len(posts))
+	for i, post := range posts {
+		analyzerPosts[i] = analyzer.Post{
+			URI:       post.URI,
+			CID:       post.CID,
+			Text:      post.AnalysisText(),
+			Author:    post.Author,
+			Likes:     post.Likes,
+			Reposts:   post.Reposts,
+			Replies:   post.Replies,
+			CreatedAt: post.CreatedAt,
+			Langs:     post.Langs,
+		}
+	}
+
+	analyzed, err := h.sentimentAnalyzer.AnalyzePosts(analyzerPosts)
+	if err != nil {
+		log.Printf("⚠️ FETCHER: Failed to analyze batch, leaving it to the processor: %v", err)
+		return
+	}
+	for i := range posts {
+		analyzed[i].Apply(&posts[i])
+	}
+}
+
 // fetchAllPostsInParallel fetches all posts using parallel API calls and internal loops
-func (h *FetcherHandler) fetchAllPostsInParallel(ctx context.Context, client *bskyclient.BlueskyClient, cutoffTime time.Time, runID string) (int, error) {
+// Returns a non-empty resume cursor when the invocation ran out of time before reaching the cutoff.
+// When analyze is set each batch is analyzed before it is buffered.
+func (h *FetcherHandler) fetchAllPostsInParallel(ctx context.Context, fetchBatch batchFetcher, cutoffTime time.Time, runID string, startCursor string, analyze bool) (int, string, error) {
 	var totalPosts int
-	currentCursor := "" // Start with empty cursor to get most recent posts
+	currentCursor := startCursor // Empty cursor starts from the most recent posts
 	iteration := 0
-	maxIterations := 100 // Increased for sequential pagination (100 pages * 100 posts = 10,000 posts max)
 
 	// Track URIs to detect duplicates per iteration
 	seenURIs := make(map[string]bool)
 
-	// Track start time for early-stop logic (stop at 14 minutes to allow 1 min for dispatch)
-	startTime := time.Now()
-	earlyStopTime := 14 * time.Minute // Stop at 14 minutes to leave 1 minute for dispatch
-	minPostsForEarlyStop := 1000      // Minimum posts needed for early stop
+	// Fetch requests run under their own deadline so they are cancelled in time to leave
+	// the writes and checkpoint below enough of the Lambda's remaining time
+	deadline := fetchDeadline(ctx)
+	fetchCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+	log.Printf("⏱️  FETCHER: Fetching until %s (%s from now)", deadline.UTC().Format("15:04:05 UTC"), time.Until(deadline).Round(time.Second))
+
+	// Buffer posts across iterations; everything buffered is flushed before returning
+	buffer := newPostBuffer(h.stateManager, runID)
 
 	log.Printf("🔄 FETCHER: Starting sequential fetch for posts since %s (sort=latest)", cutoffTime.Format("2006-01-02 15:04:05 UTC"))
 
 	for {
-		// Check time before starting new iteration - don't start if we're at 14 minutes and have enough posts
-		elapsed := time.Since(startTime)
-		if elapsed >= earlyStopTime && totalPosts > minPostsForEarlyStop {
-			log.Printf("⏰ FETCHER: Early stop triggered before iteration - Elapsed: %s, Posts: %d (>%d)", elapsed.Round(time.Second), totalPosts, minPostsForEarlyStop)
-			log.Printf("⏰ FETCHER: Stopping early to ensure processor dispatch before timeout (leaving 1 minute buffer)")
-			break
+		// Check the fetch deadline before starting new iteration - checkpoint rather than risk the Lambda timeout
+		if fetchCtx.Err() != nil {
+			log.Printf("⏰ FETCHER: Checkpoint triggered before iteration - Fetch deadline reached, Posts: %d, Cursor: '%s'", totalPosts, currentCursor)
+			if err := flushPosts(ctx, buffer); err != nil {
+				return totalPosts, "", err
+			}
+			return totalPosts, currentCursor, nil
 		}
 
-		iteration++
-		if iteration > maxIterations {
-			log.Printf("⚠️ FETCHER: Reached max iterations (%d), stopping", maxIterations)
-			break
+		// Hand the rest of the window to the next invocation once this one has fetched its pages
+		if iteration >= maxIterationsPerInvocation {
+			log.Printf("⏸️ FETCHER: Reached max iterations (%d), checkpointing at cursor '%s'", maxIterationsPerInvocation, currentCursor)
+			if err := flushPosts(ctx, buffer); err != nil {
+				return totalPosts, "", err
+			}
+			return totalPosts, currentCursor, nil
 		}
+		iteration++
 
 		log.Printf("🔄 FETCHER: Starting iteration %d with cursor: '%s'", iteration, currentCursor)
 
 		// Make a single API call with proper cursor-based pagination
-		posts, nextCursor, hasMore, err := client.GetTrendingPostsBatch(ctx, currentCursor, cutoffTime)
+		posts, nextCursor, hasMore, err := fetchBatch(fetchCtx, currentCursor, cutoffTime)
 		if err != nil {
+			// The fetch deadline cancelled this page; checkpoint so the next invocation retries it
+			if fetchCtx.Err() != nil && ctx.Err() == nil {
+				log.Printf("⏰ FETCHER: Fetch deadline reached during iteration %d, checkpointing at cursor '%s'", iteration, currentCursor)
+				if err := flushPosts(ctx, buffer); err != nil {
+					return totalPosts, "", err
+				}
+				return totalPosts, currentCursor, nil
+			}
+
 			// Handle timeout errors gracefully - skip this cursor and continue
 			if strings.Contains(err.Error(), "context deadline exceeded") || strings.Contains(err.Error(), "timeout") {
 				log.Printf("⚠️ FETCHER: Timeout error at iteration %d with cursor '%s', skipping this cursor and continuing", iteration, currentCursor)
@@ -209,8 +658,11 @@ func (h *FetcherHandler) fetchAllPostsInParallel(ctx context.Context, client *bs
 				log.Printf("⚠️ FETCHER: Cannot advance cursor after timeout, stopping with %d posts collected", totalPosts)
 				break
 			}
-			// For other errors, return immediately
-			return totalPosts, fmt.Errorf("failed to fetch batch at iteration %d: %w", iteration, err)
+			// For other errors, keep what was fetched and return immediately
+			if flushErr := flushPosts(ctx, buffer); flushErr != nil {
+				log.Printf("⚠️ FETCHER: %v", flushErr)
+			}
+			return totalPosts, "", fmt.Errorf("failed to fetch batch at iteration %d: %w", iteration, err)
 		}
 
 		log.Printf("📊 FETCHER: Iteration %d - API returned %d posts (nextCursor: '%s', hasMore: %v)",
@@ -264,13 +716,18 @@ func (h *FetcherHandler) fetchAllPostsInParallel(ctx context.Context, client *bs
 		log.Printf("🔄 FETCHER: Iteration %d - Fetched %d posts, %d duplicates (Total unique URIs: %d)",
 			iteration, len(posts), iterationDuplicates, len(seenURIs))
 
-		// Convert to state posts and store
+		// Convert to state posts and buffer them for storage
 		statePosts := h.convertToStatePosts(posts)
-		log.Printf("💾 FETCHER: Storing %d posts from iteration %d", len(statePosts), iteration)
-
-		if err := h.stateManager.AddPosts(ctx, runID, statePosts); err != nil {
-			return totalPosts, fmt.Errorf("failed to add posts: %w", err)
+		if h.imageText.Enabled() {
+			h.addImageText(ctx, posts, statePosts)
+		}
+		if analyze {
+			h.analyzeBatch(statePosts)
+		}
+		if err := buffer.Add(ctx, statePosts); err != nil {
+			return totalPosts, "", wrapAddPostsError(err)
 		}
+		log.Printf("💾 FETCHER: Buffered %d posts from iteration %d (%d awaiting write)", len(statePosts), iteration, buffer.Len())
 
 		totalPosts += len(posts)
 
@@ -294,21 +751,6 @@ func (h *FetcherHandler) fetchAllPostsInParallel(ctx context.Context, client *bs
 
 		log.Printf("✅ FETCHER: Iteration %d complete - Retrieved %d posts (Total: %d)", iteration, len(posts), totalPosts)
 
-		// Early stop check after iteration: If we're at 14 minutes and have enough posts, stop to ensure dispatch
-		elapsed = time.Since(startTime)
-		if elapsed >= earlyStopTime && totalPosts > minPostsForEarlyStop {
-			log.Printf("⏰ FETCHER: Early stop triggered after iteration - Elapsed: %s, Posts: %d (>%d)", elapsed.Round(time.Second), totalPosts, minPostsForEarlyStop)
-			log.Printf("⏰ FETCHER: Stopping early to ensure processor dispatch before timeout (leaving 1 minute buffer)")
-			break
-		}
-
-		// Log time remaining if we're getting close
-		if elapsed >= 12*time.Minute && elapsed < earlyStopTime {
-			remaining := earlyStopTime - elapsed
-			log.Printf("⏱️  FETCHER: Time check - Elapsed: %s, Remaining before early stop: %s, Posts: %d",
-				elapsed.Round(time.Second), remaining.Round(time.Second), totalPosts)
-		}
-
 		// Check if we've reached posts before our time window or no more pages
 		if shouldStop {
 			log.Printf("⏰ FETCHER: Found posts before time window, stopping at iteration %d", iteration)
@@ -320,22 +762,87 @@ func (h *FetcherHandler) fetchAllPostsInParallel(ctx context.Context, client *bs
 			break
 		}
 
+		// Checkpoint after iteration if we're out of time, resuming from the next page
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			log.Printf("⏰ FETCHER: Checkpoint triggered after iteration - Fetch deadline reached, Posts: %d, Next cursor: '%s'", totalPosts, nextCursor)
+			if err := flushPosts(ctx, buffer); err != nil {
+				return totalPosts, "", err
+			}
+			return totalPosts, nextCursor, nil
+		}
+
+		// Log time remaining if we're getting close
+		if remaining <= 2*time.Minute {
+			log.Printf("⏱️  FETCHER: Time check - Remaining before checkpoint: %s, Posts: %d",
+				remaining.Round(time.Second), totalPosts)
+		}
+
 		// Use the API's returned cursor for the next iteration
 		currentCursor = nextCursor
 		log.Printf("➡️ FETCHER: Preparing next iteration with API cursor: '%s'", currentCursor)
 	}
 
+	if err := flushPosts(ctx, buffer); err != nil {
+		return totalPosts, "", err
+	}
+
 	log.Printf("🏁 FETCHER: Sequential fetch complete - Total posts: %d across %d iterations", totalPosts, iteration)
-	return totalPosts, nil
+	return totalPosts, "", nil
+}
+
+// fetchInvocationBudget is how many invocations may fetch a run's window:
+// fetchInvocationsPerHour for each started hour, so multi-hour and daily windows get
+// a page budget in proportion to their length
+func fetchInvocationBudget(intervalMinutes int) int {
+	hours := (intervalMinutes + 59) / 60
+	return fetchInvocationsPerHour * max(hours, 1)
+}
+
+// fetchDeadline is when fetching must stop: shutdownReserve before the Lambda deadline,
+// or checkpointAfter from now when the context has no deadline
+func fetchDeadline(ctx context.Context) time.Time {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return time.Now().Add(checkpointAfter)
+	}
+	if lc, ok := lambdacontext.FromContext(ctx); ok {
+		log.Printf("⏱️  FETCHER: Request %s has %s remaining", lc.AwsRequestID, time.Until(deadline).Round(time.Second))
+	}
+	return deadline.Add(-shutdownReserve)
+}
+
+// flushPosts writes any posts still in the buffer
+func flushPosts(ctx context.Context, buffer *postBuffer) error {
+	if err := buffer.Flush(ctx); err != nil {
+		return wrapAddPostsError(err)
+	}
+	return nil
+}
+
+// wrapAddPostsError logs how many posts were lost to a partial write and wraps the error
+func wrapAddPostsError(err error) error {
+	var partial *state.PartialWriteError
+	if errors.As(err, &partial) {
+		log.Printf("❌ FETCHER: Stored %d posts, failed to store %d", partial.Stored, partial.Failed)
+	}
+	return fmt.Errorf("failed to add posts: %w", err)
 }
 
 // convertToStatePosts converts client posts to state posts
 func (h *FetcherHandler) convertToStatePosts(posts []bskyclient.Post) []state.Post {
+	fetchedAt := time.Now()
 	statePosts := make([]state.Post, len(posts))
 	for i, post := range posts {
 		// Calculate engagement score (same formula as in analyzer)
 		engagementScore := float64(post.Replies + post.Likes + post.Reposts)
 
+		// The post's age when its engagement was counted, for velocity ranking
+		var ageMinutes float64
+		if createdAt, err := time.Parse(time.RFC3339, post.CreatedAt); err == nil {
+			ageMinutes = max(0, fetchedAt.Sub(createdAt).Minutes())
+		}
+
 		statePosts[i] = state.Post{
 			URI:             post.URI,
 			CID:             post.CID,
@@ -347,55 +854,77 @@ func (h *FetcherHandler) convertToStatePosts(posts []bskyclient.Post) []state.Po
 			CreatedAt:       post.CreatedAt,
 			Sentiment:       post.Sentiment,
 			EngagementScore: engagementScore,
+			Langs:           post.Langs,
+			Media:           post.Media,
+			LinkURL:         post.LinkURL,
+			ReplyRoot:       post.ReplyRoot,
+			ContentWarnings: post.ContentWarnings,
+			AgeMinutes:      ageMinutes,
+			AvatarURL:       post.AvatarURL,
 		}
 	}
 	return statePosts
 }
 
-// getBlueskyCredentials retrieves credentials from SSM Parameter Store
-func (h *FetcherHandler) getBlueskyCredentials(ctx context.Context) (string, string, error) {
-	log.Printf("🔐 FETCHER: Attempting to retrieve credentials from SSM...")
+// getBlueskyCredentials retrieves the run channel's credentials: the default account's from
+// the Secrets Manager secret or SSM, and a channel's own account's from its SSM path
+func (h *FetcherHandler) getBlueskyCredentials(ctx context.Context, runChannel *channel.Channel) (string, string, error) {
+	provider := h.credentials
+	if runChannel.HasOwnCredentials() {
+		handleName, passwordName := runChannel.CredentialParameters()
+		provider = credentials.NewSSMProvider(h.ssmClient, handleName, passwordName)
+	}
 
-	handleParam, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
-		Name:           aws.String("/hourstats/bluesky/handle"),
-		WithDecryption: aws.Bool(false),
-	})
+	creds, err := provider.Retrieve(ctx)
 	if err != nil {
-		log.Printf("❌ FETCHER: Failed to get handle parameter: %v", err)
-		return "", "", fmt.Errorf("failed to get handle parameter: %w", err)
+		log.Printf("❌ FETCHER: Failed to get credentials: %v", err)
+		return "", "", err
 	}
-	log.Printf("✅ FETCHER: Successfully retrieved handle parameter")
+	log.Printf("🔐 FETCHER: Credentials retrieved - Handle: %s, Password length: %d", creds.Handle, len(creds.Password))
+	return creds.Handle, creds.Password, nil
+}
 
-	passwordParam, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
-		Name:           aws.String("/hourstats/bluesky/password"),
-		WithDecryption: aws.Bool(true),
-	})
+// dispatchFetcherContinuation re-invokes the fetcher lambda to resume from the checkpointed cursor
+func (h *FetcherHandler) dispatchFetcherContinuation(ctx context.Context, event events.FetcherEvent, invocation int) error {
+	continuation := events.FetcherEvent{
+		Version:                 events.Version,
+		RunID:                   event.RunID,
+		AnalysisIntervalMinutes: event.AnalysisIntervalMinutes,
+		Status:                  "fetching",
+		Resume:                  true,
+		Invocation:              invocation,
+	}
+
+	payloadBytes, err := events.Marshal(continuation)
 	if err != nil {
-		log.Printf("❌ FETCHER: Failed to get password parameter: %v", err)
-		return "", "", fmt.Errorf("failed to get password parameter: %w", err)
+		return fmt.Errorf("failed to marshal fetcher payload: %w", err)
 	}
-	log.Printf("✅ FETCHER: Successfully retrieved password parameter")
 
-	handle := aws.ToString(handleParam.Parameter.Value)
-	password := aws.ToString(passwordParam.Parameter.Value)
+	_, err = h.lambdaClient.Invoke(ctx, &awslambda.InvokeInput{
+		FunctionName:   aws.String(events.FunctionName(events.FetcherFunctionEnv, events.FetcherFunction)),
+		Payload:        payloadBytes,
+		InvocationType: "Event",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to invoke fetcher: %w", err)
+	}
 
-	log.Printf("🔐 FETCHER: Credentials retrieved - Handle: %s, Password length: %d", handle, len(password))
-	return handle, password, nil
+	return nil
 }
 
 // dispatchProcessor invokes the processor lambda
-func (h *FetcherHandler) dispatchProcessor(ctx context.Context, runID string) error {
-	processorPayload := map[string]interface{}{
-		"runId": runID,
-	}
-
-	payloadBytes, err := json.Marshal(processorPayload)
+func (h *FetcherHandler) dispatchProcessor(ctx context.Context, runID string, analysisIntervalMinutes int) error {
+	payloadBytes, err := events.Marshal(events.ProcessorEvent{
+		Version:                 events.Version,
+		RunID:                   runID,
+		AnalysisIntervalMinutes: analysisIntervalMinutes,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to marshal processor payload: %w", err)
 	}
 
 	_, err = h.lambdaClient.Invoke(ctx, &awslambda.InvokeInput{
-		FunctionName:   aws.String("hourstats-processor"),
+		FunctionName:   aws.String(events.FunctionName(events.ProcessorFunctionEnv, events.ProcessorFunction)),
 		Payload:        payloadBytes,
 		InvocationType: "Event",
 	})
@@ -407,11 +936,15 @@ func (h *FetcherHandler) dispatchProcessor(ctx context.Context, runID string) er
 }
 
 func main() {
+	if err := tracing.Init("hourstats-fetcher"); err != nil {
+		log.Printf("Tracing disabled: %v", err)
+	}
+
 	ctx := context.Background()
 	handler, err := NewFetcherHandler(ctx)
 	if err != nil {
 		log.Fatalf("Failed to create fetcher handler: %v", err)
 	}
 
-	lambda.Start(handler.Handle)
+	lambda.Start(tracing.Handler(events.Handler(handler.Handle)))
 }
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/analyzer"
+	bskyclient "github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func TestFetchDeadline(t *testing.T) {
+	lambdaDeadline := time.Now().Add(15 * time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), lambdaDeadline)
+	defer cancel()
+
+	if got := fetchDeadline(ctx); !got.Equal(lambdaDeadline.Add(-shutdownReserve)) {
+		t.Errorf("expected fetching to stop %s before the Lambda deadline, got %s", shutdownReserve, lambdaDeadline.Sub(got))
+	}
+
+	before := time.Now()
+	got := fetchDeadline(context.Background())
+	if got.Before(before.Add(checkpointAfter)) || got.After(time.Now().Add(checkpointAfter)) {
+		t.Errorf("expected a deadline %s from now without a context deadline, got %s", checkpointAfter, time.Until(got))
+	}
+}
+
+func TestFetchNetworkSample(t *testing.T) {
+	cutoff := time.Date(2025, 9, 2, 12, 0, 0, 0, time.UTC)
+	// Three pages of two posts, ten minutes apart, newest first
+	pages := map[string][]bskyclient.Post{}
+	for page := 0; page < 3; page++ {
+		for i := 0; i < 2; i++ {
+			minute := 25 - (page*2+i)*10
+			pages[fmt.Sprint(page)] = append(pages[fmt.Sprint(page)], bskyclient.Post{
+				URI:       fmt.Sprintf("post-%d", minute),
+				CreatedAt: cutoff.Add(time.Duration(minute) * time.Minute).Format(time.RFC3339),
+			})
+		}
+	}
+	calls := 0
+	fetchBatch := func(ctx context.Context, cursor string, cutoffTime time.Time) ([]bskyclient.Post, string, bool, error) {
+		calls++
+		if cursor == "" {
+			cursor = "0"
+		}
+		next := fmt.Sprint(calls)
+		return pages[cursor], next, true, nil
+	}
+
+	posts, err := fetchNetworkSample(context.Background(), fetchBatch, cutoff, 10)
+	if err != nil {
+		t.Fatalf("fetchNetworkSample() error = %v", err)
+	}
+	// Minutes 25, 15, 5 are in the window; -5 stops paging
+	if len(posts) != 3 || calls != 2 {
+		t.Errorf("Expected 3 posts from 2 pages, got %d posts from %d pages", len(posts), calls)
+	}
+
+	calls = 0
+	posts, _ = fetchNetworkSample(context.Background(), fetchBatch, cutoff, 1)
+	if len(posts) != 2 || calls != 1 {
+		t.Errorf("Expected the page cap to stop after 1 page, got %d posts from %d pages", len(posts), calls)
+	}
+
+	failing := func(ctx context.Context, cursor string, cutoffTime time.Time) ([]bskyclient.Post, string, bool, error) {
+		if cursor == "" {
+			return pages["0"], "1", true, nil
+		}
+		return nil, "", false, errors.New("rate limited")
+	}
+	posts, err = fetchNetworkSample(context.Background(), failing, cutoff, 10)
+	if err == nil || len(posts) != 2 {
+		t.Errorf("Expected the first page kept alongside the error, got %d posts, %v", len(posts), err)
+	}
+}
+
+func TestAnalyzeBatch(t *testing.T) {
+	h := &FetcherHandler{sentimentAnalyzer: analyzer.New()}
+	posts := []state.Post{
+		{URI: "at://post/1", Text: "What a wonderful day, I love it"},
+		{URI: "at://post/2", Text: "Qué día tan horrible", Langs: []string{"es"}},
+	}
+
+	h.analyzeBatch(posts)
+
+	if !posts[0].Analyzed || posts[0].Sentiment != "positive" {
+		t.Errorf("Expected the first post analyzed as positive, got %+v", posts[0])
+	}
+	if !posts[1].Analyzed || posts[1].Sentiment != "negative" {
+		t.Errorf("Expected the second post analyzed as negative, got %+v", posts[1])
+	}
+}
+
+func TestConvertToStatePostsRecordsAge(t *testing.T) {
+	h := &FetcherHandler{}
+	posts := h.convertToStatePosts([]bskyclient.Post{
+		{URI: "at://post/1", CreatedAt: time.Now().Add(-45 * time.Minute).Format(time.RFC3339), ReplyRoot: "at://post/0"},
+		{URI: "at://post/2", CreatedAt: "not a time"},
+	})
+
+	if posts[0].AgeMinutes < 44.9 || posts[0].AgeMinutes > 46 {
+		t.Errorf("Expected the first post about 45 minutes old, got %.1f", posts[0].AgeMinutes)
+	}
+	if !posts[0].IsReply() || posts[0].ReplyRoot != "at://post/0" {
+		t.Errorf("Expected the first post to keep its reply root, got %q", posts[0].ReplyRoot)
+	}
+	if posts[1].AgeMinutes != 0 {
+		t.Errorf("Expected no age without a creation time, got %.1f", posts[1].AgeMinutes)
+	}
+}
+
+func TestRequestCounter(t *testing.T) {
+	var counter requestCounter
+	calls := 0
+	fetchBatch := counter.wrap(func(ctx context.Context, cursor string, cutoffTime time.Time) ([]bskyclient.Post, string, bool, error) {
+		calls++
+		if calls == 2 {
+			return nil, "", false, errors.New("502 Bad Gateway")
+		}
+		return nil, "", false, ctx.Err()
+	})
+
+	fetchBatch(context.Background(), "", time.Time{})
+	fetchBatch(context.Background(), "", time.Time{})
+	// Requests the fetcher cancelled at its own deadline aren't the API's failures
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	fetchBatch(cancelled, "", time.Time{})
+
+	if requests, failed := counter.counts(); requests != 2 || failed != 1 {
+		t.Errorf("counts() = %d requests, %d failed, want 2 and 1", requests, failed)
+	}
+}
+
+func TestFetchInvocationBudget(t *testing.T) {
+	tests := map[int]int{
+		15:   fetchInvocationsPerHour,
+		60:   fetchInvocationsPerHour,
+		90:   2 * fetchInvocationsPerHour,
+		360:  6 * fetchInvocationsPerHour,
+		1440: 24 * fetchInvocationsPerHour,
+	}
+	for interval, want := range tests {
+		if got := fetchInvocationBudget(interval); got != want {
+			t.Errorf("fetchInvocationBudget(%d) = %d, want %d", interval, got, want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	bskyclient "github.com/christophergentle/hourstats-bsky/internal/client"
+)
+
+// requestCounter counts the page requests a batchFetcher makes and how many fail, for the
+// processor's health gate
+type requestCounter struct {
+	requests atomic.Int64
+	failed   atomic.Int64
+}
+
+// wrap returns fetchBatch counting into c. Requests the fetcher cancelled itself, at its
+// deadline, are not counted as failures.
+func (c *requestCounter) wrap(fetchBatch batchFetcher) batchFetcher {
+	return func(ctx context.Context, cursor string, cutoffTime time.Time) ([]bskyclient.Post, string, bool, error) {
+		posts, nextCursor, hasMore, err := fetchBatch(ctx, cursor, cutoffTime)
+		if ctx.Err() == nil {
+			c.requests.Add(1)
+			if err != nil {
+				c.failed.Add(1)
+			}
+		}
+		return posts, nextCursor, hasMore, err
+	}
+}
+
+// counts returns the requests and failures counted so far
+func (c *requestCounter) counts() (int, int) {
+	return int(c.requests.Load()), int(c.failed.Load())
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/interval"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// adaptiveIntervals are the analysis windows the orchestrator may choose between, shortest first
+var adaptiveIntervals = []int{15, 30, 60}
+
+const (
+	// targetPostsPerRun is the number of posts a single fetcher invocation can reliably
+	// collect before hitting the 14-minute early stop (100 iterations * 100 posts max)
+	targetPostsPerRun = 8000
+
+	// recentRunsLookback is how far back to look for runs when estimating post volume
+	recentRunsLookback = 3 * time.Hour
+
+	// recentRunsSample is the maximum number of recent runs used for the estimate
+	recentRunsSample = 4
+
+	// scheduleJitter is how far a scheduled invocation may drift from its period; a
+	// previous window ending within it of this run's start is continued from its end
+	scheduleJitter = 5 * time.Minute
+)
+
+// chooseAnalysisInterval picks the longest adaptive interval whose expected post volume fits
+// within targetPostsPerRun, based on the posts-per-minute rate observed in recent runs. The
+// requested interval is the schedule's period, so the choice never goes below it: a shorter
+// window would leave the rest of the period unanalyzed, and a busy period is sampled instead.
+// Returns the requested interval unchanged when there is no usable history, or when it is
+// longer than every adaptive interval.
+func chooseAnalysisInterval(requested int, recentRuns []state.RunState) (int, string) {
+	if requested > adaptiveIntervals[len(adaptiveIntervals)-1] {
+		return requested, fmt.Sprintf("%d-minute interval is longer than any adaptive interval", requested)
+	}
+
+	postsPerMinute, sampled := estimatePostsPerMinute(recentRuns)
+	if sampled == 0 {
+		return requested, "no recent run history, using requested interval"
+	}
+
+	chosen := requested
+	for _, interval := range adaptiveIntervals {
+		if interval > requested && postsPerMinute*float64(interval) <= targetPostsPerRun {
+			chosen = interval
+		}
+	}
+
+	if chosen == requested {
+		if expected := postsPerMinute * float64(requested); expected > targetPostsPerRun {
+			return requested, fmt.Sprintf("%.0f posts/min across %d recent runs, expected %.0f posts in %d minutes (target %d), sampling the requested interval",
+				postsPerMinute, sampled, expected, requested, targetPostsPerRun)
+		}
+		return requested, fmt.Sprintf("%.0f posts/min across %d recent runs fits requested interval", postsPerMinute, sampled)
+	}
+
+	return chosen, fmt.Sprintf("%.0f posts/min across %d recent runs, expected %.0f posts in %d minutes (target %d)",
+		postsPerMinute, sampled, postsPerMinute*float64(requested), requested, targetPostsPerRun)
+}
+
+// anchorCutoff starts a scheduled run where the latest scheduled run's window ended, so
+// consecutive windows neither overlap nor leave gaps when the adaptive interval differs from
+// the schedule's period, and returns the run's cutoff and interval. It reports false when
+// that window still covers part of this one, as when a 60-minute window is chosen on a
+// 30-minute schedule; the invocation is skipped and a later one runs the window. A window
+// that ended earlier than this one would start is caught up by growing the interval up to
+// the longest adaptive interval; an older one, after missed or failed invocations, isn't,
+// and the run covers its interval up to now.
+func anchorCutoff(now time.Time, intervalMinutes int, recentRuns []state.RunState) (time.Time, int, bool) {
+	start := now.Add(-interval.Duration(intervalMinutes))
+
+	var previousEnd time.Time
+	for i := range recentRuns {
+		if recentRuns[i].Manual {
+			continue // Manual runs analyze past windows off the schedule
+		}
+		if end := recentRuns[i].WindowEnd(); end.After(previousEnd) {
+			previousEnd = end
+		}
+	}
+	if previousEnd.IsZero() {
+		return start, intervalMinutes, true
+	}
+
+	longest := adaptiveIntervals[len(adaptiveIntervals)-1]
+	switch {
+	case previousEnd.After(start.Add(scheduleJitter)):
+		return previousEnd, intervalMinutes, false
+	case !previousEnd.Before(start.Add(-scheduleJitter)):
+		return previousEnd, intervalMinutes, true
+	case previousEnd.Before(now.Add(-interval.Duration(longest) - scheduleJitter)):
+		return start, intervalMinutes, true
+	default:
+		elapsed := int(now.Sub(previousEnd).Round(time.Minute) / time.Minute)
+		return previousEnd, min(elapsed, longest), true
+	}
+}
+
+// estimatePostsPerMinute averages the post rate of the most recent runs that retrieved posts
+// Runs are expected most recent first, as returned by StateManager.GetRecentRuns
+func estimatePostsPerMinute(recentRuns []state.RunState) (float64, int) {
+	var totalRate float64
+	sampled := 0
+
+	for _, run := range recentRuns {
+		if sampled >= recentRunsSample {
+			break
+		}
+		if run.Status != "analyzed" && run.Status != "completed" {
+			continue // Skip failed or in-progress runs, their totals are partial
+		}
+		if run.TotalPostsRetrieved == 0 || run.AnalysisIntervalMinutes <= 0 {
+			continue
+		}
+		totalRate += float64(run.TotalPostsRetrieved) / float64(run.AnalysisIntervalMinutes)
+		sampled++
+	}
+
+	if sampled == 0 {
+		return 0, 0
+	}
+
+	return totalRate / float64(sampled), sampled
+}
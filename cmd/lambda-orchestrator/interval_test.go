@@ -0,0 +1,105 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChooseAnalysisIntervalNoHistory(t *testing.T) {
+	interval, reason := chooseAnalysisInterval(30, nil)
+
+	assert.Equal(t, 30, interval)
+	assert.Contains(t, reason, "no recent run history")
+}
+
+func TestChooseAnalysisIntervalKeepsScheduleOnHighVolume(t *testing.T) {
+	// 400 posts/min means 30 minutes would be 12,000 posts, over the target; a shorter
+	// window would leave the rest of the schedule's period unanalyzed
+	recentRuns := []state.RunState{
+		{Status: "completed", TotalPostsRetrieved: 12000, AnalysisIntervalMinutes: 30},
+		{Status: "analyzed", TotalPostsRetrieved: 12000, AnalysisIntervalMinutes: 30},
+	}
+
+	interval, reason := chooseAnalysisInterval(30, recentRuns)
+	assert.Equal(t, 30, interval)
+	assert.Contains(t, reason, "sampling")
+}
+
+func TestChooseAnalysisIntervalGrowsOnLowVolume(t *testing.T) {
+	// 50 posts/min fits a full hour
+	recentRuns := []state.RunState{
+		{Status: "completed", TotalPostsRetrieved: 1500, AnalysisIntervalMinutes: 30},
+	}
+
+	interval, _ := chooseAnalysisInterval(30, recentRuns)
+	assert.Equal(t, 60, interval)
+}
+
+func TestChooseAnalysisIntervalKeepsMultiHourIntervals(t *testing.T) {
+	recentRuns := []state.RunState{
+		{Status: "completed", TotalPostsRetrieved: 12000, AnalysisIntervalMinutes: 30},
+	}
+
+	interval, reason := chooseAnalysisInterval(360, recentRuns)
+	assert.Equal(t, 360, interval)
+	assert.Contains(t, reason, "longer than any adaptive interval")
+}
+
+func TestAnchorCutoffConsecutiveRuns(t *testing.T) {
+	// A 30-minute schedule whose adaptive interval changes between ticks, with a bit of jitter
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	intervals := []int{30, 60, 60, 60, 30, 30, 60, 30}
+	var runs []state.RunState
+	var analyzed []state.RunState
+
+	for i, minutes := range intervals {
+		now := start.Add(time.Duration(i)*30*time.Minute + time.Duration(i%3)*time.Minute)
+		cutoff, minutes, ready := anchorCutoff(now, minutes, runs)
+		if !ready {
+			continue
+		}
+		run := state.RunState{CutoffTime: cutoff, AnalysisIntervalMinutes: minutes}
+		runs = append([]state.RunState{run}, runs...)
+		analyzed = append(analyzed, run)
+	}
+
+	assert.Greater(t, len(analyzed), 1)
+	last := analyzed[len(analyzed)-1].WindowEnd()
+	finalTick := start.Add(time.Duration(len(intervals)-1) * 30 * time.Minute)
+	assert.LessOrEqual(t, finalTick.Sub(last), scheduleJitter, "windows fell behind the schedule, ending at %s", last)
+	for i := 1; i < len(analyzed); i++ {
+		assert.True(t, analyzed[i].CutoffTime.Equal(analyzed[i-1].WindowEnd()),
+			"run %d starts at %s, the previous run ended at %s", i, analyzed[i].CutoffTime, analyzed[i-1].WindowEnd())
+	}
+}
+
+func TestAnchorCutoffIgnoresManualAndStaleRuns(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// A manual run over the current window doesn't claim it
+	manual := []state.RunState{{CutoffTime: now.Add(-30 * time.Minute), AnalysisIntervalMinutes: 30, Manual: true}}
+	cutoff, _, ready := anchorCutoff(now, 30, manual)
+	assert.True(t, ready)
+	assert.Equal(t, now.Add(-30*time.Minute), cutoff)
+
+	// After missed invocations the run covers its interval up to now rather than catching up
+	stale := []state.RunState{{CutoffTime: now.Add(-3 * time.Hour), AnalysisIntervalMinutes: 30}}
+	cutoff, _, ready = anchorCutoff(now, 30, stale)
+	assert.True(t, ready)
+	assert.Equal(t, now.Add(-30*time.Minute), cutoff)
+}
+
+func TestEstimatePostsPerMinuteSkipsIncompleteRuns(t *testing.T) {
+	recentRuns := []state.RunState{
+		{Status: "fetching", TotalPostsRetrieved: 100, AnalysisIntervalMinutes: 30},
+		{Status: "completed", TotalPostsRetrieved: 0, AnalysisIntervalMinutes: 30},
+		{Status: "completed", TotalPostsRetrieved: 3000, AnalysisIntervalMinutes: 30},
+	}
+
+	rate, sampled := estimatePostsPerMinute(recentRuns)
+	assert.Equal(t, 1, sampled)
+	assert.Equal(t, 100.0, rate)
+}
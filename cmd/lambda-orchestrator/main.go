@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"time"
@@ -12,7 +11,14 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	awslambda "github.com/aws/aws-sdk-go-v2/service/lambda"
 	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/christophergentle/hourstats-bsky/internal/channel"
+	appconfig "github.com/christophergentle/hourstats-bsky/internal/config"
+	"github.com/christophergentle/hourstats-bsky/internal/events"
+	"github.com/christophergentle/hourstats-bsky/internal/interval"
+	"github.com/christophergentle/hourstats-bsky/internal/retention"
 	"github.com/christophergentle/hourstats-bsky/internal/state"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
 )
 
 // Event represents the EventBridge event structure or Step Functions event
@@ -23,6 +29,15 @@ type Event struct {
 	RunID                   string `json:"runId,omitempty"`
 	IsComplete              bool   `json:"isComplete,omitempty"`
 	AnalysisIntervalMinutes int    `json:"analysisIntervalMinutes,omitempty"`
+	AdaptiveInterval        bool   `json:"adaptiveInterval,omitempty"`
+	// ChannelID names the channel the run belongs to; empty is the default channel
+	ChannelID string `json:"channelId,omitempty"`
+
+	// From and To bound the window of a manualRun; SkipPosting analyzes and stores it
+	// without posting the summary
+	From        time.Time `json:"from,omitempty"`
+	To          time.Time `json:"to,omitempty"`
+	SkipPosting bool      `json:"skipPosting,omitempty"`
 }
 
 // Response represents the Lambda response
@@ -37,18 +52,19 @@ type Response struct {
 type OrchestratorHandler struct {
 	stateManager *state.StateManager
 	lambdaClient *awslambda.Client
+	ssmClient    *ssm.Client
 }
 
 // NewOrchestratorHandler creates a new orchestrator handler
 func NewOrchestratorHandler(ctx context.Context) (*OrchestratorHandler, error) {
 	// Initialize state manager
-	stateManager, err := state.NewStateManager(ctx, "hourstats-state")
+	stateManager, err := state.NewStateManager(ctx, appconfig.TablesFromEnv().State)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create state manager: %w", err)
 	}
 
 	// Initialize Lambda client for invoking other functions
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := config.LoadDefaultConfig(ctx, tracing.WithAWS)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
@@ -56,9 +72,20 @@ func NewOrchestratorHandler(ctx context.Context) (*OrchestratorHandler, error) {
 	return &OrchestratorHandler{
 		stateManager: stateManager,
 		lambdaClient: awslambda.NewFromConfig(cfg),
+		ssmClient:    ssm.NewFromConfig(cfg),
 	}, nil
 }
 
+// applyRetention sets the state table TTL from the optional retention policy
+// Failures keep the default TTL so a bad policy never blocks a run
+func (h *OrchestratorHandler) applyRetention(ctx context.Context) {
+	policy, err := retention.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("⚠️ ORCHESTRATOR: Failed to load retention policy, using default TTLs: %v", err)
+	}
+	h.stateManager.SetTTL(policy.StateTTL)
+}
+
 // HandleRequest is the main Lambda handler
 func (h *OrchestratorHandler) HandleRequest(ctx context.Context, event Event) (Response, error) {
 	log.Printf("Orchestrator received event: %+v", event)
@@ -67,6 +94,8 @@ func (h *OrchestratorHandler) HandleRequest(ctx context.Context, event Event) (R
 	switch event.Action {
 	case "checkCompletion":
 		return h.handleCheckCompletion(ctx, event)
+	case "manualRun":
+		return h.handleManualRun(ctx, event)
 	default:
 		return h.handleStartWorkflow(ctx, event)
 	}
@@ -77,23 +106,72 @@ func (h *OrchestratorHandler) handleStartWorkflow(ctx context.Context, event Eve
 	// Generate unique run ID
 	runID := fmt.Sprintf("run-%d", time.Now().UnixNano())
 	log.Printf("Starting new analysis run: %s", runID)
+	tracing.SetRunID(ctx, runID)
 
 	// Create new run state with the analysis interval from the event
-	analysisIntervalMinutes := 30 // Default to 30 minutes
-	if event.AnalysisIntervalMinutes > 0 {
-		analysisIntervalMinutes = event.AnalysisIntervalMinutes
+	analysisIntervalMinutes, err := interval.Resolve(event.AnalysisIntervalMinutes)
+	if err != nil {
+		log.Printf("Rejected analysis interval: %v", err)
+		return Response{
+			StatusCode: 400,
+			Body:       "Invalid analysis interval: " + err.Error(),
+		}, err
+	}
+	requestedIntervalMinutes := analysisIntervalMinutes
+
+	// A channel the settings don't know would otherwise run as the default channel
+	if _, err := channel.Get(ctx, h.ssmClient, event.ChannelID); err != nil {
+		log.Printf("Rejected channel: %v", err)
+		return Response{
+			StatusCode: 400,
+			Body:       "Invalid channel: " + err.Error(),
+		}, err
+	}
+
+	// Grow the window based on recent post volume while a single fetch can still cover it
+	var intervalReason string
+	var recentRuns []state.RunState
+	if event.AdaptiveInterval {
+		recentRuns, err = h.stateManager.GetRecentChannelRuns(ctx, event.ChannelID, recentRunsLookback)
+		if err != nil {
+			log.Printf("⚠️ ORCHESTRATOR: Failed to get recent runs, using requested interval: %v", err)
+		} else {
+			analysisIntervalMinutes, intervalReason = chooseAnalysisInterval(requestedIntervalMinutes, recentRuns)
+			log.Printf("📐 ORCHESTRATOR: Adaptive interval %d minutes (requested %d): %s",
+				analysisIntervalMinutes, requestedIntervalMinutes, intervalReason)
+		}
 	}
 
 	// Calculate and log the time range for this analysis (use UTC to match API timestamps)
 	now := time.Now().UTC()
-	cutoffTime := now.Add(-time.Duration(analysisIntervalMinutes) * time.Minute)
+	cutoffTime := now.Add(-interval.Duration(analysisIntervalMinutes))
+	if event.AdaptiveInterval {
+		// Continue from the previous window so a changed interval doesn't count posts twice
+		anchored, anchoredMinutes, ready := anchorCutoff(now, analysisIntervalMinutes, recentRuns)
+		if !ready {
+			log.Printf("⏭️ ORCHESTRATOR: Previous window runs to %s, inside this %d-minute window; skipping",
+				anchored.Format("2006-01-02 15:04:05 UTC"), analysisIntervalMinutes)
+			return Response{
+				StatusCode: 200,
+				Body:       "Skipped: the previous run's window has not elapsed",
+			}, nil
+		}
+		if anchoredMinutes != analysisIntervalMinutes {
+			intervalReason = fmt.Sprintf("%s; grown to %d minutes to continue from the previous window", intervalReason, anchoredMinutes)
+			log.Printf("📐 ORCHESTRATOR: Catching up from the previous window with %d minutes", anchoredMinutes)
+		}
+		cutoffTime, analysisIntervalMinutes = anchored, anchoredMinutes
+	}
 	log.Printf("📅 ORCHESTRATOR: Analysis time range - From: %s, To: %s (interval: %d minutes)",
 		cutoffTime.Format("2006-01-02 15:04:05 UTC"),
-		now.Format("2006-01-02 15:04:05 UTC"),
+		cutoffTime.Add(interval.Duration(analysisIntervalMinutes)).Format("2006-01-02 15:04:05 UTC"),
 		analysisIntervalMinutes)
 
+	// The retention policy decides how long the run state is kept
+	h.applyRetention(ctx)
+
 	// Pass the cutoffTime to CreateRun to ensure consistency (cutoff calculated once at start)
-	_, err := h.stateManager.CreateRun(ctx, runID, analysisIntervalMinutes, cutoffTime)
+	runState, err := h.stateManager.CreateChannelRun(ctx, event.ChannelID, runID, analysisIntervalMinutes, cutoffTime)
 	if err != nil {
 		log.Printf("Failed to create run state: %v", err)
 		return Response{
@@ -103,7 +181,16 @@ func (h *OrchestratorHandler) handleStartWorkflow(ctx context.Context, event Eve
 		}, err
 	}
 
-	log.Printf("Created run state for continuous fetching: %s", runID)
+	// Persist the requested interval alongside the chosen one for diagnostics
+	if intervalReason != "" {
+		runState.RequestedIntervalMinutes = requestedIntervalMinutes
+		runState.IntervalReason = intervalReason
+		if err := h.stateManager.UpdateRun(ctx, runState); err != nil {
+			log.Printf("⚠️ ORCHESTRATOR: Failed to record interval adaptation: %v", err)
+		}
+	}
+
+	log.Printf("Created run state for continuous fetching: %s (channel %s)", runID, channel.Label(event.ChannelID))
 
 	// Dispatch the first fetcher lambda
 	err = h.dispatchFetcher(ctx, runID, analysisIntervalMinutes)
@@ -129,7 +216,7 @@ func (h *OrchestratorHandler) handleCheckCompletion(ctx context.Context, event E
 	log.Printf("Checking completion for run: %s", runID)
 
 	// Get the current run state
-	state, err := h.stateManager.GetRun(ctx, runID, "orchestrator")
+	state, err := h.stateManager.GetRun(ctx, runID, "orchestrator", state.StronglyConsistent)
 	if err != nil {
 		log.Printf("Failed to get run state: %v", err)
 		return Response{
@@ -157,20 +244,18 @@ func (h *OrchestratorHandler) handleCheckCompletion(ctx context.Context, event E
 
 // dispatchFetcher invokes the fetcher lambda
 func (h *OrchestratorHandler) dispatchFetcher(ctx context.Context, runID string, analysisIntervalMinutes int) error {
-	fetcherPayload := map[string]interface{}{
-		"runId":                   runID,
-		"analysisIntervalMinutes": analysisIntervalMinutes,
-		"status":                  "fetching",
-		"maxIterations":           30,
-	}
-
-	payloadBytes, err := json.Marshal(fetcherPayload)
+	payloadBytes, err := events.Marshal(events.FetcherEvent{
+		Version:                 events.Version,
+		RunID:                   runID,
+		AnalysisIntervalMinutes: analysisIntervalMinutes,
+		Status:                  "fetching",
+	})
 	if err != nil {
 		return fmt.Errorf("failed to marshal fetcher payload: %w", err)
 	}
 
 	_, err = h.lambdaClient.Invoke(ctx, &awslambda.InvokeInput{
-		FunctionName:  aws.String("hourstats-fetcher"),
+		FunctionName:  aws.String(events.FunctionName(events.FetcherFunctionEnv, events.FetcherFunction)),
 		Payload:       payloadBytes,
 		InvocationType: types.InvocationTypeEvent, // Asynchronous invocation
 	})
@@ -184,11 +269,15 @@ func (h *OrchestratorHandler) dispatchFetcher(ctx context.Context, runID string,
 }
 
 func main() {
+	if err := tracing.Init("hourstats-orchestrator"); err != nil {
+		log.Printf("Tracing disabled: %v", err)
+	}
+
 	ctx := context.Background()
 	handler, err := NewOrchestratorHandler(ctx)
 	if err != nil {
 		log.Fatalf("Failed to create orchestrator handler: %v", err)
 	}
 
-	lambda.Start(handler.HandleRequest)
+	lambda.Start(tracing.Handler(handler.HandleRequest))
 }
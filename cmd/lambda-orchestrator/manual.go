@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/channel"
+	"github.com/christophergentle/hourstats-bsky/internal/interval"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
+)
+
+// handleManualRun starts a run over an explicit window an operator chose, rather than the
+// interval before now. The fetcher searches only up to the window's end, and the processor
+// posts it like a replay, or only stores its sentiment when SkipPosting is set.
+func (h *OrchestratorHandler) handleManualRun(ctx context.Context, event Event) (Response, error) {
+	analysisIntervalMinutes, err := manualWindowMinutes(event.From, event.To, time.Now())
+	if err != nil {
+		log.Printf("Rejected manual run window: %v", err)
+		return Response{
+			StatusCode: 400,
+			Body:       "Invalid manual run window: " + err.Error(),
+		}, err
+	}
+
+	// A channel the settings don't know would otherwise run as the default channel
+	if _, err := channel.Get(ctx, h.ssmClient, event.ChannelID); err != nil {
+		log.Printf("Rejected channel: %v", err)
+		return Response{
+			StatusCode: 400,
+			Body:       "Invalid channel: " + err.Error(),
+		}, err
+	}
+
+	runID := fmt.Sprintf("run-%d", time.Now().UnixNano())
+	tracing.SetRunID(ctx, runID)
+	log.Printf("🛠️ ORCHESTRATOR: Starting manual run %s - From: %s, To: %s (%d minutes, skip posting: %v)",
+		runID,
+		event.From.UTC().Format("2006-01-02 15:04:05 UTC"),
+		event.To.UTC().Format("2006-01-02 15:04:05 UTC"),
+		analysisIntervalMinutes, event.SkipPosting)
+
+	// The retention policy decides how long the run state is kept
+	h.applyRetention(ctx)
+
+	runState, err := h.stateManager.CreateChannelRun(ctx, event.ChannelID, runID, analysisIntervalMinutes, event.From.UTC())
+	if err != nil {
+		log.Printf("Failed to create run state: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to create run state: " + err.Error(),
+			RunID:      runID,
+		}, err
+	}
+
+	// The fetcher and processor read these before doing anything else, so the run can't
+	// start until they are stored
+	runState.Manual = true
+	runState.SkipPosting = event.SkipPosting
+	if err := h.stateManager.UpdateRun(ctx, runState); err != nil {
+		log.Printf("Failed to mark run as manual: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to mark run as manual: " + err.Error(),
+			RunID:      runID,
+		}, err
+	}
+
+	if err := h.dispatchFetcher(ctx, runID, analysisIntervalMinutes); err != nil {
+		log.Printf("Failed to dispatch first fetcher: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to dispatch first fetcher: " + err.Error(),
+			RunID:      runID,
+		}, err
+	}
+
+	return Response{
+		StatusCode: 200,
+		Body:       "Manual run state created and first fetcher dispatched successfully",
+		RunID:      runID,
+	}, nil
+}
+
+// manualWindowMinutes checks a manual run's window and returns its length in minutes. The
+// window must have ended by now and be a valid analysis interval, so everything downstream
+// can keep reading a run's window as its interval from CutoffTime.
+func manualWindowMinutes(from, to, now time.Time) (int, error) {
+	if from.IsZero() || to.IsZero() {
+		return 0, fmt.Errorf("manualRun needs both from and to")
+	}
+	if !to.After(from) {
+		return 0, fmt.Errorf("to (%s) must be after from (%s)", to.Format(time.RFC3339), from.Format(time.RFC3339))
+	}
+	if to.After(now) {
+		return 0, fmt.Errorf("to (%s) is in the future", to.Format(time.RFC3339))
+	}
+
+	length := to.Sub(from)
+	if length%time.Minute != 0 {
+		return 0, fmt.Errorf("the window must be whole minutes, got %s", length)
+	}
+	minutes := int(length / time.Minute)
+	if err := interval.Validate(minutes); err != nil {
+		return 0, err
+	}
+	return minutes, nil
+}
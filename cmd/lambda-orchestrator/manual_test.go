@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManualWindowMinutes(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	from := time.Date(2025, 3, 2, 6, 0, 0, 0, time.UTC)
+
+	minutes, err := manualWindowMinutes(from, from.Add(6*time.Hour), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 360, minutes)
+
+	minutes, err = manualWindowMinutes(from, from.Add(45*time.Minute), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 45, minutes)
+}
+
+func TestManualWindowMinutesRejectsBadWindows(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	from := time.Date(2025, 3, 2, 6, 0, 0, 0, time.UTC)
+
+	for name, to := range map[string]time.Time{
+		"missing to":        {},
+		"before from":       from.Add(-time.Hour),
+		"in the future":     now.Add(time.Hour),
+		"partial minute":    from.Add(90 * time.Second),
+		"partial hour":      from.Add(90 * time.Minute),
+		"longer than a day": from.Add(25 * time.Hour),
+	} {
+		_, err := manualWindowMinutes(from, to, now)
+		assert.Error(t, err, name)
+	}
+}
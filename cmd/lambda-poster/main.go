@@ -7,20 +7,16 @@ import (
 	"time"
 
 	"github.com/aws/aws-lambda-go/lambda"
-	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/credentials"
+	"github.com/christophergentle/hourstats-bsky/internal/dryrun"
+	"github.com/christophergentle/hourstats-bsky/internal/events"
 	"github.com/christophergentle/hourstats-bsky/internal/state"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
 )
 
-// StepFunctionsEvent represents the event from Step Functions
-type StepFunctionsEvent struct {
-	RunID                   string `json:"runId"`
-	AnalysisIntervalMinutes int    `json:"analysisIntervalMinutes"`
-	Status                  string `json:"status"`
-}
-
 // Response represents the Lambda response
 type Response struct {
 	StatusCode int    `json:"statusCode"`
@@ -32,6 +28,10 @@ type Response struct {
 type PosterHandler struct {
 	stateManager *state.StateManager
 	ssmClient    *ssm.Client
+	credentials  credentials.Provider
+
+	// dryRun is the current invocation's dry run level, read at the start of each invocation
+	dryRun dryrun.Level
 }
 
 // NewPosterHandler creates a new poster handler
@@ -43,7 +43,7 @@ func NewPosterHandler(ctx context.Context) (*PosterHandler, error) {
 	}
 
 	// Initialize SSM client
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := config.LoadDefaultConfig(ctx, tracing.WithAWS)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
@@ -53,15 +53,16 @@ func NewPosterHandler(ctx context.Context) (*PosterHandler, error) {
 	return &PosterHandler{
 		stateManager: stateManager,
 		ssmClient:    ssmClient,
+		credentials:  credentials.Default(cfg, ssmClient),
 	}, nil
 }
 
 // HandleRequest is the main Lambda handler
-func (h *PosterHandler) HandleRequest(ctx context.Context, event StepFunctionsEvent) (Response, error) {
+func (h *PosterHandler) HandleRequest(ctx context.Context, event events.RunEvent) (Response, error) {
 	log.Printf("Poster received event: %+v", event)
 
 	// Get aggregator step for top posts
-	aggregatorState, err := h.stateManager.GetRun(ctx, event.RunID, "aggregator")
+	aggregatorState, err := h.stateManager.GetRun(ctx, event.RunID, "aggregator", state.StronglyConsistent)
 	if err != nil {
 		log.Printf("Failed to get aggregator run state: %v", err)
 		return Response{
@@ -71,7 +72,7 @@ func (h *PosterHandler) HandleRequest(ctx context.Context, event StepFunctionsEv
 	}
 
 	// Get analyzer step for sentiment
-	analyzerState, err := h.stateManager.GetRun(ctx, event.RunID, "analyzer")
+	analyzerState, err := h.stateManager.GetRun(ctx, event.RunID, "analyzer", state.StronglyConsistent)
 	if err != nil {
 		log.Printf("Failed to get analyzer run state: %v", err)
 		return Response{
@@ -124,21 +125,23 @@ func (h *PosterHandler) HandleRequest(ctx context.Context, event StepFunctionsEv
 		}, nil
 	}
 
-	// Check if dry run mode is enabled
-	dryRun, err := h.isDryRunMode(ctx)
+	// Check the dry run level; a shadow dry run posts from the shadow account
+	level, err := dryrun.Load(ctx, h.ssmClient)
 	if err != nil {
-		log.Printf("Failed to check dry run mode: %v", err)
+		log.Printf("Failed to check dry run level: %v", err)
 		return Response{
 			StatusCode: 500,
-			Body:       "Failed to check dry run mode: " + err.Error(),
+			Body:       "Failed to check dry run level: " + err.Error(),
 		}, err
 	}
 
-	if dryRun {
-		log.Printf("Dry run mode enabled, skipping post for run: %s", event.RunID)
+	h.dryRun = level
+
+	if !level.Posts() {
+		log.Printf("Dry run (%s), skipping post for run: %s", level, event.RunID)
 		return Response{
 			StatusCode: 200,
-			Body:       "Dry run mode - post skipped",
+			Body:       "Dry run (" + level.String() + ") - post skipped",
 			Posted:     false,
 		}, nil
 	}
@@ -164,7 +167,7 @@ func (h *PosterHandler) HandleRequest(ctx context.Context, event StepFunctionsEv
 	}
 
 	// Get all posts for sentiment calculation
-	allPosts, err := h.stateManager.GetAllPosts(ctx, runState.RunID)
+	allPosts, err := h.stateManager.GetAllPosts(ctx, runState.RunID, state.StronglyConsistent)
 	if err != nil {
 		log.Printf("Failed to get all posts: %v", err)
 		return Response{
@@ -219,50 +222,14 @@ func (h *PosterHandler) HandleRequest(ctx context.Context, event StepFunctionsEv
 	}, nil
 }
 
-// isDryRunMode checks if dry run mode is enabled
-func (h *PosterHandler) isDryRunMode(ctx context.Context) (bool, error) {
-	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
-		Name:           aws.String("/hourstats/settings/dry_run"),
-		WithDecryption: aws.Bool(false),
-	})
-	if err != nil {
-		return false, fmt.Errorf("failed to get dry run parameter: %w", err)
-	}
-
-	return *result.Parameter.Value == "true", nil
-}
-
-// getBlueskyCredentials retrieves credentials from SSM
+// getBlueskyCredentials retrieves credentials from the Secrets Manager secret or SSM, or
+// the shadow account's during a shadow dry run
 func (h *PosterHandler) getBlueskyCredentials(ctx context.Context) (string, string, error) {
-	parameterNames := []string{
-		"/hourstats/bluesky/handle",
-		"/hourstats/bluesky/password",
-	}
-
-	result, err := h.ssmClient.GetParameters(ctx, &ssm.GetParametersInput{
-		Names:          parameterNames,
-		WithDecryption: aws.Bool(true),
-	})
+	creds, err := h.dryRun.Credentials(h.credentials, h.ssmClient).Retrieve(ctx)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to get parameters: %w", err)
-	}
-
-	params := make(map[string]string)
-	for _, p := range result.Parameters {
-		params[*p.Name] = *p.Value
-	}
-
-	handle, ok := params["/hourstats/bluesky/handle"]
-	if !ok {
-		return "", "", fmt.Errorf("handle parameter not found")
+		return "", "", err
 	}
-
-	password, ok := params["/hourstats/bluesky/password"]
-	if !ok {
-		return "", "", fmt.Errorf("password parameter not found")
-	}
-
-	return handle, password, nil
+	return creds.Handle, creds.Password, nil
 }
 
 // convertToClientPosts converts state posts to client posts
@@ -313,11 +280,15 @@ func (h *PosterHandler) triggerSparklinePoster(ctx context.Context, runID string
 }
 
 func main() {
+	if err := tracing.Init("hourstats-poster"); err != nil {
+		log.Printf("Tracing disabled: %v", err)
+	}
+
 	ctx := context.Background()
 	handler, err := NewPosterHandler(ctx)
 	if err != nil {
 		log.Fatalf("Failed to create poster handler: %v", err)
 	}
 
-	lambda.Start(handler.HandleRequest)
+	lambda.Start(tracing.Handler(events.Handler(handler.HandleRequest)))
 }
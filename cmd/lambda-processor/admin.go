@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/christophergentle/hourstats-bsky/internal/adminnotify"
+	"github.com/christophergentle/hourstats-bsky/internal/events"
+)
+
+// handleAndNotify runs HandleRequest and messages the admin when the run fails
+func (h *ProcessorHandler) handleAndNotify(ctx context.Context, event events.ProcessorEvent) (Response, error) {
+	response, err := h.HandleRequest(ctx, event)
+	if err != nil {
+		h.messageAdmin(ctx, h.loadAdminSettings(ctx), fmt.Sprintf("❌ Run %s failed: %v", event.RunID, err))
+	}
+	return response, err
+}
+
+// loadAdminSettings reads who to message about failures and outages; unreadable settings
+// send no messages
+func (h *ProcessorHandler) loadAdminSettings(ctx context.Context) adminnotify.Settings {
+	settings, err := adminnotify.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("Failed to load admin DM settings, not messaging: %v", err)
+	}
+	return settings
+}
+
+// messageAdmin messages the admin from the default account, whichever channel or dry run
+// level the run has, reporting whether the message was sent
+func (h *ProcessorHandler) messageAdmin(ctx context.Context, settings adminnotify.Settings, text string) bool {
+	return adminnotify.Send(ctx, settings, h.defaultClient, "processor", text)
+}
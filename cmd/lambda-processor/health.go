@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/healthgate"
+	"github.com/christophergentle/hourstats-bsky/internal/metrics"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// checkHealth runs the health gate over the run. When Bluesky looks down it skips the run,
+// posts the outage notice and messages the admin if this outage hasn't had them yet, and
+// emits the OutageDetected metric the outage alarm watches; it returns the response to end
+// the run with. A healthy run ends any outage in progress and returns nil.
+func (h *ProcessorHandler) checkHealth(ctx context.Context, runState *state.RunState) *Response {
+	settings, err := healthgate.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("Ignoring health gate settings, using defaults: %v", err)
+	}
+	if settings.Disabled {
+		return nil
+	}
+
+	recentRuns, err := h.stateManager.GetRecentChannelRuns(ctx, runState.ChannelID, healthgate.BaselineWindow)
+	if err != nil {
+		log.Printf("Failed to get recent runs, checking only the error rate: %v", err)
+	}
+	verdict := healthgate.Check(settings, *runState, recentRuns)
+
+	detected := 0.0
+	if verdict.Outage {
+		detected = 1
+	}
+	if err := metrics.Emit(map[string]string{"Function": "processor"},
+		metrics.Metric{Name: "OutageDetected", Value: detected, Unit: metrics.UnitCount},
+		metrics.Metric{Name: "FetchErrorPercent", Value: verdict.ErrorRate * 100, Unit: metrics.UnitPercent},
+	); err != nil {
+		log.Printf("Failed to emit health metrics: %v", err)
+	}
+
+	outage, err := h.stateManager.GetOutage(ctx, runState.ChannelID)
+	if err != nil {
+		log.Printf("Failed to get outage: %v", err)
+	}
+
+	if !verdict.Outage {
+		if outage.Ongoing() {
+			log.Printf("✅ PROCESSOR: Bluesky looks healthy again, ending the outage that started %s", outage.StartedAt.Format("2006-01-02 15:04 UTC"))
+			outage.EndedAt = time.Now().UTC()
+			if !outage.AdminMessagedAt.IsZero() {
+				h.messageAdmin(ctx, h.loadAdminSettings(ctx), fmt.Sprintf("✅ Bluesky looks healthy again, ending the outage that started %s", outage.StartedAt.Format("2006-01-02 15:04 UTC")))
+			}
+			if err := h.stateManager.SaveOutage(ctx, runState.ChannelID, outage); err != nil {
+				log.Printf("Failed to end outage: %v", err)
+			}
+		}
+		return nil
+	}
+
+	log.Printf("🚨 PROCESSOR: Bluesky looks down, skipping post: %s", verdict.Reason)
+	if err := h.stateManager.SetRunSkipped(ctx, runState.RunID, "outage: "+verdict.Reason); err != nil {
+		log.Printf("Failed to mark run as skipped: %v", err)
+	}
+
+	if !outage.Ongoing() {
+		outage = &state.Outage{Reason: verdict.Reason, StartedAt: time.Now().UTC()}
+	}
+	admin := h.loadAdminSettings(ctx)
+	if outage.AdminMessagedAt.IsZero() && h.messageAdmin(ctx, admin, fmt.Sprintf("🚨 Bluesky looks down, skipping run %s: %s", runState.RunID, verdict.Reason)) {
+		outage.AdminMessagedAt = time.Now().UTC()
+	}
+	// The message stands in for the notice when it replaces public notices
+	if outage.NoticePostedAt.IsZero() && admin.PostsPublicNotices() && h.postOutageNotice(ctx) {
+		outage.NoticePostedAt = time.Now().UTC()
+	}
+	if err := h.stateManager.SaveOutage(ctx, runState.ChannelID, outage); err != nil {
+		log.Printf("Failed to save outage: %v", err)
+	}
+
+	return &Response{
+		StatusCode: 200,
+		Body:       "Outage detected - post skipped: " + verdict.Reason,
+	}
+}
+
+// postOutageNotice posts healthgate.NoticeText, reporting whether it was posted; a dry run
+// that doesn't post leaves it for the next run
+func (h *ProcessorHandler) postOutageNotice(ctx context.Context) bool {
+	if !h.dryRun.Posts() {
+		log.Printf("Dry run (%s), not posting the outage notice", h.dryRun)
+		return false
+	}
+	if err := h.blueskyClient.Authenticate(); err != nil {
+		log.Printf("Failed to authenticate for the outage notice: %v", err)
+		return false
+	}
+	if err := h.blueskyClient.PostText(ctx, healthgate.NoticeText); err != nil {
+		log.Printf("Failed to post the outage notice: %v", err)
+		return false
+	}
+	log.Printf("📣 PROCESSOR: Posted the outage notice")
+	return true
+}
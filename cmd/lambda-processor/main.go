@@ -2,10 +2,15 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-lambda-go/lambda"
@@ -13,20 +18,164 @@ import (
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	awslambda "github.com/aws/aws-sdk-go-v2/service/lambda"
 	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
+	"github.com/christophergentle/hourstats-bsky/internal/alttext"
 	"github.com/christophergentle/hourstats-bsky/internal/analyzer"
+	"github.com/christophergentle/hourstats-bsky/internal/blocklist"
+	"github.com/christophergentle/hourstats-bsky/internal/calendar"
+	"github.com/christophergentle/hourstats-bsky/internal/channel"
 	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/comparison"
 	"github.com/christophergentle/hourstats-bsky/internal/config"
+	"github.com/christophergentle/hourstats-bsky/internal/dryrun"
+	"github.com/christophergentle/hourstats-bsky/internal/events"
+	"github.com/christophergentle/hourstats-bsky/internal/experiments"
 	"github.com/christophergentle/hourstats-bsky/internal/formatter"
+	"github.com/christophergentle/hourstats-bsky/internal/identity"
+	"github.com/christophergentle/hourstats-bsky/internal/insight"
+	"github.com/christophergentle/hourstats-bsky/internal/interaction"
 	lambdapkg "github.com/christophergentle/hourstats-bsky/internal/lambda"
+	"github.com/christophergentle/hourstats-bsky/internal/metrics"
+	"github.com/christophergentle/hourstats-bsky/internal/pin"
+	"github.com/christophergentle/hourstats-bsky/internal/preview"
+	"github.com/christophergentle/hourstats-bsky/internal/profanity"
+	"github.com/christophergentle/hourstats-bsky/internal/provenance"
+	"github.com/christophergentle/hourstats-bsky/internal/readbudget"
+	"github.com/christophergentle/hourstats-bsky/internal/retention"
+	"github.com/christophergentle/hourstats-bsky/internal/runreport"
+	"github.com/christophergentle/hourstats-bsky/internal/sampling"
+	"github.com/christophergentle/hourstats-bsky/internal/schedule"
 	"github.com/christophergentle/hourstats-bsky/internal/state"
+	"github.com/christophergentle/hourstats-bsky/internal/status"
+	"github.com/christophergentle/hourstats-bsky/internal/toxicity"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
 )
 
-// ProcessorEvent represents the event for the processor lambda
-type ProcessorEvent struct {
-	RunID                   string `json:"runId"`
-	AnalysisIntervalMinutes int    `json:"analysisIntervalMinutes"`
-	Status                  string `json:"status"`
-}
+// storeAnalyzedPostsParameter enables storing every analyzed post for per-post queries
+const storeAnalyzedPostsParameter = "/hourstats/settings/store_analyzed_posts"
+
+// topPostsCardParameter enables attaching a rendered top posts card image to the summary
+const topPostsCardParameter = "/hourstats/settings/top_posts_card"
+
+// summaryCardParameter enables attaching a rendered summary card image to the summary,
+// in place of the top posts card when both are enabled
+const summaryCardParameter = "/hourstats/settings/summary_card"
+
+// summaryCardHistory is how much sentiment history the summary card's sparkline shows
+const summaryCardHistory = 24 * time.Hour
+
+// summaryLikesParameter follows each top post in the summary with its likes when "true"
+const summaryLikesParameter = "/hourstats/settings/summary_likes"
+
+// numberLocaleParameter optionally names the locale whose separators the summary's numbers
+// use, e.g. "de"
+const numberLocaleParameter = "/hourstats/settings/number_locale"
+
+// dominantEmotionParameter adds the run's dominant emotion to the summary post when "true"
+const dominantEmotionParameter = "/hourstats/settings/dominant_emotion"
+
+// topDomainsParameter adds the run's most shared link domains to the summary post when "true"
+const topDomainsParameter = "/hourstats/settings/top_domains"
+
+// moderationListsParameter optionally lists the at:// URIs of Bluesky moderation lists,
+// comma separated, whose members' posts are left out like blocked authors
+const moderationListsParameter = "/hourstats/settings/moderation_lists"
+
+// analysisConcurrencyParameter optionally sets how many workers analyze posts in parallel
+const analysisConcurrencyParameter = "/hourstats/settings/analysis_concurrency"
+
+// maxAnalysisConcurrency bounds the analysis worker pool whatever the setting says
+const maxAnalysisConcurrency = 32
+
+// mediaRankingParameter optionally changes how posts with media are ranked: neutral, boost, or exclude
+const mediaRankingParameter = "/hourstats/settings/media_ranking"
+
+// Media ranking modes
+const (
+	mediaRankingNeutral = "neutral"
+	mediaRankingBoost   = "boost"
+	mediaRankingExclude = "exclude"
+
+	// mediaBoostFactor scales the engagement score of media posts in boost mode
+	mediaBoostFactor = 1.5
+)
+
+// replyRankingParameter optionally keeps replies out of the top posts: include or exclude.
+// Replies count toward sentiment either way.
+const replyRankingParameter = "/hourstats/settings/reply_ranking"
+
+// Reply ranking modes
+const (
+	replyRankingInclude = "include"
+	replyRankingExclude = "exclude"
+)
+
+// contentWarningsParameter optionally lets posts whose authors put content warnings on
+// them be top posts: hide or show. They count toward sentiment either way.
+const contentWarningsParameter = "/hourstats/settings/content_warnings"
+
+// Content warning modes
+const (
+	contentWarningsHide = "hide"
+	contentWarningsShow = "show"
+)
+
+// topPostsRankingParameter optionally ranks top posts by engagement velocity rather than
+// raw engagement: engagement or velocity
+const topPostsRankingParameter = "/hourstats/settings/top_posts_ranking"
+
+// Top posts ranking modes
+const (
+	topPostsRankingEngagement = "engagement"
+	// topPostsRankingVelocity divides engagement by the post's age when fetched, so posts
+	// created late in the window aren't penalized for having had less time
+	topPostsRankingVelocity = "velocity"
+
+	// minVelocityAgeMinutes floors the age velocity divides by, so a post fetched moments
+	// after it was made can't outrank everything on a couple of likes
+	minVelocityAgeMinutes = 10.0
+)
+
+// Sentiment trend segmentation within the analysis window
+const (
+	// trendSegmentCount splits the window into equal sub-buckets; the outer thirds are compared
+	trendSegmentCount = 6
+
+	// minTrendThirdPosts is the fewest posts each outer third needs before a trend is reported
+	minTrendThirdPosts = 20
+)
+
+// Summary parameters a reprocess event may override
+const (
+	// defaultTopPosts is how many top posts a summary features
+	defaultTopPosts = 5
+)
+
+// previousCategoryLookback is how far before a window the previous run's category is
+// looked for when the sentiment thresholds have hysteresis
+const previousCategoryLookback = 6 * time.Hour
+
+// Topic sentiment stored with each run: the most-posted topics, each needing enough
+// posts for its average to mean something
+const (
+	maxRunTopics  = 10
+	minTopicPosts = 5
+)
+
+// Link domains stored with each run, and how many of them the summary's optional line names
+const (
+	maxRunDomains  = 10
+	maxNoteDomains = 3
+)
+
+// toxicityTimeout bounds toxicity scoring, which a remote backend paces to its quota
+const toxicityTimeout = 90 * time.Second
+
+// weekOverWeekTolerance is how far from exactly one week ago a history point may be
+// and still be used for the week-over-week comparison
+const weekOverWeekTolerance = 30 * time.Minute
 
 // Response represents the Lambda response
 type Response struct {
@@ -41,10 +190,31 @@ type Response struct {
 type ProcessorHandler struct {
 	stateManager            *state.StateManager
 	sentimentAnalyzer       *analyzer.SentimentAnalyzer
-	blueskyClient           *client.BlueskyClient
+	blueskyClient           client.BskyPoster
+	defaultClient           client.BskyPoster
 	lambdaClient            *awslambda.Client
+	ssmClient               *ssm.Client
 	sentimentHistoryManager *state.SentimentHistoryManager
+	dailySentimentManager   *state.DailySentimentManager
+	blocklistManager        *state.BlocklistManager
+	moderationLists         *identity.Resolver
 	config                  *config.Config
+
+	// reportsBucket receives a JSON report of every run, from REPORTS_BUCKET; empty writes none
+	reportsBucket string
+	s3Client      *s3.Client
+
+	// channel is the current run's channel, nil for the default channel; it is set at the
+	// start of each invocation, since invocations of one Lambda instance never overlap
+	channel *channel.Channel
+	// report collects the current run's report as the run progresses, set like channel
+	report *runreport.Report
+	// dryRun is the current run's dry run level, also set at the start of each invocation
+	dryRun dryrun.Level
+	// sentimentMethod averages the current run's scores into net sentiment, set likewise
+	sentimentMethod aggregation.Method
+	// sentimentThresholds categorize the current run's average score, set likewise
+	sentimentThresholds aggregation.Thresholds
 }
 
 // NewProcessorHandler creates a new processor handler
@@ -61,7 +231,7 @@ func NewProcessorHandler(ctx context.Context) (*ProcessorHandler, error) {
 	}
 
 	// Initialize state manager
-	stateManager, err := state.NewStateManager(ctx, "hourstats-state")
+	stateManager, err := state.NewStateManager(ctx, cfg.Tables.State)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create state manager: %w", err)
 	}
@@ -73,34 +243,92 @@ func NewProcessorHandler(ctx context.Context) (*ProcessorHandler, error) {
 	blueskyClient := client.New(cfg.Bluesky.Handle, cfg.Bluesky.Password)
 
 	// Initialize Lambda client for invoking other functions
-	awsCfg, err := awsconfig.LoadDefaultConfig(ctx)
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, tracing.WithAWS)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 	lambdaClient := awslambda.NewFromConfig(awsCfg)
 
 	// Initialize sentiment history manager
-	sentimentHistoryManager, err := state.NewSentimentHistoryManager(ctx, "hourstats-sentiment-history")
+	sentimentHistoryManager, err := state.NewSentimentHistoryManager(ctx, cfg.Tables.SentimentHistory)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create sentiment history manager: %w", err)
 	}
 
+	// Initialize daily sentiment manager, for ranking today among past days
+	dailySentimentManager, err := state.NewDailySentimentManager(ctx, cfg.Tables.DailySentiment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create daily sentiment manager: %w", err)
+	}
+
+	// Initialize blocklist manager, for leaving blocked authors, domains and keywords out
+	blocklistManager, err := state.NewBlocklistManager(ctx, cfg.Tables.Blocklist)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blocklist manager: %w", err)
+	}
+
+	// Moderation list members are fetched with the default account, since lists are public
+	moderationLists := identity.NewResolver(client.New(cfg.Bluesky.Handle, cfg.Bluesky.Password), stateManager)
+
 	return &ProcessorHandler{
 		stateManager:            stateManager,
 		sentimentAnalyzer:       sentimentAnalyzer,
 		blueskyClient:           blueskyClient,
+		defaultClient:           blueskyClient,
 		lambdaClient:            lambdaClient,
+		ssmClient:               ssm.NewFromConfig(awsCfg),
 		sentimentHistoryManager: sentimentHistoryManager,
+		dailySentimentManager:   dailySentimentManager,
+		blocklistManager:        blocklistManager,
+		moderationLists:         moderationLists,
 		config:                  cfg,
+		reportsBucket:           os.Getenv("REPORTS_BUCKET"),
+		s3Client:                s3.NewFromConfig(awsCfg),
 	}, nil
 }
 
 // HandleRequest is the main Lambda handler
-func (h *ProcessorHandler) HandleRequest(ctx context.Context, event ProcessorEvent) (Response, error) {
+func (h *ProcessorHandler) HandleRequest(ctx context.Context, event events.ProcessorEvent) (Response, error) {
 	log.Printf("Processor received event: %+v", event)
+	tracing.SetRunID(ctx, event.RunID)
+
+	// Reading the run's posts keeps to the configured read capacity budget
+	budget, err := readbudget.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("⚠️ PROCESSOR: Failed to load read budget, reading posts without limits: %v", err)
+	}
+	h.stateManager.SetReadBudget(budget)
+
+	if event.Reprocess != nil {
+		return h.reprocess(ctx, event)
+	}
+
+	// Every run ends here, however it ends, so refresh the public status afterwards
+	defer h.publishStatus(ctx)
+	// and write its report, filled in as the run gets further
+	h.report = &runreport.Report{RunID: event.RunID}
+	defer h.publishReport(ctx, event.RunID)
+
+	// Sentiment history and stored analyzed posts follow the configured TTLs
+	policy, err := retention.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("⚠️ PROCESSOR: Failed to load retention policy, using default TTLs: %v", err)
+	}
+	h.sentimentHistoryManager.SetTTL(policy.SentimentHistoryTTL)
+	h.stateManager.SetTTL(policy.StateTTL)
+
+	// Net sentiment is averaged by the configured method, which the run records
+	h.sentimentMethod, err = aggregation.LoadMethod(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("⚠️ PROCESSOR: Failed to load sentiment method, using the mean: %v", err)
+	}
+	h.sentimentThresholds, err = aggregation.LoadThresholds(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("⚠️ PROCESSOR: Failed to load sentiment thresholds, using the defaults: %v", err)
+	}
 
 	// Get current run state - look for orchestrator step which has the run metadata
-	runState, err := h.stateManager.GetRun(ctx, event.RunID, "orchestrator")
+	runState, err := h.stateManager.GetRun(ctx, event.RunID, "orchestrator", state.StronglyConsistent)
 	if err != nil {
 		log.Printf("Failed to get fetcher run state: %v", err)
 		return Response{
@@ -109,14 +337,48 @@ func (h *ProcessorHandler) HandleRequest(ctx context.Context, event ProcessorEve
 		}, err
 	}
 
+	// The run's channel chooses the account, layout, posting rules and history
+	if err := h.useChannel(ctx, runState.ChannelID); err != nil {
+		log.Printf("Failed to use channel: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to use channel: " + err.Error(),
+		}, err
+	}
+
+	// The dry run level decides whether the summary is posted, from which account, and
+	// whether history is stored
+	if err := h.useDryRun(ctx); err != nil {
+		log.Printf("Failed to use dry run level: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to use dry run level: " + err.Error(),
+		}, err
+	}
+
+	// Manual runs cover a past window an operator chose, so they post like replays. One
+	// started with skipPosting is only stored, until the replay tool posts it.
+	skipPosting := runState.SkipPosting && !event.Replay
+	if runState.Manual {
+		event.Replay = true
+	}
+
+	if event.Replay && runState.TopPostURI != "" {
+		log.Printf("Replay requested for run %s but it was already posted: %s", event.RunID, runState.TopPostURI)
+		return Response{
+			StatusCode: 409,
+			Body:       "Run already posted: " + runState.TopPostURI,
+		}, nil
+	}
+
 	// Log the time range being used for processing
 	log.Printf("📅 PROCESSOR: Processing posts from time range - From: %s, To: %s (current time: %s)",
 		runState.CutoffTime.Format("2006-01-02 15:04:05 UTC"),
 		time.Now().Format("2006-01-02 15:04:05 UTC"),
 		time.Now().Format("2006-01-02 15:04:05 UTC"))
 
-	// Retrieve all posts for this run
-	allPosts, err := h.stateManager.GetAllPosts(ctx, event.RunID)
+	// Retrieve all posts for this run, consistently so the fetcher's last batches are seen
+	allPosts, err := h.stateManager.GetAllPosts(ctx, event.RunID, state.StronglyConsistent)
 	if err != nil {
 		log.Printf("Failed to get all posts: %v", err)
 		return Response{
@@ -125,20 +387,55 @@ func (h *ProcessorHandler) HandleRequest(ctx context.Context, event ProcessorEve
 		}, err
 	}
 
-	// Fix URI format for posts retrieved from DynamoDB
-	allPosts = h.fixPostURIs(allPosts)
-
 	log.Printf("🔍 PROCESSOR DEBUG: Retrieved %d posts from DynamoDB for run %s", len(allPosts), event.RunID)
 	log.Printf("🔍 PROCESSOR DEBUG: Using cutoff time from DynamoDB: %s", runState.CutoffTime.Format("2006-01-02 15:04:05 UTC"))
 
 	// Deduplicate posts by URI, keeping the one with highest engagement score
-	deduplicatedPosts := h.deduplicatePostsByURI(allPosts)
+	deduplicatedPosts := state.DedupePosts(allPosts)
 	log.Printf("🔍 PROCESSOR DEBUG: After deduplication: %d posts (from %d original)", len(deduplicatedPosts), len(allPosts))
 
-	// Filter posts by cutoff time
-	filteredPosts := h.filterPostsByCutoffTime(deduplicatedPosts, runState.CutoffTime)
+	// Keep the run's window; recompute selects a run's posts the same way
+	filteredPosts := runState.WindowPosts(deduplicatedPosts)
 	log.Printf("🔍 PROCESSOR DEBUG: After time filtering: %d posts (from %d deduplicated)", len(filteredPosts), len(deduplicatedPosts))
 
+	// Leave out blocked authors, domains and keywords, so they count toward neither
+	// sentiment nor the top posts
+	postsAfterTimeFilter := len(filteredPosts)
+	filteredPosts = h.applyBlocklist(ctx, event.RunID, filteredPosts)
+
+	// Measure how much of the requested window the fetched posts actually span
+	windowEnd := runState.CutoffTime.Add(time.Duration(runState.AnalysisIntervalMinutes) * time.Minute)
+	coverage := state.CalculateCoverage(filteredPosts, runState.CutoffTime, windowEnd)
+	log.Printf("📏 PROCESSOR: Window coverage %.1f%% (earliest: %s, latest: %s)",
+		coverage.CoveragePercent,
+		coverage.EarliestPostAt.Format("2006-01-02 15:04:05 UTC"),
+		coverage.LatestPostAt.Format("2006-01-02 15:04:05 UTC"))
+	if err := metrics.Emit(map[string]string{"Function": "processor"},
+		metrics.Metric{Name: "WindowCoveragePercent", Value: coverage.CoveragePercent, Unit: metrics.UnitPercent},
+	); err != nil {
+		log.Printf("Failed to emit coverage metric: %v", err)
+	}
+	if err := h.stateManager.SetCoverage(ctx, event.RunID, coverage); err != nil {
+		log.Printf("Failed to store coverage: %v", err)
+		// Don't fail the main process if coverage storage fails
+	}
+	h.report.FetchStats = runreport.FetchStats{
+		TotalPostsFromAPI:       len(allPosts),
+		PostsAfterDeduplication: len(deduplicatedPosts),
+		PostsAfterTimeFilter:    postsAfterTimeFilter,
+		PostsAfterBlocklist:     len(filteredPosts),
+		CoveragePercent:         coverage.CoveragePercent,
+		TimeDistribution:        runreport.TimeDistribution(reportPostTimes(filteredPosts), runState.CutoffTime, windowEnd, runreport.TimeBuckets),
+	}
+
+	// An erroring API or collapsed volume would make the summary describe the outage rather
+	// than the mood. Replays and manual runs post windows that are long over.
+	if !event.Replay {
+		if response := h.checkHealth(ctx, runState); response != nil {
+			return *response, nil
+		}
+	}
+
 	if len(filteredPosts) == 0 {
 		log.Printf("No posts found for the time period, skipping analysis")
 		return Response{
@@ -147,11 +444,46 @@ func (h *ProcessorHandler) HandleRequest(ctx context.Context, event ProcessorEve
 		}, nil
 	}
 
+	// A handful of posts (API issues or quiet hours) would produce a misleading summary
+	if len(filteredPosts) < h.config.Settings.MinPostCount {
+		reason := fmt.Sprintf("only %d posts in window (minimum %d)", len(filteredPosts), h.config.Settings.MinPostCount)
+		log.Printf("⚠️ PROCESSOR: Low data, skipping post: %s", reason)
+		if err := h.stateManager.SetRunSkipped(ctx, event.RunID, reason); err != nil {
+			log.Printf("Failed to mark run as skipped: %v", err)
+		}
+		return Response{
+			StatusCode: 200,
+			Body:       "Low data - post skipped: " + reason,
+		}, nil
+	}
+
+	// Very large windows are estimated from a sample seeded by the run ID, so replays match
+	analysisPosts := filteredPosts
+	samplingSettings, err := sampling.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("Ignoring sampling settings: %v", err)
+	}
+	sampled := samplingSettings.Applies(len(filteredPosts))
+	if sampled {
+		analysisPosts = sampling.Reservoir(filteredPosts, samplingSettings.Size, sampling.Seed(event.RunID))
+		log.Printf("🎲 PROCESSOR: Sampling %d of %d posts", len(analysisPosts), len(filteredPosts))
+	}
+
 	// Step 1: Analyze posts for sentiment and calculate engagement scores
-	log.Printf("Analyzing %d posts", len(filteredPosts))
-	analyzedPosts, overallSentiment, netSentimentPercentage, err := h.analyzePosts(filteredPosts)
+	workers := h.getAnalysisConcurrency(ctx)
+	h.sentimentAnalyzer.SetWorkers(workers)
+	log.Printf("Analyzing %d posts on %d workers", len(analysisPosts), workers)
+	analyzeStart := time.Now()
+	_, analyzeSpan := tracing.Start(ctx, "analyze")
+	// Hysteresis keeps the previous Bluesky run's category while the score stays near it
+	previousCategory := h.getPreviousCategory(ctx, windowEnd)
+	analyzedPosts, overallSentiment, netSentimentPercentage, trend, err := h.analyzePosts(analysisPosts, runState.CutoffTime, windowEnd, previousCategory)
+	tracing.End(analyzeSpan, err)
+	analyzeTiming := state.NewStepTiming(state.StepAnalyze, analyzeStart, state.StepStatusCompleted)
 	if err != nil {
 		log.Printf("Failed to analyze posts: %v", err)
+		analyzeTiming.Status = state.StepStatusFailed
+		h.recordStepTimings(ctx, event.RunID, analyzeTiming)
 		return Response{
 			StatusCode: 500,
 			Body:       "Failed to analyze posts: " + err.Error(),
@@ -160,7 +492,59 @@ func (h *ProcessorHandler) HandleRequest(ctx context.Context, event ProcessorEve
 
 	// Step 2: Get top posts by engagement score
 	log.Printf("Aggregating %d posts after analysis", len(analyzedPosts))
-	topPosts := h.getTopPosts(analyzedPosts, 5)
+	aggregateStart := time.Now()
+	mediaRanking := h.getMediaRanking(ctx)
+	replyRanking := h.getReplyRanking(ctx)
+	contentWarnings := h.getContentWarnings(ctx)
+	topPostsRanking := h.getTopPostsRanking(ctx)
+	log.Printf("🧵 PROCESSOR: %.1f%% of posts were replies (ranking: %s)", replyPercent(analyzedPosts), replyRanking)
+	h.recordContentWarningPosts(ctx, event.RunID, filteredPosts, contentWarnings)
+	topPosts := h.getTopPosts(rankingCandidates(analyzedPosts, replyRanking, contentWarnings), defaultTopPosts, mediaRanking, topPostsRanking)
+	var estimate sampling.Estimate
+	if sampled {
+		// Top posts are ranked over the whole window, not just the sample
+		topPosts = h.analyzeTopPosts(rankTopPosts(rankingCandidates(filteredPosts, replyRanking, contentWarnings), defaultTopPosts, mediaRanking, topPostsRanking), topPosts)
+
+		scores := make([]float64, len(analyzedPosts))
+		for i, post := range analyzedPosts {
+			scores[i] = post.SentimentScore * 100
+		}
+		estimate = sampling.Estimate95(scores, len(filteredPosts))
+		log.Printf("🎲 PROCESSOR: Net sentiment %.1f%% ± %.2f (95%% CI) from %d of %d posts",
+			estimate.Mean, estimate.Margin, estimate.SampleSize, estimate.Population)
+	}
+
+	h.report.ProcessingStats = runreport.ProcessingStats{
+		PostsAnalyzed:     len(analyzedPosts),
+		TopPostsSelected:  len(topPosts),
+		DuplicatesRemoved: len(allPosts) - len(deduplicatedPosts),
+		Sampled:           sampled,
+	}
+	h.report.SentimentAnalysis = reportSentiment(analyzedPosts, overallSentiment, netSentimentPercentage)
+	h.report.SamplePosts = reportSamplePosts(topPosts)
+
+	mediaStats := state.CalculateMediaStats(analyzedPosts, topPosts)
+	log.Printf("🖼️ PROCESSOR: %.1f%% of posts and %.1f%% of top posts had media (ranking: %s)",
+		mediaStats.PostsWithMediaPercent, mediaStats.TopPostsWithMediaPercent, mediaRanking)
+	if err := metrics.Emit(map[string]string{"Function": "processor"},
+		metrics.Metric{Name: "PostsWithMediaPercent", Value: mediaStats.PostsWithMediaPercent, Unit: metrics.UnitPercent},
+		metrics.Metric{Name: "TopPostsWithMediaPercent", Value: mediaStats.TopPostsWithMediaPercent, Unit: metrics.UnitPercent},
+	); err != nil {
+		log.Printf("Failed to emit media metrics: %v", err)
+	}
+	if err := h.stateManager.SetMediaStats(ctx, event.RunID, mediaStats); err != nil {
+		log.Printf("Failed to store media stats: %v", err)
+		// Don't fail the main process if media stats storage fails
+	}
+
+	if h.dryRun.Writes() && h.isStoreAnalyzedPostsEnabled(ctx) {
+		if err := h.stateManager.StoreAnalyzedPosts(ctx, event.RunID, analyzedPosts); err != nil {
+			log.Printf("Failed to store analyzed posts: %v", err)
+			// Don't fail the main process if per-post storage fails
+		} else {
+			log.Printf("🗂️ PROCESSOR: Stored %d analyzed posts", len(analyzedPosts))
+		}
+	}
 
 	// Debug logging for top posts
 	log.Printf("🔍 PROCESSOR DEBUG: Top 5 posts selected:")
@@ -171,33 +555,147 @@ func (h *ProcessorHandler) HandleRequest(ctx context.Context, event ProcessorEve
 
 	// Step 3: Update run state with top posts
 	log.Printf("Updating run state with top posts")
-	err = h.stateManager.SetAnalysisComplete(ctx, event.RunID, overallSentiment, topPosts)
+	scoring := provenance.Scoring{Method: h.sentimentMethod, Threshold: h.sentimentThresholds.Threshold, Hysteresis: h.sentimentThresholds.Hysteresis}
+	if sampled {
+		scoring.SampleSize = len(analysisPosts)
+	}
+	record := provenance.New(analyzer.Version, h.sentimentAnalyzer.LexiconHash(), scoring)
+	err = h.stateManager.SetAnalysisComplete(ctx, event.RunID, overallSentiment, netSentimentPercentage, h.sentimentMethod, record, topPosts)
 	if err != nil {
 		log.Printf("Failed to update run state with top posts: %v", err)
+		h.recordStepTimings(ctx, event.RunID, analyzeTiming, state.NewStepTiming(state.StepAggregate, aggregateStart, state.StepStatusFailed))
 		return Response{
 			StatusCode: 500,
 			Body:       "Failed to update run state: " + err.Error(),
 		}, err
 	}
 
+	h.recordStepTimings(ctx, event.RunID, analyzeTiming, state.NewStepTiming(state.StepAggregate, aggregateStart, state.StepStatusCompleted))
+
+	// The run's sentiment history point, stored whether or not the summary is posted.
+	// Toxicity is measured separately from sentiment: negative posts aren't necessarily toxic.
+	dataPoint := state.NewSentimentDataPoint(event.RunID, overallSentiment, netSentimentPercentage, runState.TotalPostsRetrieved, time.Now())
+	dataPoint.ToxicityPercent = h.measureToxicity(ctx, analyzedPosts)
+	counts := state.CountSentiments(analyzedPosts)
+	dataPoint.Counts = &counts
+	weightedPercentage := state.EngagementWeightedNetSentiment(analyzedPosts)
+	dataPoint.WeightedNetSentimentPercent = &weightedPercentage
+	emotions, emotionalPosts := state.CalculateEmotions(analyzedPosts)
+	if emotionalPosts > 0 {
+		dataPoint.Emotions = &emotions
+		log.Printf("🎭 PROCESSOR: Emotions across %d posts - joy %.0f%%, anger %.0f%%, sadness %.0f%%, fear %.0f%%",
+			emotionalPosts, emotions.Joy*100, emotions.Anger*100, emotions.Sadness*100, emotions.Fear*100)
+	}
+	dataPoint.Topics = state.CalculateTopicSentiment(analyzedPosts, maxRunTopics, minTopicPosts)
+	dataPoint.Domains = state.CalculateDomainShares(analyzedPosts, maxRunDomains)
+	dataPoint.Volatility = h.measureVolatility(ctx, netSentimentPercentage, windowEnd)
+	h.report.Topics, h.report.Domains, h.report.Emotions = dataPoint.Topics, dataPoint.Domains, dataPoint.Emotions
+
+	// Measure the comparison networks over the same window, so charts can set Bluesky beside
+	// them; only the default channel is compared, and only to store the result
+	if !event.Replay && h.channel == nil && h.dryRun.Writes() {
+		h.measureComparisonNetworks(ctx, runState, windowEnd)
+	}
+
+	// The posting schedule can withhold the summary; sentiment is still stored so charts
+	// stay complete. Replays are an explicit operator action and ignore the schedule.
+	if !event.Replay {
+		if allowed, reason := h.scheduleAllows(ctx, schedule.PosterSummary); !allowed {
+			return h.withholdSummary(ctx, runState, reason, dataPoint), nil
+		}
+	}
+
+	if skipPosting {
+		return h.storeManualRun(runState, dataPoint, windowEnd), nil
+	}
+
 	// Step 4: Post summary to Bluesky
 	log.Printf("Posting summary to Bluesky")
+	postStart := time.Now()
 	log.Printf("🔍 PROCESSOR DEBUG: Sentiment data - Overall: %s, Net sentiment: %.1f%%, Total posts: %d",
 		overallSentiment, netSentimentPercentage, len(filteredPosts))
 
-	// Authenticate before posting
-	if err := h.blueskyClient.Authenticate(); err != nil {
-		log.Printf("Failed to authenticate with Bluesky: %v", err)
-		return Response{
-			StatusCode: 500,
-			Body:       "Failed to authenticate with Bluesky: " + err.Error(),
-		}, err
+	// Authenticate before posting; a dry run that doesn't post has no need to
+	if h.dryRun.Posts() {
+		if err := h.blueskyClient.Authenticate(); err != nil {
+			log.Printf("Failed to authenticate with Bluesky: %v", err)
+			h.recordStepTimings(ctx, event.RunID, state.NewStepTiming(state.StepPost, postStart, state.StepStatusFailed))
+			return Response{
+				StatusCode: 500,
+				Body:       "Failed to authenticate with Bluesky: " + err.Error(),
+			}, err
+		}
+		log.Printf("✅ Successfully authenticated with Bluesky")
 	}
-	log.Printf("✅ Successfully authenticated with Bluesky")
 
-	err = h.postSummary(runState, topPosts, overallSentiment, len(filteredPosts), netSentimentPercentage)
+	coverageNote := formatter.CoverageNote(coverage.CoveragePercent, h.config.Settings.MinCoveragePercent)
+	if runState.FetchTruncated {
+		coverageNote = formatter.TruncatedNote(coverage.CoveragePercent)
+	}
+	notes := []string{
+		formatter.FeedNote(runState.FeedLabel),
+		formatter.QueryNote(runState.SearchQueries),
+		coverageNote,
+	}
+	if trend.HasComparableThirds(minTrendThirdPosts) {
+		notes = append(notes, formatter.TrendNote(trend.FirstThirdPercent, trend.LastThirdPercent))
+	}
+	if lastWeek := h.getLastWeekPoint(ctx, windowEnd); lastWeek != nil {
+		notes = append(notes, formatter.WeekOverWeekNote(netSentimentPercentage, lastWeek.NetSentimentPercent, lastWeek.Timestamp))
+	}
+	notableDays := h.loadCalendar(ctx)
+	notes = append(notes, formatter.NotableDayNote(notableDays.On(windowEnd)))
+	badge := h.evaluateBadge(ctx, netSentimentPercentage, windowEnd, notableDays)
+	notes = append(notes, formatter.UnusualSentimentNote(badge))
+	if dataPoint.Volatility != nil {
+		notes = append(notes, formatter.StabilityNote(insight.Stability(*dataPoint.Volatility), *dataPoint.Volatility))
+	}
+	// Replays would rank a past day by today's runs, and daily averages cover only the
+	// default channel
+	if !event.Replay && h.channel == nil {
+		if rank := h.rankToday(ctx, netSentimentPercentage, windowEnd); rank != nil {
+			notes = append(notes, formatter.PercentileNote(rank.Percentile, rank.Days))
+		}
+	}
+	if emotionalPosts > 0 && h.isDominantEmotionEnabled(ctx) {
+		notes = append(notes, formatter.DominantEmotionNote(emotions.Dominant()))
+	}
+	if len(dataPoint.Domains) > 0 && h.isTopDomainsEnabled(ctx) {
+		var domains []string
+		for _, share := range dataPoint.Domains[:min(maxNoteDomains, len(dataPoint.Domains))] {
+			domains = append(domains, share.Domain)
+		}
+		notes = append(notes, formatter.DomainsNote(domains))
+	}
+	if sampled {
+		notes = append(notes, formatter.SampleNote(estimate.Margin, estimate.SampleSize))
+	}
+	// The headline shows the aggregation the settings ask for; the notes comparing it with
+	// history, and the stored point, stay per-post
+	headlineSentiment, headlinePercentage := overallSentiment, netSentimentPercentage
+	switch h.sentimentAggregation(ctx) {
+	case aggregation.ModeEngagement:
+		headlinePercentage = weightedPercentage
+		headlineSentiment = h.sentimentThresholds.Categorize(weightedPercentage/100, previousCategory)
+		notes = append(notes, formatter.EngagementWeightedNote())
+	case aggregation.ModeBoth:
+		notes = append(notes, formatter.WeightedSentimentNote(weightedPercentage))
+	}
+	if event.Replay {
+		notes = append(notes, formatter.DelayedNote(windowEnd))
+	}
+	// Replays post late, which would skew an experiment's engagement; channels post in
+	// their own layout
+	var experiment *experiments.Experiment
+	if !event.Replay && h.channel == nil {
+		if experiment, err = experiments.Load(ctx, h.ssmClient); err != nil {
+			log.Printf("Ignoring experiment: %v", err)
+		}
+	}
+	err = h.postSummary(runState, topPosts, headlineSentiment, len(filteredPosts), headlinePercentage, badge != nil && !event.Replay && h.channel == nil, experiment, notes...)
 	if err != nil {
 		log.Printf("Failed to post summary: %v", err)
+		h.recordStepTimings(ctx, event.RunID, state.NewStepTiming(state.StepPost, postStart, state.StepStatusFailed))
 		return Response{
 			StatusCode: 500,
 			Body:       "Failed to post summary: " + err.Error(),
@@ -205,60 +703,377 @@ func (h *ProcessorHandler) HandleRequest(ctx context.Context, event ProcessorEve
 	}
 
 	log.Printf("Successfully processed %d posts and posted summary for run: %s", len(analyzedPosts), event.RunID)
+	h.recordStepTimings(ctx, event.RunID, state.NewStepTiming(state.StepPost, postStart, state.StepStatusCompleted))
 
 	// Store sentiment data for sparkline generation
 	// Use TotalPostsRetrieved to show the actual number of posts collected, not just analyzed
+	// Replayed runs are stored at the end of their own window so the history stays in order
 	log.Printf("Storing sentiment data for sparkline generation")
-	err = h.storeSentimentData(event.RunID, overallSentiment, netSentimentPercentage, runState.TotalPostsRetrieved)
+	dataPoint.Timestamp = time.Now()
+	if event.Replay {
+		dataPoint.Timestamp = windowEnd
+	}
+	err = h.storeSentimentData(dataPoint)
 	if err != nil {
 		log.Printf("Failed to store sentiment data: %v", err)
 		// Don't fail the main process if sentiment storage fails
 	}
 
+	// A late sparkline would duplicate the one posted by the next on-time run
+	if event.Replay {
+		log.Printf("Replayed run %s posted, skipping sparkline poster", event.RunID)
+		return Response{
+			StatusCode:       200,
+			Body:             "Delayed summary posted",
+			PostsAnalyzed:    len(analyzedPosts),
+			TopPostsCount:    len(topPosts),
+			OverallSentiment: overallSentiment,
+		}, nil
+	}
+
 	// Trigger sparkline poster after successful main post
 	log.Printf("Triggering sparkline poster for run: %s", event.RunID)
-	err = h.triggerSparklinePoster(event.RunID)
+	err = h.triggerSparklinePoster(ctx, event.RunID, runState.AnalysisIntervalMinutes)
 	if err != nil {
 		log.Printf("Failed to trigger sparkline poster: %v", err)
 		// Don't fail the main process if sparkline fails
 	}
 
+	body := "Posts processed and summary posted successfully"
+	if !h.dryRun.Posts() {
+		body = "Posts processed, summary not posted (dry run: " + h.dryRun.String() + ")"
+	}
 	return Response{
 		StatusCode:       200,
-		Body:             "Posts processed and summary posted successfully",
+		Body:             body,
 		PostsAnalyzed:    len(analyzedPosts),
 		TopPostsCount:    len(topPosts),
 		OverallSentiment: overallSentiment,
 	}, nil
 }
 
+// scheduleAllows checks the posting schedule; an unreadable schedule is logged and allows posting
+func (h *ProcessorHandler) scheduleAllows(ctx context.Context, poster string) (bool, string) {
+	if h.channel.HasSchedule() {
+		return h.channel.Rules().Allows(poster, time.Now())
+	}
+	rules, err := schedule.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("Ignoring posting schedule: %v", err)
+		return true, ""
+	}
+	return rules.Allows(poster, time.Now())
+}
+
+// withholdSummary records a summary the schedule blocked, keeps the sentiment history
+// up to date, and still hands over to the sparkline poster, which applies its own rules
+func (h *ProcessorHandler) withholdSummary(ctx context.Context, runState *state.RunState, reason string, dataPoint state.SentimentDataPoint) Response {
+	log.Printf("🔕 PROCESSOR: Posting schedule withheld summary for run %s: %s", runState.RunID, reason)
+	if err := h.stateManager.RecordSkippedPost(ctx, runState.RunID, schedule.PosterSummary, reason); err != nil {
+		log.Printf("Failed to record skipped post: %v", err)
+	}
+	if err := h.stateManager.SetRunSkipped(ctx, runState.RunID, "posting schedule: "+reason); err != nil {
+		log.Printf("Failed to mark run as skipped: %v", err)
+	}
+
+	dataPoint.Timestamp = time.Now()
+	if err := h.storeSentimentData(dataPoint); err != nil {
+		log.Printf("Failed to store sentiment data: %v", err)
+	}
+
+	if err := h.triggerSparklinePoster(ctx, runState.RunID, runState.AnalysisIntervalMinutes); err != nil {
+		log.Printf("Failed to trigger sparkline poster: %v", err)
+	}
+
+	return Response{
+		StatusCode:       200,
+		Body:             "Posting schedule - summary skipped: " + reason,
+		OverallSentiment: dataPoint.SentimentCategory,
+	}
+}
+
+// storeManualRun stores the sentiment of a manual run started with skipPosting at the end
+// of its window, without posting it. The run isn't marked skipped, so the replay tool can
+// still post it.
+func (h *ProcessorHandler) storeManualRun(runState *state.RunState, dataPoint state.SentimentDataPoint, windowEnd time.Time) Response {
+	log.Printf("🛠️ PROCESSOR: Manual run %s analyzed, not posting its summary", runState.RunID)
+
+	dataPoint.Timestamp = windowEnd
+	if err := h.storeSentimentData(dataPoint); err != nil {
+		log.Printf("Failed to store sentiment data: %v", err)
+	}
+
+	return Response{
+		StatusCode:       200,
+		Body:             "Manual run analyzed, summary not posted",
+		PostsAnalyzed:    dataPoint.TotalPosts,
+		OverallSentiment: dataPoint.SentimentCategory,
+	}
+}
+
+// publishStatus writes status.json for status pages when a status bucket is configured
+// Failures are logged, never fatal. A staging deployment shares the setting with
+// production, so it never publishes over production's status page.
+func (h *ProcessorHandler) publishStatus(ctx context.Context) {
+	if dryrun.IsStaging() {
+		return
+	}
+	destination, err := status.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("Ignoring status page setting: %v", err)
+		return
+	}
+	if destination == nil {
+		return
+	}
+
+	runs, err := h.stateManager.GetRecentRuns(ctx, status.LookbackWindow)
+	if err != nil {
+		log.Printf("Failed to get recent runs for status page: %v", err)
+		return
+	}
+
+	s3Client, err := status.NewS3Client(ctx)
+	if err != nil {
+		log.Printf("Failed to create S3 client for status page: %v", err)
+		return
+	}
+	if err := status.Publish(ctx, s3Client, *destination, status.Build(runs, time.Now())); err != nil {
+		log.Printf("Failed to publish status page: %v", err)
+	}
+}
+
+// recordStepTimings adds step timings to the run's audit trail; failures are logged, not fatal
+func (h *ProcessorHandler) recordStepTimings(ctx context.Context, runID string, timings ...state.StepTiming) {
+	if err := h.stateManager.RecordStepTimings(ctx, runID, timings...); err != nil {
+		log.Printf("Failed to record step timings: %v", err)
+	}
+}
+
+// getLastWeekPoint looks up the sentiment recorded at the same time one week before windowEnd
+// Missing history or lookup failures return nil so the summary is posted without the comparison
+func (h *ProcessorHandler) getLastWeekPoint(ctx context.Context, windowEnd time.Time) *state.SentimentDataPoint {
+	point, err := h.sentimentHistoryManager.GetPointNearTime(ctx, windowEnd.Add(-7*24*time.Hour), weekOverWeekTolerance)
+	if err != nil {
+		log.Printf("Failed to get last week's sentiment: %v", err)
+		return nil
+	}
+	if point == nil {
+		log.Printf("No sentiment history near %s, skipping week-over-week comparison", windowEnd.Add(-7*24*time.Hour).Format("2006-01-02 15:04 UTC"))
+	}
+	return point
+}
+
+// getPreviousCategory returns the category of the latest run before windowEnd, for the
+// thresholds' hysteresis. Without hysteresis, history or a recent run it returns "", so
+// the plain threshold applies.
+func (h *ProcessorHandler) getPreviousCategory(ctx context.Context, windowEnd time.Time) string {
+	if h.sentimentThresholds.Hysteresis == 0 {
+		return ""
+	}
+	history, err := h.sentimentHistoryManager.GetSentimentHistory(ctx, time.Since(windowEnd.Add(-previousCategoryLookback)))
+	if err != nil {
+		log.Printf("Failed to get sentiment history for the previous category: %v", err)
+		return ""
+	}
+	var previous *state.SentimentDataPoint
+	for i, point := range history {
+		if point.Network != "" || !point.Timestamp.Before(windowEnd) {
+			continue
+		}
+		if previous == nil || point.Timestamp.After(previous.Timestamp) {
+			previous = &history[i]
+		}
+	}
+	if previous == nil {
+		return ""
+	}
+	return previous.SentimentCategory
+}
+
+// evaluateBadge compares the run with the hour-of-day baseline from sentiment history
+// History failures return nil so the summary is posted without a badge, as do days the
+// calendar expects to be outliers
+func (h *ProcessorHandler) evaluateBadge(ctx context.Context, netSentimentPercentage float64, windowEnd time.Time, notableDays *calendar.Calendar) *insight.Badge {
+	history, err := h.sentimentHistoryManager.GetSentimentHistory(ctx, insight.BaselineWindow)
+	if err != nil {
+		log.Printf("Failed to get sentiment history for baseline: %v", err)
+		return nil
+	}
+
+	badge := insight.EvaluateBadge(history, netSentimentPercentage, windowEnd)
+	if badge != nil {
+		log.Printf("📊 PROCESSOR: Sentiment is unusually %s for hour %d (z-score %.2f)", badge.Direction, windowEnd.UTC().Hour(), badge.ZScore)
+		if notableDays.ExpectsOutlier(windowEnd) {
+			log.Printf("📅 PROCESSOR: Not flagging unusual sentiment on %v", notableDays.On(windowEnd))
+			return nil
+		}
+	}
+	return badge
+}
+
+// useChannel loads the settings of the run's channel and scopes the handler to it: the
+// summary is posted from the channel's account and history is read and stored for the
+// channel. The default channel, with an empty ID, restores the defaults.
+func (h *ProcessorHandler) useChannel(ctx context.Context, channelID string) error {
+	runChannel, err := channel.Get(ctx, h.ssmClient, channelID)
+	if err != nil {
+		return err
+	}
+
+	h.channel = runChannel
+	h.sentimentHistoryManager.SetChannel(channelID)
+	h.blueskyClient = h.defaultClient
+	if !runChannel.HasOwnCredentials() {
+		return nil
+	}
+
+	handleName, passwordName := runChannel.CredentialParameters()
+	result, err := h.ssmClient.GetParameters(ctx, &ssm.GetParametersInput{
+		Names:          []string{handleName, passwordName},
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get channel %s credentials: %w", channelID, err)
+	}
+	params := make(map[string]string)
+	for _, p := range result.Parameters {
+		params[aws.ToString(p.Name)] = aws.ToString(p.Value)
+	}
+	if params[handleName] == "" || params[passwordName] == "" {
+		return fmt.Errorf("channel %s credentials not found under %s", channelID, runChannel.Credentials)
+	}
+	log.Printf("📺 PROCESSOR: Posting channel %s as %s", channelID, params[handleName])
+	h.blueskyClient = client.New(params[handleName], params[passwordName])
+	return nil
+}
+
+// useDryRun reads the dry run level for the run, falling back to the level loaded at
+// startup when the parameter can't be read. A shadow dry run posts from the shadow
+// account, whichever channel the run is for, so it follows useChannel.
+func (h *ProcessorHandler) useDryRun(ctx context.Context) error {
+	level, err := dryrun.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("⚠️ PROCESSOR: Failed to load dry run level, using %s: %v", h.config.Settings.DryRun, err)
+		level = h.config.Settings.DryRun
+	}
+	h.dryRun = level
+	if !level.ShadowAccount() {
+		return nil
+	}
+
+	shadow, err := level.Credentials(nil, h.ssmClient).Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get shadow account credentials: %w", err)
+	}
+	log.Printf("👥 PROCESSOR: Shadow dry run, posting as %s", shadow.Handle)
+	h.blueskyClient = client.New(shadow.Handle, shadow.Password)
+	return nil
+}
+
+// measureVolatility computes the rolling volatility of net sentiment over the last day
+// History failures and thin history return nil, so the run is stored without it
+func (h *ProcessorHandler) measureVolatility(ctx context.Context, netSentimentPercentage float64, windowEnd time.Time) *float64 {
+	history, err := h.sentimentHistoryManager.GetSentimentHistory(ctx, insight.VolatilityWindow)
+	if err != nil {
+		log.Printf("Failed to get sentiment history for volatility: %v", err)
+		return nil
+	}
+
+	volatility, samples, ok := insight.Volatility(history, netSentimentPercentage, windowEnd)
+	if !ok {
+		log.Printf("📈 PROCESSOR: Too little history for volatility (%d points)", samples)
+		return nil
+	}
+	log.Printf("📈 PROCESSOR: Volatility %.2f over %d points - mood is %s", volatility, samples, insight.Stability(volatility))
+	return &volatility
+}
+
+// rankToday ranks today's average sentiment so far, the current run included, among the
+// daily averages of the last state.PercentileDays days
+// Failures and thin history return nil so the summary is posted without it
+func (h *ProcessorHandler) rankToday(ctx context.Context, netSentimentPercentage float64, windowEnd time.Time) *state.SentimentPercentile {
+	today := windowEnd.UTC().Truncate(24 * time.Hour)
+	history, err := h.sentimentHistoryManager.GetSentimentHistory(ctx, time.Since(today))
+	if err != nil {
+		log.Printf("Failed to get today's sentiment history for percentile: %v", err)
+		return nil
+	}
+	history = append(history, state.SentimentDataPoint{Timestamp: windowEnd, NetSentimentPercent: netSentimentPercentage})
+	average, runs := state.DayAverage(history, windowEnd)
+
+	rank, err := h.dailySentimentManager.GetSentimentPercentile(ctx, today.Format("2006-01-02"), average, state.PercentileDays)
+	if err != nil {
+		log.Printf("Failed to rank today's sentiment: %v", err)
+		return nil
+	}
+	if rank == nil {
+		log.Printf("📊 PROCESSOR: Too few days of daily sentiment to rank today")
+		return nil
+	}
+	log.Printf("📊 PROCESSOR: Today's sentiment %.1f%% over %d runs ranks at percentile %d of %d days", average, runs, rank.Percentile, rank.Days)
+	return rank
+}
+
+// loadCalendar opens the configured calendar of notable days
+// Failures return nil so the summary is posted without it
+func (h *ProcessorHandler) loadCalendar(ctx context.Context) *calendar.Calendar {
+	settings, err := calendar.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("Ignoring calendar: %v", err)
+		return nil
+	}
+	if settings == nil {
+		return nil
+	}
+	notableDays, err := settings.Open(ctx)
+	if err != nil {
+		log.Printf("Ignoring calendar: %v", err)
+		return nil
+	}
+	return notableDays
+}
+
 // analyzePosts analyzes sentiment and calculates engagement scores
-func (h *ProcessorHandler) analyzePosts(posts []state.Post) ([]state.Post, string, float64, error) {
+// It also segments sentiment across the window so the summary can report a trend
+// previousCategory is the previous run's category for the thresholds' hysteresis, or ""
+// to categorize the run on its own
+func (h *ProcessorHandler) analyzePosts(posts []state.Post, windowStart, windowEnd time.Time, previousCategory string) ([]state.Post, string, float64, analyzer.SentimentTrend, error) {
 	log.Printf("Analyzing %d posts", len(posts))
 
-	// Convert state posts to analyzer posts
-	analyzerPosts := make([]analyzer.Post, len(posts))
+	// Posts the fetcher analyzed keep their stored analysis; the rest are analyzed here
+	analyzedPosts := make([]analyzer.AnalyzedPost, len(posts))
+	var analyzerPosts []analyzer.Post
+	var pending []int
 	for i, post := range posts {
-		analyzerPosts[i] = analyzer.Post{
-			URI:       post.URI,
-			CID:       post.CID,
-			Text:      post.Text,
-			Author:    post.Author,
-			Likes:     post.Likes,
-			Reposts:   post.Reposts,
-			Replies:   post.Replies,
-			CreatedAt: post.CreatedAt,
+		if stored, ok := analyzer.Stored(post); ok {
+			analyzedPosts[i] = stored
+			continue
 		}
+		analyzerPosts = append(analyzerPosts, toAnalyzerPost(post))
+		pending = append(pending, i)
+	}
+	if reused := len(posts) - len(pending); reused > 0 {
+		log.Printf("♻️ PROCESSOR: Using fetch-time analysis for %d posts, analyzing %d", reused, len(pending))
 	}
 
 	// Analyze posts
-	analyzedPosts, err := h.sentimentAnalyzer.AnalyzePosts(analyzerPosts)
+	results, err := h.sentimentAnalyzer.AnalyzePosts(analyzerPosts)
 	if err != nil {
-		return nil, "", 0.0, fmt.Errorf("failed to analyze posts: %w", err)
+		return nil, "", 0.0, analyzer.SentimentTrend{}, fmt.Errorf("failed to analyze posts: %w", err)
+	}
+	for j, i := range pending {
+		analyzedPosts[i] = results[j]
 	}
 
 	// Calculate overall sentiment using compound scores
-	overallSentiment, netSentimentPercentage := h.calculateOverallSentimentWithCompoundScores(analyzedPosts)
+	overallSentiment, netSentimentPercentage := h.calculateOverallSentimentWithCompoundScores(analyzedPosts, previousCategory)
+
+	trend := analyzer.CalculateSentimentTrend(analyzedPosts, windowStart, windowEnd, trendSegmentCount)
+	for i, segment := range trend.Segments {
+		log.Printf("📈 PROCESSOR: Segment %d (%s-%s UTC): %d posts, %.1f%% net sentiment",
+			i+1, segment.Start.Format("15:04"), segment.End.Format("15:04"), segment.PostCount, segment.NetSentimentPercent)
+	}
 
 	// Convert back to state posts with analysis results
 	statePosts := make([]state.Post, len(analyzedPosts))
@@ -266,15 +1081,21 @@ func (h *ProcessorHandler) analyzePosts(posts []state.Post) ([]state.Post, strin
 		statePosts[i] = state.Post{
 			URI:             analyzed.URI,
 			CID:             analyzed.CID,
-			Text:            analyzed.Text,
+			Text:            posts[i].Text,
 			Author:          analyzed.Author,
 			Likes:           analyzed.Likes,
 			Reposts:         analyzed.Reposts,
 			Replies:         analyzed.Replies,
-			Sentiment:       analyzed.Sentiment,
-			EngagementScore: analyzed.EngagementScore,
 			CreatedAt:       analyzed.CreatedAt,
+			Media:           posts[i].Media,
+			LinkURL:         posts[i].LinkURL,
+			ImageText:       posts[i].ImageText,
+			ReplyRoot:       posts[i].ReplyRoot,
+			ContentWarnings: posts[i].ContentWarnings,
+			AgeMinutes:      posts[i].AgeMinutes,
+			AvatarURL:       posts[i].AvatarURL,
 		}
+		analyzed.Apply(&statePosts[i])
 
 		// Debug logging for first few posts
 		if i < 5 {
@@ -283,16 +1104,16 @@ func (h *ProcessorHandler) analyzePosts(posts []state.Post) ([]state.Post, strin
 		}
 	}
 
-	return statePosts, overallSentiment, netSentimentPercentage, nil
+	return statePosts, overallSentiment, netSentimentPercentage, trend, nil
 }
 
-func (h *ProcessorHandler) calculateOverallSentimentWithCompoundScores(posts []analyzer.AnalyzedPost) (string, float64) {
+func (h *ProcessorHandler) calculateOverallSentimentWithCompoundScores(posts []analyzer.AnalyzedPost, previousCategory string) (string, float64) {
 	if len(posts) == 0 {
 		return "neutral", 0.0
 	}
 
-	var totalCompoundScore float64
-	for _, post := range posts {
+	scores := make([]float64, len(posts))
+	for i, post := range posts {
 		// Clamp compound score to expected VADER range (-1.0 to +1.0)
 		clampedScore := post.SentimentScore
 		if clampedScore > 1.0 {
@@ -300,40 +1121,111 @@ func (h *ProcessorHandler) calculateOverallSentimentWithCompoundScores(posts []a
 		} else if clampedScore < -1.0 {
 			clampedScore = -1.0
 		}
-		totalCompoundScore += clampedScore
+		scores[i] = clampedScore
 	}
 
-	averageCompoundScore := totalCompoundScore / float64(len(posts))
+	// Averaged by the run's method; the mean can be dragged by a few extreme posts
+	averageCompoundScore := h.sentimentMethod.Average(scores)
 
-	// Map compound score to category for backward compatibility
-	var sentimentCategory string
-	if averageCompoundScore >= 0.3 {
-		sentimentCategory = "positive"
-	} else if averageCompoundScore <= -0.3 {
-		sentimentCategory = "negative"
-	} else {
-		sentimentCategory = "neutral"
-	}
+	// Map compound score to category for backward compatibility, keeping the previous
+	// run's category while the score stays within the hysteresis band
+	sentimentCategory := h.sentimentThresholds.Categorize(averageCompoundScore, previousCategory)
 
 	// Scale to percentage range for 100-word system
 	netSentimentPercentage := averageCompoundScore * 100.0
 
-	log.Printf("🔍 PROCESSOR DEBUG: Average compound score: %.3f, Net sentiment: %.1f%%, Sentiment: %s",
-		averageCompoundScore, netSentimentPercentage, sentimentCategory)
+	log.Printf("🔍 PROCESSOR DEBUG: Average compound score (%s): %.3f, Net sentiment: %.1f%%, Sentiment: %s",
+		h.sentimentMethod, averageCompoundScore, netSentimentPercentage, sentimentCategory)
 
 	return sentimentCategory, netSentimentPercentage
 }
 
+// toAnalyzerPost converts a stored post for analysis
+func toAnalyzerPost(post state.Post) analyzer.Post {
+	return analyzer.Post{
+		URI:       post.URI,
+		CID:       post.CID,
+		Text:      post.AnalysisText(),
+		Author:    post.Author,
+		Likes:     post.Likes,
+		Reposts:   post.Reposts,
+		Replies:   post.Replies,
+		CreatedAt: post.CreatedAt,
+		Langs:     post.Langs,
+	}
+}
+
+// analyzeTopPosts analyzes top posts ranked from the whole window when only a sample was
+// analyzed, falling back to the sample's top posts if analysis fails
+func (h *ProcessorHandler) analyzeTopPosts(posts, fallback []state.Post) []state.Post {
+	analyzerPosts := make([]analyzer.Post, len(posts))
+	for i, post := range posts {
+		analyzerPosts[i] = toAnalyzerPost(post)
+	}
+	results, err := h.sentimentAnalyzer.AnalyzePosts(analyzerPosts)
+	if err != nil {
+		log.Printf("Failed to analyze top posts, using the sample's: %v", err)
+		return fallback
+	}
+
+	topPosts := make([]state.Post, len(posts))
+	for i, post := range posts {
+		topPosts[i] = post
+		results[i].Apply(&topPosts[i])
+	}
+	return topPosts
+}
+
+// rankTopPosts picks the top n posts the way getTopPosts does, sorting rather than
+// comparing every pair of posts so it scales to a whole sampled window. Posts outside
+// the sample haven't been analyzed, so their engagement score is taken from their counts.
+func rankTopPosts(posts []state.Post, n int, mediaRanking, topPostsRanking string) []state.Post {
+	ranked := make([]state.Post, 0, len(posts))
+	for _, post := range posts {
+		if mediaRanking != mediaRankingExclude || post.Media == "" {
+			post.EngagementScore = float64(post.Replies + post.Likes + post.Reposts)
+			ranked = append(ranked, post)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return rankingScore(ranked[i], mediaRanking, topPostsRanking) > rankingScore(ranked[j], mediaRanking, topPostsRanking)
+	})
+	return ranked[:min(n, len(ranked))]
+}
+
+// rankingScore is a post's engagement score, or its engagement per minute of age in
+// velocity mode, boosted for media posts in boost mode
+func rankingScore(post state.Post, mediaRanking, topPostsRanking string) float64 {
+	score := post.EngagementScore
+	if topPostsRanking == topPostsRankingVelocity {
+		score /= max(post.AgeMinutes, minVelocityAgeMinutes)
+	}
+	if mediaRanking == mediaRankingBoost && post.Media != "" {
+		return score * mediaBoostFactor
+	}
+	return score
+}
+
 // getTopPosts gets the top N posts by engagement score
-func (h *ProcessorHandler) getTopPosts(posts []state.Post, n int) []state.Post {
+func (h *ProcessorHandler) getTopPosts(posts []state.Post, n int, mediaRanking, topPostsRanking string) []state.Post {
+	if mediaRanking == mediaRankingExclude {
+		textOnly := make([]state.Post, 0, len(posts))
+		for _, post := range posts {
+			if post.Media == "" {
+				textOnly = append(textOnly, post)
+			}
+		}
+		posts = textOnly
+	}
+
 	if len(posts) <= n {
 		return posts
 	}
 
-	// Sort by engagement score (descending)
+	// Sort by engagement score (descending), boosting media posts when configured
 	for i := 0; i < len(posts)-1; i++ {
 		for j := i + 1; j < len(posts); j++ {
-			if posts[i].EngagementScore < posts[j].EngagementScore {
+			if rankingScore(posts[i], mediaRanking, topPostsRanking) < rankingScore(posts[j], mediaRanking, topPostsRanking) {
 				posts[i], posts[j] = posts[j], posts[i]
 			}
 		}
@@ -342,6 +1234,205 @@ func (h *ProcessorHandler) getTopPosts(posts []state.Post, n int) []state.Post {
 	return posts[:n]
 }
 
+// rankingCandidates returns the posts that may be top posts: all of them, less replies
+// when replies are excluded and posts with content warnings when those are hidden
+func rankingCandidates(posts []state.Post, replyRanking, contentWarnings string) []state.Post {
+	excludeReplies := replyRanking == replyRankingExclude
+	hideWarned := contentWarnings != contentWarningsShow
+	if !excludeReplies && !hideWarned {
+		return posts
+	}
+	candidates := make([]state.Post, 0, len(posts))
+	for _, post := range posts {
+		if (excludeReplies && post.IsReply()) || (hideWarned && post.HasContentWarning()) {
+			continue
+		}
+		candidates = append(candidates, post)
+	}
+	return candidates
+}
+
+// recordContentWarningPosts logs and stores how many of the run's posts carried content
+// warnings; they are analyzed like any other post
+func (h *ProcessorHandler) recordContentWarningPosts(ctx context.Context, runID string, posts []state.Post, contentWarnings string) {
+	warned := 0
+	for _, post := range posts {
+		if post.HasContentWarning() {
+			warned++
+		}
+	}
+	log.Printf("⚠️ PROCESSOR: %d of %d posts had content warnings (%s in top posts)", warned, len(posts), contentWarnings)
+	if err := metrics.Emit(map[string]string{"Function": "processor"},
+		metrics.Metric{Name: "ContentWarningPosts", Value: float64(warned), Unit: metrics.UnitCount},
+	); err != nil {
+		log.Printf("Failed to emit content warning metric: %v", err)
+	}
+	if warned == 0 {
+		return
+	}
+	if err := h.stateManager.SetContentWarningPosts(ctx, runID, warned); err != nil {
+		log.Printf("Failed to store content warning count: %v", err)
+	}
+}
+
+// replyPercent is the percentage of posts that are replies
+func replyPercent(posts []state.Post) float64 {
+	if len(posts) == 0 {
+		return 0
+	}
+	replies := 0
+	for _, post := range posts {
+		if post.IsReply() {
+			replies++
+		}
+	}
+	return float64(replies) / float64(len(posts)) * 100
+}
+
+// getReplyRanking reads the optional reply ranking mode, defaulting to include
+func (h *ProcessorHandler) getReplyRanking(ctx context.Context) string {
+	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(replyRankingParameter),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		return replyRankingInclude
+	}
+
+	switch mode := strings.TrimSpace(aws.ToString(result.Parameter.Value)); mode {
+	case replyRankingInclude, replyRankingExclude:
+		return mode
+	default:
+		log.Printf("Unknown %s value %q, using %s", replyRankingParameter, mode, replyRankingInclude)
+		return replyRankingInclude
+	}
+}
+
+// getContentWarnings reads the optional content warning mode, defaulting to hide
+func (h *ProcessorHandler) getContentWarnings(ctx context.Context) string {
+	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(contentWarningsParameter),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		return contentWarningsHide
+	}
+
+	switch mode := strings.TrimSpace(aws.ToString(result.Parameter.Value)); mode {
+	case contentWarningsHide, contentWarningsShow:
+		return mode
+	default:
+		log.Printf("Unknown %s value %q, using %s", contentWarningsParameter, mode, contentWarningsHide)
+		return contentWarningsHide
+	}
+}
+
+// getTopPostsRanking reads the optional top posts ranking mode, defaulting to engagement
+func (h *ProcessorHandler) getTopPostsRanking(ctx context.Context) string {
+	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(topPostsRankingParameter),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		return topPostsRankingEngagement
+	}
+
+	switch mode := strings.TrimSpace(aws.ToString(result.Parameter.Value)); mode {
+	case topPostsRankingEngagement, topPostsRankingVelocity:
+		return mode
+	default:
+		log.Printf("Unknown %s value %q, using %s", topPostsRankingParameter, mode, topPostsRankingEngagement)
+		return topPostsRankingEngagement
+	}
+}
+
+// getMediaRanking reads the optional media ranking mode, defaulting to neutral
+func (h *ProcessorHandler) getMediaRanking(ctx context.Context) string {
+	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(mediaRankingParameter),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		return mediaRankingNeutral
+	}
+
+	switch mode := strings.TrimSpace(aws.ToString(result.Parameter.Value)); mode {
+	case mediaRankingNeutral, mediaRankingBoost, mediaRankingExclude:
+		return mode
+	default:
+		log.Printf("Unknown %s value %q, using %s", mediaRankingParameter, mode, mediaRankingNeutral)
+		return mediaRankingNeutral
+	}
+}
+
+// getAnalysisConcurrency reads the optional analysis worker count, defaulting to one worker
+// per vCPU Lambda allocates, which at the processor's memory size is one
+func (h *ProcessorHandler) getAnalysisConcurrency(ctx context.Context) int {
+	workers := runtime.GOMAXPROCS(0)
+	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(analysisConcurrencyParameter),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		return workers
+	}
+
+	value := strings.TrimSpace(aws.ToString(result.Parameter.Value))
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		log.Printf("Ignoring invalid %s value %q, using %d", analysisConcurrencyParameter, value, workers)
+		return workers
+	}
+	return min(n, maxAnalysisConcurrency)
+}
+
+// loadBlocklist reads the blocklist and the members of subscribed moderation lists; a
+// failure only logs, and the run keeps the posts it would have blocked
+func (h *ProcessorHandler) loadBlocklist(ctx context.Context) *blocklist.List {
+	entries, err := h.blocklistManager.ListEntries(ctx)
+	if err != nil {
+		log.Printf("⚠️ PROCESSOR: Failed to load blocklist, keeping blocked posts: %v", err)
+	}
+	list := blocklist.New(entries)
+
+	if listURIs := h.getModerationLists(ctx); len(listURIs) > 0 {
+		list.BlockListMembers(h.moderationLists.Members(ctx, listURIs))
+	}
+	return list
+}
+
+// getModerationLists reads the optional moderation list URIs, none when unset
+func (h *ProcessorHandler) getModerationLists(ctx context.Context) []string {
+	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(moderationListsParameter),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		return nil
+	}
+	return identity.ParseListURIs(aws.ToString(result.Parameter.Value))
+}
+
+// applyBlocklist filters posts through the blocklist and records how many it left out
+func (h *ProcessorHandler) applyBlocklist(ctx context.Context, runID string, posts []state.Post) []state.Post {
+	kept, blocked := h.loadBlocklist(ctx).Filter(posts)
+	if len(kept) == len(posts) {
+		return posts
+	}
+
+	log.Printf("🚫 PROCESSOR: Blocklist left out %d of %d posts (authors: %d, domains: %d, keywords: %d, moderation lists: %d)",
+		len(posts)-len(kept), len(posts), blocked[state.BlockAuthor], blocked[state.BlockDomain], blocked[state.BlockKeyword], blocked[state.BlockListMember])
+	if err := metrics.Emit(map[string]string{"Function": "processor"},
+		metrics.Metric{Name: "BlockedPosts", Value: float64(len(posts) - len(kept)), Unit: metrics.UnitCount},
+	); err != nil {
+		log.Printf("Failed to emit blocklist metric: %v", err)
+	}
+	if err := h.stateManager.SetBlockedPosts(ctx, runID, blocked); err != nil {
+		log.Printf("Failed to store blocked post counts: %v", err)
+	}
+	return kept
+}
+
 // filterPostsByCutoffTime filters posts to only include those after the cutoff time
 func (h *ProcessorHandler) filterPostsByCutoffTime(posts []state.Post, cutoffTime time.Time) []state.Post {
 	var filteredPosts []state.Post
@@ -361,7 +1452,10 @@ func (h *ProcessorHandler) filterPostsByCutoffTime(posts []state.Post, cutoffTim
 }
 
 // postSummary posts the summary to Bluesky
-func (h *ProcessorHandler) postSummary(runState *state.RunState, topPosts []state.Post, overallSentiment string, totalPosts int, netSentimentPercentage float64) error {
+// Optional notes are appended to the post text by the formatter
+// A milestone summary, one flagged as unusual for its hour, is offered for pinning
+// During an experiment the summary is laid out in the run's variant, which is recorded
+func (h *ProcessorHandler) postSummary(runState *state.RunState, topPosts []state.Post, overallSentiment string, totalPosts int, netSentimentPercentage float64, milestone bool, experiment *experiments.Experiment, notes ...string) error {
 	// Check if we have data to post
 	if runState.TotalPostsRetrieved == 0 {
 		log.Printf("No posts retrieved, skipping post")
@@ -410,26 +1504,87 @@ func (h *ProcessorHandler) postSummary(runState *state.RunState, topPosts []stat
 		}
 	}
 
-	postContent := formatter.FormatPostContent(formatterPosts, overallSentiment, runState.AnalysisIntervalMinutes, totalPosts, netSentimentPercentage/100.0)
-	characterCount := len(postContent)
-	blueskyLimit := 300
-	remainingChars := blueskyLimit - characterCount
+	template := formatter.TemplateClassic
+	if h.channel != nil && h.channel.Template != "" {
+		template = h.channel.Template
+	}
+	if experiment != nil {
+		template = experiment.Assign(runState.RunID)
+		log.Printf("🧪 Experiment %s: posting variant %s", experiment.Name, template)
+	}
+	layout := formatter.Layout{
+		Template:   template,
+		Numbers:    h.numberFormat(context.Background()),
+		Engagement: h.isSummaryLikesEnabled(context.Background()),
+	}
+	h.blueskyClient.SetSummaryLayout(layout)
+
+	postContent, _ := formatter.FormatPostContentWithLayout(layout, formatterPosts, overallSentiment, runState.AnalysisIntervalMinutes, totalPosts, netSentimentPercentage/100.0, notes...)
+	postStats := runreport.NewPostStatistics(postContent)
+	h.report.GeneratedPost, h.report.PostStatistics = postContent, postStats
 
-	log.Printf("📊 Post Statistics - Characters: %d/%d, Remaining: %d", characterCount, blueskyLimit, remainingChars)
+	log.Printf("📊 Post Statistics - Characters: %d/%d, Remaining: %d", postStats.CharacterCount, postStats.BlueskyLimit, postStats.Remaining)
 
-	if remainingChars < 0 {
-		log.Printf("⚠️  WARNING: Post exceeds Bluesky limit by %d characters!", -remainingChars)
-	} else if remainingChars < 50 {
-		log.Printf("⚠️  WARNING: Post is close to Bluesky limit (%d characters remaining)", remainingChars)
+	if postStats.Remaining < 0 {
+		log.Printf("⚠️  WARNING: Post exceeds Bluesky limit by %d characters!", -postStats.Remaining)
+	} else if postStats.Remaining < 50 {
+		log.Printf("⚠️  WARNING: Post is close to Bluesky limit (%d characters remaining)", postStats.Remaining)
 	} else {
 		log.Printf("✅ Post is within Bluesky limits")
 	}
 
+	if !h.dryRun.Posts() {
+		log.Printf("📝 Dry run (%s), not posting:\n%s", h.dryRun, postContent)
+		return nil
+	}
+
+	// Render the optional summary or top posts card; the summary is still posted without it
+	// if rendering fails, and the post text is kept either way for accessibility
+	var cardImage []byte
+	var cardAltText string
+	if h.isSummaryCardEnabled(context.Background()) {
+		var err error
+		cardImage, cardAltText, err = h.renderSummaryCard(context.Background(), runState, totalPosts, netSentimentPercentage)
+		if err != nil {
+			log.Printf("Failed to render summary card, posting without it: %v", err)
+			cardImage, cardAltText = nil, ""
+		}
+	} else if h.isTopPostsCardEnabled(context.Background()) {
+		var err error
+		cardImage, cardAltText, err = h.renderTopPostsCard(context.Background(), topPosts, runState.AnalysisIntervalMinutes)
+		if err != nil {
+			log.Printf("Failed to render top posts card, posting without it: %v", err)
+			cardImage, cardAltText = nil, ""
+		}
+	}
+
 	// Post the summary
-	postedURI, postedCID, err := h.blueskyClient.PostTrendingSummary(clientPosts, overallSentiment, runState.AnalysisIntervalMinutes, totalPosts, netSentimentPercentage/100.0)
+	postedURI, postedCID, err := h.blueskyClient.PostTrendingSummaryWithImage(clientPosts, overallSentiment, runState.AnalysisIntervalMinutes, totalPosts, netSentimentPercentage/100.0, cardImage, cardAltText, notes...)
 	if err != nil {
 		return err
 	}
+	interaction.Apply(context.Background(), h.ssmClient, h.blueskyClient, schedule.PosterSummary, postedURI)
+	// A shadow post's engagement would skew the experiment, and its pin would replace the
+	// real account's on record
+	if h.dryRun.ShadowAccount() {
+		log.Printf("Shadow dry run, not recording the post for experiments or pinning")
+		experiment, milestone = nil, false
+	}
+	if experiment != nil {
+		if err := h.stateManager.RecordExperimentPost(context.Background(), state.ExperimentPost{
+			Experiment: experiment.Name,
+			Variant:    string(template),
+			SummaryRun: runState.RunID,
+			URI:        postedURI,
+			CID:        postedCID,
+			PostedAt:   time.Now().UTC(),
+		}); err != nil {
+			log.Printf("Failed to record experiment post: %v", err)
+		}
+	}
+	if milestone {
+		pin.Rotate(context.Background(), h.ssmClient, h.stateManager, h.blueskyClient, pin.KindMilestone, postedURI, postedCID)
+	}
 
 	// Store the posted URI and CID for reply functionality
 	if err := h.stateManager.SetTopPostURI(context.Background(), runState.RunID, postedURI, postedCID); err != nil {
@@ -442,79 +1597,217 @@ func (h *ProcessorHandler) postSummary(runState *state.RunState, topPosts []stat
 	return nil
 }
 
-// deduplicatePostsByURI removes duplicate posts by URI, keeping the one with highest engagement score
-func (h *ProcessorHandler) deduplicatePostsByURI(posts []state.Post) []state.Post {
-	uriToPost := make(map[string]state.Post)
+// isTopPostsCardEnabled checks the optional top posts card setting, defaulting to off
+func (h *ProcessorHandler) isTopPostsCardEnabled(ctx context.Context) bool {
+	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(topPostsCardParameter),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		return false
+	}
+	return aws.ToString(result.Parameter.Value) == "true"
+}
 
-	for _, post := range posts {
-		// Skip posts with empty URIs
-		if post.URI == "" {
-			continue
-		}
+// isSummaryCardEnabled checks the optional summary card setting, defaulting to off
+func (h *ProcessorHandler) isSummaryCardEnabled(ctx context.Context) bool {
+	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(summaryCardParameter),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		return false
+	}
+	return aws.ToString(result.Parameter.Value) == "true"
+}
 
-		// Calculate engagement score for this post
-		currentEngagement := post.Likes + post.Reposts + post.Replies
+// sentimentAggregation reads how the summary shows sentiment, defaulting to per post
+func (h *ProcessorHandler) sentimentAggregation(ctx context.Context) aggregation.Mode {
+	mode, err := aggregation.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("⚠️ PROCESSOR: Showing per-post sentiment: %v", err)
+	}
+	return mode
+}
 
-		// Check if we've seen this URI before
-		if existingPost, exists := uriToPost[post.URI]; exists {
-			// Calculate engagement score for existing post
-			existingEngagement := existingPost.Likes + existingPost.Reposts + existingPost.Replies
+// isStoreAnalyzedPostsEnabled checks whether every analyzed post should be stored individually
+// Defaults to false when the parameter is missing, since it adds one write per post
+func (h *ProcessorHandler) isStoreAnalyzedPostsEnabled(ctx context.Context) bool {
+	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(storeAnalyzedPostsParameter),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		return false
+	}
+	return aws.ToString(result.Parameter.Value) == "true"
+}
 
-			// Keep the post with higher engagement score
-			if currentEngagement > existingEngagement {
-				uriToPost[post.URI] = post
-				log.Printf("🔍 PROCESSOR DEBUG: Replacing post %s (engagement: %d) with better version (engagement: %d)",
-					post.URI, existingEngagement, currentEngagement)
-			}
-		} else {
-			// First time seeing this URI, add it
-			uriToPost[post.URI] = post
-		}
+// isSummaryLikesEnabled checks the optional top post likes setting, defaulting to off
+func (h *ProcessorHandler) isSummaryLikesEnabled(ctx context.Context) bool {
+	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(summaryLikesParameter),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		return false
 	}
+	return aws.ToString(result.Parameter.Value) == "true"
+}
+
+// numberFormat reads the locale of the summary's numbers, defaulting to English
+func (h *ProcessorHandler) numberFormat(ctx context.Context) formatter.NumberFormat {
+	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(numberLocaleParameter),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		return formatter.DefaultNumberFormat
+	}
+	format, err := formatter.ParseNumberFormat(aws.ToString(result.Parameter.Value))
+	if err != nil {
+		log.Printf("⚠️ PROCESSOR: Using %s numbers: %v", formatter.DefaultNumberFormat.Locale, err)
+	}
+	return format
+}
 
-	// Convert map values to slice
-	var deduplicatedPosts []state.Post
-	for _, post := range uriToPost {
-		deduplicatedPosts = append(deduplicatedPosts, post)
+// isDominantEmotionEnabled checks the optional dominant emotion line setting, defaulting to off
+func (h *ProcessorHandler) isDominantEmotionEnabled(ctx context.Context) bool {
+	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(dominantEmotionParameter),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		return false
 	}
+	return aws.ToString(result.Parameter.Value) == "true"
+}
 
-	log.Printf("🔍 PROCESSOR DEBUG: Deduplication removed %d duplicate posts", len(posts)-len(deduplicatedPosts))
-	return deduplicatedPosts
+// isTopDomainsEnabled checks the optional most shared domains line setting, defaulting to off
+func (h *ProcessorHandler) isTopDomainsEnabled(ctx context.Context) bool {
+	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(topDomainsParameter),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		return false
+	}
+	return aws.ToString(result.Parameter.Value) == "true"
 }
 
-// fixPostURIs fixes the URI format for posts retrieved from DynamoDB
-func (h *ProcessorHandler) fixPostURIs(posts []state.Post) []state.Post {
-	fixedCount := 0
-	for i, post := range posts {
-		// If the URI is in the old format (at://post-XXX), we need to construct a proper AT Protocol URI
-		// However, we don't have the DID information stored, so we'll skip posts with invalid URIs
-		if strings.HasPrefix(post.URI, "at://post-") {
-			log.Printf("🔍 URI FIX: Skipping post with invalid URI format: %s", post.URI)
-			posts[i].URI = "" // Set to empty to indicate invalid URI
-			fixedCount++
+// renderTopPostsCard draws the top posts into a preview image, fetching avatars in parallel
+// Avatars that fail to download fall back to initials, and the posts' text is passed
+// through the profanity filter
+func (h *ProcessorHandler) renderTopPostsCard(ctx context.Context, topPosts []state.Post, analysisIntervalMinutes int) ([]byte, string, error) {
+	renderer, err := preview.NewRenderer(nil)
+	if err != nil {
+		return nil, "", err
+	}
+	profanitySettings, err := profanity.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("⚠️ PROCESSOR: Masking profanity with the default settings: %v", err)
+	}
+	profanityFilter := profanity.New(profanitySettings)
+
+	cards := make([]preview.Card, len(topPosts))
+	var wg sync.WaitGroup
+	for i, post := range topPosts {
+		cards[i] = preview.Card{
+			Handle:    post.Author,
+			Text:      profanityFilter.Clean(post.Text),
+			Likes:     post.Likes,
+			Reposts:   post.Reposts,
+			Replies:   post.Replies,
+			Sentiment: post.Sentiment,
 		}
+		if post.AvatarURL == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			avatar, err := preview.FetchAvatar(ctx, http.DefaultClient, url)
+			if err != nil {
+				log.Printf("Failed to fetch avatar for @%s: %v", cards[i].Handle, err)
+				return
+			}
+			cards[i].Avatar = avatar
+		}(i, post.AvatarURL)
+	}
+	wg.Wait()
+
+	title := formatter.TopPostsCardTitle(analysisIntervalMinutes)
+	imageData, err := renderer.Render(title, cards)
+	if err != nil {
+		return nil, "", err
 	}
-	log.Printf("🔍 URI FIX: Fixed %d posts with invalid URI format", fixedCount)
-	return posts
+	return imageData, alttext.Truncate(preview.AltText(title, cards), alttext.MaxGraphemes), nil
 }
 
-// triggerSparklinePoster invokes the sparkline poster Lambda
-func (h *ProcessorHandler) triggerSparklinePoster(runID string) error {
-	log.Printf("🎯 SPARKLINE: Triggering sparkline poster for run: %s", runID)
+// renderSummaryCard draws the run's net sentiment, its change since the last window, and a
+// sparkline of the last day into a summary card image
+// History failures leave out the change and sparkline rather than failing the card
+func (h *ProcessorHandler) renderSummaryCard(ctx context.Context, runState *state.RunState, totalPosts int, netSentimentPercentage float64) ([]byte, string, error) {
+	renderer, err := preview.NewRenderer(nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	card := preview.SummaryCard{
+		Title:        formatter.SummaryCardTitle(runState.AnalysisIntervalMinutes),
+		NetSentiment: netSentimentPercentage,
+		TotalPosts:   totalPosts,
+		Mood:         formatter.MoodWord(netSentimentPercentage),
+	}
+
+	// The run's own point is stored after posting, so history ends with the last window;
+	// replays can see later windows, which are left out
+	windowEnd := runState.WindowEnd()
+	history, err := h.sentimentHistoryManager.GetSentimentHistory(ctx, time.Since(windowEnd.Add(-summaryCardHistory)))
+	if err != nil {
+		log.Printf("Failed to get sentiment history for summary card: %v", err)
+	}
+	if h.sentimentAggregation(ctx) == aggregation.ModeEngagement {
+		history = state.EngagementWeightedPoints(history)
+	}
+	for _, point := range history {
+		if point.Timestamp.Before(windowEnd) {
+			card.History = append(card.History, point.NetSentimentPercent)
+		}
+	}
+	if len(card.History) > 0 {
+		previous := card.History[len(card.History)-1]
+		card.Previous = &previous
+	}
+	card.History = append(card.History, netSentimentPercentage)
+
+	imageData, err := renderer.RenderSummary(card)
+	if err != nil {
+		return nil, "", err
+	}
+	return imageData, alttext.Truncate(preview.SummaryAltText(card), alttext.MaxGraphemes), nil
+}
 
-	// Prepare the payload for the sparkline poster
-	payload := map[string]interface{}{
-		"runId": runID,
+// triggerSparklinePoster invokes the sparkline poster Lambda
+func (h *ProcessorHandler) triggerSparklinePoster(ctx context.Context, runID string, analysisIntervalMinutes int) error {
+	if h.channel != nil {
+		log.Printf("🎯 SPARKLINE: Not triggering the sparkline poster for channel %s; the weekly chart covers the default channel", h.channel.ID)
+		return nil
 	}
+	log.Printf("🎯 SPARKLINE: Triggering sparkline poster for run: %s", runID)
 
-	payloadBytes, err := json.Marshal(payload)
+	payloadBytes, err := events.Marshal(events.RunEvent{
+		Version:                 events.Version,
+		RunID:                   runID,
+		AnalysisIntervalMinutes: analysisIntervalMinutes,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to marshal sparkline poster payload: %w", err)
 	}
 
 	// Invoke the sparkline poster Lambda asynchronously
-	_, err = h.lambdaClient.Invoke(context.Background(), &awslambda.InvokeInput{
-		FunctionName:  aws.String("hourstats-sparkline-poster"),
+	_, err = h.lambdaClient.Invoke(ctx, &awslambda.InvokeInput{
+		FunctionName:  aws.String(events.FunctionName(events.SparklinePosterEnv, events.SparklinePosterFunction)),
 		Payload:       payloadBytes,
 		InvocationType: types.InvocationTypeEvent, // Asynchronous invocation
 	})
@@ -528,30 +1821,13 @@ func (h *ProcessorHandler) triggerSparklinePoster(runID string) error {
 }
 
 // storeSentimentData stores sentiment data for sparkline generation
-func (h *ProcessorHandler) storeSentimentData(runID, overallSentiment string, netSentimentPercentage float64, totalPosts int) error {
-	log.Printf("📊 SENTIMENT: Storing sentiment data - RunID: %s, Sentiment: %s, Net: %.1f%%, Posts: %d",
-		runID, overallSentiment, netSentimentPercentage, totalPosts)
-
-	// Convert sentiment category to compound score for storage
-	var averageCompoundScore float64
-	switch overallSentiment {
-	case "positive":
-		averageCompoundScore = 0.5 + (netSentimentPercentage / 200.0) // Scale to 0.5-1.0
-	case "negative":
-		averageCompoundScore = -0.5 - (netSentimentPercentage / 200.0) // Scale to -1.0 to -0.5
-	default: // neutral
-		averageCompoundScore = netSentimentPercentage / 100.0 // Scale to -1.0 to 1.0
-	}
-
-	// Create sentiment data point
-	dataPoint := state.SentimentDataPoint{
-		RunID:                runID,
-		Timestamp:            time.Now(),
-		AverageCompoundScore: averageCompoundScore,
-		NetSentimentPercent:  netSentimentPercentage,
-		SentimentCategory:    overallSentiment,
-		TotalPosts:           totalPosts,
+func (h *ProcessorHandler) storeSentimentData(dataPoint state.SentimentDataPoint) error {
+	if !h.dryRun.Writes() {
+		log.Printf("📊 SENTIMENT: Dry run (%s), not storing sentiment data for run: %s", h.dryRun, dataPoint.RunID)
+		return nil
 	}
+	log.Printf("📊 SENTIMENT: Storing sentiment data - RunID: %s, Sentiment: %s, Net: %.1f%%, Posts: %d",
+		dataPoint.RunID, dataPoint.SentimentCategory, dataPoint.NetSentimentPercent, dataPoint.TotalPosts)
 
 	// Store the data point
 	err := h.sentimentHistoryManager.StoreSentimentData(context.Background(), dataPoint)
@@ -559,16 +1835,106 @@ func (h *ProcessorHandler) storeSentimentData(runID, overallSentiment string, ne
 		return fmt.Errorf("failed to store sentiment data point: %w", err)
 	}
 
-	log.Printf("✅ SENTIMENT: Successfully stored sentiment data for run: %s", runID)
+	log.Printf("✅ SENTIMENT: Successfully stored sentiment data for run: %s", dataPoint.RunID)
 	return nil
 }
 
+// measureToxicity scores the run's posts with the configured toxicity backend and returns
+// the percentage that were toxic, or nil when toxicity is off or couldn't be scored
+func (h *ProcessorHandler) measureToxicity(ctx context.Context, posts []state.Post) *float64 {
+	settings, err := toxicity.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("⚠️ PROCESSOR: Skipping toxicity: %v", err)
+		return nil
+	}
+	if settings == nil {
+		return nil
+	}
+	classifier, err := settings.Classifier(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("⚠️ PROCESSOR: Skipping toxicity: %v", err)
+		return nil
+	}
+
+	texts := make([]string, len(posts))
+	for i, post := range posts {
+		texts[i] = post.Text
+	}
+	scoreCtx, cancel := context.WithTimeout(ctx, toxicityTimeout)
+	defer cancel()
+	result, err := toxicity.Measure(scoreCtx, classifier, texts, settings.Threshold, settings.SampleSize)
+	if err != nil {
+		log.Printf("⚠️ PROCESSOR: Skipping toxicity: %v", err)
+		return nil
+	}
+
+	log.Printf("☣️ PROCESSOR: %.1f%% of %d scored posts were toxic (%s, average score %.2f)",
+		result.Percent, result.Scored, settings.Backend, result.AverageScore)
+	if err := metrics.Emit(map[string]string{"Function": "processor"},
+		metrics.Metric{Name: "ToxicityPercent", Value: result.Percent, Unit: metrics.UnitPercent},
+	); err != nil {
+		log.Printf("Failed to emit toxicity metric: %v", err)
+	}
+	return &result.Percent
+}
+
+// measureComparisonNetworks analyzes each comparison network's posts for the run with the
+// same analyzer and stores its sentiment alongside Bluesky's. Networks with fewer posts than
+// the minimum are skipped; failures are logged and never fail the run.
+func (h *ProcessorHandler) measureComparisonNetworks(ctx context.Context, runState *state.RunState, windowEnd time.Time) {
+	settings, err := comparison.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("⚠️ PROCESSOR: Skipping comparison networks: %v", err)
+		return
+	}
+	if settings == nil {
+		return
+	}
+
+	for _, network := range settings.Networks {
+		networkRunID := comparison.RunID(runState.RunID, network.Name)
+		posts, err := h.stateManager.GetAllPosts(ctx, networkRunID, state.StronglyConsistent)
+		if err != nil {
+			log.Printf("⚠️ PROCESSOR: Failed to get %s posts: %v", network.Label(), err)
+			continue
+		}
+		posts = h.filterPostsByCutoffTime(state.DedupePosts(posts), runState.CutoffTime)
+		if len(posts) < h.config.Settings.MinPostCount {
+			log.Printf("⚠️ PROCESSOR: Only %d %s posts in window (minimum %d), skipping comparison", len(posts), network.Label(), h.config.Settings.MinPostCount)
+			continue
+		}
+
+		// Networks are categorized on their own, without the Bluesky run's previous category
+		analyzed, overallSentiment, netSentimentPercentage, _, err := h.analyzePosts(posts, runState.CutoffTime, windowEnd, "")
+		if err != nil {
+			log.Printf("⚠️ PROCESSOR: Failed to analyze %s posts: %v", network.Label(), err)
+			continue
+		}
+
+		dataPoint := state.NewSentimentDataPoint(networkRunID, overallSentiment, netSentimentPercentage, len(posts), time.Now())
+		dataPoint.Network = network.Name
+		counts := state.CountSentiments(analyzed)
+		dataPoint.Counts = &counts
+		weightedPercentage := state.EngagementWeightedNetSentiment(analyzed)
+		dataPoint.WeightedNetSentimentPercent = &weightedPercentage
+		if err := h.sentimentHistoryManager.StoreSentimentData(ctx, dataPoint); err != nil {
+			log.Printf("⚠️ PROCESSOR: Failed to store %s sentiment: %v", network.Label(), err)
+			continue
+		}
+		log.Printf("🌐 PROCESSOR: %s net sentiment %.1f%% from %d posts", network.Label(), netSentimentPercentage, len(posts))
+	}
+}
+
 func main() {
+	if err := tracing.Init("hourstats-processor"); err != nil {
+		log.Printf("Tracing disabled: %v", err)
+	}
+
 	ctx := context.Background()
 	handler, err := NewProcessorHandler(ctx)
 	if err != nil {
 		log.Fatalf("Failed to create processor handler: %v", err)
 	}
 
-	lambda.Start(handler.HandleRequest)
+	lambda.Start(tracing.Handler(events.Handler(handler.handleAndNotify)))
 }
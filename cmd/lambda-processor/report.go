@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/runreport"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// publishReport writes the run's report to the reports bucket with the run's final state,
+// however the run ended. Failures are logged, never fatal.
+func (h *ProcessorHandler) publishReport(ctx context.Context, runID string) {
+	if h.reportsBucket == "" || h.report == nil {
+		return
+	}
+
+	report := h.report
+	report.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	createdAt := time.Now()
+	run, err := h.stateManager.GetLatestRun(ctx, runID)
+	if err != nil {
+		log.Printf("Failed to get run state for report: %v", err)
+	} else {
+		createdAt = run.CreatedAt
+		windowEnd := run.CutoffTime.Add(time.Duration(run.AnalysisIntervalMinutes) * time.Minute)
+		report.AnalysisIntervalMinutes = run.AnalysisIntervalMinutes
+		report.CutoffTime = run.CutoffTime.UTC().Format(time.RFC3339)
+		report.CurrentTime = windowEnd.UTC().Format(time.RFC3339)
+		report.Status = run.Status
+		report.SkipReason = run.SkipReason
+		report.Error = run.ErrorMessage
+		report.ChannelID = run.ChannelID
+		report.PostURI = run.TopPostURI
+		report.ProcessingStats.BlockedPosts = run.BlockedPosts
+		report.ProcessingStats.ContentWarningPosts = run.ContentWarningPosts
+	}
+
+	key := runreport.Key(runID, createdAt)
+	if err := runreport.Publish(ctx, h.s3Client, h.reportsBucket, key, report); err != nil {
+		log.Printf("Failed to publish run report: %v", err)
+		return
+	}
+	log.Printf("📄 PROCESSOR: Wrote run report to s3://%s/%s", h.reportsBucket, key)
+}
+
+// reportPostTimes lists the posts' URIs and creation times for the report's time distribution
+func reportPostTimes(posts []state.Post) []runreport.PostTime {
+	times := make([]runreport.PostTime, len(posts))
+	for i, post := range posts {
+		times[i] = runreport.PostTime{URI: post.URI, CreatedAt: post.CreatedAt}
+	}
+	return times
+}
+
+// reportSentiment summarizes the analyzed posts' sentiment for the report
+func reportSentiment(posts []state.Post, overallSentiment string, netSentimentPercentage float64) runreport.SentimentAnalysis {
+	counts := state.CountSentiments(posts)
+	return runreport.SentimentAnalysis{
+		OverallSentiment:     overallSentiment,
+		NetSentimentPercent:  netSentimentPercentage,
+		AverageCompoundScore: netSentimentPercentage / 100,
+		PositiveCount:        counts.Positive,
+		NeutralCount:         counts.Neutral,
+		NegativeCount:        counts.Negative,
+	}
+}
+
+// reportSamplePosts lists the top posts for the report
+func reportSamplePosts(posts []state.Post) []runreport.SamplePost {
+	samples := make([]runreport.SamplePost, len(posts))
+	for i, post := range posts {
+		samples[i] = runreport.SamplePost{
+			Author:          post.Author,
+			URI:             post.URI,
+			CreatedAt:       post.CreatedAt,
+			Likes:           post.Likes,
+			Reposts:         post.Reposts,
+			Replies:         post.Replies,
+			EngagementScore: post.EngagementScore,
+			Sentiment:       post.Sentiment,
+			TextPreview:     runreport.TextPreview(post.Text),
+		}
+	}
+	return samples
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
+	"github.com/christophergentle/hourstats-bsky/internal/events"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
+)
+
+// reprocess re-runs analysis and aggregation on a run's stored posts with the event's
+// parameters and stores the result under its own step. Nothing is posted, and the run's
+// own results, history point and analyzed posts are left as they were.
+func (h *ProcessorHandler) reprocess(ctx context.Context, event events.ProcessorEvent) (Response, error) {
+	runState, err := h.stateManager.GetRun(ctx, event.RunID, "orchestrator", state.StronglyConsistent)
+	if err != nil {
+		log.Printf("Failed to get run state: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to get run state: " + err.Error(),
+		}, err
+	}
+
+	parameters := h.reprocessParameters(ctx, runState, event.Reprocess)
+	log.Printf("🧪 PROCESSOR: Reprocessing run %s as %q with %+v", event.RunID, event.Reprocess.Label, parameters)
+
+	allPosts, err := h.stateManager.GetAllPosts(ctx, event.RunID, state.StronglyConsistent)
+	if err != nil {
+		log.Printf("Failed to get all posts: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to get posts: " + err.Error(),
+		}, err
+	}
+	posts := runState.WindowPosts(allPosts)
+	// The current blocklist applies, so a new entry's effect can be checked on past runs
+	posts, _ = h.loadBlocklist(ctx).Filter(posts)
+
+	result := &state.ReprocessResult{
+		Label:      event.Reprocess.Label,
+		Parameters: parameters,
+		TotalPosts: len(posts),
+	}
+	// Too few posts is a result too: the run would have been skipped with these parameters
+	if len(posts) == 0 || len(posts) < parameters.MinPostCount {
+		result.SkipReason = fmt.Sprintf("only %d posts in window (minimum %d)", len(posts), parameters.MinPostCount)
+		return h.storeReprocessResult(ctx, runState, result)
+	}
+
+	if parameters.Reanalyze {
+		for i := range posts {
+			posts[i].Analyzed = false
+		}
+	}
+
+	h.sentimentMethod = parameters.SentimentMethod
+	// A reprocessed run is categorized on its own, with the event's threshold
+	h.sentimentThresholds = aggregation.Thresholds{Threshold: parameters.SentimentThreshold}
+	h.sentimentAnalyzer.SetWorkers(h.getAnalysisConcurrency(ctx))
+	windowEnd := runState.CutoffTime.Add(time.Duration(runState.AnalysisIntervalMinutes) * time.Minute)
+	_, analyzeSpan := tracing.Start(ctx, "analyze")
+	analyzedPosts, _, netSentimentPercentage, _, err := h.analyzePosts(posts, runState.CutoffTime, windowEnd, "")
+	tracing.End(analyzeSpan, err)
+	if err != nil {
+		log.Printf("Failed to analyze posts: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to analyze posts: " + err.Error(),
+		}, err
+	}
+
+	result.OverallSentiment = aggregation.Categorize(netSentimentPercentage/100, parameters.SentimentThreshold)
+	result.NetSentimentPercentage = netSentimentPercentage
+	result.TopPosts = rankTopPosts(rankingCandidates(analyzedPosts, parameters.ReplyRanking, parameters.ContentWarnings), parameters.TopPosts, parameters.MediaRanking, parameters.TopPostsRanking)
+	result.Topics = state.CalculateTopicSentiment(analyzedPosts, maxRunTopics, parameters.MinTopicPosts)
+	if emotions, emotionalPosts := state.CalculateEmotions(analyzedPosts); emotionalPosts > 0 {
+		result.Emotions = &emotions
+	}
+
+	log.Printf("🧪 PROCESSOR: Reprocessed run %s as %q: %s (%.1f%%, originally %s %.1f%%)",
+		event.RunID, result.Label, result.OverallSentiment, result.NetSentimentPercentage,
+		runState.OverallSentiment, runState.NetSentimentPercentage)
+	return h.storeReprocessResult(ctx, runState, result)
+}
+
+// reprocessParameters fills in the parameters the event leaves unset with the ones a
+// scheduled run would use
+func (h *ProcessorHandler) reprocessParameters(ctx context.Context, runState *state.RunState, overrides *events.Reprocess) state.ReprocessParameters {
+	parameters := state.ReprocessParameters{
+		TopPosts:           overrides.TopPosts,
+		MediaRanking:       overrides.MediaRanking,
+		ReplyRanking:       overrides.ReplyRanking,
+		ContentWarnings:    overrides.ContentWarnings,
+		TopPostsRanking:    overrides.TopPostsRanking,
+		SentimentThreshold: overrides.SentimentThreshold,
+		MinPostCount:       overrides.MinPostCount,
+		MinTopicPosts:      overrides.MinTopicPosts,
+		Reanalyze:          overrides.Reanalyze,
+	}
+	if parameters.TopPosts == 0 {
+		parameters.TopPosts = defaultTopPosts
+	}
+	if parameters.MediaRanking == "" {
+		parameters.MediaRanking = h.getMediaRanking(ctx)
+	}
+	if parameters.ReplyRanking == "" {
+		parameters.ReplyRanking = h.getReplyRanking(ctx)
+	}
+	if parameters.ContentWarnings == "" {
+		parameters.ContentWarnings = h.getContentWarnings(ctx)
+	}
+	if parameters.TopPostsRanking == "" {
+		parameters.TopPostsRanking = h.getTopPostsRanking(ctx)
+	}
+	if parameters.SentimentThreshold == 0 {
+		parameters.SentimentThreshold = aggregation.DefaultThreshold
+	}
+	if parameters.MinPostCount == 0 {
+		parameters.MinPostCount = h.config.Settings.MinPostCount
+	}
+	if parameters.MinTopicPosts == 0 {
+		parameters.MinTopicPosts = minTopicPosts
+	}
+	// Unlike the other parameters, the method defaults to the one the run recorded, so a
+	// result differs from the original only by what was overridden
+	switch {
+	case overrides.SentimentMethod != "":
+		parameters.SentimentMethod = aggregation.Method{Name: overrides.SentimentMethod}
+		if overrides.SentimentMethod == aggregation.MethodTrimmedMean {
+			parameters.SentimentMethod.Trim = overrides.Trim
+			if parameters.SentimentMethod.Trim == 0 {
+				parameters.SentimentMethod.Trim = aggregation.DefaultTrim
+			}
+		}
+	case runState.SentimentMethod != nil:
+		parameters.SentimentMethod = *runState.SentimentMethod
+	default:
+		parameters.SentimentMethod = aggregation.DefaultMethod
+	}
+	return parameters
+}
+
+func (h *ProcessorHandler) storeReprocessResult(ctx context.Context, runState *state.RunState, result *state.ReprocessResult) (Response, error) {
+	if err := h.stateManager.StoreReprocessResult(ctx, runState, result); err != nil {
+		log.Printf("Failed to store reprocess result: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to store reprocess result: " + err.Error(),
+		}, err
+	}
+
+	body := "Reprocessed as " + result.Label
+	if result.SkipReason != "" {
+		body += " - would be skipped: " + result.SkipReason
+	}
+	return Response{
+		StatusCode:       200,
+		Body:             body,
+		PostsAnalyzed:    result.TotalPosts,
+		TopPostsCount:    len(result.TopPosts),
+		OverallSentiment: result.OverallSentiment,
+	}, nil
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/credentials"
+	"github.com/christophergentle/hourstats-bsky/internal/metrics"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
+)
+
+// defaultLookbackDays is how far back each run re-measures posts; engagement on a post has
+// mostly settled after a week, so each post's stored stats stop changing after that
+const defaultLookbackDays = 7
+
+// Event represents the EventBridge event structure
+type Event struct {
+	Source string `json:"source"`
+	Time   string `json:"time"`
+	Days   int    `json:"days,omitempty"` // For manual backfills further back than a week
+}
+
+// Response represents the Lambda response
+type Response struct {
+	StatusCode    int    `json:"statusCode"`
+	Body          string `json:"body"`
+	PostsMeasured int    `json:"postsMeasured"`
+}
+
+// SelfStatsHandler measures the engagement on the bot's own recent posts
+type SelfStatsHandler struct {
+	selfStatsManager *state.SelfStatsManager
+	credentials      credentials.Provider
+	newBlueskyClient client.Factory
+}
+
+// NewSelfStatsHandler creates a new self stats handler
+func NewSelfStatsHandler(ctx context.Context) (*SelfStatsHandler, error) {
+	selfStatsManager, err := state.NewSelfStatsManager(ctx, "hourstats-self-stats")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create self stats manager: %w", err)
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, tracing.WithAWS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return &SelfStatsHandler{
+		selfStatsManager: selfStatsManager,
+		credentials:      credentials.Default(cfg, ssm.NewFromConfig(cfg)),
+		newBlueskyClient: client.NewClient,
+	}, nil
+}
+
+// HandleRequest is the main Lambda handler
+func (h *SelfStatsHandler) HandleRequest(ctx context.Context, event Event) (Response, error) {
+	log.Printf("Self stats received event: %+v", event)
+
+	days := event.Days
+	if days <= 0 {
+		days = defaultLookbackDays
+	}
+	measuredAt := time.Now().UTC()
+	since := measuredAt.AddDate(0, 0, -days)
+
+	handle, password, err := h.getBlueskyCredentials(ctx)
+	if err != nil {
+		log.Printf("Failed to get Bluesky credentials: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to get credentials: " + err.Error(),
+		}, err
+	}
+
+	blueskyClient := h.newBlueskyClient(handle, password)
+	if err := blueskyClient.Authenticate(); err != nil {
+		log.Printf("Failed to authenticate with Bluesky: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to authenticate: " + err.Error(),
+		}, err
+	}
+
+	posts, err := blueskyClient.GetOwnPosts(ctx, since)
+	if err != nil {
+		log.Printf("Failed to get own posts: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to get own posts: " + err.Error(),
+		}, err
+	}
+
+	stats := make([]state.PostStats, 0, len(posts))
+	for _, post := range posts {
+		postStats := state.PostStats{
+			URI:        post.URI,
+			Kind:       post.Kind,
+			PostedAt:   post.CreatedAt,
+			Likes:      post.Engagement.Likes,
+			Reposts:    post.Engagement.Reposts,
+			Replies:    post.Engagement.Replies,
+			Quotes:     post.Engagement.Quotes,
+			MeasuredAt: measuredAt,
+		}
+		if err := h.selfStatsManager.StorePostStats(ctx, postStats); err != nil {
+			log.Printf("Failed to store post stats: %v", err)
+			return Response{
+				StatusCode:    500,
+				Body:          "Failed to store post stats: " + err.Error(),
+				PostsMeasured: len(stats),
+			}, err
+		}
+		stats = append(stats, postStats)
+	}
+
+	h.emitMetrics(stats)
+
+	log.Printf("📊 Measured engagement on %d own posts from the last %d days", len(stats), days)
+	return Response{
+		StatusCode:    200,
+		Body:          fmt.Sprintf("Measured %d posts from the last %d days", len(stats), days),
+		PostsMeasured: len(stats),
+	}, nil
+}
+
+// emitMetrics reports the mean engagement per post of each kind of post measured
+func (h *SelfStatsHandler) emitMetrics(stats []state.PostStats) {
+	type kindTotals struct{ posts, engagement int }
+	totals := make(map[string]*kindTotals)
+	for _, post := range stats {
+		if totals[post.Kind] == nil {
+			totals[post.Kind] = &kindTotals{}
+		}
+		totals[post.Kind].posts++
+		totals[post.Kind].engagement += post.Total()
+	}
+
+	for kind, t := range totals {
+		average := float64(t.engagement) / float64(t.posts)
+		log.Printf("📊   %-6s %3d posts, %.1f engagement per post", kind, t.posts, average)
+		if err := metrics.Emit(map[string]string{"Kind": kind},
+			metrics.Metric{Name: "OwnPosts", Value: float64(t.posts), Unit: metrics.UnitCount},
+			metrics.Metric{Name: "OwnPostEngagement", Value: average, Unit: metrics.UnitCount},
+		); err != nil {
+			log.Printf("Failed to emit self stats metrics: %v", err)
+		}
+	}
+}
+
+// getBlueskyCredentials retrieves credentials from the Secrets Manager secret or SSM
+func (h *SelfStatsHandler) getBlueskyCredentials(ctx context.Context) (string, string, error) {
+	creds, err := h.credentials.Retrieve(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	return creds.Handle, creds.Password, nil
+}
+
+func main() {
+	if err := tracing.Init("hourstats-selfstats"); err != nil {
+		log.Printf("Tracing disabled: %v", err)
+	}
+
+	ctx := context.Background()
+	handler, err := NewSelfStatsHandler(ctx)
+	if err != nil {
+		log.Fatalf("Failed to create self stats handler: %v", err)
+	}
+
+	lambda.Start(tracing.Handler(handler.HandleRequest))
+}
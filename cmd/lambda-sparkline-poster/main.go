@@ -4,23 +4,52 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
+	"github.com/christophergentle/hourstats-bsky/internal/alttext"
 	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/comparison"
+	appconfig "github.com/christophergentle/hourstats-bsky/internal/config"
+	"github.com/christophergentle/hourstats-bsky/internal/credentials"
+	"github.com/christophergentle/hourstats-bsky/internal/dryrun"
+	"github.com/christophergentle/hourstats-bsky/internal/events"
+	"github.com/christophergentle/hourstats-bsky/internal/formatter"
+	"github.com/christophergentle/hourstats-bsky/internal/interaction"
+	"github.com/christophergentle/hourstats-bsky/internal/pin"
+	"github.com/christophergentle/hourstats-bsky/internal/schedule"
 	"github.com/christophergentle/hourstats-bsky/internal/sparkline"
 	"github.com/christophergentle/hourstats-bsky/internal/state"
+	"github.com/christophergentle/hourstats-bsky/internal/toxicity"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
 )
 
-// StepFunctionsEvent represents the event from Step Functions
-type StepFunctionsEvent struct {
-	RunID                   string `json:"runId"`
-	AnalysisIntervalMinutes int    `json:"analysisIntervalMinutes"`
-	Status                  string `json:"status"`
-}
+// dataTableReplyParameter enables replying to charts with their values as text
+const dataTableReplyParameter = "/hourstats/settings/data_table_reply"
+
+// emotionChartParameter swaps the weekly chart for the stacked emotion breakdown when "true"
+const emotionChartParameter = "/hourstats/settings/emotion_chart"
+
+// ratioChartParameter swaps the weekly chart for the stacked split of positive, neutral and
+// negative posts when "true"
+const ratioChartParameter = "/hourstats/settings/ratio_chart"
+
+// volatilityOverlayParameter shades each run's 24-hour volatility around the weekly
+// sentiment line when "true"
+const volatilityOverlayParameter = "/hourstats/settings/volatility_overlay"
+
+// interpolateGapsParameter bridges gaps in the weekly sentiment chart with dashed,
+// interpolated points when "true"
+const interpolateGapsParameter = "/hourstats/settings/interpolate_gaps"
+
+// imageQualityParameter optionally holds a JSON client.ImageConfig for chart uploads
+const imageQualityParameter = "/hourstats/settings/image_quality"
 
 // Response represents the Lambda response
 type Response struct {
@@ -35,6 +64,11 @@ type SparklinePosterHandler struct {
 	sparklineGenerator      *sparkline.SparklineGenerator
 	stateManager            *state.StateManager
 	ssmClient               *ssm.Client
+	credentials             credentials.Provider
+	newBlueskyClient        client.Factory
+
+	// dryRun is the current invocation's dry run level, read at the start of each invocation
+	dryRun dryrun.Level
 }
 
 // NewSparklinePosterHandler creates a new sparkline poster handler
@@ -49,13 +83,13 @@ func NewSparklinePosterHandler(ctx context.Context) (*SparklinePosterHandler, er
 	sparklineGenerator := sparkline.NewSparklineGenerator(nil) // Use default config
 
 	// Initialize state manager
-	stateManager, err := state.NewStateManager(ctx, "hourstats-state")
+	stateManager, err := state.NewStateManager(ctx, appconfig.TablesFromEnv().State)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create state manager: %w", err)
 	}
 
 	// Initialize AWS clients
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := config.LoadDefaultConfig(ctx, tracing.WithAWS)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
@@ -67,28 +101,48 @@ func NewSparklinePosterHandler(ctx context.Context) (*SparklinePosterHandler, er
 		sparklineGenerator:      sparklineGenerator,
 		stateManager:            stateManager,
 		ssmClient:               ssmClient,
+		credentials:             credentials.Default(cfg, ssmClient),
+		newBlueskyClient:        client.NewClient,
 	}, nil
 }
 
 // HandleRequest is the main Lambda handler
-func (h *SparklinePosterHandler) HandleRequest(ctx context.Context, event StepFunctionsEvent) (Response, error) {
+func (h *SparklinePosterHandler) HandleRequest(ctx context.Context, event events.RunEvent) (Response, error) {
 	log.Printf("Sparkline poster received event: %+v", event)
+	tracing.SetRunID(ctx, event.RunID)
 
-	// Check if dry run mode is enabled
-	dryRun, err := h.isDryRunMode(ctx)
+	// Check the dry run level; a shadow dry run posts from the shadow account
+	level, err := dryrun.Load(ctx, h.ssmClient)
 	if err != nil {
-		log.Printf("Failed to check dry run mode: %v", err)
+		log.Printf("Failed to check dry run level: %v", err)
 		return Response{
 			StatusCode: 500,
-			Body:       "Failed to check dry run mode: " + err.Error(),
+			Body:       "Failed to check dry run level: " + err.Error(),
 		}, err
 	}
 
-	if dryRun {
-		log.Printf("Dry run mode enabled, skipping sparkline post for run: %s", event.RunID)
+	h.dryRun = level
+
+	if !level.Posts() {
+		log.Printf("Dry run (%s), skipping sparkline post for run: %s", level, event.RunID)
 		return Response{
 			StatusCode: 200,
-			Body:       "Dry run mode - sparkline post skipped",
+			Body:       "Dry run (" + level.String() + ") - sparkline post skipped",
+			Posted:     false,
+		}, nil
+	}
+
+	// Respect the posting schedule, recording the skip against the run
+	if rules, err := schedule.Load(ctx, h.ssmClient); err != nil {
+		log.Printf("Ignoring posting schedule: %v", err)
+	} else if allowed, reason := rules.Allows(schedule.PosterSparkline, time.Now()); !allowed {
+		log.Printf("🔕 Posting schedule withheld sparkline for run %s: %s", event.RunID, reason)
+		if err := h.stateManager.RecordSkippedPost(ctx, event.RunID, schedule.PosterSparkline, reason); err != nil {
+			log.Printf("Failed to record skipped post: %v", err)
+		}
+		return Response{
+			StatusCode: 200,
+			Body:       "Posting schedule - sparkline post skipped: " + reason,
 			Posted:     false,
 		}, nil
 	}
@@ -110,8 +164,43 @@ func (h *SparklinePosterHandler) HandleRequest(ctx context.Context, event StepFu
 		return h.postInsufficientDataMessage(ctx, len(dataPoints))
 	}
 
-	// Generate sparkline image
-	imageData, err := h.sparklineGenerator.GenerateSentimentSparkline(dataPoints)
+	// Chart and describe the aggregation the settings ask for: engagement-weighted sentiment
+	// in place of per-post sentiment, or the two side by side
+	mode := h.sentimentAggregation(ctx)
+	if mode == aggregation.ModeEngagement {
+		if weighted := state.EngagementWeightedPoints(dataPoints); len(weighted) >= 2 {
+			dataPoints = weighted
+		} else {
+			log.Printf("Only %d runs recorded engagement-weighted sentiment, charting per-post sentiment", len(weighted))
+			mode = aggregation.ModePost
+		}
+	}
+
+	// Generate sparkline image, comparing networks when the combined chart is enabled, or
+	// else charting toxicity beside sentiment, emotions, or sentiment ratios, when those are
+	series := h.comparisonSeries(ctx, dataPoints, mode)
+	chartToxicity := series == nil && h.isToxicityChartEnabled(ctx, dataPoints)
+	chartEmotions := series == nil && !chartToxicity && h.isEmotionChartEnabled(ctx, dataPoints)
+	chartRatios := series == nil && !chartToxicity && !chartEmotions && h.isRatioChartEnabled(ctx, dataPoints)
+	chartWeighting := series == nil && !chartToxicity && !chartEmotions && !chartRatios &&
+		mode == aggregation.ModeBoth && len(state.EngagementWeightedPoints(dataPoints)) >= 2
+	var imageData []byte
+	switch {
+	case series != nil:
+		imageData, err = h.sparklineGenerator.GenerateComparisonSparkline(series)
+	case chartToxicity:
+		imageData, err = h.sparklineGenerator.GenerateToxicitySparkline(dataPoints)
+	case chartEmotions:
+		imageData, err = h.sparklineGenerator.GenerateEmotionSparkline(dataPoints)
+	case chartRatios:
+		imageData, err = h.sparklineGenerator.GenerateRatioSparkline(dataPoints)
+	case chartWeighting:
+		imageData, err = h.sparklineGenerator.GenerateWeightingSparkline(dataPoints)
+	case h.isVolatilityOverlayEnabled(ctx):
+		imageData, err = h.sparklineGenerator.WithVolatilityBand().GenerateSentimentSparkline(h.chartPoints(ctx, dataPoints))
+	default:
+		imageData, err = h.sparklineGenerator.GenerateSentimentSparkline(h.chartPoints(ctx, dataPoints))
+	}
 	if err != nil {
 		log.Printf("Failed to generate sparkline: %v", err)
 		return Response{
@@ -120,6 +209,13 @@ func (h *SparklinePosterHandler) HandleRequest(ctx context.Context, event StepFu
 		}, err
 	}
 
+	// Fit the chart into the configured upload budget
+	if prepared, err := client.PrepareImage(imageData, h.getImageConfig(ctx)); err != nil {
+		log.Printf("Failed to prepare sparkline image, uploading as generated: %v", err)
+	} else {
+		imageData = prepared
+	}
+
 	// Get Bluesky credentials
 	handle, password, err := h.getBlueskyCredentials(ctx)
 	if err != nil {
@@ -131,7 +227,7 @@ func (h *SparklinePosterHandler) HandleRequest(ctx context.Context, event StepFu
 	}
 
 	// Create Bluesky client
-	blueskyClient := client.New(handle, password)
+	blueskyClient := h.newBlueskyClient(handle, password)
 	if err := blueskyClient.Authenticate(); err != nil {
 		log.Printf("Failed to authenticate with Bluesky: %v", err)
 		return Response{
@@ -143,11 +239,61 @@ func (h *SparklinePosterHandler) HandleRequest(ctx context.Context, event StepFu
 	// Analyze sentiment extremes
 	extremeMessage := h.analyzeSentimentExtremes(dataPoints)
 
-	// Generate comprehensive alt text
-	altText := h.generateDetailedAltText(dataPoints)
+	// Generate comprehensive alt text, describing the emotion or ratio chart first when
+	// one is drawn
+	altTexts := h.altTextBuilder(ctx)
+	var altParts []string
+	if chartEmotions {
+		altParts = append(altParts, emotionAltText(dataPoints))
+	}
+	if chartRatios {
+		altParts = append(altParts, ratioAltText(dataPoints))
+	}
+	altParts = append(altParts, h.generateDetailedAltText(altTexts, dataPoints))
+	if mode == aggregation.ModeEngagement {
+		altParts = append(altParts, "Each run's sentiment weights its posts by their likes, reposts and replies.")
+	}
+	if chartWeighting {
+		altParts = append(altParts, weightingAltText(dataPoints))
+	}
+	if series != nil {
+		altParts = append(altParts, comparisonAltText(series))
+	}
+	if chartToxicity {
+		altParts = append(altParts, toxicityAltText(dataPoints))
+	}
+	altText := altTexts.Join(altParts...)
+
+	// Optionally follow the chart with its values as text for screen-reader users
+	var dataTable []string
+	if h.isDataTableReplyEnabled(ctx) {
+		dataTable = formatter.FormatDataTable("📋 Chart data, daily average net sentiment (UTC):", dailyTableRows(dataPoints))
+	}
 
 	// Post sparkline with embedded image to Bluesky
 	postText := "📊 Seven day Bluesky sentiment"
+	if series != nil {
+		labels := make([]string, len(series))
+		for i, s := range series {
+			labels[i] = s.Label
+		}
+		postText = "📊 Seven day sentiment: " + strings.Join(labels, " vs ")
+	}
+	if chartToxicity {
+		postText = "📊 Seven day Bluesky sentiment and toxicity"
+	}
+	if chartEmotions {
+		postText = "📊 Seven day Bluesky emotions"
+	}
+	if chartRatios {
+		postText = "📊 Seven day Bluesky positive, neutral and negative posts"
+	}
+	if mode == aggregation.ModeEngagement && !chartEmotions && !chartRatios {
+		postText += ", weighted by engagement"
+	}
+	if chartWeighting {
+		postText = "📊 Seven day Bluesky sentiment, per post and weighted by engagement"
+	}
 	if extremeMessage != "" {
 		postText += "\n\n" + extremeMessage
 	}
@@ -157,20 +303,23 @@ func (h *SparklinePosterHandler) HandleRequest(ctx context.Context, event StepFu
 	if err != nil {
 		log.Printf("Failed to get run state for top post URI: %v", err)
 		// Fall back to standalone posting if we can't get the top post URI
-		return h.postStandaloneSparkline(ctx, blueskyClient, postText, imageData, altText)
+		return h.postStandaloneSparkline(ctx, blueskyClient, postText, imageData, altText, dataTable)
 	}
 
 	// Check if we have a top post URI to reply to
 	if runState.TopPostURI != "" && runState.TopPostCID != "" {
 		log.Printf("Posting sparkline as reply to top post: %s", runState.TopPostURI)
-		if err := blueskyClient.PostWithImageAsReply(ctx, postText, imageData, altText, runState.TopPostURI, runState.TopPostCID); err != nil {
+		chartURI, chartCID, err := blueskyClient.PostWithImageAsReply(ctx, postText, imageData, altText, runState.TopPostURI, runState.TopPostCID)
+		if err != nil {
 			log.Printf("Failed to post sparkline as reply: %v", err)
 			// Fall back to standalone posting
-			return h.postStandaloneSparkline(ctx, blueskyClient, postText, imageData, altText)
+			return h.postStandaloneSparkline(ctx, blueskyClient, postText, imageData, altText, dataTable)
 		}
+		postDataTable(ctx, blueskyClient, runState.TopPostURI, runState.TopPostCID, chartURI, chartCID, dataTable)
+		h.rotatePin(ctx, blueskyClient, chartURI, chartCID)
 	} else {
 		log.Printf("No top post URI available, posting sparkline standalone")
-		return h.postStandaloneSparkline(ctx, blueskyClient, postText, imageData, altText)
+		return h.postStandaloneSparkline(ctx, blueskyClient, postText, imageData, altText, dataTable)
 	}
 
 	log.Printf("Successfully posted sparkline for run: %s", event.RunID)
@@ -181,50 +330,14 @@ func (h *SparklinePosterHandler) HandleRequest(ctx context.Context, event StepFu
 	}, nil
 }
 
-// isDryRunMode checks if dry run mode is enabled
-func (h *SparklinePosterHandler) isDryRunMode(ctx context.Context) (bool, error) {
-	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
-		Name:           aws.String("/hourstats/settings/dry_run"),
-		WithDecryption: aws.Bool(false),
-	})
-	if err != nil {
-		return false, fmt.Errorf("failed to get dry run parameter: %w", err)
-	}
-
-	return *result.Parameter.Value == "true", nil
-}
-
-// getBlueskyCredentials retrieves credentials from SSM
+// getBlueskyCredentials retrieves credentials from the Secrets Manager secret or SSM, or
+// the shadow account's during a shadow dry run
 func (h *SparklinePosterHandler) getBlueskyCredentials(ctx context.Context) (string, string, error) {
-	parameterNames := []string{
-		"/hourstats/bluesky/handle",
-		"/hourstats/bluesky/password",
-	}
-
-	result, err := h.ssmClient.GetParameters(ctx, &ssm.GetParametersInput{
-		Names:          parameterNames,
-		WithDecryption: aws.Bool(true),
-	})
+	creds, err := h.dryRun.Credentials(h.credentials, h.ssmClient).Retrieve(ctx)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to get parameters: %w", err)
-	}
-
-	params := make(map[string]string)
-	for _, p := range result.Parameters {
-		params[*p.Name] = *p.Value
-	}
-
-	handle, ok := params["/hourstats/bluesky/handle"]
-	if !ok {
-		return "", "", fmt.Errorf("handle parameter not found")
+		return "", "", err
 	}
-
-	password, ok := params["/hourstats/bluesky/password"]
-	if !ok {
-		return "", "", fmt.Errorf("password parameter not found")
-	}
-
-	return handle, password, nil
+	return creds.Handle, creds.Password, nil
 }
 
 // analyzeSentimentExtremes checks if the latest sentiment is the highest or lowest for the week
@@ -263,47 +376,39 @@ func (h *SparklinePosterHandler) analyzeSentimentExtremes(dataPoints []state.Sen
 }
 
 // generateDetailedAltText creates comprehensive alt text for the sparkline chart
-func (h *SparklinePosterHandler) generateDetailedAltText(dataPoints []state.SentimentDataPoint) string {
+func (h *SparklinePosterHandler) generateDetailedAltText(altTexts *alttext.Builder, dataPoints []state.SentimentDataPoint) string {
 	if len(dataPoints) < 2 {
-		return "Seven day sentiment trend chart showing community mood over time"
+		return altTexts.Weekly(nil)
 	}
 
-	// Calculate statistics
 	stats := h.calculateSentimentStats(dataPoints)
+	return altTexts.Weekly(&alttext.Stats{
+		Current:     stats.Current,
+		CurrentTime: stats.CurrentTime,
+		Highest:     stats.Highest,
+		HighestTime: stats.HighestTime,
+		Lowest:      stats.Lowest,
+		LowestTime:  stats.LowestTime,
+		Average:     stats.Average,
+		Trend:       stats.Trend,
+	})
+}
 
-	// Format timestamps for readability (UTC)
-	formatTime := func(t time.Time) string {
-		return t.UTC().Format("Jan 2, 3:04 PM UTC")
+// altTextBuilder loads the alt text templates, falling back to the defaults
+func (h *SparklinePosterHandler) altTextBuilder(ctx context.Context) *alttext.Builder {
+	if h.ssmClient == nil {
+		return alttext.Default()
 	}
-
-	// Build comprehensive alt text
-	altText := "Seven day Bluesky sentiment trend chart. "
-
-	// Add current sentiment
-	altText += fmt.Sprintf("Current sentiment: %.1f%% (%s). ",
-		stats.Current, formatTime(stats.CurrentTime))
-
-	// Add highest sentiment
-	altText += fmt.Sprintf("Highest sentiment: %.1f%% (%s). ",
-		stats.Highest, formatTime(stats.HighestTime))
-
-	// Add lowest sentiment
-	altText += fmt.Sprintf("Lowest sentiment: %.1f%% (%s). ",
-		stats.Lowest, formatTime(stats.LowestTime))
-
-	// Add average sentiment
-	altText += fmt.Sprintf("Average sentiment: %.1f%%. ", stats.Average)
-
-	// Add trend information
-	if stats.Trend > 0 {
-		altText += "Trending positive over the period."
-	} else if stats.Trend < 0 {
-		altText += "Trending negative over the period."
-	} else {
-		altText += "Stable sentiment over the period."
+	settings, err := alttext.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("Failed to load alt text settings, using the defaults: %v", err)
 	}
-
-	return altText
+	builder, err := alttext.New(settings)
+	if err != nil {
+		log.Printf("Invalid alt text settings, using the defaults: %v", err)
+		return alttext.Default()
+	}
+	return builder
 }
 
 // SentimentStats holds calculated sentiment statistics
@@ -384,7 +489,7 @@ func (h *SparklinePosterHandler) postInsufficientDataMessage(ctx context.Context
 	}
 
 	// Create Bluesky client
-	blueskyClient := client.New(handle, password)
+	blueskyClient := h.newBlueskyClient(handle, password)
 	if err := blueskyClient.Authenticate(); err != nil {
 		log.Printf("Failed to authenticate with Bluesky: %v", err)
 		return Response{
@@ -425,8 +530,8 @@ func (h *SparklinePosterHandler) postInsufficientDataMessage(ctx context.Context
 }
 
 // postStandaloneSparkline posts the sparkline as a standalone post (fallback when reply fails)
-func (h *SparklinePosterHandler) postStandaloneSparkline(ctx context.Context, blueskyClient *client.BlueskyClient, postText string, imageData []byte, altText string) (Response, error) {
-	_, _, err := blueskyClient.PostWithImage(ctx, postText, imageData, altText)
+func (h *SparklinePosterHandler) postStandaloneSparkline(ctx context.Context, blueskyClient client.BskyPoster, postText string, imageData []byte, altText string, dataTable []string) (Response, error) {
+	chartURI, chartCID, err := blueskyClient.PostWithImage(ctx, postText, imageData, altText)
 	if err != nil {
 		log.Printf("Failed to post sparkline with embedded image: %v", err)
 		return Response{
@@ -436,6 +541,9 @@ func (h *SparklinePosterHandler) postStandaloneSparkline(ctx context.Context, bl
 	}
 
 	log.Printf("Successfully posted sparkline as standalone post")
+	h.applyInteractionSettings(ctx, blueskyClient, chartURI)
+	postDataTable(ctx, blueskyClient, chartURI, chartCID, chartURI, chartCID, dataTable)
+	h.rotatePin(ctx, blueskyClient, chartURI, chartCID)
 	return Response{
 		StatusCode: 200,
 		Body:       "Sparkline posted successfully (standalone)",
@@ -443,12 +551,346 @@ func (h *SparklinePosterHandler) postStandaloneSparkline(ctx context.Context, bl
 	}, nil
 }
 
+// comparisonSeries returns Bluesky and each configured network as chart series, or nil
+// when the combined chart is off or no other network has enough data to draw yet
+func (h *SparklinePosterHandler) comparisonSeries(ctx context.Context, dataPoints []state.SentimentDataPoint, mode aggregation.Mode) []sparkline.Series {
+	if h.ssmClient == nil {
+		return nil
+	}
+	settings, err := comparison.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("Failed to load comparison settings, posting the Bluesky chart: %v", err)
+		return nil
+	}
+	if settings == nil || !settings.CombinedChart {
+		return nil
+	}
+
+	series := []sparkline.Series{{Label: "Bluesky", Color: sparkline.ComparisonColors[0], Points: dataPoints}}
+	drawable := false
+	for i, network := range settings.Networks {
+		points, err := h.sentimentHistoryManager.GetNetworkSentimentHistory(ctx, network.Name, 7*24*time.Hour)
+		if err != nil {
+			log.Printf("Failed to get %s sentiment history: %v", network.Name, err)
+			continue
+		}
+		// Bluesky's points are already weighted, so the networks' must be too
+		if mode == aggregation.ModeEngagement {
+			points = state.EngagementWeightedPoints(points)
+		}
+		series = append(series, sparkline.Series{
+			Label:  network.Label(),
+			Color:  sparkline.ComparisonColors[(i+1)%len(sparkline.ComparisonColors)],
+			Points: points,
+		})
+		drawable = drawable || len(points) >= 2
+	}
+	if !drawable {
+		log.Printf("No comparison network has enough data yet, posting the Bluesky chart")
+		return nil
+	}
+	return series
+}
+
+// comparisonAltText describes each network's latest value on a comparison chart
+func comparisonAltText(series []sparkline.Series) string {
+	parts := make([]string, 0, len(series))
+	for _, s := range series {
+		if len(s.Points) == 0 {
+			parts = append(parts, s.Label+" has no data yet")
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s is at %.1f%%", s.Label, s.Points[len(s.Points)-1].NetSentimentPercent))
+	}
+	return "The chart also compares networks: " + strings.Join(parts, ", ") + "."
+}
+
+// isToxicityChartEnabled reports whether the toxicity settings ask for the chart and the
+// history has enough toxicity measurements to draw it
+func (h *SparklinePosterHandler) isToxicityChartEnabled(ctx context.Context, dataPoints []state.SentimentDataPoint) bool {
+	if h.ssmClient == nil {
+		return false
+	}
+	settings, err := toxicity.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("Failed to load toxicity settings, posting the sentiment chart: %v", err)
+		return false
+	}
+	if settings == nil || !settings.Chart {
+		return false
+	}
+
+	measured := 0
+	for _, point := range dataPoints {
+		if point.ToxicityPercent != nil {
+			measured++
+		}
+	}
+	if measured < 2 {
+		log.Printf("Only %d runs measured toxicity, posting the sentiment chart", measured)
+		return false
+	}
+	return true
+}
+
+// toxicityAltText describes the toxicity line: its latest value and weekly average
+func toxicityAltText(dataPoints []state.SentimentDataPoint) string {
+	var latest, sum float64
+	measured := 0
+	for _, point := range dataPoints {
+		if point.ToxicityPercent != nil {
+			latest = *point.ToxicityPercent
+			sum += latest
+			measured++
+		}
+	}
+	if measured == 0 {
+		return ""
+	}
+	return fmt.Sprintf("A second line shows the share of toxic posts: %.1f%% in the latest run, averaging %.1f%% over the period.",
+		latest, sum/float64(measured))
+}
+
+// weightingAltText describes the engagement-weighted line of the weighting chart
+func weightingAltText(dataPoints []state.SentimentDataPoint) string {
+	weighted := state.EngagementWeightedPoints(dataPoints)
+	if len(weighted) == 0 {
+		return ""
+	}
+	sum := 0.0
+	for _, point := range weighted {
+		sum += point.NetSentimentPercent
+	}
+	return fmt.Sprintf("A second line weights each post by its likes, reposts and replies: %.1f%% in the latest run, averaging %.1f%% over the period.",
+		weighted[len(weighted)-1].NetSentimentPercent, sum/float64(len(weighted)))
+}
+
+// sentimentAggregation reads how the chart shows sentiment, defaulting to per post
+func (h *SparklinePosterHandler) sentimentAggregation(ctx context.Context) aggregation.Mode {
+	if h.ssmClient == nil {
+		return aggregation.ModePost
+	}
+	mode, err := aggregation.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("Charting per-post sentiment: %v", err)
+	}
+	return mode
+}
+
+// isEmotionChartEnabled reports whether the emotion chart is switched on and the history
+// has enough runs with emotions to draw it
+func (h *SparklinePosterHandler) isEmotionChartEnabled(ctx context.Context, dataPoints []state.SentimentDataPoint) bool {
+	if !h.isFlagEnabled(ctx, emotionChartParameter) {
+		return false
+	}
+
+	measured := 0
+	for _, point := range dataPoints {
+		if point.Emotions != nil {
+			measured++
+		}
+	}
+	if measured < 2 {
+		log.Printf("Only %d runs recorded emotions, posting the sentiment chart", measured)
+		return false
+	}
+	return true
+}
+
+// isRatioChartEnabled reports whether the sentiment ratio chart is switched on and the
+// history has enough runs with sentiment counts to draw it
+func (h *SparklinePosterHandler) isRatioChartEnabled(ctx context.Context, dataPoints []state.SentimentDataPoint) bool {
+	if !h.isFlagEnabled(ctx, ratioChartParameter) {
+		return false
+	}
+
+	measured := 0
+	for _, point := range dataPoints {
+		if point.Counts != nil && point.Counts.Total() > 0 {
+			measured++
+		}
+	}
+	if measured < 2 {
+		log.Printf("Only %d runs recorded sentiment counts, posting the sentiment chart", measured)
+		return false
+	}
+	return true
+}
+
+// isFlagEnabled reports whether an optional "true"/"false" setting is on, defaulting to off
+func (h *SparklinePosterHandler) isFlagEnabled(ctx context.Context, parameter string) bool {
+	if h.ssmClient == nil {
+		return false
+	}
+	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(parameter),
+		WithDecryption: aws.Bool(false),
+	})
+	return err == nil && aws.ToString(result.Parameter.Value) == "true"
+}
+
+// isVolatilityOverlayEnabled reports whether the sentiment chart should shade volatility
+func (h *SparklinePosterHandler) isVolatilityOverlayEnabled(ctx context.Context) bool {
+	return h.isFlagEnabled(ctx, volatilityOverlayParameter)
+}
+
+// chartPoints returns the points to draw on the sentiment chart, with gaps bridged by
+// interpolated points when interpolate_gaps is on. The alt text, extremes message and
+// data table still describe the measured points alone.
+func (h *SparklinePosterHandler) chartPoints(ctx context.Context, dataPoints []state.SentimentDataPoint) []state.SentimentDataPoint {
+	if h.ssmClient == nil {
+		return dataPoints
+	}
+	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(interpolateGapsParameter),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil || aws.ToString(result.Parameter.Value) != "true" {
+		return dataPoints
+	}
+	return state.InterpolateGaps(dataPoints, 0)
+}
+
+// emotionAltText describes the emotion chart by the latest run's breakdown; the
+// sentiment description follows it, since the post still reports sentiment extremes
+func emotionAltText(dataPoints []state.SentimentDataPoint) string {
+	var latest *state.Emotions
+	for _, point := range dataPoints {
+		if point.Emotions != nil {
+			latest = point.Emotions
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return fmt.Sprintf("Stacked area chart of the emotions in Bluesky posts over seven days. Latest run: joy %.0f%%, anger %.0f%%, sadness %.0f%%, fear %.0f%%.",
+		latest.Joy*100, latest.Anger*100, latest.Sadness*100, latest.Fear*100)
+}
+
+// ratioAltText describes the ratio chart by the latest run's split and how the neutral
+// and negative shares moved over the period, since the two rising are different stories
+func ratioAltText(dataPoints []state.SentimentDataPoint) string {
+	var first, latest *state.SentimentCounts
+	for _, point := range dataPoints {
+		if point.Counts == nil || point.Counts.Total() == 0 {
+			continue
+		}
+		if first == nil {
+			first = point.Counts
+		}
+		latest = point.Counts
+	}
+	if latest == nil {
+		return ""
+	}
+	positive, neutral, negative := latest.Percentages()
+	_, firstNeutral, firstNegative := first.Percentages()
+	return fmt.Sprintf("Stacked area chart of the share of positive, neutral and negative Bluesky posts over seven days. "+
+		"Latest run: positive %.0f%%, neutral %.0f%%, negative %.0f%%. "+
+		"Over the period the neutral share changed by %+.0f points and the negative share by %+.0f points.",
+		positive, neutral, negative, neutral-firstNeutral, negative-firstNegative)
+}
+
+// applyInteractionSettings gates replies and quotes on a standalone sparkline post
+func (h *SparklinePosterHandler) applyInteractionSettings(ctx context.Context, blueskyClient client.BskyPoster, postURI string) {
+	if h.ssmClient == nil {
+		return
+	}
+	interaction.Apply(ctx, h.ssmClient, blueskyClient, schedule.PosterSparkline, postURI)
+}
+
+// rotatePin offers the chart for pinning as the weekly post
+func (h *SparklinePosterHandler) rotatePin(ctx context.Context, blueskyClient client.BskyPoster, postURI, postCID string) {
+	if h.ssmClient == nil || h.stateManager == nil {
+		return
+	}
+	// The pins on record are the real account's
+	if h.dryRun.ShadowAccount() {
+		log.Printf("Shadow dry run, not pinning the chart")
+		return
+	}
+	pin.Rotate(ctx, h.ssmClient, h.stateManager, blueskyClient, pin.KindWeekly, postURI, postCID)
+}
+
+// isDataTableReplyEnabled checks the optional data table reply setting, defaulting to off
+func (h *SparklinePosterHandler) isDataTableReplyEnabled(ctx context.Context) bool {
+	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(dataTableReplyParameter),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		log.Printf("Data table reply disabled (%s unavailable: %v)", dataTableReplyParameter, err)
+		return false
+	}
+
+	return *result.Parameter.Value == "true"
+}
+
+// getImageConfig reads the optional image quality settings, defaulting when absent or invalid
+func (h *SparklinePosterHandler) getImageConfig(ctx context.Context) client.ImageConfig {
+	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(imageQualityParameter),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		return client.DefaultImageConfig()
+	}
+
+	imageConfig, err := client.ParseImageConfig(aws.ToString(result.Parameter.Value))
+	if err != nil {
+		log.Printf("Using default image settings: %v", err)
+	}
+	return imageConfig
+}
+
+// dailyTableRows averages the seven day history into one row per UTC day, oldest first
+func dailyTableRows(dataPoints []state.SentimentDataPoint) []formatter.TableRow {
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	var days []time.Time
+	for _, point := range dataPoints {
+		day := point.Timestamp.UTC().Truncate(24 * time.Hour)
+		key := day.Format("2006-01-02")
+		if counts[key] == 0 {
+			days = append(days, day)
+		}
+		sums[key] += point.NetSentimentPercent
+		counts[key]++
+	}
+
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	rows := make([]formatter.TableRow, len(days))
+	for i, day := range days {
+		key := day.Format("2006-01-02")
+		rows[i] = formatter.TableRow{Label: day.Format("Mon Jan 2"), Value: sums[key] / float64(counts[key])}
+	}
+	return rows
+}
+
+// postDataTable threads the chart's data table below the chart post
+// Failures are logged rather than returned because the chart itself has already posted
+func postDataTable(ctx context.Context, blueskyClient client.BskyPoster, rootURI, rootCID, chartURI, chartCID string, dataTable []string) {
+	if len(dataTable) == 0 {
+		return
+	}
+	if err := client.PostReplyChain(ctx, blueskyClient, rootURI, rootCID, chartURI, chartCID, dataTable); err != nil {
+		log.Printf("Failed to post chart data table: %v", err)
+		return
+	}
+	log.Printf("Posted chart data table in %d replies", len(dataTable))
+}
+
 func main() {
+	if err := tracing.Init("hourstats-sparkline-poster"); err != nil {
+		log.Printf("Tracing disabled: %v", err)
+	}
+
 	ctx := context.Background()
 	handler, err := NewSparklinePosterHandler(ctx)
 	if err != nil {
 		log.Fatalf("Failed to create sparkline poster handler: %v", err)
 	}
 
-	lambda.Start(handler.HandleRequest)
+	lambda.Start(tracing.Handler(events.Handler(handler.HandleRequest)))
 }
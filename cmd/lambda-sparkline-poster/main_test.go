@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/client/clienttest"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func TestPostStandaloneSparkline(t *testing.T) {
+	mock := clienttest.NewMockClient()
+	h := &SparklinePosterHandler{newBlueskyClient: mock.Factory()}
+
+	resp, err := h.postStandaloneSparkline(context.Background(), mock, "📊 Seven day Bluesky sentiment", []byte("png"), "alt text", nil)
+	if err != nil {
+		t.Fatalf("postStandaloneSparkline failed: %v", err)
+	}
+	if resp.StatusCode != 200 || !resp.Posted {
+		t.Errorf("Expected posted 200 response, got %+v", resp)
+	}
+
+	posts := mock.Posts()
+	if len(posts) != 1 {
+		t.Fatalf("Expected 1 post, got %d", len(posts))
+	}
+	if posts[0].AltText != "alt text" || string(posts[0].ImageData) != "png" {
+		t.Errorf("Unexpected post recorded: %+v", posts[0])
+	}
+}
+
+func TestPostStandaloneSparklinePostError(t *testing.T) {
+	mock := clienttest.NewMockClient()
+	mock.PostErr = errors.New("rate limited")
+	h := &SparklinePosterHandler{newBlueskyClient: mock.Factory()}
+
+	resp, err := h.postStandaloneSparkline(context.Background(), mock, "text", nil, "", []string{"table"})
+	if err == nil {
+		t.Fatal("Expected error when posting fails")
+	}
+	if resp.StatusCode != 500 || resp.Posted {
+		t.Errorf("Expected unposted 500 response, got %+v", resp)
+	}
+	if len(mock.Posts()) != 0 {
+		t.Errorf("Expected no posts recorded, got %d", len(mock.Posts()))
+	}
+}
+
+func TestPostStandaloneSparklineWithDataTable(t *testing.T) {
+	mock := clienttest.NewMockClient()
+	h := &SparklinePosterHandler{newBlueskyClient: mock.Factory()}
+
+	dataTable := []string{"📋 Chart data\nMon Mar 24: +12.3%", "(cont.)\nTue Mar 25: -3.0%"}
+	resp, err := h.postStandaloneSparkline(context.Background(), mock, "📊 Seven day Bluesky sentiment", []byte("png"), "alt text", dataTable)
+	if err != nil {
+		t.Fatalf("postStandaloneSparkline failed: %v", err)
+	}
+	if !resp.Posted {
+		t.Errorf("Expected posted response, got %+v", resp)
+	}
+
+	posts := mock.Posts()
+	if len(posts) != 3 {
+		t.Fatalf("Expected chart plus 2 table replies, got %d posts", len(posts))
+	}
+	chart := posts[0]
+	if posts[1].ReplyToURI != chart.URI || posts[1].RootURI != chart.URI {
+		t.Errorf("First table reply should reply to the chart, got parent %s root %s", posts[1].ReplyToURI, posts[1].RootURI)
+	}
+	if posts[2].ReplyToURI != posts[1].URI || posts[2].RootURI != chart.URI {
+		t.Errorf("Second table reply should continue the chain under the chart, got parent %s root %s", posts[2].ReplyToURI, posts[2].RootURI)
+	}
+}
+
+func TestDailyTableRows(t *testing.T) {
+	day := func(d, h int) time.Time { return time.Date(2025, 3, d, h, 0, 0, 0, time.UTC) }
+	points := []state.SentimentDataPoint{
+		{Timestamp: day(25, 12), NetSentimentPercent: -4},
+		{Timestamp: day(24, 9), NetSentimentPercent: 10},
+		{Timestamp: day(24, 21), NetSentimentPercent: 20},
+		{Timestamp: day(25, 1), NetSentimentPercent: -2},
+	}
+
+	rows := dailyTableRows(points)
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 days, got %d", len(rows))
+	}
+	if rows[0].Label != "Mon Mar 24" || rows[0].Value != 15 {
+		t.Errorf("Expected Mon Mar 24 averaging 15, got %+v", rows[0])
+	}
+	if rows[1].Label != "Tue Mar 25" || rows[1].Value != -3 {
+		t.Errorf("Expected Tue Mar 25 averaging -3, got %+v", rows[1])
+	}
+}
+
+func TestGenerateDetailedAltText(t *testing.T) {
+	h := &SparklinePosterHandler{}
+	altTexts := h.altTextBuilder(context.Background())
+
+	at := func(d int) time.Time { return time.Date(2025, 3, d, 12, 0, 0, 0, time.UTC) }
+	points := []state.SentimentDataPoint{
+		{Timestamp: at(20), NetSentimentPercent: 5},
+		{Timestamp: at(21), NetSentimentPercent: -2},
+		{Timestamp: at(22), NetSentimentPercent: 9},
+	}
+	want := "Seven day Bluesky sentiment trend chart. Current sentiment: 9.0% (Mar 22, 12:00 PM UTC). " +
+		"Highest sentiment: 9.0% (Mar 22, 12:00 PM UTC). Lowest sentiment: -2.0% (Mar 21, 12:00 PM UTC). " +
+		"Average sentiment: 4.0%. Trending positive over the period."
+	if got := h.generateDetailedAltText(altTexts, points); got != want {
+		t.Errorf("generateDetailedAltText() = %q, want %q", got, want)
+	}
+	if got := h.generateDetailedAltText(altTexts, points[:1]); got != "Seven day sentiment trend chart showing community mood over time" {
+		t.Errorf("generateDetailedAltText() with one point = %q", got)
+	}
+}
+
+func TestRatioAltText(t *testing.T) {
+	points := []state.SentimentDataPoint{
+		{NetSentimentPercent: 4},
+		{Counts: &state.SentimentCounts{Positive: 30, Neutral: 40, Negative: 30}},
+		{Counts: &state.SentimentCounts{Positive: 25, Neutral: 55, Negative: 20}},
+	}
+	want := "Stacked area chart of the share of positive, neutral and negative Bluesky posts over seven days. " +
+		"Latest run: positive 25%, neutral 55%, negative 20%. " +
+		"Over the period the neutral share changed by +15 points and the negative share by -10 points."
+	if got := ratioAltText(points); got != want {
+		t.Errorf("ratioAltText() = %q, want %q", got, want)
+	}
+	if got := ratioAltText(points[:1]); got != "" {
+		t.Errorf("ratioAltText() without counts = %q, want empty", got)
+	}
+}
+
+func TestWeightingAltText(t *testing.T) {
+	first, latest := 2.0, 6.0
+	points := []state.SentimentDataPoint{
+		{NetSentimentPercent: 1},
+		{NetSentimentPercent: 3, WeightedNetSentimentPercent: &first},
+		{NetSentimentPercent: 4, WeightedNetSentimentPercent: &latest},
+	}
+	want := "A second line weights each post by its likes, reposts and replies: 6.0% in the latest run, averaging 4.0% over the period."
+	if got := weightingAltText(points); got != want {
+		t.Errorf("weightingAltText() = %q, want %q", got, want)
+	}
+	if got := weightingAltText(points[:1]); got != "" {
+		t.Errorf("weightingAltText() without weighted points = %q, want empty", got)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/dailydelta"
+	"github.com/christophergentle/hourstats-bsky/internal/reporter"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// postDailyDelta posts the last complete UTC day against the day before it, with the
+// day's most positive and most negative hours in the settings' time zone
+func (h *YearlyPosterHandler) postDailyDelta(ctx context.Context, settings dailydelta.Settings, now time.Time) (Response, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	day := today.AddDate(0, 0, -1)
+	previous := today.AddDate(0, 0, -2)
+
+	dailyPoints, err := h.dailySentimentManager.GetDailySentimentHistory(ctx, 2)
+	if err != nil {
+		log.Printf("Failed to get daily sentiment history: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to get daily sentiment history: " + err.Error(),
+		}, err
+	}
+	byDate := make(map[string]state.DailySentimentDataPoint, len(dailyPoints))
+	for _, point := range dailyPoints {
+		byDate[point.Date] = point
+	}
+	dayPoint, hasDay := byDate[day.Format("2006-01-02")]
+	previousPoint, hasPrevious := byDate[previous.Format("2006-01-02")]
+	if !hasDay || !hasPrevious {
+		log.Printf("Daily sentiment missing for %s or %s, skipping daily delta", day.Format("2006-01-02"), previous.Format("2006-01-02"))
+		return Response{
+			StatusCode: 200,
+			Body:       "Not enough daily data for daily delta",
+			Posted:     false,
+		}, nil
+	}
+
+	// The hourly history reaches back to the start of the day
+	points, err := h.sentimentHistoryManager.GetSentimentHistory(ctx, now.Sub(day))
+	if err != nil {
+		log.Printf("Failed to get sentiment history: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to get sentiment history: " + err.Error(),
+		}, err
+	}
+	report := reporter.SummarizeDelta(dayPoint, previousPoint, points)
+
+	handle, password, err := h.getBlueskyCredentials(ctx)
+	if err != nil {
+		log.Printf("Failed to get Bluesky credentials: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to get credentials: " + err.Error(),
+		}, err
+	}
+
+	blueskyClient := h.newBlueskyClient(handle, password)
+	if err := blueskyClient.Authenticate(); err != nil {
+		log.Printf("Failed to authenticate with Bluesky: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to authenticate: " + err.Error(),
+		}, err
+	}
+
+	if err := blueskyClient.PostWithFacets(ctx, reporter.FormatDelta(report, settings.Location()), nil); err != nil {
+		log.Printf("Failed to post daily delta: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to post daily delta: " + err.Error(),
+		}, err
+	}
+
+	log.Printf("Posted daily delta for %s: %+.1f points", dayPoint.Date, report.SentimentDelta())
+	return Response{
+		StatusCode: 200,
+		Body:       fmt.Sprintf("Daily delta for %s posted", dayPoint.Date),
+		Posted:     true,
+	}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 	"time"
 
@@ -11,18 +12,57 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/christophergentle/hourstats-bsky/internal/alttext"
 	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/credentials"
+	"github.com/christophergentle/hourstats-bsky/internal/currentevents"
+	"github.com/christophergentle/hourstats-bsky/internal/dailydelta"
+	"github.com/christophergentle/hourstats-bsky/internal/dryrun"
+	"github.com/christophergentle/hourstats-bsky/internal/experiments"
+	"github.com/christophergentle/hourstats-bsky/internal/formatter"
+	"github.com/christophergentle/hourstats-bsky/internal/interaction"
+	"github.com/christophergentle/hourstats-bsky/internal/metrics"
+	"github.com/christophergentle/hourstats-bsky/internal/pin"
+	"github.com/christophergentle/hourstats-bsky/internal/reporter"
+	"github.com/christophergentle/hourstats-bsky/internal/schedule"
 	"github.com/christophergentle/hourstats-bsky/internal/sparkline"
 	"github.com/christophergentle/hourstats-bsky/internal/state"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
 )
 
 // Event represents the EventBridge event structure
 type Event struct {
-	Source string `json:"source"`
-	Time   string `json:"time"`
-	Action string `json:"action,omitempty"`
+	Source  string `json:"source"`
+	Time    string `json:"time"`
+	Action  string `json:"action,omitempty"`
+	Month   string `json:"month,omitempty"`   // "2006-01", for a manual transparency report
+	Quarter string `json:"quarter,omitempty"` // "2006-Q1", for a manual quarterly chart
 }
 
+// Actions that post a report instead of the yearly chart
+const (
+	actionTransparencyReport = "transparency_report" // monthly transparency report
+	actionTopicReport        = "topic_report"        // weekly most loved and most hated topics
+	actionDomainReport       = "domain_report"       // weekly most shared link domains
+	actionMonthlyChart       = "monthly_chart"       // 30 day sentiment chart
+	actionQuarterlyChart     = "quarterly_chart"     // previous quarter's sentiment chart
+	actionDailyDelta         = "daily_delta"         // yesterday against the day before, checked hourly
+)
+
+// actionExperimentReport compares engagement per variant of the post format experiment;
+// it posts nothing
+const actionExperimentReport = "experiment_report"
+
+// topicReportPeriod is how far back the weekly topics and domains reports look
+const topicReportPeriod = 7 * 24 * time.Hour
+
+// The weekly experiment report covers a week of summaries, leaving out those too recent
+// to have gathered most of their engagement
+const (
+	experimentReportPeriod = 7 * 24 * time.Hour
+	experimentSettleTime   = 24 * time.Hour
+)
+
 // Response represents the Lambda response
 type Response struct {
 	StatusCode int    `json:"statusCode"`
@@ -30,11 +70,30 @@ type Response struct {
 	Posted     bool   `json:"posted"`
 }
 
+// dataTableReplyParameter enables replying to charts with their values as text
+const dataTableReplyParameter = "/hourstats/settings/data_table_reply"
+
+// interpolateGapsParameter bridges missing days in the yearly chart with dashed,
+// interpolated days when "true"
+const interpolateGapsParameter = "/hourstats/settings/interpolate_gaps"
+
 // YearlyPosterHandler handles the yearly poster Lambda function
 type YearlyPosterHandler struct {
 	dailySentimentManager    *state.DailySentimentManager
-	yearlySparklineGenerator *sparkline.YearlySparklineGenerator
-	ssmClient                *ssm.Client
+	sentimentHistoryManager  *state.SentimentHistoryManager
+	stateManager             *state.StateManager
+	selfStatsManager         *state.SelfStatsManager
+	yearlySparklineGenerator *sparkline.RangeChartGenerator
+	// monthlySparklineGenerator and quarterlySparklineGenerator draw the 30 day and quarterly charts
+	monthlySparklineGenerator   *sparkline.RangeChartGenerator
+	quarterlySparklineGenerator *sparkline.RangeChartGenerator
+	ssmClient                   *ssm.Client
+	credentials                 credentials.Provider
+	newBlueskyClient            client.Factory
+	currentEvents               *currentevents.Source
+
+	// dryRun is the current invocation's dry run level, read at the start of each invocation
+	dryRun dryrun.Level
 }
 
 // NewYearlyPosterHandler creates a new yearly poster handler
@@ -45,11 +104,31 @@ func NewYearlyPosterHandler(ctx context.Context) (*YearlyPosterHandler, error) {
 		return nil, fmt.Errorf("failed to create daily sentiment manager: %w", err)
 	}
 
+	// Initialize sentiment history manager, which holds each run's topic sentiment
+	sentimentHistoryManager, err := state.NewSentimentHistoryManager(ctx, "hourstats-sentiment-history")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sentiment history manager: %w", err)
+	}
+
+	// Initialize state manager, which holds the pin history
+	stateManager, err := state.NewStateManager(ctx, "hourstats-state")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state manager: %w", err)
+	}
+
+	// Initialize self stats manager, which holds the engagement on the bot's own posts
+	selfStatsManager, err := state.NewSelfStatsManager(ctx, "hourstats-self-stats")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create self stats manager: %w", err)
+	}
+
 	// Initialize yearly sparkline generator
 	yearlySparklineGenerator := sparkline.NewYearlySparklineGenerator(nil) // Use default config
+	monthlySparklineGenerator := sparkline.NewMonthlySparklineGenerator(nil)
+	quarterlySparklineGenerator := sparkline.NewQuarterlySparklineGenerator(nil)
 
 	// Initialize AWS clients
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := config.LoadDefaultConfig(ctx, tracing.WithAWS)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
@@ -57,9 +136,17 @@ func NewYearlyPosterHandler(ctx context.Context) (*YearlyPosterHandler, error) {
 	ssmClient := ssm.NewFromConfig(cfg)
 
 	return &YearlyPosterHandler{
-		dailySentimentManager:    dailySentimentManager,
-		yearlySparklineGenerator: yearlySparklineGenerator,
-		ssmClient:                ssmClient,
+		dailySentimentManager:       dailySentimentManager,
+		sentimentHistoryManager:     sentimentHistoryManager,
+		stateManager:                stateManager,
+		selfStatsManager:            selfStatsManager,
+		yearlySparklineGenerator:    yearlySparklineGenerator,
+		monthlySparklineGenerator:   monthlySparklineGenerator,
+		quarterlySparklineGenerator: quarterlySparklineGenerator,
+		ssmClient:                   ssmClient,
+		credentials:                 credentials.Default(cfg, ssmClient),
+		newBlueskyClient:            client.NewClient,
+		currentEvents:               currentevents.NewSource("", stateManager),
 	}, nil
 }
 
@@ -67,25 +154,98 @@ func NewYearlyPosterHandler(ctx context.Context) (*YearlyPosterHandler, error) {
 func (h *YearlyPosterHandler) HandleRequest(ctx context.Context, event Event) (Response, error) {
 	log.Printf("Yearly poster received event: %+v", event)
 
-	// Check if dry run mode is enabled
-	dryRun, err := h.isDryRunMode(ctx)
+	// The experiment report only reads, so neither dry run nor the posting schedule applies
+	if event.Action == actionExperimentReport {
+		return h.reportExperiment(ctx)
+	}
+
+	// The day-over-day post is checked every hour but only posts at its local time
+	var deltaSettings dailydelta.Settings
+	if event.Action == actionDailyDelta {
+		settings, err := dailydelta.Load(ctx, h.ssmClient)
+		if err != nil {
+			log.Printf("Ignoring daily delta settings: %v", err)
+		}
+		deltaSettings = settings
+		if !deltaSettings.Due(time.Now()) {
+			return Response{
+				StatusCode: 200,
+				Body:       "Daily delta not due until " + deltaSettings.Time + " " + deltaSettings.TimeZone,
+				Posted:     false,
+			}, nil
+		}
+	}
+
+	// Check the dry run level; a shadow dry run posts from the shadow account
+	level, err := dryrun.Load(ctx, h.ssmClient)
 	if err != nil {
-		log.Printf("Failed to check dry run mode: %v", err)
+		log.Printf("Failed to check dry run level: %v", err)
 		return Response{
 			StatusCode: 500,
-			Body:       "Failed to check dry run mode: " + err.Error(),
+			Body:       "Failed to check dry run level: " + err.Error(),
 		}, err
 	}
 
-	if dryRun {
-		log.Printf("Dry run mode enabled, skipping yearly post")
+	h.dryRun = level
+
+	if !level.Posts() {
+		log.Printf("Dry run (%s), skipping yearly post", level)
+		return Response{
+			StatusCode: 200,
+			Body:       "Dry run (" + level.String() + ") - yearly post skipped",
+			Posted:     false,
+		}, nil
+	}
+
+	// Respect the posting schedule; the yearly post has no run state, so the skip is only logged.
+	// The 30 day and quarterly charts and the daily delta have their own schedule keys, and the
+	// reports follow the yearly one.
+	poster := schedule.PosterYearly
+	switch event.Action {
+	case actionMonthlyChart:
+		poster = schedule.PosterMonthly
+	case actionQuarterlyChart:
+		poster = schedule.PosterQuarterly
+	case actionDailyDelta:
+		poster = schedule.PosterDelta
+	}
+	if rules, err := schedule.Load(ctx, h.ssmClient); err != nil {
+		log.Printf("Ignoring posting schedule: %v", err)
+	} else if allowed, reason := rules.Allows(poster, time.Now()); !allowed {
+		log.Printf("🔕 Posting schedule withheld %s post: %s", poster, reason)
 		return Response{
 			StatusCode: 200,
-			Body:       "Dry run mode - yearly post skipped",
+			Body:       "Posting schedule - " + poster + " post skipped: " + reason,
 			Posted:     false,
 		}, nil
 	}
 
+	if event.Action == actionTransparencyReport {
+		return h.postTransparencyReport(ctx, event.Month)
+	}
+	if event.Action == actionTopicReport {
+		return h.postTopicReport(ctx)
+	}
+	if event.Action == actionDomainReport {
+		return h.postDomainReport(ctx)
+	}
+	if event.Action == actionDailyDelta {
+		return h.postDailyDelta(ctx, deltaSettings, time.Now().UTC())
+	}
+	if event.Action == actionMonthlyChart {
+		return h.postRangeChart(ctx, h.monthlyChart(time.Now().UTC()))
+	}
+	if event.Action == actionQuarterlyChart {
+		chart, err := h.quarterlyChart(event.Quarter, time.Now().UTC())
+		if err != nil {
+			return Response{
+				StatusCode: 400,
+				Body:       "Invalid quarter: " + err.Error(),
+			}, err
+		}
+		return h.postRangeChart(ctx, chart)
+	}
+
 	// Get 365 days of daily sentiment data
 	yearlyData, err := h.dailySentimentManager.GetYearlySentimentData(ctx)
 	if err != nil {
@@ -101,8 +261,13 @@ func (h *YearlyPosterHandler) HandleRequest(ctx context.Context, event Event) (R
 		return h.postInsufficientDataMessage(ctx, len(yearlyData))
 	}
 
-	// Generate yearly sparkline image
-	imageData, err := h.yearlySparklineGenerator.GenerateYearlySentimentSparkline(yearlyData)
+	// Generate yearly sparkline image, bridging missing days when asked to; the alt text and
+	// extremes describe the measured days alone
+	chartData := yearlyData
+	if h.isGapInterpolationEnabled(ctx) {
+		chartData = state.InterpolateDailyGaps(yearlyData)
+	}
+	imageData, err := h.yearlySparklineGenerator.GenerateYearlySentimentSparkline(chartData)
 	if err != nil {
 		log.Printf("Failed to generate yearly sparkline: %v", err)
 		return Response{
@@ -122,7 +287,7 @@ func (h *YearlyPosterHandler) HandleRequest(ctx context.Context, event Event) (R
 	}
 
 	// Create Bluesky client
-	blueskyClient := client.New(handle, password)
+	blueskyClient := h.newBlueskyClient(handle, password)
 	if err := blueskyClient.Authenticate(); err != nil {
 		log.Printf("Failed to authenticate with Bluesky: %v", err)
 		return Response{
@@ -135,7 +300,21 @@ func (h *YearlyPosterHandler) HandleRequest(ctx context.Context, event Event) (R
 	extremeMessage := h.analyzeYearlySentimentExtremes(yearlyData)
 
 	// Generate comprehensive alt text
-	altText := h.generateYearlyAltText(yearlyData)
+	altTexts := h.altTextBuilder(ctx)
+	altParts := []string{h.generateYearlyAltText(altTexts, yearlyData)}
+
+	// Give the extremes some context from Wikipedia's current events portal, when it has any
+	headlines := h.extremeHeadlines(ctx, yearlyData)
+	if len(headlines) > 0 {
+		altParts = append(altParts, "In the news on those days: "+strings.Join(headlines, "; ")+".")
+	}
+	altText := altTexts.Join(altParts...)
+
+	// Optionally follow the chart with its values as text for screen-reader users
+	var dataTable []string
+	if h.isDataTableReplyEnabled(ctx) {
+		dataTable = formatter.FormatDataTable("📋 Chart data, monthly average net sentiment:", monthlyTableRows(yearlyData))
+	}
 
 	// Post yearly sparkline with embedded image to Bluesky
 	// Format: "Bluesky Sentiment {start date} - {end date}"
@@ -150,24 +329,15 @@ func (h *YearlyPosterHandler) HandleRequest(ctx context.Context, event Event) (R
 	if extremeMessage != "" {
 		postText += "\n\n" + extremeMessage
 	}
-
-	// Truncate post text to 300 graphemes (Bluesky limit)
-	maxGraphemes := 300
-	truncatedPostText := postText
-	if len([]rune(postText)) > maxGraphemes {
-		runes := []rune(postText)
-		if len(runes) > maxGraphemes {
-			truncated := string(runes[:maxGraphemes])
-			lastNewline := strings.LastIndex(truncated, "\n")
-			if lastNewline > maxGraphemes/2 {
-				truncatedPostText = truncated[:lastNewline]
-			} else {
-				truncatedPostText = truncated
-			}
-			log.Printf("Post text truncated from %d to %d graphemes", len(runes), len([]rune(truncatedPostText)))
+	// The headlines only go in the post when they fit; the alt text always has them
+	if len(headlines) > 0 {
+		if withHeadlines := postText + "\n\n" + strings.Join(headlines, "\n"); len([]rune(withHeadlines)) <= 300 {
+			postText = withHeadlines
 		}
 	}
 
+	truncatedPostText := truncatePostText(postText)
+
 	// Create facets for Wikipedia URLs to make them clickable (based on truncated text)
 	wikipediaFacets := client.CreateWikipediaLinkFacets(truncatedPostText)
 
@@ -185,14 +355,20 @@ func (h *YearlyPosterHandler) HandleRequest(ctx context.Context, event Event) (R
 			Body:       "Failed to post yearly sparkline: " + err.Error(),
 		}, err
 	}
+	interaction.Apply(ctx, h.ssmClient, blueskyClient, schedule.PosterYearly, postURI)
 
-	// Pin the post to the account profile
-	err = blueskyClient.PinPost(ctx, postURI, postCID)
-	if err != nil {
-		log.Printf("Failed to pin yearly post: %v (post was successful)", err)
-		// Don't fail the entire operation if pinning fails
-	} else {
-		log.Printf("Yearly sentiment chart posted and pinned successfully")
+	if len(dataTable) > 0 {
+		if err := client.PostReplyChain(ctx, blueskyClient, postURI, postCID, postURI, postCID, dataTable); err != nil {
+			log.Printf("Failed to post chart data table: %v (chart was posted)", err)
+		} else {
+			log.Printf("Posted chart data table in %d replies", len(dataTable))
+		}
+	}
+
+	// Pin the post to the account profile if the pin policy prefers it to the current pin;
+	// the pins on record are the real account's, so a shadow dry run leaves them alone
+	if !h.dryRun.ShadowAccount() {
+		pin.Rotate(ctx, h.ssmClient, h.stateManager, blueskyClient, pin.KindYearly, postURI, postCID)
 	}
 
 	log.Printf("Successfully posted yearly sentiment chart with %d days of data", len(yearlyData))
@@ -203,50 +379,84 @@ func (h *YearlyPosterHandler) HandleRequest(ctx context.Context, event Event) (R
 	}, nil
 }
 
-// isDryRunMode checks if dry run mode is enabled
-func (h *YearlyPosterHandler) isDryRunMode(ctx context.Context) (bool, error) {
+// truncatePostText cuts postText to 300 graphemes (Bluesky limit), at its last line break
+// when that keeps at least half
+func truncatePostText(postText string) string {
+	maxGraphemes := 300
+	runes := []rune(postText)
+	if len(runes) <= maxGraphemes {
+		return postText
+	}
+	truncated := string(runes[:maxGraphemes])
+	if lastNewline := strings.LastIndex(truncated, "\n"); lastNewline > maxGraphemes/2 {
+		truncated = truncated[:lastNewline]
+	}
+	log.Printf("Post text truncated from %d to %d graphemes", len(runes), len([]rune(truncated)))
+	return truncated
+}
+
+// isDataTableReplyEnabled checks the optional data table reply setting, defaulting to off
+func (h *YearlyPosterHandler) isDataTableReplyEnabled(ctx context.Context) bool {
 	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
-		Name:           aws.String("/hourstats/settings/dry_run"),
+		Name:           aws.String(dataTableReplyParameter),
 		WithDecryption: aws.Bool(false),
 	})
 	if err != nil {
-		return false, fmt.Errorf("failed to get dry run parameter: %w", err)
+		log.Printf("Data table reply disabled (%s unavailable: %v)", dataTableReplyParameter, err)
+		return false
 	}
 
-	return *result.Parameter.Value == "true", nil
+	return *result.Parameter.Value == "true"
 }
 
-// getBlueskyCredentials retrieves credentials from SSM
-func (h *YearlyPosterHandler) getBlueskyCredentials(ctx context.Context) (string, string, error) {
-	parameterNames := []string{
-		"/hourstats/bluesky/handle",
-		"/hourstats/bluesky/password",
-	}
-
-	result, err := h.ssmClient.GetParameters(ctx, &ssm.GetParametersInput{
-		Names:          parameterNames,
-		WithDecryption: aws.Bool(true),
+// isGapInterpolationEnabled checks the optional gap interpolation setting, defaulting to off
+func (h *YearlyPosterHandler) isGapInterpolationEnabled(ctx context.Context) bool {
+	result, err := h.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(interpolateGapsParameter),
+		WithDecryption: aws.Bool(false),
 	})
 	if err != nil {
-		return "", "", fmt.Errorf("failed to get parameters: %w", err)
+		return false
 	}
 
-	params := make(map[string]string)
-	for _, p := range result.Parameters {
-		params[*p.Name] = *p.Value
-	}
+	return aws.ToString(result.Parameter.Value) == "true"
+}
 
-	handle, ok := params["/hourstats/bluesky/handle"]
-	if !ok {
-		return "", "", fmt.Errorf("handle parameter not found")
+// monthlyTableRows averages the daily series into one row per month, oldest first
+// A full year of daily values would take a dozen posts; months keep the table to one or two
+func monthlyTableRows(dataPoints []state.YearlySparklineDataPoint) []formatter.TableRow {
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	var months []time.Time
+	for _, point := range dataPoints {
+		ts := point.Timestamp.UTC()
+		month := time.Date(ts.Year(), ts.Month(), 1, 0, 0, 0, 0, time.UTC)
+		key := month.Format("2006-01")
+		if counts[key] == 0 {
+			months = append(months, month)
+		}
+		sums[key] += point.AverageSentiment
+		counts[key]++
 	}
 
-	password, ok := params["/hourstats/bluesky/password"]
-	if !ok {
-		return "", "", fmt.Errorf("password parameter not found")
+	sort.Slice(months, func(i, j int) bool { return months[i].Before(months[j]) })
+
+	rows := make([]formatter.TableRow, len(months))
+	for i, month := range months {
+		key := month.Format("2006-01")
+		rows[i] = formatter.TableRow{Label: month.Format("Jan 2006"), Value: sums[key] / float64(counts[key])}
 	}
+	return rows
+}
 
-	return handle, password, nil
+// getBlueskyCredentials retrieves credentials from the Secrets Manager secret or SSM, or
+// the shadow account's during a shadow dry run
+func (h *YearlyPosterHandler) getBlueskyCredentials(ctx context.Context) (string, string, error) {
+	creds, err := h.dryRun.Credentials(h.credentials, h.ssmClient).Retrieve(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	return creds.Handle, creds.Password, nil
 }
 
 // analyzeYearlySentimentExtremes checks for notable sentiment patterns in the yearly data
@@ -323,52 +533,73 @@ func (h *YearlyPosterHandler) analyzeYearlySentimentExtremes(dataPoints []state.
 	return strings.Join(insights, "\n")
 }
 
-// generateYearlyAltText creates comprehensive alt text for the yearly sparkline chart
-func (h *YearlyPosterHandler) generateYearlyAltText(dataPoints []state.YearlySparklineDataPoint) string {
-	if len(dataPoints) < 2 {
-		return "Yearly sentiment trend chart showing community mood over the past year"
+// extremeHeadlines returns a dated headline for the lowest and highest days of the year,
+// skipping days Wikipedia has nothing for
+func (h *YearlyPosterHandler) extremeHeadlines(ctx context.Context, dataPoints []state.YearlySparklineDataPoint) []string {
+	if h.currentEvents == nil || len(dataPoints) < 2 {
+		return nil
 	}
 
-	// Calculate statistics
 	stats := h.calculateYearlySentimentStats(dataPoints)
-
-	// Format dates for readability
-	formatDate := func(dateStr string) string {
-		date, err := time.Parse("2006-01-02", dateStr)
+	var headlines []string
+	for _, day := range []string{stats.LowestDate, stats.HighestDate} {
+		date, err := time.Parse("2006-01-02", day)
 		if err != nil {
-			return dateStr
+			continue
+		}
+		if headline := h.currentEvents.Headline(ctx, date); headline != "" {
+			headlines = append(headlines, currentevents.Format(date, headline))
 		}
-		return date.Format("Jan 2, 2006")
 	}
+	return headlines
+}
 
-	// Build comprehensive alt text
-	altText := "Yearly Bluesky sentiment trend chart showing daily averages over the past year. "
-
-	// Add current sentiment
-	altText += fmt.Sprintf("Current sentiment: %.1f%% (%s). ",
-		stats.Current, formatDate(stats.CurrentDate))
-
-	// Add highest sentiment
-	altText += fmt.Sprintf("Highest sentiment: %.1f%% (%s). ",
-		stats.Highest, formatDate(stats.HighestDate))
+// generateYearlyAltText creates comprehensive alt text for the yearly sparkline chart
+func (h *YearlyPosterHandler) generateYearlyAltText(altTexts *alttext.Builder, dataPoints []state.YearlySparklineDataPoint) string {
+	if len(dataPoints) < 2 {
+		return altTexts.Yearly(nil)
+	}
 
-	// Add lowest sentiment
-	altText += fmt.Sprintf("Lowest sentiment: %.1f%% (%s). ",
-		stats.Lowest, formatDate(stats.LowestDate))
+	return altTexts.Yearly(h.altTextStats(dataPoints))
+}
 
-	// Add yearly average sentiment
-	altText += fmt.Sprintf("Yearly average sentiment: %.1f%%. ", stats.Average)
+// altTextStats calculates the figures the daily charts' alt text describes
+func (h *YearlyPosterHandler) altTextStats(dataPoints []state.YearlySparklineDataPoint) *alttext.Stats {
+	stats := h.calculateYearlySentimentStats(dataPoints)
+	return &alttext.Stats{
+		Current:     stats.Current,
+		CurrentTime: parseStatsDate(stats.CurrentDate),
+		Highest:     stats.Highest,
+		HighestTime: parseStatsDate(stats.HighestDate),
+		Lowest:      stats.Lowest,
+		LowestTime:  parseStatsDate(stats.LowestDate),
+		Average:     stats.Average,
+		Trend:       stats.Trend,
+	}
+}
 
-	// Add trend information
-	if stats.Trend > 0 {
-		altText += "Trending positive over the year."
-	} else if stats.Trend < 0 {
-		altText += "Trending negative over the year."
-	} else {
-		altText += "Stable sentiment over the year."
+// parseStatsDate parses a data point's YYYY-MM-DD date, returning the zero time when it
+// is malformed
+func parseStatsDate(date string) time.Time {
+	parsed, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		log.Printf("Invalid yearly data point date %q: %v", date, err)
 	}
+	return parsed
+}
 
-	return altText
+// altTextBuilder loads the alt text templates, falling back to the defaults
+func (h *YearlyPosterHandler) altTextBuilder(ctx context.Context) *alttext.Builder {
+	settings, err := alttext.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("Failed to load alt text settings, using the defaults: %v", err)
+	}
+	builder, err := alttext.New(settings)
+	if err != nil {
+		log.Printf("Invalid alt text settings, using the defaults: %v", err)
+		return alttext.Default()
+	}
+	return builder
 }
 
 // YearlySentimentStats holds calculated yearly sentiment statistics
@@ -436,6 +667,292 @@ func (h *YearlyPosterHandler) calculateYearlySentimentStats(dataPoints []state.Y
 	return stats
 }
 
+// postTransparencyReport posts the transparency report for month ("2006-01"), defaulting
+// to the previous calendar month
+func (h *YearlyPosterHandler) postTransparencyReport(ctx context.Context, month string) (Response, error) {
+	target, err := reportMonth(month, time.Now().UTC())
+	if err != nil {
+		return Response{
+			StatusCode: 400,
+			Body:       "Invalid report month: " + err.Error(),
+		}, err
+	}
+
+	days, err := h.dailySentimentManager.GetDailySentimentHistory(ctx, int(time.Since(target).Hours()/24)+1)
+	if err != nil {
+		log.Printf("Failed to get daily sentiment history: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to get daily sentiment history: " + err.Error(),
+		}, err
+	}
+
+	report := reporter.Summarize(target, days)
+	if report.DaysWithData == 0 {
+		log.Printf("No daily data for %s, skipping transparency report", target.Format("2006-01"))
+		return Response{
+			StatusCode: 200,
+			Body:       "No data for transparency report: " + target.Format("2006-01"),
+			Posted:     false,
+		}, nil
+	}
+
+	// Engagement on our own posts is a nice-to-have; the report goes out without it
+	ownPosts, err := h.selfStatsManager.GetPostStats(ctx, target, target.AddDate(0, 1, 0))
+	if err != nil {
+		log.Printf("Failed to get self stats, reporting without own post engagement: %v", err)
+	} else {
+		report.AddOwnPosts(ownPosts)
+	}
+
+	handle, password, err := h.getBlueskyCredentials(ctx)
+	if err != nil {
+		log.Printf("Failed to get Bluesky credentials: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to get credentials: " + err.Error(),
+		}, err
+	}
+
+	blueskyClient := h.newBlueskyClient(handle, password)
+	if err := blueskyClient.Authenticate(); err != nil {
+		log.Printf("Failed to authenticate with Bluesky: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to authenticate: " + err.Error(),
+		}, err
+	}
+
+	if err := blueskyClient.PostWithFacets(ctx, reporter.Format(report), nil); err != nil {
+		log.Printf("Failed to post transparency report: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to post transparency report: " + err.Error(),
+		}, err
+	}
+
+	log.Printf("Posted transparency report for %s: %+v", target.Format("2006-01"), report)
+	return Response{
+		StatusCode: 200,
+		Body:       "Transparency report posted for " + target.Format("2006-01"),
+		Posted:     true,
+	}, nil
+}
+
+// postTopicReport posts the most loved and most hated trending topics of the past week
+func (h *YearlyPosterHandler) postTopicReport(ctx context.Context) (Response, error) {
+	points, err := h.sentimentHistoryManager.GetSentimentHistory(ctx, topicReportPeriod)
+	if err != nil {
+		log.Printf("Failed to get sentiment history: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to get sentiment history: " + err.Error(),
+		}, err
+	}
+
+	report := reporter.SummarizeTopics(points, reporter.MinTopicRuns)
+	if len(report.Loved) == 0 && len(report.Hated) == 0 {
+		log.Printf("No topics trended in at least %d of %d runs, skipping topics report", reporter.MinTopicRuns, report.Runs)
+		return Response{
+			StatusCode: 200,
+			Body:       "Not enough topic data for topics report",
+			Posted:     false,
+		}, nil
+	}
+
+	handle, password, err := h.getBlueskyCredentials(ctx)
+	if err != nil {
+		log.Printf("Failed to get Bluesky credentials: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to get credentials: " + err.Error(),
+		}, err
+	}
+
+	blueskyClient := h.newBlueskyClient(handle, password)
+	if err := blueskyClient.Authenticate(); err != nil {
+		log.Printf("Failed to authenticate with Bluesky: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to authenticate: " + err.Error(),
+		}, err
+	}
+
+	if err := blueskyClient.PostWithFacets(ctx, reporter.FormatTopics(report), nil); err != nil {
+		log.Printf("Failed to post topics report: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to post topics report: " + err.Error(),
+		}, err
+	}
+
+	log.Printf("Posted topics report over %d runs: %d loved, %d hated", report.Runs, len(report.Loved), len(report.Hated))
+	return Response{
+		StatusCode: 200,
+		Body:       "Topics report posted",
+		Posted:     true,
+	}, nil
+}
+
+// postDomainReport posts the most shared link domains of the past week
+func (h *YearlyPosterHandler) postDomainReport(ctx context.Context) (Response, error) {
+	points, err := h.sentimentHistoryManager.GetSentimentHistory(ctx, topicReportPeriod)
+	if err != nil {
+		log.Printf("Failed to get sentiment history: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to get sentiment history: " + err.Error(),
+		}, err
+	}
+
+	report := reporter.SummarizeDomains(points, reporter.DomainsPerReport)
+	if len(report.Domains) == 0 {
+		log.Printf("No links shared in %d runs, skipping domains report", len(points))
+		return Response{
+			StatusCode: 200,
+			Body:       "Not enough link data for domains report",
+			Posted:     false,
+		}, nil
+	}
+
+	handle, password, err := h.getBlueskyCredentials(ctx)
+	if err != nil {
+		log.Printf("Failed to get Bluesky credentials: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to get credentials: " + err.Error(),
+		}, err
+	}
+
+	blueskyClient := h.newBlueskyClient(handle, password)
+	if err := blueskyClient.Authenticate(); err != nil {
+		log.Printf("Failed to authenticate with Bluesky: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to authenticate: " + err.Error(),
+		}, err
+	}
+
+	if err := blueskyClient.PostWithFacets(ctx, reporter.FormatDomains(report), nil); err != nil {
+		log.Printf("Failed to post domains report: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to post domains report: " + err.Error(),
+		}, err
+	}
+
+	log.Printf("Posted domains report over %d runs: %d domains", report.Runs, len(report.Domains))
+	return Response{
+		StatusCode: 200,
+		Body:       "Domains report posted",
+		Posted:     true,
+	}, nil
+}
+
+// reportExperiment compares engagement on the summaries posted in each variant of the
+// running experiment, logging the comparison and emitting it as metrics per variant
+func (h *YearlyPosterHandler) reportExperiment(ctx context.Context) (Response, error) {
+	experiment, err := experiments.Load(ctx, h.ssmClient)
+	if err != nil {
+		log.Printf("Failed to load experiment: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to load experiment: " + err.Error(),
+		}, err
+	}
+	if experiment == nil {
+		log.Printf("No experiment running, skipping experiment report")
+		return Response{
+			StatusCode: 200,
+			Body:       "No experiment running",
+		}, nil
+	}
+
+	settled := time.Now().Add(-experimentSettleTime)
+	posts, err := h.stateManager.GetExperimentPosts(ctx, experiment.Name, settled.Add(-experimentReportPeriod))
+	if err != nil {
+		log.Printf("Failed to get experiment posts: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to get experiment posts: " + err.Error(),
+		}, err
+	}
+	var uris []string
+	for _, post := range posts {
+		if post.PostedAt.Before(settled) {
+			uris = append(uris, post.URI)
+		}
+	}
+	if len(uris) == 0 {
+		log.Printf("No settled posts in experiment %s, skipping experiment report", experiment.Name)
+		return Response{
+			StatusCode: 200,
+			Body:       "No experiment posts to compare",
+		}, nil
+	}
+
+	handle, password, err := h.getBlueskyCredentials(ctx)
+	if err != nil {
+		log.Printf("Failed to get Bluesky credentials: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to get credentials: " + err.Error(),
+		}, err
+	}
+
+	blueskyClient := h.newBlueskyClient(handle, password)
+	if err := blueskyClient.Authenticate(); err != nil {
+		log.Printf("Failed to authenticate with Bluesky: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to authenticate: " + err.Error(),
+		}, err
+	}
+
+	engagement, err := blueskyClient.GetPostEngagement(ctx, uris)
+	if err != nil {
+		log.Printf("Failed to get experiment post engagement: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to get engagement: " + err.Error(),
+		}, err
+	}
+
+	// Deleted posts are left out rather than counted as having no engagement
+	var results []experiments.Result
+	for _, post := range posts {
+		if e, ok := engagement[post.URI]; ok && post.PostedAt.Before(settled) {
+			results = append(results, experiments.Result{Variant: formatter.Template(post.Variant), Engagement: e})
+		}
+	}
+
+	log.Printf("🧪 Experiment %s over %d posts:", experiment.Name, len(results))
+	for _, summary := range experiment.Compare(results) {
+		log.Printf("🧪   %-12s %3d posts, %.1f ± %.1f engagement (%.1f likes, %.1f reposts, %.1f replies, %.1f quotes), %+.1f%% vs %s",
+			summary.Variant, summary.Posts, summary.Total, summary.StdErr,
+			summary.Likes, summary.Reposts, summary.Replies, summary.Quotes, summary.Lift, experiment.Variants[0])
+		if err := metrics.Emit(map[string]string{"Experiment": experiment.Name, "Variant": string(summary.Variant)},
+			metrics.Metric{Name: "ExperimentPosts", Value: float64(summary.Posts), Unit: metrics.UnitCount},
+			metrics.Metric{Name: "ExperimentEngagement", Value: summary.Total, Unit: metrics.UnitCount},
+		); err != nil {
+			log.Printf("Failed to emit experiment metrics: %v", err)
+		}
+	}
+
+	return Response{
+		StatusCode: 200,
+		Body:       fmt.Sprintf("Compared %d posts in experiment %s", len(results), experiment.Name),
+	}, nil
+}
+
+// reportMonth parses month ("2006-01"), or returns the month before now when it is empty
+func reportMonth(month string, now time.Time) (time.Time, error) {
+	if month == "" {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0), nil
+	}
+	return time.Parse("2006-01", month)
+}
+
 // postInsufficientDataMessage posts a message about insufficient yearly data
 func (h *YearlyPosterHandler) postInsufficientDataMessage(ctx context.Context, dataPointCount int) (Response, error) {
 	// Get Bluesky credentials
@@ -449,7 +966,7 @@ func (h *YearlyPosterHandler) postInsufficientDataMessage(ctx context.Context, d
 	}
 
 	// Create Bluesky client
-	blueskyClient := client.New(handle, password)
+	blueskyClient := h.newBlueskyClient(handle, password)
 	if err := blueskyClient.Authenticate(); err != nil {
 		log.Printf("Failed to authenticate with Bluesky: %v", err)
 		return Response{
@@ -490,11 +1007,15 @@ func (h *YearlyPosterHandler) postInsufficientDataMessage(ctx context.Context, d
 }
 
 func main() {
+	if err := tracing.Init("hourstats-yearly-poster"); err != nil {
+		log.Printf("Tracing disabled: %v", err)
+	}
+
 	ctx := context.Background()
 	handler, err := NewYearlyPosterHandler(ctx)
 	if err != nil {
 		log.Fatalf("Failed to create yearly poster handler: %v", err)
 	}
 
-	lambda.Start(handler.HandleRequest)
+	lambda.Start(tracing.Handler(handler.HandleRequest))
 }
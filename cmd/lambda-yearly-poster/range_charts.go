@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/alttext"
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/formatter"
+	"github.com/christophergentle/hourstats-bsky/internal/interaction"
+	"github.com/christophergentle/hourstats-bsky/internal/pin"
+	"github.com/christophergentle/hourstats-bsky/internal/schedule"
+	"github.com/christophergentle/hourstats-bsky/internal/sparkline"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// rangeChartMinDays is the fewest days of data a range chart is drawn from
+const rangeChartMinDays = 7
+
+// rangeChart is a chart of daily sentiment over a range shorter than the yearly chart's
+type rangeChart struct {
+	name       string // "30 day" or "quarterly", for logs and responses
+	period     string // closes the post's heading, e.g. "last 30 days" or "Q3 2025"
+	average    string // names the range's average in the extremes, e.g. "30 day average"
+	poster     string // posting schedule and interaction key
+	pinKind    string
+	chartRange sparkline.ChartRange
+	generator  *sparkline.RangeChartGenerator
+	altText    func(*alttext.Builder, *alttext.Stats) string
+}
+
+// monthlyChart is the chart of the last 30 days, between the weekly sparkline and the
+// yearly chart in span
+func (h *YearlyPosterHandler) monthlyChart(now time.Time) rangeChart {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return rangeChart{
+		name:       "30 day",
+		period:     "last 30 days",
+		average:    "30 day average",
+		poster:     schedule.PosterMonthly,
+		pinKind:    pin.KindMonthly,
+		chartRange: sparkline.ChartRange{Start: today.AddDate(0, 0, -state.MonthlyDays), End: today.AddDate(0, 0, 1), Resolution: sparkline.ResolutionDaily},
+		generator:  h.monthlySparklineGenerator,
+		altText:    (*alttext.Builder).Monthly,
+	}
+}
+
+// quarterlyChart is the chart of quarter ("2006-Q1"), defaulting to the quarter before now
+func (h *YearlyPosterHandler) quarterlyChart(quarter string, now time.Time) (rangeChart, error) {
+	start, err := reportQuarter(quarter, now)
+	if err != nil {
+		return rangeChart{}, err
+	}
+	return rangeChart{
+		name:       "quarterly",
+		period:     fmt.Sprintf("Q%d %d", int(start.Month()-1)/3+1, start.Year()),
+		average:    "quarterly average",
+		poster:     schedule.PosterQuarterly,
+		pinKind:    pin.KindQuarterly,
+		chartRange: sparkline.ChartRange{Start: start, End: start.AddDate(0, 3, 0), Resolution: sparkline.ResolutionDaily},
+		generator:  h.quarterlySparklineGenerator,
+		altText:    (*alttext.Builder).Quarterly,
+	}, nil
+}
+
+// reportQuarter parses quarter ("2006-Q1") into its first day, or returns the first day of
+// the quarter before now when it is empty
+func reportQuarter(quarter string, now time.Time) (time.Time, error) {
+	if quarter == "" {
+		thisQuarter := time.Date(now.Year(), (now.Month()-1)/3*3+1, 1, 0, 0, 0, 0, time.UTC)
+		return thisQuarter.AddDate(0, -3, 0), nil
+	}
+	year, number, ok := strings.Cut(quarter, "-Q")
+	y, yearErr := strconv.Atoi(year)
+	q, quarterErr := strconv.Atoi(number)
+	if !ok || yearErr != nil || quarterErr != nil || q < 1 || q > 4 {
+		return time.Time{}, fmt.Errorf("invalid quarter %q: want e.g. 2025-Q3", quarter)
+	}
+	return time.Date(y, time.Month(q*3-2), 1, 0, 0, 0, 0, time.UTC), nil
+}
+
+// postRangeChart posts chart with its extremes, alt text and optional data table
+func (h *YearlyPosterHandler) postRangeChart(ctx context.Context, chart rangeChart) (Response, error) {
+	rangeData, err := h.dailySentimentManager.GetSentimentDataBetween(ctx, chart.chartRange.Start, chart.chartRange.End)
+	if err != nil {
+		log.Printf("Failed to get %s sentiment data: %v", chart.name, err)
+		return Response{
+			StatusCode: 500,
+			Body:       fmt.Sprintf("Failed to get %s sentiment data: %v", chart.name, err),
+		}, err
+	}
+	rangeData = chart.chartRange.Points(rangeData)
+
+	if len(rangeData) < rangeChartMinDays {
+		log.Printf("Insufficient sentiment data for %s chart (got %d days, need at least %d), skipping", chart.name, len(rangeData), rangeChartMinDays)
+		return Response{
+			StatusCode: 200,
+			Body:       fmt.Sprintf("Not enough data for %s chart: %d days", chart.name, len(rangeData)),
+			Posted:     false,
+		}, nil
+	}
+
+	// Bridge missing days as the yearly chart does; the alt text and extremes describe the
+	// measured days alone
+	chartData := rangeData
+	if h.isGapInterpolationEnabled(ctx) && chart.chartRange.Resolution == sparkline.ResolutionDaily {
+		chartData = state.InterpolateDailyGaps(rangeData)
+	}
+	imageData, err := chart.generator.GenerateYearlySentimentSparkline(chartData)
+	if err != nil {
+		log.Printf("Failed to generate %s sparkline: %v", chart.name, err)
+		return Response{
+			StatusCode: 500,
+			Body:       fmt.Sprintf("Failed to generate %s sparkline: %v", chart.name, err),
+		}, err
+	}
+
+	handle, password, err := h.getBlueskyCredentials(ctx)
+	if err != nil {
+		log.Printf("Failed to get Bluesky credentials: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to get credentials: " + err.Error(),
+		}, err
+	}
+
+	blueskyClient := h.newBlueskyClient(handle, password)
+	if err := blueskyClient.Authenticate(); err != nil {
+		log.Printf("Failed to authenticate with Bluesky: %v", err)
+		return Response{
+			StatusCode: 500,
+			Body:       "Failed to authenticate: " + err.Error(),
+		}, err
+	}
+
+	extremeMessage := h.analyzeRangeSentimentExtremes(rangeData, chart.average)
+
+	altTexts := h.altTextBuilder(ctx)
+	altParts := []string{chart.altText(altTexts, h.altTextStats(rangeData))}
+	headlines := h.extremeHeadlines(ctx, rangeData)
+	if len(headlines) > 0 {
+		altParts = append(altParts, "In the news on those days: "+strings.Join(headlines, "; ")+".")
+	}
+	altText := altTexts.Join(altParts...)
+
+	var dataTable []string
+	if h.isDataTableReplyEnabled(ctx) {
+		dataTable = formatter.FormatDataTable("📋 Chart data, daily average net sentiment:", dailyTableRows(rangeData))
+	}
+
+	// Format: "Bluesky Sentiment {start date} - {end date}, {period}"; the Wikipedia facets
+	// take the year of the "events" links from the start date
+	postText := fmt.Sprintf("Bluesky Sentiment %s - %s, %s",
+		rangeData[0].Timestamp.Format("2006-01-02"), rangeData[len(rangeData)-1].Timestamp.Format("2006-01-02"), chart.period)
+	if extremeMessage != "" {
+		postText += "\n\n" + extremeMessage
+	}
+	if len(headlines) > 0 {
+		if withHeadlines := postText + "\n\n" + strings.Join(headlines, "\n"); len([]rune(withHeadlines)) <= 300 {
+			postText = withHeadlines
+		}
+	}
+	postText = truncatePostText(postText)
+
+	var postURI, postCID string
+	if wikipediaFacets := client.CreateWikipediaLinkFacets(postText); len(wikipediaFacets) > 0 {
+		postURI, postCID, err = blueskyClient.PostWithImage(ctx, postText, imageData, altText, wikipediaFacets)
+	} else {
+		postURI, postCID, err = blueskyClient.PostWithImage(ctx, postText, imageData, altText)
+	}
+	if err != nil {
+		log.Printf("Failed to post %s sparkline: %v", chart.name, err)
+		return Response{
+			StatusCode: 500,
+			Body:       fmt.Sprintf("Failed to post %s sparkline: %v", chart.name, err),
+		}, err
+	}
+	interaction.Apply(ctx, h.ssmClient, blueskyClient, chart.poster, postURI)
+
+	if len(dataTable) > 0 {
+		if err := client.PostReplyChain(ctx, blueskyClient, postURI, postCID, postURI, postCID, dataTable); err != nil {
+			log.Printf("Failed to post chart data table: %v (chart was posted)", err)
+		} else {
+			log.Printf("Posted chart data table in %d replies", len(dataTable))
+		}
+	}
+
+	if !h.dryRun.ShadowAccount() {
+		pin.Rotate(ctx, h.ssmClient, h.stateManager, blueskyClient, chart.pinKind, postURI, postCID)
+	}
+
+	log.Printf("Successfully posted %s sentiment chart with %d days of data", chart.name, len(rangeData))
+	return Response{
+		StatusCode: 200,
+		Body:       fmt.Sprintf("%s sentiment chart posted successfully", chart.name),
+		Posted:     true,
+	}, nil
+}
+
+// analyzeRangeSentimentExtremes describes a range chart's days: where the latest day sits
+// against the range's average, the lowest and highest days, and the sharpest change from
+// one measured day to the next
+func (h *YearlyPosterHandler) analyzeRangeSentimentExtremes(dataPoints []state.YearlySparklineDataPoint, average string) string {
+	if len(dataPoints) < rangeChartMinDays {
+		return ""
+	}
+
+	stats := h.calculateYearlySentimentStats(dataPoints)
+	var insights []string
+
+	// A month or quarter moves less than a year, so a smaller margin counts as notable
+	if stats.Current > stats.Average+3 {
+		insights = append(insights, "Currently above "+average)
+	} else if stats.Current < stats.Average-3 {
+		insights = append(insights, "Currently below "+average)
+	}
+
+	// The date + "events" text is linked to Wikipedia via facets
+	insights = append(insights, strings.TrimSpace(fmt.Sprintf("Lowest: %.1f%% %s", stats.Lowest, eventsLinkText(stats.LowestDate))))
+	insights = append(insights, strings.TrimSpace(fmt.Sprintf("Highest: %.1f%% %s", stats.Highest, eventsLinkText(stats.HighestDate))))
+
+	var swing float64
+	var swingDate string
+	for i := 1; i < len(dataPoints); i++ {
+		if change := dataPoints[i].AverageSentiment - dataPoints[i-1].AverageSentiment; math.Abs(change) > math.Abs(swing) {
+			swing = change
+			swingDate = dataPoints[i].Date
+		}
+	}
+	if date, err := time.Parse("2006-01-02", swingDate); err == nil {
+		insights = append(insights, fmt.Sprintf("Biggest daily swing: %+.1f points on %s", swing, date.Format("Jan 2")))
+	}
+
+	return strings.Join(insights, "\n")
+}
+
+// eventsLinkText formats a YYYY-MM-DD date as "Sep 18 events", the text the Wikipedia
+// facets link; a malformed date is left out
+func eventsLinkText(date string) string {
+	parsed, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return ""
+	}
+	return parsed.Format("Jan 2") + " events"
+}
+
+// dailyTableRows lists the daily series one row per day, oldest first
+func dailyTableRows(dataPoints []state.YearlySparklineDataPoint) []formatter.TableRow {
+	rows := make([]formatter.TableRow, len(dataPoints))
+	for i, point := range dataPoints {
+		rows[i] = formatter.TableRow{Label: point.Timestamp.Format("Jan 2"), Value: point.AverageSentiment}
+	}
+	return rows
+}
@@ -9,10 +9,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
 	"github.com/christophergentle/hourstats-bsky/internal/analyzer"
 	bskyclient "github.com/christophergentle/hourstats-bsky/internal/client"
 	"github.com/christophergentle/hourstats-bsky/internal/config"
 	"github.com/christophergentle/hourstats-bsky/internal/formatter"
+	"github.com/christophergentle/hourstats-bsky/internal/interval"
+	"github.com/christophergentle/hourstats-bsky/internal/provenance"
 	"github.com/christophergentle/hourstats-bsky/internal/state"
 )
 
@@ -22,22 +25,6 @@ type MockLambdaClient struct {
 	superDebugMode bool
 }
 
-// MockFetcherEvent represents the event for the fetcher lambda
-type MockFetcherEvent struct {
-	RunID                   string `json:"runId"`
-	AnalysisIntervalMinutes int    `json:"analysisIntervalMinutes"`
-	Status                  string `json:"status"`
-	MaxIterations           int    `json:"maxIterations"`
-	Cursor                  string `json:"cursor,omitempty"`
-}
-
-// MockProcessorEvent represents the event for the processor lambda
-type MockProcessorEvent struct {
-	RunID                   string `json:"runId"`
-	AnalysisIntervalMinutes int    `json:"analysisIntervalMinutes"`
-	Status                  string `json:"status"`
-}
-
 func main() {
 	if len(os.Args) < 2 {
 		fmt.Println("Usage: go run cmd/local-test/main.go <test-interval-minutes> [live] [super-debug]")
@@ -71,8 +58,8 @@ func main() {
 		log.Fatalf("Invalid interval: %v", err)
 	}
 
-	if testIntervalMinutes < 1 || testIntervalMinutes > 60 {
-		log.Fatalf("Test interval must be between 1 and 60 minutes")
+	if err := interval.Validate(testIntervalMinutes); err != nil {
+		log.Fatalf("Invalid test interval: %v", err)
 	}
 
 	// Check for live mode and super debug mode
@@ -201,7 +188,7 @@ func (m *MockLambdaClient) runFetcherChain(ctx context.Context, runID string, li
 	}
 
 	// Get run state
-	runState, err := m.stateManager.GetRun(ctx, runID, "orchestrator")
+	runState, err := m.stateManager.GetRun(ctx, runID, "orchestrator", state.StronglyConsistent)
 	if err != nil {
 		return fmt.Errorf("failed to get run state: %w", err)
 	}
@@ -226,7 +213,7 @@ func (m *MockLambdaClient) runFetcherChain(ctx context.Context, runID string, li
 // runProcessor simulates the processor execution
 func (m *MockLambdaClient) runProcessor(ctx context.Context, runID string, analysisIntervalMinutes int, liveMode bool) error {
 	// Get all posts for this run
-	allPosts, err := m.stateManager.GetAllPosts(ctx, runID)
+	allPosts, err := m.stateManager.GetAllPosts(ctx, runID, state.StronglyConsistent)
 	if err != nil {
 		return fmt.Errorf("failed to get all posts: %w", err)
 	}
@@ -327,7 +314,8 @@ func (m *MockLambdaClient) runProcessor(ctx context.Context, runID string, analy
 	}
 
 	// Update run state with top posts
-	err = m.stateManager.SetAnalysisComplete(ctx, runID, overallSentiment, topPosts)
+	record := provenance.New(analyzer.Version, sentimentAnalyzer.LexiconHash(), provenance.Scoring{Method: aggregation.DefaultMethod, Threshold: aggregation.DefaultThreshold})
+	err = m.stateManager.SetAnalysisComplete(ctx, runID, overallSentiment, netSentimentPercentage, aggregation.DefaultMethod, record, topPosts)
 	if err != nil {
 		return fmt.Errorf("failed to set top posts: %w", err)
 	}
@@ -383,7 +371,7 @@ func (m *MockLambdaClient) runProcessor(ctx context.Context, runID string, analy
 	}
 
 	// Update run state to processor step
-	runState, err := m.stateManager.GetRun(ctx, runID, "orchestrator")
+	runState, err := m.stateManager.GetRun(ctx, runID, "orchestrator", state.StronglyConsistent)
 	if err != nil {
 		return fmt.Errorf("failed to get run state: %w", err)
 	}
@@ -403,7 +391,7 @@ func (m *MockLambdaClient) runProcessor(ctx context.Context, runID string, analy
 // showResults displays the test results
 func (m *MockLambdaClient) showResults(ctx context.Context, runID string) error {
 	// Get run stats
-	stats, err := m.stateManager.GetRunStats(ctx, runID)
+	stats, err := m.stateManager.GetRunStats(ctx, runID, state.StronglyConsistent)
 	if err != nil {
 		return fmt.Errorf("failed to get run stats: %w", err)
 	}
@@ -457,14 +445,7 @@ func (m *MockLambdaClient) calculateOverallSentimentWithCompoundScores(posts []a
 	averageCompoundScore := totalCompoundScore / float64(len(posts))
 
 	// Map compound score to category for backward compatibility
-	var sentimentCategory string
-	if averageCompoundScore >= 0.3 {
-		sentimentCategory = "positive"
-	} else if averageCompoundScore <= -0.3 {
-		sentimentCategory = "negative"
-	} else {
-		sentimentCategory = "neutral"
-	}
+	sentimentCategory := aggregation.Categorize(averageCompoundScore, aggregation.DefaultThreshold)
 
 	// Scale to percentage range for 100-word system
 	netSentimentPercentage := averageCompoundScore * 100.0
@@ -142,14 +142,18 @@ func main() {
 	log.Printf("Alt text: %s", altText)
 
 	// Check if this is a dry run
-	if cfg.Settings.DryRun {
-		log.Println("⚠️  DRY RUN MODE - Not posting to Bluesky")
-		log.Println("Set dry_run: false in config.yaml or unset DRY_RUN env var to post")
+	if !cfg.Settings.DryRun.Posts() {
+		log.Printf("⚠️  DRY RUN MODE (%s) - Not posting to Bluesky", cfg.Settings.DryRun)
+		log.Println("Set dry_run: off in config.yaml or unset DRY_RUN env var to post")
 		return
 	}
 
-	// Initialize Bluesky client
-	blueskyClient := client.New(cfg.Bluesky.Handle, cfg.Bluesky.Password)
+	// Initialize Bluesky client, for the shadow account during a shadow dry run
+	account := cfg.Bluesky
+	if cfg.Settings.DryRun.ShadowAccount() {
+		account = cfg.Shadow
+	}
+	blueskyClient := client.New(account.Handle, account.Password)
 
 	// Authenticate
 	if err := blueskyClient.Authenticate(); err != nil {
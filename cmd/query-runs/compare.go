@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// runSnapshot is everything compared between two runs
+type runSnapshot struct {
+	State *state.RunState
+	Stats *state.RunStats
+}
+
+// comparisonRow is one line of the side-by-side comparison
+type comparisonRow struct {
+	Label string
+	A     string
+	B     string
+	Delta string
+}
+
+// compareRuns prints two runs side by side: post counts, sentiment, coverage, timing,
+// and how many top posts they share
+func compareRuns(ctx context.Context, stateManager *state.StateManager, runA, runB string) {
+	fmt.Printf("🔀 Comparing runs:\n  A: %s\n  B: %s\n\n", runA, runB)
+
+	a := loadSnapshot(ctx, stateManager, runA)
+	b := loadSnapshot(ctx, stateManager, runB)
+
+	fmt.Printf("  %-24s %-20s %-20s %s\n", "", "A", "B", "Δ (B - A)")
+	fmt.Println("  " + strings.Repeat("─", 76))
+	for _, row := range buildComparison(a, b) {
+		fmt.Printf("  %-24s %-20s %-20s %s\n", row.Label, row.A, row.B, row.Delta)
+	}
+	fmt.Println()
+
+	printTopPostOverlap(a.State.TopPosts, b.State.TopPosts)
+}
+
+func loadSnapshot(ctx context.Context, stateManager *state.StateManager, runID string) runSnapshot {
+	runState, err := stateManager.GetLatestRun(ctx, runID)
+	if err != nil {
+		log.Fatalf("Failed to get run state for %s: %v", runID, err)
+	}
+	stats, err := stateManager.GetRunStats(ctx, runID, state.EventuallyConsistent)
+	if err != nil {
+		log.Fatalf("Failed to get run stats for %s: %v", runID, err)
+	}
+	return runSnapshot{State: runState, Stats: stats}
+}
+
+// buildComparison lays out the comparable fields of two runs
+func buildComparison(a, b runSnapshot) []comparisonRow {
+	rows := []comparisonRow{
+		{Label: "Status", A: a.State.Status, B: b.State.Status},
+		{Label: "Interval (min)", A: fmt.Sprint(a.State.AnalysisIntervalMinutes), B: fmt.Sprint(b.State.AnalysisIntervalMinutes),
+			Delta: intDelta(a.State.AnalysisIntervalMinutes, b.State.AnalysisIntervalMinutes)},
+		{Label: "Posts retrieved", A: fmt.Sprint(a.State.TotalPostsRetrieved), B: fmt.Sprint(b.State.TotalPostsRetrieved),
+			Delta: intDelta(a.State.TotalPostsRetrieved, b.State.TotalPostsRetrieved)},
+		{Label: "Posts in DB", A: fmt.Sprint(a.Stats.ActualPostsCount), B: fmt.Sprint(b.Stats.ActualPostsCount),
+			Delta: intDelta(a.Stats.ActualPostsCount, b.Stats.ActualPostsCount)},
+		{Label: "Sentiment", A: orNA(a.State.OverallSentiment), B: orNA(b.State.OverallSentiment)},
+		{Label: "Net sentiment", A: fmt.Sprintf("%.1f%%", a.State.NetSentimentPercentage), B: fmt.Sprintf("%.1f%%", b.State.NetSentimentPercentage),
+			Delta: fmt.Sprintf("%+.1f pts", b.State.NetSentimentPercentage-a.State.NetSentimentPercentage)},
+		{Label: "Window coverage", A: fmt.Sprintf("%.1f%%", a.Stats.CoveragePercent), B: fmt.Sprintf("%.1f%%", b.Stats.CoveragePercent),
+			Delta: fmt.Sprintf("%+.1f pts", b.Stats.CoveragePercent-a.Stats.CoveragePercent)},
+		{Label: "Fetch checkpoints", A: fmt.Sprint(a.State.FetchCheckpoints), B: fmt.Sprint(b.State.FetchCheckpoints),
+			Delta: intDelta(a.State.FetchCheckpoints, b.State.FetchCheckpoints)},
+	}
+
+	for _, step := range []string{state.StepFetch, state.StepAnalyze, state.StepAggregate, state.StepPost} {
+		durationA, okA := stepDuration(a.State, step)
+		durationB, okB := stepDuration(b.State, step)
+		row := comparisonRow{Label: step + " time", A: durationOrNA(durationA, okA), B: durationOrNA(durationB, okB)}
+		if okA && okB {
+			row.Delta = signedDuration(durationB - durationA)
+		}
+		rows = append(rows, row)
+	}
+
+	totalA := a.State.UpdatedAt.Sub(a.State.CreatedAt)
+	totalB := b.State.UpdatedAt.Sub(b.State.CreatedAt)
+	rows = append(rows, comparisonRow{
+		Label: "Total time",
+		A:     totalA.Round(time.Second).String(),
+		B:     totalB.Round(time.Second).String(),
+		Delta: signedDuration(totalB - totalA),
+	})
+
+	return rows
+}
+
+// topPostOverlap returns the URIs present in both top post lists, in A's order
+func topPostOverlap(a, b []state.Post) []string {
+	inB := make(map[string]bool, len(b))
+	for _, post := range b {
+		inB[post.URI] = true
+	}
+
+	var shared []string
+	for _, post := range a {
+		if inB[post.URI] {
+			shared = append(shared, post.URI)
+		}
+	}
+	return shared
+}
+
+func printTopPostOverlap(a, b []state.Post) {
+	shared := topPostOverlap(a, b)
+	sharedSet := make(map[string]bool, len(shared))
+	for _, uri := range shared {
+		sharedSet[uri] = true
+	}
+
+	fmt.Printf("🏆 Top Posts: %d shared (A has %d, B has %d)\n", len(shared), len(a), len(b))
+	rows := len(a)
+	if len(b) > rows {
+		rows = len(b)
+	}
+	for i := 0; i < rows; i++ {
+		fmt.Printf("  %d. %-36s %s\n", i+1, topPostLabel(a, i, sharedSet), topPostLabel(b, i, sharedSet))
+	}
+	fmt.Println("  (* = in both runs)")
+}
+
+func topPostLabel(posts []state.Post, i int, shared map[string]bool) string {
+	if i >= len(posts) {
+		return "-"
+	}
+	marker := " "
+	if shared[posts[i].URI] {
+		marker = "*"
+	}
+	return fmt.Sprintf("%s@%s (%.0f)", marker, truncateAuthor(posts[i].Author, 24), posts[i].EngagementScore)
+}
+
+func stepDuration(runState *state.RunState, step string) (time.Duration, bool) {
+	for _, timing := range runState.StepTimings {
+		if timing.Step == step {
+			return timing.Duration(), true
+		}
+	}
+	return 0, false
+}
+
+func intDelta(a, b int) string {
+	return fmt.Sprintf("%+d", b-a)
+}
+
+func signedDuration(d time.Duration) string {
+	d = d.Round(100 * time.Millisecond)
+	if d >= 0 {
+		return "+" + d.String()
+	}
+	return d.String()
+}
+
+func durationOrNA(d time.Duration, ok bool) string {
+	if !ok {
+		return "N/A"
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+func orNA(s string) string {
+	if s == "" {
+		return "N/A"
+	}
+	return s
+}
+
+func truncateAuthor(author string, maxLen int) string {
+	if len(author) <= maxLen {
+		return author
+	}
+	return author[:maxLen-3] + "..."
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func TestTopPostOverlap(t *testing.T) {
+	a := []state.Post{{URI: "at://a/1"}, {URI: "at://a/2"}, {URI: "at://a/3"}}
+	b := []state.Post{{URI: "at://a/3"}, {URI: "at://b/9"}, {URI: "at://a/1"}}
+
+	shared := topPostOverlap(a, b)
+	if len(shared) != 2 || shared[0] != "at://a/1" || shared[1] != "at://a/3" {
+		t.Errorf("Expected [at://a/1 at://a/3], got %v", shared)
+	}
+}
+
+func TestBuildComparison(t *testing.T) {
+	start := time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC)
+	a := runSnapshot{
+		State: &state.RunState{
+			TotalPostsRetrieved:    4000,
+			NetSentimentPercentage: 5.0,
+			CreatedAt:              start,
+			UpdatedAt:              start.Add(10 * time.Minute),
+			StepTimings:            []state.StepTiming{{Step: state.StepFetch, DurationMs: 480000}},
+		},
+		Stats: &state.RunStats{CoveragePercent: 90},
+	}
+	b := runSnapshot{
+		State: &state.RunState{
+			TotalPostsRetrieved:    4500,
+			NetSentimentPercentage: 3.5,
+			CreatedAt:              start,
+			UpdatedAt:              start.Add(8 * time.Minute),
+			StepTimings:            []state.StepTiming{{Step: state.StepFetch, DurationMs: 360000}},
+		},
+		Stats: &state.RunStats{CoveragePercent: 100},
+	}
+
+	rows := make(map[string]comparisonRow)
+	for _, row := range buildComparison(a, b) {
+		rows[row.Label] = row
+	}
+
+	expected := map[string]string{
+		"Posts retrieved": "+500",
+		"Net sentiment":   "-1.5 pts",
+		"Window coverage": "+10.0 pts",
+		"fetch time":      "-2m0s",
+		"Total time":      "-2m0s",
+		"post time":       "",
+	}
+	for label, delta := range expected {
+		if rows[label].Delta != delta {
+			t.Errorf("%s delta: expected %q, got %q", label, delta, rows[label].Delta)
+		}
+	}
+	if rows["post time"].A != "N/A" {
+		t.Errorf("Expected N/A for unrecorded step, got %q", rows["post time"].A)
+	}
+}
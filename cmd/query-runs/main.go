@@ -9,8 +9,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
 	"github.com/christophergentle/hourstats-bsky/internal/analyzer"
 	"github.com/christophergentle/hourstats-bsky/internal/formatter"
+	"github.com/christophergentle/hourstats-bsky/internal/provenance"
 	"github.com/christophergentle/hourstats-bsky/internal/state"
 )
 
@@ -20,6 +22,11 @@ func main() {
 		runID       = flag.String("run", "", "Run ID to analyze")
 		limit       = flag.Int("limit", 10, "Limit number of runs to list")
 		showDetails = flag.Bool("details", false, "Show detailed run information")
+		compare     = flag.Bool("compare", false, "Compare two runs side by side: -compare <runA> <runB>")
+		sentiment   = flag.String("sentiment", "", "With -run, list the run's stored analyzed posts of this sentiment (positive, negative, neutral)")
+		showSource  = flag.Bool("provenance", false, "With -run, print the analyzer, lexicons, scoring and build that produced the run's sentiment")
+		channelID   = flag.String("channel", "", "With -list, list this channel's runs instead of the default channel's")
+		backfill    = flag.Bool("backfill-run-index", false, "Add runs stored before the run listing index to it")
 	)
 	flag.Parse()
 
@@ -31,25 +38,57 @@ func main() {
 		log.Fatalf("Failed to create state manager: %v", err)
 	}
 
+	if *compare {
+		if flag.NArg() != 2 {
+			fmt.Println("Usage: go run ./cmd/query-runs -compare <runA> <runB>")
+			os.Exit(1)
+		}
+		compareRuns(ctx, stateManager, flag.Arg(0), flag.Arg(1))
+		return
+	}
+
+	if *backfill {
+		updated, err := stateManager.BackfillRunIndex(ctx)
+		if err != nil {
+			log.Fatalf("Failed to backfill the run index: %v", err)
+		}
+		fmt.Printf("✅ Added %d runs to the run index\n", updated)
+		return
+	}
+
 	if *listRuns {
-		listAllRuns(ctx, stateManager, *limit, *showDetails)
+		listAllRuns(ctx, stateManager, *channelID, *limit, *showDetails)
 		return
 	}
 
 	if *runID == "" {
 		fmt.Println("Usage:")
-		fmt.Println("  List runs:    go run cmd/query-runs/main.go -list [-limit=10] [-details]")
-		fmt.Println("  Analyze run:  go run cmd/query-runs/main.go -run <runID>")
+		fmt.Println("  List runs:    go run ./cmd/query-runs -list [-limit=10] [-details] [-channel=<id>]")
+		fmt.Println("  Analyze run:  go run ./cmd/query-runs -run <runID>")
+		fmt.Println("  Compare runs: go run ./cmd/query-runs -compare <runA> <runB>")
+		fmt.Println("  Post cohort:  go run ./cmd/query-runs -run <runID> -sentiment negative [-limit=10]")
+		fmt.Println("  Provenance:   go run ./cmd/query-runs -run <runID> -provenance")
+		fmt.Println("  Backfill:     go run ./cmd/query-runs -backfill-run-index")
 		os.Exit(1)
 	}
 
+	if *showSource {
+		printProvenance(ctx, stateManager, *runID)
+		return
+	}
+
+	if *sentiment != "" {
+		listPostsBySentiment(ctx, stateManager, *runID, *sentiment, *limit)
+		return
+	}
+
 	analyzeRun(ctx, stateManager, *runID)
 }
 
-func listAllRuns(ctx context.Context, stateManager *state.StateManager, limit int, showDetails bool) {
+func listAllRuns(ctx context.Context, stateManager *state.StateManager, channelID string, limit int, showDetails bool) {
 	fmt.Printf("📋 Listing last %d runs:\n\n", limit)
 
-	runIDs, err := stateManager.ListRuns(ctx, int32(limit))
+	runIDs, err := stateManager.ListChannelRuns(ctx, channelID, int32(limit))
 	if err != nil {
 		log.Fatalf("Failed to list runs: %v", err)
 	}
@@ -59,13 +98,21 @@ func listAllRuns(ctx context.Context, stateManager *state.StateManager, limit in
 		return
 	}
 
+	var allStats map[string]*state.RunStats
+	if showDetails {
+		allStats, err = stateManager.BatchGetRunStats(ctx, runIDs)
+		if err != nil {
+			log.Fatalf("Failed to get run stats: %v", err)
+		}
+	}
+
 	for i, runID := range runIDs {
 		fmt.Printf("%d. %s", i+1, runID)
 
 		if showDetails {
-			stats, err := stateManager.GetRunStats(ctx, runID)
-			if err != nil {
-				fmt.Printf(" (error getting stats: %v)", err)
+			stats, ok := allStats[runID]
+			if !ok {
+				fmt.Printf(" (stats unavailable)")
 			} else {
 				// Calculate duration and format times
 				duration := stats.UpdatedAt.Sub(stats.CutoffTime)
@@ -85,11 +132,60 @@ func listAllRuns(ctx context.Context, stateManager *state.StateManager, limit in
 	}
 }
 
+// listPostsBySentiment prints a run's most engaging stored posts of one sentiment
+// Posts are only stored when /hourstats/settings/store_analyzed_posts is enabled
+func listPostsBySentiment(ctx context.Context, stateManager *state.StateManager, runID, sentiment string, limit int) {
+	posts, err := stateManager.GetPostsBySentiment(ctx, runID, sentiment, int32(limit))
+	if err != nil {
+		log.Fatalf("Failed to get %s posts: %v", sentiment, err)
+	}
+
+	if len(posts) == 0 {
+		fmt.Printf("No stored %s posts for %s (is store_analyzed_posts enabled?)\n", sentiment, runID)
+		return
+	}
+
+	fmt.Printf("🗂️ Top %d %s posts in %s:\n\n", len(posts), sentiment, runID)
+	for i, post := range posts {
+		fmt.Printf("%d. @%s (engagement %.1f, compound %.2f)\n   %s\n   %s\n",
+			i+1, post.Author, post.EngagementScore, post.SentimentScore, strings.ReplaceAll(post.Text, "\n", " "), post.URI)
+	}
+}
+
+// printProvenance prints what produced a run's sentiment and how it differs from this build
+func printProvenance(ctx context.Context, stateManager *state.StateManager, runID string) {
+	run, err := stateManager.GetRun(ctx, runID, "orchestrator", state.EventuallyConsistent)
+	if err != nil {
+		log.Fatalf("Failed to get run: %v", err)
+	}
+
+	fmt.Printf("🧾 Provenance of %s (net sentiment %+.1f%%, %s):\n", runID, run.NetSentimentPercentage, run.OverallSentiment)
+	if run.Provenance == nil {
+		fmt.Println("  Not recorded; the run was analyzed before provenance was kept")
+		return
+	}
+	for _, line := range run.Provenance.Lines() {
+		fmt.Printf("  %s\n", line)
+	}
+
+	// Compare the scoring inputs with this build's; the scoring settings are the run's own
+	current := provenance.New(analyzer.Version, analyzer.New().LexiconHash(), run.Provenance.Scoring)
+	changes := run.Provenance.Changes(current)
+	if len(changes) == 0 {
+		fmt.Println("\n✅ Matches this build")
+		return
+	}
+	fmt.Println("\n⚠️  Differs from this build:")
+	for _, change := range changes {
+		fmt.Printf("  %s\n", change)
+	}
+}
+
 func analyzeRun(ctx context.Context, stateManager *state.StateManager, runID string) {
 	fmt.Printf("🔍 Analyzing run: %s\n\n", runID)
 
 	// Get run stats
-	stats, err := stateManager.GetRunStats(ctx, runID)
+	stats, err := stateManager.GetRunStats(ctx, runID, state.EventuallyConsistent)
 	if err != nil {
 		log.Fatalf("Failed to get run stats: %v", err)
 	}
@@ -115,16 +211,29 @@ func analyzeRun(ctx context.Context, stateManager *state.StateManager, runID str
 	fmt.Printf("  Cutoff Time (UTC): %s\n", stats.CutoffTime.Format("2006-01-02 15:04:05 UTC"))
 	fmt.Printf("  Total Posts Retrieved: %d\n", stats.TotalPostsRetrieved)
 	fmt.Printf("  Actual Posts in DB: %d\n", stats.ActualPostsCount)
+	fmt.Printf("  Window Coverage: %.1f%%\n", stats.CoveragePercent)
 	fmt.Printf("  Created: %s\n", createdTime.Format("2006-01-02 15:04:05"))
 	fmt.Printf("  Updated: %s\n", updatedTime.Format("2006-01-02 15:04:05"))
 	if stats.OverallSentiment != "" {
 		fmt.Printf("  Overall Sentiment: %s\n", stats.OverallSentiment)
 	}
 	fmt.Printf("  Top Posts Count: %d\n", stats.TopPostsCount)
+	if stats.Media != nil {
+		fmt.Printf("  Posts With Media: %d (%.1f%%)\n", stats.Media.PostsWithMedia, stats.Media.PostsWithMediaPercent)
+		fmt.Printf("  Top Posts With Media: %d (%.0f%%)\n", stats.Media.TopPostsWithMedia, stats.Media.TopPostsWithMediaPercent)
+	}
+	if stats.SkipReason != "" {
+		fmt.Printf("  Skip Reason: %s\n", stats.SkipReason)
+	}
+	for _, skipped := range stats.SkippedPosts {
+		fmt.Printf("  Skipped %s post at %s: %s\n", skipped.Poster, skipped.At.Format("2006-01-02 15:04:05 UTC"), skipped.Reason)
+	}
 	fmt.Println()
 
+	printTimeline(stats.StepTimings)
+
 	// Get all posts for this run
-	posts, err := stateManager.GetAllPosts(ctx, runID)
+	posts, err := stateManager.GetAllPosts(ctx, runID, state.EventuallyConsistent)
 	if err != nil {
 		log.Fatalf("Failed to get posts: %v", err)
 	}
@@ -234,6 +343,7 @@ func analyzePosts(posts []state.Post) ([]state.Post, string, float64, error) {
 			Reposts:   post.Reposts,
 			Replies:   post.Replies,
 			CreatedAt: post.CreatedAt,
+			Langs:     post.Langs,
 		}
 	}
 
@@ -279,14 +389,7 @@ func calculateOverallSentimentWithCompoundScores(posts []analyzer.AnalyzedPost)
 	averageCompoundScore := totalCompoundScore / float64(len(posts))
 
 	// Map compound score to category for backward compatibility
-	var sentimentCategory string
-	if averageCompoundScore >= 0.3 {
-		sentimentCategory = "positive"
-	} else if averageCompoundScore <= -0.3 {
-		sentimentCategory = "negative"
-	} else {
-		sentimentCategory = "neutral"
-	}
+	sentimentCategory := aggregation.Categorize(averageCompoundScore, aggregation.DefaultThreshold)
 
 	// Scale to percentage range for 100-word system
 	netSentimentPercentage := averageCompoundScore * 100.0
@@ -347,3 +450,42 @@ func deduplicatePostsByURI(posts []state.Post) []state.Post {
 
 	return deduplicatedPosts
 }
+
+// printTimeline prints each recorded pipeline step with its offset from the first step,
+// its duration, and a bar scaled to the longest step so slow steps stand out
+func printTimeline(timings []state.StepTiming) {
+	if len(timings) == 0 {
+		fmt.Println("⏱️  Step Timeline: not recorded for this run")
+		fmt.Println()
+		return
+	}
+
+	const barWidth = 30
+	runStart := timings[0].StartedAt
+	var longest int64
+	for _, timing := range timings {
+		if timing.DurationMs > longest {
+			longest = timing.DurationMs
+		}
+	}
+	total := timings[len(timings)-1].EndedAt.Sub(runStart)
+
+	fmt.Printf("⏱️  Step Timeline:\n")
+	for _, timing := range timings {
+		bar := 1
+		if longest > 0 {
+			bar = int(timing.DurationMs * barWidth / longest)
+			if bar < 1 {
+				bar = 1
+			}
+		}
+		fmt.Printf("  %-10s %-10s +%-8s %9s  %s\n",
+			timing.Step,
+			timing.Status,
+			timing.StartedAt.Sub(runStart).Round(time.Second),
+			timing.Duration().Round(100*time.Millisecond),
+			strings.Repeat("█", bar))
+	}
+	fmt.Printf("  Total: %s\n", total.Round(time.Second))
+	fmt.Println()
+}
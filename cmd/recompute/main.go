@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/analyzer"
+	"github.com/christophergentle/hourstats-bsky/internal/blocklist"
+	"github.com/christophergentle/hourstats-bsky/internal/recompute"
+	"github.com/christophergentle/hourstats-bsky/internal/sparkline"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+const dateLayout = "2006-01-02"
+
+func main() {
+	var (
+		from      = flag.String("from", "", "First day of runs to re-score, YYYY-MM-DD (UTC)")
+		to        = flag.String("to", "", "Day after the last day of runs to re-score, YYYY-MM-DD (default: now)")
+		series    = flag.String("series", recompute.DefaultSeries, "History series the re-scored points are written to")
+		channelID = flag.String("channel", "", "Channel whose runs are re-scored (default: the default channel)")
+		dryRun    = flag.Bool("dry-run", false, "Print the re-scored values without writing them")
+		chartPath = flag.String("chart", "", "Write a PNG chart of the live and recomputed series over the range to this file")
+	)
+	flag.Parse()
+
+	if *from == "" || *series == "" {
+		fmt.Println("Usage:")
+		fmt.Println("  Re-score runs:  go run ./cmd/recompute -from 2025-09-01 [-to 2025-09-08] [-series v2] [-channel <id>] [-dry-run]")
+		fmt.Println("  Compare series: go run ./cmd/recompute -from 2025-09-01 -chart compare.png [-dry-run]")
+		os.Exit(1)
+	}
+	start, err := time.Parse(dateLayout, *from)
+	if err != nil {
+		log.Fatalf("Invalid -from: %v", err)
+	}
+	end := time.Now().UTC()
+	if *to != "" {
+		if end, err = time.Parse(dateLayout, *to); err != nil {
+			log.Fatalf("Invalid -to: %v", err)
+		}
+	}
+	if !start.Before(end) {
+		log.Fatalf("-from %s must be before -to %s", start.Format(dateLayout), end.Format(dateLayout))
+	}
+
+	ctx := context.Background()
+
+	stateManager, err := state.NewStateManager(ctx, "hourstats-state")
+	if err != nil {
+		log.Fatalf("Failed to create state manager: %v", err)
+	}
+	liveHistory, err := state.NewSentimentHistoryManager(ctx, "hourstats-sentiment-history")
+	if err != nil {
+		log.Fatalf("Failed to create sentiment history manager: %v", err)
+	}
+	liveHistory.SetChannel(*channelID)
+	seriesHistory, err := state.NewSentimentHistoryManager(ctx, "hourstats-sentiment-history")
+	if err != nil {
+		log.Fatalf("Failed to create sentiment history manager: %v", err)
+	}
+	seriesHistory.SetChannel(*channelID)
+	seriesHistory.SetSeries(*series)
+
+	// Points are stamped a little after their run's window ends, so read a day past the range
+	since := time.Since(start)
+	live, err := liveHistory.GetSentimentHistory(ctx, since)
+	if err != nil {
+		log.Fatalf("Failed to get sentiment history: %v", err)
+	}
+	live = pointsBefore(live, end.Add(24*time.Hour))
+
+	runs, err := stateManager.GetRecentChannelRuns(ctx, *channelID, since)
+	if err != nil {
+		log.Fatalf("Failed to get runs: %v", err)
+	}
+
+	// Runs leave out blocked posts as the processor does, by the blocklist as it is now
+	blocklistManager, err := state.NewBlocklistManager(ctx, "hourstats-blocklist")
+	if err != nil {
+		log.Fatalf("Failed to create blocklist manager: %v", err)
+	}
+	entries, err := blocklistManager.ListEntries(ctx)
+	if err != nil {
+		log.Fatalf("Failed to get blocklist: %v", err)
+	}
+
+	written := rescore(ctx, stateManager, seriesHistory, runs, live, blocklist.New(entries), start, end, *dryRun)
+
+	if *chartPath != "" {
+		recomputed, err := seriesHistory.GetSentimentHistory(ctx, since)
+		if err != nil {
+			log.Fatalf("Failed to get the %s series: %v", *series, err)
+		}
+		// A dry run's points aren't stored, so chart them from memory
+		if *dryRun {
+			recomputed = written
+		}
+		if err := writeChart(*chartPath, pointsBetween(live, start, end), pointsBetween(recomputed, start, end), *series); err != nil {
+			log.Fatalf("Failed to write chart: %v", err)
+		}
+		fmt.Printf("🖼️  Wrote %s\n", *chartPath)
+	}
+}
+
+// rescore re-scores the runs whose windows start in [start, end), oldest first, printing
+// each run's change and writing its point to the series unless dryRun. It returns the
+// re-scored points. Runs whose posts have expired are reported and skipped.
+func rescore(ctx context.Context, stateManager *state.StateManager, seriesHistory *state.SentimentHistoryManager, runs []state.RunState, live []state.SentimentDataPoint, blocked *blocklist.List, start, end time.Time, dryRun bool) []state.SentimentDataPoint {
+	timestamps := make(map[string]time.Time, len(live))
+	for _, point := range live {
+		timestamps[point.RunID] = point.Timestamp
+	}
+
+	sentimentAnalyzer := analyzer.New()
+	fmt.Printf("🔁 Re-scoring runs from %s to %s with analyzer version %d, lexicons %s\n\n",
+		start.Format(dateLayout), end.Format(dateLayout), analyzer.Version, sentimentAnalyzer.LexiconHash())
+	fmt.Printf("%-36s %-10s %8s %8s %8s\n", "RUN", "SENTIMENT", "LIVE", "NEW", "CHANGE")
+
+	var points []state.SentimentDataPoint
+	var skipped int
+	for i := len(runs) - 1; i >= 0; i-- {
+		run := runs[i]
+		if run.CutoffTime.Before(start) || !run.CutoffTime.Before(end) || run.OverallSentiment == "" {
+			continue // Outside the range, or never analyzed
+		}
+
+		posts, err := stateManager.GetAllPosts(ctx, run.RunID, state.EventuallyConsistent)
+		if err != nil {
+			log.Printf("Skipping %s: failed to get posts: %v", run.RunID, err)
+			skipped++
+			continue
+		}
+		timestamp, ok := timestamps[run.RunID]
+		if !ok {
+			timestamp = run.WindowEnd()
+		}
+		result, err := recompute.Run(sentimentAnalyzer, run, posts, blocked, timestamp)
+		if err != nil {
+			log.Printf("Skipping %s: %v", run.RunID, err)
+			skipped++
+			continue
+		}
+
+		fmt.Printf("%-36s %-10s %7.1f%% %7.1f%% %+7.1f%%\n", run.RunID, result.Point.SentimentCategory,
+			result.Previous, result.Point.NetSentimentPercent, result.Delta())
+		if !dryRun {
+			if err := seriesHistory.StoreSentimentData(ctx, result.Point); err != nil {
+				log.Printf("Skipping %s: %v", run.RunID, err)
+				skipped++
+				continue
+			}
+		}
+		points = append(points, result.Point)
+	}
+
+	action := "Wrote"
+	if dryRun {
+		action = "Dry run, would write"
+	}
+	fmt.Printf("\n✅ %s %d points, skipped %d runs\n", action, len(points), skipped)
+	return points
+}
+
+// writeChart renders the live and recomputed series to a PNG file
+func writeChart(path string, live, recomputed []state.SentimentDataPoint, series string) error {
+	image, err := sparkline.NewSparklineGenerator(nil).GenerateRecomputeSparkline(live, recomputed, series)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, image, 0o644)
+}
+
+// pointsBefore keeps the points stamped before end
+func pointsBefore(points []state.SentimentDataPoint, end time.Time) []state.SentimentDataPoint {
+	var kept []state.SentimentDataPoint
+	for _, point := range points {
+		if point.Timestamp.Before(end) {
+			kept = append(kept, point)
+		}
+	}
+	return kept
+}
+
+// pointsBetween keeps the points stamped in [start, end)
+func pointsBetween(points []state.SentimentDataPoint, start, end time.Time) []state.SentimentDataPoint {
+	var kept []state.SentimentDataPoint
+	for _, point := range points {
+		if !point.Timestamp.Before(start) && point.Timestamp.Before(end) {
+			kept = append(kept, point)
+		}
+	}
+	return kept
+}
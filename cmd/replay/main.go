@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	awslambda "github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/christophergentle/hourstats-bsky/internal/events"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+const (
+	processorFunction = "hourstats-processor"
+
+	// postRetention matches the default state table TTL; it applies to runs without a TTL,
+	// since a retention policy may give runs a different one
+	postRetention = state.DefaultStateTTL
+
+	// inProgressGrace is how long a fetching run is assumed to still be running
+	inProgressGrace = 20 * time.Minute
+)
+
+// processorResponse mirrors the fields of the processor Lambda's response used here
+type processorResponse struct {
+	StatusCode    int    `json:"statusCode"`
+	Body          string `json:"body"`
+	PostsAnalyzed int    `json:"postsAnalyzed"`
+}
+
+// replayDecision records whether a run should be replayed and why
+type replayDecision struct {
+	Run    state.RunState
+	Replay bool
+	Reason string
+}
+
+func main() {
+	var (
+		runID  = flag.String("run", "", "Run ID to replay")
+		from   = flag.String("from", "", "Replay unposted runs created at or after this time (RFC3339 or \"2006-01-02 15:04\" UTC)")
+		to     = flag.String("to", "", "Replay unposted runs created before this time (default: now)")
+		dryRun = flag.Bool("dry-run", false, "List the runs that would be replayed without posting")
+	)
+	flag.Parse()
+
+	if *runID == "" && *from == "" {
+		fmt.Println("Usage:")
+		fmt.Println("  Replay a run:    go run ./cmd/replay -run <runID> [-dry-run]")
+		fmt.Println("  Replay a window: go run ./cmd/replay -from \"2025-01-05 12:00\" [-to \"2025-01-05 18:00\"] [-dry-run]")
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+
+	stateManager, err := state.NewStateManager(ctx, "hourstats-state")
+	if err != nil {
+		log.Fatalf("Failed to create state manager: %v", err)
+	}
+
+	var runs []state.RunState
+	if *runID != "" {
+		run, err := stateManager.GetRun(ctx, *runID, "orchestrator", state.EventuallyConsistent)
+		if err != nil {
+			log.Fatalf("Failed to get run %s: %v", *runID, err)
+		}
+		runs = []state.RunState{*run}
+	} else {
+		start, err := parseTime(*from)
+		if err != nil {
+			log.Fatalf("Invalid -from: %v", err)
+		}
+		end := time.Now().UTC()
+		if *to != "" {
+			if end, err = parseTime(*to); err != nil {
+				log.Fatalf("Invalid -to: %v", err)
+			}
+		}
+
+		recent, err := stateManager.GetRecentRuns(ctx, time.Since(start))
+		if err != nil {
+			log.Fatalf("Failed to list runs: %v", err)
+		}
+		for _, run := range recent {
+			if run.CreatedAt.Before(end) {
+				runs = append(runs, run)
+			}
+		}
+		fmt.Printf("Found %d runs between %s and %s\n\n", len(runs),
+			start.Format("2006-01-02 15:04 UTC"), end.Format("2006-01-02 15:04 UTC"))
+	}
+
+	decisions := decideReplays(runs, time.Now().UTC())
+
+	// Replay oldest first so delayed posts appear in window order
+	replayed, failed := 0, 0
+	for i := len(decisions) - 1; i >= 0; i-- {
+		decision := decisions[i]
+		if !decision.Replay {
+			fmt.Printf("⏭️  %s - %s\n", decision.Run.RunID, decision.Reason)
+			continue
+		}
+
+		if *dryRun {
+			fmt.Printf("🔁 %s - would replay (%s)\n", decision.Run.RunID, decision.Reason)
+			continue
+		}
+
+		fmt.Printf("🔁 %s - replaying (%s)...\n", decision.Run.RunID, decision.Reason)
+		resp, err := invokeReplay(ctx, decision.Run)
+		if err != nil {
+			fmt.Printf("   ❌ %v\n", err)
+			failed++
+			continue
+		}
+		if resp.StatusCode != 200 {
+			fmt.Printf("   ❌ processor returned %d: %s\n", resp.StatusCode, resp.Body)
+			failed++
+			continue
+		}
+		fmt.Printf("   ✅ %s (%d posts analyzed)\n", resp.Body, resp.PostsAnalyzed)
+		replayed++
+	}
+
+	if !*dryRun {
+		fmt.Printf("\nReplayed %d runs, %d failed\n", replayed, failed)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// decideReplays picks the runs whose summary was never posted and whose posts are still stored
+func decideReplays(runs []state.RunState, now time.Time) []replayDecision {
+	decisions := make([]replayDecision, 0, len(runs))
+	for _, run := range runs {
+		decision := replayDecision{Run: run}
+		switch {
+		case run.TopPostURI != "":
+			decision.Reason = "already posted"
+		case run.Status == "skipped":
+			decision.Reason = "skipped by design: " + run.SkipReason
+		case run.TTL > 0 && now.Unix() >= run.TTL:
+			decision.Reason = fmt.Sprintf("posts expired (TTL passed at %s)", time.Unix(run.TTL, 0).UTC().Format("2006-01-02 15:04 UTC"))
+		case run.TTL == 0 && now.Sub(run.CreatedAt) > postRetention:
+			decision.Reason = fmt.Sprintf("posts expired (older than %s)", postRetention)
+		case run.Status == "fetching" && now.Sub(run.UpdatedAt) < inProgressGrace:
+			decision.Reason = "still in progress"
+		default:
+			decision.Replay = true
+			decision.Reason = fmt.Sprintf("status %s/%s, %d posts", run.Status, run.Step, run.TotalPostsRetrieved)
+		}
+		decisions = append(decisions, decision)
+	}
+	return decisions
+}
+
+// invokeReplay runs the processor synchronously for a run in replay mode
+func invokeReplay(ctx context.Context, run state.RunState) (*processorResponse, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	payload, err := events.Marshal(events.ProcessorEvent{
+		Version:                 events.Version,
+		RunID:                   run.RunID,
+		AnalysisIntervalMinutes: run.AnalysisIntervalMinutes,
+		Replay:                  true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal processor event: %w", err)
+	}
+
+	output, err := awslambda.NewFromConfig(cfg).Invoke(ctx, &awslambda.InvokeInput{
+		FunctionName:   aws.String(processorFunction),
+		Payload:        payload,
+		InvocationType: types.InvocationTypeRequestResponse,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to invoke processor: %w", err)
+	}
+	if output.FunctionError != nil {
+		return nil, fmt.Errorf("processor failed: %s", string(output.Payload))
+	}
+
+	var resp processorResponse
+	if err := json.Unmarshal(output.Payload, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse processor response: %w", err)
+	}
+	return &resp, nil
+}
+
+// parseTime accepts RFC3339 or "2006-01-02 15:04" in UTC
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse("2006-01-02 15:04", value)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func TestDecideReplays(t *testing.T) {
+	now := time.Date(2025, 1, 5, 18, 0, 0, 0, time.UTC)
+	runs := []state.RunState{
+		{RunID: "posted", Status: "analyzed", TopPostURI: "at://did:plc:x/app.bsky.feed.post/1", CreatedAt: now.Add(-time.Hour)},
+		{RunID: "skipped", Status: "skipped", SkipReason: "only 12 posts", CreatedAt: now.Add(-time.Hour)},
+		{RunID: "expired", Status: "analyzed", CreatedAt: now.Add(-72 * time.Hour)},
+		{RunID: "running", Status: "fetching", CreatedAt: now.Add(-10 * time.Minute), UpdatedAt: now.Add(-time.Minute)},
+		{RunID: "stalled", Status: "fetching", CreatedAt: now.Add(-2 * time.Hour), UpdatedAt: now.Add(-100 * time.Minute)},
+		{RunID: "failed", Status: "analyzed", CreatedAt: now.Add(-3 * time.Hour)},
+		{RunID: "short-ttl", Status: "analyzed", CreatedAt: now.Add(-30 * time.Hour), TTL: now.Add(-6 * time.Hour).Unix()},
+		{RunID: "long-ttl", Status: "analyzed", CreatedAt: now.Add(-72 * time.Hour), TTL: now.Add(24 * time.Hour).Unix()},
+	}
+
+	expected := map[string]bool{
+		"posted":    false,
+		"skipped":   false,
+		"expired":   false,
+		"running":   false,
+		"stalled":   true,
+		"failed":    true,
+		"short-ttl": false,
+		"long-ttl":  true,
+	}
+
+	for _, decision := range decideReplays(runs, now) {
+		if decision.Replay != expected[decision.Run.RunID] {
+			t.Errorf("%s: expected replay=%v, got %v (%s)", decision.Run.RunID, expected[decision.Run.RunID], decision.Replay, decision.Reason)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	for _, value := range []string{"2025-01-05T12:00:00Z", "2025-01-05 12:00"} {
+		got, err := parseTime(value)
+		if err != nil {
+			t.Fatalf("parseTime(%q) failed: %v", value, err)
+		}
+		if !got.Equal(time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC)) {
+			t.Errorf("parseTime(%q) = %v", value, got)
+		}
+	}
+}
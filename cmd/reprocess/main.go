@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	awslambda "github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
+	"github.com/christophergentle/hourstats-bsky/internal/events"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+const processorFunction = "hourstats-processor"
+
+// processorResponse mirrors the fields of the processor Lambda's response used here
+type processorResponse struct {
+	StatusCode int    `json:"statusCode"`
+	Body       string `json:"body"`
+}
+
+func main() {
+	var (
+		runID           = flag.String("run", "", "Run ID to reprocess")
+		label           = flag.String("label", "", "Name of the result; reprocessing with the same label replaces it")
+		topPosts        = flag.Int("top", 0, "Number of top posts (default: 5)")
+		mediaRanking    = flag.String("media", "", "Media ranking: neutral, boost or exclude (default: the configured mode)")
+		replyRanking    = flag.String("replies", "", "Reply ranking: include or exclude replies from top posts (default: the configured mode)")
+		contentWarnings = flag.String("cw", "", "Content warnings: hide or show posts with content warnings in top posts (default: the configured mode)")
+		ranking         = flag.String("rank", "", "Top posts ranking: engagement or velocity (default: the configured mode)")
+		threshold       = flag.Float64("threshold", 0, "Average compound score beyond which sentiment is positive or negative (default: 0.3)")
+		minPostCount    = flag.Int("min-posts", 0, "Fewest posts for a summary (default: the configured minimum)")
+		minTopicPosts   = flag.Int("min-topic-posts", 0, "Fewest posts for a topic's sentiment (default: 5)")
+		reanalyze       = flag.Bool("reanalyze", false, "Score every post again instead of reusing the fetcher's analysis")
+		method          = flag.String("method", "", "Averaging method: mean, trimmedMean or median (default: the run's method)")
+		trim            = flag.Float64("trim", 0, "Fraction of scores a trimmed mean drops from each end (default: 0.1)")
+	)
+	flag.Parse()
+
+	if *runID == "" {
+		fmt.Println("Usage:")
+		fmt.Println("  Reprocess a run: go run ./cmd/reprocess -run <runID> -label <label> [-top 10] [-media boost] [-replies exclude] [-cw show] [-rank velocity] [-threshold 0.2] [-method median] [-reanalyze]")
+		fmt.Println("  Compare results: go run ./cmd/reprocess -run <runID>")
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+
+	stateManager, err := state.NewStateManager(ctx, "hourstats-state")
+	if err != nil {
+		log.Fatalf("Failed to create state manager: %v", err)
+	}
+	run, err := stateManager.GetRun(ctx, *runID, "orchestrator", state.EventuallyConsistent)
+	if err != nil {
+		log.Fatalf("Failed to get run %s: %v", *runID, err)
+	}
+
+	if *label != "" {
+		resp, err := invokeReprocess(ctx, *run, &events.Reprocess{
+			Label:              *label,
+			TopPosts:           *topPosts,
+			MediaRanking:       *mediaRanking,
+			ReplyRanking:       *replyRanking,
+			ContentWarnings:    *contentWarnings,
+			TopPostsRanking:    *ranking,
+			SentimentThreshold: *threshold,
+			MinPostCount:       *minPostCount,
+			MinTopicPosts:      *minTopicPosts,
+			Reanalyze:          *reanalyze,
+			SentimentMethod:    *method,
+			Trim:               *trim,
+		})
+		if err != nil {
+			log.Fatalf("Failed to reprocess run %s: %v", *runID, err)
+		}
+		if resp.StatusCode != 200 {
+			log.Fatalf("Processor returned %d: %s", resp.StatusCode, resp.Body)
+		}
+		fmt.Printf("✅ %s\n\n", resp.Body)
+	}
+
+	results, err := stateManager.GetReprocessResults(ctx, *runID)
+	if err != nil {
+		log.Fatalf("Failed to get reprocess results: %v", err)
+	}
+
+	originalMethod := aggregation.DefaultMethod
+	if run.SentimentMethod != nil {
+		originalMethod = *run.SentimentMethod
+	}
+	fmt.Printf("%-20s %-10s %8s %-18s %6s  %s\n", "RESULT", "SENTIMENT", "NET", "METHOD", "POSTS", "TOP POST")
+	fmt.Printf("%-20s %-10s %7.1f%% %-18s %6d  %s\n", "original", run.OverallSentiment, run.NetSentimentPercentage,
+		originalMethod, run.TotalPostsRetrieved, topPostURI(run.TopPosts))
+	for _, result := range results {
+		if result.SkipReason != "" {
+			fmt.Printf("%-20s skipped: %s\n", result.Label, result.SkipReason)
+			continue
+		}
+		fmt.Printf("%-20s %-10s %7.1f%% %-18s %6d  %s\n", result.Label, result.OverallSentiment, result.NetSentimentPercentage,
+			result.Parameters.SentimentMethod, result.TotalPosts, topPostURI(result.TopPosts))
+	}
+}
+
+func topPostURI(posts []state.Post) string {
+	if len(posts) == 0 {
+		return "-"
+	}
+	return posts[0].URI
+}
+
+// invokeReprocess runs the processor synchronously for a run in reprocess mode
+func invokeReprocess(ctx context.Context, run state.RunState, reprocess *events.Reprocess) (*processorResponse, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	payload, err := events.Marshal(events.ProcessorEvent{
+		Version:                 events.Version,
+		RunID:                   run.RunID,
+		AnalysisIntervalMinutes: run.AnalysisIntervalMinutes,
+		Reprocess:               reprocess,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal processor event: %w", err)
+	}
+
+	output, err := awslambda.NewFromConfig(cfg).Invoke(ctx, &awslambda.InvokeInput{
+		FunctionName:   aws.String(processorFunction),
+		Payload:        payload,
+		InvocationType: types.InvocationTypeRequestResponse,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to invoke processor: %w", err)
+	}
+	if output.FunctionError != nil {
+		return nil, fmt.Errorf("processor failed: %s", string(output.Payload))
+	}
+
+	var resp processorResponse
+	if err := json.Unmarshal(output.Payload, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse processor response: %w", err)
+	}
+	return &resp, nil
+}
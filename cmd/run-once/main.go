@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/dryrun"
+	"github.com/christophergentle/hourstats-bsky/internal/interval"
+	"github.com/christophergentle/hourstats-bsky/internal/pipeline"
+)
+
+// envInt returns the environment variable name as an integer, or fallback when it is unset
+func envInt(name string, fallback int) (int, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a whole number, got %q", name, value)
+	}
+	return n, nil
+}
+
+// loadOptions reads the pipeline options from flags, each defaulting to its environment variable
+func loadOptions(args []string) (pipeline.Options, error) {
+	intervalMinutes, err := envInt("HOURSTATS_INTERVAL_MINUTES", interval.Default)
+	if err != nil {
+		return pipeline.Options{}, err
+	}
+	topPostsCount, err := envInt("HOURSTATS_TOP_POSTS", pipeline.DefaultTopPostsCount)
+	if err != nil {
+		return pipeline.Options{}, err
+	}
+	minPostCount, err := envInt("HOURSTATS_MIN_POSTS", 100)
+	if err != nil {
+		return pipeline.Options{}, err
+	}
+	var dryRun dryrun.Level
+	if err := dryRun.Set(os.Getenv("DRY_RUN")); err != nil {
+		return pipeline.Options{}, fmt.Errorf("DRY_RUN: %w", err)
+	}
+
+	fs := flag.NewFlagSet("run-once", flag.ExitOnError)
+	handle := fs.String("handle", os.Getenv("BLUESKY_HANDLE"), "Bluesky handle to post as (BLUESKY_HANDLE)")
+	password := fs.String("password", os.Getenv("BLUESKY_PASSWORD"), "Bluesky app password (BLUESKY_PASSWORD)")
+	fs.IntVar(&intervalMinutes, "interval", intervalMinutes, "Minutes to analyze, from 1 to 1440 (HOURSTATS_INTERVAL_MINUTES)")
+	queries := fs.String("queries", os.Getenv("HOURSTATS_SEARCH_QUERIES"), "Comma-separated search queries instead of all public posts (HOURSTATS_SEARCH_QUERIES)")
+	fs.IntVar(&topPostsCount, "top-posts", topPostsCount, "Top posts to list in the summary (HOURSTATS_TOP_POSTS)")
+	fs.IntVar(&minPostCount, "min-posts", minPostCount, "Skip the summary of windows with fewer posts (HOURSTATS_MIN_POSTS)")
+	fs.Var(&dryRun, "dry-run", "Dry run level: no-post logs the summary, no-write also leaves -history unsaved, shadow posts as -shadow-handle; a bare -dry-run is no-post (DRY_RUN)")
+	shadowHandle := fs.String("shadow-handle", os.Getenv("BLUESKY_SHADOW_HANDLE"), "Test account a shadow dry run posts as (BLUESKY_SHADOW_HANDLE)")
+	shadowPassword := fs.String("shadow-password", os.Getenv("BLUESKY_SHADOW_PASSWORD"), "Test account's app password (BLUESKY_SHADOW_PASSWORD)")
+	historyPath := fs.String("history", os.Getenv("HOURSTATS_HISTORY_FILE"), "File that keeps sentiment history between runs (HOURSTATS_HISTORY_FILE)")
+	thresholdsJSON := fs.String("thresholds", os.Getenv("HOURSTATS_SENTIMENT_THRESHOLDS"), `JSON sentiment category thresholds, e.g. {"threshold": 0.3, "hysteresis": 0.05} (HOURSTATS_SENTIMENT_THRESHOLDS)`)
+	if err := fs.Parse(args); err != nil {
+		return pipeline.Options{}, err
+	}
+	thresholds, err := aggregation.ParseThresholds(*thresholdsJSON)
+	if err != nil {
+		return pipeline.Options{}, err
+	}
+
+	if *handle == "" || *password == "" {
+		return pipeline.Options{}, fmt.Errorf("set -handle and -password, or BLUESKY_HANDLE and BLUESKY_PASSWORD")
+	}
+	return pipeline.Options{
+		Handle:          *handle,
+		Password:        *password,
+		IntervalMinutes: intervalMinutes,
+		Queries:         client.ParseSearchQueries(*queries),
+		TopPostsCount:   topPostsCount,
+		MinPostCount:    minPostCount,
+		Thresholds:      thresholds,
+		DryRun:          dryRun,
+		ShadowHandle:    *shadowHandle,
+		ShadowPassword:  *shadowPassword,
+		HistoryPath:     *historyPath,
+	}, nil
+}
+
+func main() {
+	options, err := loadOptions(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	p, err := pipeline.New(options)
+	if err != nil {
+		log.Fatalf("Failed to create pipeline: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Running one Bluesky HourStats cycle as %s over the last %s (dry run: %s)",
+		options.Handle, interval.Describe(options.IntervalMinutes), options.DryRun)
+	if err := p.RunOnce(ctx); err != nil {
+		log.Fatalf("Run failed: %v", err)
+	}
+	log.Println("Run completed")
+}
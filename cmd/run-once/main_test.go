@@ -0,0 +1,62 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/christophergentle/hourstats-bsky/internal/dryrun"
+)
+
+func TestLoadOptions(t *testing.T) {
+	t.Setenv("BLUESKY_HANDLE", "test.bsky.social")
+	t.Setenv("BLUESKY_PASSWORD", "app-password")
+	t.Setenv("HOURSTATS_INTERVAL_MINUTES", "360")
+	t.Setenv("HOURSTATS_SEARCH_QUERIES", "golang, rustlang")
+
+	options, err := loadOptions([]string{"-min-posts", "10", "-dry-run"})
+	if err != nil {
+		t.Fatalf("loadOptions() error = %v", err)
+	}
+	if options.IntervalMinutes != 360 || options.MinPostCount != 10 || options.DryRun != dryrun.NoPost || options.TopPostsCount != 5 {
+		t.Errorf("Expected flags over environment over defaults, got %+v", options)
+	}
+	if !reflect.DeepEqual(options.Queries, []string{"golang", "rustlang"}) {
+		t.Errorf("Queries = %v, want [golang rustlang]", options.Queries)
+	}
+
+	if options, err := loadOptions([]string{"-interval", "45"}); err != nil || options.IntervalMinutes != 45 {
+		t.Errorf("Expected -interval to override the environment, got %+v, %v", options, err)
+	}
+
+	if options, err := loadOptions([]string{"-thresholds", `{"hysteresis": 0.05}`}); err != nil || options.Thresholds.Threshold != 0.3 || options.Thresholds.Hysteresis != 0.05 {
+		t.Errorf("Expected -thresholds over the default threshold, got %+v, %v", options, err)
+	}
+	t.Setenv("HOURSTATS_SENTIMENT_THRESHOLDS", `{"threshold": 2}`)
+	if _, err := loadOptions(nil); err == nil {
+		t.Error("Expected an error for an out of range threshold")
+	}
+	t.Setenv("HOURSTATS_SENTIMENT_THRESHOLDS", "")
+
+	t.Setenv("DRY_RUN", "true")
+	if options, err := loadOptions(nil); err != nil || options.DryRun != dryrun.NoPost {
+		t.Errorf("Expected DRY_RUN=true to mean no-post, got %+v, %v", options, err)
+	}
+	if options, err := loadOptions([]string{"-dry-run=no-write"}); err != nil || options.DryRun != dryrun.NoWrite {
+		t.Errorf("Expected -dry-run to override DRY_RUN, got %+v, %v", options, err)
+	}
+	t.Setenv("DRY_RUN", "sometimes")
+	if _, err := loadOptions(nil); err == nil {
+		t.Error("Expected an error for an unknown DRY_RUN level")
+	}
+	t.Setenv("DRY_RUN", "")
+
+	t.Setenv("HOURSTATS_INTERVAL_MINUTES", "half an hour")
+	if _, err := loadOptions(nil); err == nil {
+		t.Error("Expected an error for a non-numeric interval")
+	}
+	t.Setenv("HOURSTATS_INTERVAL_MINUTES", "")
+	t.Setenv("BLUESKY_PASSWORD", "")
+	if _, err := loadOptions(nil); err == nil {
+		t.Error("Expected an error without a password")
+	}
+}
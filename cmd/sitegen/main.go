@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/christophergentle/hourstats-bsky/internal/profanity"
+	"github.com/christophergentle/hourstats-bsky/internal/retention"
+	"github.com/christophergentle/hourstats-bsky/internal/sparkline"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// recentRunWindow is how far back run summaries are read from the state table, which
+// expires them after two days
+const recentRunWindow = 48 * time.Hour
+
+// historyWindow matches the sentiment history table's retention
+const historyWindow = 14 * 24 * time.Hour
+
+func main() {
+	var (
+		outDir     = flag.String("out", "site", "Directory to write the site into")
+		days       = flag.Int("days", 365, "Number of days of history to include")
+		resolution = flag.String("resolution", "daily", "Resolution of the index chart: daily or weekly")
+	)
+	flag.Parse()
+
+	chartResolution, err := sparkline.ParseResolution(*resolution)
+	if *days <= 0 || *outDir == "" || err != nil {
+		fmt.Println("Usage: go run ./cmd/sitegen [-out site] [-days 365] [-resolution daily|weekly]")
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	dailyManager, err := state.NewDailySentimentManager(ctx, "hourstats-daily-sentiment")
+	if err != nil {
+		log.Fatalf("Failed to create daily sentiment manager: %v", err)
+	}
+	daily, err := dailyManager.GetDailySentimentHistory(ctx, *days)
+	if err != nil {
+		log.Fatalf("Failed to read daily sentiment: %v", err)
+	}
+
+	historyManager, err := state.NewSentimentHistoryManager(ctx, "hourstats-sentiment-history")
+	if err != nil {
+		log.Fatalf("Failed to create sentiment history manager: %v", err)
+	}
+	history, err := historyManager.GetSentimentHistory(ctx, min(historyWindow, time.Duration(*days)*24*time.Hour))
+	if err != nil {
+		log.Fatalf("Failed to read sentiment history: %v", err)
+	}
+
+	stateManager, err := state.NewStateManager(ctx, "hourstats-state")
+	if err != nil {
+		log.Fatalf("Failed to create state manager: %v", err)
+	}
+	runs, err := stateManager.GetRecentRuns(ctx, recentRunWindow)
+	if err != nil {
+		log.Fatalf("Failed to read recent runs: %v", err)
+	}
+
+	archived, err := loadArchivedRuns(ctx, now.AddDate(0, 0, -*days), now)
+	if err != nil {
+		log.Fatalf("Failed to read archived runs: %v", err)
+	}
+	runs = append(archived, runs...)
+
+	// The index chart covers the same days as the pages
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	chartRange := sparkline.ChartRange{Start: today.AddDate(0, 0, -*days), End: today.AddDate(0, 0, 1), Resolution: chartResolution}
+	var yearlyChart []byte
+	if chartData, err := dailyManager.GetSentimentDataBetween(ctx, chartRange.Start, chartRange.End); err != nil {
+		log.Printf("Skipping yearly chart: %v", err)
+	} else if len(chartRange.Points(chartData)) > 1 {
+		yearlyChart, err = sparkline.NewYearlySparklineGenerator(nil).GenerateRangeChart(chartData, chartRange)
+		if err != nil {
+			log.Printf("Skipping yearly chart: %v", err)
+		}
+	}
+
+	pages := buildDays(daily, history, runs)
+	filter, err := loadProfanityFilter(ctx)
+	if err != nil {
+		log.Printf("Masking profanity with the default settings: %v", err)
+	}
+	cleanTopPosts(pages, filter)
+	if err := writeSite(*outDir, pages, yearlyChart, now); err != nil {
+		log.Fatalf("Failed to write site: %v", err)
+	}
+	log.Printf("Wrote %d day pages to %s", len(pages), *outDir)
+}
+
+// loadArchivedRuns reads run summaries from the retention archive, so days older than the
+// state table's TTL still list their top posts. Without an archive only recent days do.
+func loadArchivedRuns(ctx context.Context, start, end time.Time) ([]state.RunState, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	policy, err := retention.Load(ctx, ssm.NewFromConfig(cfg))
+	if err != nil {
+		return nil, err
+	}
+	if !policy.Archive.Enabled() {
+		log.Printf("No run archive configured; only the last %s will list top posts", recentRunWindow)
+		return nil, nil
+	}
+
+	reader, err := retention.NewArchiveReader(ctx, policy.Archive)
+	if err != nil {
+		return nil, err
+	}
+	return reader.RunsBetween(ctx, start, end)
+}
+
+// loadProfanityFilter builds the filter the top posts card uses, so the site republishes
+// post text as the summaries do
+func loadProfanityFilter(ctx context.Context) (*profanity.Filter, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return profanity.New(profanity.DefaultSettings), fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	settings, err := profanity.Load(ctx, ssm.NewFromConfig(cfg))
+	return profanity.New(settings), err
+}
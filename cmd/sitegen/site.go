@@ -0,0 +1,324 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/profanity"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// maxTopPosts is how many top posts each day page lists
+const maxTopPosts = 10
+
+// dayPage is one day of the archive
+type dayPage struct {
+	Date     string
+	Daily    *state.DailySentimentDataPoint
+	Points   []chartPoint
+	TopPosts []state.Post
+	Prev     string // the day before, if it has a page
+	Next     string // the day after, if it has a page
+}
+
+// chartPoint is one run's net sentiment
+type chartPoint struct {
+	At  time.Time
+	Net float64
+}
+
+// Title names the day, e.g. "Tuesday 2 September 2025"
+func (p dayPage) Title() string {
+	day, err := time.Parse("2006-01-02", p.Date)
+	if err != nil {
+		return p.Date
+	}
+	return day.Format("Monday 2 January 2006")
+}
+
+// Chart draws the day's runs as an inline SVG, empty when there is nothing to plot
+func (p dayPage) Chart() template.HTML {
+	day, err := time.Parse("2006-01-02", p.Date)
+	if err != nil || len(p.Points) == 0 {
+		return ""
+	}
+	return sentimentSVG(p.Points, day, day.Add(24*time.Hour))
+}
+
+// buildDays groups daily aggregates, sentiment history and run summaries into day pages,
+// most recent first. A run found in both the history and the run summaries is plotted once.
+func buildDays(daily []state.DailySentimentDataPoint, history []state.SentimentDataPoint, runs []state.RunState) []dayPage {
+	pages := make(map[string]*dayPage)
+	page := func(date string) *dayPage {
+		if pages[date] == nil {
+			pages[date] = &dayPage{Date: date}
+		}
+		return pages[date]
+	}
+
+	for i := range daily {
+		page(daily[i].Date).Daily = &daily[i]
+	}
+
+	plotted := make(map[string]bool)
+	topPosts := make(map[string]map[string]state.Post)
+	for _, run := range runs {
+		date := run.CreatedAt.UTC().Format("2006-01-02")
+		p := page(date)
+		if run.OverallSentiment != "" && !plotted[run.RunID] {
+			plotted[run.RunID] = true
+			p.Points = append(p.Points, chartPoint{At: run.CreatedAt.UTC(), Net: run.NetSentimentPercentage})
+		}
+		if len(run.TopPosts) > 0 && topPosts[date] == nil {
+			topPosts[date] = make(map[string]state.Post)
+		}
+		for _, post := range run.TopPosts {
+			if existing, ok := topPosts[date][post.URI]; !ok || post.EngagementScore > existing.EngagementScore {
+				topPosts[date][post.URI] = post
+			}
+		}
+	}
+	for _, point := range history {
+		if plotted[point.RunID] {
+			continue
+		}
+		plotted[point.RunID] = true
+		p := page(point.Timestamp.UTC().Format("2006-01-02"))
+		p.Points = append(p.Points, chartPoint{At: point.Timestamp.UTC(), Net: point.NetSentimentPercent})
+	}
+
+	var result []dayPage
+	for date, p := range pages {
+		sort.Slice(p.Points, func(i, j int) bool { return p.Points[i].At.Before(p.Points[j].At) })
+		for _, post := range topPosts[date] {
+			p.TopPosts = append(p.TopPosts, post)
+		}
+		sort.Slice(p.TopPosts, func(i, j int) bool {
+			if p.TopPosts[i].EngagementScore != p.TopPosts[j].EngagementScore {
+				return p.TopPosts[i].EngagementScore > p.TopPosts[j].EngagementScore
+			}
+			return p.TopPosts[i].URI < p.TopPosts[j].URI
+		})
+		if len(p.TopPosts) > maxTopPosts {
+			p.TopPosts = p.TopPosts[:maxTopPosts]
+		}
+		result = append(result, *p)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Date > result[j].Date })
+	for i := range result {
+		if i > 0 {
+			result[i].Next = result[i-1].Date
+		}
+		if i < len(result)-1 {
+			result[i].Prev = result[i+1].Date
+		}
+	}
+	return result
+}
+
+// cleanTopPosts passes the text of the pages' top posts through filter
+func cleanTopPosts(pages []dayPage, filter *profanity.Filter) {
+	for i := range pages {
+		for j := range pages[i].TopPosts {
+			pages[i].TopPosts[j].Text = filter.Clean(pages[i].TopPosts[j].Text)
+		}
+	}
+}
+
+// sentimentSVG plots points between start and end, with a zero line and a y-axis that
+// always includes ±10% so quiet days don't look dramatic
+func sentimentSVG(points []chartPoint, start, end time.Time) template.HTML {
+	const width, height, pad = 640.0, 180.0, 20.0
+
+	limit := 10.0
+	for _, point := range points {
+		limit = math.Max(limit, math.Abs(point.Net))
+	}
+	limit = math.Ceil(limit/10) * 10
+
+	x := func(t time.Time) float64 {
+		return pad + (width-2*pad)*t.Sub(start).Seconds()/end.Sub(start).Seconds()
+	}
+	y := func(net float64) float64 {
+		return pad + (height-2*pad)*(limit-net)/(2*limit)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="chart" viewBox="0 0 %.0f %.0f" role="img" aria-label="Net sentiment through the day">`, width, height)
+	fmt.Fprintf(&b, `<line class="zero" x1="%.0f" y1="%.1f" x2="%.0f" y2="%.1f"/>`, pad, y(0), width-pad, y(0))
+	fmt.Fprintf(&b, `<text x="2" y="%.1f">+%.0f%%</text><text x="2" y="%.1f">-%.0f%%</text>`, y(limit)+4, limit, y(-limit), limit)
+	for hour := 6; hour < 24; hour += 6 {
+		at := start.Add(time.Duration(hour) * time.Hour)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.0f" text-anchor="middle">%02d:00</text>`, x(at), height-4, hour)
+	}
+
+	coords := make([]string, len(points))
+	for i, point := range points {
+		coords[i] = fmt.Sprintf("%.1f,%.1f", x(point.At), y(point.Net))
+	}
+	fmt.Fprintf(&b, `<polyline class="line" points="%s"/>`, strings.Join(coords, " "))
+	if len(points) == 1 {
+		fmt.Fprintf(&b, `<circle class="dot" cx="%.1f" cy="%.1f" r="3"/>`, x(points[0].At), y(points[0].Net))
+	}
+	b.WriteString(`</svg>`)
+
+	return template.HTML(b.String())
+}
+
+// postURL converts a post AT-URI to its bsky.app page, or "" if it is not a post URI
+func postURL(uri string) string {
+	parts := strings.Split(strings.TrimPrefix(uri, "at://"), "/")
+	if !strings.HasPrefix(uri, "at://") || len(parts) != 3 || parts[1] != "app.bsky.feed.post" {
+		return ""
+	}
+	return fmt.Sprintf("https://bsky.app/profile/%s/post/%s", parts[0], parts[2])
+}
+
+// monthGroup is a month of days on the index page
+type monthGroup struct {
+	Title string
+	Days  []dayPage
+}
+
+// groupByMonth groups pages (most recent first) by calendar month
+func groupByMonth(pages []dayPage) []monthGroup {
+	var groups []monthGroup
+	for _, p := range pages {
+		day, err := time.Parse("2006-01-02", p.Date)
+		if err != nil {
+			continue
+		}
+		title := day.Format("January 2006")
+		if len(groups) == 0 || groups[len(groups)-1].Title != title {
+			groups = append(groups, monthGroup{Title: title})
+		}
+		groups[len(groups)-1].Days = append(groups[len(groups)-1].Days, p)
+	}
+	return groups
+}
+
+var funcs = template.FuncMap{
+	"postURL": postURL,
+	"signed":  func(v float64) string { return fmt.Sprintf("%+.1f%%", v) },
+}
+
+var indexTemplate = template.Must(template.New("index").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Bluesky Sentiment Archive</title>
+<link rel="stylesheet" href="style.css">
+</head>
+<body>
+<h1>Bluesky Sentiment Archive</h1>
+<p>Generated {{.Generated.Format "2 January 2006 15:04 UTC"}}.</p>
+{{if .YearlyChart}}<img class="yearly" src="yearly.png" alt="Average net sentiment over the archived days">{{end}}
+{{range .Months}}
+<h2>{{.Title}}</h2>
+<table>
+<tr><th>Day</th><th>Average</th><th>Low</th><th>High</th><th>Runs</th><th>Posts</th></tr>
+{{range .Days}}<tr>
+<td><a href="days/{{.Date}}.html">{{.Date}}</a></td>
+{{with .Daily}}<td>{{signed .AverageSentiment}}</td><td>{{signed .MinSentiment}}</td><td>{{signed .MaxSentiment}}</td><td>{{.TotalRuns}}</td><td>{{.TotalPosts}}</td>{{else}}<td colspan="5">not yet aggregated</td>{{end}}
+</tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
+
+var dayTemplate = template.Must(template.New("day").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Bluesky Sentiment, {{.Date}}</title>
+<link rel="stylesheet" href="../style.css">
+</head>
+<body>
+<nav>{{if .Prev}}<a href="{{.Prev}}.html">← {{.Prev}}</a> · {{end}}<a href="../index.html">Archive</a>{{if .Next}} · <a href="{{.Next}}.html">{{.Next}} →</a>{{end}}</nav>
+<h1>{{.Title}}</h1>
+{{with .Daily}}<p>Average net sentiment {{signed .AverageSentiment}} (low {{signed .MinSentiment}}, high {{signed .MaxSentiment}}) across {{.TotalRuns}} runs and {{.TotalPosts}} posts.</p>{{end}}
+{{.Chart}}
+{{if .TopPosts}}
+<h2>Top posts</h2>
+<ol class="posts">
+{{range .TopPosts}}<li><span class="{{.Sentiment}}">{{.Sentiment}}</span> {{with postURL .URI}}<a href="{{.}}">{{end}}@{{.Author}}{{if postURL .URI}}</a>{{end}}: {{.Text}} <small>♥ {{.Likes}} · ⟲ {{.Reposts}} · 💬 {{.Replies}}</small></li>
+{{end}}</ol>
+{{else}}
+<p>No top posts were archived for this day.</p>
+{{end}}
+</body>
+</html>
+`))
+
+const stylesheet = `body { font-family: system-ui, sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; color: #1d2733; }
+a { color: #0a66c2; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2rem; }
+th, td { text-align: right; padding: 0.25rem 0.5rem; border-bottom: 1px solid #e3e8ee; }
+th:first-child, td:first-child { text-align: left; }
+img.yearly { width: 100%; height: auto; }
+svg.chart { width: 100%; height: auto; font-size: 10px; fill: #5b6b7c; }
+svg.chart .zero { stroke: #9aa8b6; stroke-dasharray: 4 3; }
+svg.chart .line { fill: none; stroke: #0a66c2; stroke-width: 2; }
+svg.chart .dot { fill: #0a66c2; }
+ol.posts li { margin-bottom: 0.75rem; }
+.positive { color: #1a7f37; }
+.negative { color: #cf222e; }
+.neutral { color: #5b6b7c; }
+`
+
+// writeSite renders the archive into dir: index.html, style.css, yearly.png (when a
+// yearly chart is given) and days/<date>.html for every page
+func writeSite(dir string, pages []dayPage, yearlyChart []byte, generated time.Time) error {
+	if err := os.MkdirAll(filepath.Join(dir, "days"), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "style.css"), []byte(stylesheet), 0o644); err != nil {
+		return fmt.Errorf("failed to write style.css: %w", err)
+	}
+	if len(yearlyChart) > 0 {
+		if err := os.WriteFile(filepath.Join(dir, "yearly.png"), yearlyChart, 0o644); err != nil {
+			return fmt.Errorf("failed to write yearly.png: %w", err)
+		}
+	}
+
+	index := struct {
+		Generated   time.Time
+		YearlyChart bool
+		Months      []monthGroup
+	}{generated.UTC(), len(yearlyChart) > 0, groupByMonth(pages)}
+	if err := renderFile(filepath.Join(dir, "index.html"), indexTemplate, index); err != nil {
+		return err
+	}
+
+	for _, p := range pages {
+		if err := renderFile(filepath.Join(dir, "days", p.Date+".html"), dayTemplate, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderFile executes tmpl with data into path
+func renderFile(path string, tmpl *template.Template, data any) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if err := tmpl.Execute(file, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", path, err)
+	}
+	return file.Close()
+}
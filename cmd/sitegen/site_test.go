@@ -0,0 +1,111 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func TestBuildDays(t *testing.T) {
+	day := time.Date(2025, 9, 2, 0, 0, 0, 0, time.UTC)
+	daily := []state.DailySentimentDataPoint{
+		{Date: "2025-09-01", AverageSentiment: 4.2, TotalRuns: 48},
+		{Date: "2025-09-02", AverageSentiment: -1.5, TotalRuns: 40},
+	}
+	history := []state.SentimentDataPoint{
+		{RunID: "run-1", Timestamp: day.Add(time.Hour), NetSentimentPercent: 10},
+		{RunID: "run-2", Timestamp: day.Add(2 * time.Hour), NetSentimentPercent: -5},
+	}
+	runs := []state.RunState{
+		{RunID: "run-2", CreatedAt: day.Add(2 * time.Hour), OverallSentiment: "negative", NetSentimentPercentage: -5, TopPosts: []state.Post{
+			{URI: "at://did:plc:a/app.bsky.feed.post/1", EngagementScore: 10},
+			{URI: "at://did:plc:b/app.bsky.feed.post/2", EngagementScore: 30},
+		}},
+		{RunID: "run-3", CreatedAt: day.Add(3 * time.Hour), OverallSentiment: "positive", NetSentimentPercentage: 8, TopPosts: []state.Post{
+			{URI: "at://did:plc:a/app.bsky.feed.post/1", EngagementScore: 50},
+		}},
+		{RunID: "run-4", CreatedAt: day.Add(27 * time.Hour), Status: "failed"},
+	}
+
+	pages := buildDays(daily, history, runs)
+
+	if len(pages) != 3 || pages[0].Date != "2025-09-03" || pages[2].Date != "2025-09-01" {
+		t.Fatalf("Expected three days, most recent first, got %+v", pages)
+	}
+	if pages[0].Prev != "2025-09-02" || pages[0].Next != "" || pages[2].Next != "2025-09-02" || pages[2].Prev != "" {
+		t.Errorf("Unexpected navigation links %+v", pages)
+	}
+
+	sep2 := pages[1]
+	if sep2.Daily == nil || sep2.Daily.AverageSentiment != -1.5 {
+		t.Errorf("Expected the daily aggregate on 2025-09-02, got %+v", sep2.Daily)
+	}
+	if len(sep2.Points) != 3 || sep2.Points[0].Net != 10 || sep2.Points[2].Net != 8 {
+		t.Errorf("Expected run-1..3 plotted once each in order, got %+v", sep2.Points)
+	}
+	if len(sep2.TopPosts) != 2 || sep2.TopPosts[0].EngagementScore != 50 || sep2.TopPosts[1].EngagementScore != 30 {
+		t.Errorf("Expected posts deduplicated by URI and sorted by engagement, got %+v", sep2.TopPosts)
+	}
+	if len(pages[0].Points) != 0 || pages[0].Daily != nil {
+		t.Errorf("Expected a failed run to add an empty day, got %+v", pages[0])
+	}
+}
+
+func TestPostURL(t *testing.T) {
+	tests := map[string]string{
+		"at://did:plc:abc/app.bsky.feed.post/3kxyz": "https://bsky.app/profile/did:plc:abc/post/3kxyz",
+		"at://did:plc:abc/app.bsky.feed.like/3kxyz": "",
+		"https://bsky.app/profile/x/post/y":         "",
+	}
+	for uri, want := range tests {
+		if got := postURL(uri); got != want {
+			t.Errorf("postURL(%q) = %q, want %q", uri, got, want)
+		}
+	}
+}
+
+func TestWriteSite(t *testing.T) {
+	day := time.Date(2025, 9, 2, 0, 0, 0, 0, time.UTC)
+	pages := buildDays(
+		[]state.DailySentimentDataPoint{{Date: "2025-09-02", AverageSentiment: 3.5, MinSentiment: -2, MaxSentiment: 9, TotalRuns: 48, TotalPosts: 1200}},
+		nil,
+		[]state.RunState{{RunID: "run-1", CreatedAt: day.Add(time.Hour), OverallSentiment: "positive", NetSentimentPercentage: 9, TopPosts: []state.Post{
+			{URI: "at://did:plc:a/app.bsky.feed.post/1", Author: "alice.bsky.social", Text: "<b>hello</b>", Sentiment: "positive", EngagementScore: 12},
+		}}},
+	)
+
+	dir := t.TempDir()
+	if err := writeSite(dir, pages, []byte("png"), day.Add(30*time.Hour)); err != nil {
+		t.Fatalf("writeSite() error = %v", err)
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil {
+		t.Fatalf("index.html missing: %v", err)
+	}
+	for _, want := range []string{"September 2025", `href="days/2025-09-02.html"`, "&#43;3.5%", "-2.0%", `src="yearly.png"`} {
+		if !strings.Contains(string(index), want) {
+			t.Errorf("Expected %q in index.html:\n%s", want, index)
+		}
+	}
+
+	page, err := os.ReadFile(filepath.Join(dir, "days", "2025-09-02.html"))
+	if err != nil {
+		t.Fatalf("day page missing: %v", err)
+	}
+	for _, want := range []string{"Tuesday 2 September 2025", "<svg", "https://bsky.app/profile/did:plc:a/post/1", "&lt;b&gt;hello&lt;/b&gt;"} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("Expected %q in day page:\n%s", want, page)
+		}
+	}
+
+	for _, name := range []string{"style.css", "yearly.png"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to be written: %v", name, err)
+		}
+	}
+}
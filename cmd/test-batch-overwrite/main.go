@@ -52,7 +52,7 @@ func main() {
 		}
 
 		// Check what's actually stored
-		allPosts, err := stateManager.GetAllPosts(ctx, runID)
+		allPosts, err := stateManager.GetAllPosts(ctx, runID, state.StronglyConsistent)
 		if err != nil {
 			log.Fatalf("Failed to get posts: %v", err)
 		}
@@ -10,76 +10,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
 	"github.com/christophergentle/hourstats-bsky/internal/analyzer"
 	bskyclient "github.com/christophergentle/hourstats-bsky/internal/client"
 	"github.com/christophergentle/hourstats-bsky/internal/config"
 	"github.com/christophergentle/hourstats-bsky/internal/formatter"
+	"github.com/christophergentle/hourstats-bsky/internal/runreport"
 )
 
-type AnalysisResult struct {
-	RunID                     string                    `json:"runId"`
-	Timestamp                 string                    `json:"timestamp"`
-	AnalysisIntervalMinutes   int                       `json:"analysisIntervalMinutes"`
-	CutoffTime                string                    `json:"cutoffTime"`
-	CurrentTime               string                    `json:"currentTime"`
-	FetchStats                FetchStats                `json:"fetchStats"`
-	ProcessingStats           ProcessingStats           `json:"processingStats"`
-	SentimentAnalysis         SentimentAnalysis         `json:"sentimentAnalysis"`
-	GeneratedPost             string                    `json:"generatedPost"`
-	PostStatistics            PostStatistics            `json:"postStatistics"`
-	SamplePosts               []SamplePost              `json:"samplePosts"`
-}
-
-type FetchStats struct {
-	TotalAPICalls          int                    `json:"totalApiCalls"`
-	TotalPostsFromAPI      int                    `json:"totalPostsFromApi"`
-	PostsAfterTimeFilter   int                    `json:"postsAfterTimeFilter"`
-	PostsAfterAdultFilter  int                    `json:"postsAfterAdultFilter"`
-	PostsAfterDeduplication int                   `json:"postsAfterDeduplication"`
-	TimeDistribution       []TimeDistributionBucket `json:"timeDistribution"`
-}
-
-type TimeDistributionBucket struct {
-	BucketStart    string `json:"bucketStart"`
-	BucketEnd      string `json:"bucketEnd"`
-	PostCount      int    `json:"postCount"`
-	SamplePosts    []string `json:"samplePosts"`
-}
-
-type ProcessingStats struct {
-	PostsAnalyzed          int     `json:"postsAnalyzed"`
-	TopPostsSelected       int     `json:"topPostsSelected"`
-	DuplicatesRemoved      int     `json:"duplicatesRemoved"`
-}
-
-type SentimentAnalysis struct {
-	OverallSentiment      string  `json:"overallSentiment"`
-	NetSentimentPercent   float64 `json:"netSentimentPercent"`
-	AverageCompoundScore  float64 `json:"averageCompoundScore"`
-	PositiveCount         int     `json:"positiveCount"`
-	NeutralCount          int     `json:"neutralCount"`
-	NegativeCount         int     `json:"negativeCount"`
-}
-
-type PostStatistics struct {
-	CharacterCount int    `json:"characterCount"`
-	BlueskyLimit   int    `json:"blueskyLimit"`
-	Remaining      int    `json:"remaining"`
-	Status         string `json:"status"`
-}
-
-type SamplePost struct {
-	Author          string  `json:"author"`
-	URI             string  `json:"uri"`
-	CreatedAt       string  `json:"createdAt"`
-	Likes           int     `json:"likes"`
-	Reposts         int     `json:"reposts"`
-	Replies         int     `json:"replies"`
-	EngagementScore float64 `json:"engagementScore"`
-	Sentiment       string  `json:"sentiment"`
-	TextPreview     string  `json:"textPreview"`
-}
-
 func main() {
 	if len(os.Args) < 2 {
 		fmt.Println("Usage: go run cmd/test-sentiment-analysis/main.go <interval-minutes> [output-file.json]")
@@ -131,7 +69,7 @@ func main() {
 	fmt.Printf("   Window: %d minutes\n\n", intervalMinutes)
 
 	// Fetch posts
-	result := &AnalysisResult{
+	result := &runreport.Report{
 		RunID:                   fmt.Sprintf("dry-run-%d", time.Now().Unix()),
 		Timestamp:               now.Format(time.RFC3339),
 		AnalysisIntervalMinutes: intervalMinutes,
@@ -170,7 +108,7 @@ func main() {
 	// Perform sentiment analysis
 	sentimentResult := analyzeSentiment(fetchedPosts)
 	result.SentimentAnalysis = sentimentResult
-	result.ProcessingStats = ProcessingStats{
+	result.ProcessingStats = runreport.ProcessingStats{
 		PostsAnalyzed:    len(fetchedPosts),
 		TopPostsSelected: 5,
 	}
@@ -204,10 +142,10 @@ func main() {
 	fmt.Printf("\n✅ Results saved to: %s\n", outputFile)
 }
 
-func fetchAndAnalyzePosts(ctx context.Context, client *bskyclient.BlueskyClient, cutoffTime, now time.Time, intervalMinutes int) (FetchStats, []bskyclient.Post) {
-	var stats FetchStats
+func fetchAndAnalyzePosts(ctx context.Context, client *bskyclient.BlueskyClient, cutoffTime, now time.Time, intervalMinutes int) (runreport.FetchStats, []bskyclient.Post) {
+	var stats runreport.FetchStats
 	var allPosts []bskyclient.Post
-	var timeDistributionPosts []bskyclient.Post // Track posts for time distribution
+	var timeDistributionPosts []runreport.PostTime // Track posts for time distribution
 	currentCursor := ""
 	apiCallCount := 0
 	maxIterations := 100
@@ -244,7 +182,7 @@ func fetchAndAnalyzePosts(ctx context.Context, client *bskyclient.BlueskyClient,
 			}
 
 			stats.PostsAfterTimeFilter++
-			timeDistributionPosts = append(timeDistributionPosts, post)
+			timeDistributionPosts = append(timeDistributionPosts, runreport.PostTime{URI: post.URI, CreatedAt: post.CreatedAt})
 
 			// Deduplicate by URI (keep highest engagement)
 			if existing, exists := uriToPost[post.URI]; exists {
@@ -286,7 +224,7 @@ func fetchAndAnalyzePosts(ctx context.Context, client *bskyclient.BlueskyClient,
 	stats.PostsAfterDeduplication = len(allPosts)
 
 	// Populate time distribution with actual post timestamps
-	populateTimeDistribution(&stats, timeDistributionPosts, cutoffTime, now)
+	stats.TimeDistribution = runreport.TimeDistribution(timeDistributionPosts, cutoffTime, now, runreport.TimeBuckets)
 
 	return stats, allPosts
 }
@@ -302,57 +240,7 @@ type PostData struct {
 	CreatedAt string
 }
 
-func populateTimeDistribution(stats *FetchStats, posts []bskyclient.Post, cutoffTime, now time.Time) {
-	// Create 6 buckets for time distribution
-	bucketCount := 6
-	buckets := make([]TimeDistributionBucket, bucketCount)
-	windowDuration := now.Sub(cutoffTime)
-	bucketDuration := windowDuration / time.Duration(bucketCount)
-
-	// Initialize buckets
-	for i := 0; i < bucketCount; i++ {
-		bucketStart := cutoffTime.Add(time.Duration(i) * bucketDuration)
-		bucketEnd := cutoffTime.Add(time.Duration(i+1) * bucketDuration)
-		if i == bucketCount-1 {
-			bucketEnd = now // Ensure last bucket includes now
-		}
-
-		buckets[i] = TimeDistributionBucket{
-			BucketStart: bucketStart.Format("15:04:05"),
-			BucketEnd:   bucketEnd.Format("15:04:05"),
-		}
-	}
-
-	// Distribute posts into buckets
-	for _, post := range posts {
-		postTime, err := time.Parse(time.RFC3339, post.CreatedAt)
-		if err != nil {
-			continue
-		}
-
-		// Find which bucket this post belongs to
-		for i := 0; i < bucketCount; i++ {
-			bucketStart := cutoffTime.Add(time.Duration(i) * bucketDuration)
-			bucketEnd := cutoffTime.Add(time.Duration(i+1) * bucketDuration)
-			if i == bucketCount-1 {
-				bucketEnd = now
-			}
-
-			if (postTime.Equal(bucketStart) || postTime.After(bucketStart)) && postTime.Before(bucketEnd) {
-				buckets[i].PostCount++
-				// Store sample URIs (first 3 per bucket)
-				if len(buckets[i].SamplePosts) < 3 {
-					buckets[i].SamplePosts = append(buckets[i].SamplePosts, post.URI)
-				}
-				break
-			}
-		}
-	}
-
-	stats.TimeDistribution = buckets
-}
-
-func analyzeSentiment(posts []bskyclient.Post) SentimentAnalysis {
+func analyzeSentiment(posts []bskyclient.Post) runreport.SentimentAnalysis {
 	// Convert to analyzer posts
 	sentimentAnalyzer := analyzer.New()
 	analyzerPosts := make([]analyzer.Post, len(posts))
@@ -385,11 +273,12 @@ func analyzeSentiment(posts []bskyclient.Post) SentimentAnalysis {
 	for _, analyzed := range analyzedPosts {
 		totalCompoundScore += analyzed.SentimentScore
 
-		if analyzed.SentimentScore >= 0.3 {
+		switch aggregation.Categorize(analyzed.SentimentScore, aggregation.DefaultThreshold) {
+		case aggregation.CategoryPositive:
 			positiveCount++
-		} else if analyzed.SentimentScore <= -0.3 {
+		case aggregation.CategoryNegative:
 			negativeCount++
-		} else {
+		default:
 			neutralCount++
 		}
 	}
@@ -397,16 +286,9 @@ func analyzeSentiment(posts []bskyclient.Post) SentimentAnalysis {
 	averageCompoundScore := totalCompoundScore / float64(len(analyzedPosts))
 	netSentimentPercent := averageCompoundScore * 100.0
 
-	var overallSentiment string
-	if averageCompoundScore >= 0.3 {
-		overallSentiment = "positive"
-	} else if averageCompoundScore <= -0.3 {
-		overallSentiment = "negative"
-	} else {
-		overallSentiment = "neutral"
-	}
+	overallSentiment := aggregation.Categorize(averageCompoundScore, aggregation.DefaultThreshold)
 
-	return SentimentAnalysis{
+	return runreport.SentimentAnalysis{
 		OverallSentiment:     overallSentiment,
 		NetSentimentPercent:  netSentimentPercent,
 		AverageCompoundScore: averageCompoundScore,
@@ -417,15 +299,15 @@ func analyzeSentiment(posts []bskyclient.Post) SentimentAnalysis {
 }
 
 type PostGenerationResult struct {
-	PostText   string
-	Stats      PostStatistics
-	SamplePosts []SamplePost
+	PostText    string
+	Stats       runreport.PostStatistics
+	SamplePosts []runreport.SamplePost
 }
 
-func generatePostContent(posts []bskyclient.Post, sentiment SentimentAnalysis, intervalMinutes int) PostGenerationResult {
+func generatePostContent(posts []bskyclient.Post, sentiment runreport.SentimentAnalysis, intervalMinutes int) PostGenerationResult {
 	// Calculate engagement scores
 	type PostWithEngagement struct {
-		Post           bskyclient.Post
+		Post            bskyclient.Post
 		EngagementScore float64
 	}
 
@@ -451,7 +333,7 @@ func generatePostContent(posts []bskyclient.Post, sentiment SentimentAnalysis, i
 
 	// Convert to formatter posts
 	formatterPosts := make([]formatter.Post, topCount)
-	samplePosts := make([]SamplePost, topCount)
+	samplePosts := make([]runreport.SamplePost, topCount)
 
 	for i := 0; i < topCount; i++ {
 		p := postsWithEngagement[i].Post
@@ -466,12 +348,7 @@ func generatePostContent(posts []bskyclient.Post, sentiment SentimentAnalysis, i
 			EngagementScore: postsWithEngagement[i].EngagementScore,
 		}
 
-		textPreview := p.Text
-		if len(textPreview) > 100 {
-			textPreview = textPreview[:100] + "..."
-		}
-
-		samplePosts[i] = SamplePost{
+		samplePosts[i] = runreport.SamplePost{
 			Author:          p.Author,
 			URI:             p.URI,
 			CreatedAt:       p.CreatedAt,
@@ -480,7 +357,7 @@ func generatePostContent(posts []bskyclient.Post, sentiment SentimentAnalysis, i
 			Replies:         p.Replies,
 			EngagementScore: postsWithEngagement[i].EngagementScore,
 			Sentiment:       "", // Could analyze individually
-			TextPreview:     textPreview,
+			TextPreview:     runreport.TextPreview(p.Text),
 		}
 	}
 
@@ -493,33 +370,14 @@ func generatePostContent(posts []bskyclient.Post, sentiment SentimentAnalysis, i
 		sentiment.AverageCompoundScore,
 	)
 
-	// Calculate statistics
-	charCount := len([]rune(postText))
-	blueskyLimit := 300
-	remaining := blueskyLimit - charCount
-
-	var status string
-	if remaining < 0 {
-		status = "EXCEEDS_LIMIT"
-	} else if remaining < 50 {
-		status = "CLOSE_TO_LIMIT"
-	} else {
-		status = "WITHIN_LIMIT"
-	}
-
 	return PostGenerationResult{
 		PostText:    postText,
-		Stats:       PostStatistics{
-			CharacterCount: charCount,
-			BlueskyLimit:   blueskyLimit,
-			Remaining:      remaining,
-			Status:         status,
-		},
+		Stats:       runreport.NewPostStatistics(postText),
 		SamplePosts: samplePosts,
 	}
 }
 
-func saveResults(result *AnalysisResult, filename string) error {
+func saveResults(result *runreport.Report, filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return err
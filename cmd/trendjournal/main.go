@@ -28,7 +28,7 @@ func main() {
 
 	log.Printf("Starting Bluesky HourStats bot...")
 	log.Printf("Handle: %s", cfg.Bluesky.Handle)
-	log.Printf("Dry run mode: %v", cfg.Settings.DryRun)
+	log.Printf("Dry run mode: %s", cfg.Settings.DryRun)
 
 	if err := scheduler.Start(); err != nil {
 		log.Fatalf("Failed to start scheduler: %v", err)
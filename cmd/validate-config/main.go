@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/christophergentle/hourstats-bsky/internal/config"
+	lambdapkg "github.com/christophergentle/hourstats-bsky/internal/lambda"
+)
+
+// validate-config checks the SSM parameters the Lambdas load at startup, or a local
+// config.yaml, and exits 1 listing every problem, so deploys fail on a misconfiguration
+// instead of the next run
+func main() {
+	file := flag.String("file", "", "Validate a YAML config file instead of the SSM parameters")
+	flag.Parse()
+
+	cfg, err := load(context.Background(), *file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Configuration is valid\n")
+	fmt.Printf("  Handle:            %s\n", cfg.Bluesky.Handle)
+	fmt.Printf("  Analysis interval: %d minutes\n", cfg.Settings.AnalysisIntervalMinutes)
+	fmt.Printf("  Top posts:         %d\n", cfg.Settings.TopPostsCount)
+	fmt.Printf("  Min coverage:      %.0f%%\n", cfg.Settings.MinCoveragePercent)
+	fmt.Printf("  Min post count:    %d\n", cfg.Settings.MinPostCount)
+	fmt.Printf("  Dry run:           %s\n", cfg.Settings.DryRun)
+	fmt.Printf("  Tables:            %s, %s, %s, %s\n",
+		cfg.Tables.State, cfg.Tables.SentimentHistory, cfg.Tables.DailySentiment, cfg.Tables.Blocklist)
+}
+
+// load reads and validates the config file, or the SSM parameters when file is empty
+func load(ctx context.Context, file string) (*config.Config, error) {
+	if file != "" {
+		return config.LoadConfigFile(file)
+	}
+
+	loader, err := lambdapkg.NewSSMConfigLoader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SSM config loader: %w", err)
+	}
+	return loader.LoadConfig(ctx)
+}
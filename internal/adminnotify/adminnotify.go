@@ -0,0 +1,121 @@
+// Package adminnotify tells the bot's operator about failed runs and outages by Bluesky
+// direct message, alongside or instead of the bot's public notices
+package adminnotify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// ParameterName holds the optional JSON settings; see Settings
+const ParameterName = "/hourstats/settings/admin_dm"
+
+// Settings choose who is messaged
+//
+//	{"recipient": "operator.bsky.social", "replacePublicNotices": true}
+type Settings struct {
+	// Recipient is the handle or DID messaged; empty sends no messages
+	Recipient string `json:"recipient,omitempty"`
+	// ReplacePublicNotices sends notices the bot would otherwise post publicly, such as
+	// the outage notice, only as a message to Recipient
+	ReplacePublicNotices bool `json:"replacePublicNotices,omitempty"`
+}
+
+// Enabled reports whether messages are sent
+func (s Settings) Enabled() bool {
+	return s.Recipient != ""
+}
+
+// PostsPublicNotices reports whether public notices are still posted; they are unless a
+// recipient receives them instead
+func (s Settings) PostsPublicNotices() bool {
+	return !s.Enabled() || !s.ReplacePublicNotices
+}
+
+// Validate checks the recipient looks like a handle or DID
+func (s Settings) Validate() error {
+	if s.ReplacePublicNotices && !s.Enabled() {
+		return fmt.Errorf("replacePublicNotices needs a recipient")
+	}
+	if s.Enabled() && !strings.HasPrefix(s.Recipient, "did:") && !strings.Contains(s.Recipient, ".") {
+		return fmt.Errorf("recipient must be a handle or DID, got %q", s.Recipient)
+	}
+	return nil
+}
+
+// ParameterGetter is the subset of the SSM client Load needs
+type ParameterGetter interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Load reads the settings from SSM. A missing parameter returns the zero settings, which
+// send no messages, as does an invalid one along with its error.
+func Load(ctx context.Context, ssmClient ParameterGetter) (Settings, error) {
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ParameterName),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return Settings{}, nil
+		}
+		return Settings{}, fmt.Errorf("failed to get %s: %w", ParameterName, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return Settings{}, nil
+	}
+	return Parse(*result.Parameter.Value)
+}
+
+// Parse decodes and validates JSON settings
+func Parse(value string) (Settings, error) {
+	var settings Settings
+	if strings.TrimSpace(value) == "" {
+		return settings, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&settings); err != nil {
+		return Settings{}, fmt.Errorf("invalid admin DM JSON: %w", err)
+	}
+	settings.Recipient = strings.TrimPrefix(strings.TrimSpace(settings.Recipient), "@")
+	if err := settings.Validate(); err != nil {
+		return Settings{}, fmt.Errorf("invalid admin DM settings: %w", err)
+	}
+	return settings, nil
+}
+
+// Messenger sends direct messages; client.BskyPoster satisfies it
+type Messenger interface {
+	Authenticate() error
+	SendDirectMessage(ctx context.Context, recipient, text string) error
+}
+
+// Send messages the recipient as source, e.g. "processor", reporting whether the message
+// was sent. Failures are logged rather than returned, so telling the operator about a
+// problem never becomes one.
+func Send(ctx context.Context, settings Settings, messenger Messenger, source, text string) bool {
+	if !settings.Enabled() {
+		return false
+	}
+	if err := messenger.Authenticate(); err != nil {
+		log.Printf("Failed to authenticate to message %s: %v", settings.Recipient, err)
+		return false
+	}
+	if err := messenger.SendDirectMessage(ctx, settings.Recipient, fmt.Sprintf("[hourstats %s] %s", source, text)); err != nil {
+		log.Printf("Failed to message %s: %v", settings.Recipient, err)
+		return false
+	}
+	log.Printf("✉️ Messaged %s: %s", settings.Recipient, text)
+	return true
+}
@@ -0,0 +1,56 @@
+package adminnotify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christophergentle/hourstats-bsky/internal/client/clienttest"
+)
+
+func TestParse(t *testing.T) {
+	settings, err := Parse(`{"recipient": "@operator.bsky.social", "replacePublicNotices": true}`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if settings.Recipient != "operator.bsky.social" || !settings.Enabled() || settings.PostsPublicNotices() {
+		t.Errorf("Parse() = %+v, want messages replacing public notices", settings)
+	}
+
+	if settings, err := Parse(""); err != nil || settings.Enabled() || !settings.PostsPublicNotices() {
+		t.Errorf("Parse(\"\") = %+v, %v, want messages off", settings, err)
+	}
+	if settings, err := Parse(`{"recipient": "did:plc:operator"}`); err != nil || !settings.PostsPublicNotices() {
+		t.Errorf("Parse(DID) = %+v, %v, want messages alongside public notices", settings, err)
+	}
+
+	for _, value := range []string{`{"recipient": "operator"}`, `{"replacePublicNotices": true}`, `{"recipent": "a.bsky.social"}`} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("Parse(%s) succeeded, want error", value)
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	mock := clienttest.NewMockClient()
+	settings := Settings{Recipient: "operator.bsky.social"}
+
+	if !Send(context.Background(), settings, mock, "processor", "run failed") {
+		t.Fatal("Send() = false, want the message sent")
+	}
+	messages := mock.Messages()
+	if len(messages) != 1 || messages[0].Recipient != "operator.bsky.social" || messages[0].Text != "[hourstats processor] run failed" {
+		t.Errorf("sent %+v", messages)
+	}
+
+	if Send(context.Background(), Settings{}, mock, "processor", "ignored") {
+		t.Error("Send() without a recipient = true, want nothing sent")
+	}
+	mock.PostErr = errors.New("messages disabled")
+	if Send(context.Background(), settings, mock, "processor", "lost") {
+		t.Error("Send() = true when sending fails")
+	}
+	if len(mock.Messages()) != 1 {
+		t.Errorf("expected only the first message, got %d", len(mock.Messages()))
+	}
+}
@@ -0,0 +1,80 @@
+// Package aggregation chooses how a run's post sentiments are combined into net sentiment:
+// the averaging method, which can resist a few extreme posts, and whether the posts and
+// charts show each post counting once or each weighted by its engagement, which is closer
+// to what people actually saw. Both weightings are measured and stored every run. It also
+// holds the thresholds that turn an average score into a positive, neutral or negative
+// category.
+package aggregation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// ParameterName holds the optional mode: "post", "engagement" or "both"
+const ParameterName = "/hourstats/settings/sentiment_aggregation"
+
+// Mode is how sentiment is shown
+type Mode string
+
+const (
+	// ModePost shows the per-post average, each post counting once; it is the default
+	ModePost Mode = "post"
+	// ModeEngagement shows the engagement-weighted average in its place
+	ModeEngagement Mode = "engagement"
+	// ModeBoth shows the per-post average with the engagement-weighted one beside it
+	ModeBoth Mode = "both"
+)
+
+// ShowsPerPost reports whether the per-post average is shown
+func (m Mode) ShowsPerPost() bool {
+	return m != ModeEngagement
+}
+
+// ShowsWeighted reports whether the engagement-weighted average is shown
+func (m Mode) ShowsWeighted() bool {
+	return m == ModeEngagement || m == ModeBoth
+}
+
+// ParameterGetter is the subset of the SSM client Load needs
+type ParameterGetter interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Load reads the mode from SSM. A missing parameter returns ModePost, as does an invalid
+// one along with its error.
+func Load(ctx context.Context, ssmClient ParameterGetter) (Mode, error) {
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ParameterName),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return ModePost, nil
+		}
+		return ModePost, fmt.Errorf("failed to get %s: %w", ParameterName, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return ModePost, nil
+	}
+	return Parse(*result.Parameter.Value)
+}
+
+// Parse reads a mode, case-insensitively; empty is ModePost
+func Parse(value string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "":
+		return ModePost, nil
+	case ModePost, ModeEngagement, ModeBoth:
+		return mode, nil
+	default:
+		return ModePost, fmt.Errorf("invalid sentiment aggregation %q, want post, engagement or both", value)
+	}
+}
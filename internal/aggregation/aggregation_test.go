@@ -0,0 +1,32 @@
+package aggregation
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	for value, want := range map[string]Mode{
+		"":             ModePost,
+		"post":         ModePost,
+		" Engagement ": ModeEngagement,
+		"both":         ModeBoth,
+	} {
+		got, err := Parse(value)
+		if err != nil || got != want {
+			t.Errorf("Parse(%q) = %q, %v, want %q", value, got, err, want)
+		}
+	}
+	if got, err := Parse("weighted"); err == nil || got != ModePost {
+		t.Errorf("Parse(weighted) = %q, %v, want ModePost and an error", got, err)
+	}
+}
+
+func TestModeShows(t *testing.T) {
+	for mode, want := range map[Mode][2]bool{
+		ModePost:       {true, false},
+		ModeEngagement: {false, true},
+		ModeBoth:       {true, true},
+	} {
+		if got := [2]bool{mode.ShowsPerPost(), mode.ShowsWeighted()}; got != want {
+			t.Errorf("%s shows per-post, weighted = %v, want %v", mode, got, want)
+		}
+	}
+}
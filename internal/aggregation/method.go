@@ -0,0 +1,150 @@
+package aggregation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// MethodParameterName holds the optional JSON averaging method; see Method
+const MethodParameterName = "/hourstats/settings/sentiment_method"
+
+// Averaging methods
+const (
+	// MethodMean averages every post's score; it is the default
+	MethodMean = "mean"
+	// MethodTrimmedMean drops the Trim fraction of highest and of lowest scores first
+	MethodTrimmedMean = "trimmedMean"
+	// MethodMedian takes the middle score
+	MethodMedian = "median"
+)
+
+// DefaultTrim is the fraction cut from each end when a trimmed mean doesn't set one
+const DefaultTrim = 0.1
+
+// maxTrim keeps at least a tenth of the scores in a trimmed mean
+const maxTrim = 0.45
+
+// Method chooses how the posts' compound scores are averaged into net sentiment. The mean
+// can be dragged by a handful of extreme posts; the trimmed mean and median resist them.
+// Runs record the method they used, so their figures can be reproduced.
+//
+//	{"name": "trimmedMean", "trim": 0.1}
+type Method struct {
+	Name string `json:"name" dynamodbav:"name"`
+	// Trim is the fraction of scores dropped from each end by a trimmed mean
+	Trim float64 `json:"trim,omitempty" dynamodbav:"trim,omitempty"`
+}
+
+// DefaultMethod applies when the parameter is missing
+var DefaultMethod = Method{Name: MethodMean}
+
+// Validate checks the method is known and its trim in range
+func (m Method) Validate() error {
+	switch m.Name {
+	case MethodMean, MethodMedian:
+		if m.Trim != 0 {
+			return fmt.Errorf("trim only applies to %s", MethodTrimmedMean)
+		}
+	case MethodTrimmedMean:
+		if m.Trim <= 0 || m.Trim > maxTrim {
+			return fmt.Errorf("trim must be above 0 and at most %.2f, got %g", maxTrim, m.Trim)
+		}
+	default:
+		return fmt.Errorf("unknown method %q, want %s, %s or %s", m.Name, MethodMean, MethodTrimmedMean, MethodMedian)
+	}
+	return nil
+}
+
+// String describes the method for logs and run comparisons, e.g. "10% trimmed mean"; the
+// zero Method, recorded by runs from before methods were, is the mean
+func (m Method) String() string {
+	switch m.Name {
+	case "":
+		return MethodMean
+	case MethodTrimmedMean:
+		return fmt.Sprintf("%.0f%% trimmed mean", m.Trim*100)
+	}
+	return m.Name
+}
+
+// Average combines scores by the method; no scores average to 0. An unknown method
+// averages as the mean.
+func (m Method) Average(scores []float64) float64 {
+	if len(scores) == 0 {
+		return 0
+	}
+	if m.Name == MethodMean || (m.Name != MethodMedian && m.Name != MethodTrimmedMean) {
+		return mean(scores)
+	}
+
+	sorted := append([]float64(nil), scores...)
+	sort.Float64s(sorted)
+	if m.Name == MethodMedian {
+		middle := len(sorted) / 2
+		if len(sorted)%2 == 0 {
+			return (sorted[middle-1] + sorted[middle]) / 2
+		}
+		return sorted[middle]
+	}
+	cut := int(float64(len(sorted)) * m.Trim)
+	return mean(sorted[cut : len(sorted)-cut])
+}
+
+// mean averages scores, which must not be empty
+func mean(scores []float64) float64 {
+	sum := 0.0
+	for _, score := range scores {
+		sum += score
+	}
+	return sum / float64(len(scores))
+}
+
+// LoadMethod reads the averaging method from SSM. A missing parameter returns
+// DefaultMethod, as does an invalid one along with its error.
+func LoadMethod(ctx context.Context, ssmClient ParameterGetter) (Method, error) {
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(MethodParameterName),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return DefaultMethod, nil
+		}
+		return DefaultMethod, fmt.Errorf("failed to get %s: %w", MethodParameterName, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return DefaultMethod, nil
+	}
+	return ParseMethod(*result.Parameter.Value)
+}
+
+// ParseMethod decodes and validates a JSON method; a trimmed mean without a trim cuts
+// DefaultTrim from each end
+func ParseMethod(value string) (Method, error) {
+	if strings.TrimSpace(value) == "" {
+		return DefaultMethod, nil
+	}
+
+	var method Method
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&method); err != nil {
+		return DefaultMethod, fmt.Errorf("invalid sentiment method JSON: %w", err)
+	}
+	if method.Name == MethodTrimmedMean && method.Trim == 0 {
+		method.Trim = DefaultTrim
+	}
+	if err := method.Validate(); err != nil {
+		return DefaultMethod, fmt.Errorf("invalid sentiment method: %w", err)
+	}
+	return method, nil
+}
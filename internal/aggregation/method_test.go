@@ -0,0 +1,60 @@
+package aggregation
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMethodAverage(t *testing.T) {
+	// One extreme post among mildly positive ones
+	scores := []float64{0.2, 0.3, -1, 0.1, 0.25, 0.2, 0.3, 0.15, 0.2, 0.1}
+
+	tests := []struct {
+		method Method
+		want   float64
+	}{
+		{DefaultMethod, 0.08},
+		{Method{Name: MethodMedian}, 0.2},
+		// Drops -1 and one 0.3
+		{Method{Name: MethodTrimmedMean, Trim: 0.1}, 1.5 / 8},
+	}
+	for _, tt := range tests {
+		if got := tt.method.Average(scores); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s Average() = %.4f, want %.4f", tt.method, got, tt.want)
+		}
+	}
+
+	if got := (Method{Name: MethodMedian}).Average([]float64{0.4, -0.2, 0.1}); got != 0.1 {
+		t.Errorf("median of an odd count = %.2f, want 0.1", got)
+	}
+	if got := (Method{Name: MethodMedian}).Average(nil); got != 0 {
+		t.Errorf("Average(nil) = %.2f, want 0", got)
+	}
+	if scores[2] != -1 {
+		t.Error("Average() reordered its input")
+	}
+}
+
+func TestParseMethod(t *testing.T) {
+	method, err := ParseMethod(`{"name": "trimmedMean"}`)
+	if err != nil || method != (Method{Name: MethodTrimmedMean, Trim: DefaultTrim}) {
+		t.Errorf("ParseMethod() = %+v, %v, want the default trim", method, err)
+	}
+	if method.String() != "10% trimmed mean" {
+		t.Errorf("String() = %q", method.String())
+	}
+	if method, err := ParseMethod(""); err != nil || method != DefaultMethod {
+		t.Errorf("ParseMethod(empty) = %+v, %v, want the mean", method, err)
+	}
+
+	for _, value := range []string{
+		`{"name": "mode"}`,
+		`{"name": "median", "trim": 0.1}`,
+		`{"name": "trimmedMean", "trim": 0.5}`,
+		`{"name": "mean", "window": 3}`,
+	} {
+		if method, err := ParseMethod(value); err == nil || method != DefaultMethod {
+			t.Errorf("ParseMethod(%s) = %+v, %v, want the mean and an error", value, method, err)
+		}
+	}
+}
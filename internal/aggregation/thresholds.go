@@ -0,0 +1,124 @@
+package aggregation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// ThresholdsParameterName holds the optional JSON category thresholds; see Thresholds
+const ThresholdsParameterName = "/hourstats/settings/sentiment_thresholds"
+
+// Sentiment categories
+const (
+	CategoryPositive = "positive"
+	CategoryNeutral  = "neutral"
+	CategoryNegative = "negative"
+)
+
+// DefaultThreshold is how far from zero an average compound score must be for its
+// category to be positive or negative rather than neutral
+const DefaultThreshold = 0.3
+
+// Categorize maps an average compound score to positive, negative or neutral, neutral
+// being within threshold of zero
+func Categorize(score, threshold float64) string {
+	switch {
+	case score >= threshold:
+		return CategoryPositive
+	case score <= -threshold:
+		return CategoryNegative
+	default:
+		return CategoryNeutral
+	}
+}
+
+// Thresholds set where a run's overall category changes. Hysteresis widens the boundary
+// into a band either side of the threshold: a run keeps the previous run's category until
+// its score leaves the band, so a score hovering at the boundary doesn't flip the label
+// from one run to the next. Runs record their thresholds in their provenance.
+//
+//	{"threshold": 0.3, "hysteresis": 0.05}
+type Thresholds struct {
+	Threshold float64 `json:"threshold" yaml:"threshold"`
+	// Hysteresis is the half-width of the band around the threshold; 0 disables it
+	Hysteresis float64 `json:"hysteresis,omitempty" yaml:"hysteresis"`
+}
+
+// DefaultThresholds apply when the parameter is missing, without hysteresis
+var DefaultThresholds = Thresholds{Threshold: DefaultThreshold}
+
+// Validate checks the threshold is within the compound score's range and the band
+// doesn't reach zero
+func (t Thresholds) Validate() error {
+	if t.Threshold <= 0 || t.Threshold >= 1 {
+		return fmt.Errorf("threshold must be above 0 and below 1, got %g", t.Threshold)
+	}
+	if t.Hysteresis < 0 || t.Hysteresis >= t.Threshold {
+		return fmt.Errorf("hysteresis must be at least 0 and below the threshold %g, got %g", t.Threshold, t.Hysteresis)
+	}
+	return nil
+}
+
+// Categorize maps score to a category given the previous run's. Entering positive or
+// negative takes a score past the threshold plus the hysteresis, and leaving it a score
+// back past the threshold minus it. Without a previous category, or without hysteresis,
+// the plain threshold applies.
+func (t Thresholds) Categorize(score float64, previous string) string {
+	if t.Hysteresis == 0 || previous == "" {
+		return Categorize(score, t.Threshold)
+	}
+	enter, stay := t.Threshold+t.Hysteresis, t.Threshold-t.Hysteresis
+	switch {
+	case previous == CategoryPositive && score >= stay, score >= enter:
+		return CategoryPositive
+	case previous == CategoryNegative && score <= -stay, score <= -enter:
+		return CategoryNegative
+	default:
+		return CategoryNeutral
+	}
+}
+
+// LoadThresholds reads the category thresholds from SSM. A missing parameter returns
+// DefaultThresholds, as does an invalid one along with its error.
+func LoadThresholds(ctx context.Context, ssmClient ParameterGetter) (Thresholds, error) {
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ThresholdsParameterName),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return DefaultThresholds, nil
+		}
+		return DefaultThresholds, fmt.Errorf("failed to get %s: %w", ThresholdsParameterName, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return DefaultThresholds, nil
+	}
+	return ParseThresholds(*result.Parameter.Value)
+}
+
+// ParseThresholds decodes and validates JSON thresholds over DefaultThresholds
+func ParseThresholds(value string) (Thresholds, error) {
+	thresholds := DefaultThresholds
+	if strings.TrimSpace(value) == "" {
+		return thresholds, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&thresholds); err != nil {
+		return DefaultThresholds, fmt.Errorf("invalid sentiment thresholds JSON: %w", err)
+	}
+	if err := thresholds.Validate(); err != nil {
+		return DefaultThresholds, fmt.Errorf("invalid sentiment thresholds: %w", err)
+	}
+	return thresholds, nil
+}
@@ -0,0 +1,73 @@
+package aggregation
+
+import "testing"
+
+func TestCategorize(t *testing.T) {
+	for score, want := range map[float64]string{
+		0.3:   CategoryPositive,
+		0.29:  CategoryNeutral,
+		0:     CategoryNeutral,
+		-0.29: CategoryNeutral,
+		-0.3:  CategoryNegative,
+	} {
+		if got := Categorize(score, DefaultThreshold); got != want {
+			t.Errorf("Categorize(%.2f) = %s, want %s", score, got, want)
+		}
+	}
+}
+
+func TestThresholdsCategorize(t *testing.T) {
+	thresholds := Thresholds{Threshold: 0.3, Hysteresis: 0.05}
+	tests := []struct {
+		score    float64
+		previous string
+		want     string
+	}{
+		// Without a previous category the plain threshold applies
+		{0.31, "", CategoryPositive},
+		// Entering positive or negative takes a score past the band
+		{0.31, CategoryNeutral, CategoryNeutral},
+		{0.35, CategoryNeutral, CategoryPositive},
+		{-0.34, CategoryNeutral, CategoryNeutral},
+		{-0.35, CategoryPositive, CategoryNegative},
+		// and leaving it a score back past the band
+		{0.26, CategoryPositive, CategoryPositive},
+		{0.24, CategoryPositive, CategoryNeutral},
+		{-0.25, CategoryNegative, CategoryNegative},
+		{-0.2, CategoryNegative, CategoryNeutral},
+	}
+	for _, tt := range tests {
+		if got := thresholds.Categorize(tt.score, tt.previous); got != tt.want {
+			t.Errorf("Categorize(%.2f, %q) = %s, want %s", tt.score, tt.previous, got, tt.want)
+		}
+	}
+
+	if got := DefaultThresholds.Categorize(0.31, CategoryNeutral); got != CategoryPositive {
+		t.Errorf("Expected no hysteresis by default, got %s", got)
+	}
+}
+
+func TestParseThresholds(t *testing.T) {
+	thresholds, err := ParseThresholds(`{"threshold": 0.25, "hysteresis": 0.05}`)
+	if err != nil || thresholds != (Thresholds{Threshold: 0.25, Hysteresis: 0.05}) {
+		t.Errorf("Unexpected thresholds %+v, %v", thresholds, err)
+	}
+	if thresholds, err := ParseThresholds(`{"hysteresis": 0.05}`); err != nil || thresholds.Threshold != DefaultThreshold {
+		t.Errorf("Expected the default threshold, got %+v, %v", thresholds, err)
+	}
+	if thresholds, err := ParseThresholds(""); err != nil || thresholds != DefaultThresholds {
+		t.Errorf("Expected the defaults for an empty value, got %+v, %v", thresholds, err)
+	}
+
+	for _, value := range []string{
+		`{"threshold": 0}`,
+		`{"threshold": 1}`,
+		`{"hysteresis": -0.1}`,
+		`{"threshold": 0.2, "hysteresis": 0.2}`,
+		`{"band": 0.1}`,
+	} {
+		if thresholds, err := ParseThresholds(value); err == nil || thresholds != DefaultThresholds {
+			t.Errorf("Expected an error and the defaults for %s, got %+v", value, thresholds)
+		}
+	}
+}
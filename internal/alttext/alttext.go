@@ -0,0 +1,299 @@
+// Package alttext builds the alt text of the charts the posters attach, from templates a
+// deployment can reword or translate, and keeps it within the length Bluesky accepts.
+package alttext
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+)
+
+// ParameterName holds the optional JSON settings; see Settings
+const ParameterName = "/hourstats/settings/alt_text"
+
+// MaxGraphemes is the longest alt text Bluesky's apps accept
+const MaxGraphemes = 2000
+
+// Stats are the figures a chart's alt text describes
+type Stats struct {
+	Current     float64
+	CurrentTime time.Time
+	Highest     float64
+	HighestTime time.Time
+	Lowest      float64
+	LowestTime  time.Time
+	Average     float64
+	// Trend is the last value minus the first; templates describe its sign
+	Trend float64
+}
+
+// Settings hold the alt text templates. Templates are Go text/template over Stats, with
+// the functions percent (12.3 as "12.3%"), datetime ("Jan 2, 3:04 PM UTC"), date
+// ("Jan 2, 2006") and month ("Jan"); the date functions use Months.
+//
+//	{"weekly": "Sentiment chart, now {{percent .Current}}.", "maxLength": 1000}
+type Settings struct {
+	// Weekly describes the seven day chart, and WeeklyEmpty replaces it when there are
+	// too few points to describe
+	Weekly      string `json:"weekly,omitempty"`
+	WeeklyEmpty string `json:"weeklyEmpty,omitempty"`
+	// Yearly and YearlyEmpty do the same for the yearly chart
+	Yearly      string `json:"yearly,omitempty"`
+	YearlyEmpty string `json:"yearlyEmpty,omitempty"`
+	// Monthly and MonthlyEmpty do the same for the 30 day chart
+	Monthly      string `json:"monthly,omitempty"`
+	MonthlyEmpty string `json:"monthlyEmpty,omitempty"`
+	// Quarterly and QuarterlyEmpty do the same for the quarterly chart
+	Quarterly      string `json:"quarterly,omitempty"`
+	QuarterlyEmpty string `json:"quarterlyEmpty,omitempty"`
+	// Months are the abbreviated month names, January first
+	Months []string `json:"months,omitempty"`
+	// MaxLength caps the alt text in graphemes, at most MaxGraphemes
+	MaxLength int `json:"maxLength,omitempty"`
+}
+
+// DefaultSettings apply when the parameter is missing, and fill in the fields it omits
+var DefaultSettings = Settings{
+	Weekly: "Seven day Bluesky sentiment trend chart. " +
+		"Current sentiment: {{percent .Current}} ({{datetime .CurrentTime}}). " +
+		"Highest sentiment: {{percent .Highest}} ({{datetime .HighestTime}}). " +
+		"Lowest sentiment: {{percent .Lowest}} ({{datetime .LowestTime}}). " +
+		"Average sentiment: {{percent .Average}}. " +
+		"{{if gt .Trend 0.0}}Trending positive over the period.{{else if lt .Trend 0.0}}Trending negative over the period.{{else}}Stable sentiment over the period.{{end}}",
+	WeeklyEmpty: "Seven day sentiment trend chart showing community mood over time",
+	Yearly: "Yearly Bluesky sentiment trend chart showing daily averages over the past year. " +
+		"Current sentiment: {{percent .Current}} ({{date .CurrentTime}}). " +
+		"Highest sentiment: {{percent .Highest}} ({{date .HighestTime}}). " +
+		"Lowest sentiment: {{percent .Lowest}} ({{date .LowestTime}}). " +
+		"Yearly average sentiment: {{percent .Average}}. " +
+		"{{if gt .Trend 0.0}}Trending positive over the year.{{else if lt .Trend 0.0}}Trending negative over the year.{{else}}Stable sentiment over the year.{{end}}",
+	YearlyEmpty: "Yearly sentiment trend chart showing community mood over the past year",
+	Monthly: "30 day Bluesky sentiment trend chart showing daily averages over the past month. " +
+		"Current sentiment: {{percent .Current}} ({{date .CurrentTime}}). " +
+		"Highest sentiment: {{percent .Highest}} ({{date .HighestTime}}). " +
+		"Lowest sentiment: {{percent .Lowest}} ({{date .LowestTime}}). " +
+		"30 day average sentiment: {{percent .Average}}. " +
+		"{{if gt .Trend 0.0}}Trending positive over the month.{{else if lt .Trend 0.0}}Trending negative over the month.{{else}}Stable sentiment over the month.{{end}}",
+	MonthlyEmpty: "30 day sentiment trend chart showing community mood over the past month",
+	Quarterly: "Quarterly Bluesky sentiment trend chart showing daily averages over the quarter. " +
+		"Latest sentiment: {{percent .Current}} ({{date .CurrentTime}}). " +
+		"Highest sentiment: {{percent .Highest}} ({{date .HighestTime}}). " +
+		"Lowest sentiment: {{percent .Lowest}} ({{date .LowestTime}}). " +
+		"Quarterly average sentiment: {{percent .Average}}. " +
+		"{{if gt .Trend 0.0}}Trending positive over the quarter.{{else if lt .Trend 0.0}}Trending negative over the quarter.{{else}}Stable sentiment over the quarter.{{end}}",
+	QuarterlyEmpty: "Quarterly sentiment trend chart showing community mood over the quarter",
+	Months:         []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	MaxLength:      MaxGraphemes,
+}
+
+// Validate checks the settings' bounds and that every template renders
+func (s Settings) Validate() error {
+	if len(s.Months) != 12 {
+		return fmt.Errorf("months must name all 12 months, got %d", len(s.Months))
+	}
+	if s.MaxLength < 1 || s.MaxLength > MaxGraphemes {
+		return fmt.Errorf("maxLength must be between 1 and %d, got %d", MaxGraphemes, s.MaxLength)
+	}
+	_, err := New(s)
+	return err
+}
+
+// ParameterGetter is the subset of the SSM client Load needs
+type ParameterGetter interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Load reads the settings from SSM. A missing parameter returns DefaultSettings, as does
+// an invalid one along with its error.
+func Load(ctx context.Context, ssmClient ParameterGetter) (Settings, error) {
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ParameterName),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return DefaultSettings, nil
+		}
+		return DefaultSettings, fmt.Errorf("failed to get %s: %w", ParameterName, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return DefaultSettings, nil
+	}
+	return Parse(*result.Parameter.Value)
+}
+
+// Parse decodes and validates JSON settings over DefaultSettings
+func Parse(value string) (Settings, error) {
+	settings := DefaultSettings
+	if strings.TrimSpace(value) == "" {
+		return settings, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&settings); err != nil {
+		return DefaultSettings, fmt.Errorf("invalid alt text JSON: %w", err)
+	}
+	if err := settings.Validate(); err != nil {
+		return DefaultSettings, fmt.Errorf("invalid alt text settings: %w", err)
+	}
+	return settings, nil
+}
+
+// Builder renders alt text from compiled settings
+type Builder struct {
+	weekly         *template.Template
+	weeklyEmpty    string
+	yearly         *template.Template
+	yearlyEmpty    string
+	monthly        *template.Template
+	monthlyEmpty   string
+	quarterly      *template.Template
+	quarterlyEmpty string
+	maxLength      int
+}
+
+// New compiles the settings' templates, rendering each once with sample figures so a
+// template naming a missing field fails here rather than when a chart is posted
+func New(settings Settings) (*Builder, error) {
+	months := settings.Months
+	if len(months) != 12 {
+		months = DefaultSettings.Months
+	}
+	funcs := template.FuncMap{
+		"percent": func(v float64) string { return fmt.Sprintf("%.1f%%", v) },
+		"month":   func(t time.Time) string { return months[t.UTC().Month()-1] },
+		"date": func(t time.Time) string {
+			t = t.UTC()
+			return fmt.Sprintf("%s %d, %d", months[t.Month()-1], t.Day(), t.Year())
+		},
+		"datetime": func(t time.Time) string {
+			t = t.UTC()
+			return fmt.Sprintf("%s %d, %s UTC", months[t.Month()-1], t.Day(), t.Format("3:04 PM"))
+		},
+	}
+
+	b := &Builder{
+		weeklyEmpty:    settings.WeeklyEmpty,
+		yearlyEmpty:    settings.YearlyEmpty,
+		monthlyEmpty:   settings.MonthlyEmpty,
+		quarterlyEmpty: settings.QuarterlyEmpty,
+		maxLength:      settings.MaxLength,
+	}
+	if b.maxLength <= 0 || b.maxLength > MaxGraphemes {
+		b.maxLength = MaxGraphemes
+	}
+	for _, t := range []struct {
+		name string
+		text string
+		dest **template.Template
+	}{{"weekly", settings.Weekly, &b.weekly}, {"yearly", settings.Yearly, &b.yearly}, {"monthly", settings.Monthly, &b.monthly}, {"quarterly", settings.Quarterly, &b.quarterly}} {
+		tmpl, err := template.New(t.name).Funcs(funcs).Parse(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s template: %w", t.name, err)
+		}
+		if err := tmpl.Execute(&bytes.Buffer{}, Stats{}); err != nil {
+			return nil, fmt.Errorf("invalid %s template: %w", t.name, err)
+		}
+		*t.dest = tmpl
+	}
+	return b, nil
+}
+
+// Default returns a builder for DefaultSettings, which always compile
+func Default() *Builder {
+	b, err := New(DefaultSettings)
+	if err != nil {
+		panic(fmt.Sprintf("default alt text templates: %v", err))
+	}
+	return b
+}
+
+// Weekly describes the seven day chart, or returns the empty text when stats is nil
+func (b *Builder) Weekly(stats *Stats) string {
+	if stats == nil {
+		return b.weeklyEmpty
+	}
+	return b.render(b.weekly, *stats, b.weeklyEmpty)
+}
+
+// Yearly describes the yearly chart, or returns the empty text when stats is nil
+func (b *Builder) Yearly(stats *Stats) string {
+	if stats == nil {
+		return b.yearlyEmpty
+	}
+	return b.render(b.yearly, *stats, b.yearlyEmpty)
+}
+
+// Monthly describes the 30 day chart, or returns the empty text when stats is nil
+func (b *Builder) Monthly(stats *Stats) string {
+	if stats == nil {
+		return b.monthlyEmpty
+	}
+	return b.render(b.monthly, *stats, b.monthlyEmpty)
+}
+
+// Quarterly describes the quarterly chart, or returns the empty text when stats is nil
+func (b *Builder) Quarterly(stats *Stats) string {
+	if stats == nil {
+		return b.quarterlyEmpty
+	}
+	return b.render(b.quarterly, *stats, b.quarterlyEmpty)
+}
+
+// render executes tmpl, falling back to fallback if it fails
+func (b *Builder) render(tmpl *template.Template, stats Stats, fallback string) string {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, stats); err != nil {
+		log.Printf("Failed to render %s alt text, using the plain description: %v", tmpl.Name(), err)
+		return fallback
+	}
+	return buf.String()
+}
+
+// Join joins the non-empty parts with spaces and truncates the result to the builder's
+// maximum length; put the parts that matter most first
+func (b *Builder) Join(parts ...string) string {
+	kept := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return Truncate(strings.Join(kept, " "), b.maxLength)
+}
+
+// Truncate cuts text to at most limit graphemes. It ends on the last whole sentence when
+// that keeps at least half the text, then on the last whole word, marking a cut within a
+// sentence with an ellipsis.
+func Truncate(text string, limit int) string {
+	if client.GraphemeCount(text) <= limit {
+		return text
+	}
+	cut := strings.TrimSuffix(client.TruncateGraphemes(text, limit), "…")
+	if strings.HasSuffix(cut, ".") {
+		return cut
+	}
+	if i := strings.LastIndex(cut, ". "); i >= len(cut)/2 {
+		return cut[:i+1]
+	}
+	// cut is a prefix of text, so the character after it tells whether its last word is whole
+	if next := text[len(cut)]; next != ' ' && next != '\n' {
+		if i := strings.LastIndexAny(cut, " \n"); i >= len(cut)/2 {
+			cut = cut[:i]
+		}
+	}
+	return strings.TrimRight(cut, " \n,;:") + "…"
+}
@@ -0,0 +1,140 @@
+package alttext
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+)
+
+func testStats() *Stats {
+	return &Stats{
+		Current:     12.34,
+		CurrentTime: time.Date(2025, 3, 25, 14, 5, 0, 0, time.UTC),
+		Highest:     20,
+		HighestTime: time.Date(2025, 3, 22, 9, 30, 0, 0, time.UTC),
+		Lowest:      -5.5,
+		LowestTime:  time.Date(2025, 3, 20, 23, 0, 0, 0, time.UTC),
+		Average:     4.25,
+		Trend:       -1,
+	}
+}
+
+func TestDefaultTemplates(t *testing.T) {
+	b := Default()
+
+	want := "Seven day Bluesky sentiment trend chart. Current sentiment: 12.3% (Mar 25, 2:05 PM UTC). " +
+		"Highest sentiment: 20.0% (Mar 22, 9:30 AM UTC). Lowest sentiment: -5.5% (Mar 20, 11:00 PM UTC). " +
+		"Average sentiment: 4.2%. Trending negative over the period."
+	if got := b.Weekly(testStats()); got != want {
+		t.Errorf("Weekly() = %q, want %q", got, want)
+	}
+
+	want = "Yearly Bluesky sentiment trend chart showing daily averages over the past year. Current sentiment: 12.3% (Mar 25, 2025). " +
+		"Highest sentiment: 20.0% (Mar 22, 2025). Lowest sentiment: -5.5% (Mar 20, 2025). " +
+		"Yearly average sentiment: 4.2%. Trending negative over the year."
+	if got := b.Yearly(testStats()); got != want {
+		t.Errorf("Yearly() = %q, want %q", got, want)
+	}
+
+	want = "30 day Bluesky sentiment trend chart showing daily averages over the past month. Current sentiment: 12.3% (Mar 25, 2025). " +
+		"Highest sentiment: 20.0% (Mar 22, 2025). Lowest sentiment: -5.5% (Mar 20, 2025). " +
+		"30 day average sentiment: 4.2%. Trending negative over the month."
+	if got := b.Monthly(testStats()); got != want {
+		t.Errorf("Monthly() = %q, want %q", got, want)
+	}
+
+	want = "Quarterly Bluesky sentiment trend chart showing daily averages over the quarter. Latest sentiment: 12.3% (Mar 25, 2025). " +
+		"Highest sentiment: 20.0% (Mar 22, 2025). Lowest sentiment: -5.5% (Mar 20, 2025). " +
+		"Quarterly average sentiment: 4.2%. Trending negative over the quarter."
+	if got := b.Quarterly(testStats()); got != want {
+		t.Errorf("Quarterly() = %q, want %q", got, want)
+	}
+
+	if got := b.Weekly(nil); got != DefaultSettings.WeeklyEmpty {
+		t.Errorf("Weekly(nil) = %q, want the empty text", got)
+	}
+	if got := b.Monthly(nil); got != DefaultSettings.MonthlyEmpty {
+		t.Errorf("Monthly(nil) = %q, want the empty text", got)
+	}
+}
+
+func TestParseLocalized(t *testing.T) {
+	settings, err := Parse(`{
+		"weekly": "Stimmung der letzten sieben Tage: {{percent .Current}} am {{datetime .CurrentTime}}.",
+		"months": ["Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"]
+	}`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if settings.Yearly != DefaultSettings.Yearly || settings.MaxLength != MaxGraphemes {
+		t.Errorf("Parse() should keep the defaults for omitted fields, got %+v", settings)
+	}
+
+	b, err := New(settings)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	want := "Stimmung der letzten sieben Tage: 12.3% am Mär 25, 2:05 PM UTC."
+	if got := b.Weekly(testStats()); got != want {
+		t.Errorf("Weekly() = %q, want %q", got, want)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for name, value := range map[string]string{
+		"unknown field":  `{"weekley": "x"}`,
+		"bad template":   `{"weekly": "{{percent .Current"}`,
+		"missing field":  `{"yearly": "{{.Median}}"}`,
+		"unknown func":   `{"weekly": "{{median .Current}}"}`,
+		"short months":   `{"months": ["Jan"]}`,
+		"length too big": `{"maxLength": 5000}`,
+	} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("%s: Parse(%s) succeeded, want error", name, value)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	b, err := New(Settings{Weekly: "x", Yearly: "y", MaxLength: 40})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if got := b.Join("First sentence.", "", "  Second one. "); got != "First sentence. Second one." {
+		t.Errorf("Join() = %q", got)
+	}
+	got := b.Join("The chart shows seven days.", "The mood was mostly positive all week.")
+	if got != "The chart shows seven days." {
+		t.Errorf("Join() over the limit = %q, want it cut after the first sentence", got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		text  string
+		limit int
+		want  string
+	}{
+		{"Short enough.", 20, "Short enough."},
+		{"One sentence here. Another sentence that runs long.", 30, "One sentence here."},
+		{"A sentence made of several words that runs long", 20, "A sentence made of…"},
+		{"Unbreakablewordthatrunslong", 10, "Unbreakab…"},
+	}
+	for _, tt := range tests {
+		got := Truncate(tt.text, tt.limit)
+		if got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.text, tt.limit, got, tt.want)
+		}
+		if client.GraphemeCount(got) > tt.limit {
+			t.Errorf("Truncate(%q, %d) is %d graphemes long", tt.text, tt.limit, client.GraphemeCount(got))
+		}
+	}
+
+	long := strings.Repeat("Sentiment stayed level. ", 200)
+	if got := Truncate(long, MaxGraphemes); client.GraphemeCount(got) > MaxGraphemes || !strings.HasSuffix(got, ".") {
+		t.Errorf("Truncate(long) = %d graphemes ending %q", client.GraphemeCount(got), got[len(got)-10:])
+	}
+}
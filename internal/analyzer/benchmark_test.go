@@ -0,0 +1,115 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Performance budget for AnalyzePosts: a busy run analyzes around 10,000 posts, and
+// sequential analysis must stay well inside the processor's timeout on a single vCPU.
+// The budget is about ten times the measured cost (0.3s), to absorb slow CI runners.
+const (
+	budgetCorpusSize = 10000
+	analyzeBudget    = 3 * time.Second
+)
+
+// corpusTexts mixes languages, lengths, hashtags, emoji and negation like a run's posts
+var corpusTexts = []struct {
+	text  string
+	langs []string
+}{
+	{"Just finished my morning run and honestly feeling amazing. The sunrise over the river was beautiful! #running", []string{"en"}},
+	{"This is absolutely terrible news. I can't believe they let this happen again, what a disaster.", []string{"en"}},
+	{"Anyone else watching the game tonight? Not sure who to root for", []string{"en"}},
+	{"I don't hate it, but the new update isn't great either. Kind of meh tbh 😐", []string{"en"}},
+	{"Thanks so much to everyone who came out to the show last night ❤️🎉 you made it so special #music #art", []string{"en"}},
+	{"The climate report is out and it's grim. We need action on climate change now. #climate #science", nil},
+	{"¡Qué día tan bonito! Estoy muy feliz con los resultados 😊", []string{"es"}},
+	{"No estoy contento con el servicio, fue un desastre total", []string{"es-MX"}},
+	{"Adorei o show de ontem, foi incrível demais! kkkk", []string{"pt-BR"}},
+	{"O trânsito hoje está horrível, não aguento mais", []string{"pt"}},
+	{"今日はとても楽しかった！また行きたい😊", []string{"ja"}},
+	{"電車が遅れて最悪…本当に疲れた", nil},
+	{"lol", []string{"en"}},
+	{"New blog post: a long look at how we rebuilt our data pipeline, what went wrong, what went right, and the lessons we learned along the way about testing, monitoring and keeping the team sane during the migration. Link in reply.", []string{"en"}},
+}
+
+// benchmarkCorpus builds n posts from corpusTexts, varied so no two are identical
+func benchmarkCorpus(n int) []Post {
+	posts := make([]Post, n)
+	for i := range posts {
+		sample := corpusTexts[i%len(corpusTexts)]
+		posts[i] = Post{
+			URI:       fmt.Sprintf("at://did:plc:bench/app.bsky.feed.post/%d", i),
+			Text:      fmt.Sprintf("%s %d", sample.text, i),
+			Author:    fmt.Sprintf("user%d.bsky.social", i%500),
+			Likes:     i % 97,
+			Reposts:   i % 13,
+			Replies:   i % 29,
+			CreatedAt: "2025-09-01T12:00:00Z",
+			Langs:     sample.langs,
+		}
+	}
+	return posts
+}
+
+func BenchmarkAnalyzePosts(b *testing.B) {
+	posts := benchmarkCorpus(budgetCorpusSize)
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			analyzer := New()
+			analyzer.SetWorkers(workers)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := analyzer.AnalyzePosts(posts); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(b.Elapsed().Microseconds())/float64(b.N*len(posts)), "µs/post")
+		})
+	}
+}
+
+func BenchmarkNew(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		New()
+	}
+}
+
+func TestAnalyzePostsBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the performance budget in short mode")
+	}
+
+	analyzer := New()
+	posts := benchmarkCorpus(budgetCorpusSize)
+	start := time.Now()
+	if _, err := analyzer.AnalyzePosts(posts); err != nil {
+		t.Fatalf("AnalyzePosts() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > analyzeBudget {
+		t.Errorf("Analyzing %d posts took %v, over the %v budget", len(posts), elapsed, analyzeBudget)
+	}
+}
+
+func TestAnalyzePostsParallel(t *testing.T) {
+	posts := benchmarkCorpus(1001)
+
+	sequential, err := New().AnalyzePosts(posts)
+	if err != nil {
+		t.Fatalf("AnalyzePosts() error = %v", err)
+	}
+
+	analyzer := New()
+	analyzer.SetWorkers(4)
+	parallel, err := analyzer.AnalyzePosts(posts)
+	if err != nil {
+		t.Fatalf("parallel AnalyzePosts() error = %v", err)
+	}
+	if !reflect.DeepEqual(sequential, parallel) {
+		t.Error("Expected parallel analysis to match sequential analysis, in the same order")
+	}
+}
@@ -0,0 +1,127 @@
+package analyzer
+
+import (
+	"strings"
+)
+
+// Emotions is a post's emotion vector: the share of its emotion words expressing each
+// emotion. The shares sum to 1 when the post has any emotion words and are all 0 otherwise.
+type Emotions struct {
+	Joy     float64
+	Anger   float64
+	Sadness float64
+	Fear    float64
+}
+
+// Total is the sum of the shares, 1 for a post with emotion words and 0 without
+func (e Emotions) Total() float64 {
+	return e.Joy + e.Anger + e.Sadness + e.Fear
+}
+
+// emotionLexicon maps words to the emotions they express, in the style of the NRC
+// Emotion Lexicon: a word can carry more than one emotion ("outrage" is anger, "dread"
+// is fear and sadness). It is deliberately small and lists common inflections rather
+// than stemming.
+var emotionLexicon = buildEmotionLexicon(map[string][]string{
+	"joy": {
+		"happy", "happiness", "joy", "joyful", "glad", "delighted", "delight", "love", "loved", "lovely",
+		"excited", "exciting", "thrilled", "wonderful", "amazing", "awesome", "great", "fantastic",
+		"celebrate", "celebrating", "celebration", "proud", "grateful", "thankful", "blessed", "fun",
+		"laugh", "laughing", "smile", "smiling", "yay", "hooray", "beautiful", "cheerful", "hope",
+		"hopeful", "enjoy", "enjoying", "win", "won", "😂", "😊", "😍", "🥰", "🎉", "❤️", "😄",
+	},
+	"anger": {
+		"angry", "anger", "furious", "fury", "rage", "raging", "mad", "outrage", "outraged",
+		"outrageous", "hate", "hated", "hatred", "annoyed", "annoying", "irritated", "infuriating",
+		"livid", "pissed", "disgusted", "disgusting", "resent", "resentment", "hostile", "fed up",
+		"unacceptable", "ridiculous", "shameful", "betrayed", "betrayal", "😡", "🤬", "😠",
+	},
+	"sadness": {
+		"sad", "sadness", "unhappy", "depressed", "depressing", "heartbroken", "heartbreaking",
+		"grief", "grieving", "mourn", "mourning", "miss", "missing", "lonely", "loneliness", "cry",
+		"crying", "tears", "sorrow", "tragic", "tragedy", "loss", "lost", "gloomy", "miserable",
+		"hopeless", "devastated", "devastating", "dread", "rip", "😢", "😭", "💔", "😞",
+	},
+	"fear": {
+		"afraid", "scared", "scary", "fear", "fearful", "frightened", "terrified", "terrifying",
+		"terror", "panic", "panicking", "anxious", "anxiety", "worried", "worry", "worrying",
+		"nervous", "dread", "alarming", "alarmed", "threat", "threatening", "danger", "dangerous",
+		"unsafe", "horror", "horrifying", "nightmare", "emergency", "evacuate", "😨", "😱", "😰",
+	},
+})
+
+// emotionEntry is the set of emotions a lexicon word expresses
+type emotionEntry struct {
+	joy, anger, sadness, fear bool
+}
+
+func buildEmotionLexicon(words map[string][]string) map[string]emotionEntry {
+	lexicon := make(map[string]emotionEntry)
+	for emotion, list := range words {
+		for _, word := range list {
+			entry := lexicon[word]
+			switch emotion {
+			case "joy":
+				entry.joy = true
+			case "anger":
+				entry.anger = true
+			case "sadness":
+				entry.sadness = true
+			case "fear":
+				entry.fear = true
+			}
+			lexicon[word] = entry
+		}
+	}
+	return lexicon
+}
+
+// ScoreEmotions counts the post's emotion words and returns each emotion's share of them
+// A negated word ("not happy") is skipped rather than flipped, since the lexicon has no
+// opposite for most emotions.
+func ScoreEmotions(text string) Emotions {
+	words := strings.Fields(strings.ToLower(text))
+	var counts Emotions
+	for i, word := range words {
+		word = strings.Trim(word, ".,!?;:\"'()[]*")
+		entry, ok := emotionLexicon[word]
+		if !ok && i+1 < len(words) {
+			entry, ok = emotionLexicon[word+" "+strings.Trim(words[i+1], ".,!?;:\"'()[]*")]
+		}
+		if !ok || (i > 0 && isNegation(words[i-1])) {
+			continue
+		}
+		if entry.joy {
+			counts.Joy++
+		}
+		if entry.anger {
+			counts.Anger++
+		}
+		if entry.sadness {
+			counts.Sadness++
+		}
+		if entry.fear {
+			counts.Fear++
+		}
+	}
+
+	total := counts.Total()
+	if total == 0 {
+		return Emotions{}
+	}
+	return Emotions{
+		Joy:     counts.Joy / total,
+		Anger:   counts.Anger / total,
+		Sadness: counts.Sadness / total,
+		Fear:    counts.Fear / total,
+	}
+}
+
+// isNegation reports whether word negates the word after it
+func isNegation(word string) bool {
+	switch strings.Trim(word, ".,!?;:\"'()") {
+	case "not", "no", "never", "isn't", "wasn't", "aren't", "don't", "didn't", "hardly":
+		return true
+	}
+	return false
+}
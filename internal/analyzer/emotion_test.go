@@ -0,0 +1,36 @@
+package analyzer
+
+import "testing"
+
+func TestScoreEmotions(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Emotions
+	}{
+		{"single emotion", "So happy about this, what a great day!", Emotions{Joy: 1}},
+		{"mixed", "Furious and honestly scared about the news", Emotions{Anger: 0.5, Fear: 0.5}},
+		{"word with two emotions", "A sense of dread", Emotions{Sadness: 0.5, Fear: 0.5}},
+		{"phrase", "I'm fed up", Emotions{Anger: 1}},
+		{"negated", "Not happy, just tired", Emotions{}},
+		{"no emotion words", "The meeting moved to Thursday", Emotions{}},
+		{"emoji", "finally home 😭", Emotions{Sadness: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ScoreEmotions(tt.text); got != tt.want {
+				t.Errorf("ScoreEmotions(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzePostsScoresEmotions(t *testing.T) {
+	analyzed, err := New().AnalyzePosts([]Post{{URI: "test://post/1", Text: "I'm so worried"}})
+	if err != nil {
+		t.Fatalf("AnalyzePosts() error = %v", err)
+	}
+	if analyzed[0].Emotions != (Emotions{Fear: 1}) {
+		t.Errorf("Expected the post's emotions, got %+v", analyzed[0].Emotions)
+	}
+}
@@ -0,0 +1,105 @@
+package analyzer
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/jonreiter/govader"
+)
+
+// Scorer scores a text's sentiment the way VADER does; the analyzer reads the compound score
+type Scorer interface {
+	PolarityScores(text string) govader.Sentiment
+}
+
+// English is the language of the default VADER lexicon, used for posts in languages
+// without a lexicon of their own
+const English = "en"
+
+// VADER's valence adjustments, which govader doesn't export
+const (
+	boostIncrement = 0.293 // added to a valence by a booster word ("muy", "とても")
+	negationScalar = -0.74 // multiplies a negated valence
+)
+
+// Language picks the lexicon for a post: the base of its first declared language tag
+// ("es-MX" -> "es"), or Japanese for undeclared text containing kana, which no other
+// language uses. Undeclared text is otherwise treated as English.
+func Language(post Post) string {
+	if len(post.Langs) > 0 {
+		base, _, _ := strings.Cut(strings.ToLower(post.Langs[0]), "-")
+		if base != "" {
+			return base
+		}
+	}
+	for _, r := range post.Text {
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana) {
+			return "ja"
+		}
+	}
+	return English
+}
+
+// SetLexicon scores posts in lang with scorer instead of the English lexicon
+func (sa *SentimentAnalyzer) SetLexicon(lang string, scorer Scorer) {
+	sa.lexicons[lang] = scorer
+}
+
+// scorerFor returns the scorer for lang and whether it is the English one
+func (sa *SentimentAnalyzer) scorerFor(lang string) (Scorer, bool) {
+	if scorer, ok := sa.lexicons[lang]; ok && lang != English {
+		return scorer, false
+	}
+	return sa.analyzer, true
+}
+
+// newWordLexicon builds a VADER analyzer for a space-separated language from its own
+// words, negations and boosters. The rest of VADER's rules carry over: "no" before a
+// word already negates it, as it does in Spanish and Portuguese, so negations should
+// leave it out. Emoji are described in English, so the English words that describe
+// them are kept to score emoji the same in every language.
+func newWordLexicon(english *govader.SentimentIntensityAnalyzer, words map[string]float64, negations []string, boosters map[string]float64) *govader.SentimentIntensityAnalyzer {
+	lexicon := make(map[string]float64, len(words))
+	for _, description := range english.EmojiDict {
+		for _, word := range strings.Fields(description) {
+			if valence, ok := english.Lexicon[word]; ok {
+				lexicon[word] = valence
+			}
+		}
+	}
+	for word, valence := range words {
+		lexicon[word] = valence
+	}
+
+	regex := govader.NewPythonesqueRegex()
+	regex.PunctuationString += "¡¿«»“”"
+
+	return &govader.SentimentIntensityAnalyzer{
+		Lexicon:   lexicon,
+		EmojiDict: english.EmojiDict,
+		Constants: &govader.TermConstants{
+			NegateList:        negations,
+			BoosterDict:       boosters,
+			LadenIdioms:       map[string]float64{},
+			SpecialCaseIdioms: map[string]float64{},
+			Regex:             regex,
+		},
+	}
+}
+
+// rewriteScorer replaces whole space-separated words before scoring, for words a
+// language shares with English that VADER's built-in English rules would misread
+type rewriteScorer struct {
+	scorer   Scorer
+	rewrites map[string]string
+}
+
+func (rs rewriteScorer) PolarityScores(text string) govader.Sentiment {
+	words := strings.Split(text, " ")
+	for i, word := range words {
+		if rewrite, ok := rs.rewrites[word]; ok {
+			words[i] = rewrite
+		}
+	}
+	return rs.scorer.PolarityScores(strings.Join(words, " "))
+}
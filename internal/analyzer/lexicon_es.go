@@ -0,0 +1,45 @@
+package analyzer
+
+// spanishWords are Spanish sentiment words on VADER's -4 to 4 scale, with common
+// inflections listed rather than stemmed
+var spanishWords = map[string]float64{
+	// Positive
+	"bueno": 1.9, "buena": 1.9, "buenos": 1.9, "buenas": 1.9, "bien": 1.5, "mejor": 2.0, "mejores": 2.0,
+	"excelente": 3.1, "genial": 2.9, "geniales": 2.9, "increíble": 2.8, "increíbles": 2.8,
+	"maravilloso": 3.0, "maravillosa": 3.0, "fantástico": 3.0, "fantástica": 3.0, "perfecto": 2.7,
+	"perfecta": 2.7, "hermoso": 2.7, "hermosa": 2.7, "bonito": 2.2, "bonita": 2.2, "lindo": 2.2,
+	"linda": 2.2, "precioso": 2.7, "preciosa": 2.7, "feliz": 2.8, "felices": 2.8, "felicidad": 3.0,
+	"alegre": 2.4, "alegría": 2.8, "contento": 2.2, "contenta": 2.2, "encanta": 2.9, "encantan": 2.9,
+	"encantado": 2.5, "encantada": 2.5, "amor": 3.2, "amo": 3.0, "quiero": 1.2, "gracias": 1.9,
+	"agradecido": 2.3, "agradecida": 2.3, "divertido": 2.0, "divertida": 2.0, "gusta": 1.9,
+	"gustan": 1.9, "éxito": 2.7, "ganar": 2.0, "ganamos": 2.0, "ganó": 2.0, "esperanza": 1.9,
+	"orgulloso": 2.2, "orgullosa": 2.2, "bendecido": 2.2, "bendecida": 2.2, "tranquilo": 1.2,
+	"tranquila": 1.2, "disfrutar": 2.2, "disfruta": 2.2, "disfrutando": 2.2, "ja": 1.2, "jaja": 1.8,
+	"jajaja": 1.9, "jajajaja": 1.9, "guapo": 2.0, "guapa": 2.0, "bravo": 2.3, "felicidades": 2.8,
+	"felicitaciones": 2.8,
+
+	// Negative
+	"malo": -2.5, "mala": -2.5, "malos": -2.5, "malas": -2.5, "mal": -2.1, "peor": -2.6, "peores": -2.6,
+	"terrible": -2.9, "terribles": -2.9, "horrible": -3.1, "horribles": -3.1, "pésimo": -3.1,
+	"pésima": -3.1, "triste": -2.1, "tristes": -2.1, "tristeza": -2.4, "odio": -3.2, "odia": -3.0,
+	"odian": -3.0, "asco": -2.6, "asqueroso": -2.8, "asquerosa": -2.8, "enojado": -2.2, "enojada": -2.2,
+	"enfadado": -2.2, "enfadada": -2.2, "furioso": -2.9, "furiosa": -2.9, "rabia": -2.6, "miedo": -2.2,
+	"asustado": -2.0, "asustada": -2.0, "preocupado": -1.7, "preocupada": -1.7, "problema": -1.7,
+	"problemas": -1.7, "muerte": -2.9, "muerto": -2.7, "muertos": -2.7, "muerta": -2.7, "guerra": -2.9,
+	"dolor": -2.4, "sufrimiento": -2.8, "crisis": -2.2, "fracaso": -2.6, "perder": -1.5, "perdimos": -1.8,
+	"perdió": -1.8, "culpa": -1.9, "vergüenza": -2.1, "cansado": -1.2, "cansada": -1.2, "aburrido": -1.3,
+	"aburrida": -1.3, "estúpido": -2.4, "estúpida": -2.4, "idiota": -2.5, "mierda": -2.6, "basura": -2.2,
+	"desastre": -3.1, "injusto": -2.1, "injusta": -2.1, "solo": -0.3, "sola": -0.3, "llorar": -2.0,
+	"llorando": -2.0, "violencia": -3.0, "ataque": -2.1, "corrupción": -2.6, "decepcionado": -1.9,
+	"decepcionada": -1.9,
+}
+
+// spanishNegations leaves out "no", which VADER already treats as negating
+var spanishNegations = []string{"nunca", "jamás", "tampoco", "ni", "nada", "nadie", "sin", "ningún", "ninguna"}
+
+var spanishBoosters = map[string]float64{
+	"muy": boostIncrement, "tan": boostIncrement, "super": boostIncrement, "súper": boostIncrement,
+	"demasiado": boostIncrement, "bastante": boostIncrement, "realmente": boostIncrement,
+	"totalmente": boostIncrement, "absolutamente": boostIncrement, "increíblemente": boostIncrement,
+	"poco": -boostIncrement, "apenas": -boostIncrement, "algo": -boostIncrement,
+}
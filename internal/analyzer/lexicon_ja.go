@@ -0,0 +1,161 @@
+package analyzer
+
+import (
+	"math"
+	"strings"
+
+	"github.com/jonreiter/govader"
+)
+
+// japaneseTerms are Japanese sentiment terms on VADER's -4 to 4 scale. Japanese isn't
+// written with spaces, so terms are matched as substrings, longest first; adjectives are
+// listed by their stem ("楽し") so every inflection matches.
+var japaneseTerms = map[string]float64{
+	// Positive
+	"嬉し": 2.8, "うれし": 2.8, "楽し": 2.6, "たのし": 2.6, "幸せ": 3.0, "しあわせ": 3.0, "好き": 2.4,
+	"大好き": 3.1, "愛": 3.0, "最高": 3.2, "素晴らし": 3.0, "すばらし": 3.0, "素敵": 2.7, "すてき": 2.7,
+	"良い": 1.9, "良く": 1.9, "よい": 1.9, "いい": 1.6, "良かった": 2.2, "よかった": 2.2, "可愛": 2.4, "かわい": 2.4,
+	"美し": 2.6, "綺麗": 2.4, "きれい": 2.4, "面白": 2.2, "おもしろ": 2.2, "ありがと": 2.2, "感謝": 2.5,
+	"おめでと": 2.8, "成功": 2.5, "勝っ": 2.0, "勝利": 2.5, "安心": 1.8, "希望": 1.9, "笑": 1.6,
+	"草": 1.4, "すご": 1.8, "凄": 1.8, "感動": 2.6, "やった": 2.2, "ワクワク": 2.4, "わくわく": 2.4,
+	"癒": 2.0, "美味し": 2.4, "おいし": 2.4, "うまい": 2.0, "元気": 1.9, "頑張": 1.2, "がんば": 1.2,
+
+	// Negative
+	"悲し": -2.5, "かなし": -2.5, "寂し": -2.0, "さみし": -2.0, "辛": -2.2, "つら": -2.2, "苦し": -2.4,
+	"嫌い": -2.6, "きらい": -2.6, "大嫌い": -3.1, "嫌": -2.0, "いや": -1.2, "最悪": -3.2, "最低": -3.0,
+	"悪い": -2.3, "わるい": -2.3, "ひどい": -2.7, "酷い": -2.7, "怖": -2.2, "こわ": -2.2, "恐": -2.3,
+	"不安": -2.0, "心配": -1.7, "怒": -2.4, "腹立": -2.6, "ムカつ": -2.6, "むかつ": -2.6, "うざ": -2.2,
+	"死": -2.8, "殺": -3.2, "戦争": -2.9, "事故": -2.3, "地震": -1.6, "災害": -2.5, "被害": -2.3,
+	"失敗": -2.5, "負け": -1.9, "疲れ": -1.5, "つかれ": -1.5, "しんど": -2.0, "痛": -2.0, "泣": -1.8,
+	"残念": -2.0, "無理": -1.6, "困": -1.8, "ダメ": -1.9, "だめ": -1.9, "バカ": -2.4, "ばか": -2.4,
+	"馬鹿": -2.4, "クソ": -2.6, "くそ": -2.6, "うんざり": -2.3, "絶望": -3.1, "憂鬱": -2.4,
+}
+
+// japaneseNegations follow the term they negate: 好きじゃない, 楽しくない, 良くなかった
+var japaneseNegations = []string{
+	"じゃない", "じゃなかった", "ではない", "ではなかった", "くない", "くなかった", "ない", "なかった",
+	"ません", "ませんでした", "ず",
+}
+
+// japaneseBoosters come before the term they strengthen or soften
+var japaneseBoosters = map[string]float64{
+	"とても": boostIncrement, "すごく": boostIncrement, "めっちゃ": boostIncrement, "超": boostIncrement,
+	"本当に": boostIncrement, "ほんとに": boostIncrement, "かなり": boostIncrement, "マジで": boostIncrement,
+	"ちょっと": -boostIncrement, "少し": -boostIncrement, "やや": -boostIncrement,
+}
+
+// japaneseScorer scores Japanese text by matching terms, negations and boosters as
+// substrings, then normalizes the summed valence the way VADER does. Emoji are scored
+// by the English words that describe them, as they are for every other language.
+type japaneseScorer struct {
+	terms    map[string]float64
+	boosters map[string]float64
+	emoji    map[rune]float64
+	longest  int // longest term or booster, in runes
+}
+
+func newJapaneseScorer(english *govader.SentimentIntensityAnalyzer) *japaneseScorer {
+	js := &japaneseScorer{terms: japaneseTerms, boosters: japaneseBoosters, emoji: make(map[rune]float64)}
+	for symbol, description := range english.EmojiDict {
+		runes := []rune(symbol)
+		if len(runes) != 1 {
+			continue
+		}
+		valence := 0.0
+		for _, word := range strings.Fields(description) {
+			valence += english.Lexicon[word]
+		}
+		if valence != 0 {
+			js.emoji[runes[0]] = valence
+		}
+	}
+	for term := range js.terms {
+		js.longest = max(js.longest, len([]rune(term)))
+	}
+	for booster := range js.boosters {
+		js.longest = max(js.longest, len([]rune(booster)))
+	}
+	return js
+}
+
+func (js *japaneseScorer) PolarityScores(text string) govader.Sentiment {
+	runes := []rune(text)
+	var positive, negative, boost float64
+	for i := 0; i < len(runes); {
+		if valence, ok := js.emoji[runes[i]]; ok {
+			positive, negative = addValence(positive, negative, valence)
+			i++
+			continue
+		}
+
+		term, length := js.match(runes[i:])
+		if length == 0 {
+			boost = 0
+			i++
+			continue
+		}
+		i += length
+		if value, ok := js.boosters[term]; ok {
+			boost = value
+			continue
+		}
+
+		valence := js.terms[term]
+		if valence > 0 {
+			valence += boost
+		} else {
+			valence -= boost
+		}
+		boost = 0
+		if negation := negationAt(runes[i:]); negation > 0 {
+			valence *= negationScalar
+			i += negation
+		}
+		positive, negative = addValence(positive, negative, valence)
+	}
+
+	if positive == 0 && negative == 0 {
+		return govader.Sentiment{Neutral: 1}
+	}
+	sum := positive + negative
+	total := positive - negative
+	return govader.Sentiment{
+		Positive: positive / total,
+		Negative: -negative / total,
+		Compound: sum / math.Sqrt(sum*sum+15),
+	}
+}
+
+// match returns the longest term or booster at the start of runes, and its length in runes
+func (js *japaneseScorer) match(runes []rune) (string, int) {
+	for length := min(js.longest, len(runes)); length > 0; length-- {
+		candidate := string(runes[:length])
+		if _, ok := js.terms[candidate]; ok {
+			return candidate, length
+		}
+		if _, ok := js.boosters[candidate]; ok {
+			return candidate, length
+		}
+	}
+	return "", 0
+}
+
+// negationAt returns the length in runes of the longest negation at the start of runes, or 0
+func negationAt(runes []rune) int {
+	following := string(runes[:min(len(runes), 6)]) // long enough for ませんでした
+	longest := 0
+	for _, negation := range japaneseNegations {
+		if strings.HasPrefix(following, negation) {
+			longest = max(longest, len([]rune(negation)))
+		}
+	}
+	return longest
+}
+
+// addValence adds valence to the positive or negative total
+func addValence(positive, negative, valence float64) (float64, float64) {
+	if valence > 0 {
+		return positive + valence, negative
+	}
+	return positive, negative + valence
+}
@@ -0,0 +1,48 @@
+package analyzer
+
+// portugueseWords are Portuguese sentiment words on VADER's -4 to 4 scale, with common
+// inflections listed rather than stemmed
+var portugueseWords = map[string]float64{
+	// Positive
+	"bom": 1.9, "boa": 1.9, "bons": 1.9, "boas": 1.9, "bem": 1.5, "melhor": 2.0, "melhores": 2.0,
+	"ótimo": 2.9, "ótima": 2.9, "excelente": 3.1, "incrível": 2.8, "incríveis": 2.8,
+	"maravilhoso": 3.0, "maravilhosa": 3.0, "fantástico": 3.0, "fantástica": 3.0, "perfeito": 2.7,
+	"perfeita": 2.7, "lindo": 2.4, "linda": 2.4, "lindos": 2.4, "lindas": 2.4, "bonito": 2.2,
+	"bonita": 2.2, "feliz": 2.8, "felizes": 2.8, "felicidade": 3.0, "alegre": 2.4, "alegria": 2.8,
+	"contente": 2.2, "adoro": 3.0, "adorei": 3.0, "amor": 3.2, "amo": 3.0, "amei": 3.0,
+	"obrigado": 1.9, "obrigada": 1.9, "grato": 2.3, "grata": 2.3, "divertido": 2.0, "divertida": 2.0,
+	"gosto": 1.9, "gostei": 1.9, "sucesso": 2.7, "ganhar": 2.0, "ganhamos": 2.0, "ganhou": 2.0,
+	"esperança": 1.9, "orgulhoso": 2.2, "orgulhosa": 2.2, "abençoado": 2.2, "abençoada": 2.2,
+	"tranquilo": 1.2, "tranquila": 1.2, "aproveitar": 1.8, "curtindo": 1.8, "legal": 1.8, "massa": 1.6,
+	"demais": 1.2, "kkk": 1.8, "kkkk": 1.9, "kkkkk": 1.9, "haha": 1.8, "parabéns": 2.8, "saudade": -0.4,
+	"top": 1.8,
+
+	// Negative
+	"mau": -2.5, "má": -2.5, "ruim": -2.5, "ruins": -2.5, "mal": -2.1, "pior": -2.6, "piores": -2.6,
+	"terrível": -2.9, "terríveis": -2.9, "horrível": -3.1, "horríveis": -3.1, "péssimo": -3.1,
+	"péssima": -3.1, "triste": -2.1, "tristes": -2.1, "tristeza": -2.4, "ódio": -3.2, "odeio": -3.2,
+	"odeia": -3.0, "nojo": -2.6, "nojento": -2.8, "nojenta": -2.8, "raiva": -2.6, "irritado": -2.0,
+	"irritada": -2.0, "furioso": -2.9, "furiosa": -2.9, "medo": -2.2, "assustado": -2.0,
+	"assustada": -2.0, "preocupado": -1.7, "preocupada": -1.7, "problema": -1.7, "problemas": -1.7,
+	"morte": -2.9, "morto": -2.7, "mortos": -2.7, "morta": -2.7, "guerra": -2.9, "dor": -2.4,
+	"sofrimento": -2.8, "crise": -2.2, "fracasso": -2.6, "perder": -1.5, "perdemos": -1.8,
+	"perdeu": -1.8, "culpa": -1.9, "vergonha": -2.1, "cansado": -1.2, "cansada": -1.2, "chato": -1.6,
+	"chata": -1.6, "burro": -2.2, "burra": -2.2, "idiota": -2.5, "merda": -2.6, "lixo": -2.2,
+	"desastre": -3.1, "injusto": -2.1, "injusta": -2.1, "sozinho": -0.8, "sozinha": -0.8,
+	"chorar": -2.0, "chorando": -2.0, "violência": -3.0, "ataque": -2.1, "corrupção": -2.6,
+	"decepcionado": -1.9, "decepcionada": -1.9,
+}
+
+// portugueseNegations are the words that negate the sentiment word after them
+var portugueseNegations = []string{"não", "nunca", "jamais", "nem", "nada", "ninguém", "sem", "nenhum", "nenhuma"}
+
+// portugueseRewrites keeps "no" ("in the") from being read as VADER's English "no",
+// which negates the sentiment word after it
+var portugueseRewrites = map[string]string{"no": "em", "No": "Em", "NO": "EM"}
+
+var portugueseBoosters = map[string]float64{
+	"muito": boostIncrement, "muita": boostIncrement, "tão": boostIncrement, "super": boostIncrement,
+	"bastante": boostIncrement, "realmente": boostIncrement, "totalmente": boostIncrement,
+	"absolutamente": boostIncrement, "extremamente": boostIncrement, "pouco": -boostIncrement,
+	"quase": -boostIncrement, "meio": -boostIncrement,
+}
@@ -0,0 +1,62 @@
+package analyzer
+
+import "testing"
+
+func TestLanguage(t *testing.T) {
+	for _, tc := range []struct {
+		post Post
+		want string
+	}{
+		{Post{Text: "Hola", Langs: []string{"es-MX"}}, "es"},
+		{Post{Text: "Olá", Langs: []string{"PT", "en"}}, "pt"},
+		{Post{Text: "今日はとても楽しかった"}, "ja"},
+		{Post{Text: "東京"}, English}, // no kana: could be Chinese
+		{Post{Text: "Hello"}, English},
+	} {
+		if got := Language(tc.post); got != tc.want {
+			t.Errorf("Language(%q, %v) = %q, want %q", tc.post.Text, tc.post.Langs, got, tc.want)
+		}
+	}
+}
+
+func TestLanguageLexicons(t *testing.T) {
+	analyzer := New()
+	for _, tc := range []struct {
+		name string
+		post Post
+		want string
+	}{
+		{"spanish positive", Post{Text: "¡Estoy muy feliz hoy!", Langs: []string{"es"}}, "positive"},
+		{"spanish negated", Post{Text: "No estoy feliz con esto", Langs: []string{"es"}}, "negative"},
+		{"spanish negative", Post{Text: "Qué día tan horrible y triste", Langs: []string{"es"}}, "negative"},
+		{"portuguese positive", Post{Text: "Adorei o show, foi incrível", Langs: []string{"pt-BR"}}, "positive"},
+		{"portuguese no is not a negation", Post{Text: "Foi o melhor dia no Brasil", Langs: []string{"pt"}}, "positive"},
+		{"portuguese negated", Post{Text: "O filme não foi bom", Langs: []string{"pt"}}, "negative"},
+		{"japanese positive", Post{Text: "今日はとても楽しかった！", Langs: []string{"ja"}}, "positive"},
+		{"japanese negated", Post{Text: "この映画は好きじゃない", Langs: []string{"ja"}}, "negative"},
+		{"japanese negative", Post{Text: "最悪の一日で悲しい"}, "negative"},
+		{"japanese neutral", Post{Text: "明日は東京に行きます", Langs: []string{"ja"}}, "neutral"},
+		{"emoji in another language", Post{Text: "Hoy 😍😍", Langs: []string{"es"}}, "positive"},
+		{"unsupported language uses English", Post{Text: "I love it", Langs: []string{"de"}}, "positive"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			analyzed, err := analyzer.analyzePost(tc.post)
+			if err != nil {
+				t.Fatalf("analyzePost() error = %v", err)
+			}
+			if analyzed.Sentiment != tc.want {
+				t.Errorf("analyzePost(%q) = %s (score %.3f), want %s", tc.post.Text, analyzed.Sentiment, analyzed.SentimentScore, tc.want)
+			}
+		})
+	}
+}
+
+func TestSetLexicon(t *testing.T) {
+	analyzer := New()
+	analyzer.SetLexicon("de", newWordLexicon(analyzer.analyzer, map[string]float64{"gut": 2.0}, []string{"nicht"}, nil))
+
+	analyzed, err := analyzer.analyzePost(Post{Text: "Das ist sehr gut", Langs: []string{"de"}})
+	if err != nil || analyzed.Sentiment != "positive" {
+		t.Errorf("Expected the registered lexicon to score German, got %s (%.3f), %v", analyzed.Sentiment, analyzed.SentimentScore, err)
+	}
+}
@@ -0,0 +1,81 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"sort"
+)
+
+// Version numbers the analyzer's scoring rules. Bump it with any change that scores the
+// same text differently without touching a lexicon, such as a new rewrite or threshold,
+// so runs scored before and after can be told apart.
+const Version = 1
+
+// lexiconHashLength is how many hex digits of the SHA-256 LexiconHash keeps
+const lexiconHashLength = 12
+
+// LexiconHash fingerprints the word lists the analyzer scores with: the English VADER
+// lexicon and emoji, the built-in Spanish, Portuguese and Japanese tables, the emotion
+// lexicon, and which languages have a lexicon. A scorer set with SetLexicon counts only
+// by its language, since its word list isn't visible here.
+func (sa *SentimentAnalyzer) LexiconHash() string {
+	h := sha256.New()
+	hashScores(h, "en", sa.analyzer.Lexicon)
+	hashStrings(h, "emoji", sa.analyzer.EmojiDict)
+	hashScores(h, "es", spanishWords)
+	hashList(h, "es-negations", spanishNegations)
+	hashScores(h, "es-boosters", spanishBoosters)
+	hashScores(h, "pt", portugueseWords)
+	hashList(h, "pt-negations", portugueseNegations)
+	hashStrings(h, "pt-rewrites", portugueseRewrites)
+	hashScores(h, "pt-boosters", portugueseBoosters)
+	hashScores(h, "ja", japaneseTerms)
+	hashList(h, "ja-negations", japaneseNegations)
+	hashScores(h, "ja-boosters", japaneseBoosters)
+
+	emotions := make(map[string]string, len(emotionLexicon))
+	for word, entry := range emotionLexicon {
+		emotions[word] = fmt.Sprintf("%+v", entry)
+	}
+	hashStrings(h, "emotions", emotions)
+
+	languages := make([]string, 0, len(sa.lexicons))
+	for lang := range sa.lexicons {
+		languages = append(languages, lang)
+	}
+	hashList(h, "languages", languages)
+
+	return hex.EncodeToString(h.Sum(nil))[:lexiconHashLength]
+}
+
+// hashScores writes a word list to h in sorted order, so the hash doesn't depend on map order
+func hashScores(h hash.Hash, name string, scores map[string]float64) {
+	entries := make(map[string]string, len(scores))
+	for word, score := range scores {
+		entries[word] = fmt.Sprintf("%g", score)
+	}
+	hashStrings(h, name, entries)
+}
+
+func hashStrings(h hash.Hash, name string, entries map[string]string) {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fmt.Fprintf(h, "[%s]\n", name)
+	for _, key := range keys {
+		fmt.Fprintf(h, "%q=%q\n", key, entries[key])
+	}
+}
+
+func hashList(h hash.Hash, name string, list []string) {
+	sorted := append([]string(nil), list...)
+	sort.Strings(sorted)
+	fmt.Fprintf(h, "[%s]\n", name)
+	for _, item := range sorted {
+		fmt.Fprintf(h, "%q\n", item)
+	}
+}
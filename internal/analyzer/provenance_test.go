@@ -0,0 +1,19 @@
+package analyzer
+
+import "testing"
+
+func TestLexiconHash(t *testing.T) {
+	hash := New().LexiconHash()
+	if len(hash) != lexiconHashLength {
+		t.Fatalf("LexiconHash() = %q, want %d hex digits", hash, lexiconHashLength)
+	}
+	if again := New().LexiconHash(); again != hash {
+		t.Errorf("LexiconHash() = %q then %q, want it stable", hash, again)
+	}
+
+	sa := New()
+	sa.SetLexicon("de", sa.analyzer)
+	if got := sa.LexiconHash(); got == hash {
+		t.Errorf("LexiconHash() = %q after adding a language, want it to change", got)
+	}
+}
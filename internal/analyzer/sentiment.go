@@ -3,7 +3,9 @@ package analyzer
 import (
 	"fmt"
 	"strings"
+	"sync"
 
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
 	"github.com/jonreiter/govader"
 )
 
@@ -13,6 +15,7 @@ type AnalyzedPost struct {
 	SentimentScore  float64
 	Topics          []string
 	EngagementScore float64
+	Emotions        Emotions
 }
 
 // Post represents a social media post for analysis
@@ -25,19 +28,43 @@ type Post struct {
 	Reposts   int
 	Replies   int
 	CreatedAt string
+	Langs     []string // Declared language tags, which choose the lexicon
 }
 
 type SentimentAnalyzer struct {
 	analyzer *govader.SentimentIntensityAnalyzer
+	lexicons map[string]Scorer // Scorers for languages other than English
+	workers  int               // Goroutines AnalyzePosts uses; 0 or 1 analyzes in order
 }
 
+// New creates an analyzer with the English VADER lexicon and lexicons for Spanish,
+// Portuguese and Japanese
 func New() *SentimentAnalyzer {
+	english := govader.NewSentimentIntensityAnalyzer()
 	return &SentimentAnalyzer{
-		analyzer: govader.NewSentimentIntensityAnalyzer(),
+		analyzer: english,
+		lexicons: map[string]Scorer{
+			"es": newWordLexicon(english, spanishWords, spanishNegations, spanishBoosters),
+			"pt": rewriteScorer{
+				scorer:   newWordLexicon(english, portugueseWords, portugueseNegations, portugueseBoosters),
+				rewrites: portugueseRewrites,
+			},
+			"ja": newJapaneseScorer(english),
+		},
 	}
 }
 
+// SetWorkers makes AnalyzePosts analyze posts on a pool of n goroutines. Analysis only
+// reads the lexicons, so posts can be analyzed concurrently; results keep the order of posts.
+func (sa *SentimentAnalyzer) SetWorkers(n int) {
+	sa.workers = n
+}
+
 func (sa *SentimentAnalyzer) AnalyzePosts(posts []Post) ([]AnalyzedPost, error) {
+	if sa.workers > 1 && len(posts) > 1 {
+		return sa.analyzePostsParallel(posts)
+	}
+
 	var analyzedPosts []AnalyzedPost
 
 	for _, post := range posts {
@@ -51,19 +78,64 @@ func (sa *SentimentAnalyzer) AnalyzePosts(posts []Post) ([]AnalyzedPost, error)
 	return analyzedPosts, nil
 }
 
-func (sa *SentimentAnalyzer) analyzePost(post Post) (AnalyzedPost, error) {
-	// Analyze sentiment using VADER
-	sentiment := sa.analyzer.PolarityScores(post.Text)
+// parallelBatchSize is how many posts a worker takes at a time: small enough to balance
+// long and short posts across workers, large enough that handing out batches is cheap
+const parallelBatchSize = 256
+
+// analyzePostsParallel analyzes posts on a bounded pool of workers, each taking the next
+// batch of posts and writing results at the posts' own indexes, so the order is the
+// same as the sequential loop. It reports the error for the earliest post that failed.
+func (sa *SentimentAnalyzer) analyzePostsParallel(posts []Post) ([]AnalyzedPost, error) {
+	batches := (len(posts) + parallelBatchSize - 1) / parallelBatchSize
+	analyzedPosts := make([]AnalyzedPost, len(posts))
+	errs := make([]error, batches)
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(sa.workers, batches); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range next {
+				for i := batch * parallelBatchSize; i < min((batch+1)*parallelBatchSize, len(posts)); i++ {
+					analyzedPost, err := sa.analyzePost(posts[i])
+					if err != nil {
+						errs[batch] = fmt.Errorf("failed to analyze post %s: %w", posts[i].URI, err)
+						break
+					}
+					analyzedPosts[i] = analyzedPost
+				}
+			}
+		}()
+	}
+	for batch := 0; batch < batches; batch++ {
+		next <- batch
+	}
+	close(next)
+	wg.Wait()
 
-	// Also do keyword-based sentiment analysis as a fallback
-	keywordSentiment := sa.analyzeKeywordSentiment(post.Text)
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return analyzedPosts, nil
+}
 
-	// Determine sentiment category (combine both approaches)
+func (sa *SentimentAnalyzer) analyzePost(post Post) (AnalyzedPost, error) {
+	// Analyze sentiment using VADER, with the lexicon for the post's language
+	scorer, english := sa.scorerFor(Language(post))
+	sentiment := scorer.PolarityScores(post.Text)
+
+	// Determine sentiment category
 	sentimentCategory := sa.categorizeSentiment(sentiment)
 
 	// If VADER is neutral but keywords suggest otherwise, use keyword sentiment
-	if sentimentCategory == "neutral" && keywordSentiment != "neutral" {
-		sentimentCategory = keywordSentiment
+	// The keywords are English, so other languages rely on their lexicon alone.
+	if english && sentimentCategory == "neutral" {
+		if keywordSentiment := sa.analyzeKeywordSentiment(post.Text); keywordSentiment != "neutral" {
+			sentimentCategory = keywordSentiment
+		}
 	}
 
 	// Extract topics (simple keyword extraction for now)
@@ -78,20 +150,15 @@ func (sa *SentimentAnalyzer) analyzePost(post Post) (AnalyzedPost, error) {
 		SentimentScore:  sentiment.Compound,
 		Topics:          topics,
 		EngagementScore: engagementScore,
+		Emotions:        ScoreEmotions(post.Text),
 	}, nil
 }
 
+// categorizeSentiment labels a single post with the default threshold, which is wide enough
+// to keep neutral language like "okay" neutral; a run's configured thresholds only
+// categorize its average
 func (sa *SentimentAnalyzer) categorizeSentiment(sentiment govader.Sentiment) string {
-	compound := sentiment.Compound
-
-	// Use more nuanced thresholds for better emotion detection
-	// Adjusted thresholds to better handle neutral language like "okay"
-	if compound >= 0.3 {
-		return "positive"
-	} else if compound <= -0.3 {
-		return "negative"
-	}
-	return "neutral"
+	return aggregation.Categorize(sentiment.Compound, aggregation.DefaultThreshold)
 }
 
 func (sa *SentimentAnalyzer) extractTopics(text string) []string {
@@ -0,0 +1,47 @@
+package analyzer
+
+import "github.com/christophergentle/hourstats-bsky/internal/state"
+
+// Apply writes the analysis into a stored post and marks it analyzed, so a later step
+// can read it back with Stored instead of analyzing the post again
+func (ap AnalyzedPost) Apply(post *state.Post) {
+	post.Sentiment = ap.Sentiment
+	post.SentimentScore = ap.SentimentScore
+	post.EngagementScore = ap.EngagementScore
+	post.Topics = ap.Topics
+	post.Emotions = nil
+	if ap.Emotions.Total() > 0 {
+		emotions := state.Emotions(ap.Emotions)
+		post.Emotions = &emotions
+	}
+	post.Analyzed = true
+}
+
+// Stored returns the analysis Apply wrote into a stored post, or false if the post was
+// stored without one
+func Stored(post state.Post) (AnalyzedPost, bool) {
+	if !post.Analyzed {
+		return AnalyzedPost{}, false
+	}
+	analyzed := AnalyzedPost{
+		Post: Post{
+			URI:       post.URI,
+			CID:       post.CID,
+			Text:      post.Text,
+			Author:    post.Author,
+			Likes:     post.Likes,
+			Reposts:   post.Reposts,
+			Replies:   post.Replies,
+			CreatedAt: post.CreatedAt,
+			Langs:     post.Langs,
+		},
+		Sentiment:       post.Sentiment,
+		SentimentScore:  post.SentimentScore,
+		Topics:          post.Topics,
+		EngagementScore: post.EngagementScore,
+	}
+	if post.Emotions != nil {
+		analyzed.Emotions = Emotions(*post.Emotions)
+	}
+	return analyzed, true
+}
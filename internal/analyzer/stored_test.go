@@ -0,0 +1,32 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func TestApplyAndStored(t *testing.T) {
+	post := state.Post{URI: "at://post/1", Text: "I love the #music at this festival, amazing", Likes: 3, Replies: 1, Langs: []string{"en"}}
+	if _, ok := Stored(post); ok {
+		t.Fatal("Expected an unanalyzed post to have no stored analysis")
+	}
+
+	analyzed, err := New().AnalyzePosts([]Post{{URI: post.URI, Text: post.Text, Likes: post.Likes, Replies: post.Replies, Langs: post.Langs}})
+	if err != nil {
+		t.Fatalf("AnalyzePosts() error = %v", err)
+	}
+	analyzed[0].Apply(&post)
+	if !post.Analyzed || post.Sentiment != "positive" || post.Emotions == nil || len(post.Topics) == 0 {
+		t.Fatalf("Expected the analysis applied, got %+v", post)
+	}
+
+	stored, ok := Stored(post)
+	if !ok {
+		t.Fatal("Expected the applied analysis to be stored")
+	}
+	if !reflect.DeepEqual(stored, analyzed[0]) {
+		t.Errorf("Stored() = %+v, want %+v", stored, analyzed[0])
+	}
+}
@@ -0,0 +1,105 @@
+package analyzer
+
+import (
+	"time"
+)
+
+// SentimentSegment is the net sentiment of the posts in one slice of the analysis window
+type SentimentSegment struct {
+	Start               time.Time
+	End                 time.Time
+	PostCount           int
+	NetSentimentPercent float64
+
+	scoreSum float64
+}
+
+// SentimentTrend splits the analysis window into equal segments so the summary can say
+// whether the mood shifted while the window was open
+type SentimentTrend struct {
+	Segments          []SentimentSegment
+	FirstThirdPosts   int
+	FirstThirdPercent float64
+	LastThirdPosts    int
+	LastThirdPercent  float64
+}
+
+// Delta is the change in net sentiment percentage from the first to the last third
+func (t SentimentTrend) Delta() float64 {
+	return t.LastThirdPercent - t.FirstThirdPercent
+}
+
+// HasComparableThirds reports whether both outer thirds hold enough posts for the
+// comparison to mean anything
+func (t SentimentTrend) HasComparableThirds(minPosts int) bool {
+	return t.FirstThirdPosts >= minPosts && t.LastThirdPosts >= minPosts
+}
+
+// CalculateSentimentTrend buckets posts by creation time into segmentCount equal slices
+// of the window and compares the first third of the segments with the last third
+// Compound scores are clamped to the VADER range to match the overall calculation;
+// posts with invalid timestamps or outside the window are ignored
+func CalculateSentimentTrend(posts []AnalyzedPost, windowStart, windowEnd time.Time, segmentCount int) SentimentTrend {
+	var trend SentimentTrend
+
+	window := windowEnd.Sub(windowStart)
+	if window <= 0 || segmentCount < 3 {
+		return trend
+	}
+
+	segmentDuration := window / time.Duration(segmentCount)
+	trend.Segments = make([]SentimentSegment, segmentCount)
+	for i := range trend.Segments {
+		trend.Segments[i].Start = windowStart.Add(time.Duration(i) * segmentDuration)
+		trend.Segments[i].End = windowStart.Add(time.Duration(i+1) * segmentDuration)
+	}
+	trend.Segments[segmentCount-1].End = windowEnd
+
+	for _, post := range posts {
+		postTime, err := time.Parse(time.RFC3339, post.CreatedAt)
+		if err != nil || postTime.Before(windowStart) || postTime.After(windowEnd) {
+			continue
+		}
+
+		index := int(postTime.Sub(windowStart) / segmentDuration)
+		if index >= segmentCount {
+			index = segmentCount - 1
+		}
+
+		score := post.SentimentScore
+		if score > 1.0 {
+			score = 1.0
+		} else if score < -1.0 {
+			score = -1.0
+		}
+
+		trend.Segments[index].PostCount++
+		trend.Segments[index].scoreSum += score
+	}
+
+	third := segmentCount / 3
+	var firstSum, lastSum float64
+	for i := range trend.Segments {
+		segment := &trend.Segments[i]
+		if segment.PostCount > 0 {
+			segment.NetSentimentPercent = segment.scoreSum / float64(segment.PostCount) * 100.0
+		}
+		if i < third {
+			trend.FirstThirdPosts += segment.PostCount
+			firstSum += segment.scoreSum
+		}
+		if i >= segmentCount-third {
+			trend.LastThirdPosts += segment.PostCount
+			lastSum += segment.scoreSum
+		}
+	}
+
+	if trend.FirstThirdPosts > 0 {
+		trend.FirstThirdPercent = firstSum / float64(trend.FirstThirdPosts) * 100.0
+	}
+	if trend.LastThirdPosts > 0 {
+		trend.LastThirdPercent = lastSum / float64(trend.LastThirdPosts) * 100.0
+	}
+
+	return trend
+}
@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCalculateSentimentTrend(t *testing.T) {
+	start := time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC)
+	end := start.Add(60 * time.Minute)
+
+	post := func(minute int, score float64) AnalyzedPost {
+		return AnalyzedPost{
+			Post:           Post{CreatedAt: start.Add(time.Duration(minute) * time.Minute).Format(time.RFC3339)},
+			SentimentScore: score,
+		}
+	}
+
+	posts := []AnalyzedPost{
+		post(1, -0.5),
+		post(15, -0.1), // second segment, still in the first third
+		post(30, 0.9),  // middle third is not compared
+		post(45, 0.2),
+		post(59, 1.4),  // clamped to 1.0
+		post(90, -1.0), // outside the window
+		{Post: Post{CreatedAt: "not a time"}, SentimentScore: -1.0},
+	}
+
+	trend := CalculateSentimentTrend(posts, start, end, 6)
+
+	if len(trend.Segments) != 6 {
+		t.Fatalf("expected 6 segments, got %d", len(trend.Segments))
+	}
+	if trend.FirstThirdPosts != 2 || trend.LastThirdPosts != 2 {
+		t.Errorf("expected 2 posts in each outer third, got %d and %d", trend.FirstThirdPosts, trend.LastThirdPosts)
+	}
+	if math.Abs(trend.FirstThirdPercent-(-30.0)) > 0.001 {
+		t.Errorf("expected first third at -30%%, got %.3f", trend.FirstThirdPercent)
+	}
+	if math.Abs(trend.LastThirdPercent-60.0) > 0.001 {
+		t.Errorf("expected last third at 60%%, got %.3f", trend.LastThirdPercent)
+	}
+	if math.Abs(trend.Delta()-90.0) > 0.001 {
+		t.Errorf("expected delta of 90, got %.3f", trend.Delta())
+	}
+	if trend.Segments[3].PostCount != 1 || math.Abs(trend.Segments[3].NetSentimentPercent-90.0) > 0.001 {
+		t.Errorf("unexpected middle segment: %+v", trend.Segments[3])
+	}
+
+	if !trend.HasComparableThirds(2) {
+		t.Error("expected thirds with 2 posts each to be comparable at a minimum of 2")
+	}
+	if trend.HasComparableThirds(3) {
+		t.Error("expected thirds with 2 posts each not to be comparable at a minimum of 3")
+	}
+}
+
+func TestCalculateSentimentTrendEmptyWindow(t *testing.T) {
+	start := time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC)
+
+	trend := CalculateSentimentTrend(nil, start, start, 6)
+	if len(trend.Segments) != 0 || trend.HasComparableThirds(1) {
+		t.Errorf("expected an empty trend for a zero-length window, got %+v", trend)
+	}
+}
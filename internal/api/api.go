@@ -0,0 +1,461 @@
+// Package api serves a read-only JSON view of runs, sentiment history, daily aggregates and
+// percentiles, top posts and engagement on the bot's own posts, so dashboards can integrate without access to the DynamoDB tables
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+const (
+	// DefaultLimit is the page size when a request does not set limit
+	DefaultLimit = 50
+	// MaxLimit caps the page size
+	MaxLimit = 500
+
+	// DefaultWindow is how far back runs, history and top posts are read without since
+	DefaultWindow = 24 * time.Hour
+	// MaxRunWindow matches the state table's TTL; older runs no longer exist
+	MaxRunWindow = 48 * time.Hour
+	// MaxHistoryWindow matches the sentiment history table's TTL
+	MaxHistoryWindow = 14 * 24 * time.Hour
+
+	// DefaultDays and MaxDays bound the daily aggregates returned
+	DefaultDays = 30
+	MaxDays     = 365
+)
+
+// RunReader reads run state; satisfied by *state.StateManager
+type RunReader interface {
+	GetRecentRuns(ctx context.Context, since time.Duration) ([]state.RunState, error)
+}
+
+// HistoryReader reads per-run sentiment; satisfied by *state.SentimentHistoryManager
+type HistoryReader interface {
+	GetSentimentHistory(ctx context.Context, duration time.Duration) ([]state.SentimentDataPoint, error)
+}
+
+// DailyReader reads daily aggregates; satisfied by *state.DailySentimentManager
+type DailyReader interface {
+	GetDailySentimentHistory(ctx context.Context, days int) ([]state.DailySentimentDataPoint, error)
+	GetSentimentPercentile(ctx context.Context, date string, average float64, days int) (*state.SentimentPercentile, error)
+}
+
+// SelfStatsReader reads engagement on the bot's own posts; satisfied by *state.SelfStatsManager
+type SelfStatsReader interface {
+	GetPostStats(ctx context.Context, from, to time.Time) ([]state.PostStats, error)
+}
+
+// Request is a transport-independent API request
+type Request struct {
+	Method string
+	Path   string
+	Query  map[string]string
+}
+
+// Response is a status code and a body to encode as JSON
+type Response struct {
+	StatusCode int
+	Body       any
+}
+
+// Page is one page of a list endpoint; pass NextCursor as cursor to get the next page
+type Page struct {
+	Data       any    `json:"data"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// Error is the body of every error response
+type Error struct {
+	Error string `json:"error"`
+}
+
+// Run is the public view of a run. Fetch cursors, audit trails and TTLs are left out.
+type Run struct {
+	RunID                   string       `json:"runId"`
+	Status                  string       `json:"status"`
+	CreatedAt               time.Time    `json:"createdAt"`
+	UpdatedAt               time.Time    `json:"updatedAt"`
+	CutoffTime              time.Time    `json:"cutoffTime"`
+	AnalysisIntervalMinutes int          `json:"analysisIntervalMinutes"`
+	TotalPostsRetrieved     int          `json:"totalPostsRetrieved"`
+	CoveragePercent         float64      `json:"coveragePercent,omitempty"`
+	OverallSentiment        string       `json:"overallSentiment,omitempty"`
+	NetSentimentPercentage  float64      `json:"netSentimentPercentage,omitempty"`
+	FeedLabel               string       `json:"feedLabel,omitempty"`
+	SkipReason              string       `json:"skipReason,omitempty"`
+	TopPosts                []state.Post `json:"topPosts,omitempty"`
+}
+
+// Handler routes requests to the readers
+type Handler struct {
+	runs      RunReader
+	history   HistoryReader
+	daily     DailyReader
+	selfStats SelfStatsReader
+	now       func() time.Time
+}
+
+// NewHandler creates a handler over the given readers
+func NewHandler(runs RunReader, history HistoryReader, daily DailyReader, selfStats SelfStatsReader) *Handler {
+	return &Handler{runs: runs, history: history, daily: daily, selfStats: selfStats, now: time.Now}
+}
+
+// badRequest is returned by parsers for invalid query parameters
+type badRequest struct{ message string }
+
+func (e badRequest) Error() string { return e.message }
+
+// Serve handles one request:
+//
+//	GET /runs?since=24h&status=completed&sentiment=positive
+//	GET /runs/{runId}
+//	GET /history?since=7d&sentiment=negative
+//	GET /daily?days=90&from=2025-01-01&to=2025-01-31
+//	GET /percentile?days=90
+//	GET /posts/top?since=24h&sentiment=positive&author=alice.bsky.social
+//	GET /selfstats?days=30&kind=image
+//
+// List endpoints take limit and cursor and return a Page.
+func (h *Handler) Serve(ctx context.Context, req Request) Response {
+	if req.Method != http.MethodGet {
+		return errorResponse(http.StatusMethodNotAllowed, "only GET is supported")
+	}
+
+	path := strings.Trim(req.Path, "/")
+	var (
+		body any
+		err  error
+	)
+	switch {
+	case path == "runs":
+		body, err = h.listRuns(ctx, req.Query)
+	case strings.HasPrefix(path, "runs/") && !strings.Contains(strings.TrimPrefix(path, "runs/"), "/"):
+		body, err = h.getRun(ctx, strings.TrimPrefix(path, "runs/"))
+		if err == nil && body == nil {
+			return errorResponse(http.StatusNotFound, "run not found")
+		}
+	case path == "history":
+		body, err = h.listHistory(ctx, req.Query)
+	case path == "daily":
+		body, err = h.listDaily(ctx, req.Query)
+	case path == "percentile":
+		body, err = h.getPercentile(ctx, req.Query)
+		if err == nil && body == nil {
+			return errorResponse(http.StatusNotFound, "not enough history to rank today")
+		}
+	case path == "posts/top":
+		body, err = h.listTopPosts(ctx, req.Query)
+	case path == "selfstats":
+		body, err = h.listSelfStats(ctx, req.Query)
+	default:
+		return errorResponse(http.StatusNotFound, "unknown path /"+path)
+	}
+
+	if err != nil {
+		if bad, ok := err.(badRequest); ok {
+			return errorResponse(http.StatusBadRequest, bad.message)
+		}
+		log.Printf("API request %s failed: %v", req.Path, err)
+		return errorResponse(http.StatusInternalServerError, "internal error")
+	}
+	return Response{StatusCode: http.StatusOK, Body: body}
+}
+
+func (h *Handler) listRuns(ctx context.Context, query map[string]string) (any, error) {
+	since, err := parseWindow(query["since"], MaxRunWindow)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := h.runs.GetRecentRuns(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read runs: %w", err)
+	}
+
+	result := []Run{}
+	for i := range runs {
+		run := &runs[i]
+		if !matches(query["status"], run.Status) || !matches(query["sentiment"], run.OverallSentiment) {
+			continue
+		}
+		summary := publicRun(run)
+		summary.TopPosts = nil
+		result = append(result, summary)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+
+	return paginate(result, query)
+}
+
+// getRun returns nil if the run does not exist or has expired
+func (h *Handler) getRun(ctx context.Context, runID string) (any, error) {
+	runs, err := h.runs.GetRecentRuns(ctx, MaxRunWindow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read runs: %w", err)
+	}
+	for i := range runs {
+		if runs[i].RunID == runID {
+			return publicRun(&runs[i]), nil
+		}
+	}
+	return nil, nil
+}
+
+func (h *Handler) listHistory(ctx context.Context, query map[string]string) (any, error) {
+	since, err := parseWindow(query["since"], MaxHistoryWindow)
+	if err != nil {
+		return nil, err
+	}
+	points, err := h.history.GetSentimentHistory(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sentiment history: %w", err)
+	}
+
+	result := []state.SentimentDataPoint{}
+	for _, point := range points {
+		if matches(query["sentiment"], point.SentimentCategory) {
+			result = append(result, point)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Timestamp.After(result[j].Timestamp) })
+
+	return paginate(result, query)
+}
+
+func (h *Handler) listDaily(ctx context.Context, query map[string]string) (any, error) {
+	days, err := parseInt(query["days"], DefaultDays, MaxDays, "days")
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range []string{"from", "to"} {
+		if value := query[key]; value != "" {
+			if _, err := time.Parse("2006-01-02", value); err != nil {
+				return nil, badRequest{fmt.Sprintf("%s must be a YYYY-MM-DD date", key)}
+			}
+		}
+	}
+
+	points, err := h.daily.GetDailySentimentHistory(ctx, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read daily sentiment: %w", err)
+	}
+
+	result := []state.DailySentimentDataPoint{}
+	for _, point := range points {
+		if from := query["from"]; from != "" && point.Date < from {
+			continue
+		}
+		if to := query["to"]; to != "" && point.Date > to {
+			continue
+		}
+		result = append(result, point)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Date > result[j].Date })
+
+	return paginate(result, query)
+}
+
+// getPercentile ranks today's average sentiment so far among the daily averages of the
+// days before it; nil means there are no runs today or too few days to rank against
+func (h *Handler) getPercentile(ctx context.Context, query map[string]string) (any, error) {
+	days, err := parseInt(query["days"], state.PercentileDays, MaxDays, "days")
+	if err != nil {
+		return nil, err
+	}
+
+	now := h.now().UTC()
+	today := now.Truncate(24 * time.Hour)
+	points, err := h.history.GetSentimentHistory(ctx, now.Sub(today))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sentiment history: %w", err)
+	}
+	average, runs := state.DayAverage(points, now)
+	if runs == 0 {
+		return nil, nil
+	}
+
+	rank, err := h.daily.GetSentimentPercentile(ctx, today.Format("2006-01-02"), average, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank daily sentiment: %w", err)
+	}
+	if rank == nil {
+		return nil, nil
+	}
+	return rank, nil
+}
+
+// listTopPosts merges the top posts of recent runs, keeping each post's highest
+// engagement score, most engaging first
+func (h *Handler) listTopPosts(ctx context.Context, query map[string]string) (any, error) {
+	since, err := parseWindow(query["since"], MaxRunWindow)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := h.runs.GetRecentRuns(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read runs: %w", err)
+	}
+
+	byURI := make(map[string]state.Post)
+	for _, run := range runs {
+		for _, post := range run.TopPosts {
+			if !matches(query["sentiment"], post.Sentiment) || !matches(query["author"], post.Author) {
+				continue
+			}
+			if existing, ok := byURI[post.URI]; !ok || post.EngagementScore > existing.EngagementScore {
+				byURI[post.URI] = post
+			}
+		}
+	}
+
+	result := make([]state.Post, 0, len(byURI))
+	for _, post := range byURI {
+		result = append(result, post)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].EngagementScore != result[j].EngagementScore {
+			return result[i].EngagementScore > result[j].EngagementScore
+		}
+		return result[i].URI < result[j].URI
+	})
+
+	return paginate(result, query)
+}
+
+// listSelfStats returns the mean engagement per post on the bot's own posts, per day and
+// kind of post, most recent first
+func (h *Handler) listSelfStats(ctx context.Context, query map[string]string) (any, error) {
+	days, err := parseInt(query["days"], DefaultDays, MaxDays, "days")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	posts, err := h.selfStats.GetPostStats(ctx, now.AddDate(0, 0, -days), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read self stats: %w", err)
+	}
+
+	result := []state.EngagementTrendPoint{}
+	for _, point := range state.EngagementTrend(posts) {
+		if matches(query["kind"], point.Kind) {
+			result = append(result, point)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Date > result[j].Date })
+
+	return paginate(result, query)
+}
+
+// publicRun copies the public fields of a run
+func publicRun(run *state.RunState) Run {
+	return Run{
+		RunID:                   run.RunID,
+		Status:                  run.Status,
+		CreatedAt:               run.CreatedAt,
+		UpdatedAt:               run.UpdatedAt,
+		CutoffTime:              run.CutoffTime,
+		AnalysisIntervalMinutes: run.AnalysisIntervalMinutes,
+		TotalPostsRetrieved:     run.TotalPostsRetrieved,
+		CoveragePercent:         run.CoveragePercent,
+		OverallSentiment:        run.OverallSentiment,
+		NetSentimentPercentage:  run.NetSentimentPercentage,
+		FeedLabel:               run.FeedLabel,
+		SkipReason:              run.SkipReason,
+		TopPosts:                run.TopPosts,
+	}
+}
+
+// matches reports whether value passes an optional case-insensitive filter
+func matches(filter, value string) bool {
+	return filter == "" || strings.EqualFold(filter, value)
+}
+
+// paginate returns the page of items selected by the limit and cursor parameters.
+// Cursors are opaque offsets into the filtered, sorted list.
+func paginate[T any](items []T, query map[string]string) (Page, error) {
+	limit, err := parseInt(query["limit"], DefaultLimit, MaxLimit, "limit")
+	if err != nil {
+		return Page{}, err
+	}
+
+	offset := 0
+	if cursor := query["cursor"]; cursor != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err == nil {
+			offset, err = strconv.Atoi(string(decoded))
+		}
+		if err != nil || offset < 0 {
+			return Page{}, badRequest{"invalid cursor"}
+		}
+	}
+
+	if offset > len(items) {
+		offset = len(items)
+	}
+	end := min(offset+limit, len(items))
+
+	page := Page{Data: items[offset:end]}
+	if end < len(items) {
+		page.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(end)))
+	}
+	return page, nil
+}
+
+// parseWindow parses a since parameter: a Go duration ("90m", "24h") or a number of days
+// ("7d"). Empty means DefaultWindow, capped at maxWindow.
+func parseWindow(value string, maxWindow time.Duration) (time.Duration, error) {
+	if value == "" {
+		return min(DefaultWindow, maxWindow), nil
+	}
+
+	var window time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, badRequest{fmt.Sprintf("invalid since %q", value)}
+		}
+		window = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if window, err = time.ParseDuration(value); err != nil {
+			return 0, badRequest{fmt.Sprintf("invalid since %q", value)}
+		}
+	}
+
+	if window <= 0 {
+		return 0, badRequest{"since must be positive"}
+	}
+	if window > maxWindow {
+		return 0, badRequest{fmt.Sprintf("since can be at most %s", maxWindow)}
+	}
+	return window, nil
+}
+
+// parseInt parses a positive integer parameter, defaulting when empty
+func parseInt(value string, fallback, maxValue int, name string) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, badRequest{fmt.Sprintf("%s must be a positive integer", name)}
+	}
+	if n > maxValue {
+		return 0, badRequest{fmt.Sprintf("%s can be at most %d", name, maxValue)}
+	}
+	return n, nil
+}
+
+func errorResponse(status int, message string) Response {
+	return Response{StatusCode: status, Body: Error{Error: message}}
+}
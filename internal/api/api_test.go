@@ -0,0 +1,248 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+type fakeStore struct {
+	runs    []state.RunState
+	history []state.SentimentDataPoint
+	daily   []state.DailySentimentDataPoint
+	posts   []state.PostStats
+	err     error
+	since   time.Duration
+}
+
+func (f *fakeStore) GetRecentRuns(ctx context.Context, since time.Duration) ([]state.RunState, error) {
+	f.since = since
+	return f.runs, f.err
+}
+
+func (f *fakeStore) GetSentimentHistory(ctx context.Context, duration time.Duration) ([]state.SentimentDataPoint, error) {
+	f.since = duration
+	return f.history, f.err
+}
+
+func (f *fakeStore) GetDailySentimentHistory(ctx context.Context, days int) ([]state.DailySentimentDataPoint, error) {
+	return f.daily, f.err
+}
+
+func (f *fakeStore) GetSentimentPercentile(ctx context.Context, date string, average float64, days int) (*state.SentimentPercentile, error) {
+	return state.RankSentiment(f.daily, date, average, days), f.err
+}
+
+func (f *fakeStore) GetPostStats(ctx context.Context, from, to time.Time) ([]state.PostStats, error) {
+	f.since = to.Sub(from)
+	return f.posts, f.err
+}
+
+func newTestHandler() (*Handler, *fakeStore) {
+	start := time.Date(2025, 9, 2, 12, 0, 0, 0, time.UTC)
+	store := &fakeStore{
+		runs: []state.RunState{
+			{RunID: "run-1", Status: "completed", OverallSentiment: "positive", CreatedAt: start, TopPosts: []state.Post{
+				{URI: "at://a/app.bsky.feed.post/1", Author: "alice.bsky.social", Sentiment: "positive", EngagementScore: 10},
+				{URI: "at://b/app.bsky.feed.post/2", Author: "bob.bsky.social", Sentiment: "negative", EngagementScore: 40},
+			}},
+			{RunID: "run-2", Status: "completed", OverallSentiment: "negative", CreatedAt: start.Add(30 * time.Minute), TopPosts: []state.Post{
+				{URI: "at://a/app.bsky.feed.post/1", Author: "alice.bsky.social", Sentiment: "positive", EngagementScore: 60},
+			}},
+			{RunID: "run-3", Status: "failed", CreatedAt: start.Add(time.Hour)},
+		},
+		history: []state.SentimentDataPoint{
+			{RunID: "run-1", Timestamp: start, SentimentCategory: "positive"},
+			{RunID: "run-2", Timestamp: start.Add(30 * time.Minute), SentimentCategory: "negative"},
+		},
+		daily: []state.DailySentimentDataPoint{
+			{Date: "2025-08-30"}, {Date: "2025-09-01"}, {Date: "2025-08-31"},
+		},
+		posts: []state.PostStats{
+			{Kind: "image", PostedAt: start, Likes: 10},
+			{Kind: "image", PostedAt: start.Add(time.Hour), Likes: 20, Reposts: 2},
+			{Kind: "text", PostedAt: start, Likes: 3},
+			{Kind: "image", PostedAt: start.Add(24 * time.Hour), Likes: 8},
+		},
+	}
+	return NewHandler(store, store, store, store), store
+}
+
+// decode round-trips a response body through JSON, as clients see it
+func decode(t *testing.T, resp Response, v any) {
+	t.Helper()
+	data, err := json.Marshal(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to marshal body: %v", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("failed to unmarshal %s: %v", data, err)
+	}
+}
+
+func TestListRunsFiltersAndPaginates(t *testing.T) {
+	handler, store := newTestHandler()
+
+	resp := handler.Serve(context.Background(), Request{Method: "GET", Path: "/runs", Query: map[string]string{"status": "completed", "limit": "1"}})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %+v", resp.StatusCode, resp.Body)
+	}
+	if store.since != DefaultWindow {
+		t.Errorf("Expected the default window, got %v", store.since)
+	}
+
+	var page struct {
+		Data       []Run  `json:"data"`
+		NextCursor string `json:"nextCursor"`
+	}
+	decode(t, resp, &page)
+	if len(page.Data) != 1 || page.Data[0].RunID != "run-2" || page.NextCursor == "" {
+		t.Fatalf("Expected run-2 first with a next cursor, got %+v", page)
+	}
+	if len(page.Data[0].TopPosts) != 0 {
+		t.Errorf("Expected run lists to leave out top posts")
+	}
+
+	resp = handler.Serve(context.Background(), Request{Method: "GET", Path: "/runs", Query: map[string]string{"status": "completed", "limit": "1", "cursor": page.NextCursor}})
+	page.NextCursor = ""
+	decode(t, resp, &page)
+	if len(page.Data) != 1 || page.Data[0].RunID != "run-1" || page.NextCursor != "" {
+		t.Errorf("Expected run-1 on the last page, got %+v", page)
+	}
+}
+
+func TestGetRun(t *testing.T) {
+	handler, _ := newTestHandler()
+
+	resp := handler.Serve(context.Background(), Request{Method: "GET", Path: "/runs/run-1"})
+	var run Run
+	decode(t, resp, &run)
+	if resp.StatusCode != http.StatusOK || run.RunID != "run-1" || len(run.TopPosts) != 2 {
+		t.Errorf("Expected run-1 with its top posts, got %d %+v", resp.StatusCode, run)
+	}
+
+	if resp := handler.Serve(context.Background(), Request{Method: "GET", Path: "/runs/missing"}); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown run, got %d", resp.StatusCode)
+	}
+}
+
+func TestListTopPosts(t *testing.T) {
+	handler, _ := newTestHandler()
+
+	resp := handler.Serve(context.Background(), Request{Method: "GET", Path: "/posts/top"})
+	var page struct {
+		Data []state.Post `json:"data"`
+	}
+	decode(t, resp, &page)
+	if len(page.Data) != 2 || page.Data[0].EngagementScore != 60 || page.Data[1].EngagementScore != 40 {
+		t.Errorf("Expected posts deduplicated by URI, most engaging first, got %+v", page.Data)
+	}
+
+	resp = handler.Serve(context.Background(), Request{Method: "GET", Path: "/posts/top", Query: map[string]string{"author": "BOB.bsky.social"}})
+	decode(t, resp, &page)
+	if len(page.Data) != 1 || page.Data[0].Author != "bob.bsky.social" {
+		t.Errorf("Expected only bob's post, got %+v", page.Data)
+	}
+}
+
+func TestListHistoryAndDaily(t *testing.T) {
+	handler, store := newTestHandler()
+
+	resp := handler.Serve(context.Background(), Request{Method: "GET", Path: "/history", Query: map[string]string{"since": "7d", "sentiment": "negative"}})
+	var history struct {
+		Data []state.SentimentDataPoint `json:"data"`
+	}
+	decode(t, resp, &history)
+	if store.since != 7*24*time.Hour || len(history.Data) != 1 || history.Data[0].RunID != "run-2" {
+		t.Errorf("Expected run-2 from a 7-day window, got %v %+v", store.since, history.Data)
+	}
+
+	resp = handler.Serve(context.Background(), Request{Method: "GET", Path: "/daily", Query: map[string]string{"from": "2025-08-31"}})
+	var daily struct {
+		Data []state.DailySentimentDataPoint `json:"data"`
+	}
+	decode(t, resp, &daily)
+	if len(daily.Data) != 2 || daily.Data[0].Date != "2025-09-01" || daily.Data[1].Date != "2025-08-31" {
+		t.Errorf("Expected days from 2025-08-31, most recent first, got %+v", daily.Data)
+	}
+}
+
+func TestListSelfStats(t *testing.T) {
+	handler, store := newTestHandler()
+
+	resp := handler.Serve(context.Background(), Request{Method: "GET", Path: "/selfstats", Query: map[string]string{"days": "7", "kind": "image"}})
+	var page struct {
+		Data []state.EngagementTrendPoint `json:"data"`
+	}
+	decode(t, resp, &page)
+	if store.since != 7*24*time.Hour {
+		t.Errorf("Expected a 7-day window, got %v", store.since)
+	}
+	if len(page.Data) != 2 || page.Data[0].Date != "2025-09-03" || page.Data[1].Posts != 2 || page.Data[1].Total != 16 {
+		t.Errorf("Expected image posts per day, most recent first, got %+v", page.Data)
+	}
+}
+
+func TestGetPercentile(t *testing.T) {
+	handler, store := newTestHandler()
+	handler.now = func() time.Time { return time.Date(2025, 9, 2, 13, 0, 0, 0, time.UTC) }
+
+	resp := handler.Serve(context.Background(), Request{Method: "GET", Path: "/percentile"})
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 with only 3 days of history, got %d", resp.StatusCode)
+	}
+
+	store.daily = nil
+	for day := 1; day <= 20; day++ {
+		store.daily = append(store.daily, state.DailySentimentDataPoint{
+			Date:             time.Date(2025, 8, day+12, 0, 0, 0, 0, time.UTC).Format("2006-01-02"),
+			AverageSentiment: float64(day),
+		})
+	}
+	store.history[0].NetSentimentPercent, store.history[1].NetSentimentPercent = 10, 11
+
+	resp = handler.Serve(context.Background(), Request{Method: "GET", Path: "/percentile", Query: map[string]string{"days": "30"}})
+	var rank state.SentimentPercentile
+	decode(t, resp, &rank)
+	if store.since != 13*time.Hour || rank.Date != "2025-09-02" || rank.Days != 20 || rank.Percentile != 50 {
+		t.Errorf("Expected today's 10.5%% at the 50th percentile of 20 days, got %v %+v", store.since, rank)
+	}
+}
+
+func TestServeErrors(t *testing.T) {
+	handler, store := newTestHandler()
+
+	tests := []struct {
+		name string
+		req  Request
+		want int
+	}{
+		{"method", Request{Method: "POST", Path: "/runs"}, http.StatusMethodNotAllowed},
+		{"path", Request{Method: "GET", Path: "/tables"}, http.StatusNotFound},
+		{"window too long", Request{Method: "GET", Path: "/runs", Query: map[string]string{"since": "3d"}}, http.StatusBadRequest},
+		{"bad limit", Request{Method: "GET", Path: "/history", Query: map[string]string{"limit": "0"}}, http.StatusBadRequest},
+		{"bad cursor", Request{Method: "GET", Path: "/runs", Query: map[string]string{"cursor": "!!"}}, http.StatusBadRequest},
+		{"bad date", Request{Method: "GET", Path: "/daily", Query: map[string]string{"to": "yesterday"}}, http.StatusBadRequest},
+		{"too many days", Request{Method: "GET", Path: "/selfstats", Query: map[string]string{"days": "400"}}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if resp := handler.Serve(context.Background(), tt.req); resp.StatusCode != tt.want {
+				t.Errorf("Expected %d, got %d: %+v", tt.want, resp.StatusCode, resp.Body)
+			}
+		})
+	}
+
+	store.err = errors.New("dynamodb unavailable")
+	resp := handler.Serve(context.Background(), Request{Method: "GET", Path: "/runs"})
+	var body Error
+	decode(t, resp, &body)
+	if resp.StatusCode != http.StatusInternalServerError || body.Error != "internal error" {
+		t.Errorf("Expected a generic 500, got %d %+v", resp.StatusCode, body)
+	}
+}
@@ -56,7 +56,7 @@ func Backup(ctx context.Context, options BackupOptions) (*BackupResult, error) {
 
 	manifest := Manifest{
 		BackupTimestamp: timestamp,
-		BackupVersion:   "1.0",
+		BackupVersion:   backupFormatVersion,
 		Tables:          []TableManifest{},
 	}
 
@@ -197,7 +197,7 @@ func Backup(ctx context.Context, options BackupOptions) (*BackupResult, error) {
 
 		s3Prefix := options.S3Prefix
 		if s3Prefix == "" {
-			s3Prefix = "hourstats-backup"
+			s3Prefix = DefaultS3Prefix
 		}
 		s3Prefix = fmt.Sprintf("%s/backup-%s", s3Prefix, timestamp)
 
@@ -224,7 +224,7 @@ func writeItemsJSONL(filePath string, items []map[string]types.AttributeValue) (
 
 	for _, item := range items {
 		// Convert DynamoDB attribute map to JSON
-		itemJSON, err := json.Marshal(item)
+		itemJSON, err := marshalItem(item)
 		if err != nil {
 			return totalSize, fmt.Errorf("failed to marshal item: %w", err)
 		}
@@ -258,13 +258,16 @@ func writeItemsJSONLCompressed(filePath string, items []map[string]types.Attribu
 	// Use compress/gzip for compression
 	gzipWriter := gzip.NewWriter(file)
 
-	encoder := json.NewEncoder(gzipWriter)
-
 	for _, item := range items {
-		if err := encoder.Encode(item); err != nil {
+		itemJSON, err := marshalItem(item)
+		if err != nil {
 			gzipWriter.Close()
 			return 0, fmt.Errorf("failed to encode item: %w", err)
 		}
+		if _, err := gzipWriter.Write(append(itemJSON, '\n')); err != nil {
+			gzipWriter.Close()
+			return 0, fmt.Errorf("failed to write item: %w", err)
+		}
 	}
 
 	if err := gzipWriter.Close(); err != nil {
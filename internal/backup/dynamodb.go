@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -11,6 +12,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
 )
 
 // DynamoDBClient wraps DynamoDB operations for backup/restore
@@ -20,7 +22,7 @@ type DynamoDBClient struct {
 
 // NewDynamoDBClient creates a new DynamoDB client
 func NewDynamoDBClient(ctx context.Context) (*DynamoDBClient, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := config.LoadDefaultConfig(ctx, tracing.WithAWS)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
@@ -179,3 +181,31 @@ func ConvertFromDynamoDBItem(av map[string]types.AttributeValue, out interface{}
 	return attributevalue.UnmarshalMap(av, out)
 }
 
+
+// ScanKeys scans only the given key attributes of every item in a table
+// Projecting the keys keeps the read cost low when comparing a table against a backup
+func (d *DynamoDBClient) ScanKeys(ctx context.Context, tableName string, keyNames []string) ([]map[string]types.AttributeValue, error) {
+	names := make(map[string]string, len(keyNames))
+	projection := make([]string, len(keyNames))
+	for i, name := range keyNames {
+		placeholder := fmt.Sprintf("#k%d", i) // Key names such as "date" are reserved words
+		names[placeholder] = name
+		projection[i] = placeholder
+	}
+
+	var keys []map[string]types.AttributeValue
+	paginator := dynamodb.NewScanPaginator(d.client, &dynamodb.ScanInput{
+		TableName:                aws.String(tableName),
+		ProjectionExpression:     aws.String(strings.Join(projection, ", ")),
+		ExpressionAttributeNames: names,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan keys of table %s: %w", tableName, err)
+		}
+		keys = append(keys, page.Items...)
+	}
+
+	return keys, nil
+}
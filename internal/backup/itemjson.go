@@ -0,0 +1,165 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Items are stored in DynamoDB JSON, the typed format used by the DynamoDB API and
+// AWS exports ({"S":"..."}, {"N":"..."}, ...). encoding/json cannot round-trip
+// types.AttributeValue on its own because it is an interface: marshalling drops
+// the type and unmarshalling fails, so backups written before format 2.0 only
+// contain {"Value":...} and cannot be restored.
+
+// backupFormatVersion is recorded in the manifest of every new backup
+const backupFormatVersion = "2.0"
+
+// marshalItem encodes an item as DynamoDB JSON
+func marshalItem(item map[string]types.AttributeValue) ([]byte, error) {
+	encoded, err := encodeAttributeMap(item)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(encoded)
+}
+
+// unmarshalItem decodes an item from DynamoDB JSON
+func unmarshalItem(data []byte) (map[string]types.AttributeValue, error) {
+	var raw map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	return decodeAttributeMap(raw)
+}
+
+func encodeAttributeMap(item map[string]types.AttributeValue) (map[string]interface{}, error) {
+	encoded := make(map[string]interface{}, len(item))
+	for name, av := range item {
+		value, err := encodeAttributeValue(av)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s: %w", name, err)
+		}
+		encoded[name] = value
+	}
+	return encoded, nil
+}
+
+func encodeAttributeValue(av types.AttributeValue) (map[string]interface{}, error) {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		return map[string]interface{}{"S": v.Value}, nil
+	case *types.AttributeValueMemberN:
+		return map[string]interface{}{"N": v.Value}, nil
+	case *types.AttributeValueMemberB:
+		return map[string]interface{}{"B": v.Value}, nil // []byte marshals as base64
+	case *types.AttributeValueMemberBOOL:
+		return map[string]interface{}{"BOOL": v.Value}, nil
+	case *types.AttributeValueMemberNULL:
+		return map[string]interface{}{"NULL": v.Value}, nil
+	case *types.AttributeValueMemberSS:
+		return map[string]interface{}{"SS": v.Value}, nil
+	case *types.AttributeValueMemberNS:
+		return map[string]interface{}{"NS": v.Value}, nil
+	case *types.AttributeValueMemberBS:
+		return map[string]interface{}{"BS": v.Value}, nil
+	case *types.AttributeValueMemberM:
+		m, err := encodeAttributeMap(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"M": m}, nil
+	case *types.AttributeValueMemberL:
+		list := make([]interface{}, len(v.Value))
+		for i, elem := range v.Value {
+			encoded, err := encodeAttributeValue(elem)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = encoded
+		}
+		return map[string]interface{}{"L": list}, nil
+	default:
+		return nil, fmt.Errorf("unsupported attribute value type %T", av)
+	}
+}
+
+func decodeAttributeMap(raw map[string]map[string]json.RawMessage) (map[string]types.AttributeValue, error) {
+	item := make(map[string]types.AttributeValue, len(raw))
+	for name, value := range raw {
+		av, err := decodeAttributeValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s: %w", name, err)
+		}
+		item[name] = av
+	}
+	return item, nil
+}
+
+func decodeAttributeValue(raw map[string]json.RawMessage) (types.AttributeValue, error) {
+	if len(raw) != 1 {
+		return nil, fmt.Errorf("expected exactly one type key, got %d", len(raw))
+	}
+
+	for typ, data := range raw {
+		switch typ {
+		case "S":
+			var v string
+			err := json.Unmarshal(data, &v)
+			return &types.AttributeValueMemberS{Value: v}, err
+		case "N":
+			var v string
+			err := json.Unmarshal(data, &v)
+			return &types.AttributeValueMemberN{Value: v}, err
+		case "B":
+			var v []byte
+			err := json.Unmarshal(data, &v)
+			return &types.AttributeValueMemberB{Value: v}, err
+		case "BOOL":
+			var v bool
+			err := json.Unmarshal(data, &v)
+			return &types.AttributeValueMemberBOOL{Value: v}, err
+		case "NULL":
+			var v bool
+			err := json.Unmarshal(data, &v)
+			return &types.AttributeValueMemberNULL{Value: v}, err
+		case "SS":
+			var v []string
+			err := json.Unmarshal(data, &v)
+			return &types.AttributeValueMemberSS{Value: v}, err
+		case "NS":
+			var v []string
+			err := json.Unmarshal(data, &v)
+			return &types.AttributeValueMemberNS{Value: v}, err
+		case "BS":
+			var v [][]byte
+			err := json.Unmarshal(data, &v)
+			return &types.AttributeValueMemberBS{Value: v}, err
+		case "M":
+			var m map[string]map[string]json.RawMessage
+			if err := json.Unmarshal(data, &m); err != nil {
+				return nil, err
+			}
+			decoded, err := decodeAttributeMap(m)
+			return &types.AttributeValueMemberM{Value: decoded}, err
+		case "L":
+			var list []map[string]json.RawMessage
+			if err := json.Unmarshal(data, &list); err != nil {
+				return nil, err
+			}
+			decoded := make([]types.AttributeValue, len(list))
+			for i, elem := range list {
+				av, err := decodeAttributeValue(elem)
+				if err != nil {
+					return nil, err
+				}
+				decoded[i] = av
+			}
+			return &types.AttributeValueMemberL{Value: decoded}, nil
+		default:
+			return nil, fmt.Errorf("unknown attribute type %q", typ)
+		}
+	}
+	return nil, nil // unreachable
+}
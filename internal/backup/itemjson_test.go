@@ -0,0 +1,46 @@
+package backup
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestItemJSONRoundTrip(t *testing.T) {
+	item := map[string]types.AttributeValue{
+		"postId": &types.AttributeValueMemberS{Value: "orchestrator"},
+		"ttl":    &types.AttributeValueMemberN{Value: "1735689600"},
+		"blob":   &types.AttributeValueMemberB{Value: []byte{0x00, 0xff}},
+		"done":   &types.AttributeValueMemberBOOL{Value: true},
+		"none":   &types.AttributeValueMemberNULL{Value: true},
+		"tags":   &types.AttributeValueMemberSS{Value: []string{"a", "b"}},
+		"nums":   &types.AttributeValueMemberNS{Value: []string{"1", "2.5"}},
+		"blobs":  &types.AttributeValueMemberBS{Value: [][]byte{{0x01}}},
+		"posts": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+			&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+				"uri":   &types.AttributeValueMemberS{Value: "at://did/app.bsky.feed.post/1"},
+				"likes": &types.AttributeValueMemberN{Value: "42"},
+			}},
+		}},
+	}
+
+	data, err := marshalItem(item)
+	if err != nil {
+		t.Fatalf("marshalItem: %v", err)
+	}
+	decoded, err := unmarshalItem(data)
+	if err != nil {
+		t.Fatalf("unmarshalItem(%s): %v", data, err)
+	}
+	if !reflect.DeepEqual(decoded, item) {
+		t.Errorf("round trip mismatch:\n got %#v\nwant %#v", decoded, item)
+	}
+}
+
+func TestUnmarshalItemRejectsUntypedLegacyFormat(t *testing.T) {
+	// Backups before format 2.0 were written with encoding/json directly, which drops the type
+	if _, err := unmarshalItem([]byte(`{"postId":{"Value":"orchestrator"}}`)); err == nil {
+		t.Error("expected an error decoding an untyped item")
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"bufio"
 	"compress/gzip"
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"os"
@@ -36,42 +35,11 @@ type RestoreResult struct {
 
 // Restore restores data from a backup
 func Restore(ctx context.Context, options RestoreOptions) (*RestoreResult, error) {
-	// Download from S3 if specified
-	restorePath := options.InputPath
-	if options.S3Bucket != "" {
-		tempDir, err := os.MkdirTemp("", "dynamodb-restore-*")
-		if err != nil {
-			return nil, fmt.Errorf("failed to create temp directory: %w", err)
-		}
-		defer os.RemoveAll(tempDir)
-
-		s3Client, err := NewS3Client(ctx, options.S3Bucket)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create S3 client: %w", err)
-		}
-
-		log.Printf("Downloading backup from s3://%s/%s", options.S3Bucket, options.S3Prefix)
-		if err := s3Client.DownloadDirectory(ctx, options.S3Prefix, tempDir); err != nil {
-			return nil, fmt.Errorf("failed to download from S3: %w", err)
-		}
-
-		// Find the backup directory (should be the only subdirectory)
-		entries, err := os.ReadDir(tempDir)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read temp directory: %w", err)
-		}
-
-		for _, entry := range entries {
-			if entry.IsDir() {
-				restorePath = filepath.Join(tempDir, entry.Name())
-				break
-			}
-		}
-
-		if restorePath == options.InputPath {
-			return nil, fmt.Errorf("failed to find backup directory in S3 download")
-		}
+	restorePath, cleanup, err := resolveBackupPath(ctx, options.InputPath, options.S3Bucket, options.S3Prefix)
+	if err != nil {
+		return nil, err
 	}
+	defer cleanup()
 
 	// Read manifest
 	manifestPath := filepath.Join(restorePath, "manifest.json")
@@ -120,13 +88,18 @@ func Restore(ctx context.Context, options RestoreOptions) (*RestoreResult, error
 
 		// Read items from JSONL file
 		filePath := filepath.Join(restorePath, tableManifest.FileName)
-		items, err := readItemsJSONL(filePath, tableManifest.FileName)
+		items, skipped, err := readItemsJSONL(filePath, tableManifest.FileName)
 		if err != nil {
 			errMsg := fmt.Sprintf("Failed to read items for table %s: %v", tableName, err)
 			result.Errors = append(result.Errors, errMsg)
 			log.Printf("Error: %s", errMsg)
 			continue
 		}
+		if skipped > 0 {
+			errMsg := fmt.Sprintf("Skipped %d unreadable items for table %s (backup format %s)", skipped, tableName, manifest.BackupVersion)
+			result.Errors = append(result.Errors, errMsg)
+			log.Printf("Error: %s", errMsg)
+		}
 
 		if options.DryRun {
 			log.Printf("[DRY RUN] Would restore %d items to table %s", len(items), tableName)
@@ -165,11 +138,60 @@ func Restore(ctx context.Context, options RestoreOptions) (*RestoreResult, error
 	return result, nil
 }
 
+// resolveBackupPath returns a local directory holding the backup, downloading it
+// from S3 when a bucket is given. The cleanup func removes any downloaded copy
+func resolveBackupPath(ctx context.Context, inputPath, s3Bucket, s3Prefix string) (string, func(), error) {
+	if s3Bucket == "" {
+		return inputPath, func() {}, nil
+	}
+
+	tempDir, err := os.MkdirTemp("", "dynamodb-restore-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(tempDir) }
+
+	s3Client, err := NewS3Client(ctx, s3Bucket)
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	log.Printf("Downloading backup from s3://%s/%s", s3Bucket, s3Prefix)
+	if err := s3Client.DownloadDirectory(ctx, s3Prefix, tempDir); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to download from S3: %w", err)
+	}
+
+	// The backup files sit either directly in the download or in its only subdirectory
+	if _, err := os.Stat(filepath.Join(tempDir, "manifest.json")); err == nil {
+		return tempDir, cleanup, nil
+	}
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to read temp directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			return filepath.Join(tempDir, entry.Name()), cleanup, nil
+		}
+	}
+
+	cleanup()
+	return "", nil, fmt.Errorf("failed to find backup directory in S3 download")
+}
+
+// maxItemLineSize bounds a single JSONL line; DynamoDB items are at most 400KB
+// but the typed JSON encoding is larger than the stored item
+const maxItemLineSize = 4 * 1024 * 1024
+
 // readItemsJSONL reads items from a JSONL file (or gzip-compressed JSONL)
-func readItemsJSONL(filePath string, fileName string) ([]map[string]types.AttributeValue, error) {
+// Lines that cannot be decoded are skipped and counted in the second return value
+func readItemsJSONL(filePath string, fileName string) ([]map[string]types.AttributeValue, int, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, 0, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
@@ -180,7 +202,7 @@ func readItemsJSONL(filePath string, fileName string) ([]map[string]types.Attrib
 	if strings.HasSuffix(fileName, ".gz") {
 		gzipReader, err := gzip.NewReader(file)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+			return nil, 0, fmt.Errorf("failed to create gzip reader: %w", err)
 		}
 		defer gzipReader.Close()
 		scanner = bufio.NewScanner(gzipReader)
@@ -188,14 +210,18 @@ func readItemsJSONL(filePath string, fileName string) ([]map[string]types.Attrib
 		scanner = bufio.NewScanner(file)
 	}
 
+	scanner.Buffer(make([]byte, 0, 64*1024), maxItemLineSize)
+
 	lineNum := 0
+	skipped := 0
 	for scanner.Scan() {
 		lineNum++
 		line := scanner.Bytes()
 
-		var item map[string]types.AttributeValue
-		if err := json.Unmarshal(line, &item); err != nil {
+		item, err := unmarshalItem(line)
+		if err != nil {
 			log.Printf("Warning: failed to parse item on line %d: %v", lineNum, err)
+			skipped++
 			continue
 		}
 
@@ -203,9 +229,9 @@ func readItemsJSONL(filePath string, fileName string) ([]map[string]types.Attrib
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, 0, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	return items, nil
+	return items, skipped, nil
 }
 
@@ -0,0 +1,142 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultS3Prefix is the S3 prefix backups are stored under when none is given
+const DefaultS3Prefix = "hourstats-backup"
+
+// BackupSet identifies one backup stored in S3
+type BackupSet struct {
+	Prefix    string // S3 prefix of the backup directory, e.g. hourstats-backup/backup-2025-01-05T03-00-00Z
+	Timestamp time.Time
+}
+
+// RetentionPolicy keeps the newest backup of each of the last KeepDaily days and
+// the newest backup of each of the last KeepWeekly ISO weeks; everything else expires
+type RetentionPolicy struct {
+	KeepDaily  int
+	KeepWeekly int
+}
+
+// DefaultRetentionPolicy keeps 7 daily and 4 weekly backups
+var DefaultRetentionPolicy = RetentionPolicy{KeepDaily: 7, KeepWeekly: 4}
+
+// RetentionResult reports what a retention pass kept and deleted
+type RetentionResult struct {
+	Kept           []BackupSet
+	Expired        []BackupSet
+	ObjectsDeleted int
+}
+
+// ListBackups returns the backups stored under prefix, newest first
+func ListBackups(ctx context.Context, s3Client *S3Client, prefix string) ([]BackupSet, error) {
+	keys, err := s3Client.ListObjects(ctx, prefix+"/backup-")
+	if err != nil {
+		return nil, err
+	}
+	return backupSetsFromKeys(prefix, keys), nil
+}
+
+// backupSetsFromKeys groups object keys into backup directories, newest first
+func backupSetsFromKeys(prefix string, keys []string) []BackupSet {
+	seen := make(map[string]bool)
+	var backups []BackupSet
+	for _, key := range keys {
+		rest := strings.TrimPrefix(key, prefix+"/")
+		dir, _, found := strings.Cut(rest, "/")
+		if !found || !strings.HasPrefix(dir, "backup-") || seen[dir] {
+			continue
+		}
+		timestamp, err := ParseBackupTimestamp(strings.TrimPrefix(dir, "backup-"))
+		if err != nil {
+			continue // Not a backup directory written by Backup
+		}
+		seen[dir] = true
+		backups = append(backups, BackupSet{Prefix: prefix + "/" + dir, Timestamp: timestamp})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Timestamp.After(backups[j].Timestamp)
+	})
+	return backups
+}
+
+// Select splits backups (newest first) into those the policy keeps and those that expire
+func (p RetentionPolicy) Select(backups []BackupSet) (keep, expire []BackupSet) {
+	kept := make(map[string]bool)
+	days := make(map[string]bool)
+	weeks := make(map[string]bool)
+
+	for _, b := range backups {
+		day := b.Timestamp.UTC().Format("2006-01-02")
+		if !days[day] && len(days) < p.KeepDaily {
+			days[day] = true
+			kept[b.Prefix] = true
+		}
+
+		year, week := b.Timestamp.UTC().ISOWeek()
+		weekKey := fmt.Sprintf("%d-W%02d", year, week)
+		if !weeks[weekKey] && len(weeks) < p.KeepWeekly {
+			weeks[weekKey] = true
+			kept[b.Prefix] = true
+		}
+	}
+
+	for _, b := range backups {
+		if kept[b.Prefix] {
+			keep = append(keep, b)
+		} else {
+			expire = append(expire, b)
+		}
+	}
+	return keep, expire
+}
+
+// ApplyRetention deletes the backups under prefix that the policy no longer keeps
+// With dryRun set, nothing is deleted and the result lists what would expire
+func ApplyRetention(ctx context.Context, s3Client *S3Client, prefix string, policy RetentionPolicy, dryRun bool) (*RetentionResult, error) {
+	backups, err := ListBackups(ctx, s3Client, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	keep, expire := policy.Select(backups)
+	result := &RetentionResult{Kept: keep, Expired: expire}
+
+	for _, b := range expire {
+		if dryRun {
+			log.Printf("Retention (dry run): would delete %s", b.Prefix)
+			continue
+		}
+		deleted, err := s3Client.DeletePrefix(ctx, b.Prefix+"/")
+		result.ObjectsDeleted += deleted
+		if err != nil {
+			return result, fmt.Errorf("failed to delete expired backup %s: %w", b.Prefix, err)
+		}
+		log.Printf("Retention: deleted %s (%d objects)", b.Prefix, deleted)
+	}
+
+	return result, nil
+}
+
+// ReadRemoteManifest reads the manifest of a backup stored in S3
+func ReadRemoteManifest(ctx context.Context, s3Client *S3Client, b BackupSet) (*Manifest, error) {
+	data, err := s3Client.ReadObject(ctx, b.Prefix+"/manifest.json")
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	return &manifest, nil
+}
@@ -0,0 +1,81 @@
+package backup
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func backupsEvery(start time.Time, step time.Duration, n int) []BackupSet {
+	backups := make([]BackupSet, n)
+	for i := 0; i < n; i++ {
+		ts := start.Add(-time.Duration(i) * step)
+		backups[i] = BackupSet{Prefix: "hourstats-backup/backup-" + ts.Format("2006-01-02T15-04-05Z"), Timestamp: ts}
+	}
+	return backups
+}
+
+func TestRetentionPolicySelectNightly(t *testing.T) {
+	// 60 nightly backups, newest first, ending on Sunday 2025-03-30
+	start := time.Date(2025, 3, 30, 3, 0, 0, 0, time.UTC)
+	backups := backupsEvery(start, 24*time.Hour, 60)
+
+	keep, expire := DefaultRetentionPolicy.Select(backups)
+
+	// 7 daily (Mar 24-30) plus the newest of the three earlier ISO weeks:
+	// Mar 23, Mar 16, Mar 9 (Mar 30 already covers its own week)
+	want := []string{"2025-03-30", "2025-03-29", "2025-03-28", "2025-03-27", "2025-03-26",
+		"2025-03-25", "2025-03-24", "2025-03-23", "2025-03-16", "2025-03-09"}
+	if len(keep) != len(want) {
+		t.Fatalf("kept %d backups, want %d", len(keep), len(want))
+	}
+	for i, b := range keep {
+		if got := b.Timestamp.Format("2006-01-02"); got != want[i] {
+			t.Errorf("keep[%d] = %s, want %s", i, got, want[i])
+		}
+	}
+	if len(expire) != 60-len(want) {
+		t.Errorf("expired %d backups, want %d", len(expire), 60-len(want))
+	}
+}
+
+func TestRetentionPolicySelectKeepsNewestPerDay(t *testing.T) {
+	// Two backups on the same day: only the newer one counts towards the daily slot
+	start := time.Date(2025, 3, 30, 15, 0, 0, 0, time.UTC)
+	backups := backupsEvery(start, 12*time.Hour, 3)
+
+	keep, expire := RetentionPolicy{KeepDaily: 1}.Select(backups)
+	if len(keep) != 1 || keep[0].Prefix != backups[0].Prefix {
+		t.Fatalf("keep = %v, want only %s", keep, backups[0].Prefix)
+	}
+	if len(expire) != 2 {
+		t.Errorf("expired %d backups, want 2", len(expire))
+	}
+}
+
+func TestRetentionPolicySelectEmpty(t *testing.T) {
+	keep, expire := DefaultRetentionPolicy.Select(nil)
+	if len(keep) != 0 || len(expire) != 0 {
+		t.Errorf("Select(nil) = %v, %v, want nothing", keep, expire)
+	}
+}
+
+func TestBackupSetsFromKeys(t *testing.T) {
+	keys := []string{
+		"hourstats-backup/backup-2025-03-29T03-00-00Z/manifest.json",
+		"hourstats-backup/backup-2025-03-29T03-00-00Z/hourstats-state.jsonl.gz",
+		"hourstats-backup/backup-2025-03-30T03-00-00Z/manifest.json",
+		"hourstats-backup/backup-not-a-timestamp/manifest.json",
+		"hourstats-backup/stray-file.json",
+	}
+
+	backups := backupSetsFromKeys("hourstats-backup", keys)
+	if len(backups) != 2 {
+		t.Fatalf("got %d backups, want 2: %v", len(backups), backups)
+	}
+	for i, want := range []string{"2025-03-30T03-00-00Z", "2025-03-29T03-00-00Z"} {
+		if got := backups[i].Prefix; got != fmt.Sprintf("hourstats-backup/backup-%s", want) {
+			t.Errorf("backups[%d].Prefix = %s, want backup-%s", i, got, want)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
 )
 
 // S3Client wraps S3 operations for backup/restore
@@ -22,7 +23,7 @@ type S3Client struct {
 
 // NewS3Client creates a new S3 client
 func NewS3Client(ctx context.Context, bucket string) (*S3Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := config.LoadDefaultConfig(ctx, tracing.WithAWS)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
@@ -254,3 +255,53 @@ func (s *S3Client) DownloadDirectory(ctx context.Context, s3Prefix string, local
 	return nil
 }
 
+// ReadObject returns the contents of a single object
+func (s *S3Client) ReadObject(ctx context.Context, key string) ([]byte, error) {
+	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read s3://%s/%s: %w", s.bucket, key, err)
+	}
+	defer result.Body.Close()
+
+	return io.ReadAll(result.Body)
+}
+
+// DeletePrefix deletes every object under a prefix, returning how many were deleted
+func (s *S3Client) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	keys, err := s.ListObjects(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	// DeleteObjects accepts at most 1000 keys per request
+	const maxKeysPerDelete = 1000
+	deleted := 0
+	for start := 0; start < len(keys); start += maxKeysPerDelete {
+		end := start + maxKeysPerDelete
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		objects := make([]types.ObjectIdentifier, 0, end-start)
+		for _, key := range keys[start:end] {
+			objects = append(objects, types.ObjectIdentifier{Key: aws.String(key)})
+		}
+
+		result, err := s.client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(s.bucket),
+			Delete: &types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete objects under %s: %w", prefix, err)
+		}
+		if len(result.Errors) > 0 {
+			return deleted, fmt.Errorf("failed to delete %s: %s", aws.ToString(result.Errors[0].Key), aws.ToString(result.Errors[0].Message))
+		}
+		deleted += len(objects)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,334 @@
+package backup
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// VerifyOptions configures a read-only check of a backup against live tables
+type VerifyOptions struct {
+	InputPath   string
+	S3Bucket    string
+	S3Prefix    string
+	Tables      []string
+	TargetTable string // Compare against this table instead of the one the backup was taken from (single table only)
+}
+
+// TableVerification is the outcome of verifying one table in a backup
+type TableVerification struct {
+	TableName     string
+	TargetTable   string
+	ManifestItems int
+	BackupItems   int
+	LiveItems     int
+	MissingInLive int      // Backed-up items whose key is no longer in the target table
+	NewInLive     int      // Target table items whose key is not in the backup
+	Problems      []string // Integrity failures that make the backup unsafe to restore
+}
+
+// Valid reports whether the table's backup passed every integrity check
+func (t TableVerification) Valid() bool {
+	return len(t.Problems) == 0
+}
+
+// Drifted reports whether the target table has changed since the backup
+func (t TableVerification) Drifted() bool {
+	return t.MissingInLive > 0 || t.NewInLive > 0
+}
+
+// VerifyResult contains the outcome of verifying a backup
+type VerifyResult struct {
+	BackupTimestamp string
+	BackupVersion   string
+	Tables          []TableVerification
+	Duration        time.Duration
+}
+
+// Valid reports whether every verified table passed its integrity checks
+// Drift alone does not make a backup invalid: live tables keep changing after a backup
+func (r *VerifyResult) Valid() bool {
+	for _, t := range r.Tables {
+		if !t.Valid() {
+			return false
+		}
+	}
+	return true
+}
+
+// keyAttribute is one attribute of a table's primary key
+type keyAttribute struct {
+	Name string
+	Type types.ScalarAttributeType
+}
+
+// Verify reads a backup and checks it against the live tables without writing anything:
+// file checksums, item counts against the manifest, key schema against the target table,
+// every item carrying well-typed unique keys, and which keys have drifted since the backup
+func Verify(ctx context.Context, options VerifyOptions) (*VerifyResult, error) {
+	startTime := time.Now()
+
+	backupPath, cleanup, err := resolveBackupPath(ctx, options.InputPath, options.S3Bucket, options.S3Prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	manifest, err := ReadManifest(filepath.Join(backupPath, "manifest.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	tables := options.Tables
+	if len(tables) == 0 {
+		for _, tm := range manifest.Tables {
+			tables = append(tables, tm.TableName)
+		}
+	}
+	if options.TargetTable != "" && len(tables) != 1 {
+		return nil, fmt.Errorf("a target table can only be used when verifying a single table (got %d)", len(tables))
+	}
+
+	dbClient, err := NewDynamoDBClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DynamoDB client: %w", err)
+	}
+
+	log.Printf("Verifying backup %s (format %s)", manifest.BackupTimestamp, manifest.BackupVersion)
+
+	result := &VerifyResult{
+		BackupTimestamp: manifest.BackupTimestamp,
+		BackupVersion:   manifest.BackupVersion,
+	}
+	for _, tableName := range tables {
+		target := tableName
+		if options.TargetTable != "" {
+			target = options.TargetTable
+		}
+		result.Tables = append(result.Tables, verifyTable(ctx, dbClient, backupPath, manifest, tableName, target))
+	}
+
+	result.Duration = time.Since(startTime)
+	return result, nil
+}
+
+// verifyTable runs every check for one table, collecting failures rather than stopping at the first
+func verifyTable(ctx context.Context, dbClient *DynamoDBClient, backupPath string, manifest *Manifest, tableName, target string) TableVerification {
+	tv := TableVerification{TableName: tableName, TargetTable: target}
+
+	var tableManifest *TableManifest
+	for i := range manifest.Tables {
+		if manifest.Tables[i].TableName == tableName {
+			tableManifest = &manifest.Tables[i]
+			break
+		}
+	}
+	if tableManifest == nil {
+		tv.Problems = append(tv.Problems, "table not found in backup manifest")
+		return tv
+	}
+	tv.ManifestItems = tableManifest.ItemCount
+
+	filePath := filepath.Join(backupPath, tableManifest.FileName)
+	if tableManifest.Checksum != "" {
+		checksum, err := CalculateFileChecksum(filePath)
+		if err != nil {
+			tv.Problems = append(tv.Problems, fmt.Sprintf("cannot checksum %s: %v", tableManifest.FileName, err))
+			return tv
+		}
+		if checksum != tableManifest.Checksum {
+			tv.Problems = append(tv.Problems, fmt.Sprintf("checksum mismatch for %s", tableManifest.FileName))
+		}
+	}
+
+	items, skipped, err := readItemsJSONL(filePath, tableManifest.FileName)
+	if err != nil {
+		tv.Problems = append(tv.Problems, fmt.Sprintf("cannot read items: %v", err))
+		return tv
+	}
+	tv.BackupItems = len(items)
+	if skipped > 0 {
+		tv.Problems = append(tv.Problems, fmt.Sprintf("%d items cannot be decoded (backup format %s)", skipped, manifest.BackupVersion))
+	}
+	if len(items)+skipped != tableManifest.ItemCount {
+		tv.Problems = append(tv.Problems, fmt.Sprintf("manifest lists %d items but the file holds %d", tableManifest.ItemCount, len(items)+skipped))
+	}
+
+	desc, err := dbClient.GetTableDescription(ctx, target)
+	if err != nil {
+		tv.Problems = append(tv.Problems, fmt.Sprintf("target table unavailable: %v", err))
+		return tv
+	}
+	keys := keyAttributes(desc)
+
+	if backedUp, err := readMetadataKeySchema(filepath.Join(backupPath, fmt.Sprintf("%s.metadata.json", tableName))); err != nil {
+		log.Printf("Warning: no key schema recorded for %s, skipping schema comparison: %v", tableName, err)
+	} else if problem := compareKeySchema(backedUp, desc.KeySchema); problem != "" {
+		tv.Problems = append(tv.Problems, problem)
+	}
+
+	backupKeys, problems := checkItemKeys(items, keys)
+	tv.Problems = append(tv.Problems, problems...)
+
+	liveItems, err := dbClient.ScanKeys(ctx, target, keyNames(keys))
+	if err != nil {
+		tv.Problems = append(tv.Problems, fmt.Sprintf("cannot scan target table: %v", err))
+		return tv
+	}
+	liveKeys, _ := checkItemKeys(liveItems, keys)
+	tv.LiveItems = len(liveItems)
+	tv.MissingInLive, tv.NewInLive = compareKeySets(backupKeys, liveKeys)
+
+	return tv
+}
+
+// keyAttributes returns a table's key attributes, partition key first
+func keyAttributes(desc *types.TableDescription) []keyAttribute {
+	attrTypes := make(map[string]types.ScalarAttributeType, len(desc.AttributeDefinitions))
+	for _, def := range desc.AttributeDefinitions {
+		attrTypes[aws.ToString(def.AttributeName)] = def.AttributeType
+	}
+
+	var keys []keyAttribute
+	for _, keyType := range []types.KeyType{types.KeyTypeHash, types.KeyTypeRange} {
+		for _, elem := range desc.KeySchema {
+			if elem.KeyType == keyType {
+				name := aws.ToString(elem.AttributeName)
+				keys = append(keys, keyAttribute{Name: name, Type: attrTypes[name]})
+			}
+		}
+	}
+	return keys
+}
+
+func keyNames(keys []keyAttribute) []string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k.Name
+	}
+	return names
+}
+
+// readMetadataKeySchema reads the key schema recorded alongside a table's backup
+func readMetadataKeySchema(path string) ([]types.KeySchemaElement, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata struct {
+		KeySchema []types.KeySchemaElement `json:"keySchema"`
+	}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata: %w", err)
+	}
+	if len(metadata.KeySchema) == 0 {
+		return nil, fmt.Errorf("metadata has no key schema")
+	}
+	return metadata.KeySchema, nil
+}
+
+// compareKeySchema describes how the backed-up key schema differs from the target's, or returns ""
+func compareKeySchema(backedUp, target []types.KeySchemaElement) string {
+	format := func(schema []types.KeySchemaElement) string {
+		parts := make([]string, len(schema))
+		for i, elem := range schema {
+			parts[i] = fmt.Sprintf("%s(%s)", aws.ToString(elem.AttributeName), elem.KeyType)
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	if format(backedUp) != format(target) {
+		return fmt.Sprintf("key schema differs: backup has [%s], target has [%s]", format(backedUp), format(target))
+	}
+	return ""
+}
+
+// checkItemKeys builds the set of primary keys in items, reporting items whose key
+// attributes are missing or of the wrong type, and keys that appear more than once
+func checkItemKeys(items []map[string]types.AttributeValue, keys []keyAttribute) (map[string]bool, []string) {
+	seen := make(map[string]bool, len(items))
+	badKeys, duplicates := 0, 0
+	var firstBad, firstDuplicate string
+
+	for i, item := range items {
+		key, err := itemKey(item, keys)
+		if err != nil {
+			if badKeys == 0 {
+				firstBad = fmt.Sprintf("item %d: %v", i+1, err)
+			}
+			badKeys++
+			continue
+		}
+		if seen[key] {
+			if duplicates == 0 {
+				firstDuplicate = key
+			}
+			duplicates++
+			continue
+		}
+		seen[key] = true
+	}
+
+	var problems []string
+	if badKeys > 0 {
+		problems = append(problems, fmt.Sprintf("%d items have missing or mistyped key attributes (first: %s)", badKeys, firstBad))
+	}
+	if duplicates > 0 {
+		problems = append(problems, fmt.Sprintf("%d items repeat a primary key (first: %s)", duplicates, firstDuplicate))
+	}
+	return seen, problems
+}
+
+// itemKey renders an item's primary key as a comparable string
+func itemKey(item map[string]types.AttributeValue, keys []keyAttribute) (string, error) {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		av, ok := item[k.Name]
+		if !ok {
+			return "", fmt.Errorf("missing key attribute %s", k.Name)
+		}
+
+		var value string
+		var typ types.ScalarAttributeType
+		switch v := av.(type) {
+		case *types.AttributeValueMemberS:
+			value, typ = v.Value, types.ScalarAttributeTypeS
+		case *types.AttributeValueMemberN:
+			value, typ = v.Value, types.ScalarAttributeTypeN
+		case *types.AttributeValueMemberB:
+			value, typ = base64.StdEncoding.EncodeToString(v.Value), types.ScalarAttributeTypeB
+		default:
+			return "", fmt.Errorf("key attribute %s has non-scalar type %T", k.Name, av)
+		}
+		if k.Type != "" && typ != k.Type {
+			return "", fmt.Errorf("key attribute %s is %s, table expects %s", k.Name, typ, k.Type)
+		}
+		parts[i] = fmt.Sprintf("%s=%s", k.Name, value)
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// compareKeySets counts backup keys absent from the live table and live keys absent from the backup
+func compareKeySets(backup, live map[string]bool) (missingInLive, newInLive int) {
+	for key := range backup {
+		if !live[key] {
+			missingInLive++
+		}
+	}
+	for key := range live {
+		if !backup[key] {
+			newInLive++
+		}
+	}
+	return missingInLive, newInLive
+}
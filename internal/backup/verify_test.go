@@ -0,0 +1,93 @@
+package backup
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var stateKeys = []keyAttribute{
+	{Name: "runId", Type: types.ScalarAttributeTypeS},
+	{Name: "postId", Type: types.ScalarAttributeTypeS},
+}
+
+func stateItem(runID, postID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"runId":  &types.AttributeValueMemberS{Value: runID},
+		"postId": &types.AttributeValueMemberS{Value: postID},
+	}
+}
+
+func TestKeyAttributesPartitionKeyFirst(t *testing.T) {
+	desc := &types.TableDescription{
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("runId"), KeyType: types.KeyTypeRange},
+			{AttributeName: aws.String("date"), KeyType: types.KeyTypeHash},
+		},
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("date"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("runId"), AttributeType: types.ScalarAttributeTypeS},
+		},
+	}
+
+	keys := keyAttributes(desc)
+	if len(keys) != 2 || keys[0].Name != "date" || keys[1].Name != "runId" {
+		t.Errorf("keyAttributes = %+v, want date then runId", keys)
+	}
+}
+
+func TestCheckItemKeys(t *testing.T) {
+	missingSortKey := map[string]types.AttributeValue{"runId": &types.AttributeValueMemberS{Value: "run-3"}}
+	wrongType := map[string]types.AttributeValue{
+		"runId":  &types.AttributeValueMemberN{Value: "4"},
+		"postId": &types.AttributeValueMemberS{Value: "orchestrator"},
+	}
+	items := []map[string]types.AttributeValue{
+		stateItem("run-1", "orchestrator"),
+		stateItem("run-2", "orchestrator"),
+		stateItem("run-1", "orchestrator"), // duplicate
+		missingSortKey,
+		wrongType,
+	}
+
+	keys, problems := checkItemKeys(items, stateKeys)
+	if len(keys) != 2 {
+		t.Errorf("got %d unique keys, want 2", len(keys))
+	}
+	if len(problems) != 2 {
+		t.Fatalf("problems = %v, want one for bad keys and one for duplicates", problems)
+	}
+}
+
+func TestCheckItemKeysClean(t *testing.T) {
+	_, problems := checkItemKeys([]map[string]types.AttributeValue{stateItem("run-1", "orchestrator")}, stateKeys)
+	if len(problems) != 0 {
+		t.Errorf("problems = %v, want none", problems)
+	}
+}
+
+func TestCompareKeySets(t *testing.T) {
+	backup := map[string]bool{"a": true, "b": true, "c": true}
+	live := map[string]bool{"b": true, "c": true, "d": true, "e": true}
+
+	missing, added := compareKeySets(backup, live)
+	if missing != 1 || added != 2 {
+		t.Errorf("compareKeySets = (%d, %d), want (1, 2)", missing, added)
+	}
+}
+
+func TestCompareKeySchema(t *testing.T) {
+	schema := []types.KeySchemaElement{
+		{AttributeName: aws.String("runId"), KeyType: types.KeyTypeHash},
+		{AttributeName: aws.String("postId"), KeyType: types.KeyTypeRange},
+	}
+	if problem := compareKeySchema(schema, schema); problem != "" {
+		t.Errorf("identical schemas reported %q", problem)
+	}
+
+	other := []types.KeySchemaElement{{AttributeName: aws.String("date"), KeyType: types.KeyTypeHash}}
+	if problem := compareKeySchema(schema, other); problem == "" {
+		t.Error("expected differing schemas to be reported")
+	}
+}
@@ -0,0 +1,154 @@
+// Package blocklist leaves posts out of a run when their author, link domain or text is
+// blocked, so spam rings and unwanted accounts count toward neither sentiment nor the top
+// posts. Authors on subscribed moderation lists are blocked too. Allowed authors and
+// domains are kept even when they match a block.
+package blocklist
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/christophergentle/hourstats-bsky/internal/identity"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// List matches posts against blocklist entries
+type List struct {
+	authors      map[string]bool
+	domains      map[string]bool
+	keywords     []string
+	listMembers  map[string]bool
+	allowAuthors map[string]bool
+	allowDomains map[string]bool
+}
+
+// New builds a list from stored entries; entries that don't normalize are skipped
+func New(entries []state.BlocklistEntry) *List {
+	l := &List{
+		authors:      make(map[string]bool),
+		domains:      make(map[string]bool),
+		allowAuthors: make(map[string]bool),
+		allowDomains: make(map[string]bool),
+	}
+	for _, entry := range entries {
+		entry, err := state.NormalizeBlocklistEntry(entry)
+		if err != nil {
+			continue
+		}
+		switch {
+		case entry.Kind == state.BlockAuthor && entry.Allow:
+			l.allowAuthors[entry.Value] = true
+		case entry.Kind == state.BlockAuthor:
+			l.authors[entry.Value] = true
+		case entry.Kind == state.BlockDomain && entry.Allow:
+			l.allowDomains[entry.Value] = true
+		case entry.Kind == state.BlockDomain:
+			l.domains[entry.Value] = true
+		case entry.Kind == state.BlockKeyword:
+			l.keywords = append(l.keywords, entry.Value)
+		}
+	}
+	return l
+}
+
+// BlockListMembers also blocks posts by the DIDs, the members of subscribed moderation lists
+func (l *List) BlockListMembers(dids map[string]bool) {
+	l.listMembers = dids
+}
+
+// Empty reports whether the list blocks nothing
+func (l *List) Empty() bool {
+	return l == nil || len(l.authors)+len(l.domains)+len(l.keywords)+len(l.listMembers) == 0
+}
+
+// Match returns the kind of entry that blocks a post, or an empty string when it is kept
+func (l *List) Match(post state.Post) string {
+	if l.Empty() {
+		return ""
+	}
+	author := strings.ToLower(post.Author)
+	domain := state.LinkDomain(post.LinkURL)
+	if l.allowAuthors[author] || (domain != "" && matchesDomain(l.allowDomains, domain)) {
+		return ""
+	}
+
+	if l.authors[author] {
+		return state.BlockAuthor
+	}
+	if did := identity.AuthorDID(post.URI); did != "" && l.listMembers[did] {
+		return state.BlockListMember
+	}
+	if domain != "" && matchesDomain(l.domains, domain) {
+		return state.BlockDomain
+	}
+	text := strings.ToLower(post.Text)
+	for _, keyword := range l.keywords {
+		if containsWords(text, keyword) {
+			return state.BlockKeyword
+		}
+	}
+	return ""
+}
+
+// Filter returns the posts the list keeps, and how many it blocked of each kind
+func (l *List) Filter(posts []state.Post) ([]state.Post, map[string]int) {
+	blocked := make(map[string]int)
+	if l.Empty() {
+		return posts, blocked
+	}
+
+	kept := make([]state.Post, 0, len(posts))
+	for _, post := range posts {
+		if kind := l.Match(post); kind != "" {
+			blocked[kind]++
+			continue
+		}
+		kept = append(kept, post)
+	}
+	return kept, blocked
+}
+
+// matchesDomain reports whether domain or one of its parent domains is in domains
+func matchesDomain(domains map[string]bool, domain string) bool {
+	for {
+		if domains[domain] {
+			return true
+		}
+		_, parent, ok := strings.Cut(domain, ".")
+		if !ok || !strings.Contains(parent, ".") {
+			return false
+		}
+		domain = parent
+	}
+}
+
+// containsWords reports whether phrase appears in text as whole words, so "ass" doesn't
+// match "class"; both are lower case
+func containsWords(text, phrase string) bool {
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], phrase)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(phrase)
+		if !wordRuneBefore(text, start) && !wordRuneAfter(text, end) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+func wordRuneBefore(text string, i int) bool {
+	r, size := utf8.DecodeLastRuneInString(text[:i])
+	return size > 0 && isWordRune(r)
+}
+
+func wordRuneAfter(text string, i int) bool {
+	r, size := utf8.DecodeRuneInString(text[i:])
+	return size > 0 && isWordRune(r)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
@@ -0,0 +1,82 @@
+package blocklist
+
+import (
+	"testing"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func TestMatch(t *testing.T) {
+	list := New([]state.BlocklistEntry{
+		{Kind: state.BlockAuthor, Value: "@Spammer.bsky.social"},
+		{Kind: state.BlockDomain, Value: "https://www.spam.example/offer"},
+		{Kind: state.BlockDomain, Value: "substack.com"},
+		{Kind: state.BlockDomain, Value: "good.substack.com", Allow: true},
+		{Kind: state.BlockKeyword, Value: "free  crypto"},
+		{Kind: state.BlockKeyword, Value: "ass"},
+		{Kind: state.BlockAuthor, Value: "trusted.bsky.social", Allow: true},
+		{Kind: "mood", Value: "sad"},
+	})
+
+	for _, tc := range []struct {
+		post state.Post
+		want string
+	}{
+		{state.Post{Author: "spammer.bsky.social", Text: "hello"}, state.BlockAuthor},
+		{state.Post{Author: "alice.bsky.social", LinkURL: "https://spam.example/x"}, state.BlockDomain},
+		{state.Post{Author: "alice.bsky.social", LinkURL: "https://news.substack.com/p/1"}, state.BlockDomain},
+		{state.Post{Author: "alice.bsky.social", LinkURL: "https://good.substack.com/p/1"}, ""},
+		{state.Post{Author: "alice.bsky.social", Text: "Get FREE crypto now!"}, state.BlockKeyword},
+		{state.Post{Author: "alice.bsky.social", Text: "Maths class was great"}, ""},
+		{state.Post{Author: "alice.bsky.social", Text: "what a pain in the ass"}, state.BlockKeyword},
+		{state.Post{Author: "trusted.bsky.social", Text: "free crypto explained"}, ""},
+		{state.Post{Author: "alice.bsky.social", Text: "sad"}, ""},
+	} {
+		if got := list.Match(tc.post); got != tc.want {
+			t.Errorf("Match(%+v) = %q, want %q", tc.post, got, tc.want)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	posts := []state.Post{
+		{URI: "1", Author: "spammer.bsky.social"},
+		{URI: "2", Author: "alice.bsky.social", Text: "free crypto"},
+		{URI: "3", Author: "bob.bsky.social"},
+	}
+
+	kept, blocked := New(nil).Filter(posts)
+	if len(kept) != 3 || len(blocked) != 0 {
+		t.Errorf("Expected an empty list to keep every post, got %d kept, %v blocked", len(kept), blocked)
+	}
+
+	list := New([]state.BlocklistEntry{
+		{Kind: state.BlockAuthor, Value: "spammer.bsky.social"},
+		{Kind: state.BlockKeyword, Value: "free crypto"},
+	})
+	kept, blocked = list.Filter(posts)
+	if len(kept) != 1 || kept[0].URI != "3" {
+		t.Errorf("Expected only post 3 kept, got %+v", kept)
+	}
+	if blocked[state.BlockAuthor] != 1 || blocked[state.BlockKeyword] != 1 {
+		t.Errorf("Unexpected blocked counts %v", blocked)
+	}
+}
+
+func TestListMembers(t *testing.T) {
+	list := New([]state.BlocklistEntry{{Kind: state.BlockAuthor, Value: "trusted.bsky.social", Allow: true}})
+	list.BlockListMembers(map[string]bool{"did:plc:spam": true, "did:plc:trusted": true})
+
+	for _, tc := range []struct {
+		post state.Post
+		want string
+	}{
+		{state.Post{URI: "at://did:plc:spam/app.bsky.feed.post/1", Author: "spam.bsky.social"}, state.BlockListMember},
+		{state.Post{URI: "at://did:plc:alice/app.bsky.feed.post/1", Author: "alice.bsky.social"}, ""},
+		{state.Post{URI: "at://did:plc:trusted/app.bsky.feed.post/1", Author: "trusted.bsky.social"}, ""},
+	} {
+		if got := list.Match(tc.post); got != tc.want {
+			t.Errorf("Match(%+v) = %q, want %q", tc.post, got, tc.want)
+		}
+	}
+}
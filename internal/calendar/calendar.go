@@ -0,0 +1,200 @@
+// Package calendar knows which days are notable, from a built-in list of holidays or an ICS
+// feed, so the hourly post can name the day and its sentiment isn't flagged as unusual when
+// an outlier is expected. It is configured by an optional setting stored in SSM.
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// ParameterName holds the JSON calendar settings; when it is absent no day is notable
+const ParameterName = "/hourstats/settings/calendar"
+
+// requestTimeout bounds the ICS download; the calendar is optional to a post, so it must
+// not hold one up
+const requestTimeout = 10 * time.Second
+
+// Settings picks the calendars to use, e.g.
+//
+//	{"builtin": true, "ics": "https://example.com/holidays.ics", "suppressBadges": true}
+type Settings struct {
+	// Builtin adds the built-in list of widely observed holidays
+	Builtin bool `json:"builtin"`
+	// ICS is the URL of an iCalendar feed of further days
+	ICS string `json:"ics,omitempty"`
+	// SuppressBadges stops the unusual sentiment badge on notable days; defaults to true
+	SuppressBadges *bool `json:"suppressBadges,omitempty"`
+}
+
+// ParameterGetter is the subset of the SSM client Load needs
+type ParameterGetter interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Load reads the calendar settings from SSM. A missing or empty parameter returns nil,
+// meaning no day is notable.
+func Load(ctx context.Context, ssmClient ParameterGetter) (*Settings, error) {
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ParameterName),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %s: %w", ParameterName, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return nil, nil
+	}
+	return Parse(*result.Parameter.Value)
+}
+
+// Parse decodes and validates JSON calendar settings
+func Parse(value string) (*Settings, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var settings Settings
+	if err := json.Unmarshal([]byte(value), &settings); err != nil {
+		return nil, fmt.Errorf("invalid calendar JSON: %w", err)
+	}
+	if !settings.Builtin && settings.ICS == "" {
+		return nil, fmt.Errorf("calendar settings need builtin or an ics URL")
+	}
+	if settings.ICS != "" {
+		u, err := url.Parse(settings.ICS)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, fmt.Errorf("calendar ics %q must be an http(s) URL", settings.ICS)
+		}
+	}
+	return &settings, nil
+}
+
+// Open builds the calendar, downloading the ICS feed when one is configured
+func (s *Settings) Open(ctx context.Context) (*Calendar, error) {
+	calendar := &Calendar{
+		builtin:        s.Builtin,
+		suppressBadges: s.SuppressBadges == nil || *s.SuppressBadges,
+	}
+	if s.ICS == "" {
+		return calendar, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.ICS, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create calendar request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download calendar: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("calendar download returned %s", resp.Status)
+	}
+
+	calendar.events, err = ParseICS(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+// Calendar answers which notable days a date falls on
+type Calendar struct {
+	builtin        bool
+	events         []Event
+	suppressBadges bool
+}
+
+// New creates a calendar of the given events, plus the built-in holidays if builtin is set
+func New(builtin bool, events []Event) *Calendar {
+	return &Calendar{builtin: builtin, events: events, suppressBadges: true}
+}
+
+// On names the notable days on t's UTC date, built-in holidays first. A nil calendar has none.
+func (c *Calendar) On(t time.Time) []string {
+	if c == nil {
+		return nil
+	}
+	date := t.UTC()
+
+	var names []string
+	if c.builtin {
+		for _, holiday := range Holidays(date.Year()) {
+			if holiday.On(date) {
+				names = append(names, holiday.Name)
+			}
+		}
+	}
+	for _, event := range c.events {
+		if event.On(date) && !contains(names, event.Name) {
+			names = append(names, event.Name)
+		}
+	}
+	return names
+}
+
+// ExpectsOutlier reports whether unusual sentiment on t is expected, so it should not be
+// flagged: t is a notable day and badges are suppressed on notable days
+func (c *Calendar) ExpectsOutlier(t time.Time) bool {
+	return c != nil && c.suppressBadges && len(c.On(t)) > 0
+}
+
+// Event is a notable day or run of days
+type Event struct {
+	Name string
+	// Start and End are UTC midnights; End is exclusive, so a one-day event ends the next day
+	Start time.Time
+	End   time.Time
+	// Yearly events recur on the same dates every year
+	Yearly bool
+}
+
+// On reports whether the event covers t's UTC date
+func (e Event) On(t time.Time) bool {
+	date := truncateDay(t.UTC())
+	start, end := e.Start, e.End
+	if e.Yearly {
+		// Move the event to the year that could cover date; runs that cross New Year
+		// are also tried starting the year before
+		offset := date.Year() - start.Year()
+		for _, years := range []int{offset, offset - 1} {
+			from, to := start.AddDate(years, 0, 0), end.AddDate(years, 0, 0)
+			if !date.Before(from) && date.Before(to) {
+				return true
+			}
+		}
+		return false
+	}
+	return !date.Before(start) && date.Before(end)
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package calendar
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	settings, err := Parse(`{"builtin": true}`)
+	if err != nil || !settings.Builtin || settings.SuppressBadges != nil {
+		t.Errorf("Expected the built-in calendar, got %+v, %v", settings, err)
+	}
+	if settings, err := Parse(""); err != nil || settings != nil {
+		t.Errorf("Parse(empty) = %+v, %v; want nil", settings, err)
+	}
+	for name, value := range map[string]string{
+		"no calendar":  `{"suppressBadges": false}`,
+		"not a URL":    `{"ics": "holidays.ics"}`,
+		"invalid JSON": `{"builtin": `,
+	} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestHolidays(t *testing.T) {
+	want := map[string]time.Time{
+		"Easter Sunday":     date(2025, time.April, 20),
+		"Good Friday":       date(2025, time.April, 18),
+		"Thanksgiving (US)": date(2025, time.November, 27),
+	}
+	for _, holiday := range Holidays(2025) {
+		if day, ok := want[holiday.Name]; ok && !holiday.Start.Equal(day) {
+			t.Errorf("%s 2025 = %s, want %s", holiday.Name, holiday.Start.Format("2006-01-02"), day.Format("2006-01-02"))
+		}
+	}
+	if easter := easterSunday(2024); !easter.Equal(date(2024, time.March, 31)) {
+		t.Errorf("Easter 2024 = %s, want 2024-03-31", easter.Format("2006-01-02"))
+	}
+}
+
+const testICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Election Day\\, US\r\n" +
+	"DTSTART;VALUE=DATE:20241105\r\n" +
+	"DTEND;VALUE=DATE:20241106\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Eurovision Song\r\n" +
+	"  Contest\r\n" +
+	"DTSTART:20250517T190000Z\r\n" +
+	"DTEND:20250517T230000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Festive season\r\n" +
+	"DTSTART;VALUE=DATE:20231224\r\n" +
+	"DTEND;VALUE=DATE:20240102\r\n" +
+	"RRULE:FREQ=YEARLY\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICS(t *testing.T) {
+	events, err := ParseICS(strings.NewReader(testICS))
+	if err != nil {
+		t.Fatalf("ParseICS() error = %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %+v", events)
+	}
+	if events[0].Name != "Election Day, US" || !events[0].End.Equal(date(2024, time.November, 6)) {
+		t.Errorf("Expected a one-day Election Day, got %+v", events[0])
+	}
+	if events[1].Name != "Eurovision Song Contest" || !events[1].End.Equal(date(2025, time.May, 18)) {
+		t.Errorf("Expected the timed event to cover its date, got %+v", events[1])
+	}
+	if !events[2].Yearly {
+		t.Errorf("Expected the festive season to recur yearly")
+	}
+
+	if _, err := ParseICS(strings.NewReader("BEGIN:VEVENT\nDTSTART:20250101\nEND:VEVENT\n")); err == nil {
+		t.Error("Expected an error for an event without a summary")
+	}
+}
+
+func TestCalendarOn(t *testing.T) {
+	events, err := ParseICS(strings.NewReader(testICS))
+	if err != nil {
+		t.Fatalf("ParseICS() error = %v", err)
+	}
+	calendar := New(true, events)
+
+	tests := []struct {
+		at   time.Time
+		want []string
+	}{
+		{time.Date(2025, 12, 25, 18, 0, 0, 0, time.UTC), []string{"Christmas Day", "Festive season"}},
+		{time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC), []string{"New Year's Day", "Festive season"}},
+		{time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC), nil},
+		{time.Date(2024, 11, 5, 23, 0, 0, 0, time.UTC), []string{"Election Day, US"}},
+		{time.Date(2025, 11, 5, 12, 0, 0, 0, time.UTC), nil},
+	}
+	for _, tt := range tests {
+		if got := calendar.On(tt.at); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("On(%s) = %v, want %v", tt.at.Format("2006-01-02"), got, tt.want)
+		}
+	}
+
+	if !calendar.ExpectsOutlier(tests[0].at) || calendar.ExpectsOutlier(tests[2].at) {
+		t.Error("Expected outliers only on notable days")
+	}
+	var none *Calendar
+	if none.On(tests[0].at) != nil || none.ExpectsOutlier(tests[0].at) {
+		t.Error("Expected a nil calendar to have no notable days")
+	}
+}
@@ -0,0 +1,52 @@
+package calendar
+
+import "time"
+
+// Holidays returns the built-in notable days of a year: widely observed holidays on which
+// Bluesky's mood is known to swing. US holidays are included since much of Bluesky is there.
+func Holidays(year int) []Event {
+	easter := easterSunday(year)
+	return []Event{
+		day("New Year's Day", date(year, time.January, 1)),
+		day("Valentine's Day", date(year, time.February, 14)),
+		day("Good Friday", easter.AddDate(0, 0, -2)),
+		day("Easter Sunday", easter),
+		day("Independence Day (US)", date(year, time.July, 4)),
+		day("Halloween", date(year, time.October, 31)),
+		day("Thanksgiving (US)", nthWeekday(year, time.November, time.Thursday, 4)),
+		day("Christmas Eve", date(year, time.December, 24)),
+		day("Christmas Day", date(year, time.December, 25)),
+		day("New Year's Eve", date(year, time.December, 31)),
+	}
+}
+
+func day(name string, start time.Time) Event {
+	return Event{Name: name, Start: start, End: start.AddDate(0, 0, 1)}
+}
+
+func date(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+// nthWeekday returns the nth given weekday of a month, e.g. the fourth Thursday of November
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	first := date(year, month, 1)
+	offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+(n-1)*7)
+}
+
+// easterSunday computes Western Easter with the anonymous Gregorian algorithm
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	dayOfMonth := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), dayOfMonth)
+}
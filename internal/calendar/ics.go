@@ -0,0 +1,121 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ParseICS reads the events of an iCalendar feed. Only what a calendar of notable days
+// needs is supported: each VEVENT's SUMMARY, its start and end dates, and yearly
+// recurrence. Times of day are dropped, so an event covers every date it touches.
+func ParseICS(r io.Reader) ([]Event, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+
+	var events []Event
+	var current *icsEvent
+	for _, line := range lines {
+		name, params, value := splitProperty(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			current = &icsEvent{}
+		case name == "END" && value == "VEVENT":
+			if current == nil {
+				return nil, fmt.Errorf("END:VEVENT without BEGIN:VEVENT")
+			}
+			event, err := current.event()
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, event)
+			current = nil
+		case current == nil:
+			continue
+		case name == "SUMMARY":
+			current.summary = unescapeText(value)
+		case name == "DTSTART":
+			current.start = value
+		case name == "DTEND":
+			current.end, current.endIsDate = value, strings.Contains(params, "VALUE=DATE") && !strings.Contains(params, "VALUE=DATE-TIME")
+		case name == "RRULE":
+			current.yearly = strings.Contains(";"+value+";", ";FREQ=YEARLY;")
+		}
+	}
+	return events, nil
+}
+
+// icsEvent holds a VEVENT's raw properties until END:VEVENT
+type icsEvent struct {
+	summary    string
+	start, end string
+	endIsDate  bool
+	yearly     bool
+}
+
+func (e *icsEvent) event() (Event, error) {
+	if e.summary == "" {
+		return Event{}, fmt.Errorf("calendar event without SUMMARY")
+	}
+	start, err := parseICSDate(e.start)
+	if err != nil {
+		return Event{}, fmt.Errorf("calendar event %q has invalid DTSTART: %w", e.summary, err)
+	}
+
+	end := start.AddDate(0, 0, 1)
+	if e.end != "" {
+		if end, err = parseICSDate(e.end); err != nil {
+			return Event{}, fmt.Errorf("calendar event %q has invalid DTEND: %w", e.summary, err)
+		}
+		// An all-day DTEND is exclusive already; a timed one covers its own date
+		if !e.endIsDate && len(e.end) > len("20060102") {
+			end = end.AddDate(0, 0, 1)
+		}
+		if !end.After(start) {
+			end = start.AddDate(0, 0, 1)
+		}
+	}
+	return Event{Name: e.summary, Start: start, End: end, Yearly: e.yearly}, nil
+}
+
+// parseICSDate takes the date of a DATE or DATE-TIME value, e.g. 20250101 or 20250101T090000Z
+func parseICSDate(value string) (time.Time, error) {
+	if len(value) < len("20060102") {
+		return time.Time{}, fmt.Errorf("%q is not a date", value)
+	}
+	return time.Parse("20060102", value[:len("20060102")])
+}
+
+// unfoldLines joins continuation lines, which start with a space or tab, to the line
+// before them
+func unfoldLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// splitProperty splits "DTSTART;VALUE=DATE:20250101" into its name, parameters and value
+func splitProperty(line string) (name, params, value string) {
+	head, value, _ := strings.Cut(line, ":")
+	name, params, _ = strings.Cut(head, ";")
+	return strings.ToUpper(name), strings.ToUpper(params), value
+}
+
+// unescapeText undoes iCalendar TEXT escaping
+func unescapeText(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
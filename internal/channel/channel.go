@@ -0,0 +1,186 @@
+// Package channel configures the logical channels one deployment runs side by side, e.g. a
+// 30-minute global summary, a 6-hour tech feed and a daily digest. Each channel has its own
+// schedule (an EventBridge rule naming it), post source, Bluesky account, summary template
+// and posting rules; runs record their channel and are listed per channel. The channels are
+// an optional setting stored in SSM; the default channel, with an empty ID, needs no entry.
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/christophergentle/hourstats-bsky/internal/formatter"
+	"github.com/christophergentle/hourstats-bsky/internal/schedule"
+)
+
+// ParameterName holds the JSON channel settings, keyed by channel ID
+const ParameterName = "/hourstats/settings/channels"
+
+// Default credential parameters, used by the default channel and channels without their own
+const (
+	DefaultHandleParameter   = "/hourstats/bluesky/handle"
+	DefaultPasswordParameter = "/hourstats/bluesky/password"
+)
+
+// validID keeps channel IDs short and safe to use in keys and metric dimensions
+var validID = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// Channel is one channel's settings, e.g.
+//
+//	{
+//	  "tech": {
+//	    "queries": ["golang", "rustlang"],
+//	    "credentials": "/hourstats/channels/tech/bluesky",
+//	    "template": "score-first",
+//	    "schedule": {"quietHours": ["00:00-07:00"]}
+//	  }
+//	}
+type Channel struct {
+	ID string `json:"-"`
+	// FeedURI and Queries choose the post source as /hourstats/settings/feed_uri and
+	// search_queries do for the default channel; neither means the global search
+	FeedURI string   `json:"feed,omitempty"`
+	Queries []string `json:"queries,omitempty"`
+	// Credentials is an SSM path holding the account's handle and password parameters;
+	// empty posts from the default account
+	Credentials string `json:"credentials,omitempty"`
+	// Template lays out the channel's summaries; empty is the classic layout
+	Template formatter.Template `json:"template,omitempty"`
+	// Schedule holds posting rules in the posting_schedule format, replacing the global ones
+	Schedule json.RawMessage `json:"schedule,omitempty"`
+
+	rules *schedule.Rules
+}
+
+// ParameterGetter is the subset of the SSM client Load needs
+type ParameterGetter interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Load reads the channel settings from SSM. A missing or empty parameter returns no
+// channels, so only the default channel runs.
+func Load(ctx context.Context, ssmClient ParameterGetter) (map[string]*Channel, error) {
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ParameterName),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %s: %w", ParameterName, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return nil, nil
+	}
+	return Parse(*result.Parameter.Value)
+}
+
+// Parse decodes and validates JSON channel settings
+func Parse(value string) (map[string]*Channel, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var channels map[string]*Channel
+	if err := json.Unmarshal([]byte(value), &channels); err != nil {
+		return nil, fmt.Errorf("invalid channels JSON: %w", err)
+	}
+	for id, channel := range channels {
+		if !validID.MatchString(id) {
+			return nil, fmt.Errorf("channel ID %q must be 1-32 lowercase letters, digits or dashes", id)
+		}
+		if channel == nil {
+			return nil, fmt.Errorf("channel %s has no settings", id)
+		}
+		channel.ID = id
+		if channel.FeedURI != "" && len(channel.Queries) > 0 {
+			return nil, fmt.Errorf("channel %s sets both feed and queries; configure only one", id)
+		}
+		if channel.Credentials != "" && !strings.HasPrefix(channel.Credentials, "/") {
+			return nil, fmt.Errorf("channel %s credentials must be an SSM path, got %q", id, channel.Credentials)
+		}
+		if channel.Template != "" && !isTemplate(channel.Template) {
+			return nil, fmt.Errorf("channel %s has unknown template %q", id, channel.Template)
+		}
+		if len(channel.Schedule) > 0 {
+			rules, err := schedule.Parse(string(channel.Schedule))
+			if err != nil {
+				return nil, fmt.Errorf("channel %s: %w", id, err)
+			}
+			channel.rules = rules
+		}
+	}
+	return channels, nil
+}
+
+// Get loads the settings of channel id. The default channel, with an empty ID, returns nil;
+// an ID missing from the settings is an error, so a mistyped schedule fails instead of
+// posting as the default channel.
+func Get(ctx context.Context, ssmClient ParameterGetter, id string) (*Channel, error) {
+	if id == "" {
+		return nil, nil
+	}
+	channels, err := Load(ctx, ssmClient)
+	if err != nil {
+		return nil, err
+	}
+	channel, ok := channels[id]
+	if !ok {
+		return nil, fmt.Errorf("channel %q is not configured in %s", id, ParameterName)
+	}
+	return channel, nil
+}
+
+// CredentialParameters names the SSM parameters holding the channel's Bluesky handle and
+// password. Nil channels and channels without credentials use the default account.
+func (c *Channel) CredentialParameters() (handle, password string) {
+	if c == nil || c.Credentials == "" {
+		return DefaultHandleParameter, DefaultPasswordParameter
+	}
+	prefix := strings.TrimSuffix(c.Credentials, "/")
+	return prefix + "/handle", prefix + "/password"
+}
+
+// HasOwnCredentials reports whether the channel posts from its own account
+func (c *Channel) HasOwnCredentials() bool {
+	return c != nil && c.Credentials != ""
+}
+
+// Rules returns the channel's posting rules, or nil when it follows the global schedule
+func (c *Channel) Rules() *schedule.Rules {
+	if c == nil {
+		return nil
+	}
+	return c.rules
+}
+
+// HasSchedule reports whether the channel's posting rules replace the global schedule
+func (c *Channel) HasSchedule() bool {
+	return c != nil && c.rules != nil
+}
+
+// Label names the channel in logs, "default" for the default channel
+func Label(id string) string {
+	if id == "" {
+		return "default"
+	}
+	return id
+}
+
+func isTemplate(template formatter.Template) bool {
+	for _, known := range formatter.Templates {
+		if template == known {
+			return true
+		}
+	}
+	return false
+}
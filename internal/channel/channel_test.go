@@ -0,0 +1,60 @@
+package channel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	channels, err := Parse(`{
+		"tech": {"queries": ["golang"], "credentials": "/hourstats/channels/tech/bluesky/", "template": "score-first",
+			"schedule": {"quietHours": ["00:00-07:00"]}},
+		"digest": {}
+	}`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tech := channels["tech"]
+	if tech == nil || tech.ID != "tech" || tech.Template != "score-first" {
+		t.Fatalf("Expected the tech channel, got %+v", tech)
+	}
+	if handle, password := tech.CredentialParameters(); handle != "/hourstats/channels/tech/bluesky/handle" || password != "/hourstats/channels/tech/bluesky/password" {
+		t.Errorf("CredentialParameters() = %s, %s", handle, password)
+	}
+	if allowed, _ := tech.Rules().Allows("summary", time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC)); allowed || !tech.HasSchedule() {
+		t.Error("Expected the tech channel's quiet hours to apply")
+	}
+
+	digest := channels["digest"]
+	if digest.HasOwnCredentials() || digest.HasSchedule() {
+		t.Errorf("Expected the digest channel to use the defaults, got %+v", digest)
+	}
+	if handle, _ := digest.CredentialParameters(); handle != DefaultHandleParameter {
+		t.Errorf("Expected the default account, got %s", handle)
+	}
+
+	var none *Channel
+	if handle, _ := none.CredentialParameters(); handle != DefaultHandleParameter || none.Rules() != nil {
+		t.Error("Expected a nil channel to be the default channel")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for name, value := range map[string]string{
+		"bad ID":           `{"Tech News": {}}`,
+		"feed and query":   `{"tech": {"feed": "at://did:plc:x/app.bsky.feed.generator/tech", "queries": ["golang"]}}`,
+		"relative path":    `{"tech": {"credentials": "tech/bluesky"}}`,
+		"unknown template": `{"tech": {"template": "fancy"}}`,
+		"bad schedule":     `{"tech": {"schedule": {"quietHours": ["25:00-26:00"]}}}`,
+		"no settings":      `{"tech": null}`,
+		"invalid JSON":     `{"tech": `,
+	} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if channels, err := Parse(""); err != nil || channels != nil {
+		t.Errorf("Parse(empty) = %v, %v; want nil", channels, err)
+	}
+}
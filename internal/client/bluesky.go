@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"strings"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/bluesky-social/indigo/atproto/client"
 	"github.com/bluesky-social/indigo/lex/util"
 	"github.com/christophergentle/hourstats-bsky/internal/formatter"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
 )
 
 type Post struct {
@@ -26,23 +28,67 @@ type Post struct {
 	CreatedAt       string
 	Sentiment       string // "positive", "negative", or "neutral"
 	EngagementScore float64
+	Langs           []string // Language tags the author declared on the post, if any
+	Media           string   // MediaImage, MediaVideo, MediaExternal, or empty for text-only posts
+	LinkURL         string   // URL of the post's external link card, if it has one
+	Images          []Image  // Images the post embeds, including those under a quoted post
+	ReplyRoot       string   // URI of the thread's root post when the post is a reply
+	ContentWarnings []string // Content warnings the author applied to the post, e.g. "graphic-media"
+	AvatarURL       string   // Author's avatar image, if they have one
 }
 
+// Image is an image embedded in a post
+type Image struct {
+	URL string // Full-size image on the CDN
+	Alt string // Alt text the author wrote, if any
+}
+
+// Media kinds a post can embed; quoted posts count as their attached media, if any
+const (
+	MediaImage    = "image"
+	MediaVideo    = "video"
+	MediaExternal = "external"
+)
+
 type BlueskyClient struct {
 	client   *client.APIClient
 	handle   string
 	password string
+
+	// preAuthenticated is set when the API client was supplied already authenticated
+	// (e.g. a replay client in tests), so Authenticate must not replace it
+	preAuthenticated bool
+
+	// summaryLayout lays out summaries; the zero value is formatter.TemplateClassic without likes
+	summaryLayout formatter.Layout
+
+	// searchUntil bounds searches to posts before it; zero searches up to now
+	searchUntil time.Time
 }
 
 func New(handle, password string) *BlueskyClient {
 	return &BlueskyClient{
-		client:   client.NewAPIClient("https://bsky.social"),
+		client:   traced(client.NewAPIClient("https://bsky.social")),
 		handle:   handle,
 		password: password,
 	}
 }
 
+// NewWithAPIClient creates a client around an existing, already authenticated API client
+// Authenticate is a no-op for these clients; used to inject replay transports in tests
+func NewWithAPIClient(handle string, apiClient *client.APIClient) *BlueskyClient {
+	return &BlueskyClient{
+		client:           apiClient,
+		handle:           handle,
+		preAuthenticated: true,
+	}
+}
+
 func (c *BlueskyClient) Authenticate() error {
+	if c.preAuthenticated {
+		return nil
+	}
+
 	ctx := context.Background()
 
 	// Create an authenticated client
@@ -52,32 +98,70 @@ func (c *BlueskyClient) Authenticate() error {
 	}
 
 	// Replace the client with the authenticated one
-	c.client = authClient
+	c.client = traced(authClient)
 
 	return nil
 }
 
+// traced sends apiClient's requests through a transport that traces them
+func traced(apiClient *client.APIClient) *client.APIClient {
+	apiClient.Client = &http.Client{
+		Transport: tracing.Transport(apiClient.Client.Transport),
+		Timeout:   apiClient.Client.Timeout,
+	}
+	return apiClient
+}
+
+// sleepContext waits for d, returning early with the context's error if it is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // GetTrendingPostsBatch fetches a single batch of posts using cursor-based pagination
 func (c *BlueskyClient) GetTrendingPostsBatch(ctx context.Context, cursor string, cutoffTime time.Time) ([]Post, string, bool, error) {
-	log.Printf("Fetching posts batch with cursor: %s", cursor)
+	return c.SearchPostsBatch(ctx, GlobalSearchQuery, cursor, cutoffTime)
+}
+
+// SearchPostsBatch fetches a single batch of posts matching query using cursor-based pagination
+func (c *BlueskyClient) SearchPostsBatch(ctx context.Context, query, cursor string, cutoffTime time.Time) ([]Post, string, bool, error) {
+	log.Printf("Fetching posts batch for query %q with cursor: %s", query, cursor)
 
 	// Make the API request with retry logic
 	var searchResult *bsky.FeedSearchPosts_Output
 	var err error
 
+	until := ""
+	if !c.searchUntil.IsZero() {
+		until = c.searchUntil.UTC().Format(time.RFC3339)
+	}
+
 	for retries := 0; retries < 3; retries++ {
 		// Search for all public posts - matching original working code (no sort, no since)
 		// The API will return posts sorted by engagement (default), and we'll filter by time client-side
 		log.Printf("Making API request with cursor: '%s' (default sort, no time filter)", cursor)
-		searchResult, err = bsky.FeedSearchPosts(ctx, c.client, "", cursor, "", "en", 100, "", "*", "", "", nil, "", "")
+		searchResult, err = bsky.FeedSearchPosts(ctx, c.client, "", cursor, "", "en", 100, "", query, "", "", nil, until, "")
 		if err == nil {
 			break
 		}
 
+		// The caller's deadline has passed or it gave up; don't retry or skip the cursor
+		if ctx.Err() != nil {
+			return nil, "", false, fmt.Errorf("search cancelled: %w", ctx.Err())
+		}
+
 		// If it's a rate limit error, wait and retry
 		if strings.Contains(err.Error(), "502") || strings.Contains(err.Error(), "rate") {
 			log.Printf("API rate limit hit, waiting 5 seconds before retry %d/3", retries+1)
-			time.Sleep(5 * time.Second)
+			if err := sleepContext(ctx, 5*time.Second); err != nil {
+				return nil, "", false, fmt.Errorf("search cancelled: %w", err)
+			}
 			continue
 		}
 
@@ -88,7 +172,9 @@ func (c *BlueskyClient) GetTrendingPostsBatch(ctx context.Context, cursor string
 				// Wait longer before retrying timeout errors
 				waitTime := time.Duration(retries+1) * 10 * time.Second
 				log.Printf("⏳ Waiting %v before retry...", waitTime)
-				time.Sleep(waitTime)
+				if err := sleepContext(ctx, waitTime); err != nil {
+					return nil, "", false, fmt.Errorf("search cancelled: %w", err)
+				}
 				continue
 			}
 			// After 3 retries, if it's a timeout at a high cursor, skip this cursor
@@ -189,69 +275,14 @@ func (c *BlueskyClient) GetTrendingPostsBatch(ctx context.Context, cursor string
 			continue
 		}
 
-		// Filter out adult content based on moderation labels
-		if c.hasAdultContentLabel(postView.Labels) {
+		// Filter out adult content based on moderation labels; posts whose authors applied
+		// their own content warnings are kept and counted, but never shown as top posts
+		if c.hasAdultContentLabel(labelerLabels(postView)) {
 			log.Printf("Filtering out post with adult content: %s", postView.Uri)
 			continue
 		}
 
-		// Handle pointer fields safely
-		var author string
-		if postView.Author != nil {
-			author = postView.Author.Handle
-		}
-
-		var text string
-		if postView.Record != nil {
-			if feedPost, ok := postView.Record.Val.(*bsky.FeedPost); ok {
-				text = feedPost.Text
-			}
-		}
-
-		// Count engagement metrics - using correct lowercase field names
-		likes := 0
-		if postView.LikeCount != nil {
-			likes = int(*postView.LikeCount)
-		}
-
-		reposts := 0
-		if postView.RepostCount != nil {
-			reposts = int(*postView.RepostCount)
-		}
-
-		replies := 0
-		if postView.ReplyCount != nil {
-			replies = int(*postView.ReplyCount)
-		}
-
-		// Construct proper AT Protocol URI
-		uri := postView.Uri
-		if !strings.HasPrefix(postView.Uri, "at://") && postView.Author != nil {
-			// Try to construct AT Protocol URI from available data
-			// Format: at://did:plc:abc123/app.bsky.feed.post/xyz789
-			if postView.Author.Did != "" {
-				// Use the original URI as the record ID if it's not already an AT Protocol URI
-				// The API might return something like "post-123" or just "123"
-				recordID := strings.TrimPrefix(postView.Uri, "post-")
-				uri = fmt.Sprintf("at://%s/app.bsky.feed.post/%s", postView.Author.Did, recordID)
-			}
-		}
-
-		// Extract CID from the postView
-		cid := postView.Cid
-
-		post := Post{
-			URI:       uri,
-			CID:       cid,
-			Text:      text,
-			Author:    author,
-			Likes:     likes,
-			Reposts:   reposts,
-			Replies:   replies,
-			CreatedAt: postTime.Format(time.RFC3339),
-		}
-
-		posts = append(posts, post)
+		posts = append(posts, convertPostView(postView, postTime))
 	}
 
 	// Extract next cursor and determine if there are more posts
@@ -282,6 +313,141 @@ func (c *BlueskyClient) GetTrendingPostsBatch(ctx context.Context, cursor string
 	return posts, nextCursor, hasMorePosts, nil
 }
 
+// convertPostView converts an API post view to our Post format
+func convertPostView(postView *bsky.FeedDefs_PostView, postTime time.Time) Post {
+	// Handle pointer fields safely
+	var author, avatarURL string
+	if postView.Author != nil {
+		author = postView.Author.Handle
+		if postView.Author.Avatar != nil {
+			avatarURL = *postView.Author.Avatar
+		}
+	}
+
+	var text, replyRoot string
+	var langs []string
+	if postView.Record != nil {
+		if feedPost, ok := postView.Record.Val.(*bsky.FeedPost); ok {
+			text = feedPost.Text
+			langs = feedPost.Langs
+			if feedPost.Reply != nil && feedPost.Reply.Root != nil {
+				replyRoot = feedPost.Reply.Root.Uri
+			}
+		}
+	}
+
+	// Count engagement metrics - using correct lowercase field names
+	likes := 0
+	if postView.LikeCount != nil {
+		likes = int(*postView.LikeCount)
+	}
+
+	reposts := 0
+	if postView.RepostCount != nil {
+		reposts = int(*postView.RepostCount)
+	}
+
+	replies := 0
+	if postView.ReplyCount != nil {
+		replies = int(*postView.ReplyCount)
+	}
+
+	// Construct proper AT Protocol URI
+	uri := postView.Uri
+	if !strings.HasPrefix(postView.Uri, "at://") && postView.Author != nil {
+		// Try to construct AT Protocol URI from available data
+		// Format: at://did:plc:abc123/app.bsky.feed.post/xyz789
+		if postView.Author.Did != "" {
+			// Use the original URI as the record ID if it's not already an AT Protocol URI
+			// The API might return something like "post-123" or just "123"
+			recordID := strings.TrimPrefix(postView.Uri, "post-")
+			uri = fmt.Sprintf("at://%s/app.bsky.feed.post/%s", postView.Author.Did, recordID)
+		}
+	}
+
+	return Post{
+		URI:             uri,
+		CID:             postView.Cid,
+		Text:            text,
+		Author:          author,
+		Likes:           likes,
+		Reposts:         reposts,
+		Replies:         replies,
+		CreatedAt:       postTime.Format(time.RFC3339),
+		Langs:           langs,
+		Media:           mediaKind(postView.Embed),
+		LinkURL:         externalLink(postView.Embed),
+		Images:          embeddedImages(postView.Embed),
+		ReplyRoot:       replyRoot,
+		AvatarURL:       avatarURL,
+		ContentWarnings: contentWarnings(postView),
+	}
+}
+
+// mediaKind returns the kind of media a post embeds, or an empty string for none
+func mediaKind(embed *bsky.FeedDefs_PostView_Embed) string {
+	if embed == nil {
+		return ""
+	}
+
+	images, video, external := embed.EmbedImages_View != nil, embed.EmbedVideo_View != nil, embed.EmbedExternal_View != nil
+	if withMedia := embed.EmbedRecordWithMedia_View; withMedia != nil && withMedia.Media != nil {
+		images = images || withMedia.Media.EmbedImages_View != nil
+		video = video || withMedia.Media.EmbedVideo_View != nil
+		external = external || withMedia.Media.EmbedExternal_View != nil
+	}
+
+	switch {
+	case video:
+		return MediaVideo
+	case images:
+		return MediaImage
+	case external:
+		return MediaExternal
+	default:
+		return ""
+	}
+}
+
+// embeddedImages returns the images a post embeds, or nil for none
+func embeddedImages(embed *bsky.FeedDefs_PostView_Embed) []Image {
+	if embed == nil {
+		return nil
+	}
+	view := embed.EmbedImages_View
+	if withMedia := embed.EmbedRecordWithMedia_View; view == nil && withMedia != nil && withMedia.Media != nil {
+		view = withMedia.Media.EmbedImages_View
+	}
+	if view == nil {
+		return nil
+	}
+
+	images := make([]Image, 0, len(view.Images))
+	for _, image := range view.Images {
+		if image == nil {
+			continue
+		}
+		images = append(images, Image{URL: image.Fullsize, Alt: strings.TrimSpace(image.Alt)})
+	}
+	return images
+}
+
+// externalLink returns the URL of the link card a post embeds, or an empty string for none
+func externalLink(embed *bsky.FeedDefs_PostView_Embed) string {
+	if embed == nil {
+		return ""
+	}
+	if embed.EmbedExternal_View != nil && embed.EmbedExternal_View.External != nil {
+		return embed.EmbedExternal_View.External.Uri
+	}
+	if withMedia := embed.EmbedRecordWithMedia_View; withMedia != nil && withMedia.Media != nil {
+		if external := withMedia.Media.EmbedExternal_View; external != nil && external.External != nil {
+			return external.External.Uri
+		}
+	}
+	return ""
+}
+
 func (c *BlueskyClient) GetTrendingPosts(analysisIntervalMinutes int) ([]Post, error) {
 	ctx := context.Background()
 
@@ -314,7 +480,9 @@ func (c *BlueskyClient) GetTrendingPosts(analysisIntervalMinutes int) ([]Post, e
 			// If it's a rate limit error, wait and retry
 			if strings.Contains(err.Error(), "502") || strings.Contains(err.Error(), "rate") {
 				log.Printf("API rate limit hit, waiting 5 seconds before retry %d/3", retries+1)
-				time.Sleep(5 * time.Second)
+				if err := sleepContext(ctx, 5*time.Second); err != nil {
+					return nil, fmt.Errorf("search cancelled: %w", err)
+				}
 				continue
 			}
 
@@ -430,10 +598,12 @@ func (c *BlueskyClient) GetTrendingPosts(analysisIntervalMinutes int) ([]Post, e
 
 		// Extract the actual post text from the record
 		text := "No text available"
+		var langs []string
 		if postView.Record != nil {
 			// Try to cast the record to FeedPost type
 			if feedPost, ok := postView.Record.Val.(*bsky.FeedPost); ok {
 				text = feedPost.Text
+				langs = feedPost.Langs
 			}
 		}
 
@@ -474,6 +644,7 @@ func (c *BlueskyClient) GetTrendingPosts(analysisIntervalMinutes int) ([]Post, e
 			Reposts:   reposts,
 			Replies:   replies,
 			CreatedAt: postView.IndexedAt,
+			Langs:     langs,
 		}
 
 		// Debug: Log URI format to understand what we're getting
@@ -507,7 +678,24 @@ func (c *BlueskyClient) GetTrendingPosts(analysisIntervalMinutes int) ([]Post, e
 	return posts, nil
 }
 
-func (c *BlueskyClient) PostTrendingSummary(posts []Post, overallSentiment string, analysisIntervalMinutes int, totalPosts int, netSentimentPercentage float64) (string, string, error) {
+// SetSummaryLayout sets the layout of the summaries posted after it
+func (c *BlueskyClient) SetSummaryLayout(layout formatter.Layout) {
+	c.summaryLayout = layout
+}
+
+// SetSearchUntil bounds later searches to posts before until, for runs over a past
+// window; the zero time searches up to now. Feeds can't be bounded and are unaffected.
+func (c *BlueskyClient) SetSearchUntil(until time.Time) {
+	c.searchUntil = until
+}
+
+func (c *BlueskyClient) PostTrendingSummary(posts []Post, overallSentiment string, analysisIntervalMinutes int, totalPosts int, netSentimentPercentage float64, notes ...string) (string, string, error) {
+	return c.PostTrendingSummaryWithImage(posts, overallSentiment, analysisIntervalMinutes, totalPosts, netSentimentPercentage, nil, "", notes...)
+}
+
+// PostTrendingSummaryWithImage posts the summary with an attached image, such as a top posts card
+// The image is combined with the quoted top post embed; a nil image posts a plain summary
+func (c *BlueskyClient) PostTrendingSummaryWithImage(posts []Post, overallSentiment string, analysisIntervalMinutes int, totalPosts int, netSentimentPercentage float64, imageData []byte, altText string, notes ...string) (string, string, error) {
 	ctx := context.Background()
 
 	// Convert client posts to formatter posts
@@ -528,19 +716,19 @@ func (c *BlueskyClient) PostTrendingSummary(posts []Post, overallSentiment strin
 	// Use the pre-calculated sentiment data from all posts, not just the top 5
 
 	// Use shared formatter to generate the post content
-	summaryText := formatter.FormatPostContent(formatterPosts, overallSentiment, analysisIntervalMinutes, totalPosts, netSentimentPercentage)
+	summaryText, spans := formatter.FormatPostContentWithLayout(c.summaryLayout, formatterPosts, overallSentiment, analysisIntervalMinutes, totalPosts, netSentimentPercentage, notes...)
 
-	// Check if we need to truncate, but try to keep all 5 posts
+	// The formatter drops likes and then top posts to fit; only overlong notes are left to truncate
 	if len([]rune(summaryText)) > 300 {
-		// If still too long, truncate but preserve the structure
 		summaryText = truncateText(summaryText, 300)
 	}
 
 	// Post to Bluesky
 	log.Printf("Posting to Bluesky: %s", summaryText)
 
-	// Create facets for clickable links (user handles to posts)
-	facets := createUserHandleFacets(summaryText, posts)
+	// Make the hashtags and author mentions tappable
+	facets := createSummaryFacets(summaryText, spans)
+	summaryText, facets, _ = sanitizePost(summaryText, facets)
 
 	// Create embed card for the first post if available (skip posts with invalid URIs)
 	var embed *bsky.FeedPost_Embed
@@ -556,6 +744,34 @@ func (c *BlueskyClient) PostTrendingSummary(posts []Post, overallSentiment strin
 		}
 	}
 
+	// An image the PDS would reject is dropped so the summary still posts
+	if len(imageData) > 0 {
+		imageData = fitImage(imageData)
+		if err := ValidateImage(imageData); err != nil {
+			log.Printf("⚠️ Posting summary without its image: %v", err)
+			imageData = nil
+		}
+	}
+
+	// Attach the image alongside the quoted post when one was provided
+	if len(imageData) > 0 {
+		imageRef, err := c.UploadImage(ctx, imageData, altText)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to upload summary image: %w", err)
+		}
+		images := &bsky.EmbedImages{Images: []*bsky.EmbedImages_Image{imageRef}}
+		if embed != nil && embed.EmbedRecord != nil {
+			embed = &bsky.FeedPost_Embed{
+				EmbedRecordWithMedia: &bsky.EmbedRecordWithMedia{
+					Record: embed.EmbedRecord,
+					Media:  &bsky.EmbedRecordWithMedia_Media{EmbedImages: images},
+				},
+			}
+		} else {
+			embed = &bsky.FeedPost_Embed{EmbedImages: images}
+		}
+	}
+
 	// Create the post using the AT Protocol
 	postRecord := &bsky.FeedPost{
 		Text:      summaryText,
@@ -602,107 +818,58 @@ func (c *BlueskyClient) createEmbedCard(ctx context.Context, post Post) *bsky.Fe
 	}
 }
 
-// createUserHandleFacets creates facets to link user handles to their posts and mood hashtag
-func createUserHandleFacets(text string, posts []Post) []*bsky.RichtextFacet {
+// createSummaryFacets turns the formatter's tag and mention spans into richtext facets
+// Spans cut off by truncation are skipped, as are mentions whose post URI doesn't name
+// the author's DID.
+func createSummaryFacets(text string, spans []formatter.Span) []*bsky.RichtextFacet {
 	var facets []*bsky.RichtextFacet
 
-	// Create hashtag facet for mood word (e.g., #satisfied)
-	if strings.HasPrefix(text, "Bluesky is #") {
-		// Find the hashtag in the text
-		hashtagStart := strings.Index(text, "#")
-		if hashtagStart != -1 {
-			// Find the end of the hashtag (end of line or space)
-			hashtagEnd := strings.Index(text[hashtagStart:], "\n")
-			if hashtagEnd == -1 {
-				hashtagEnd = len(text)
-			} else {
-				hashtagEnd = hashtagStart + hashtagEnd
-			}
-
-			// Extract the hashtag text (without #)
-			hashtagText := text[hashtagStart+1 : hashtagEnd]
-
-			// Create hashtag facet
-			hashtagFacet := &bsky.RichtextFacet{
-				Index: &bsky.RichtextFacet_ByteSlice{
-					ByteStart: int64(hashtagStart),
-					ByteEnd:   int64(hashtagEnd),
-				},
-				Features: []*bsky.RichtextFacet_Features_Elem{
-					{
-						RichtextFacet_Tag: &bsky.RichtextFacet_Tag{
-							Tag: hashtagText,
-						},
-					},
-				},
-			}
-
-			facets = append(facets, hashtagFacet)
-		}
-	}
-
-	// Create facets for each user handle linking to their post
-	for _, post := range posts {
-		if post.URI == "" {
+	for _, span := range spans {
+		if span.Start < 0 || span.End > len(text) || span.Start >= span.End {
 			continue
 		}
 
-		// Find the handle in the text and create a facet
-		handle := "@" + post.Author
-		startIndex := strings.Index(text, handle)
-		if startIndex == -1 {
+		var feature *bsky.RichtextFacet_Features_Elem
+		switch {
+		case span.Tag != "":
+			if text[span.Start:span.End] != "#"+span.Tag {
+				continue
+			}
+			feature = &bsky.RichtextFacet_Features_Elem{
+				RichtextFacet_Tag: &bsky.RichtextFacet_Tag{Tag: span.Tag},
+			}
+		case span.Handle != "":
+			did := didFromATURI(span.PostURI)
+			if did == "" || text[span.Start:span.End] != "@"+span.Handle {
+				continue
+			}
+			feature = &bsky.RichtextFacet_Features_Elem{
+				RichtextFacet_Mention: &bsky.RichtextFacet_Mention{Did: did},
+			}
+		default:
 			continue
 		}
 
-		endIndex := startIndex + len(handle)
-
-		// Convert AT Protocol URI to web URL for clickable links
-		webURL := convertATURItoWebURL(post.URI)
-
-		facet := &bsky.RichtextFacet{
+		facets = append(facets, &bsky.RichtextFacet{
 			Index: &bsky.RichtextFacet_ByteSlice{
-				ByteStart: int64(startIndex),
-				ByteEnd:   int64(endIndex),
+				ByteStart: int64(span.Start),
+				ByteEnd:   int64(span.End),
 			},
-			Features: []*bsky.RichtextFacet_Features_Elem{
-				{
-					RichtextFacet_Link: &bsky.RichtextFacet_Link{
-						Uri: webURL,
-					},
-				},
-			},
-		}
-
-		facets = append(facets, facet)
+			Features: []*bsky.RichtextFacet_Features_Elem{feature},
+		})
 	}
 
 	return facets
 }
 
-// convertATURItoWebURL converts an AT Protocol URI to a web-friendly URL
-// Example: at://did:plc:abc123/app.bsky.feed.post/xyz789 -> https://bsky.app/profile/did:plc:abc123/post/xyz789
-func convertATURItoWebURL(uri string) string {
-	// Handle AT Protocol URIs
-	if strings.HasPrefix(uri, "at://") {
-		// Remove the at:// prefix
-		uri = strings.TrimPrefix(uri, "at://")
-
-		// Split by / to get parts: [did, app.bsky.feed.post, recordId]
-		parts := strings.Split(uri, "/")
-		if len(parts) >= 3 {
-			did := parts[0]
-			recordType := parts[1]
-			recordId := parts[2]
-
-			// Convert to web URL format
-			if recordType == "app.bsky.feed.post" {
-				return fmt.Sprintf("https://bsky.app/profile/%s/post/%s", did, recordId)
-			}
-		}
+// didFromATURI returns the DID authority of an at:// URI, or "" if it isn't a DID
+// Example: at://did:plc:abc123/app.bsky.feed.post/xyz789 -> did:plc:abc123
+func didFromATURI(uri string) string {
+	authority, _, _ := strings.Cut(strings.TrimPrefix(uri, "at://"), "/")
+	if !strings.HasPrefix(uri, "at://") || !strings.HasPrefix(authority, "did:") {
+		return ""
 	}
-
-	// If it's already a web URL or we can't parse it, return as-is
-	return uri
+	return authority
 }
 
 func truncateText(text string, maxLength int) string {
@@ -745,6 +912,8 @@ func (c *BlueskyClient) PostWithFacets(ctx context.Context, text string, facets
 		return fmt.Errorf("client not authenticated")
 	}
 
+	text, facets, _ = sanitizePost(text, facets)
+
 	// Create a text post with optional facets
 	postRecord := &bsky.FeedPost{
 		Text:      text,
@@ -777,6 +946,12 @@ func (c *BlueskyClient) UploadImage(ctx context.Context, imageData []byte, altTe
 		return nil, fmt.Errorf("client not authenticated")
 	}
 
+	// Shrink oversized images, then check size and type here rather than getting a 400 from the PDS
+	imageData = fitImage(imageData)
+	if err := ValidateImage(imageData); err != nil {
+		return nil, err
+	}
+
 	// Determine content type from image data
 	contentType := "image/png" // Default for our sparkline images
 	if len(imageData) > 4 {
@@ -821,6 +996,12 @@ func (c *BlueskyClient) PostWithImage(ctx context.Context, text string, imageDat
 		return "", "", fmt.Errorf("failed to upload image: %w", err)
 	}
 
+	var postFacets []*bsky.RichtextFacet
+	if len(facets) > 0 {
+		postFacets = facets[0]
+	}
+	text, postFacets, _ = sanitizePost(text, postFacets)
+
 	// Create the post with image embed
 	postRecord := &bsky.FeedPost{
 		Text:      text,
@@ -833,8 +1014,8 @@ func (c *BlueskyClient) PostWithImage(ctx context.Context, text string, imageDat
 	}
 
 	// Add facets if provided
-	if len(facets) > 0 && len(facets[0]) > 0 {
-		postRecord.Facets = facets[0]
+	if len(postFacets) > 0 {
+		postRecord.Facets = postFacets
 	}
 
 	// Post the record
@@ -855,17 +1036,20 @@ func (c *BlueskyClient) PostWithImage(ctx context.Context, text string, imageDat
 }
 
 // PostWithImageAsReply posts a text with an embedded image as a reply to another post
-func (c *BlueskyClient) PostWithImageAsReply(ctx context.Context, text string, imageData []byte, altText string, replyToURI, replyToCID string) error {
+// and returns the reply's URI and CID
+func (c *BlueskyClient) PostWithImageAsReply(ctx context.Context, text string, imageData []byte, altText string, replyToURI, replyToCID string) (string, string, error) {
 	if c.client == nil {
-		return fmt.Errorf("client not authenticated")
+		return "", "", fmt.Errorf("client not authenticated")
 	}
 
 	// Upload the image first
 	imageRef, err := c.UploadImage(ctx, imageData, altText)
 	if err != nil {
-		return fmt.Errorf("failed to upload image: %w", err)
+		return "", "", fmt.Errorf("failed to upload image: %w", err)
 	}
 
+	text, _, _ = sanitizePost(text, nil)
+
 	// Create the post with image embed and reply structure
 	postRecord := &bsky.FeedPost{
 		Text:      text,
@@ -888,22 +1072,80 @@ func (c *BlueskyClient) PostWithImageAsReply(ctx context.Context, text string, i
 	}
 
 	// Post the record
-	_, err = atproto.RepoCreateRecord(ctx, c.client, &atproto.RepoCreateRecord_Input{
+	result, err := atproto.RepoCreateRecord(ctx, c.client, &atproto.RepoCreateRecord_Input{
 		Repo:       c.handle,
 		Collection: "app.bsky.feed.post",
 		Record:     &util.LexiconTypeDecoder{Val: postRecord},
 	})
 
 	if err != nil {
-		return fmt.Errorf("failed to post reply with image: %w", err)
+		return "", "", fmt.Errorf("failed to post reply with image: %w", err)
 	}
 
 	log.Printf("Successfully posted reply with embedded image: %s (replying to: %s)", text[:min(50, len(text))], replyToURI)
-	return nil
+	return result.Uri, result.Cid, nil
 }
 
-// PinPost pins a post to the account's profile
+// PostTextAsReply posts a text reply within a thread and returns the reply's URI and CID
+// The root is the first post of the thread; the parent is the post being replied to
+func (c *BlueskyClient) PostTextAsReply(ctx context.Context, text string, rootURI, rootCID, parentURI, parentCID string) (string, string, error) {
+	if c.client == nil {
+		return "", "", fmt.Errorf("client not authenticated")
+	}
+
+	text, _, _ = sanitizePost(text, nil)
+
+	postRecord := &bsky.FeedPost{
+		Text:      text,
+		CreatedAt: time.Now().Format(time.RFC3339),
+		Reply: &bsky.FeedPost_ReplyRef{
+			Root: &atproto.RepoStrongRef{
+				Uri: rootURI,
+				Cid: rootCID,
+			},
+			Parent: &atproto.RepoStrongRef{
+				Uri: parentURI,
+				Cid: parentCID,
+			},
+		},
+	}
+
+	result, err := atproto.RepoCreateRecord(ctx, c.client, &atproto.RepoCreateRecord_Input{
+		Repo:       c.handle,
+		Collection: "app.bsky.feed.post",
+		Record:     &util.LexiconTypeDecoder{Val: postRecord},
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to post reply: %w", err)
+	}
+
+	log.Printf("Successfully posted reply: %s (replying to: %s)", text[:min(50, len(text))], parentURI)
+	return result.Uri, result.Cid, nil
+}
+
+// PinPost pins a post to the account's profile, replacing any previously pinned post
 func (c *BlueskyClient) PinPost(ctx context.Context, postURI string, postCID string) error {
+	if err := c.setPinnedPost(ctx, &atproto.RepoStrongRef{Uri: postURI, Cid: postCID}); err != nil {
+		return fmt.Errorf("failed to pin post: %w", err)
+	}
+
+	log.Printf("Successfully pinned post: %s", postURI)
+	return nil
+}
+
+// UnpinPost clears the pinned post from the account's profile
+func (c *BlueskyClient) UnpinPost(ctx context.Context) error {
+	if err := c.setPinnedPost(ctx, nil); err != nil {
+		return fmt.Errorf("failed to unpin post: %w", err)
+	}
+
+	log.Printf("Successfully unpinned post")
+	return nil
+}
+
+// setPinnedPost rewrites the profile record with pinnedPost, preserving its other fields
+// A nil pinnedPost clears the pin
+func (c *BlueskyClient) setPinnedPost(ctx context.Context, pinnedPost *atproto.RepoStrongRef) error {
 	if c.client == nil {
 		return fmt.Errorf("client not authenticated")
 	}
@@ -961,12 +1203,6 @@ func (c *BlueskyClient) PinPost(ctx context.Context, postURI string, postCID str
 		return fmt.Errorf("failed to parse profile record as ActorProfile")
 	}
 
-	// Create pinned post reference
-	pinnedPost := &atproto.RepoStrongRef{
-		Uri: postURI,
-		Cid: postCID,
-	}
-
 	// Update profile with pinned post (preserves all other fields)
 	profileRecord.PinnedPost = pinnedPost
 
@@ -980,10 +1216,9 @@ func (c *BlueskyClient) PinPost(ctx context.Context, postURI string, postCID str
 	})
 
 	if err != nil {
-		return fmt.Errorf("failed to pin post: %w", err)
+		return fmt.Errorf("failed to update profile: %w", err)
 	}
 
-	log.Printf("Successfully pinned post: %s", postURI)
 	return nil
 }
 
@@ -0,0 +1,219 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/analyzer"
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/client/clienttest"
+)
+
+var fixtureCutoff = time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC)
+
+func TestGetTrendingPostsBatchReplay(t *testing.T) {
+	transport := clienttest.NewReplayTransport(clienttest.DefaultFixtures())
+	bsky := clienttest.NewClient(transport)
+
+	if err := bsky.Authenticate(); err != nil {
+		t.Fatalf("Authenticate() on replay client error = %v", err)
+	}
+
+	posts, nextCursor, hasMore, err := bsky.GetTrendingPostsBatch(context.Background(), "", fixtureCutoff)
+	if err != nil {
+		t.Fatalf("GetTrendingPostsBatch() page 1 error = %v", err)
+	}
+
+	// The adult-labeled post is filtered out
+	if len(posts) != 3 {
+		t.Errorf("Expected 3 posts on page 1, got %d", len(posts))
+	}
+	if nextCursor != "100" || !hasMore {
+		t.Errorf("Expected cursor 100 with more pages, got %q (hasMore: %v)", nextCursor, hasMore)
+	}
+	for _, post := range posts {
+		if post.Author == "nsfw.bsky.social" {
+			t.Errorf("Adult-labeled post was not filtered: %s", post.URI)
+		}
+	}
+
+	posts, _, _, err = bsky.GetTrendingPostsBatch(context.Background(), nextCursor, fixtureCutoff)
+	if err != nil {
+		t.Fatalf("GetTrendingPostsBatch() page 2 error = %v", err)
+	}
+
+	// The post from before the cutoff is filtered out
+	if len(posts) != 1 || posts[0].Author != "dave.bsky.social" {
+		t.Errorf("Expected only dave's post on page 2, got %+v", posts)
+	}
+
+	if unmatched := transport.Unmatched(); len(unmatched) > 0 {
+		t.Errorf("Unexpected unmatched requests: %+v", unmatched)
+	}
+}
+
+func TestFetchAnalyzePostReplay(t *testing.T) {
+	transport := clienttest.NewReplayTransport(clienttest.DefaultFixtures())
+	bsky := clienttest.NewClient(transport)
+	ctx := context.Background()
+
+	// Fetch both pages
+	var fetched []client.Post
+	cursor := ""
+	for page := 0; page < 2; page++ {
+		posts, nextCursor, _, err := bsky.GetTrendingPostsBatch(ctx, cursor, fixtureCutoff)
+		if err != nil {
+			t.Fatalf("GetTrendingPostsBatch() error = %v", err)
+		}
+		fetched = append(fetched, posts...)
+		cursor = nextCursor
+	}
+
+	// Analyze
+	analyzerPosts := make([]analyzer.Post, len(fetched))
+	for i, post := range fetched {
+		analyzerPosts[i] = analyzer.Post{
+			URI:       post.URI,
+			CID:       post.CID,
+			Text:      post.Text,
+			Author:    post.Author,
+			Likes:     post.Likes,
+			Reposts:   post.Reposts,
+			Replies:   post.Replies,
+			CreatedAt: post.CreatedAt,
+		}
+	}
+	analyzed, err := analyzer.New().AnalyzePosts(analyzerPosts)
+	if err != nil {
+		t.Fatalf("AnalyzePosts() error = %v", err)
+	}
+
+	topPosts := make([]client.Post, len(analyzed))
+	for i, post := range analyzed {
+		topPosts[i] = client.Post{
+			URI:             post.URI,
+			CID:             post.CID,
+			Author:          post.Author,
+			Sentiment:       post.Sentiment,
+			EngagementScore: post.EngagementScore,
+		}
+	}
+
+	// Post
+	uri, cid, err := bsky.PostTrendingSummary(topPosts, "positive", 30, len(fetched), 0.25)
+	if err != nil {
+		t.Fatalf("PostTrendingSummary() error = %v", err)
+	}
+	if !strings.HasSuffix(uri, "/3lsummary0001") || cid == "" {
+		t.Errorf("Expected recorded summary URI and CID, got %s %s", uri, cid)
+	}
+
+	created := transport.RequestsTo("com.atproto.repo.createRecord")
+	if len(created) != 1 {
+		t.Fatalf("Expected 1 createRecord request, got %d", len(created))
+	}
+
+	var body struct {
+		Repo   string `json:"repo"`
+		Record struct {
+			Text  string `json:"text"`
+			Embed struct {
+				Record struct {
+					URI string `json:"uri"`
+				} `json:"record"`
+			} `json:"embed"`
+		} `json:"record"`
+	}
+	if err := json.Unmarshal(created[0].Body, &body); err != nil {
+		t.Fatalf("Failed to decode createRecord body: %v", err)
+	}
+
+	if body.Repo != clienttest.Handle {
+		t.Errorf("Expected repo %s, got %s", clienttest.Handle, body.Repo)
+	}
+	if !strings.HasPrefix(body.Record.Text, "Bluesky is #") {
+		t.Errorf("Unexpected post text: %q", body.Record.Text)
+	}
+	for _, post := range fetched {
+		if !strings.Contains(body.Record.Text, "@"+post.Author) {
+			t.Errorf("Post text missing @%s: %q", post.Author, body.Record.Text)
+		}
+	}
+	if body.Record.Embed.Record.URI != topPosts[0].URI {
+		t.Errorf("Expected embed of first top post %s, got %s", topPosts[0].URI, body.Record.Embed.Record.URI)
+	}
+}
+
+func TestPostWithImageReplay(t *testing.T) {
+	transport := clienttest.NewReplayTransport(clienttest.DefaultFixtures())
+	bsky := clienttest.NewClient(transport)
+
+	png := []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}
+	uri, _, err := bsky.PostWithImage(context.Background(), "Sentiment chart", png, "A sparkline")
+	if err != nil {
+		t.Fatalf("PostWithImage() error = %v", err)
+	}
+	if uri == "" {
+		t.Error("Expected a post URI")
+	}
+
+	if got := len(transport.RequestsTo("com.atproto.repo.uploadBlob")); got != 1 {
+		t.Errorf("Expected 1 uploadBlob request, got %d", got)
+	}
+}
+
+func TestPostTrendingSummaryWithImageReplay(t *testing.T) {
+	transport := clienttest.NewReplayTransport(clienttest.DefaultFixtures())
+	bsky := clienttest.NewClient(transport)
+
+	topPosts := []client.Post{{
+		URI:       "at://did:plc:alice0000000000000000000/app.bsky.feed.post/3l1abcdef",
+		CID:       "bafyreiamthap7f5ltwiyaonpd44qochkdyfdf7xnlrbunezi7yvvlhesma",
+		Author:    "alice.bsky.social",
+		Sentiment: "positive",
+	}}
+	png := []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}
+	if _, _, err := bsky.PostTrendingSummaryWithImage(topPosts, "positive", 60, 100, 0.25, png, "Top posts card"); err != nil {
+		t.Fatalf("PostTrendingSummaryWithImage() error = %v", err)
+	}
+
+	created := transport.RequestsTo("com.atproto.repo.createRecord")
+	if len(created) != 1 {
+		t.Fatalf("Expected 1 createRecord request, got %d", len(created))
+	}
+
+	var body struct {
+		Record struct {
+			Embed struct {
+				Type   string `json:"$type"`
+				Record struct {
+					Record struct {
+						URI string `json:"uri"`
+					} `json:"record"`
+				} `json:"record"`
+				Media struct {
+					Images []struct {
+						Alt string `json:"alt"`
+					} `json:"images"`
+				} `json:"media"`
+			} `json:"embed"`
+		} `json:"record"`
+	}
+	if err := json.Unmarshal(created[0].Body, &body); err != nil {
+		t.Fatalf("Failed to decode createRecord body: %v", err)
+	}
+
+	embed := body.Record.Embed
+	if embed.Type != "app.bsky.embed.recordWithMedia" {
+		t.Errorf("Expected recordWithMedia embed, got %q", embed.Type)
+	}
+	if embed.Record.Record.URI != topPosts[0].URI {
+		t.Errorf("Expected quoted top post %s, got %s", topPosts[0].URI, embed.Record.Record.URI)
+	}
+	if len(embed.Media.Images) != 1 || embed.Media.Images[0].Alt != "Top posts card" {
+		t.Errorf("Expected one image with alt text, got %+v", embed.Media.Images)
+	}
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/api/chat"
+)
+
+// chatService is the service reference chat.bsky requests are proxied to through the PDS
+const chatService = "did:web:api.bsky.chat#bsky_chat"
+
+// MaxMessageGraphemes is the longest direct message the chat service accepts
+const MaxMessageGraphemes = 1000
+
+// SendDirectMessage sends text to recipient's direct messages, opening a conversation with
+// them if there isn't one. recipient is a handle or DID, and must accept messages from the
+// account; its app password needs direct message access. Text over MaxMessageGraphemes is cut.
+func (c *BlueskyClient) SendDirectMessage(ctx context.Context, recipient, text string) error {
+	if c.client == nil {
+		return fmt.Errorf("client not authenticated")
+	}
+
+	did, err := c.resolveDID(ctx, recipient)
+	if err != nil {
+		return err
+	}
+
+	chatClient := c.client.WithService(chatService)
+	convo, err := chat.ConvoGetConvoForMembers(ctx, chatClient, []string{did})
+	if err != nil {
+		return fmt.Errorf("failed to open conversation with %s: %w", recipient, err)
+	}
+	if convo.Convo == nil {
+		return fmt.Errorf("no conversation returned for %s", recipient)
+	}
+
+	_, err = chat.ConvoSendMessage(ctx, chatClient, &chat.ConvoSendMessage_Input{
+		ConvoId: convo.Convo.Id,
+		Message: &chat.ConvoDefs_MessageInput{Text: TruncateGraphemes(text, MaxMessageGraphemes)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send message to %s: %w", recipient, err)
+	}
+	return nil
+}
+
+// resolveDID returns the DID of a handle or DID, with or without a leading @
+func (c *BlueskyClient) resolveDID(ctx context.Context, actor string) (string, error) {
+	actor = strings.TrimPrefix(strings.TrimSpace(actor), "@")
+	if actor == "" {
+		return "", fmt.Errorf("no recipient given")
+	}
+	if strings.HasPrefix(actor, "did:") {
+		return actor, nil
+	}
+
+	resolved, err := atproto.IdentityResolveHandle(ctx, c.client, actor)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", actor, err)
+	}
+	return resolved.Did, nil
+}
@@ -0,0 +1,69 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/client/clienttest"
+)
+
+const adminDID = "did:plc:admin00000000000000000000"
+
+func TestSendDirectMessage(t *testing.T) {
+	sent := interaction(t, "send", "/xrpc/chat.bsky.convo.sendMessage", nil, map[string]any{
+		"id": "msg1", "rev": "1", "text": "hi", "sender": map[string]any{"did": clienttest.DID}, "sentAt": "2025-01-05T12:00:00Z",
+	})
+	sent.Request.Method = "POST"
+	transport := clienttest.NewReplayTransport([]clienttest.Interaction{
+		interaction(t, "resolve", "/xrpc/com.atproto.identity.resolveHandle", map[string]string{"handle": "admin.bsky.social"}, map[string]any{"did": adminDID}),
+		interaction(t, "convo", "/xrpc/chat.bsky.convo.getConvoForMembers", map[string]string{"members": adminDID}, map[string]any{
+			"convo": map[string]any{"id": "convo1", "rev": "1", "members": []any{}, "muted": false, "unreadCount": 0},
+		}),
+		sent,
+	})
+	bsky := clienttest.NewClient(transport)
+
+	text := strings.Repeat("x", client.MaxMessageGraphemes+50)
+	if err := bsky.SendDirectMessage(context.Background(), "@admin.bsky.social", text); err != nil {
+		t.Fatalf("SendDirectMessage() error = %v", err)
+	}
+	if unmatched := transport.Unmatched(); len(unmatched) > 0 {
+		t.Fatalf("unexpected requests: %+v", unmatched)
+	}
+
+	requests := transport.RequestsTo("chat.bsky.convo.sendMessage")
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 sendMessage request, got %d", len(requests))
+	}
+	var input struct {
+		ConvoID string `json:"convoId"`
+		Message struct {
+			Text string `json:"text"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(requests[0].Body, &input); err != nil {
+		t.Fatal(err)
+	}
+	if input.ConvoID != "convo1" || client.GraphemeCount(input.Message.Text) != client.MaxMessageGraphemes {
+		t.Errorf("sent %q with %d graphemes, want convo1 cut to %d", input.ConvoID, client.GraphemeCount(input.Message.Text), client.MaxMessageGraphemes)
+	}
+}
+
+func TestSendDirectMessageToDID(t *testing.T) {
+	transport := clienttest.NewReplayTransport(nil)
+	bsky := clienttest.NewClient(transport)
+
+	// A DID needs no resolving, so the first request opens the conversation
+	if err := bsky.SendDirectMessage(context.Background(), adminDID, "hi"); err == nil {
+		t.Fatal("expected an error when the chat service can't be reached")
+	}
+	if requests := transport.Requests(); len(requests) == 0 || requests[0].Path != "/xrpc/chat.bsky.convo.getConvoForMembers" {
+		t.Errorf("first request = %+v, want getConvoForMembers", requests)
+	}
+	if err := bsky.SendDirectMessage(context.Background(), " ", "hi"); err == nil {
+		t.Error("expected an error for an empty recipient")
+	}
+}
@@ -0,0 +1,396 @@
+package clienttest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/formatter"
+)
+
+// MockBatch is one page returned by MockClient.GetTrendingPostsBatch
+type MockBatch struct {
+	Posts   []client.Post
+	Cursor  string
+	HasMore bool
+	Err     error
+}
+
+// MockPost records a post made through MockClient
+type MockPost struct {
+	URI        string
+	CID        string
+	Text       string
+	Facets     []*bsky.RichtextFacet
+	ImageData  []byte
+	AltText    string
+	ReplyToURI string
+	ReplyToCID string
+	RootURI    string           // Thread root for replies; equals ReplyToURI for direct replies
+	Summary    []client.Post    // Top posts passed to PostTrendingSummary, nil for other post types
+	Layout     formatter.Layout // Summary layout set with SetSummaryLayout, for summaries
+}
+
+// MockMessage records a direct message sent through MockClient
+type MockMessage struct {
+	Recipient string
+	Text      string
+}
+
+// MockClient is an in-memory client.Client for handler tests
+// Fetches are served from Batches in order; posts are recorded and assigned sequential URIs
+type MockClient struct {
+	Batches []MockBatch
+
+	// FeedName is returned by ResolveFeed as the feed's display name
+	FeedName string
+
+	// Engagement is returned by GetPostEngagement for the URIs it has
+	Engagement map[string]client.Engagement
+
+	// OwnPosts is returned by GetOwnPosts, keeping those created since the given time
+	OwnPosts []client.OwnPost
+
+	// AuthErr and PostErr, when set, are returned by Authenticate and every posting method,
+	// SendDirectMessage included
+	AuthErr error
+	PostErr error
+
+	mu             sync.Mutex
+	nextBatch      int
+	authenticated  int
+	feedURIs       []string
+	queries        []string
+	posts          []MockPost
+	pinned         []string
+	unpins         int
+	gated          map[string]client.InteractionSettings
+	uploadedImages int
+	layout         formatter.Layout
+	searchUntil    time.Time
+	messages       []MockMessage
+}
+
+var _ client.Client = (*MockClient)(nil)
+
+// NewMockClient creates a mock that serves the given batches
+func NewMockClient(batches ...MockBatch) *MockClient {
+	return &MockClient{Batches: batches}
+}
+
+// Factory returns a client.Factory that always hands out this mock, ignoring credentials
+func (m *MockClient) Factory() client.Factory {
+	return func(handle, password string) client.Client {
+		return m
+	}
+}
+
+// Authenticate counts the call and returns AuthErr
+func (m *MockClient) Authenticate() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.authenticated++
+	return m.AuthErr
+}
+
+// GetTrendingPostsBatch returns the next batch, or an empty final page once Batches is exhausted
+// Posts created before cutoffTime are filtered out, matching the live client
+func (m *MockClient) GetTrendingPostsBatch(ctx context.Context, cursor string, cutoffTime time.Time) ([]client.Post, string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.nextBatch >= len(m.Batches) {
+		return nil, "", false, nil
+	}
+	batch := m.Batches[m.nextBatch]
+	m.nextBatch++
+
+	if batch.Err != nil {
+		return nil, "", false, batch.Err
+	}
+	return filterByCutoff(batch.Posts, cutoffTime), batch.Cursor, batch.HasMore, nil
+}
+
+// SearchPostsBatch records the query and serves the next batch like GetTrendingPostsBatch
+func (m *MockClient) SearchPostsBatch(ctx context.Context, query, cursor string, cutoffTime time.Time) ([]client.Post, string, bool, error) {
+	m.mu.Lock()
+	m.queries = append(m.queries, query)
+	m.mu.Unlock()
+	return m.GetTrendingPostsBatch(ctx, cursor, cutoffTime)
+}
+
+// Queries returns the query of every SearchPostsBatch call, in order
+func (m *MockClient) Queries() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.queries...)
+}
+
+// SetSearchUntil records the bound; batches are served regardless of it
+func (m *MockClient) SetSearchUntil(until time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.searchUntil = until
+}
+
+// SearchUntil returns the bound set with SetSearchUntil
+func (m *MockClient) SearchUntil() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.searchUntil
+}
+
+// GetFeedPostsBatch records the feed URI and serves the next batch like GetTrendingPostsBatch
+func (m *MockClient) GetFeedPostsBatch(ctx context.Context, feedURI, cursor string, cutoffTime time.Time) ([]client.Post, string, bool, error) {
+	m.mu.Lock()
+	m.feedURIs = append(m.feedURIs, feedURI)
+	m.mu.Unlock()
+	return m.GetTrendingPostsBatch(ctx, cursor, cutoffTime)
+}
+
+// ResolveFeed returns the feed with FeedName as its display name
+func (m *MockClient) ResolveFeed(ctx context.Context, feedURI string) (client.FeedSource, error) {
+	kind, err := client.FeedKind(feedURI)
+	if err != nil {
+		return client.FeedSource{}, err
+	}
+	return client.FeedSource{URI: feedURI, Kind: kind, Name: m.FeedName}, nil
+}
+
+// FeedURIs returns the feed URI of every GetFeedPostsBatch call, in order
+func (m *MockClient) FeedURIs() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.feedURIs...)
+}
+
+// GetTrendingPosts returns every post from the remaining batches
+func (m *MockClient) GetTrendingPosts(analysisIntervalMinutes int) ([]client.Post, error) {
+	cutoffTime := time.Now().Add(-time.Duration(analysisIntervalMinutes) * time.Minute)
+
+	var posts []client.Post
+	for {
+		batch, _, hasMore, err := m.GetTrendingPostsBatch(context.Background(), "", cutoffTime)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, batch...)
+		if !hasMore {
+			return posts, nil
+		}
+	}
+}
+
+// GetPostEngagement returns the Engagement entries for the given URIs
+func (m *MockClient) GetPostEngagement(ctx context.Context, uris []string) (map[string]client.Engagement, error) {
+	engagement := make(map[string]client.Engagement)
+	for _, uri := range uris {
+		if e, ok := m.Engagement[uri]; ok {
+			engagement[uri] = e
+		}
+	}
+	return engagement, nil
+}
+
+// GetOwnPosts returns the OwnPosts created since the given time
+func (m *MockClient) GetOwnPosts(ctx context.Context, since time.Time) ([]client.OwnPost, error) {
+	var posts []client.OwnPost
+	for _, post := range m.OwnPosts {
+		if !post.CreatedAt.Before(since) {
+			posts = append(posts, post)
+		}
+	}
+	return posts, nil
+}
+
+// SetSummaryLayout records the layout for the summaries posted after it
+func (m *MockClient) SetSummaryLayout(layout formatter.Layout) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.layout = layout
+}
+
+// PostTrendingSummary records a summary post
+func (m *MockClient) PostTrendingSummary(posts []client.Post, overallSentiment string, analysisIntervalMinutes int, totalPosts int, netSentimentPercentage float64, notes ...string) (string, string, error) {
+	return m.PostTrendingSummaryWithImage(posts, overallSentiment, analysisIntervalMinutes, totalPosts, netSentimentPercentage, nil, "", notes...)
+}
+
+// PostTrendingSummaryWithImage records a summary post with its attached image, if any
+func (m *MockClient) PostTrendingSummaryWithImage(posts []client.Post, overallSentiment string, analysisIntervalMinutes int, totalPosts int, netSentimentPercentage float64, imageData []byte, altText string, notes ...string) (string, string, error) {
+	text := fmt.Sprintf("%s %.1f%% sentiment, %d posts in %d min", overallSentiment, netSentimentPercentage*100, totalPosts, analysisIntervalMinutes)
+	for _, note := range notes {
+		if note != "" {
+			text += "\n" + note
+		}
+	}
+	m.mu.Lock()
+	layout := m.layout
+	m.mu.Unlock()
+	post, err := m.record(MockPost{Text: text, ImageData: imageData, AltText: altText, Summary: append([]client.Post{}, posts...), Layout: layout})
+	return post.URI, post.CID, err
+}
+
+// PostText records a plain text post
+func (m *MockClient) PostText(ctx context.Context, text string) error {
+	return m.PostWithFacets(ctx, text, nil)
+}
+
+// PostWithFacets records a text post with facets
+func (m *MockClient) PostWithFacets(ctx context.Context, text string, facets []*bsky.RichtextFacet) error {
+	_, err := m.record(MockPost{Text: text, Facets: facets})
+	return err
+}
+
+// UploadImage counts the upload and returns a placeholder image embed
+func (m *MockClient) UploadImage(ctx context.Context, imageData []byte, altText string) (*bsky.EmbedImages_Image, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.PostErr != nil {
+		return nil, m.PostErr
+	}
+	m.uploadedImages++
+	return &bsky.EmbedImages_Image{Alt: altText}, nil
+}
+
+// PostWithImage records an image post
+func (m *MockClient) PostWithImage(ctx context.Context, text string, imageData []byte, altText string, facets ...[]*bsky.RichtextFacet) (string, string, error) {
+	post := MockPost{Text: text, ImageData: imageData, AltText: altText}
+	if len(facets) > 0 {
+		post.Facets = facets[0]
+	}
+	post, err := m.record(post)
+	return post.URI, post.CID, err
+}
+
+// PostWithImageAsReply records an image post replying to another post
+func (m *MockClient) PostWithImageAsReply(ctx context.Context, text string, imageData []byte, altText string, replyToURI, replyToCID string) (string, string, error) {
+	post, err := m.record(MockPost{Text: text, ImageData: imageData, AltText: altText, ReplyToURI: replyToURI, ReplyToCID: replyToCID, RootURI: replyToURI})
+	return post.URI, post.CID, err
+}
+
+// PostTextAsReply records a text reply within a thread
+func (m *MockClient) PostTextAsReply(ctx context.Context, text string, rootURI, rootCID, parentURI, parentCID string) (string, string, error) {
+	post, err := m.record(MockPost{Text: text, ReplyToURI: parentURI, ReplyToCID: parentCID, RootURI: rootURI})
+	return post.URI, post.CID, err
+}
+
+// PinPost records the pinned post URI
+func (m *MockClient) PinPost(ctx context.Context, postURI string, postCID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.PostErr != nil {
+		return m.PostErr
+	}
+	m.pinned = append(m.pinned, postURI)
+	return nil
+}
+
+// UnpinPost records that the pinned post was cleared
+func (m *MockClient) UnpinPost(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.PostErr != nil {
+		return m.PostErr
+	}
+	m.unpins++
+	return nil
+}
+
+// SetInteractionSettings records the settings applied to a post
+func (m *MockClient) SetInteractionSettings(ctx context.Context, postURI string, settings client.InteractionSettings) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.PostErr != nil {
+		return m.PostErr
+	}
+	if m.gated == nil {
+		m.gated = make(map[string]client.InteractionSettings)
+	}
+	m.gated[postURI] = settings
+	return nil
+}
+
+// InteractionSettings returns the settings applied to postURI, and whether any were
+func (m *MockClient) InteractionSettings(postURI string) (client.InteractionSettings, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	settings, ok := m.gated[postURI]
+	return settings, ok
+}
+
+// SendDirectMessage records the message, or returns PostErr without recording
+func (m *MockClient) SendDirectMessage(ctx context.Context, recipient, text string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.PostErr != nil {
+		return m.PostErr
+	}
+	m.messages = append(m.messages, MockMessage{Recipient: recipient, Text: text})
+	return nil
+}
+
+// Messages returns every direct message sent so far, in order
+func (m *MockClient) Messages() []MockMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MockMessage(nil), m.messages...)
+}
+
+// Posts returns every post recorded so far, in order
+func (m *MockClient) Posts() []MockPost {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MockPost(nil), m.posts...)
+}
+
+// Pinned returns the URIs passed to PinPost, in order
+func (m *MockClient) Pinned() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.pinned...)
+}
+
+// Unpins returns how many times UnpinPost was called
+func (m *MockClient) Unpins() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.unpins
+}
+
+// AuthenticateCalls returns how many times Authenticate was called
+func (m *MockClient) AuthenticateCalls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.authenticated
+}
+
+// record stores a post with a sequential URI, or returns PostErr without recording
+func (m *MockClient) record(post MockPost) (MockPost, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.PostErr != nil {
+		return MockPost{}, m.PostErr
+	}
+	n := len(m.posts) + 1
+	post.URI = fmt.Sprintf("at://%s/app.bsky.feed.post/mock%04d", DID, n)
+	post.CID = fmt.Sprintf("bafymockcid%04d", n)
+	m.posts = append(m.posts, post)
+	return post, nil
+}
+
+// filterByCutoff drops posts created before cutoffTime; posts with unparseable timestamps are kept
+func filterByCutoff(posts []client.Post, cutoffTime time.Time) []client.Post {
+	var kept []client.Post
+	for _, post := range posts {
+		createdAt, err := time.Parse(time.RFC3339, post.CreatedAt)
+		if err == nil && createdAt.Before(cutoffTime) {
+			continue
+		}
+		kept = append(kept, post)
+	}
+	return kept
+}
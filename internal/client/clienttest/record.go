@@ -0,0 +1,99 @@
+package clienttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// recordedQueryParams are the query parameters that distinguish otherwise identical requests
+// (e.g. pages of searchPosts); everything else is left out of recorded fixtures
+var recordedQueryParams = []string{"cursor", "q", "actor", "feed", "list"}
+
+// RecordingTransport wraps a real transport and saves every request/response pair as a fixture
+// Authentication requests are never recorded so fixtures can't leak credentials or tokens
+type RecordingTransport struct {
+	Base http.RoundTripper
+	Dir  string
+
+	mu    sync.Mutex
+	count int
+}
+
+// NewRecordingTransport records interactions into dir using http.DefaultTransport
+func NewRecordingTransport(dir string) *RecordingTransport {
+	return &RecordingTransport{
+		Base: http.DefaultTransport,
+		Dir:  dir,
+	}
+}
+
+// RoundTrip performs the request and writes the interaction to the fixture directory
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.Contains(req.URL.Path, "com.atproto.server.") {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if !json.Valid(body) {
+		return resp, nil // Only JSON responses can be replayed
+	}
+
+	query := make(map[string]string)
+	for _, key := range recordedQueryParams {
+		if req.URL.Query().Has(key) {
+			query[key] = req.URL.Query().Get(key)
+		}
+	}
+
+	nsid := strings.TrimPrefix(req.URL.Path, "/xrpc/")
+
+	t.mu.Lock()
+	t.count++
+	name := fmt.Sprintf("%03d_%s", t.count, nsid)
+	t.mu.Unlock()
+
+	interaction := Interaction{
+		Name: name,
+		Request: RecordedRequest{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  query,
+		},
+		Response: RecordedResponse{
+			Status: resp.StatusCode,
+			Body:   body,
+		},
+	}
+
+	data, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal interaction: %w", err)
+	}
+
+	if err := os.MkdirAll(t.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(t.Dir, name+".json"), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write fixture: %w", err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,234 @@
+// Package clienttest provides a mock Bluesky client, an HTTP replay transport, and recorded API fixtures
+// so code built on client.BlueskyClient can be tested without live credentials
+package clienttest
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	indigoclient "github.com/bluesky-social/indigo/atproto/client"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+)
+
+//go:embed testdata/*.json
+var defaultFixtures embed.FS
+
+// Host is the PDS host recorded fixtures were captured against
+const Host = "https://bsky.social"
+
+// Handle and DID of the account the fixtures were recorded with
+const (
+	Handle = "hourstats-test.bsky.social"
+	DID    = "did:plc:hourstatstestaccount"
+)
+
+// Interaction is a single recorded request/response pair
+type Interaction struct {
+	Name     string           `json:"name"`
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest identifies which requests an interaction answers
+// Only the query parameters listed are matched; others are ignored
+type RecordedRequest struct {
+	Method string            `json:"method"`
+	Path   string            `json:"path"`
+	Query  map[string]string `json:"query,omitempty"`
+}
+
+// RecordedResponse is the response replayed for a matching request
+type RecordedResponse struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// ReplayedRequest captures a request served by the replay transport, for assertions in tests
+type ReplayedRequest struct {
+	Method string
+	Path   string
+	Query  map[string]string
+	Body   []byte
+	Name   string // Name of the interaction that answered, empty if unmatched
+}
+
+// ReplayTransport is an http.RoundTripper that answers requests from recorded interactions
+// Each interaction is used once, in load order, so paginated endpoints replay page by page
+type ReplayTransport struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+	requests     []ReplayedRequest
+}
+
+// NewReplayTransport creates a transport replaying the given interactions
+func NewReplayTransport(interactions []Interaction) *ReplayTransport {
+	return &ReplayTransport{
+		interactions: interactions,
+		used:         make([]bool, len(interactions)),
+	}
+}
+
+// LoadFixtures reads every *.json interaction in dir, sorted by file name
+func LoadFixtures(fsys fs.FS, dir string) ([]Interaction, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	interactions := make([]Interaction, 0, len(names))
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture %s: %w", name, err)
+		}
+
+		var interaction Interaction
+		if err := json.Unmarshal(data, &interaction); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture %s: %w", name, err)
+		}
+		if interaction.Name == "" {
+			interaction.Name = strings.TrimSuffix(name, ".json")
+		}
+		interactions = append(interactions, interaction)
+	}
+
+	return interactions, nil
+}
+
+// DefaultFixtures returns the recorded searchPosts, uploadBlob, and createRecord fixtures
+// shipped with this package
+func DefaultFixtures() []Interaction {
+	interactions, err := LoadFixtures(defaultFixtures, "testdata")
+	if err != nil {
+		// The fixtures are embedded at build time, so this only fails if they are malformed
+		panic(fmt.Sprintf("clienttest: invalid embedded fixtures: %v", err))
+	}
+	return interactions
+}
+
+// RoundTrip answers the request with the first unused matching interaction
+// Unmatched requests get an XRPC-style 501 error so the failure is visible to the caller
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body.Close()
+	}
+
+	query := make(map[string]string)
+	for key, values := range req.URL.Query() {
+		if len(values) > 0 {
+			query[key] = values[0]
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	replayed := ReplayedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  query,
+		Body:   body,
+	}
+
+	for i, interaction := range t.interactions {
+		if t.used[i] || !matches(interaction.Request, req.Method, req.URL.Path, query) {
+			continue
+		}
+		t.used[i] = true
+		replayed.Name = interaction.Name
+		t.requests = append(t.requests, replayed)
+		return newResponse(req, interaction.Response.Status, interaction.Response.Body), nil
+	}
+
+	t.requests = append(t.requests, replayed)
+	errorBody := fmt.Sprintf(`{"error":"NoFixture","message":"no recorded interaction for %s %s"}`, req.Method, req.URL.Path)
+	return newResponse(req, http.StatusNotImplemented, []byte(errorBody)), nil
+}
+
+// Requests returns every request the transport has served, in order
+func (t *ReplayTransport) Requests() []ReplayedRequest {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]ReplayedRequest(nil), t.requests...)
+}
+
+// RequestsTo returns the served requests for a single XRPC method, e.g. "com.atproto.repo.createRecord"
+func (t *ReplayTransport) RequestsTo(nsid string) []ReplayedRequest {
+	var matched []ReplayedRequest
+	for _, req := range t.Requests() {
+		if req.Path == "/xrpc/"+nsid {
+			matched = append(matched, req)
+		}
+	}
+	return matched
+}
+
+// Unmatched returns requests that had no recorded interaction
+func (t *ReplayTransport) Unmatched() []ReplayedRequest {
+	var unmatched []ReplayedRequest
+	for _, req := range t.Requests() {
+		if req.Name == "" {
+			unmatched = append(unmatched, req)
+		}
+	}
+	return unmatched
+}
+
+// NewClient returns a pre-authenticated BlueskyClient whose requests are served by transport
+func NewClient(transport http.RoundTripper) *client.BlueskyClient {
+	did := syntax.DID(DID)
+	apiClient := indigoclient.NewAPIClient(Host)
+	apiClient.Client = &http.Client{Transport: transport}
+	apiClient.AccountDID = &did
+	return client.NewWithAPIClient(Handle, apiClient)
+}
+
+// matches reports whether a recorded request answers the given request
+func matches(recorded RecordedRequest, method, urlPath string, query map[string]string) bool {
+	if !strings.EqualFold(recorded.Method, method) || recorded.Path != urlPath {
+		return false
+	}
+	for key, value := range recorded.Query {
+		if query[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// newResponse builds a JSON response for a replayed interaction
+func newResponse(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode:    status,
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
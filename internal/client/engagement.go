@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+)
+
+// getPostsLimit is the most URIs app.bsky.feed.getPosts accepts in one call
+const getPostsLimit = 25
+
+// Engagement is what other accounts have done with a post so far
+type Engagement struct {
+	Likes   int `json:"likes" dynamodbav:"likes"`
+	Reposts int `json:"reposts" dynamodbav:"reposts"`
+	Replies int `json:"replies" dynamodbav:"replies"`
+	Quotes  int `json:"quotes" dynamodbav:"quotes"`
+}
+
+// Total is the sum of every kind of engagement
+func (e Engagement) Total() int {
+	return e.Likes + e.Reposts + e.Replies + e.Quotes
+}
+
+// GetPostEngagement looks up the current engagement of posts by URI, such as the bot's own
+// summaries. Deleted posts are missing from the result.
+func (c *BlueskyClient) GetPostEngagement(ctx context.Context, uris []string) (map[string]Engagement, error) {
+	engagement := make(map[string]Engagement, len(uris))
+	for start := 0; start < len(uris); start += getPostsLimit {
+		end := min(start+getPostsLimit, len(uris))
+		result, err := bsky.FeedGetPosts(ctx, c.client, uris[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get posts %d-%d of %d: %w", start, end, len(uris), err)
+		}
+		for _, post := range result.Posts {
+			engagement[post.Uri] = Engagement{
+				Likes:   countOf(post.LikeCount),
+				Reposts: countOf(post.RepostCount),
+				Replies: countOf(post.ReplyCount),
+				Quotes:  countOf(post.QuoteCount),
+			}
+		}
+	}
+	return engagement, nil
+}
+
+func countOf(count *int64) int {
+	if count == nil {
+		return 0
+	}
+	return int(*count)
+}
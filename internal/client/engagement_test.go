@@ -0,0 +1,37 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/client/clienttest"
+)
+
+func TestGetPostEngagement(t *testing.T) {
+	// 30 URIs take two calls, the first with the first 25
+	uris := make([]string, 30)
+	for i := range uris {
+		uris[i] = fmt.Sprintf("at://did:plc:hourstats000000000000000/app.bsky.feed.post/%d", i)
+	}
+	first := feedPostJSON("0", "2025-01-05T12:30:00Z")
+	first["uri"] = uris[0]
+	first["quoteCount"] = 3
+	transport := clienttest.NewReplayTransport([]clienttest.Interaction{
+		interaction(t, "posts_page1", "/xrpc/app.bsky.feed.getPosts", map[string]string{"uris": uris[0]}, map[string]any{"posts": []any{first}}),
+		interaction(t, "posts_page2", "/xrpc/app.bsky.feed.getPosts", map[string]string{"uris": uris[25]}, map[string]any{"posts": []any{}}),
+	})
+
+	engagement, err := clienttest.NewClient(transport).GetPostEngagement(context.Background(), uris)
+	if err != nil {
+		t.Fatalf("GetPostEngagement() error = %v", err)
+	}
+	want := client.Engagement{Likes: 10, Reposts: 2, Replies: 1, Quotes: 3}
+	if len(engagement) != 1 || engagement[uris[0]] != want {
+		t.Errorf("GetPostEngagement() = %+v, want only %s with %+v", engagement, uris[0], want)
+	}
+	if total := want.Total(); total != 16 {
+		t.Errorf("Total() = %d, want 16", total)
+	}
+}
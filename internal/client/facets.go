@@ -3,16 +3,95 @@ package client
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
+
 	"github.com/bluesky-social/indigo/api/bsky"
 )
 
+// LinkSpec makes an occurrence of Substring in a post's text link to URL
+type LinkSpec struct {
+	Substring string
+	URL       string
+}
+
+// BuildLinkFacets creates link facets for each spec found in text
+// Facet ranges are UTF-8 byte offsets, as the PDS requires. Each spec links the first
+// occurrence of its substring that doesn't overlap an earlier spec's link, so repeating
+// a spec links successive occurrences. Specs whose substring is empty or not found are
+// skipped. Facets are returned in text order.
+func BuildLinkFacets(text string, specs []LinkSpec) []*bsky.RichtextFacet {
+	var facets []*bsky.RichtextFacet
+
+	for _, spec := range specs {
+		if spec.Substring == "" || spec.URL == "" {
+			continue
+		}
+
+		start, end, found := findUnlinked(text, spec.Substring, facets)
+		if !found {
+			continue
+		}
+
+		facets = append(facets, &bsky.RichtextFacet{
+			Index: &bsky.RichtextFacet_ByteSlice{
+				ByteStart: int64(start),
+				ByteEnd:   int64(end),
+			},
+			Features: []*bsky.RichtextFacet_Features_Elem{
+				{
+					RichtextFacet_Link: &bsky.RichtextFacet_Link{
+						Uri: spec.URL,
+					},
+				},
+			},
+		})
+	}
+
+	sort.Slice(facets, func(i, j int) bool {
+		return facets[i].Index.ByteStart < facets[j].Index.ByteStart
+	})
+	return facets
+}
+
+// findUnlinked returns the byte range of the first occurrence of substring in text that
+// doesn't overlap an existing facet
+func findUnlinked(text, substring string, facets []*bsky.RichtextFacet) (int, int, bool) {
+	for offset := 0; offset <= len(text)-len(substring); {
+		index := strings.Index(text[offset:], substring)
+		if index == -1 {
+			return 0, 0, false
+		}
+		start := offset + index
+		end := start + len(substring)
+
+		overlapping := false
+		for _, facet := range facets {
+			if int64(start) < facet.Index.ByteEnd && int64(end) > facet.Index.ByteStart {
+				overlapping = true
+				break
+			}
+		}
+		if !overlapping {
+			return start, end, true
+		}
+		offset = start + 1
+	}
+	return 0, 0, false
+}
+
 // CreateWikipediaLinkFacets creates facets for Wikipedia link text in the post
+func CreateWikipediaLinkFacets(text string) []*bsky.RichtextFacet {
+	return BuildLinkFacets(text, WikipediaLinkSpecs(text))
+}
+
+// WikipediaLinkSpecs links date phrases in the post to Wikipedia's current events portal
 // Looks for patterns like "Sep 18 events" or "Oct 10 events" and makes them clickable
 // The URLs are no longer in the text, so we match the date + "events" pattern directly
-func CreateWikipediaLinkFacets(text string) []*bsky.RichtextFacet {
-	var facets []*bsky.RichtextFacet
+func WikipediaLinkSpecs(text string) []LinkSpec {
+	var specs []LinkSpec
 
 	// Pattern to match: "Jan 2 events", "Sep 18 events", "Oct 10 events", etc.
 	// Matches month abbreviation (3 letters) + space + day (1-2 digits) + space + "events"
@@ -29,7 +108,7 @@ func CreateWikipediaLinkFacets(text string) []*bsky.RichtextFacet {
 
 		// Extract the matched text (e.g., "Sep 18 events")
 		matchedText := text[match[2]:match[3]]
-		
+
 		// Extract the date portion (e.g., "Sep 18")
 		datePortion := matchedText[:len(matchedText)-7] // Remove " events" (7 chars)
 
@@ -50,9 +129,9 @@ func CreateWikipediaLinkFacets(text string) []*bsky.RichtextFacet {
 			// Fallback to current year
 			year = time.Now().Year()
 		}
-		
+
 		dateStr := fmt.Sprintf("%s %d", datePortion, year)
-		
+
 		// Try to parse the date
 		date, err := time.Parse("Jan 2 2006", dateStr)
 		if err != nil {
@@ -66,26 +145,9 @@ func CreateWikipediaLinkFacets(text string) []*bsky.RichtextFacet {
 		wikiURL := fmt.Sprintf("https://en.wikipedia.org/wiki/Portal:Current_events/%s_%d#%d_%s_%d",
 			monthName, year, year, monthName, day)
 
-		// Create a link facet for the entire date + "events" phrase
-		facet := &bsky.RichtextFacet{
-			Index: &bsky.RichtextFacet_ByteSlice{
-				ByteStart: int64(match[2]),
-				ByteEnd:   int64(match[3]),
-			},
-			Features: []*bsky.RichtextFacet_Features_Elem{
-				{
-					RichtextFacet_Link: &bsky.RichtextFacet_Link{
-						Uri: wikiURL,
-					},
-				},
-			},
-		}
-
-		facets = append(facets, facet)
+		// Link the entire date + "events" phrase
+		specs = append(specs, LinkSpec{Substring: matchedText, URL: wikiURL})
 	}
 
-	// No fallback needed - we always try to match the date pattern
-
-	return facets
+	return specs
 }
-
@@ -0,0 +1,159 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/christophergentle/hourstats-bsky/internal/formatter"
+)
+
+func TestBuildLinkFacetsByteOffsets(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		substring string
+	}{
+		{"ascii", "See the events here", "events"},
+		{"at start", "events first", "events"},
+		{"at end", "last are events", "events"},
+		{"whole text", "events", "events"},
+		{"after accented latin", "Café crème: events", "events"},
+		{"after emoji", "📈📉 Sep 18 events", "Sep 18 events"},
+		{"after flag", "🇦🇺 news: Sep 18 events", "Sep 18 events"},
+		{"after ZWJ sequence", "👩‍💻 coded Sep 18 events", "Sep 18 events"},
+		{"after combining mark", "é then events", "events"},
+		{"after CJK", "日本語のニュース events", "events"},
+		{"multibyte substring", "Read über alles today", "über alles"},
+		{"emoji substring", "Mood 😀 today", "😀"},
+		{"CJK substring", "今日は晴れです", "晴れ"},
+		{"handle after emoji", "🔥 @alice.bsky.social posted", "@alice.bsky.social"},
+		{"four byte runes around", "𝄞 events 𝄞", "events"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			facets := BuildLinkFacets(tt.text, []LinkSpec{{Substring: tt.substring, URL: "https://example.com/"}})
+			if len(facets) != 1 {
+				t.Fatalf("expected 1 facet, got %d", len(facets))
+			}
+
+			start, end := facets[0].Index.ByteStart, facets[0].Index.ByteEnd
+			if got := tt.text[start:end]; got != tt.substring {
+				t.Errorf("facet covers %q, want %q", got, tt.substring)
+			}
+			if problem := facetProblem(tt.text, facets[0]); problem != "" {
+				t.Errorf("facet is invalid: %s", problem)
+			}
+			if facets[0].Features[0].RichtextFacet_Link.Uri != "https://example.com/" {
+				t.Errorf("unexpected link %q", facets[0].Features[0].RichtextFacet_Link.Uri)
+			}
+		})
+	}
+}
+
+func TestBuildLinkFacetsRepeatedAndOverlapping(t *testing.T) {
+	text := "🎉 Sep 18 events, then Sep 18 events again"
+
+	facets := BuildLinkFacets(text, []LinkSpec{
+		{Substring: "Sep 18 events", URL: "https://example.com/1"},
+		{Substring: "Sep 18 events", URL: "https://example.com/2"},
+		{Substring: "Sep 18 events", URL: "https://example.com/3"},
+	})
+	if len(facets) != 2 {
+		t.Fatalf("expected the third spec to find no unlinked occurrence, got %d facets", len(facets))
+	}
+	if facets[0].Index.ByteStart >= facets[1].Index.ByteStart {
+		t.Error("expected repeated specs to link successive occurrences")
+	}
+
+	// A spec overlapping an earlier link skips to a later occurrence
+	facets = BuildLinkFacets("alpha beta alpha", []LinkSpec{
+		{Substring: "alpha beta", URL: "https://example.com/1"},
+		{Substring: "beta", URL: "https://example.com/2"},
+		{Substring: "alpha", URL: "https://example.com/3"},
+	})
+	if len(facets) != 2 {
+		t.Fatalf("expected 2 facets, got %d", len(facets))
+	}
+	if facets[1].Index.ByteStart != 11 || facets[1].Index.ByteEnd != 16 {
+		t.Errorf("expected the second alpha at 11-16, got %d-%d", facets[1].Index.ByteStart, facets[1].Index.ByteEnd)
+	}
+}
+
+func TestBuildLinkFacetsOrderAndSkips(t *testing.T) {
+	text := "first ünïcode second"
+
+	facets := BuildLinkFacets(text, []LinkSpec{
+		{Substring: "second", URL: "https://example.com/2"},
+		{Substring: "missing", URL: "https://example.com/missing"},
+		{Substring: "", URL: "https://example.com/empty"},
+		{Substring: "first", URL: ""},
+		{Substring: "ünïcode", URL: "https://example.com/1"},
+	})
+	if len(facets) != 2 {
+		t.Fatalf("expected 2 facets, got %d", len(facets))
+	}
+	if text[facets[0].Index.ByteStart:facets[0].Index.ByteEnd] != "ünïcode" {
+		t.Errorf("expected facets in text order, first covers %q", text[facets[0].Index.ByteStart:facets[0].Index.ByteEnd])
+	}
+	if facets := BuildLinkFacets("", []LinkSpec{{Substring: "x", URL: "https://example.com/"}}); len(facets) != 0 {
+		t.Errorf("expected no facets for empty text, got %d", len(facets))
+	}
+}
+
+func TestCreateWikipediaLinkFacets(t *testing.T) {
+	text := "📊 Bluesky Sentiment 2024-01-01 - 2024-12-31\n🔺 Sep 18 events\n🔻 Mar 3 events"
+
+	facets := CreateWikipediaLinkFacets(text)
+	if len(facets) != 2 {
+		t.Fatalf("expected 2 facets, got %d", len(facets))
+	}
+
+	expected := []struct {
+		substring string
+		url       string
+	}{
+		{"Sep 18 events", "https://en.wikipedia.org/wiki/Portal:Current_events/September_2024#2024_September_18"},
+		{"Mar 3 events", "https://en.wikipedia.org/wiki/Portal:Current_events/March_2024#2024_March_3"},
+	}
+	for i, want := range expected {
+		facet := facets[i]
+		if got := text[facet.Index.ByteStart:facet.Index.ByteEnd]; got != want.substring {
+			t.Errorf("facet %d covers %q, want %q", i, got, want.substring)
+		}
+		if got := facet.Features[0].RichtextFacet_Link.Uri; got != want.url {
+			t.Errorf("facet %d links %q, want %q", i, got, want.url)
+		}
+	}
+}
+
+func TestCreateSummaryFacets(t *testing.T) {
+	text := "Bluesky is #calm\n1. @alice.bsky.social +\n2. @bob.test -\n#hourstats"
+	spans := []formatter.Span{
+		{Start: 11, End: 16, Tag: "calm"},
+		{Start: 20, End: 38, Handle: "alice.bsky.social", PostURI: "at://did:plc:alice/app.bsky.feed.post/1"},
+		{Start: 44, End: 53, Handle: "bob.test", PostURI: "at://post-123"}, // no DID to mention
+		{Start: 56, End: 66, Tag: "hourstats"},
+		{Start: 60, End: 90, Tag: "cut"},                                                    // truncated away
+		{Start: 0, End: 7, Handle: "someone", PostURI: "at://did:plc:x/app.bsky.feed.post"}, // text doesn't match
+	}
+
+	facets := createSummaryFacets(text, spans)
+	if len(facets) != 3 {
+		t.Fatalf("expected 3 facets, got %d", len(facets))
+	}
+
+	if tag := facets[0].Features[0].RichtextFacet_Tag; tag == nil || tag.Tag != "calm" {
+		t.Errorf("expected the mood tag first, got %+v", facets[0].Features[0])
+	}
+	if mention := facets[1].Features[0].RichtextFacet_Mention; mention == nil || mention.Did != "did:plc:alice" {
+		t.Errorf("expected a mention of did:plc:alice, got %+v", facets[1].Features[0])
+	}
+	if tag := facets[2].Features[0].RichtextFacet_Tag; tag == nil || tag.Tag != "hourstats" {
+		t.Errorf("expected the project tag last, got %+v", facets[2].Features[0])
+	}
+	for _, facet := range facets {
+		if problem := facetProblem(text, facet); problem != "" {
+			t.Errorf("facet is invalid: %s", problem)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+)
+
+// Feed kinds, taken from the collection of the feed's at:// URI
+const (
+	FeedKindGenerator = "feed"
+	FeedKindList      = "list"
+)
+
+// FeedSource is a custom feed or list the fetcher reads instead of the global search
+type FeedSource struct {
+	URI  string
+	Kind string
+	Name string
+}
+
+// Label names the source for the summary post, e.g. "Science feed"
+func (s FeedSource) Label() string {
+	name := s.Name
+	if name == "" {
+		name = s.URI
+	}
+	return name + " " + s.Kind
+}
+
+// FeedKind returns the kind of feed an at:// URI points to: a feed generator
+// (app.bsky.feed.generator) or a list (app.bsky.graph.list)
+func FeedKind(feedURI string) (string, error) {
+	uri, err := syntax.ParseATURI(feedURI)
+	if err != nil {
+		return "", fmt.Errorf("invalid feed URI %q: %w", feedURI, err)
+	}
+
+	switch uri.Collection().String() {
+	case "app.bsky.feed.generator":
+		return FeedKindGenerator, nil
+	case "app.bsky.graph.list":
+		return FeedKindList, nil
+	default:
+		return "", fmt.Errorf("feed URI %q is not a feed generator or list", feedURI)
+	}
+}
+
+// ResolveFeed looks up the display name of a feed generator or list
+func (c *BlueskyClient) ResolveFeed(ctx context.Context, feedURI string) (FeedSource, error) {
+	kind, err := FeedKind(feedURI)
+	if err != nil {
+		return FeedSource{}, err
+	}
+
+	source := FeedSource{URI: feedURI, Kind: kind}
+	switch kind {
+	case FeedKindGenerator:
+		result, err := bsky.FeedGetFeedGenerator(ctx, c.client, feedURI)
+		if err != nil {
+			return FeedSource{}, fmt.Errorf("failed to get feed generator: %w", err)
+		}
+		if result.View != nil {
+			source.Name = result.View.DisplayName
+		}
+	case FeedKindList:
+		result, err := bsky.GraphGetList(ctx, c.client, "", 1, feedURI)
+		if err != nil {
+			return FeedSource{}, fmt.Errorf("failed to get list: %w", err)
+		}
+		if result.List != nil {
+			source.Name = result.List.Name
+		}
+	}
+
+	return source, nil
+}
+
+// GetFeedPostsBatch fetches a single page of a feed generator or list, keeping posts
+// indexed after cutoffTime. Custom feeds are not always in time order, so paging only
+// stops once a whole page falls before the cutoff. Reposts are skipped so each post
+// counts once, at its own time.
+func (c *BlueskyClient) GetFeedPostsBatch(ctx context.Context, feedURI, cursor string, cutoffTime time.Time) ([]Post, string, bool, error) {
+	kind, err := FeedKind(feedURI)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	log.Printf("Fetching %s batch from %s with cursor: %s", kind, feedURI, cursor)
+
+	var items []*bsky.FeedDefs_FeedViewPost
+	var nextCursor *string
+	for retries := 0; retries < 3; retries++ {
+		if kind == FeedKindList {
+			var result *bsky.FeedGetListFeed_Output
+			result, err = bsky.FeedGetListFeed(ctx, c.client, cursor, 100, feedURI)
+			if err == nil {
+				items, nextCursor = result.Feed, result.Cursor
+			}
+		} else {
+			var result *bsky.FeedGetFeed_Output
+			result, err = bsky.FeedGetFeed(ctx, c.client, cursor, feedURI, 100)
+			if err == nil {
+				items, nextCursor = result.Feed, result.Cursor
+			}
+		}
+		if err == nil {
+			break
+		}
+
+		// Rate limits and gateway errors are retried; anything else fails the batch
+		if strings.Contains(err.Error(), "502") || strings.Contains(err.Error(), "rate") {
+			log.Printf("API rate limit hit, waiting 5 seconds before retry %d/3", retries+1)
+			if err := sleepContext(ctx, 5*time.Second); err != nil {
+				return nil, "", false, fmt.Errorf("%s fetch cancelled: %w", kind, err)
+			}
+			continue
+		}
+		return nil, "", false, fmt.Errorf("failed to get %s posts: %w", kind, err)
+	}
+	if err != nil {
+		return nil, "", false, fmt.Errorf("failed to get %s posts after 3 retries: %w", kind, err)
+	}
+
+	var posts []Post
+	inWindow := 0
+	for _, item := range items {
+		if item.Post == nil {
+			continue
+		}
+
+		postTime, err := time.Parse(time.RFC3339, item.Post.IndexedAt)
+		if err != nil || postTime.Before(cutoffTime) {
+			continue
+		}
+		inWindow++
+
+		if item.Reason != nil {
+			continue
+		}
+
+		if c.hasAdultContentLabel(labelerLabels(item.Post)) {
+			log.Printf("Filtering out post with adult content: %s", item.Post.Uri)
+			continue
+		}
+
+		posts = append(posts, convertPostView(item.Post, postTime))
+	}
+
+	hasMorePosts := nextCursor != nil && *nextCursor != "" && inWindow > 0
+	next := ""
+	if hasMorePosts {
+		next = *nextCursor
+	}
+
+	log.Printf("Retrieved %d posts from %s batch (cursor: %s, nextCursor: %s, hasMore: %v)", len(posts), kind, cursor, next, hasMorePosts)
+	return posts, next, hasMorePosts, nil
+}
@@ -0,0 +1,241 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/client/clienttest"
+)
+
+const scienceFeedURI = "at://did:plc:feedowner000000000000000/app.bsky.feed.generator/science"
+
+func feedPostJSON(rkey, indexedAt string) map[string]any {
+	return map[string]any{
+		"uri":         "at://did:plc:alice0000000000000000000/app.bsky.feed.post/" + rkey,
+		"cid":         "bafyreiamthap7f5ltwiyaonpd44qochkdyfdf7xnlrbunezi7yvvlhesma",
+		"author":      map[string]any{"did": "did:plc:alice0000000000000000000", "handle": "alice.bsky.social"},
+		"record":      map[string]any{"$type": "app.bsky.feed.post", "text": "New results on " + rkey, "createdAt": indexedAt},
+		"indexedAt":   indexedAt,
+		"likeCount":   10,
+		"repostCount": 2,
+		"replyCount":  1,
+	}
+}
+
+func interaction(t *testing.T, name, path string, query map[string]string, body any) clienttest.Interaction {
+	t.Helper()
+	raw, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("marshal %s: %v", name, err)
+	}
+	return clienttest.Interaction{
+		Name:     name,
+		Request:  clienttest.RecordedRequest{Method: "GET", Path: path, Query: query},
+		Response: clienttest.RecordedResponse{Status: 200, Body: raw},
+	}
+}
+
+func TestGetFeedPostsBatch(t *testing.T) {
+	repost := map[string]any{
+		"post":   feedPostJSON("reposted", "2025-01-05T12:20:00Z"),
+		"reason": map[string]any{"$type": "app.bsky.feed.defs#reasonRepost", "by": map[string]any{"did": "did:plc:bob000000000000000000000", "handle": "bob.bsky.social"}, "indexedAt": "2025-01-05T12:21:00Z"},
+	}
+	transport := clienttest.NewReplayTransport([]clienttest.Interaction{
+		interaction(t, "feed_page1", "/xrpc/app.bsky.feed.getFeed", map[string]string{"feed": scienceFeedURI, "cursor": ""}, map[string]any{
+			"cursor": "page2",
+			"feed": []any{
+				map[string]any{"post": feedPostJSON("fresh", "2025-01-05T12:30:00Z")},
+				repost,
+				map[string]any{"post": feedPostJSON("stale", "2025-01-05T11:00:00Z")},
+			},
+		}),
+		interaction(t, "feed_page2", "/xrpc/app.bsky.feed.getFeed", map[string]string{"cursor": "page2"}, map[string]any{
+			"cursor": "page3",
+			"feed":   []any{map[string]any{"post": feedPostJSON("older", "2025-01-05T10:00:00Z")}},
+		}),
+	})
+	bsky := clienttest.NewClient(transport)
+
+	posts, nextCursor, hasMore, err := bsky.GetFeedPostsBatch(context.Background(), scienceFeedURI, "", fixtureCutoff)
+	if err != nil {
+		t.Fatalf("GetFeedPostsBatch() page 1 error = %v", err)
+	}
+	// The repost and the post before the cutoff are dropped
+	if len(posts) != 1 || posts[0].Text != "New results on fresh" {
+		t.Fatalf("page 1 posts = %+v, want only the fresh post", posts)
+	}
+	if !hasMore || nextCursor != "page2" {
+		t.Errorf("page 1 hasMore = %v, cursor = %q, want more from page2", hasMore, nextCursor)
+	}
+
+	// A page entirely before the cutoff ends pagination even though the feed has a cursor
+	posts, nextCursor, hasMore, err = bsky.GetFeedPostsBatch(context.Background(), scienceFeedURI, nextCursor, fixtureCutoff)
+	if err != nil {
+		t.Fatalf("GetFeedPostsBatch() page 2 error = %v", err)
+	}
+	if len(posts) != 0 || hasMore || nextCursor != "" {
+		t.Errorf("page 2 = %d posts, hasMore %v, cursor %q, want an empty final page", len(posts), hasMore, nextCursor)
+	}
+}
+
+func TestResolveFeed(t *testing.T) {
+	transport := clienttest.NewReplayTransport([]clienttest.Interaction{
+		interaction(t, "feed_generator", "/xrpc/app.bsky.feed.getFeedGenerator", map[string]string{"feed": scienceFeedURI}, map[string]any{
+			"isOnline": true,
+			"isValid":  true,
+			"view": map[string]any{
+				"uri":         scienceFeedURI,
+				"cid":         "bafyreiamthap7f5ltwiyaonpd44qochkdyfdf7xnlrbunezi7yvvlhesma",
+				"did":         "did:web:feeds.example.com",
+				"creator":     map[string]any{"did": "did:plc:feedowner000000000000000", "handle": "feeds.example.com"},
+				"displayName": "Science",
+				"indexedAt":   "2025-01-01T00:00:00Z",
+			},
+		}),
+	})
+	bsky := clienttest.NewClient(transport)
+
+	source, err := bsky.ResolveFeed(context.Background(), scienceFeedURI)
+	if err != nil {
+		t.Fatalf("ResolveFeed() error = %v", err)
+	}
+	if source.Kind != client.FeedKindGenerator || source.Label() != "Science feed" {
+		t.Errorf("ResolveFeed() = %+v (label %q), want the Science feed", source, source.Label())
+	}
+}
+
+func TestFeedKind(t *testing.T) {
+	tests := []struct {
+		uri     string
+		want    string
+		wantErr bool
+	}{
+		{scienceFeedURI, client.FeedKindGenerator, false},
+		{"at://did:plc:feedowner000000000000000/app.bsky.graph.list/3kabc", client.FeedKindList, false},
+		{"at://did:plc:alice0000000000000000000/app.bsky.feed.post/3l1abcdef", "", true},
+		{"https://bsky.app/profile/feeds.example.com/feed/science", "", true},
+	}
+	for _, tt := range tests {
+		got, err := client.FeedKind(tt.uri)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("FeedKind(%q) = %q, %v, want %q (error %v)", tt.uri, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFeedPostsMediaKind(t *testing.T) {
+	withEmbed := func(rkey string, embed map[string]any) map[string]any {
+		post := feedPostJSON(rkey, "2025-01-05T12:30:00Z")
+		post["embed"] = embed
+		return map[string]any{"post": post}
+	}
+	transport := clienttest.NewReplayTransport([]clienttest.Interaction{
+		interaction(t, "feed_media", "/xrpc/app.bsky.feed.getFeed", map[string]string{"feed": scienceFeedURI}, map[string]any{
+			"feed": []any{
+				withEmbed("photo", map[string]any{
+					"$type":  "app.bsky.embed.images#view",
+					"images": []any{map[string]any{"thumb": "https://cdn.example.com/t.jpg", "fullsize": "https://cdn.example.com/f.jpg", "alt": " A cat asleep "}},
+				}),
+				withEmbed("link", map[string]any{
+					"$type":    "app.bsky.embed.external#view",
+					"external": map[string]any{"uri": "https://example.com", "title": "Example", "description": ""},
+				}),
+				withEmbed("quote", map[string]any{
+					"$type":  "app.bsky.embed.recordWithMedia#view",
+					"record": map[string]any{"record": map[string]any{"$type": "app.bsky.embed.record#viewNotFound", "uri": "at://did:plc:bob000000000000000000000/app.bsky.feed.post/gone", "notFound": true}},
+					"media":  map[string]any{"$type": "app.bsky.embed.video#view", "cid": "bafyreiamthap7f5ltwiyaonpd44qochkdyfdf7xnlrbunezi7yvvlhesma", "playlist": "https://video.example.com/p.m3u8"},
+				}),
+				map[string]any{"post": feedPostJSON("plain", "2025-01-05T12:30:00Z")},
+			},
+		}),
+	})
+	bsky := clienttest.NewClient(transport)
+
+	posts, _, _, err := bsky.GetFeedPostsBatch(context.Background(), scienceFeedURI, "", fixtureCutoff)
+	if err != nil {
+		t.Fatalf("GetFeedPostsBatch() error = %v", err)
+	}
+
+	want := []string{client.MediaImage, client.MediaExternal, client.MediaVideo, ""}
+	if len(posts) != len(want) {
+		t.Fatalf("got %d posts, want %d", len(posts), len(want))
+	}
+	for i, post := range posts {
+		if post.Media != want[i] {
+			t.Errorf("%s Media = %q, want %q", post.URI, post.Media, want[i])
+		}
+	}
+	if posts[1].LinkURL != "https://example.com" || posts[0].LinkURL != "" {
+		t.Errorf("LinkURL = %q and %q, want the link card's URL only", posts[1].LinkURL, posts[0].LinkURL)
+	}
+	if len(posts[0].Images) != 1 || posts[0].Images[0].URL != "https://cdn.example.com/f.jpg" || posts[0].Images[0].Alt != "A cat asleep" || posts[1].Images != nil {
+		t.Errorf("Images = %+v and %+v, want the photo's full-size image only", posts[0].Images, posts[1].Images)
+	}
+}
+
+func TestFeedPostsReplyRoot(t *testing.T) {
+	rootURI := "at://did:plc:bob000000000000000000000/app.bsky.feed.post/root"
+	reply := feedPostJSON("reply", "2025-01-05T12:30:00Z")
+	reply["record"] = map[string]any{
+		"$type": "app.bsky.feed.post", "text": "Agreed", "createdAt": "2025-01-05T12:30:00Z",
+		"reply": map[string]any{
+			"root":   map[string]any{"uri": rootURI, "cid": "bafyreiamthap7f5ltwiyaonpd44qochkdyfdf7xnlrbunezi7yvvlhesma"},
+			"parent": map[string]any{"uri": rootURI, "cid": "bafyreiamthap7f5ltwiyaonpd44qochkdyfdf7xnlrbunezi7yvvlhesma"},
+		},
+	}
+	transport := clienttest.NewReplayTransport([]clienttest.Interaction{
+		interaction(t, "feed_replies", "/xrpc/app.bsky.feed.getFeed", map[string]string{"feed": scienceFeedURI}, map[string]any{
+			"feed": []any{
+				map[string]any{"post": reply},
+				map[string]any{"post": feedPostJSON("root", "2025-01-05T12:30:00Z")},
+			},
+		}),
+	})
+	bsky := clienttest.NewClient(transport)
+
+	posts, _, _, err := bsky.GetFeedPostsBatch(context.Background(), scienceFeedURI, "", fixtureCutoff)
+	if err != nil {
+		t.Fatalf("GetFeedPostsBatch() error = %v", err)
+	}
+	if len(posts) != 2 {
+		t.Fatalf("got %d posts, want 2", len(posts))
+	}
+	if posts[0].ReplyRoot != rootURI || posts[1].ReplyRoot != "" {
+		t.Errorf("ReplyRoot = %q and %q, want the reply's root only", posts[0].ReplyRoot, posts[1].ReplyRoot)
+	}
+}
+
+func TestFeedPostsContentWarnings(t *testing.T) {
+	label := func(src, val string) map[string]any {
+		return map[string]any{"src": src, "uri": "at://did:plc:alice0000000000000000000/app.bsky.feed.post/x", "val": val, "cts": "2025-01-05T12:30:00Z"}
+	}
+	selfLabeled := feedPostJSON("warned", "2025-01-05T12:30:00Z")
+	selfLabeled["labels"] = []any{label("did:plc:alice0000000000000000000", "graphic-media"), label("did:plc:alice0000000000000000000", "!no-unauthenticated")}
+	moderated := feedPostJSON("moderated", "2025-01-05T12:30:00Z")
+	moderated["labels"] = []any{label("did:plc:ar7c4by46qjdydhdevvrndac", "porn")}
+
+	transport := clienttest.NewReplayTransport([]clienttest.Interaction{
+		interaction(t, "feed_labels", "/xrpc/app.bsky.feed.getFeed", map[string]string{"feed": scienceFeedURI}, map[string]any{
+			"feed": []any{
+				map[string]any{"post": selfLabeled},
+				map[string]any{"post": moderated},
+				map[string]any{"post": feedPostJSON("plain", "2025-01-05T12:30:00Z")},
+			},
+		}),
+	})
+	bsky := clienttest.NewClient(transport)
+
+	posts, _, _, err := bsky.GetFeedPostsBatch(context.Background(), scienceFeedURI, "", fixtureCutoff)
+	if err != nil {
+		t.Fatalf("GetFeedPostsBatch() error = %v", err)
+	}
+	// The post a labeler marked adult is dropped; the author's own warning is kept and recorded
+	if len(posts) != 2 {
+		t.Fatalf("got %d posts, want 2", len(posts))
+	}
+	if len(posts[0].ContentWarnings) != 1 || posts[0].ContentWarnings[0] != "graphic-media" || len(posts[1].ContentWarnings) != 0 {
+		t.Errorf("ContentWarnings = %v and %v, want graphic-media on the first post only", posts[0].ContentWarnings, posts[1].ContentWarnings)
+	}
+}
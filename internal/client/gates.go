@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/api/bsky"
+	"github.com/bluesky-social/indigo/lex/util"
+)
+
+// Reply rules for InteractionSettings.Replies
+const (
+	ReplyMentioned = "mentioned" // accounts mentioned in the post
+	ReplyFollowers = "followers" // accounts following the bot
+	ReplyFollowing = "following" // accounts the bot follows
+)
+
+// InteractionSettings limit who can reply to or quote a post, through threadgate and
+// postgate records stored alongside it. The zero value leaves the post open.
+type InteractionSettings struct {
+	// Replies lists who may reply, as ReplyMentioned, ReplyFollowers or ReplyFollowing;
+	// empty leaves replies open unless DisableReplies is set
+	Replies []string `json:"replies,omitempty"`
+	// ReplyLists are AT-URIs of lists whose members may also reply
+	ReplyLists []string `json:"replyLists,omitempty"`
+	// DisableReplies stops everyone replying; Replies and ReplyLists are ignored
+	DisableReplies bool `json:"disableReplies,omitempty"`
+	// DisableQuotes stops the post being quoted
+	DisableQuotes bool `json:"disableQuotes,omitempty"`
+}
+
+// GatesReplies reports whether the settings need a threadgate
+func (s InteractionSettings) GatesReplies() bool {
+	return s.DisableReplies || len(s.Replies) > 0 || len(s.ReplyLists) > 0
+}
+
+// IsZero reports whether the settings leave a post open to everyone
+func (s InteractionSettings) IsZero() bool {
+	return !s.GatesReplies() && !s.DisableQuotes
+}
+
+// Validate checks reply rules and list URIs
+func (s InteractionSettings) Validate() error {
+	for _, rule := range s.Replies {
+		switch rule {
+		case ReplyMentioned, ReplyFollowers, ReplyFollowing:
+		default:
+			return fmt.Errorf("unknown reply rule %q: want %s, %s or %s", rule, ReplyMentioned, ReplyFollowers, ReplyFollowing)
+		}
+	}
+	for _, list := range s.ReplyLists {
+		if !strings.HasPrefix(list, "at://") || !strings.Contains(list, "/app.bsky.graph.list/") {
+			return fmt.Errorf("reply list %q is not a list AT-URI", list)
+		}
+	}
+	return nil
+}
+
+// threadgate builds the threadgate record for postURI; an empty allow list blocks all replies
+func (s InteractionSettings) threadgate(postURI string, createdAt time.Time) *bsky.FeedThreadgate {
+	allow := []*bsky.FeedThreadgate_Allow_Elem{}
+	if !s.DisableReplies {
+		for _, rule := range s.Replies {
+			switch rule {
+			case ReplyMentioned:
+				allow = append(allow, &bsky.FeedThreadgate_Allow_Elem{FeedThreadgate_MentionRule: &bsky.FeedThreadgate_MentionRule{}})
+			case ReplyFollowers:
+				allow = append(allow, &bsky.FeedThreadgate_Allow_Elem{FeedThreadgate_FollowerRule: &bsky.FeedThreadgate_FollowerRule{}})
+			case ReplyFollowing:
+				allow = append(allow, &bsky.FeedThreadgate_Allow_Elem{FeedThreadgate_FollowingRule: &bsky.FeedThreadgate_FollowingRule{}})
+			}
+		}
+		for _, list := range s.ReplyLists {
+			allow = append(allow, &bsky.FeedThreadgate_Allow_Elem{FeedThreadgate_ListRule: &bsky.FeedThreadgate_ListRule{List: list}})
+		}
+	}
+
+	return &bsky.FeedThreadgate{
+		Post:      postURI,
+		Allow:     allow,
+		CreatedAt: createdAt.Format(time.RFC3339),
+	}
+}
+
+// postgate builds the postgate record for postURI that disables quoting
+func (s InteractionSettings) postgate(postURI string, createdAt time.Time) *bsky.FeedPostgate {
+	return &bsky.FeedPostgate{
+		Post: postURI,
+		EmbeddingRules: []*bsky.FeedPostgate_EmbeddingRules_Elem{
+			{FeedPostgate_DisableRule: &bsky.FeedPostgate_DisableRule{}},
+		},
+		CreatedAt: createdAt.Format(time.RFC3339),
+	}
+}
+
+// SetInteractionSettings gates replies and quotes on one of the bot's own root posts
+// Threadgates only apply to the root of a thread, so replies posted by the bot should not
+// be passed here. Zero settings do nothing.
+func (c *BlueskyClient) SetInteractionSettings(ctx context.Context, postURI string, settings InteractionSettings) error {
+	if settings.IsZero() {
+		return nil
+	}
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+
+	repo, rkey, err := splitPostURI(postURI)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if settings.GatesReplies() {
+		if err := c.createRecord(ctx, repo, "app.bsky.feed.threadgate", rkey, settings.threadgate(postURI, now)); err != nil {
+			return fmt.Errorf("failed to create threadgate: %w", err)
+		}
+	}
+	if settings.DisableQuotes {
+		if err := c.createRecord(ctx, repo, "app.bsky.feed.postgate", rkey, settings.postgate(postURI, now)); err != nil {
+			return fmt.Errorf("failed to create postgate: %w", err)
+		}
+	}
+
+	log.Printf("Set interaction settings on %s: %+v", postURI, settings)
+	return nil
+}
+
+// createRecord writes record to collection under rkey in repo
+// Gate records must share their post's record key, so the key is always given.
+func (c *BlueskyClient) createRecord(ctx context.Context, repo, collection, rkey string, record util.CBOR) error {
+	if c.client == nil {
+		return fmt.Errorf("client not authenticated")
+	}
+
+	_, err := atproto.RepoCreateRecord(ctx, c.client, &atproto.RepoCreateRecord_Input{
+		Repo:       repo,
+		Collection: collection,
+		Rkey:       &rkey,
+		Record:     &util.LexiconTypeDecoder{Val: record},
+	})
+	return err
+}
+
+// splitPostURI returns the repo DID and record key of an app.bsky.feed.post AT-URI
+func splitPostURI(uri string) (string, string, error) {
+	parts := strings.Split(strings.TrimPrefix(uri, "at://"), "/")
+	if !strings.HasPrefix(uri, "at://") || len(parts) != 3 || parts[1] != "app.bsky.feed.post" || parts[0] == "" || parts[2] == "" {
+		return "", "", fmt.Errorf("%q is not a post AT-URI", uri)
+	}
+	return parts[0], parts[2], nil
+}
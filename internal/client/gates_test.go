@@ -0,0 +1,65 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInteractionSettingsThreadgate(t *testing.T) {
+	const uri = "at://did:plc:bot/app.bsky.feed.post/abc"
+	list := "at://did:plc:bot/app.bsky.graph.list/friends"
+	now := time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC)
+
+	settings := InteractionSettings{Replies: []string{ReplyMentioned, ReplyFollowers, ReplyFollowing}, ReplyLists: []string{list}}
+	gate := settings.threadgate(uri, now)
+	if gate.Post != uri || gate.CreatedAt != "2025-01-05T12:00:00Z" {
+		t.Errorf("unexpected threadgate %+v", gate)
+	}
+	if len(gate.Allow) != 4 {
+		t.Fatalf("expected 4 allow rules, got %d", len(gate.Allow))
+	}
+	if gate.Allow[0].FeedThreadgate_MentionRule == nil || gate.Allow[1].FeedThreadgate_FollowerRule == nil ||
+		gate.Allow[2].FeedThreadgate_FollowingRule == nil || gate.Allow[3].FeedThreadgate_ListRule.List != list {
+		t.Errorf("allow rules out of order: %+v", gate.Allow)
+	}
+
+	// Disabling replies ignores the allow rules and leaves an empty (non-nil) allow list
+	settings.DisableReplies = true
+	gate = settings.threadgate(uri, now)
+	if gate.Allow == nil || len(gate.Allow) != 0 {
+		t.Errorf("expected an empty allow list, got %+v", gate.Allow)
+	}
+
+	postgate := InteractionSettings{DisableQuotes: true}.postgate(uri, now)
+	if len(postgate.EmbeddingRules) != 1 || postgate.EmbeddingRules[0].FeedPostgate_DisableRule == nil {
+		t.Errorf("expected a single disable rule, got %+v", postgate.EmbeddingRules)
+	}
+}
+
+func TestInteractionSettingsValidate(t *testing.T) {
+	if err := (InteractionSettings{Replies: []string{"anyone"}}).Validate(); err == nil {
+		t.Error("expected an error for an unknown reply rule")
+	}
+	if err := (InteractionSettings{ReplyLists: []string{"at://did:plc:bot/app.bsky.feed.post/abc"}}).Validate(); err == nil {
+		t.Error("expected an error for a non-list reply list")
+	}
+	if err := (InteractionSettings{Replies: []string{ReplyFollowers}}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if !(InteractionSettings{}).IsZero() || (InteractionSettings{DisableQuotes: true}).IsZero() {
+		t.Error("IsZero reports the wrong result")
+	}
+}
+
+func TestSplitPostURI(t *testing.T) {
+	repo, rkey, err := splitPostURI("at://did:plc:bot/app.bsky.feed.post/3kabc")
+	if err != nil || repo != "did:plc:bot" || rkey != "3kabc" {
+		t.Errorf("splitPostURI() = %q, %q, %v", repo, rkey, err)
+	}
+
+	for _, uri := range []string{"", "at://post-123", "at://did:plc:bot/app.bsky.graph.list/1", "https://bsky.app/profile/x/post/1", "at://did:plc:bot/app.bsky.feed.post/"} {
+		if _, _, err := splitPostURI(uri); err == nil {
+			t.Errorf("splitPostURI(%q) expected an error", uri)
+		}
+	}
+}
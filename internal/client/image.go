@@ -0,0 +1,159 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"log"
+	"strings"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // registers the WebP decoder
+)
+
+// ImageConfig controls how images are shrunk to fit the upload budget
+type ImageConfig struct {
+	// MaxBytes is the byte budget an uploaded image must fit in; capped at MaxImageBytes
+	MaxBytes int `json:"maxBytes,omitempty"`
+	// MaxDimension is the longest side in pixels; larger images are downscaled first
+	MaxDimension int `json:"maxDimension,omitempty"`
+	// JPEGQuality is the first quality tried when PNG can't meet the budget
+	JPEGQuality int `json:"jpegQuality,omitempty"`
+	// MinJPEGQuality is the lowest quality tried before the image is downscaled further
+	MinJPEGQuality int `json:"minJpegQuality,omitempty"`
+}
+
+// DefaultImageConfig keeps images just under the blob limit at a size Bluesky displays
+// without further scaling
+func DefaultImageConfig() ImageConfig {
+	return ImageConfig{
+		MaxBytes:       950_000,
+		MaxDimension:   2000,
+		JPEGQuality:    90,
+		MinJPEGQuality: 60,
+	}
+}
+
+// ParseImageConfig decodes a JSON image config; omitted or invalid fields keep their defaults
+func ParseImageConfig(value string) (ImageConfig, error) {
+	config := DefaultImageConfig()
+	if strings.TrimSpace(value) == "" {
+		return config, nil
+	}
+	if err := json.Unmarshal([]byte(value), &config); err != nil {
+		return DefaultImageConfig(), fmt.Errorf("invalid image config JSON: %w", err)
+	}
+	return config.withDefaults(), nil
+}
+
+// withDefaults replaces out of range settings with the defaults
+func (c ImageConfig) withDefaults() ImageConfig {
+	defaults := DefaultImageConfig()
+	if c.MaxBytes <= 0 || c.MaxBytes > MaxImageBytes {
+		c.MaxBytes = defaults.MaxBytes
+	}
+	if c.MaxDimension <= 0 {
+		c.MaxDimension = defaults.MaxDimension
+	}
+	if c.JPEGQuality < 1 || c.JPEGQuality > 100 {
+		c.JPEGQuality = defaults.JPEGQuality
+	}
+	if c.MinJPEGQuality < 1 || c.MinJPEGQuality > c.JPEGQuality {
+		c.MinJPEGQuality = min(defaults.MinJPEGQuality, c.JPEGQuality)
+	}
+	return c
+}
+
+// PrepareImage fits an image into the config's byte budget and dimensions
+// Images that already fit are returned unchanged. Otherwise the image is downscaled to
+// MaxDimension and re-encoded as PNG, which keeps chart text sharp; if that is still too
+// large it is encoded as JPEG at decreasing quality, then downscaled further.
+func PrepareImage(imageData []byte, config ImageConfig) ([]byte, error) {
+	config = config.withDefaults()
+
+	decoded, format, err := image.Decode(bytes.NewReader(imageData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := decoded.Bounds()
+	if len(imageData) <= config.MaxBytes && max(bounds.Dx(), bounds.Dy()) <= config.MaxDimension && imageProblem(imageData) == "" {
+		return imageData, nil
+	}
+
+	img := downscale(decoded, config.MaxDimension)
+	for attempt := 0; attempt < 5; attempt++ {
+		var buf bytes.Buffer
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+		if err := encoder.Encode(&buf, img); err != nil {
+			return nil, fmt.Errorf("failed to encode PNG: %w", err)
+		}
+		if buf.Len() <= config.MaxBytes {
+			logResize(format, len(imageData), bounds, "png", buf.Len(), img.Bounds())
+			return buf.Bytes(), nil
+		}
+
+		// JPEG has no alpha channel, so flatten onto white first
+		opaque := flatten(img)
+		for quality := config.JPEGQuality; quality >= config.MinJPEGQuality; quality -= 10 {
+			buf.Reset()
+			if err := jpeg.Encode(&buf, opaque, &jpeg.Options{Quality: quality}); err != nil {
+				return nil, fmt.Errorf("failed to encode JPEG: %w", err)
+			}
+			if buf.Len() <= config.MaxBytes {
+				logResize(format, len(imageData), bounds, fmt.Sprintf("jpeg q%d", quality), buf.Len(), img.Bounds())
+				return buf.Bytes(), nil
+			}
+		}
+
+		longest := max(img.Bounds().Dx(), img.Bounds().Dy())
+		img = downscale(img, longest*3/4)
+	}
+
+	return nil, fmt.Errorf("could not fit %d byte image into %d bytes", len(imageData), config.MaxBytes)
+}
+
+// fitImage applies the default image config before upload, returning the original data
+// if it can't be processed so validation reports the problem
+func fitImage(imageData []byte) []byte {
+	prepared, err := PrepareImage(imageData, DefaultImageConfig())
+	if err != nil {
+		log.Printf("⚠️ Could not prepare image for upload: %v", err)
+		return imageData
+	}
+	return prepared
+}
+
+// downscale resizes img so its longest side is at most maxDimension, keeping the aspect ratio
+func downscale(img image.Image, maxDimension int) image.Image {
+	bounds := img.Bounds()
+	longest := max(bounds.Dx(), bounds.Dy())
+	if longest <= maxDimension {
+		return img
+	}
+
+	width := max(1, bounds.Dx()*maxDimension/longest)
+	height := max(1, bounds.Dy()*maxDimension/longest)
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), img, bounds, draw.Src, nil)
+	return scaled
+}
+
+// flatten draws img over a white background, removing transparency
+func flatten(img image.Image) image.Image {
+	bounds := img.Bounds()
+	opaque := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(opaque, opaque.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(opaque, opaque.Bounds(), img, bounds.Min, draw.Over)
+	return opaque
+}
+
+func logResize(fromFormat string, fromBytes int, fromBounds image.Rectangle, toFormat string, toBytes int, toBounds image.Rectangle) {
+	log.Printf("🖼️ Resized %s image %dx%d (%d bytes) to %s %dx%d (%d bytes)",
+		fromFormat, fromBounds.Dx(), fromBounds.Dy(), fromBytes, toFormat, toBounds.Dx(), toBounds.Dy(), toBytes)
+}
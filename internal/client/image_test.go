@@ -0,0 +1,97 @@
+package client
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand/v2"
+	"testing"
+)
+
+// noisyPNG encodes an image that compresses poorly, so its size tracks its dimensions
+func noisyPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	rng := rand.New(rand.NewPCG(1, 2))
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(rng.IntN(256)), uint8(rng.IntN(256)), uint8(rng.IntN(256)), 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestPrepareImageKeepsImagesThatFit(t *testing.T) {
+	data := noisyPNG(t, 40, 30)
+	prepared, err := PrepareImage(data, DefaultImageConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(prepared, data) {
+		t.Error("expected an image within budget to be returned unchanged")
+	}
+}
+
+func TestPrepareImageDownscalesLargeDimensions(t *testing.T) {
+	data := noisyPNG(t, 400, 200)
+	prepared, err := PrepareImage(data, ImageConfig{MaxDimension: 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(prepared))
+	if err != nil {
+		t.Fatalf("prepared image does not decode: %v", err)
+	}
+	if decoded.Bounds().Dx() != 100 || decoded.Bounds().Dy() != 50 {
+		t.Errorf("expected 100x50 keeping the aspect ratio, got %v", decoded.Bounds().Size())
+	}
+}
+
+func TestPrepareImageMeetsByteBudget(t *testing.T) {
+	data := noisyPNG(t, 300, 300)
+	budget := len(data) / 4
+
+	prepared, err := PrepareImage(data, ImageConfig{MaxBytes: budget, MaxDimension: 2000})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prepared) > budget {
+		t.Errorf("expected at most %d bytes, got %d", budget, len(prepared))
+	}
+	if err := ValidateImage(prepared); err != nil {
+		t.Errorf("expected the prepared image to be uploadable: %v", err)
+	}
+}
+
+func TestPrepareImageRejectsUndecodableData(t *testing.T) {
+	if _, err := PrepareImage([]byte("not an image"), DefaultImageConfig()); err == nil {
+		t.Error("expected an error for data that isn't an image")
+	}
+}
+
+func TestParseImageConfig(t *testing.T) {
+	config, err := ParseImageConfig(`{"maxBytes": 500000, "jpegQuality": 150, "minJpegQuality": 70}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defaults := DefaultImageConfig()
+	if config.MaxBytes != 500000 || config.MaxDimension != defaults.MaxDimension {
+		t.Errorf("expected the byte budget set and the default dimension kept, got %+v", config)
+	}
+	if config.JPEGQuality != defaults.JPEGQuality || config.MinJPEGQuality != 70 {
+		t.Errorf("expected an out of range quality replaced by the default, got %+v", config)
+	}
+
+	if config, _ := ParseImageConfig(`{"maxBytes": 5000000}`); config.MaxBytes != defaults.MaxBytes {
+		t.Errorf("expected a budget over the blob limit to be capped, got %d", config.MaxBytes)
+	}
+	if _, err := ParseImageConfig(`{`); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
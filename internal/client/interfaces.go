@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+	"github.com/christophergentle/hourstats-bsky/internal/formatter"
+)
+
+// BskyFetcher is the read side of the Bluesky client, used to collect posts for analysis
+type BskyFetcher interface {
+	Authenticate() error
+	GetTrendingPostsBatch(ctx context.Context, cursor string, cutoffTime time.Time) ([]Post, string, bool, error)
+	GetTrendingPosts(analysisIntervalMinutes int) ([]Post, error)
+	SearchPostsBatch(ctx context.Context, query, cursor string, cutoffTime time.Time) ([]Post, string, bool, error)
+	GetFeedPostsBatch(ctx context.Context, feedURI, cursor string, cutoffTime time.Time) ([]Post, string, bool, error)
+	ResolveFeed(ctx context.Context, feedURI string) (FeedSource, error)
+	GetPostEngagement(ctx context.Context, uris []string) (map[string]Engagement, error)
+	GetOwnPosts(ctx context.Context, since time.Time) ([]OwnPost, error)
+	SetSearchUntil(until time.Time)
+}
+
+// BskyPoster is the write side of the Bluesky client, used to publish summaries and charts
+type BskyPoster interface {
+	Authenticate() error
+	SetSummaryLayout(layout formatter.Layout)
+	PostTrendingSummary(posts []Post, overallSentiment string, analysisIntervalMinutes int, totalPosts int, netSentimentPercentage float64, notes ...string) (string, string, error)
+	PostTrendingSummaryWithImage(posts []Post, overallSentiment string, analysisIntervalMinutes int, totalPosts int, netSentimentPercentage float64, imageData []byte, altText string, notes ...string) (string, string, error)
+	PostText(ctx context.Context, text string) error
+	PostWithFacets(ctx context.Context, text string, facets []*bsky.RichtextFacet) error
+	UploadImage(ctx context.Context, imageData []byte, altText string) (*bsky.EmbedImages_Image, error)
+	PostWithImage(ctx context.Context, text string, imageData []byte, altText string, facets ...[]*bsky.RichtextFacet) (string, string, error)
+	PostWithImageAsReply(ctx context.Context, text string, imageData []byte, altText string, replyToURI, replyToCID string) (string, string, error)
+	PostTextAsReply(ctx context.Context, text string, rootURI, rootCID, parentURI, parentCID string) (string, string, error)
+	PinPost(ctx context.Context, postURI string, postCID string) error
+	UnpinPost(ctx context.Context) error
+	SetInteractionSettings(ctx context.Context, postURI string, settings InteractionSettings) error
+	SendDirectMessage(ctx context.Context, recipient, text string) error
+}
+
+// Client is the full Bluesky client, satisfied by *BlueskyClient and clienttest.MockClient
+type Client interface {
+	BskyFetcher
+	BskyPoster
+}
+
+// Factory creates an unauthenticated client for the given credentials
+// Handlers that read credentials per invocation hold a Factory so tests can substitute a mock
+type Factory func(handle, password string) Client
+
+// NewClient is the default Factory, returning a live BlueskyClient
+func NewClient(handle, password string) Client {
+	return New(handle, password)
+}
+
+var _ Client = (*BlueskyClient)(nil)
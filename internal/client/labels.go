@@ -0,0 +1,43 @@
+package client
+
+import (
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/api/bsky"
+)
+
+// noUnauthenticatedLabel asks apps to hide the author's posts from logged-out viewers;
+// it is a self-label but not a content warning
+const noUnauthenticatedLabel = "!no-unauthenticated"
+
+// isSelfLabel reports whether the post's author applied the label to their own post
+func isSelfLabel(postView *bsky.FeedDefs_PostView, label *atproto.LabelDefs_Label) bool {
+	return postView.Author != nil && postView.Author.Did != "" && label.Src == postView.Author.Did
+}
+
+// labelerLabels returns the labels moderation services applied to a post, leaving out
+// the content warnings its author applied
+func labelerLabels(postView *bsky.FeedDefs_PostView) []*atproto.LabelDefs_Label {
+	var labels []*atproto.LabelDefs_Label
+	for _, label := range postView.Labels {
+		if label != nil && !isSelfLabel(postView, label) {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// contentWarnings returns the content warnings a post's author applied to it, e.g.
+// "graphic-media", in the order they were applied
+func contentWarnings(postView *bsky.FeedDefs_PostView) []string {
+	var warnings []string
+	for _, label := range postView.Labels {
+		if label == nil || !isSelfLabel(postView, label) || label.Val == noUnauthenticatedLabel {
+			continue
+		}
+		if label.Neg != nil && *label.Neg {
+			continue
+		}
+		warnings = append(warnings, label.Val)
+	}
+	return warnings
+}
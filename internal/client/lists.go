@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+)
+
+// MaxListMembers bounds how many members GetListMembers pages through, so one huge
+// list can't stall a run
+const MaxListMembers = 10000
+
+// ListMember is an account on a list
+type ListMember struct {
+	DID    string
+	Handle string
+}
+
+// GetListMembers returns a list's name and members, e.g. a moderation list's blocked
+// accounts, up to MaxListMembers
+func (c *BlueskyClient) GetListMembers(ctx context.Context, listURI string) (string, []ListMember, error) {
+	kind, err := FeedKind(listURI)
+	if err != nil {
+		return "", nil, err
+	}
+	if kind != FeedKindList {
+		return "", nil, fmt.Errorf("%q is not a list", listURI)
+	}
+
+	var name string
+	var members []ListMember
+	cursor := ""
+	for len(members) < MaxListMembers {
+		result, err := bsky.GraphGetList(ctx, c.client, cursor, 100, listURI)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get list members: %w", err)
+		}
+		if result.List != nil {
+			name = result.List.Name
+		}
+		for _, item := range result.Items {
+			if item.Subject == nil || item.Subject.Did == "" {
+				continue
+			}
+			members = append(members, ListMember{DID: item.Subject.Did, Handle: item.Subject.Handle})
+		}
+
+		if result.Cursor == nil || *result.Cursor == "" || len(result.Items) == 0 {
+			return name, members, nil
+		}
+		cursor = *result.Cursor
+	}
+	return name, members[:MaxListMembers], nil
+}
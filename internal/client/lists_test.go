@@ -0,0 +1,52 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christophergentle/hourstats-bsky/internal/client/clienttest"
+)
+
+const spamListURI = "at://did:plc:modowner00000000000000000/app.bsky.graph.list/spam"
+
+func listItemJSON(did, handle string) map[string]any {
+	return map[string]any{
+		"uri":     "at://did:plc:modowner00000000000000000/app.bsky.graph.listitem/" + handle,
+		"subject": map[string]any{"did": did, "handle": handle},
+	}
+}
+
+func TestGetListMembers(t *testing.T) {
+	list := map[string]any{
+		"uri":       spamListURI,
+		"cid":       "bafyreiamthap7f5ltwiyaonpd44qochkdyfdf7xnlrbunezi7yvvlhesma",
+		"creator":   map[string]any{"did": "did:plc:modowner00000000000000000", "handle": "mods.example.com"},
+		"name":      "Spam rings",
+		"purpose":   "app.bsky.graph.defs#modlist",
+		"indexedAt": "2025-01-01T00:00:00Z",
+	}
+	transport := clienttest.NewReplayTransport([]clienttest.Interaction{
+		interaction(t, "list_page1", "/xrpc/app.bsky.graph.getList", map[string]string{"list": spamListURI, "cursor": ""}, map[string]any{
+			"cursor": "page2",
+			"list":   list,
+			"items":  []any{listItemJSON("did:plc:spam1000000000000000000000", "spam1.bsky.social")},
+		}),
+		interaction(t, "list_page2", "/xrpc/app.bsky.graph.getList", map[string]string{"cursor": "page2"}, map[string]any{
+			"list":  list,
+			"items": []any{listItemJSON("did:plc:spam2000000000000000000000", "spam2.bsky.social")},
+		}),
+	})
+	bsky := clienttest.NewClient(transport)
+
+	name, members, err := bsky.GetListMembers(context.Background(), spamListURI)
+	if err != nil {
+		t.Fatalf("GetListMembers() error = %v", err)
+	}
+	if name != "Spam rings" || len(members) != 2 || members[1].DID != "did:plc:spam2000000000000000000000" || members[0].Handle != "spam1.bsky.social" {
+		t.Errorf("GetListMembers() = %q, %+v, want both pages of Spam rings", name, members)
+	}
+
+	if _, _, err := bsky.GetListMembers(context.Background(), scienceFeedURI); err == nil {
+		t.Error("Expected an error for a feed generator URI")
+	}
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+)
+
+// Kinds of post the bot makes, told apart by their shape so engagement can be compared
+// between formats
+const (
+	OwnPostKindImage = "image" // summaries and charts with an image attached
+	OwnPostKindText  = "text"  // text-only posts such as the reports
+	OwnPostKindReply = "reply" // follow-ups posted in a thread
+)
+
+// authorFeedPageSize is the most posts app.bsky.feed.getAuthorFeed returns per page
+const authorFeedPageSize = 100
+
+// OwnPost is one of the bot's own posts with the engagement it has received so far
+type OwnPost struct {
+	URI        string
+	CID        string
+	Text       string
+	Kind       string
+	CreatedAt  time.Time
+	Engagement Engagement
+}
+
+// GetOwnPosts returns the bot's posts created since the given time, newest first. Reposts
+// are skipped, since their engagement belongs to the original author.
+func (c *BlueskyClient) GetOwnPosts(ctx context.Context, since time.Time) ([]OwnPost, error) {
+	actor := strings.Trim(c.handle, `"`)
+
+	var posts []OwnPost
+	cursor := ""
+	for {
+		result, err := bsky.FeedGetAuthorFeed(ctx, c.client, actor, cursor, "posts_with_replies", false, authorFeedPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get author feed for %s: %w", actor, err)
+		}
+
+		reachedSince := false
+		for _, item := range result.Feed {
+			if item.Post == nil || item.Post.Record == nil || item.Reason != nil {
+				continue
+			}
+			feedPost, ok := item.Post.Record.Val.(*bsky.FeedPost)
+			if !ok {
+				continue
+			}
+			createdAt, err := time.Parse(time.RFC3339, feedPost.CreatedAt)
+			if err != nil {
+				continue
+			}
+			if createdAt.Before(since) {
+				reachedSince = true
+				continue
+			}
+
+			posts = append(posts, OwnPost{
+				URI:       item.Post.Uri,
+				CID:       item.Post.Cid,
+				Text:      feedPost.Text,
+				Kind:      ownPostKind(feedPost, item.Post.Embed),
+				CreatedAt: createdAt,
+				Engagement: Engagement{
+					Likes:   countOf(item.Post.LikeCount),
+					Reposts: countOf(item.Post.RepostCount),
+					Replies: countOf(item.Post.ReplyCount),
+					Quotes:  countOf(item.Post.QuoteCount),
+				},
+			})
+		}
+
+		// The author feed is in reverse time order, so the first older post ends the walk
+		if reachedSince || result.Cursor == nil || *result.Cursor == "" {
+			break
+		}
+		cursor = *result.Cursor
+	}
+	return posts, nil
+}
+
+func ownPostKind(record *bsky.FeedPost, embed *bsky.FeedDefs_PostView_Embed) string {
+	if record.Reply != nil {
+		return OwnPostKindReply
+	}
+	if embed != nil && (embed.EmbedImages_View != nil ||
+		(embed.EmbedRecordWithMedia_View != nil && embed.EmbedRecordWithMedia_View.Media != nil &&
+			embed.EmbedRecordWithMedia_View.Media.EmbedImages_View != nil)) {
+		return OwnPostKindImage
+	}
+	return OwnPostKindText
+}
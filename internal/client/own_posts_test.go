@@ -0,0 +1,62 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/client/clienttest"
+)
+
+func TestGetOwnPosts(t *testing.T) {
+	withImage := feedPostJSON("summary", "2025-01-05T12:30:00Z")
+	withImage["embed"] = map[string]any{
+		"$type":  "app.bsky.embed.images#view",
+		"images": []any{map[string]any{"thumb": "https://cdn.example.com/t.png", "fullsize": "https://cdn.example.com/f.png", "alt": "chart"}},
+	}
+	ref := map[string]any{"uri": withImage["uri"], "cid": withImage["cid"]}
+	reply := feedPostJSON("reply", "2025-01-05T12:00:00Z")
+	reply["record"].(map[string]any)["reply"] = map[string]any{"root": ref, "parent": ref}
+	repost := map[string]any{
+		"post":   feedPostJSON("reposted", "2025-01-05T11:30:00Z"),
+		"reason": map[string]any{"$type": "app.bsky.feed.defs#reasonRepost", "by": map[string]any{"did": clienttest.DID, "handle": clienttest.Handle}, "indexedAt": "2025-01-05T11:31:00Z"},
+	}
+
+	transport := clienttest.NewReplayTransport([]clienttest.Interaction{
+		interaction(t, "author_page1", "/xrpc/app.bsky.feed.getAuthorFeed", map[string]string{"actor": clienttest.Handle, "cursor": ""}, map[string]any{
+			"cursor": "page2",
+			"feed":   []any{map[string]any{"post": withImage}, map[string]any{"post": reply}, repost},
+		}),
+		interaction(t, "author_page2", "/xrpc/app.bsky.feed.getAuthorFeed", map[string]string{"cursor": "page2"}, map[string]any{
+			"cursor": "page3",
+			"feed": []any{
+				map[string]any{"post": feedPostJSON("report", "2025-01-05T10:00:00Z")},
+				map[string]any{"post": feedPostJSON("old", "2025-01-04T10:00:00Z")},
+			},
+		}),
+	})
+
+	since := time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)
+	posts, err := clienttest.NewClient(transport).GetOwnPosts(context.Background(), since)
+	if err != nil {
+		t.Fatalf("GetOwnPosts() error = %v", err)
+	}
+
+	// The repost is skipped and the post before since ends paging before page3
+	wantKinds := []string{client.OwnPostKindImage, client.OwnPostKindReply, client.OwnPostKindText}
+	if len(posts) != len(wantKinds) {
+		t.Fatalf("GetOwnPosts() = %d posts, want %d: %+v", len(posts), len(wantKinds), posts)
+	}
+	for i, kind := range wantKinds {
+		if posts[i].Kind != kind {
+			t.Errorf("posts[%d].Kind = %q, want %q", i, posts[i].Kind, kind)
+		}
+	}
+	if want := (client.Engagement{Likes: 10, Reposts: 2, Replies: 1}); posts[0].Engagement != want {
+		t.Errorf("posts[0].Engagement = %+v, want %+v", posts[0].Engagement, want)
+	}
+	if unmatched := transport.Unmatched(); len(unmatched) > 0 {
+		t.Errorf("unexpected requests: %+v", unmatched)
+	}
+}
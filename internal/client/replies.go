@@ -0,0 +1,18 @@
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// PostReplyChain posts texts as a chain of replies under parent, each replying to the one before
+func PostReplyChain(ctx context.Context, poster BskyPoster, rootURI, rootCID, parentURI, parentCID string, texts []string) error {
+	for i, text := range texts {
+		uri, cid, err := poster.PostTextAsReply(ctx, text, rootURI, rootCID, parentURI, parentCID)
+		if err != nil {
+			return fmt.Errorf("reply %d/%d: %w", i+1, len(texts), err)
+		}
+		parentURI, parentCID = uri, cid
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GlobalSearchQuery matches every public post; it is the default when no queries are configured
+const GlobalSearchQuery = "*"
+
+// ParseSearchQueries splits a comma-separated list of search queries, dropping blanks and repeats
+func ParseSearchQueries(value string) []string {
+	var queries []string
+	seen := make(map[string]bool)
+	for _, query := range strings.Split(value, ",") {
+		query = strings.TrimSpace(query)
+		if query == "" || seen[query] {
+			continue
+		}
+		seen[query] = true
+		queries = append(queries, query)
+	}
+	return queries
+}
+
+// MultiSearch pages through several search queries one after another as a single
+// stream, so posts matching any of the queries are collected once.
+//
+// Its cursors have the form "q<index>:<search cursor>", letting a checkpointed fetch
+// resume in the middle of a later query. Duplicates are only tracked within one
+// MultiSearch, so a resumed fetch may store a post again; the processor deduplicates.
+type MultiSearch struct {
+	fetcher BskyFetcher
+	queries []string
+	seen    map[string]bool
+}
+
+// NewMultiSearch creates a stream over queries, which must not be empty
+func NewMultiSearch(fetcher BskyFetcher, queries []string) *MultiSearch {
+	return &MultiSearch{
+		fetcher: fetcher,
+		queries: queries,
+		seen:    make(map[string]bool),
+	}
+}
+
+// Batch fetches the next page; its signature matches GetTrendingPostsBatch
+func (m *MultiSearch) Batch(ctx context.Context, cursor string, cutoffTime time.Time) ([]Post, string, bool, error) {
+	index, searchCursor, err := parseMultiSearchCursor(cursor, len(m.queries))
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	query := m.queries[index]
+	posts, nextCursor, hasMore, err := m.fetcher.SearchPostsBatch(ctx, query, searchCursor, cutoffTime)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("query %q: %w", query, err)
+	}
+
+	unique := posts[:0]
+	for _, post := range posts {
+		if m.seen[post.URI] {
+			continue
+		}
+		m.seen[post.URI] = true
+		unique = append(unique, post)
+	}
+
+	switch {
+	case hasMore && nextCursor != "":
+		return unique, formatMultiSearchCursor(index, nextCursor), true, nil
+	case index+1 < len(m.queries):
+		return unique, formatMultiSearchCursor(index+1, ""), true, nil
+	default:
+		return unique, "", false, nil
+	}
+}
+
+func formatMultiSearchCursor(index int, searchCursor string) string {
+	return fmt.Sprintf("q%d:%s", index, searchCursor)
+}
+
+// parseMultiSearchCursor splits a MultiSearch cursor; an empty cursor starts the first query
+func parseMultiSearchCursor(cursor string, queryCount int) (int, string, error) {
+	if cursor == "" {
+		return 0, "", nil
+	}
+
+	indexText, searchCursor, ok := strings.Cut(strings.TrimPrefix(cursor, "q"), ":")
+	index, err := strconv.Atoi(indexText)
+	if !strings.HasPrefix(cursor, "q") || !ok || err != nil || index < 0 || index >= queryCount {
+		return 0, "", fmt.Errorf("invalid multi-query cursor %q", cursor)
+	}
+	return index, searchCursor, nil
+}
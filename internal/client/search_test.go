@@ -0,0 +1,70 @@
+package client_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/client/clienttest"
+)
+
+func TestParseSearchQueries(t *testing.T) {
+	got := client.ParseSearchQueries(" AI, climate,,AI , ")
+	want := []string{"AI", "climate"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSearchQueries() = %v, want %v", got, want)
+	}
+	if got := client.ParseSearchQueries(""); got != nil {
+		t.Errorf("ParseSearchQueries(\"\") = %v, want nil", got)
+	}
+}
+
+func TestMultiSearchMergesQueries(t *testing.T) {
+	post := func(uri string) client.Post {
+		return client.Post{URI: uri, CreatedAt: "2025-01-05T12:30:00Z"}
+	}
+	mock := clienttest.NewMockClient(
+		clienttest.MockBatch{Posts: []client.Post{post("a"), post("b")}, Cursor: "100", HasMore: true},
+		clienttest.MockBatch{Posts: []client.Post{post("c")}},
+		clienttest.MockBatch{Posts: []client.Post{post("b"), post("d")}, Cursor: "100"},
+	)
+	search := client.NewMultiSearch(mock, []string{"AI", "climate"})
+
+	var uris []string
+	var cursors []string
+	cursor := ""
+	for i := 0; i < 5; i++ {
+		posts, next, hasMore, err := search.Batch(context.Background(), cursor, fixtureCutoff)
+		if err != nil {
+			t.Fatalf("Batch(%q) error = %v", cursor, err)
+		}
+		for _, p := range posts {
+			uris = append(uris, p.URI)
+		}
+		if !hasMore {
+			break
+		}
+		cursor = next
+		cursors = append(cursors, next)
+	}
+
+	if want := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(uris, want) {
+		t.Errorf("merged URIs = %v, want %v (duplicate b dropped)", uris, want)
+	}
+	if want := []string{"q0:100", "q1:"}; !reflect.DeepEqual(cursors, want) {
+		t.Errorf("cursors = %v, want %v", cursors, want)
+	}
+	if want := []string{"AI", "AI", "climate"}; !reflect.DeepEqual(mock.Queries(), want) {
+		t.Errorf("queries = %v, want %v", mock.Queries(), want)
+	}
+}
+
+func TestMultiSearchRejectsForeignCursor(t *testing.T) {
+	search := client.NewMultiSearch(clienttest.NewMockClient(), []string{"AI"})
+	for _, cursor := range []string{"100", "q5:", "qx:1"} {
+		if _, _, _, err := search.Batch(context.Background(), cursor, fixtureCutoff); err == nil {
+			t.Errorf("Batch(%q) succeeded, want error", cursor)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package client
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+)
+
+// Limits the PDS enforces on app.bsky.feed.post records and their image blobs
+const (
+	MaxPostGraphemes = 300
+	MaxImageBytes    = 1_000_000
+)
+
+// allowedImageTypes are the image MIME types Bluesky accepts for embedded images
+var allowedImageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/webp": true,
+	"image/gif":  true,
+}
+
+// ValidationIssue is one problem found in a post before it is sent
+type ValidationIssue struct {
+	Field   string // "text", "facet" or "image"
+	Index   int    // position of the facet or image; 0 for the text
+	Problem string
+}
+
+func (i ValidationIssue) String() string {
+	if i.Field == "text" {
+		return "text: " + i.Problem
+	}
+	return fmt.Sprintf("%s %d: %s", i.Field, i.Index, i.Problem)
+}
+
+// ValidationError lists every problem that would make the PDS reject a post
+type ValidationError struct {
+	Issues []ValidationIssue
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		problems[i] = issue.String()
+	}
+	return "post failed validation: " + strings.Join(problems, "; ")
+}
+
+// ValidatePost checks a post's text, facets and images against the PDS limits
+// Returns every issue found, or nil when the post can be sent as is.
+func ValidatePost(text string, facets []*bsky.RichtextFacet, images ...[]byte) []ValidationIssue {
+	var issues []ValidationIssue
+
+	if !utf8.ValidString(text) {
+		issues = append(issues, ValidationIssue{Field: "text", Problem: "text is not valid UTF-8"})
+	}
+	if count := GraphemeCount(text); count > MaxPostGraphemes {
+		issues = append(issues, ValidationIssue{Field: "text", Problem: fmt.Sprintf("%d graphemes exceeds the %d limit", count, MaxPostGraphemes)})
+	}
+
+	for i, facet := range facets {
+		if problem := facetProblem(text, facet); problem != "" {
+			issues = append(issues, ValidationIssue{Field: "facet", Index: i, Problem: problem})
+		}
+	}
+
+	for i, image := range images {
+		if problem := imageProblem(image); problem != "" {
+			issues = append(issues, ValidationIssue{Field: "image", Index: i, Problem: problem})
+		}
+	}
+
+	return issues
+}
+
+// ValidateImage checks an image blob's size and MIME type before it is uploaded
+func ValidateImage(imageData []byte) error {
+	if problem := imageProblem(imageData); problem != "" {
+		return &ValidationError{Issues: []ValidationIssue{{Field: "image", Problem: problem}}}
+	}
+	return nil
+}
+
+// imageProblem describes why an image would be rejected, or returns "" if it is acceptable
+func imageProblem(imageData []byte) string {
+	if len(imageData) == 0 {
+		return "image is empty"
+	}
+	if len(imageData) > MaxImageBytes {
+		return fmt.Sprintf("%d bytes exceeds the %d byte limit", len(imageData), MaxImageBytes)
+	}
+	if contentType := http.DetectContentType(imageData); !allowedImageTypes[contentType] {
+		return fmt.Sprintf("unsupported MIME type %s", contentType)
+	}
+	return ""
+}
+
+// facetProblem describes why a facet would be rejected, or returns "" if it is valid
+// Byte ranges must fall inside the text on UTF-8 character boundaries.
+func facetProblem(text string, facet *bsky.RichtextFacet) string {
+	if facet == nil || facet.Index == nil {
+		return "facet has no byte range"
+	}
+	start, end := facet.Index.ByteStart, facet.Index.ByteEnd
+	if start < 0 || end <= start || end > int64(len(text)) {
+		return fmt.Sprintf("byte range %d-%d is outside the %d byte text", start, end, len(text))
+	}
+	if !isRuneBoundary(text, int(start)) || !isRuneBoundary(text, int(end)) {
+		return fmt.Sprintf("byte range %d-%d splits a UTF-8 character", start, end)
+	}
+	if len(facet.Features) == 0 {
+		return "facet has no features"
+	}
+
+	for _, feature := range facet.Features {
+		switch {
+		case feature == nil:
+			return "facet has an empty feature"
+		case feature.RichtextFacet_Link != nil:
+			if !isValidLink(feature.RichtextFacet_Link.Uri) {
+				return fmt.Sprintf("link %q is not a valid URL", feature.RichtextFacet_Link.Uri)
+			}
+		case feature.RichtextFacet_Mention != nil:
+			if !strings.HasPrefix(feature.RichtextFacet_Mention.Did, "did:") {
+				return fmt.Sprintf("mention %q is not a DID", feature.RichtextFacet_Mention.Did)
+			}
+		case feature.RichtextFacet_Tag != nil:
+			tag := feature.RichtextFacet_Tag.Tag
+			if tag == "" || strings.ContainsAny(tag, " \n\t") {
+				return fmt.Sprintf("tag %q is empty or contains whitespace", tag)
+			}
+		}
+	}
+	return ""
+}
+
+// isRuneBoundary reports whether offset is at the start of a UTF-8 character or the end of text
+func isRuneBoundary(text string, offset int) bool {
+	return offset == len(text) || utf8.RuneStart(text[offset])
+}
+
+// isValidLink reports whether uri is an absolute http(s) URL with a host
+func isValidLink(uri string) bool {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "https" || parsed.Scheme == "http") && parsed.Host != ""
+}
+
+// GraphemeCount approximates the number of user-perceived characters in text, as the PDS
+// counts them: combining marks, variation selectors, emoji modifiers and ZWJ sequences
+// join the preceding character, and regional indicators pair into flags.
+func GraphemeCount(text string) int {
+	return len(graphemeStarts(text))
+}
+
+// TruncateGraphemes cuts text to at most limit graphemes, ending a cut text with an ellipsis
+func TruncateGraphemes(text string, limit int) string {
+	starts := graphemeStarts(text)
+	if len(starts) <= limit {
+		return text
+	}
+	if limit <= 1 {
+		return "…"
+	}
+	return strings.TrimRight(text[:starts[limit-1]], " \n") + "…"
+}
+
+// graphemeStarts returns the byte offset at which each grapheme of text begins
+func graphemeStarts(text string) []int {
+	var starts []int
+	prev := rune(-1)
+	regionalRun := 0
+	for offset, r := range text {
+		joins := false
+		switch {
+		case prev == -1:
+		case prev == zeroWidthJoiner, r == zeroWidthJoiner:
+			joins = true
+		case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+			joins = true
+		case r >= 0xFE00 && r <= 0xFE0F, r >= 0x1F3FB && r <= 0x1F3FF, r >= 0xE0020 && r <= 0xE007F:
+			joins = true
+		case isRegionalIndicator(r) && isRegionalIndicator(prev) && regionalRun%2 == 1:
+			joins = true
+		}
+
+		if isRegionalIndicator(r) {
+			regionalRun++
+		} else {
+			regionalRun = 0
+		}
+		if !joins {
+			starts = append(starts, offset)
+		}
+		prev = r
+	}
+	return starts
+}
+
+const zeroWidthJoiner = '\u200d'
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// sanitizePost degrades a post the PDS would reject into one it accepts: text is cut to
+// the grapheme limit and invalid facets are dropped. Every issue found is logged and returned.
+func sanitizePost(text string, facets []*bsky.RichtextFacet) (string, []*bsky.RichtextFacet, []ValidationIssue) {
+	issues := ValidatePost(text, facets)
+	if len(issues) == 0 {
+		return text, facets, nil
+	}
+
+	for _, issue := range issues {
+		log.Printf("⚠️ Post validation: %s", issue)
+	}
+
+	text = strings.ToValidUTF8(text, "�")
+	text = TruncateGraphemes(text, MaxPostGraphemes)
+
+	var kept []*bsky.RichtextFacet
+	for _, facet := range facets {
+		if facetProblem(text, facet) == "" {
+			kept = append(kept, facet)
+		}
+	}
+	return text, kept, issues
+}
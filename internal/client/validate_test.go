@@ -0,0 +1,140 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+)
+
+func linkFacet(start, end int64, uri string) *bsky.RichtextFacet {
+	return &bsky.RichtextFacet{
+		Index: &bsky.RichtextFacet_ByteSlice{ByteStart: start, ByteEnd: end},
+		Features: []*bsky.RichtextFacet_Features_Elem{
+			{RichtextFacet_Link: &bsky.RichtextFacet_Link{Uri: uri}},
+		},
+	}
+}
+
+func TestGraphemeCount(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"hello", 5},
+		{"café", 4},
+		{"cafe\u0301", 4},                 // combining acute accent
+		{"\U0001F44D\U0001F3FD", 1},       // skin tone modifier
+		{"\U0001F469\u200d\U0001F4BB", 1}, // ZWJ sequence
+		{"\U0001F1E6\U0001F1FA\U0001F1F3\U0001F1FF", 2}, // two flags
+		{"\u2764\ufe0f ok", 4},                          // variation selector
+		{strings.Repeat("é", 300), 300},
+	}
+	for _, tt := range tests {
+		if got := GraphemeCount(tt.text); got != tt.want {
+			t.Errorf("GraphemeCount(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateGraphemes(t *testing.T) {
+	tests := []struct {
+		text  string
+		limit int
+		want  string
+	}{
+		{"hello", 5, "hello"},
+		{"hello world", 7, "hello…"},
+		{"ab\U0001F469\u200d\U0001F4BBcd", 4, "ab\U0001F469\u200d\U0001F4BB…"}, // keeps the ZWJ sequence whole
+		{"hello", 1, "…"},
+	}
+	for _, tt := range tests {
+		if got := TruncateGraphemes(tt.text, tt.limit); got != tt.want {
+			t.Errorf("TruncateGraphemes(%q, %d) = %q, want %q", tt.text, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePost(t *testing.T) {
+	text := "Bluesky is #happy — see @alice.bsky.social"
+	handle := int64(strings.Index(text, "@"))
+
+	valid := []*bsky.RichtextFacet{linkFacet(handle, int64(len(text)), "https://bsky.app/profile/alice")}
+	if issues := ValidatePost(text, valid); len(issues) != 0 {
+		t.Fatalf("expected a valid post, got %v", issues)
+	}
+
+	dash := int64(strings.Index(text, "—"))
+	invalid := []*bsky.RichtextFacet{
+		linkFacet(dash+1, handle, "https://example.com"),            // starts inside the em dash
+		linkFacet(0, int64(len(text))+1, "https://example.com"),     // past the end
+		linkFacet(handle, int64(len(text)), "at://did:plc:abc/app"), // not an http(s) URL
+		linkFacet(handle, handle, "https://example.com"),            // empty range
+	}
+	issues := ValidatePost(strings.Repeat("a", 301)+text, nil)
+	if len(issues) != 1 || issues[0].Field != "text" {
+		t.Errorf("expected one text issue for an overlong post, got %v", issues)
+	}
+
+	issues = ValidatePost(text, invalid)
+	if len(issues) != len(invalid) {
+		t.Fatalf("expected %d facet issues, got %v", len(invalid), issues)
+	}
+	for i, issue := range issues {
+		if issue.Field != "facet" || issue.Index != i {
+			t.Errorf("issue %d = %v, want facet %d", i, issue, i)
+		}
+	}
+}
+
+func TestValidateImage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateImage(buf.Bytes()); err != nil {
+		t.Errorf("expected a small PNG to be valid, got %v", err)
+	}
+
+	oversized := append(buf.Bytes(), make([]byte, MaxImageBytes)...)
+	var validationErr *ValidationError
+	if err := ValidateImage(oversized); !errors.As(err, &validationErr) || !strings.Contains(err.Error(), "byte limit") {
+		t.Errorf("expected a size error, got %v", err)
+	}
+
+	if err := ValidateImage([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>")); err == nil || !strings.Contains(err.Error(), "MIME") {
+		t.Errorf("expected a MIME type error, got %v", err)
+	}
+}
+
+func TestSanitizePost(t *testing.T) {
+	text := strings.Repeat("word ", 70) + "@alice"
+	handle := int64(strings.Index(text, "@"))
+	facets := []*bsky.RichtextFacet{
+		linkFacet(0, 4, "https://example.com"),
+		linkFacet(handle, int64(len(text)), "https://bsky.app/profile/alice"),
+		linkFacet(5, 9, "not a url"),
+	}
+
+	sanitized, kept, issues := sanitizePost(text, facets)
+	if len(issues) == 0 {
+		t.Fatal("expected issues for an overlong post with a broken link")
+	}
+	if GraphemeCount(sanitized) > MaxPostGraphemes || !strings.HasSuffix(sanitized, "…") {
+		t.Errorf("expected text truncated to %d graphemes with an ellipsis, got %d", MaxPostGraphemes, GraphemeCount(sanitized))
+	}
+	if len(kept) != 1 || kept[0] != facets[0] {
+		t.Errorf("expected only the in-range valid facet to be kept, got %d facets", len(kept))
+	}
+	if remaining := ValidatePost(sanitized, kept); len(remaining) != 0 {
+		t.Errorf("expected the sanitized post to validate, got %v", remaining)
+	}
+
+	if same, kept, issues := sanitizePost("fine", nil); same != "fine" || kept != nil || issues != nil {
+		t.Error("expected a valid post to pass through unchanged")
+	}
+}
@@ -0,0 +1,131 @@
+// Package comparison configures other social networks whose sentiment is measured
+// alongside Bluesky's each run, for the combined comparison chart
+package comparison
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/mastodon"
+)
+
+// ParameterName holds the JSON comparison settings; when it is absent only Bluesky is measured
+const ParameterName = "/hourstats/settings/comparison"
+
+// NetworkMastodon reads a Mastodon instance's public timeline
+const NetworkMastodon = "mastodon"
+
+// DefaultMaxPages caps how many timeline pages are read per run. Busy instances post far
+// more than this in 30 minutes, so the comparison is a sample of the latest posts.
+const DefaultMaxPages = 25
+
+// maxPagesLimit keeps a misconfigured cap from eating the fetcher's time budget
+const maxPagesLimit = 100
+
+// Network is one comparison source, e.g. {"name": "mastodon", "instance": "https://mastodon.social"}
+type Network struct {
+	Name     string `json:"name"`
+	Instance string `json:"instance"`
+	// Local reads only the instance's own posts rather than everything it federates
+	Local    bool `json:"local,omitempty"`
+	MaxPages int  `json:"maxPages,omitempty"`
+}
+
+// Settings lists the comparison networks. CombinedChart plots them on the sparkline
+// alongside Bluesky instead of the standard chart.
+type Settings struct {
+	Networks      []Network `json:"networks"`
+	CombinedChart bool      `json:"combinedChart,omitempty"`
+}
+
+// BatchFunc fetches one page of posts, matching the fetcher's batch signature
+type BatchFunc func(ctx context.Context, cursor string, cutoffTime time.Time) ([]client.Post, string, bool, error)
+
+// Batch returns the network's page fetcher
+func (n Network) Batch() BatchFunc {
+	return mastodon.NewClient(n.Instance, n.Local).TimelineBatch
+}
+
+// Label names the network on charts and in alt text, e.g. "Mastodon (mastodon.social)"
+func (n Network) Label() string {
+	host := n.Instance
+	if parsed, err := url.Parse(n.Instance); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+	return fmt.Sprintf("Mastodon (%s)", host)
+}
+
+// RunID is the key a network's posts and sentiment are stored under for a Bluesky run
+func RunID(runID, network string) string {
+	return runID + "#" + network
+}
+
+// ParameterGetter is the subset of the SSM client Load needs
+type ParameterGetter interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Load reads the comparison settings from SSM. A missing or empty parameter returns nil,
+// which disables comparison.
+func Load(ctx context.Context, ssmClient ParameterGetter) (*Settings, error) {
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ParameterName),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %s: %w", ParameterName, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return nil, nil
+	}
+	return Parse(*result.Parameter.Value)
+}
+
+// Parse decodes and validates JSON comparison settings
+func Parse(value string) (*Settings, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var settings Settings
+	if err := json.Unmarshal([]byte(value), &settings); err != nil {
+		return nil, fmt.Errorf("invalid comparison JSON: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := range settings.Networks {
+		network := &settings.Networks[i]
+		if network.Name != NetworkMastodon {
+			return nil, fmt.Errorf("unknown comparison network %q (want %q)", network.Name, NetworkMastodon)
+		}
+		if seen[network.Name] {
+			return nil, fmt.Errorf("comparison network %q is listed twice", network.Name)
+		}
+		seen[network.Name] = true
+
+		instance, err := url.Parse(network.Instance)
+		if err != nil || instance.Scheme != "https" || instance.Host == "" {
+			return nil, fmt.Errorf("comparison network %q needs an https instance URL, got %q", network.Name, network.Instance)
+		}
+		switch {
+		case network.MaxPages == 0:
+			network.MaxPages = DefaultMaxPages
+		case network.MaxPages < 0 || network.MaxPages > maxPagesLimit:
+			return nil, fmt.Errorf("comparison network %q maxPages must be between 1 and %d", network.Name, maxPagesLimit)
+		}
+	}
+	return &settings, nil
+}
@@ -0,0 +1,38 @@
+package comparison
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	settings, err := Parse(`{"networks": [{"name": "mastodon", "instance": "https://mastodon.social"}], "combinedChart": true}`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !settings.CombinedChart || len(settings.Networks) != 1 || settings.Networks[0].MaxPages != DefaultMaxPages {
+		t.Errorf("Unexpected settings %+v", settings)
+	}
+	if label := settings.Networks[0].Label(); label != "Mastodon (mastodon.social)" {
+		t.Errorf("Label() = %q", label)
+	}
+
+	if settings, err := Parse(" "); err != nil || settings != nil {
+		t.Errorf("Parse(empty) = %+v, %v; want nil", settings, err)
+	}
+
+	for name, value := range map[string]string{
+		"unknown network": `{"networks": [{"name": "threads", "instance": "https://threads.net"}]}`,
+		"duplicate":       `{"networks": [{"name": "mastodon", "instance": "https://a.example"}, {"name": "mastodon", "instance": "https://b.example"}]}`,
+		"plain http":      `{"networks": [{"name": "mastodon", "instance": "http://mastodon.social"}]}`,
+		"too many pages":  `{"networks": [{"name": "mastodon", "instance": "https://mastodon.social", "maxPages": 500}]}`,
+		"invalid JSON":    `{"networks": `,
+	} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestRunID(t *testing.T) {
+	if got := RunID("run-1", NetworkMastodon); got != "run-1#mastodon" {
+		t.Errorf("RunID() = %q", got)
+	}
+}
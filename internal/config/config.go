@@ -6,11 +6,17 @@ import (
 	"path/filepath"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
+	"github.com/christophergentle/hourstats-bsky/internal/dryrun"
 )
 
 type Config struct {
-	Bluesky  BlueskyConfig  `yaml:"bluesky"`
+	Bluesky BlueskyConfig `yaml:"bluesky"`
+	// Shadow is the test account a shadow dry run posts from; it is only needed then
+	Shadow   BlueskyConfig  `yaml:"shadow"`
 	Settings SettingsConfig `yaml:"settings"`
+	Tables   TablesConfig   `yaml:"tables"`
 }
 
 type BlueskyConfig struct {
@@ -19,20 +25,74 @@ type BlueskyConfig struct {
 }
 
 type SettingsConfig struct {
-	AnalysisIntervalMinutes int  `yaml:"analysis_interval_minutes"`
-	TopPostsCount           int  `yaml:"top_posts_count"`
-	MinEngagementScore      int  `yaml:"min_engagement_score"`
-	DryRun                  bool `yaml:"dry_run"`
+	AnalysisIntervalMinutes int          `yaml:"analysis_interval_minutes"`
+	TopPostsCount           int          `yaml:"top_posts_count"`
+	MinEngagementScore      int          `yaml:"min_engagement_score"`
+	DryRun                  dryrun.Level `yaml:"dry_run"`
+	MinCoveragePercent      float64      `yaml:"min_coverage_percent"`
+	MinPostCount            int          `yaml:"min_post_count"`
+	// SentimentThresholds categorize each window's average score; the Lambdas read theirs
+	// from SSM instead
+	SentimentThresholds aggregation.Thresholds `yaml:"sentiment_thresholds"`
+}
+
+// TablesConfig names the DynamoDB tables; empty names use DefaultTables
+type TablesConfig struct {
+	State            string `yaml:"state"`
+	SentimentHistory string `yaml:"sentiment_history"`
+	DailySentiment   string `yaml:"daily_sentiment"`
+	Blocklist        string `yaml:"blocklist"`
+}
+
+// DefaultTables returns the tables Terraform creates
+func DefaultTables() TablesConfig {
+	return TablesConfig{
+		State:            "hourstats-state",
+		SentimentHistory: "hourstats-sentiment-history",
+		DailySentiment:   "hourstats-daily-sentiment",
+		Blocklist:        "hourstats-blocklist",
+	}
+}
+
+// TablesFromEnv returns the default tables, with the state table taken from
+// DYNAMODB_TABLE when it is set, as Terraform does for the Lambdas
+func TablesFromEnv() TablesConfig {
+	tables := DefaultTables()
+	if table := os.Getenv("DYNAMODB_TABLE"); table != "" {
+		tables.State = table
+	}
+	return tables
+}
+
+// withDefaults fills in the tables left empty
+func (t TablesConfig) withDefaults() TablesConfig {
+	defaults := DefaultTables()
+	if t.State == "" {
+		t.State = defaults.State
+	}
+	if t.SentimentHistory == "" {
+		t.SentimentHistory = defaults.SentimentHistory
+	}
+	if t.DailySentiment == "" {
+		t.DailySentiment = defaults.DailySentiment
+	}
+	if t.Blocklist == "" {
+		t.Blocklist = defaults.Blocklist
+	}
+	return t
 }
 
 // LoadConfig loads configuration from config.yaml file
 func LoadConfig() (*Config, error) {
 	// Look for config.yaml in current directory
-	configPath := "config.yaml"
+	return LoadConfigFile("config.yaml")
+}
 
+// LoadConfigFile loads and validates configuration from a YAML file at configPath
+func LoadConfigFile(configPath string) (*Config, error) {
 	// Check if file exists
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("config.yaml not found. Please copy config.example.yaml to config.yaml and fill in your credentials")
+		return nil, fmt.Errorf("%s not found. Please copy config.example.yaml to config.yaml and fill in your credentials", configPath)
 	}
 
 	// Read the file
@@ -41,19 +101,20 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	// Parse YAML
-	var config Config
+	// Parse YAML over the defaults of settings that may be 0, so an explicit 0 turns
+	// their check off rather than restoring the default
+	config := Config{Settings: SettingsConfig{MinCoveragePercent: 80, MinPostCount: 100, SentimentThresholds: aggregation.DefaultThresholds}}
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
 	// Validate required fields
 	if config.Bluesky.Handle == "" || config.Bluesky.Handle == "your-handle.bsky.social" {
-		return nil, fmt.Errorf("please set your Bluesky handle in config.yaml")
+		return nil, fmt.Errorf("please set your Bluesky handle in %s", configPath)
 	}
 
 	if config.Bluesky.Password == "" || config.Bluesky.Password == "your-app-password" {
-		return nil, fmt.Errorf("please set your Bluesky app password in config.yaml")
+		return nil, fmt.Errorf("please set your Bluesky app password in %s", configPath)
 	}
 
 	// Set defaults for optional fields
@@ -66,6 +127,11 @@ func LoadConfig() (*Config, error) {
 	if config.Settings.MinEngagementScore == 0 {
 		config.Settings.MinEngagementScore = 10
 	}
+	config.Tables = config.Tables.withDefaults()
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", configPath, err)
+	}
 
 	return &config, nil
 }
@@ -77,13 +143,30 @@ func LoadConfigFromEnv() *Config {
 			Handle:   os.Getenv("BLUESKY_HANDLE"),
 			Password: os.Getenv("BLUESKY_PASSWORD"),
 		},
+		Shadow: BlueskyConfig{
+			Handle:   os.Getenv("BLUESKY_SHADOW_HANDLE"),
+			Password: os.Getenv("BLUESKY_SHADOW_PASSWORD"),
+		},
 		Settings: SettingsConfig{
 			AnalysisIntervalMinutes: 60, // Default to 1 hour in minutes
 			TopPostsCount:           5,
 			MinEngagementScore:      10,
-			DryRun:                  os.Getenv("DRY_RUN") == "true",
+			DryRun:                  dryRunFromEnv(),
+			MinCoveragePercent:      80,
+			MinPostCount:            100,
+			SentimentThresholds:     aggregation.DefaultThresholds,
 		},
+		Tables: TablesFromEnv(),
+	}
+}
+
+// dryRunFromEnv parses DRY_RUN, keeping an unknown level as given for Validate to report
+func dryRunFromEnv() dryrun.Level {
+	level, err := dryrun.Parse(os.Getenv("DRY_RUN"))
+	if err != nil {
+		return dryrun.Level(os.Getenv("DRY_RUN"))
 	}
+	return level
 }
 
 // GetConfigPath returns the path to the config file
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigFileKeepsZeroSettings(t *testing.T) {
+	write := func(settings string) string {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		content := "bluesky:\n  handle: hourstats.bsky.social\n  password: app-password\nsettings:\n" + settings
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	cfg, err := LoadConfigFile(write("  min_coverage_percent: 0\n  min_post_count: 0\n"))
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+	if cfg.Settings.MinCoveragePercent != 0 || cfg.Settings.MinPostCount != 0 {
+		t.Errorf("Expected explicit zeros to turn the checks off, got %+v", cfg.Settings)
+	}
+
+	cfg, err = LoadConfigFile(write("  top_posts_count: 5\n"))
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+	if cfg.Settings.MinCoveragePercent != 80 || cfg.Settings.MinPostCount != 100 {
+		t.Errorf("Expected the defaults when unset, got %+v", cfg.Settings)
+	}
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+
+	"github.com/christophergentle/hourstats-bsky/internal/formatter"
+	"github.com/christophergentle/hourstats-bsky/internal/interval"
+)
+
+// tableNamePattern is DynamoDB's rule for table names
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{3,255}$`)
+
+// templateCheckHandle stands in for top post authors when checking that summaries fit;
+// most handles are shorter
+const templateCheckHandle = "a-rather-long-handle.bsky.social"
+
+// Validate reports every problem with the configuration at once, so a bad deploy shows
+// the whole list rather than one problem per attempt
+func (c *Config) Validate() error {
+	var problems []error
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	if _, err := syntax.ParseHandle(strings.TrimPrefix(c.Bluesky.Handle, "@")); err != nil {
+		add("bluesky handle %q is not a valid handle", c.Bluesky.Handle)
+	}
+	if c.Bluesky.Password == "" {
+		add("bluesky password is empty")
+	}
+
+	settings := c.Settings
+	if err := interval.Validate(settings.AnalysisIntervalMinutes); err != nil {
+		add("analysis_interval_minutes: %w", err)
+	}
+	if settings.TopPostsCount < 1 {
+		add("top_posts_count must be at least 1, got %d", settings.TopPostsCount)
+	}
+	if settings.MinEngagementScore < 0 {
+		add("min_engagement_score must not be negative, got %d", settings.MinEngagementScore)
+	}
+	if settings.MinCoveragePercent < 0 || settings.MinCoveragePercent > 100 {
+		add("min_coverage_percent must be between 0 and 100, got %g", settings.MinCoveragePercent)
+	}
+	if settings.MinPostCount < 0 {
+		add("min_post_count must not be negative, got %d", settings.MinPostCount)
+	}
+	if err := settings.SentimentThresholds.Validate(); err != nil {
+		add("sentiment_thresholds: %w", err)
+	}
+	if err := settings.DryRun.Validate(); err != nil {
+		add("dry_run: %w", err)
+	}
+	if settings.DryRun.ShadowAccount() {
+		if _, err := syntax.ParseHandle(strings.TrimPrefix(c.Shadow.Handle, "@")); err != nil {
+			add("shadow handle %q is not a valid handle, and a shadow dry run needs one", c.Shadow.Handle)
+		}
+		if c.Shadow.Password == "" {
+			add("shadow password is empty, and a shadow dry run needs one")
+		}
+	}
+
+	for _, table := range []struct{ name, value string }{
+		{"state", c.Tables.State},
+		{"sentiment_history", c.Tables.SentimentHistory},
+		{"daily_sentiment", c.Tables.DailySentiment},
+		{"blocklist", c.Tables.Blocklist},
+	} {
+		if !tableNamePattern.MatchString(table.value) {
+			add("%s table %q is not a valid DynamoDB table name", table.name, table.value)
+		}
+	}
+
+	if settings.TopPostsCount >= 1 {
+		for _, template := range formatter.Templates {
+			if kept := fewestTopPosts(template, settings.TopPostsCount, settings.AnalysisIntervalMinutes); kept < settings.TopPostsCount {
+				add("%s summaries with %d top posts can run over Bluesky's %d characters, leaving room for only %d",
+					template, settings.TopPostsCount, formatter.MaxPostLength, kept)
+			}
+		}
+	}
+
+	return errors.Join(problems...)
+}
+
+// fewestTopPosts is the fewest of count top posts the template keeps in a summary, over
+// every net sentiment from -100% to +100%; the formatter drops the rest to fit
+func fewestTopPosts(template formatter.Template, count, analysisIntervalMinutes int) int {
+	posts := make([]formatter.Post, count)
+	for i := range posts {
+		posts[i] = formatter.Post{Author: templateCheckHandle, Sentiment: "negative"}
+	}
+
+	fewest := count
+	for percent := -100; percent <= 100; percent++ {
+		_, spans := formatter.FormatPostContentWithTemplate(template, posts, "negative", analysisIntervalMinutes, 0, float64(percent)/100)
+		kept := 0
+		for _, span := range spans {
+			if span.Handle != "" {
+				kept++
+			}
+		}
+		fewest = min(fewest, kept)
+	}
+	return fewest
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
+	"github.com/christophergentle/hourstats-bsky/internal/dryrun"
+)
+
+func validConfig() *Config {
+	return &Config{
+		Bluesky: BlueskyConfig{Handle: "hourstats.bsky.social", Password: "app-password"},
+		Settings: SettingsConfig{
+			AnalysisIntervalMinutes: 60,
+			TopPostsCount:           5,
+			MinEngagementScore:      10,
+			MinCoveragePercent:      80,
+			MinPostCount:            100,
+			SentimentThresholds:     aggregation.DefaultThresholds,
+		},
+		Tables: DefaultTables(),
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := validConfig().Validate(); err != nil {
+		t.Fatalf("Expected the default configuration to be valid, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(*Config)
+		want   string
+	}{
+		{"handle", func(c *Config) { c.Bluesky.Handle = "not a handle" }, "not a valid handle"},
+		{"password", func(c *Config) { c.Bluesky.Password = "" }, "password is empty"},
+		{"interval", func(c *Config) { c.Settings.AnalysisIntervalMinutes = 90 }, "analysis_interval_minutes"},
+		{"top posts", func(c *Config) { c.Settings.TopPostsCount = 0 }, "top_posts_count"},
+		{"engagement", func(c *Config) { c.Settings.MinEngagementScore = -1 }, "min_engagement_score"},
+		{"coverage", func(c *Config) { c.Settings.MinCoveragePercent = 120 }, "min_coverage_percent"},
+		{"post count", func(c *Config) { c.Settings.MinPostCount = -5 }, "min_post_count"},
+		{"thresholds", func(c *Config) { c.Settings.SentimentThresholds.Hysteresis = 0.5 }, "sentiment_thresholds"},
+		{"table", func(c *Config) { c.Tables.Blocklist = "block list" }, "blocklist table"},
+		{"summary length", func(c *Config) { c.Settings.TopPostsCount = 10 }, "over Bluesky's 300"},
+		{"dry run", func(c *Config) { c.Settings.DryRun = "sometimes" }, "unknown dry run level"},
+		{"shadow account", func(c *Config) { c.Settings.DryRun = dryrun.Shadow }, "shadow handle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := validConfig()
+			tt.change(config)
+			err := config.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() = %v, want an error mentioning %q", err, tt.want)
+			}
+		})
+	}
+
+	// A shadow dry run is valid with a shadow account
+	config := validConfig()
+	config.Settings.DryRun = dryrun.Shadow
+	config.Shadow = BlueskyConfig{Handle: "hourstats-test.bsky.social", Password: "test-password"}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected a shadow dry run with an account to be valid, got %v", err)
+	}
+
+	// Every problem is reported at once
+	config = validConfig()
+	config.Bluesky.Password = ""
+	config.Settings.MinPostCount = -1
+	if err := config.Validate(); err == nil || len(strings.Split(err.Error(), "\n")) != 2 {
+		t.Errorf("Expected two problems, got %v", err)
+	}
+}
+
+func TestTablesFromEnv(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE", "hourstats-state-staging")
+	tables := TablesFromEnv()
+	if tables.State != "hourstats-state-staging" || tables.Blocklist != DefaultTables().Blocklist {
+		t.Errorf("Unexpected tables %+v", tables)
+	}
+}
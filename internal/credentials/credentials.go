@@ -0,0 +1,123 @@
+// Package credentials finds the Bluesky account's handle and app password. A Secrets
+// Manager secret named by BLUESKY_SECRET_ID holds them when it is set, so the password can
+// be rotated there; otherwise the SSM parameters do, and remain the default.
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+
+	"github.com/christophergentle/hourstats-bsky/internal/channel"
+)
+
+// SecretIDEnv names the environment variable holding the secret's name or ARN
+const SecretIDEnv = "BLUESKY_SECRET_ID"
+
+// Credentials are a Bluesky handle and app password, stored in a secret as JSON
+type Credentials struct {
+	Handle   string `json:"handle"`
+	Password string `json:"password"`
+}
+
+// Provider retrieves credentials from one place
+type Provider interface {
+	Retrieve(ctx context.Context) (Credentials, error)
+}
+
+// Default returns the credentials for the default account: the secret named by
+// BLUESKY_SECRET_ID when it is set, otherwise the default SSM parameters. The parameters
+// aren't a fallback for the secret, since rotation only updates the secret and they would
+// hold a password it has replaced.
+func Default(cfg aws.Config, ssmClient ParameterGetter) Provider {
+	if secretID := os.Getenv(SecretIDEnv); secretID != "" {
+		return NewSecretProvider(secretsmanager.NewFromConfig(cfg), secretID)
+	}
+	return NewSSMProvider(ssmClient, channel.DefaultHandleParameter, channel.DefaultPasswordParameter)
+}
+
+// ParameterGetter is the subset of the SSM client SSMProvider needs
+type ParameterGetter interface {
+	GetParameters(ctx context.Context, params *ssm.GetParametersInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersOutput, error)
+}
+
+// SSMProvider reads credentials from a handle and a password parameter
+type SSMProvider struct {
+	client            ParameterGetter
+	handleParameter   string
+	passwordParameter string
+}
+
+// NewSSMProvider creates a provider reading the two parameters
+func NewSSMProvider(client ParameterGetter, handleParameter, passwordParameter string) *SSMProvider {
+	return &SSMProvider{client: client, handleParameter: handleParameter, passwordParameter: passwordParameter}
+}
+
+// Retrieve reads the parameters, decrypting the password
+func (p *SSMProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	result, err := p.client.GetParameters(ctx, &ssm.GetParametersInput{
+		Names:          []string{p.handleParameter, p.passwordParameter},
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to get credential parameters: %w", err)
+	}
+
+	params := make(map[string]string)
+	for _, param := range result.Parameters {
+		params[aws.ToString(param.Name)] = aws.ToString(param.Value)
+	}
+	credentials := Credentials{Handle: params[p.handleParameter], Password: params[p.passwordParameter]}
+	if credentials.Handle == "" {
+		return Credentials{}, fmt.Errorf("parameter %s not found", p.handleParameter)
+	}
+	if credentials.Password == "" {
+		return Credentials{}, fmt.Errorf("parameter %s not found", p.passwordParameter)
+	}
+	return credentials, nil
+}
+
+// SecretProvider reads credentials from the current version of a Secrets Manager secret
+type SecretProvider struct {
+	client   SecretGetter
+	secretID string
+}
+
+// NewSecretProvider creates a provider reading the secret
+func NewSecretProvider(client SecretGetter, secretID string) *SecretProvider {
+	return &SecretProvider{client: client, secretID: secretID}
+}
+
+// Retrieve reads the secret's AWSCURRENT version
+func (p *SecretProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	result, err := p.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId:     aws.String(p.secretID),
+		VersionStage: aws.String(StageCurrent),
+	})
+	if err != nil {
+		return Credentials{}, fmt.Errorf("secret %s: %w", p.secretID, err)
+	}
+	credentials, err := ParseSecret(aws.ToString(result.SecretString))
+	if err != nil {
+		return Credentials{}, fmt.Errorf("secret %s: %w", p.secretID, err)
+	}
+	return credentials, nil
+}
+
+// ParseSecret decodes a secret's JSON value, which must have a handle and a password
+func ParseSecret(value string) (Credentials, error) {
+	var credentials Credentials
+	if err := json.Unmarshal([]byte(value), &credentials); err != nil {
+		return Credentials{}, fmt.Errorf("invalid credentials JSON: %w", err)
+	}
+	if credentials.Handle == "" || credentials.Password == "" {
+		return Credentials{}, errors.New(`credentials need both a "handle" and a "password"`)
+	}
+	return credentials, nil
+}
@@ -0,0 +1,81 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+type fakeParameters map[string]string
+
+func (f fakeParameters) GetParameters(ctx context.Context, params *ssm.GetParametersInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersOutput, error) {
+	output := &ssm.GetParametersOutput{}
+	for _, name := range params.Names {
+		if value, ok := f[name]; ok {
+			output.Parameters = append(output.Parameters, ssmtypes.Parameter{Name: aws.String(name), Value: aws.String(value)})
+		}
+	}
+	return output, nil
+}
+
+type fakeSecretValue string
+
+func (f fakeSecretValue) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	if aws.ToString(params.VersionStage) != StageCurrent {
+		return nil, errors.New("expected the current version")
+	}
+	return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(string(f))}, nil
+}
+
+func TestSSMProvider(t *testing.T) {
+	got, err := NewSSMProvider(fakeParameters{"/h": "hourstats.bsky.social", "/p": "app-password"}, "/h", "/p").Retrieve(context.Background())
+	if err != nil || got != (Credentials{Handle: "hourstats.bsky.social", Password: "app-password"}) {
+		t.Errorf("Retrieve() = %+v, %v, want the SSM credentials", got, err)
+	}
+
+	missing := NewSSMProvider(fakeParameters{"/h": "hourstats.bsky.social"}, "/h", "/p")
+	if _, err := missing.Retrieve(context.Background()); err == nil {
+		t.Error("Expected an error without a password parameter")
+	}
+}
+
+func TestDefault(t *testing.T) {
+	t.Setenv(SecretIDEnv, "")
+	if _, ok := Default(aws.Config{}, fakeParameters{}).(*SSMProvider); !ok {
+		t.Error("Expected the SSM parameters without a secret")
+	}
+
+	// The secret is the only source once set, so a rotated password never falls back to SSM
+	t.Setenv(SecretIDEnv, "hourstats/bluesky")
+	if _, ok := Default(aws.Config{Region: "us-east-1"}, fakeParameters{}).(*SecretProvider); !ok {
+		t.Error("Expected only the secret when BLUESKY_SECRET_ID is set")
+	}
+}
+
+func TestParseSecret(t *testing.T) {
+	if got, err := ParseSecret(`{"handle": "hourstats.bsky.social", "password": "app-password"}`); err != nil || got.Handle != "hourstats.bsky.social" {
+		t.Errorf("ParseSecret() = %+v, %v", got, err)
+	}
+	for _, value := range []string{`not json`, `{"handle": "hourstats.bsky.social"}`} {
+		if _, err := ParseSecret(value); err == nil {
+			t.Errorf("Expected an error parsing %s", value)
+		}
+	}
+}
+
+func TestSecretProvider(t *testing.T) {
+	secrets := fakeSecretValue(`{"handle": "hourstats.bsky.social", "password": "app-password"}`)
+	got, err := NewSecretProvider(secrets, "hourstats/bluesky").Retrieve(context.Background())
+	if err != nil || got.Password != "app-password" {
+		t.Errorf("Retrieve() = %+v, %v, want the secret's credentials", got, err)
+	}
+
+	if _, err := NewSecretProvider(fakeSecretValue(`{"handle": "hourstats.bsky.social"}`), "hourstats/bluesky").Retrieve(context.Background()); err == nil {
+		t.Error("Expected a secret without a password to fail")
+	}
+}
@@ -0,0 +1,117 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// Rotation steps, in the order Secrets Manager calls the rotation Lambda
+const (
+	StepCreate = "createSecret"
+	StepSet    = "setSecret"
+	StepTest   = "testSecret"
+	StepFinish = "finishSecret"
+)
+
+// RotationEvent is the event Secrets Manager sends the rotation Lambda for each step
+type RotationEvent struct {
+	SecretID           string `json:"SecretId"`
+	ClientRequestToken string `json:"ClientRequestToken"`
+	Step               string `json:"Step"`
+}
+
+// Rotator rotates the Bluesky app password held in a secret. Bluesky app passwords can't
+// be created through the API with an app password, so the new password is created in
+// Bluesky's settings and staged as AWSPENDING under the rotation's token first; rotation
+// then logs in with it and only makes it current once Bluesky accepts it.
+type Rotator struct {
+	secrets RotationClient
+	// authenticate logs in to Bluesky with the credentials
+	authenticate func(Credentials) error
+}
+
+// NewRotator creates a rotator that checks new credentials with authenticate
+func NewRotator(secrets RotationClient, authenticate func(Credentials) error) *Rotator {
+	return &Rotator{secrets: secrets, authenticate: authenticate}
+}
+
+// Rotate runs one step of a rotation
+func (r *Rotator) Rotate(ctx context.Context, event RotationEvent) error {
+	description, err := r.secrets.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{SecretId: aws.String(event.SecretID)})
+	if err != nil {
+		return fmt.Errorf("failed to describe secret %s: %w", event.SecretID, err)
+	}
+	if !aws.ToBool(description.RotationEnabled) {
+		return fmt.Errorf("secret %s does not have rotation enabled", event.SecretID)
+	}
+	stages, ok := description.VersionIdsToStages[event.ClientRequestToken]
+	if !ok {
+		return fmt.Errorf("secret %s has no version %s; stage the new app password as AWSPENDING with this token before rotating",
+			event.SecretID, event.ClientRequestToken)
+	}
+	if hasStage(stages, StageCurrent) {
+		log.Printf("🔑 ROTATION: Version %s of %s is already current", event.ClientRequestToken, event.SecretID)
+		return nil
+	}
+	if !hasStage(stages, StagePending) {
+		return fmt.Errorf("version %s of secret %s is not staged AWSPENDING", event.ClientRequestToken, event.SecretID)
+	}
+
+	switch event.Step {
+	case StepCreate:
+		// The pending version was staged by hand; check it parses so a typo fails early
+		_, err := r.pending(ctx, event)
+		return err
+	case StepSet:
+		// Bluesky already holds the new app password, created in its settings
+		return nil
+	case StepTest:
+		credentials, err := r.pending(ctx, event)
+		if err != nil {
+			return err
+		}
+		if err := r.authenticate(credentials); err != nil {
+			return fmt.Errorf("bluesky rejected the pending credentials for %s: %w", credentials.Handle, err)
+		}
+		log.Printf("🔑 ROTATION: Pending credentials for %s authenticated", credentials.Handle)
+		return nil
+	case StepFinish:
+		current := currentVersion(description.VersionIdsToStages)
+		update := &secretsmanager.UpdateSecretVersionStageInput{
+			SecretId:        aws.String(event.SecretID),
+			VersionStage:    aws.String(StageCurrent),
+			MoveToVersionId: aws.String(event.ClientRequestToken),
+		}
+		if current != "" {
+			update.RemoveFromVersionId = aws.String(current)
+		}
+		if _, err := r.secrets.UpdateSecretVersionStage(ctx, update); err != nil {
+			return fmt.Errorf("failed to make version %s of %s current: %w", event.ClientRequestToken, event.SecretID, err)
+		}
+		log.Printf("🔑 ROTATION: Version %s of %s is now current, replacing %s", event.ClientRequestToken, event.SecretID, current)
+		return nil
+	default:
+		return fmt.Errorf("unknown rotation step %q", event.Step)
+	}
+}
+
+// pending reads the credentials staged under the rotation's token
+func (r *Rotator) pending(ctx context.Context, event RotationEvent) (Credentials, error) {
+	result, err := r.secrets.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId:     aws.String(event.SecretID),
+		VersionId:    aws.String(event.ClientRequestToken),
+		VersionStage: aws.String(StagePending),
+	})
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to get pending version of %s: %w", event.SecretID, err)
+	}
+	credentials, err := ParseSecret(aws.ToString(result.SecretString))
+	if err != nil {
+		return Credentials{}, fmt.Errorf("pending version of %s: %w", event.SecretID, err)
+	}
+	return credentials, nil
+}
@@ -0,0 +1,83 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+type fakeSecrets struct {
+	rotationEnabled bool
+	versions        map[string][]string
+	values          map[string]string
+	moved           []string
+}
+
+func (f *fakeSecrets) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	value, ok := f.values[aws.ToString(params.VersionId)]
+	if !ok {
+		return nil, errors.New("version not found")
+	}
+	return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(value)}, nil
+}
+
+func (f *fakeSecrets) DescribeSecret(ctx context.Context, params *secretsmanager.DescribeSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error) {
+	return &secretsmanager.DescribeSecretOutput{RotationEnabled: aws.Bool(f.rotationEnabled), VersionIdsToStages: f.versions}, nil
+}
+
+func (f *fakeSecrets) UpdateSecretVersionStage(ctx context.Context, params *secretsmanager.UpdateSecretVersionStageInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretVersionStageOutput, error) {
+	f.moved = append(f.moved, aws.ToString(params.VersionStage)+" "+aws.ToString(params.RemoveFromVersionId)+"->"+aws.ToString(params.MoveToVersionId))
+	return &secretsmanager.UpdateSecretVersionStageOutput{}, nil
+}
+
+func newFakeSecrets() *fakeSecrets {
+	return &fakeSecrets{
+		rotationEnabled: true,
+		versions: map[string][]string{
+			"old": {StageCurrent},
+			"new": {StagePending},
+		},
+		values: map[string]string{
+			"old": `{"handle": "hourstats.bsky.social", "password": "old-password"}`,
+			"new": `{"handle": "hourstats.bsky.social", "password": "new-password"}`,
+		},
+	}
+}
+
+func TestRotate(t *testing.T) {
+	secrets := newFakeSecrets()
+	var tried []string
+	rotator := NewRotator(secrets, func(c Credentials) error {
+		tried = append(tried, c.Password)
+		return nil
+	})
+
+	for _, step := range []string{StepCreate, StepSet, StepTest, StepFinish} {
+		if err := rotator.Rotate(context.Background(), RotationEvent{SecretID: "hourstats/bluesky", ClientRequestToken: "new", Step: step}); err != nil {
+			t.Fatalf("%s failed: %v", step, err)
+		}
+	}
+	if len(tried) != 1 || tried[0] != "new-password" {
+		t.Errorf("Expected one login with the new password, got %v", tried)
+	}
+	if len(secrets.moved) != 1 || secrets.moved[0] != "AWSCURRENT old->new" {
+		t.Errorf("Expected AWSCURRENT to move from old to new, got %v", secrets.moved)
+	}
+}
+
+func TestRotateRejectedPassword(t *testing.T) {
+	secrets := newFakeSecrets()
+	rotator := NewRotator(secrets, func(c Credentials) error { return errors.New("invalid password") })
+
+	if err := rotator.Rotate(context.Background(), RotationEvent{SecretID: "hourstats/bluesky", ClientRequestToken: "new", Step: StepTest}); err == nil {
+		t.Error("Expected testSecret to fail when Bluesky rejects the password")
+	}
+
+	// Without a staged version there is nothing to rotate to
+	if err := rotator.Rotate(context.Background(), RotationEvent{SecretID: "hourstats/bluesky", ClientRequestToken: "missing", Step: StepCreate}); err == nil {
+		t.Error("Expected createSecret to fail without a pending version")
+	}
+}
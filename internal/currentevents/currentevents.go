@@ -0,0 +1,223 @@
+// Package currentevents looks up what was in the news on a date from Wikipedia's
+// Current Events portal, so sentiment extremes can be given some context
+package currentevents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// DefaultEndpoint is the English Wikipedia action API
+const DefaultEndpoint = "https://en.wikipedia.org/w/api.php"
+
+// userAgent identifies the bot, as Wikimedia's API policy asks
+const userAgent = "hourstats-bsky (https://github.com/brainsnorkel/hourstats-bsky)"
+
+// requestTimeout bounds each lookup; the context is optional to a post, so it must not hold one up
+const requestTimeout = 10 * time.Second
+
+// MaxHeadlineLength caps a headline in runes, cut at a word boundary
+const MaxHeadlineLength = 100
+
+// A date's portal page keeps being edited for a day or two. Cached headlines fetched
+// after settleAfter are final; earlier ones are refreshed once older than refreshAfter.
+const (
+	settleAfter  = 48 * time.Hour
+	refreshAfter = 6 * time.Hour
+)
+
+// Cache stores headlines between runs; *state.StateManager implements it
+type Cache interface {
+	GetCurrentEventsHeadline(ctx context.Context, date string) (*state.CurrentEventsHeadline, error)
+	SaveCurrentEventsHeadline(ctx context.Context, date, headline string) error
+}
+
+// Source looks up headlines, through the cache when it has one
+type Source struct {
+	endpoint   string
+	httpClient *http.Client
+	cache      Cache
+	now        func() time.Time
+}
+
+// NewSource creates a source reading from endpoint (DefaultEndpoint when empty). A nil
+// cache fetches every time.
+func NewSource(endpoint string, cache Cache) *Source {
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	return &Source{
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: requestTimeout},
+		cache:      cache,
+		now:        time.Now,
+	}
+}
+
+// Headline returns one headline for the UTC date, or "" when there is none or it can't be
+// fetched. Failures are logged rather than returned: a post goes out without the context.
+func (s *Source) Headline(ctx context.Context, date time.Time) string {
+	date = date.UTC()
+	key := date.Format("2006-01-02")
+
+	if s.cache != nil {
+		cached, err := s.cache.GetCurrentEventsHeadline(ctx, key)
+		if err != nil {
+			log.Printf("Failed to read cached current events for %s: %v", key, err)
+		} else if cached != nil && s.isFresh(date, cached.FetchedAt) {
+			return cached.Headline
+		}
+	}
+
+	headline, err := s.Fetch(ctx, date)
+	if err != nil {
+		log.Printf("Failed to fetch current events for %s: %v", key, err)
+		return ""
+	}
+
+	if s.cache != nil {
+		if err := s.cache.SaveCurrentEventsHeadline(ctx, key, headline); err != nil {
+			log.Printf("Failed to cache current events for %s: %v", key, err)
+		}
+	}
+	return headline
+}
+
+// isFresh reports whether a headline for date fetched at fetchedAt can be used as is
+func (s *Source) isFresh(date, fetchedAt time.Time) bool {
+	if fetchedAt.After(date.Add(settleAfter)) {
+		return true
+	}
+	return s.now().Sub(fetchedAt) < refreshAfter
+}
+
+// parseResponse is the action API's reply to a wikitext parse request
+type parseResponse struct {
+	Parse struct {
+		Wikitext string `json:"wikitext"`
+	} `json:"parse"`
+	Error *struct {
+		Code string `json:"code"`
+		Info string `json:"info"`
+	} `json:"error"`
+}
+
+// Fetch reads the date's portal page and returns its first headline, bypassing the cache.
+// A date without a page has no headline and no error.
+func (s *Source) Fetch(ctx context.Context, date time.Time) (string, error) {
+	query := url.Values{
+		"action":        {"parse"},
+		"page":          {PageTitle(date)},
+		"prop":          {"wikitext"},
+		"redirects":     {"1"},
+		"format":        {"json"},
+		"formatversion": {"2"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build current events request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch current events: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("current events returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var parsed parseResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("invalid current events response: %w", err)
+	}
+	if parsed.Error != nil {
+		if parsed.Error.Code == "missingtitle" {
+			return "", nil
+		}
+		return "", fmt.Errorf("current events error %s: %s", parsed.Error.Code, parsed.Error.Info)
+	}
+	return FirstHeadline(parsed.Parse.Wikitext), nil
+}
+
+// PageTitle is the portal page for a date, e.g. "Portal:Current events/2025 September 18"
+func PageTitle(date time.Time) string {
+	return "Portal:Current events/" + date.UTC().Format("2006 January 2")
+}
+
+// Format prefixes a headline with its date, e.g. "Sep 18: A magnitude 7.8 earthquake..."
+func Format(date time.Time, headline string) string {
+	return date.UTC().Format("Jan 2") + ": " + headline
+}
+
+var (
+	topicLine     = regexp.MustCompile(`^\[\[[^\]]+\]\]$`)
+	comments      = regexp.MustCompile(`(?s)<!--.*?-->`)
+	references    = regexp.MustCompile(`(?s)<ref[^>]*/>|<ref[^>]*>.*?</ref>`)
+	templates     = regexp.MustCompile(`\{\{[^{}]*\}\}`)
+	externalLinks = regexp.MustCompile(`\[https?://[^\]]*\]`)
+	wikiLinks     = regexp.MustCompile(`\[\[(?:[^|\]]*\|)?([^\]]*)\]\]`)
+	markup        = regexp.MustCompile(`'{2,}|<[^>]*>`)
+	whitespace    = regexp.MustCompile(`\s+`)
+)
+
+// FirstHeadline returns the first news item in a portal page's wikitext as plain text.
+// Items are bulleted, nested under bullets that only link their topic, e.g.
+//
+//	*[[Russian invasion of Ukraine]]
+//	**A drone strike hits a power station in [[Kharkiv]]. [https://example.com (Reuters)]
+//
+// gives "A drone strike hits a power station in Kharkiv".
+func FirstHeadline(wikitext string) string {
+	wikitext = comments.ReplaceAllString(wikitext, "")
+	for _, line := range strings.Split(wikitext, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "*") {
+			continue
+		}
+		item := strings.TrimSpace(strings.TrimLeft(line, "*"))
+		if item == "" || topicLine.MatchString(item) {
+			continue
+		}
+
+		text := references.ReplaceAllString(item, "")
+		text = templates.ReplaceAllString(text, "")
+		text = externalLinks.ReplaceAllString(text, "")
+		text = wikiLinks.ReplaceAllString(text, "$1")
+		text = markup.ReplaceAllString(text, "")
+		text = strings.TrimSpace(whitespace.ReplaceAllString(text, " "))
+		text = strings.TrimRight(text, ". ")
+		if len(strings.Fields(text)) < 3 {
+			continue
+		}
+		return truncate(text, MaxHeadlineLength)
+	}
+	return ""
+}
+
+// truncate shortens text to at most limit runes, at a word boundary with an ellipsis
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	cut := string(runes[:limit-1])
+	if space := strings.LastIndex(cut, " "); space > 0 {
+		cut = cut[:space]
+	}
+	return strings.TrimRight(cut, ",;: ") + "…"
+}
@@ -0,0 +1,132 @@
+package currentevents
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+const portalWikitext = `{{Current events|year=2025|month=09|day=18|content=
+<!-- All news items below this line -->
+'''Armed conflicts and attacks'''
+*[[Russian invasion of Ukraine]]
+**[[Russian strikes against Ukrainian infrastructure (2022–present)|Strikes on infrastructure]]
+***A drone strike hits a power station in [[Kharkiv]], cutting power to '''200,000''' homes.<ref>{{cite web|url=https://example.com}}</ref> [https://example.com/news (Reuters)]
+
+'''Disasters and accidents'''
+*A magnitude 7.8 [[earthquake]] strikes off the coast. [https://example.com/quake (AP)]
+<!-- All news items above this line -->}}`
+
+func TestFirstHeadline(t *testing.T) {
+	want := "A drone strike hits a power station in Kharkiv, cutting power to 200,000 homes"
+	if got := FirstHeadline(portalWikitext); got != want {
+		t.Errorf("FirstHeadline() = %q, want %q", got, want)
+	}
+
+	if got := FirstHeadline("'''Sports'''\n*[[Association football]]\n"); got != "" {
+		t.Errorf("Expected no headline from topic links alone, got %q", got)
+	}
+
+	long := "*" + strings.Repeat("word ", 40)
+	got := FirstHeadline(long)
+	if len([]rune(got)) > MaxHeadlineLength || !strings.HasSuffix(got, "word…") {
+		t.Errorf("Expected a headline cut at a word under %d runes, got %q", MaxHeadlineLength, got)
+	}
+}
+
+func TestPageTitleAndFormat(t *testing.T) {
+	date := time.Date(2025, 9, 8, 0, 0, 0, 0, time.UTC)
+	if got := PageTitle(date); got != "Portal:Current events/2025 September 8" {
+		t.Errorf("PageTitle() = %q", got)
+	}
+	if got := Format(date, "Something happened"); got != "Sep 8: Something happened" {
+		t.Errorf("Format() = %q", got)
+	}
+}
+
+// fakeCache is an in-memory Cache
+type fakeCache struct {
+	items map[string]*state.CurrentEventsHeadline
+	saves int
+}
+
+func (c *fakeCache) GetCurrentEventsHeadline(ctx context.Context, date string) (*state.CurrentEventsHeadline, error) {
+	return c.items[date], nil
+}
+
+func (c *fakeCache) SaveCurrentEventsHeadline(ctx context.Context, date, headline string) error {
+	c.saves++
+	c.items[date] = &state.CurrentEventsHeadline{PostID: date, Headline: headline, FetchedAt: time.Now()}
+	return nil
+}
+
+func TestHeadlineCachesAndFallsBack(t *testing.T) {
+	requests := 0
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("User-Agent") == "" {
+			t.Error("Expected a User-Agent")
+		}
+		if page := r.URL.Query().Get("page"); page != "Portal:Current events/2025 September 18" {
+			t.Errorf("Unexpected page %q", page)
+		}
+		w.WriteHeader(status)
+		var body struct {
+			Parse struct {
+				Wikitext string `json:"wikitext"`
+			} `json:"parse"`
+		}
+		body.Parse.Wikitext = portalWikitext
+		json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	date := time.Date(2025, 9, 18, 0, 0, 0, 0, time.UTC)
+	cache := &fakeCache{items: map[string]*state.CurrentEventsHeadline{}}
+	source := NewSource(server.URL, cache)
+
+	first := source.Headline(context.Background(), date)
+	if !strings.HasPrefix(first, "A drone strike") {
+		t.Fatalf("Unexpected headline %q", first)
+	}
+	if second := source.Headline(context.Background(), date); second != first || requests != 1 {
+		t.Errorf("Expected the cached headline without a second request, got %q after %d requests", second, requests)
+	}
+
+	// A headline cached while the day was still being edited is refreshed after a while
+	cache.items["2025-09-18"].FetchedAt = date.Add(12 * time.Hour)
+	source.now = func() time.Time { return date.Add(24 * time.Hour) }
+	source.Headline(context.Background(), date)
+	if requests != 2 {
+		t.Errorf("Expected an unsettled cache entry to be refetched, got %d requests", requests)
+	}
+
+	// Failures return no headline and aren't cached
+	status = http.StatusServiceUnavailable
+	uncached := NewSource(server.URL, &fakeCache{items: map[string]*state.CurrentEventsHeadline{}})
+	if got := uncached.Headline(context.Background(), date); got != "" {
+		t.Errorf("Expected no headline when Wikipedia fails, got %q", got)
+	}
+	if saves := uncached.cache.(*fakeCache).saves; saves != 0 {
+		t.Errorf("Expected a failed lookup not to be cached, got %d saves", saves)
+	}
+}
+
+func TestFetchMissingPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error": {"code": "missingtitle", "info": "The page you specified doesn't exist."}}`))
+	}))
+	defer server.Close()
+
+	headline, err := NewSource(server.URL, nil).Fetch(context.Background(), time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil || headline != "" {
+		t.Errorf("Fetch(missing page) = %q, %v; want no headline and no error", headline, err)
+	}
+}
@@ -0,0 +1,109 @@
+// Package dailydelta configures when the day-over-day post goes out. The post is checked
+// for every hour, so it is due in the hour starting at its local time.
+package dailydelta
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // Lambda's runtime carries no zoneinfo
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// ParameterName holds the optional JSON settings; when it is absent the post goes out at
+// 08:00 UTC
+//
+//	{"time": "07:30", "timeZone": "Europe/London"}
+const ParameterName = "/hourstats/settings/daily_delta"
+
+// Settings are the local time of the day-over-day post
+type Settings struct {
+	// Time is the local time of day, "HH:MM"
+	Time string `json:"time"`
+	// TimeZone is an IANA time zone name such as "America/New_York"
+	TimeZone string `json:"timeZone"`
+}
+
+// DefaultSettings post each morning at 08:00 UTC
+var DefaultSettings = Settings{Time: "08:00", TimeZone: "UTC"}
+
+// ParameterGetter is the subset of the SSM client Load needs
+type ParameterGetter interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Load reads the settings from SSM. A missing or empty parameter returns DefaultSettings.
+func Load(ctx context.Context, ssmClient ParameterGetter) (Settings, error) {
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ParameterName),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return DefaultSettings, nil
+		}
+		return DefaultSettings, fmt.Errorf("failed to get %s: %w", ParameterName, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return DefaultSettings, nil
+	}
+	return Parse(*result.Parameter.Value)
+}
+
+// Parse decodes and validates JSON settings over DefaultSettings
+func Parse(value string) (Settings, error) {
+	settings := DefaultSettings
+	if strings.TrimSpace(value) == "" {
+		return settings, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&settings); err != nil {
+		return DefaultSettings, fmt.Errorf("invalid daily delta JSON: %w", err)
+	}
+	if err := settings.Validate(); err != nil {
+		return DefaultSettings, fmt.Errorf("invalid daily delta settings: %w", err)
+	}
+	return settings, nil
+}
+
+// Validate checks the time and time zone
+func (s Settings) Validate() error {
+	if _, err := time.Parse("15:04", s.Time); err != nil {
+		return fmt.Errorf("invalid time %q: want HH:MM", s.Time)
+	}
+	if _, err := time.LoadLocation(s.TimeZone); err != nil {
+		return fmt.Errorf("invalid time zone %q: %w", s.TimeZone, err)
+	}
+	return nil
+}
+
+// Location is the settings' time zone, UTC when it is invalid
+func (s Settings) Location() *time.Location {
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Due reports whether now falls in the hour starting at the local post time, so an hourly
+// check posts once a day. A post time skipped by a daylight saving change moves with the
+// clocks.
+func (s Settings) Due(now time.Time) bool {
+	clock, err := time.Parse("15:04", s.Time)
+	if err != nil {
+		return false
+	}
+	local := now.In(s.Location())
+	postTime := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, local.Location())
+	return !local.Before(postTime) && local.Before(postTime.Add(time.Hour))
+}
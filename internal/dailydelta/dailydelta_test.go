@@ -0,0 +1,55 @@
+package dailydelta
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	settings, err := Parse(`{"time": "07:30", "timeZone": "Europe/London"}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if settings.Time != "07:30" || settings.TimeZone != "Europe/London" {
+		t.Errorf("Unexpected settings %+v", settings)
+	}
+
+	if settings, err := Parse(""); err != nil || settings != DefaultSettings {
+		t.Errorf("Expected the default settings for an empty value, got %+v, %v", settings, err)
+	}
+	if settings, err := Parse(`{"time": "06:00"}`); err != nil || settings.TimeZone != "UTC" {
+		t.Errorf("Expected the time zone to default to UTC, got %+v, %v", settings, err)
+	}
+
+	for _, value := range []string{
+		`{"time": "25:00"}`,
+		`{"time": "8am"}`,
+		`{"timeZone": "Mars/Olympus_Mons"}`,
+		`{"hour": 8}`,
+		`not json`,
+	} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("Expected an error for %s", value)
+		}
+	}
+}
+
+func TestDue(t *testing.T) {
+	settings := Settings{Time: "07:30", TimeZone: "Europe/London"}
+	tests := []struct {
+		now  time.Time
+		want bool
+	}{
+		{time.Date(2025, 9, 7, 6, 29, 0, 0, time.UTC), false}, // 07:29 BST
+		{time.Date(2025, 9, 7, 6, 30, 0, 0, time.UTC), true},  // 07:30 BST
+		{time.Date(2025, 9, 7, 7, 10, 0, 0, time.UTC), true},  // 08:10 BST
+		{time.Date(2025, 9, 7, 7, 30, 0, 0, time.UTC), false}, // 08:30 BST
+		{time.Date(2025, 12, 7, 7, 45, 0, 0, time.UTC), true}, // 07:45 GMT
+		{time.Date(2025, 12, 7, 6, 45, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		if got := settings.Due(tt.now); got != tt.want {
+			t.Errorf("Due(%s) = %v, want %v", tt.now.Format(time.RFC3339), got, tt.want)
+		}
+	}
+}
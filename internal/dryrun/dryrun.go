@@ -0,0 +1,175 @@
+// Package dryrun defines how much of a run a dry run holds back. The level is the
+// /hourstats/settings/dry_run parameter, which every Lambda reads as it runs, so a change
+// takes effect on the next invocation. "true" and "false", from when a dry run only
+// skipped posting, still mean no-post and off. A staging deployment, marked by
+// HOURSTATS_STAGE, always runs at Staging whatever the parameter says, since it shares
+// the parameter with production.
+package dryrun
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"gopkg.in/yaml.v3"
+
+	"github.com/christophergentle/hourstats-bsky/internal/credentials"
+)
+
+// Level is how far a dry run goes; the zero value is Off
+type Level string
+
+const (
+	// Off runs normally
+	Off Level = "off"
+	// NoPost computes and stores everything, but posts nothing
+	NoPost Level = "no-post"
+	// NoWrite also skips the DynamoDB writes that outlive the run: sentiment history,
+	// stored analyzed posts and the records of posts. Run state, which hands the run from
+	// one Lambda to the next and expires, is still written.
+	NoWrite Level = "no-write"
+	// Shadow runs normally but posts from the shadow account, leaving the real account
+	// and its pins untouched
+	Shadow Level = "shadow"
+	// Staging posts from the shadow account and makes none of NoWrite's writes, so a
+	// staging deployment can preview changes live while only reading production's data
+	Staging Level = "staging"
+)
+
+// Levels lists every level, for help text and errors
+var Levels = []Level{Off, NoPost, NoWrite, Shadow, Staging}
+
+const (
+	// StageEnv names the environment variable marking a deployment's stage
+	StageEnv = "HOURSTATS_STAGE"
+	// StageStaging is StageEnv's value for a staging deployment
+	StageStaging = "staging"
+)
+
+const (
+	// Parameter holds the level
+	Parameter = "/hourstats/settings/dry_run"
+
+	// ShadowHandleParameter and ShadowPasswordParameter hold the shadow account's credentials
+	ShadowHandleParameter   = "/hourstats/shadow/handle"
+	ShadowPasswordParameter = "/hourstats/shadow/password"
+)
+
+// Parse reads a level, accepting the old boolean values; empty is Off
+func Parse(value string) (Level, error) {
+	switch value = strings.ToLower(strings.TrimSpace(value)); value {
+	case "", "false":
+		return Off, nil
+	case "true":
+		return NoPost, nil
+	}
+	level := Level(value)
+	if err := level.Validate(); err != nil {
+		return "", err
+	}
+	return level, nil
+}
+
+// Validate checks that the level is one of Levels, or empty
+func (l Level) Validate() error {
+	switch l {
+	case "", Off, NoPost, NoWrite, Shadow, Staging:
+		return nil
+	}
+	return fmt.Errorf("unknown dry run level %q, want one of %v", string(l), Levels)
+}
+
+// Posts reports whether summaries and charts are posted, from the shadow account for
+// Shadow and Staging
+func (l Level) Posts() bool {
+	return l != NoPost && l != NoWrite
+}
+
+// Writes reports whether the DynamoDB writes that outlive the run are made
+func (l Level) Writes() bool {
+	return l != NoWrite && l != Staging
+}
+
+// ShadowAccount reports whether posts come from the shadow account rather than the bot's
+func (l Level) ShadowAccount() bool {
+	return l == Shadow || l == Staging
+}
+
+// String returns the level's name, "off" for the zero value
+func (l Level) String() string {
+	if l == "" {
+		return string(Off)
+	}
+	return string(l)
+}
+
+// Set parses a flag's value, so a Level can back a -dry-run flag
+func (l *Level) Set(value string) error {
+	level, err := Parse(value)
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// IsBoolFlag lets a bare -dry-run mean no-post, as it did before there were levels
+func (l *Level) IsBoolFlag() bool {
+	return true
+}
+
+// UnmarshalYAML parses a level from a config file, where dry_run may still be a boolean
+func (l *Level) UnmarshalYAML(node *yaml.Node) error {
+	return l.Set(node.Value)
+}
+
+// Credentials returns the provider a poster should log in with: the shadow account's
+// parameters for Shadow and Staging, else account
+func (l Level) Credentials(account credentials.Provider, ssmClient credentials.ParameterGetter) credentials.Provider {
+	if !l.ShadowAccount() {
+		return account
+	}
+	return credentials.NewSSMProvider(ssmClient, ShadowHandleParameter, ShadowPasswordParameter)
+}
+
+// IsStaging reports whether this process belongs to a staging deployment
+func IsStaging() bool {
+	return os.Getenv(StageEnv) == StageStaging
+}
+
+// ForDeployment returns Staging in a staging deployment, else level
+func ForDeployment(level Level) Level {
+	if IsStaging() {
+		return Staging
+	}
+	return level
+}
+
+// ParameterGetter is the subset of the SSM client Load needs
+type ParameterGetter interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Load reads the level from SSM, or returns Staging in a staging deployment without
+// reading it. The parameter is one of the required settings, so a missing parameter is an
+// error like any other.
+func Load(ctx context.Context, ssmClient ParameterGetter) (Level, error) {
+	if IsStaging() {
+		return Staging, nil
+	}
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(Parameter),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s: %w", Parameter, err)
+	}
+	level, err := Parse(aws.ToString(result.Parameter.Value))
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", Parameter, err)
+	}
+	return level, nil
+}
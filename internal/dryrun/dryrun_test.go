@@ -0,0 +1,130 @@
+package dryrun
+
+import (
+	"context"
+	"flag"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"gopkg.in/yaml.v3"
+)
+
+type fakeParameterGetter struct {
+	value string
+}
+
+func (f fakeParameterGetter) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	if f.value == "" {
+		return nil, &types.ParameterNotFound{}
+	}
+	return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: aws.String(f.value)}}, nil
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Level
+	}{
+		{"", Off},
+		{"false", Off},
+		{"off", Off},
+		{"true", NoPost},
+		{"TRUE", NoPost},
+		{"no-post", NoPost},
+		{" no-write\n", NoWrite},
+		{"shadow", Shadow},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+		}
+	}
+
+	for _, value := range []string{"yes", "nopost", "1"} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", value)
+		}
+	}
+}
+
+func TestLevels(t *testing.T) {
+	tests := []struct {
+		level  Level
+		posts  bool
+		writes bool
+	}{
+		{"", true, true},
+		{Off, true, true},
+		{NoPost, false, true},
+		{NoWrite, false, false},
+		{Shadow, true, true},
+		{Staging, true, false},
+	}
+	for _, tt := range tests {
+		if got := tt.level.Posts(); got != tt.posts {
+			t.Errorf("%s.Posts() = %t, want %t", tt.level, got, tt.posts)
+		}
+		if got := tt.level.Writes(); got != tt.writes {
+			t.Errorf("%s.Writes() = %t, want %t", tt.level, got, tt.writes)
+		}
+	}
+}
+
+func TestFlag(t *testing.T) {
+	tests := []struct {
+		args []string
+		want Level
+	}{
+		{nil, ""},
+		{[]string{"-dry-run"}, NoPost},
+		{[]string{"-dry-run=false"}, Off},
+		{[]string{"-dry-run=shadow"}, Shadow},
+	}
+	for _, tt := range tests {
+		var level Level
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(&level, "dry-run", "")
+		if err := fs.Parse(tt.args); err != nil || level != tt.want {
+			t.Errorf("flags %v = %q, %v, want %q", tt.args, level, err, tt.want)
+		}
+	}
+}
+
+func TestUnmarshalYAML(t *testing.T) {
+	var settings struct {
+		DryRun Level `yaml:"dry_run"`
+	}
+	for value, want := range map[string]Level{"true": NoPost, "false": Off, "no-write": NoWrite} {
+		if err := yaml.Unmarshal([]byte("dry_run: "+value), &settings); err != nil || settings.DryRun != want {
+			t.Errorf("dry_run: %s = %q, %v, want %q", value, settings.DryRun, err, want)
+		}
+	}
+	if err := yaml.Unmarshal([]byte("dry_run: maybe"), &settings); err == nil {
+		t.Error("dry_run: maybe succeeded, want error")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	level, err := Load(context.Background(), fakeParameterGetter{value: "true"})
+	if err != nil || level != NoPost {
+		t.Errorf("Load(true) = %q, %v, want no-post", level, err)
+	}
+	if _, err := Load(context.Background(), fakeParameterGetter{}); err == nil {
+		t.Error("Load(missing) succeeded, want error")
+	}
+	if _, err := Load(context.Background(), fakeParameterGetter{value: "sometimes"}); err == nil {
+		t.Error("Load(sometimes) succeeded, want error")
+	}
+
+	// A staging deployment ignores the shared parameter
+	t.Setenv(StageEnv, StageStaging)
+	if level, err := Load(context.Background(), fakeParameterGetter{}); err != nil || level != Staging {
+		t.Errorf("Load() in staging = %q, %v, want staging", level, err)
+	}
+	if level := ForDeployment(Off); level != Staging {
+		t.Errorf("ForDeployment(off) in staging = %q, want staging", level)
+	}
+}
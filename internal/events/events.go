@@ -0,0 +1,139 @@
+// Package events defines the payloads the lambdas invoke each other with. Each event has a
+// JSON Schema in schemas/ that payloads are checked against when they are sent and again
+// when they are received, so a sender and receiver that disagree fail on the first
+// invocation instead of running with zero values.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+)
+
+// Version is the contract version senders stamp on events. Events without a version,
+// sent before events were versioned, are read as version 1; a receiver rejects versions
+// newer than its schemas, so a partial deploy fails rather than misreading an event.
+const Version = 1
+
+// The lambdas events are sent to, each overridden by the environment variable beside it
+const (
+	FetcherFunction         = "hourstats-fetcher"
+	FetcherFunctionEnv      = "FETCHER_FUNCTION"
+	ProcessorFunction       = "hourstats-processor"
+	ProcessorFunctionEnv    = "PROCESSOR_FUNCTION"
+	SparklinePosterFunction = "hourstats-sparkline-poster"
+	SparklinePosterEnv      = "SPARKLINE_POSTER_FUNCTION"
+)
+
+// FunctionName returns the lambda named by the environment variable env, or name when it
+// is unset, so a staging deployment's lambdas invoke each other rather than production's
+func FunctionName(env, name string) string {
+	if function := os.Getenv(env); function != "" {
+		return function
+	}
+	return name
+}
+
+// Event is a payload with a schema
+type Event interface {
+	schemaName() string
+}
+
+// FetcherEvent starts or resumes fetching a run's posts
+type FetcherEvent struct {
+	Version                 int    `json:"version,omitempty"`
+	RunID                   string `json:"runId"`
+	AnalysisIntervalMinutes int    `json:"analysisIntervalMinutes"`
+	Status                  string `json:"status,omitempty"`
+	Resume                  bool   `json:"resume,omitempty"`
+	Invocation              int    `json:"invocation,omitempty"`
+}
+
+func (FetcherEvent) schemaName() string { return "fetcher" }
+
+// ProcessorEvent analyzes a fetched run's posts and posts its summary
+type ProcessorEvent struct {
+	Version                 int    `json:"version,omitempty"`
+	RunID                   string `json:"runId"`
+	AnalysisIntervalMinutes int    `json:"analysisIntervalMinutes"`
+	Status                  string `json:"status,omitempty"`
+	// Replay re-posts a run whose summary was never posted, marked as delayed (see cmd/replay)
+	Replay bool `json:"replay,omitempty"`
+	// Reprocess re-analyzes the run's stored posts with different parameters, storing the
+	// result beside the run without posting it or changing the run's own results
+	Reprocess *Reprocess `json:"reprocess,omitempty"`
+}
+
+// Reprocess overrides the processor's analysis parameters; zero values keep the defaults
+type Reprocess struct {
+	// Label names the result; reprocessing again with the same label replaces it
+	Label              string  `json:"label"`
+	TopPosts           int     `json:"topPosts,omitempty"`
+	MediaRanking       string  `json:"mediaRanking,omitempty"`
+	ReplyRanking       string  `json:"replyRanking,omitempty"`
+	ContentWarnings    string  `json:"contentWarnings,omitempty"`
+	TopPostsRanking    string  `json:"topPostsRanking,omitempty"`
+	SentimentThreshold float64 `json:"sentimentThreshold,omitempty"`
+	MinPostCount       int     `json:"minPostCount,omitempty"`
+	MinTopicPosts      int     `json:"minTopicPosts,omitempty"`
+	// SentimentMethod and Trim choose how scores are averaged, see aggregation.Method;
+	// unset, the method the run recorded is used
+	SentimentMethod string  `json:"sentimentMethod,omitempty"`
+	Trim            float64 `json:"trim,omitempty"`
+	// Reanalyze scores every post again instead of reusing the fetcher's analysis, to
+	// compare a changed analyzer against the original results
+	Reanalyze bool `json:"reanalyze,omitempty"`
+}
+
+func (ProcessorEvent) schemaName() string { return "processor" }
+
+// RunEvent works on an analyzed run; it is the sparkline poster's event and the one the
+// legacy Step Functions stages share
+type RunEvent struct {
+	Version                 int    `json:"version,omitempty"`
+	RunID                   string `json:"runId"`
+	AnalysisIntervalMinutes int    `json:"analysisIntervalMinutes"`
+	Status                  string `json:"status,omitempty"`
+}
+
+func (RunEvent) schemaName() string { return "run" }
+
+// Marshal encodes event for sending, checking it against its schema first
+func Marshal(event Event) ([]byte, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s event: %w", event.schemaName(), err)
+	}
+	if err := validate(event.schemaName(), payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// Decode checks payload against E's schema and decodes it
+func Decode[E Event](payload []byte) (E, error) {
+	var event E
+	if err := validate(event.schemaName(), payload); err != nil {
+		return event, err
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return event, fmt.Errorf("failed to unmarshal %s event: %w", event.schemaName(), err)
+	}
+	return event, nil
+}
+
+// Handler adapts a lambda handler to receive the raw payload, decoding it with Decode.
+// An invalid payload fails the invocation before the handler runs.
+func Handler[E Event, R any](handle func(context.Context, E) (R, error)) func(context.Context, json.RawMessage) (R, error) {
+	return func(ctx context.Context, payload json.RawMessage) (R, error) {
+		event, err := Decode[E](payload)
+		if err != nil {
+			log.Printf("❌ Rejected event %s: %v", string(payload), err)
+			var response R
+			return response, err
+		}
+		return handle(ctx, event)
+	}
+}
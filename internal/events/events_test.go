@@ -0,0 +1,104 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	for _, tc := range []struct {
+		payload string
+		problem string // in the error, or "" for a valid payload
+	}{
+		{`{"runId": "run-1", "analysisIntervalMinutes": 30}`, ""},
+		{`{"version": 1, "runId": "run-1", "analysisIntervalMinutes": 30, "status": "fetching", "resume": true, "invocation": 2}`, ""},
+		{`{"runId": "run-1"}`, "analysisIntervalMinutes is required"},
+		{`{"runId": "", "analysisIntervalMinutes": 30}`, "runId must not be empty"},
+		{`{"runId": "run-1", "analysisIntervalMinutes": "30"}`, "analysisIntervalMinutes must be an integer"},
+		{`{"runId": "run-1", "analysisIntervalMinutes": 30.5}`, "analysisIntervalMinutes must be an integer"},
+		{`{"runId": "run-1", "analysisIntervalMinutes": 30, "maxIterations": 30}`, "maxIterations is not a known field"},
+		{`{"version": 2, "runId": "run-1", "analysisIntervalMinutes": 30}`, "version must be at most 1"},
+		{`{"resume": "yes"}`, "runId is required; analysisIntervalMinutes is required; resume must be a boolean"},
+		{`["run-1"]`, "must be a JSON object"},
+	} {
+		event, err := Decode[FetcherEvent]([]byte(tc.payload))
+		if tc.problem == "" {
+			if err != nil || event.RunID != "run-1" || event.AnalysisIntervalMinutes != 30 {
+				t.Errorf("Decode(%s) = %+v, %v; want the decoded event", tc.payload, event, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), tc.problem) {
+			t.Errorf("Decode(%s) error = %v, want %q", tc.payload, err, tc.problem)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	payload, err := Marshal(ProcessorEvent{Version: Version, RunID: "run-1", AnalysisIntervalMinutes: 30, Replay: true})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	event, err := Decode[ProcessorEvent](payload)
+	if err != nil || !event.Replay || event.Version != Version {
+		t.Errorf("Expected the event to round trip, got %+v, %v", event, err)
+	}
+
+	if _, err := Marshal(RunEvent{RunID: "run-1"}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Expected a sparkline event without an interval to be rejected before sending, got %v", err)
+	}
+}
+
+func TestDecodeReprocess(t *testing.T) {
+	event, err := Decode[ProcessorEvent]([]byte(`{"runId": "run-1", "analysisIntervalMinutes": 30, "reprocess": {"label": "boost", "mediaRanking": "boost", "sentimentThreshold": 0.2}}`))
+	if err != nil || event.Reprocess == nil || event.Reprocess.Label != "boost" || event.Reprocess.SentimentThreshold != 0.2 {
+		t.Fatalf("Expected the reprocess parameters, got %+v, %v", event.Reprocess, err)
+	}
+	event, err = Decode[ProcessorEvent]([]byte(`{"runId": "run-1", "analysisIntervalMinutes": 30, "reprocess": {"label": "trim", "sentimentMethod": "trimmedMean", "trim": 0.2}}`))
+	if err != nil || event.Reprocess.SentimentMethod != "trimmedMean" || event.Reprocess.Trim != 0.2 {
+		t.Fatalf("Expected the sentiment method, got %+v, %v", event.Reprocess, err)
+	}
+
+	for payload, problem := range map[string]string{
+		`{"runId": "run-1", "analysisIntervalMinutes": 30, "reprocess": true}`:                                        "reprocess must be an object",
+		`{"runId": "run-1", "analysisIntervalMinutes": 30, "reprocess": {"topPosts": 3}}`:                             "reprocess.label is required",
+		`{"runId": "run-1", "analysisIntervalMinutes": 30, "reprocess": {"label": "a", "mediaRanking": "loud"}}`:      "reprocess.mediaRanking must be one of neutral, boost, exclude",
+		`{"runId": "run-1", "analysisIntervalMinutes": 30, "reprocess": {"label": "a", "sentimentThreshold": "0.2"}}`: "reprocess.sentimentThreshold must be a number",
+		`{"runId": "run-1", "analysisIntervalMinutes": 30, "reprocess": {"label": "a", "sentimentThreshold": 1.5}}`:   "reprocess.sentimentThreshold must be at most 1",
+		`{"runId": "run-1", "analysisIntervalMinutes": 30, "reprocess": {"label": "a", "threshold": 0.2}}`:            "reprocess.threshold is not a known field",
+		`{"runId": "run-1", "analysisIntervalMinutes": 30, "reprocess": {"label": "a", "sentimentMethod": "mode"}}`:   "reprocess.sentimentMethod must be one of mean, trimmedMean, median",
+		`{"runId": "run-1", "analysisIntervalMinutes": 30, "reprocess": {"label": "a", "trim": 0.6}}`:                 "reprocess.trim must be at most 0.45",
+	} {
+		if _, err := Decode[ProcessorEvent]([]byte(payload)); !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), problem) {
+			t.Errorf("Decode(%s) error = %v, want %q", payload, err, problem)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	called := false
+	handle := Handler(func(ctx context.Context, event RunEvent) (string, error) {
+		called = true
+		return event.RunID, nil
+	})
+
+	if _, err := handle(context.Background(), json.RawMessage(`{"runId": "run-1"}`)); !errors.Is(err, ErrInvalid) || called {
+		t.Errorf("Expected an invalid event to fail without calling the handler, got %v (called %v)", err, called)
+	}
+	if runID, err := handle(context.Background(), json.RawMessage(`{"runId": "run-1", "analysisIntervalMinutes": 30}`)); err != nil || runID != "run-1" {
+		t.Errorf("handle() = %q, %v; want the handler's response", runID, err)
+	}
+}
+
+func TestFunctionName(t *testing.T) {
+	if got := FunctionName(FetcherFunctionEnv, FetcherFunction); got != FetcherFunction {
+		t.Errorf("FunctionName() = %q, want %q", got, FetcherFunction)
+	}
+	t.Setenv(FetcherFunctionEnv, "hourstats-staging-fetcher")
+	if got := FunctionName(FetcherFunctionEnv, FetcherFunction); got != "hourstats-staging-fetcher" {
+		t.Errorf("FunctionName() = %q, want the environment's function", got)
+	}
+}
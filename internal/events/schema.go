@@ -0,0 +1,179 @@
+package events
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//go:embed schemas/*.json
+var schemaFiles embed.FS
+
+// ErrInvalid is wrapped by every error for a payload that doesn't match its schema
+var ErrInvalid = errors.New("invalid event")
+
+// schema is the subset of JSON Schema the event schemas use: an object of typed
+// properties, some required, with no others allowed. Properties may themselves be objects.
+type schema struct {
+	Title string `json:"title"`
+	property
+}
+
+type property struct {
+	Type                 string              `json:"type"`
+	Enum                 []string            `json:"enum"`
+	Minimum              *float64            `json:"minimum"`
+	Maximum              *float64            `json:"maximum"`
+	MinLength            int                 `json:"minLength"`
+	Properties           map[string]property `json:"properties"`
+	Required             []string            `json:"required"`
+	AdditionalProperties *bool               `json:"additionalProperties"`
+}
+
+var schemas = loadSchemas()
+
+func loadSchemas() map[string]schema {
+	entries, err := schemaFiles.ReadDir("schemas")
+	if err != nil {
+		panic(fmt.Sprintf("failed to read event schemas: %v", err))
+	}
+	loaded := make(map[string]schema, len(entries))
+	for _, entry := range entries {
+		data, err := schemaFiles.ReadFile("schemas/" + entry.Name())
+		if err != nil {
+			panic(fmt.Sprintf("failed to read event schema %s: %v", entry.Name(), err))
+		}
+		var s schema
+		if err := json.Unmarshal(data, &s); err != nil {
+			panic(fmt.Sprintf("invalid event schema %s: %v", entry.Name(), err))
+		}
+		loaded[strings.TrimSuffix(entry.Name(), ".json")] = s
+	}
+	return loaded
+}
+
+// validate checks payload against the named schema, reporting every mismatch at once
+func validate(name string, payload []byte) error {
+	s, ok := schemas[name]
+	if !ok {
+		return fmt.Errorf("no schema for %s events", name)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil || fields == nil {
+		return fmt.Errorf("%w: %s must be a JSON object", ErrInvalid, s.Title)
+	}
+
+	if problems := s.checkObject("", fields); len(problems) > 0 {
+		return fmt.Errorf("%w: %s: %s", ErrInvalid, s.Title, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// checkObject returns what is wrong with each of an object's fields, naming nested fields
+// by their path from the event, e.g. "reprocess.label"
+func (p property) checkObject(path string, fields map[string]interface{}) []string {
+	var problems []string
+	for _, key := range p.Required {
+		if _, ok := fields[key]; !ok {
+			problems = append(problems, path+key+" is required")
+		}
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		field, ok := p.Properties[key]
+		if !ok {
+			if p.AdditionalProperties != nil && !*p.AdditionalProperties {
+				problems = append(problems, path+key+" is not a known field")
+			}
+			continue
+		}
+		if field.Type == "object" {
+			nested, ok := fields[key].(map[string]interface{})
+			if !ok {
+				problems = append(problems, path+key+" must be an object")
+				continue
+			}
+			problems = append(problems, field.checkObject(path+key+".", nested)...)
+			continue
+		}
+		if problem := field.check(fields[key]); problem != "" {
+			problems = append(problems, path+key+" "+problem)
+		}
+	}
+	return problems
+}
+
+// check returns what is wrong with a scalar value, or "" if it matches
+func (p property) check(value interface{}) string {
+	switch p.Type {
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return "must be a string"
+		}
+		if p.MinLength == 1 && text == "" {
+			return "must not be empty"
+		}
+		if len(text) < p.MinLength {
+			return fmt.Sprintf("must be at least %d characters", p.MinLength)
+		}
+		if len(p.Enum) > 0 && !contains(p.Enum, text) {
+			return "must be one of " + strings.Join(p.Enum, ", ")
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return "must be a boolean"
+		}
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return "must be an integer"
+		}
+		n, err := number.Int64()
+		if err != nil {
+			return "must be an integer"
+		}
+		return p.checkRange(float64(n))
+	case "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			return "must be a number"
+		}
+		n, err := number.Float64()
+		if err != nil {
+			return "must be a number"
+		}
+		return p.checkRange(n)
+	}
+	return ""
+}
+
+func (p property) checkRange(n float64) string {
+	if p.Minimum != nil && n < *p.Minimum {
+		return fmt.Sprintf("must be at least %g", *p.Minimum)
+	}
+	if p.Maximum != nil && n > *p.Maximum {
+		return fmt.Sprintf("must be at most %g", *p.Maximum)
+	}
+	return ""
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+// Package experiments A/B tests the layout of the hourly summary. While an experiment is
+// configured, each run's summary is posted in one of its variants, picked at random but
+// seeded by the run ID so a retried run keeps its variant, and the weekly report compares
+// engagement on the bot's own posts per variant. It is configured by an optional setting
+// stored in SSM.
+package experiments
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/formatter"
+)
+
+// ParameterName holds the JSON experiment; when it is absent every summary uses the
+// classic layout
+const ParameterName = "/hourstats/settings/experiment"
+
+// Experiment compares summary layouts; the first variant is the control the others are
+// measured against
+type Experiment struct {
+	Name     string               `json:"name"`
+	Variants []formatter.Template `json:"variants"`
+}
+
+// ParameterGetter is the subset of the SSM client Load needs
+type ParameterGetter interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Load reads the running experiment from SSM. A missing or empty parameter returns nil,
+// meaning no experiment is running.
+func Load(ctx context.Context, ssmClient ParameterGetter) (*Experiment, error) {
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ParameterName),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %s: %w", ParameterName, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return nil, nil
+	}
+	return Parse(*result.Parameter.Value)
+}
+
+// Parse decodes and validates a JSON experiment
+func Parse(value string) (*Experiment, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var experiment Experiment
+	if err := json.Unmarshal([]byte(value), &experiment); err != nil {
+		return nil, fmt.Errorf("invalid experiment JSON: %w", err)
+	}
+	if experiment.Name == "" || strings.Contains(experiment.Name, "#") {
+		return nil, fmt.Errorf("experiment name %q must be set and must not contain #", experiment.Name)
+	}
+	if len(experiment.Variants) < 2 {
+		return nil, fmt.Errorf("experiment %s needs at least two variants, got %d", experiment.Name, len(experiment.Variants))
+	}
+	seen := make(map[formatter.Template]bool, len(experiment.Variants))
+	for _, variant := range experiment.Variants {
+		if !isTemplate(variant) {
+			return nil, fmt.Errorf("experiment %s has unknown variant %q", experiment.Name, variant)
+		}
+		if seen[variant] {
+			return nil, fmt.Errorf("experiment %s lists variant %q twice", experiment.Name, variant)
+		}
+		seen[variant] = true
+	}
+	return &experiment, nil
+}
+
+func isTemplate(variant formatter.Template) bool {
+	for _, template := range formatter.Templates {
+		if variant == template {
+			return true
+		}
+	}
+	return false
+}
+
+// Assign picks the run's variant, each with equal chance. The pick depends only on the
+// experiment name and run ID, so a retried run posts the same variant.
+func (e *Experiment) Assign(runID string) formatter.Template {
+	h := fnv.New64a()
+	h.Write([]byte(e.Name + "#" + runID))
+	return e.Variants[h.Sum64()%uint64(len(e.Variants))]
+}
+
+// Result is the engagement one experiment post has received
+type Result struct {
+	Variant    formatter.Template
+	Engagement client.Engagement
+}
+
+// VariantSummary is a variant's mean engagement per post
+type VariantSummary struct {
+	Variant formatter.Template
+	Posts   int
+	Likes   float64
+	Reposts float64
+	Replies float64
+	Quotes  float64
+	Total   float64
+	// StdErr is the standard error of Total, to judge whether a difference is noise
+	StdErr float64
+	// Lift is Total relative to the control's, in percent; zero for the control itself
+	// or when the control has no engagement
+	Lift float64
+}
+
+// Compare summarizes results per variant, in the experiment's order; variants without
+// results are included with no posts
+func (e *Experiment) Compare(results []Result) []VariantSummary {
+	summaries := make([]VariantSummary, len(e.Variants))
+	totals := make([][]float64, len(e.Variants))
+	index := make(map[formatter.Template]int, len(e.Variants))
+	for i, variant := range e.Variants {
+		summaries[i].Variant = variant
+		index[variant] = i
+	}
+
+	for _, result := range results {
+		i, ok := index[result.Variant]
+		if !ok {
+			continue
+		}
+		s := &summaries[i]
+		s.Posts++
+		s.Likes += float64(result.Engagement.Likes)
+		s.Reposts += float64(result.Engagement.Reposts)
+		s.Replies += float64(result.Engagement.Replies)
+		s.Quotes += float64(result.Engagement.Quotes)
+		totals[i] = append(totals[i], float64(result.Engagement.Total()))
+	}
+
+	for i := range summaries {
+		s := &summaries[i]
+		if s.Posts == 0 {
+			continue
+		}
+		n := float64(s.Posts)
+		s.Likes /= n
+		s.Reposts /= n
+		s.Replies /= n
+		s.Quotes /= n
+		s.Total = s.Likes + s.Reposts + s.Replies + s.Quotes
+		if s.Posts > 1 {
+			var squares float64
+			for _, total := range totals[i] {
+				squares += (total - s.Total) * (total - s.Total)
+			}
+			s.StdErr = math.Sqrt(squares/(n-1)) / math.Sqrt(n)
+		}
+	}
+
+	control := summaries[0].Total
+	if control > 0 {
+		for i := 1; i < len(summaries); i++ {
+			if summaries[i].Posts > 0 {
+				summaries[i].Lift = (summaries[i].Total - control) / control * 100
+			}
+		}
+	}
+	return summaries
+}
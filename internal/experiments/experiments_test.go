@@ -0,0 +1,73 @@
+package experiments
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/formatter"
+)
+
+func TestParse(t *testing.T) {
+	experiment, err := Parse(`{"name": "headline", "variants": ["classic", "score-first"]}`)
+	if err != nil || experiment.Name != "headline" || len(experiment.Variants) != 2 {
+		t.Errorf("Expected the experiment, got %+v, %v", experiment, err)
+	}
+	if experiment, err := Parse(""); err != nil || experiment != nil {
+		t.Errorf("Parse(empty) = %+v, %v; want nil", experiment, err)
+	}
+	for name, value := range map[string]string{
+		"no name":           `{"variants": ["classic", "emoji"]}`,
+		"name with #":       `{"name": "a#b", "variants": ["classic", "emoji"]}`,
+		"one variant":       `{"name": "headline", "variants": ["classic"]}`,
+		"unknown variant":   `{"name": "headline", "variants": ["classic", "fancy"]}`,
+		"duplicate variant": `{"name": "headline", "variants": ["classic", "classic"]}`,
+		"invalid JSON":      `{"name": `,
+	} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestAssign(t *testing.T) {
+	experiment := &Experiment{Name: "markers", Variants: []formatter.Template{formatter.TemplateClassic, formatter.TemplateEmoji}}
+
+	counts := make(map[formatter.Template]int)
+	for i := 0; i < 1000; i++ {
+		runID := fmt.Sprintf("run-%d", i)
+		variant := experiment.Assign(runID)
+		if again := experiment.Assign(runID); again != variant {
+			t.Fatalf("Expected %s to keep variant %s, got %s", runID, variant, again)
+		}
+		counts[variant]++
+	}
+	if counts[formatter.TemplateClassic] < 400 || counts[formatter.TemplateEmoji] < 400 {
+		t.Errorf("Expected roughly even assignment, got %v", counts)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	experiment := &Experiment{Name: "markers", Variants: []formatter.Template{formatter.TemplateClassic, formatter.TemplateEmoji, formatter.TemplateScoreFirst}}
+	summaries := experiment.Compare([]Result{
+		{Variant: formatter.TemplateClassic, Engagement: client.Engagement{Likes: 8, Reposts: 2}},
+		{Variant: formatter.TemplateClassic, Engagement: client.Engagement{Likes: 4, Replies: 2}},
+		{Variant: formatter.TemplateEmoji, Engagement: client.Engagement{Likes: 10, Quotes: 2}},
+		{Variant: "retired", Engagement: client.Engagement{Likes: 100}},
+	})
+
+	if len(summaries) != 3 {
+		t.Fatalf("Expected one summary per variant, got %d", len(summaries))
+	}
+	control, emoji, scoreFirst := summaries[0], summaries[1], summaries[2]
+	if control.Posts != 2 || control.Total != 8 || control.Likes != 6 || math.Abs(control.StdErr-2) > 1e-9 {
+		t.Errorf("Expected the control's means, got %+v", control)
+	}
+	if emoji.Posts != 1 || emoji.Total != 12 || emoji.Lift != 50 || emoji.StdErr != 0 {
+		t.Errorf("Expected a 50%% lift for emoji, got %+v", emoji)
+	}
+	if scoreFirst.Posts != 0 || scoreFirst.Lift != 0 {
+		t.Errorf("Expected no results for score-first, got %+v", scoreFirst)
+	}
+}
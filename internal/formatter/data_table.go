@@ -0,0 +1,35 @@
+package formatter
+
+import "fmt"
+
+// maxPostGraphemes is Bluesky's post length limit
+const maxPostGraphemes = 300
+
+// TableRow is one labelled value from a chart
+type TableRow struct {
+	Label string
+	Value float64 // Net sentiment percentage
+}
+
+// FormatDataTable renders chart values as "label: +12.3%" lines for screen-reader users,
+// split into as many posts as needed to stay within Bluesky's length limit.
+// The title opens the first post; later posts are marked as continuations.
+func FormatDataTable(title string, rows []TableRow) []string {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var posts []string
+	current := title
+	for _, row := range rows {
+		line := fmt.Sprintf("%s: %+.1f%%", row.Label, row.Value)
+		if len([]rune(current))+1+len([]rune(line)) > maxPostGraphemes {
+			posts = append(posts, current)
+			current = "(cont.)"
+		}
+		current += "\n" + line
+	}
+	posts = append(posts, current)
+
+	return posts
+}
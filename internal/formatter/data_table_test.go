@@ -0,0 +1,52 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFormatDataTable(t *testing.T) {
+	rows := []TableRow{{Label: "Mon Mar 24", Value: 12.34}, {Label: "Tue Mar 25", Value: -3.0}}
+	posts := FormatDataTable("Chart data", rows)
+
+	if len(posts) != 1 {
+		t.Fatalf("Expected 1 post, got %d", len(posts))
+	}
+	want := "Chart data\nMon Mar 24: +12.3%\nTue Mar 25: -3.0%"
+	if posts[0] != want {
+		t.Errorf("Expected %q, got %q", want, posts[0])
+	}
+}
+
+func TestFormatDataTableSplitsLongTables(t *testing.T) {
+	var rows []TableRow
+	for i := 0; i < 60; i++ {
+		rows = append(rows, TableRow{Label: fmt.Sprintf("Day %02d", i), Value: float64(i)})
+	}
+
+	posts := FormatDataTable("Chart data", rows)
+	if len(posts) < 2 {
+		t.Fatalf("Expected the table to span several posts, got %d", len(posts))
+	}
+
+	lines := 0
+	for i, post := range posts {
+		if n := len([]rune(post)); n > maxPostGraphemes {
+			t.Errorf("Post %d is %d graphemes, over the %d limit", i, n, maxPostGraphemes)
+		}
+		if i > 0 && !strings.HasPrefix(post, "(cont.)") {
+			t.Errorf("Post %d should be marked as a continuation: %q", i, post)
+		}
+		lines += strings.Count(post, "\n")
+	}
+	if lines != len(rows) {
+		t.Errorf("Expected %d rows across all posts, got %d", len(rows), lines)
+	}
+}
+
+func TestFormatDataTableEmpty(t *testing.T) {
+	if posts := FormatDataTable("Chart data", nil); posts != nil {
+		t.Errorf("Expected no posts for an empty table, got %v", posts)
+	}
+}
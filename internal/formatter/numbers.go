@@ -0,0 +1,107 @@
+package formatter
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NumberFormat is a locale's digit grouping and decimal separators
+type NumberFormat struct {
+	Locale  string
+	Group   string // between groups of three digits, e.g. "," in 12,400
+	Decimal string // before the fraction, e.g. "." in 12.4k
+}
+
+// NumberFormats are the supported locales; "en" is the default
+var NumberFormats = map[string]NumberFormat{
+	"en": {Locale: "en", Group: ",", Decimal: "."},
+	"ja": {Locale: "ja", Group: ",", Decimal: "."},
+	"de": {Locale: "de", Group: ".", Decimal: ","},
+	"es": {Locale: "es", Group: ".", Decimal: ","},
+	"it": {Locale: "it", Group: ".", Decimal: ","},
+	"nl": {Locale: "nl", Group: ".", Decimal: ","},
+	"pt": {Locale: "pt", Group: ".", Decimal: ","},
+	"fr": {Locale: "fr", Group: "\u202f", Decimal: ","}, // narrow no-break space
+}
+
+// DefaultNumberFormat groups digits with commas, as the posts always have
+var DefaultNumberFormat = NumberFormats["en"]
+
+// ParseNumberFormat returns the number format of locale, e.g. "de" or "pt-BR"; a region is
+// ignored and an empty locale is the default
+func ParseNumberFormat(locale string) (NumberFormat, error) {
+	language, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(locale)), "-")
+	if language == "" {
+		return DefaultNumberFormat, nil
+	}
+	format, ok := NumberFormats[language]
+	if !ok {
+		known := make([]string, 0, len(NumberFormats))
+		for name := range NumberFormats {
+			known = append(known, name)
+		}
+		sort.Strings(known)
+		return DefaultNumberFormat, fmt.Errorf("unknown number locale %q, want one of %s", locale, strings.Join(known, ", "))
+	}
+	return format, nil
+}
+
+// Count formats n in full with grouped digits, e.g. 12,400
+func (f NumberFormat) Count(n int) string {
+	if n < 0 {
+		return "-" + f.Count(-n)
+	}
+	digits := strconv.Itoa(n)
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(f.Group)
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
+
+// compactUnits are the suffixes of Compact, smallest first
+var compactUnits = []struct {
+	size   float64
+	suffix string
+}{
+	{1e3, "k"},
+	{1e6, "M"},
+	{1e9, "B"},
+}
+
+// Compact formats n in at most three digits and a suffix, e.g. 950, 1.2k, 12.4k, 124k or
+// 1.2M. One decimal place is kept below 100 of a unit, and dropped when it is zero.
+func (f NumberFormat) Compact(n int) string {
+	if n < 0 {
+		return "-" + f.Compact(-n)
+	}
+	if n < 1000 {
+		return strconv.Itoa(n)
+	}
+	unit := 0
+	for unit+1 < len(compactUnits) && float64(n) >= compactUnits[unit+1].size {
+		unit++
+	}
+	scaled := compactRound(float64(n) / compactUnits[unit].size)
+	// 999,950 rounds to 1000k, which is written as the next unit
+	if scaled >= 1000 && unit+1 < len(compactUnits) {
+		unit++
+		scaled = compactRound(float64(n) / compactUnits[unit].size)
+	}
+	text := strings.TrimSuffix(strconv.FormatFloat(scaled, 'f', 1, 64), ".0")
+	return strings.Replace(text, ".", f.Decimal, 1) + compactUnits[unit].suffix
+}
+
+// compactRound keeps one decimal place below 100 and none from 100 up
+func compactRound(value float64) float64 {
+	if value >= 99.95 {
+		return math.Round(value)
+	}
+	return math.Round(value*10) / 10
+}
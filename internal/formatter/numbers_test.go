@@ -0,0 +1,63 @@
+package formatter
+
+import "testing"
+
+func TestNumberFormatCount(t *testing.T) {
+	de, _ := ParseNumberFormat("de")
+	fr, _ := ParseNumberFormat("fr-CA")
+	tests := []struct {
+		format NumberFormat
+		n      int
+		want   string
+	}{
+		{DefaultNumberFormat, 0, "0"},
+		{DefaultNumberFormat, 999, "999"},
+		{DefaultNumberFormat, 12400, "12,400"},
+		{DefaultNumberFormat, -1234567, "-1,234,567"},
+		{de, 1234567, "1.234.567"},
+		{fr, 12400, "12\u202f400"},
+	}
+	for _, tt := range tests {
+		if got := tt.format.Count(tt.n); got != tt.want {
+			t.Errorf("%s Count(%d) = %q, want %q", tt.format.Locale, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestNumberFormatCompact(t *testing.T) {
+	de, _ := ParseNumberFormat("de")
+	tests := []struct {
+		format NumberFormat
+		n      int
+		want   string
+	}{
+		{DefaultNumberFormat, 950, "950"},
+		{DefaultNumberFormat, 1000, "1k"},
+		{DefaultNumberFormat, 1234, "1.2k"},
+		{DefaultNumberFormat, 12400, "12.4k"},
+		{DefaultNumberFormat, 99960, "100k"},
+		{DefaultNumberFormat, 124499, "124k"},
+		{DefaultNumberFormat, 999950, "1M"},
+		{DefaultNumberFormat, 1250000, "1.3M"},
+		{DefaultNumberFormat, 2000000000, "2B"},
+		{DefaultNumberFormat, -12400, "-12.4k"},
+		{de, 12400, "12,4k"},
+	}
+	for _, tt := range tests {
+		if got := tt.format.Compact(tt.n); got != tt.want {
+			t.Errorf("%s Compact(%d) = %q, want %q", tt.format.Locale, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestParseNumberFormat(t *testing.T) {
+	if format, err := ParseNumberFormat(""); err != nil || format != DefaultNumberFormat {
+		t.Errorf("Expected the default format for an empty locale, got %+v, %v", format, err)
+	}
+	if format, err := ParseNumberFormat("PT-br"); err != nil || format.Locale != "pt" {
+		t.Errorf("Expected pt for PT-br, got %+v, %v", format, err)
+	}
+	if _, err := ParseNumberFormat("tlh"); err == nil {
+		t.Error("Expected an error for an unknown locale")
+	}
+}
@@ -2,6 +2,12 @@ package formatter
 
 import (
 	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/christophergentle/hourstats-bsky/internal/insight"
+	"github.com/christophergentle/hourstats-bsky/internal/interval"
 )
 
 // Post represents a post for formatting
@@ -16,8 +22,108 @@ type Post struct {
 	EngagementScore float64
 }
 
+// Span marks a range of formatted post text that should become a richtext facet
+type Span struct {
+	Start int // UTF-8 byte offset of the first byte
+	End   int // UTF-8 byte offset just past the last byte
+
+	Tag     string // hashtag without the #, for tag spans
+	Handle  string // author handle without the @, for mention spans
+	PostURI string // at:// URI of the author's post, which names their DID
+}
+
+// PostTags are the hashtags closing every summary, when they fit
+var PostTags = []string{"BlueskySentiment", "hourstats"}
+
+// MaxPostLength is Bluesky's post limit; the closing tags are left off rather than
+// pushing a summary over it
+const MaxPostLength = 300
+
+// Template is a layout of the summary post; experiments assign one to each summary
+type Template string
+
+// Summary templates
+const (
+	// TemplateClassic leads with the mood hashtag and marks top posts +, - or x
+	TemplateClassic Template = "classic"
+	// TemplateScoreFirst leads with the net sentiment and follows it with the mood hashtag
+	TemplateScoreFirst Template = "score-first"
+	// TemplateEmoji marks top posts with coloured circles instead of +, - or x
+	TemplateEmoji Template = "emoji"
+)
+
+// Templates lists every summary template
+var Templates = []Template{TemplateClassic, TemplateScoreFirst, TemplateEmoji}
+
 // FormatPostContent generates the post content that will be posted to Bluesky
-func FormatPostContent(topPosts []Post, overallSentiment string, analysisIntervalMinutes int, totalPosts int, averageCompoundScore float64) string {
+// Optional notes are appended as trailing lines; empty notes are skipped
+func FormatPostContent(topPosts []Post, overallSentiment string, analysisIntervalMinutes int, totalPosts int, averageCompoundScore float64, notes ...string) string {
+	content, _ := FormatPostContentWithSpans(topPosts, overallSentiment, analysisIntervalMinutes, totalPosts, averageCompoundScore, notes...)
+	return content
+}
+
+// FormatPostContentWithSpans generates the post content along with the spans of its
+// hashtags and author mentions, so the client can make them tappable
+func FormatPostContentWithSpans(topPosts []Post, overallSentiment string, analysisIntervalMinutes int, totalPosts int, averageCompoundScore float64, notes ...string) (string, []Span) {
+	return FormatPostContentWithTemplate(TemplateClassic, topPosts, overallSentiment, analysisIntervalMinutes, totalPosts, averageCompoundScore, notes...)
+}
+
+// FormatPostContentWithTemplate generates the post content and its spans in the given
+// template; an unknown template is laid out as TemplateClassic
+func FormatPostContentWithTemplate(template Template, topPosts []Post, overallSentiment string, analysisIntervalMinutes int, totalPosts int, averageCompoundScore float64, notes ...string) (string, []Span) {
+	return FormatPostContentWithLayout(Layout{Template: template}, topPosts, overallSentiment, analysisIntervalMinutes, totalPosts, averageCompoundScore, notes...)
+}
+
+// Layout is how a summary is laid out
+type Layout struct {
+	Template Template
+	// Numbers formats the top posts' likes; the zero value is DefaultNumberFormat
+	Numbers NumberFormat
+	// Engagement follows each top post's sentiment marker with its likes
+	Engagement bool
+}
+
+// engagementStyle is how a top post's likes are written
+type engagementStyle int
+
+const (
+	engagementNone    engagementStyle = iota
+	engagementFull                    // 12,400
+	engagementCompact                 // 12.4k
+)
+
+// FormatPostContentWithLayout generates the post content and its spans, degrading the
+// layout until the summary fits in MaxPostLength: likes in full, then compact likes, then
+// no likes, then fewer top posts, dropping the last first. The closing tags are left off
+// whenever they don't fit.
+func FormatPostContentWithLayout(layout Layout, topPosts []Post, overallSentiment string, analysisIntervalMinutes int, totalPosts int, averageCompoundScore float64, notes ...string) (string, []Span) {
+	numbers := layout.Numbers
+	if numbers.Locale == "" {
+		numbers = DefaultNumberFormat
+	}
+	format := func(posts []Post, style engagementStyle) (string, []Span) {
+		return formatSummary(layout.Template, numbers, style, posts, analysisIntervalMinutes, averageCompoundScore, notes)
+	}
+	fits := func(content string) bool {
+		return utf8.RuneCountInString(content) <= MaxPostLength
+	}
+
+	if layout.Engagement {
+		for _, style := range []engagementStyle{engagementFull, engagementCompact} {
+			if content, spans := format(topPosts, style); fits(content) {
+				return content, spans
+			}
+		}
+	}
+	content, spans := format(topPosts, engagementNone)
+	for kept := len(topPosts) - 1; kept >= 0 && !fits(content); kept-- {
+		content, spans = format(topPosts[:kept], engagementNone)
+	}
+	return content, spans
+}
+
+// formatSummary lays out a summary with its top posts' likes written in style
+func formatSummary(template Template, numbers NumberFormat, style engagementStyle, topPosts []Post, analysisIntervalMinutes int, averageCompoundScore float64, notes []string) (string, []Span) {
 	// Scale compound score to percentage range for 100-word system
 	// Vader compound score: -1.0 to +1.0
 	// Scale to percentage: -100% to +100%
@@ -34,16 +140,224 @@ func FormatPostContent(topPosts []Post, overallSentiment string, analysisInterva
 	} else {
 		sentimentSign = ""
 	}
-	content := fmt.Sprintf("Bluesky is #%s\n%s%.1f%% sentiment\n\n", moodWord, sentimentSign, netSentiment)
+
+	var content strings.Builder
+	var spans []Span
+	writeTag := func(tag string) {
+		start := content.Len()
+		content.WriteString("#" + tag)
+		spans = append(spans, Span{Start: start, End: content.Len(), Tag: tag})
+	}
+
+	if template == TemplateScoreFirst {
+		fmt.Fprintf(&content, "%s%.1f%% sentiment%s\nBluesky is ", sentimentSign, netSentiment, formatIntervalSuffix(analysisIntervalMinutes))
+		writeTag(moodWord)
+		content.WriteString("\n\n")
+	} else {
+		content.WriteString("Bluesky is ")
+		writeTag(moodWord)
+		fmt.Fprintf(&content, "\n%s%.1f%% sentiment%s\n\n", sentimentSign, netSentiment, formatIntervalSuffix(analysisIntervalMinutes))
+	}
 
 	for i, post := range topPosts {
 		sentimentSymbol := getSentimentSymbol(post.Sentiment)
+		if template == TemplateEmoji {
+			sentimentSymbol = getSentimentEmoji(post.Sentiment)
+		}
 
 		// Just show the handle and sentiment - facets will handle the linking
-		content += fmt.Sprintf("%d. @%s %s\n", i+1, post.Author, sentimentSymbol)
+		fmt.Fprintf(&content, "%d. ", i+1)
+		start := content.Len()
+		content.WriteString("@" + post.Author)
+		spans = append(spans, Span{Start: start, End: content.Len(), Handle: post.Author, PostURI: post.URI})
+		content.WriteString(" " + sentimentSymbol)
+		switch style {
+		case engagementFull:
+			content.WriteString(" ♥" + numbers.Count(post.Likes))
+		case engagementCompact:
+			content.WriteString(" ♥" + numbers.Compact(post.Likes))
+		}
+		content.WriteString("\n")
 	}
 
-	return content
+	for _, note := range notes {
+		if note != "" {
+			content.WriteString(note + "\n")
+		}
+	}
+
+	// Close with the project hashtags if they fit
+	tagLine := "#" + strings.Join(PostTags, " #")
+	if utf8.RuneCountInString(content.String())+utf8.RuneCountInString(tagLine) <= MaxPostLength {
+		for i, tag := range PostTags {
+			if i > 0 {
+				content.WriteString(" ")
+			}
+			writeTag(tag)
+		}
+	}
+
+	return content.String(), spans
+}
+
+// CoverageNote returns a footnote when the fetched posts covered less of the analysis window
+// than the threshold, or an empty string when coverage is acceptable
+func CoverageNote(coveragePercent, minCoveragePercent float64) string {
+	if coveragePercent >= minCoveragePercent {
+		return ""
+	}
+	return fmt.Sprintf("*partial data: %.0f%% of window fetched", coveragePercent)
+}
+
+// TruncatedNote marks a summary whose fetch stopped before the window's start, so its posts
+// are only the newest part of the window, whatever the coverage threshold
+func TruncatedNote(coveragePercent float64) string {
+	return fmt.Sprintf("*partial data: fetch stopped at %.0f%% of window", coveragePercent)
+}
+
+// FeedNote names the custom feed or list a summary was drawn from, or returns an empty
+// string for summaries of the global search
+func FeedNote(feedLabel string) string {
+	if feedLabel == "" {
+		return ""
+	}
+	return "from the " + feedLabel
+}
+
+// QueryNote lists the search queries a summary was scoped to, or returns an empty
+// string when every post was analyzed
+func QueryNote(queries []string) string {
+	if len(queries) == 0 {
+		return ""
+	}
+	return "matching " + strings.Join(queries, " OR ")
+}
+
+// trendNoteThreshold is how far, in net sentiment percentage points, the last third of the
+// window must differ from the first before the summary calls out a trend
+const trendNoteThreshold = 5.0
+
+// TrendNote describes how sentiment moved between the first and last third of the window,
+// or returns an empty string when it held roughly steady
+func TrendNote(firstThirdPercent, lastThirdPercent float64) string {
+	delta := lastThirdPercent - firstThirdPercent
+	switch {
+	case delta >= trendNoteThreshold:
+		return "trend: improving ↗"
+	case delta <= -trendNoteThreshold:
+		return "trend: worsening ↘"
+	default:
+		return ""
+	}
+}
+
+// WeekOverWeekNote compares the current net sentiment with the same time last week,
+// e.g. "+6.2 vs last Tuesday 14:00"
+func WeekOverWeekNote(currentPercent, lastWeekPercent float64, lastWeekAt time.Time) string {
+	return fmt.Sprintf("%+.1f vs last %s", currentPercent-lastWeekPercent, lastWeekAt.UTC().Format("Monday 15:04"))
+}
+
+// UnusualSentimentNote describes a z-score badge, e.g. "unusually negative for a Friday evening",
+// or returns an empty string when there is no badge
+func UnusualSentimentNote(badge *insight.Badge) string {
+	if badge == nil {
+		return ""
+	}
+	return fmt.Sprintf("unusually %s for a %s %s", badge.Direction, badge.At.Weekday(), insight.PartOfDay(badge.At.Hour()))
+}
+
+// NotableDayNote names the holidays or events the window fell on, e.g. "on New Year's Day",
+// or returns an empty string on an ordinary day
+func NotableDayNote(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	return "on " + strings.Join(names, " and ")
+}
+
+// PercentileNote ranks today's sentiment so far among recent days, e.g.
+// "today: 84th percentile of 90 days"
+func PercentileNote(percentile, days int) string {
+	return fmt.Sprintf("today: %s percentile of %d days", ordinal(percentile), days)
+}
+
+// ordinal formats n as an English ordinal, e.g. 1st, 12th or 23rd
+func ordinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+// StabilityNote describes how much sentiment has swung over the last day, with its
+// volatility in percentage points, e.g. "mood steady (±3.2)"
+func StabilityNote(stability string, volatility float64) string {
+	if stability == "" {
+		return ""
+	}
+	return fmt.Sprintf("mood %s (±%.1f)", stability, volatility)
+}
+
+// DominantEmotionNote names the run's strongest emotion and its share, e.g.
+// "mostly joy (41%)", or returns an empty string when no emotion was found
+func DominantEmotionNote(emotion string, share float64) string {
+	if emotion == "" {
+		return ""
+	}
+	return fmt.Sprintf("mostly %s (%.0f%%)", emotion, share*100)
+}
+
+// DomainsNote names the run's most shared link domains, e.g. "most shared: bbc.co.uk,
+// apnews.com", or returns an empty string when no links were shared
+func DomainsNote(domains []string) string {
+	if len(domains) == 0 {
+		return ""
+	}
+	return "most shared: " + strings.Join(domains, ", ")
+}
+
+// SampleNote reports that net sentiment was estimated from a sample, with the margin of
+// its 95% confidence interval in percentage points, e.g. "±0.7 (95% CI, sample of 20k posts)"
+func SampleNote(margin float64, sampleSize int) string {
+	size := fmt.Sprintf("%d", sampleSize)
+	if sampleSize >= 1000 {
+		size = fmt.Sprintf("%.0fk", float64(sampleSize)/1000)
+	}
+	return fmt.Sprintf("±%.1f (95%% CI, sample of %s posts)", margin, size)
+}
+
+// EngagementWeightedNote marks a summary whose net sentiment weights posts by engagement
+func EngagementWeightedNote() string {
+	return "weighted by engagement"
+}
+
+// WeightedSentimentNote gives the engagement-weighted net sentiment beside the per-post
+// figure, e.g. "engagement-weighted: +4.1%"
+func WeightedSentimentNote(weightedPercent float64) string {
+	return fmt.Sprintf("engagement-weighted: %+.1f%%", weightedPercent)
+}
+
+// TopPostsCardTitle is the heading drawn on the top posts card image
+func TopPostsCardTitle(analysisIntervalMinutes int) string {
+	return "Top posts" + formatIntervalSuffix(analysisIntervalMinutes)
+}
+
+// SummaryCardTitle is the heading drawn on the summary card image
+func SummaryCardTitle(analysisIntervalMinutes int) string {
+	return "Bluesky mood" + formatIntervalSuffix(analysisIntervalMinutes)
+}
+
+// DelayedNote marks a summary posted late by the replay tool, naming when its window ended
+func DelayedNote(windowEnd time.Time) string {
+	return fmt.Sprintf("(delayed) window ended %s UTC", windowEnd.UTC().Format("Jan 2 15:04"))
 }
 
 // getSentimentSymbol returns the symbol for sentiment (+ for positive, - for negative, x for neutral)
@@ -59,3 +373,24 @@ func getSentimentSymbol(sentiment string) string {
 		return "x" // fallback to neutral
 	}
 }
+
+// getSentimentEmoji returns the coloured circle for sentiment, used by TemplateEmoji
+func getSentimentEmoji(sentiment string) string {
+	switch sentiment {
+	case "positive":
+		return "🟢"
+	case "negative":
+		return "🔴"
+	default:
+		return "⚪"
+	}
+}
+
+// formatIntervalSuffix describes the analysis window, which the orchestrator may adapt per run
+// Returns an empty string when the interval is unknown
+func formatIntervalSuffix(analysisIntervalMinutes int) string {
+	if description := interval.Describe(analysisIntervalMinutes); description != "" {
+		return " in the last " + description
+	}
+	return ""
+}
@@ -0,0 +1,169 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatPostContentWithSpans(t *testing.T) {
+	posts := []Post{
+		{URI: "at://did:plc:alice/app.bsky.feed.post/1", Author: "alice.bsky.social", Sentiment: "positive"},
+		{URI: "at://did:plc:bob/app.bsky.feed.post/2", Author: "böb.example", Sentiment: "negative"},
+	}
+
+	content, spans := FormatPostContentWithSpans(posts, "positive", 60, 1000, 0.25, "trend: improving ↗")
+
+	if !strings.HasSuffix(content, "#BlueskySentiment #hourstats") {
+		t.Errorf("expected the closing hashtags, got %q", content)
+	}
+
+	var tags, handles []string
+	for _, span := range spans {
+		covered := content[span.Start:span.End]
+		switch {
+		case span.Tag != "":
+			if covered != "#"+span.Tag {
+				t.Errorf("tag span covers %q, want #%s", covered, span.Tag)
+			}
+			tags = append(tags, span.Tag)
+		case span.Handle != "":
+			if covered != "@"+span.Handle {
+				t.Errorf("mention span covers %q, want @%s", covered, span.Handle)
+			}
+			handles = append(handles, span.Handle)
+		}
+	}
+
+	if len(tags) != 3 || tags[1] != "BlueskySentiment" || tags[2] != "hourstats" {
+		t.Errorf("expected the mood word and project tags, got %v", tags)
+	}
+	if len(handles) != 2 || handles[1] != "böb.example" {
+		t.Errorf("expected both author mentions, got %v", handles)
+	}
+
+	if plain := FormatPostContent(posts, "positive", 60, 1000, 0.25, "trend: improving ↗"); plain != content {
+		t.Errorf("FormatPostContent differs from FormatPostContentWithSpans: %q", plain)
+	}
+}
+
+func TestFormatPostContentOmitsTagsWhenFull(t *testing.T) {
+	content, spans := FormatPostContentWithSpans(nil, "neutral", 60, 1000, 0, strings.Repeat("x", 260))
+
+	if strings.Contains(content, "#hourstats") {
+		t.Errorf("expected the closing hashtags to be left off a full post, got %q", content)
+	}
+	if len(spans) != 1 {
+		t.Errorf("expected only the mood word span, got %d", len(spans))
+	}
+}
+
+func TestFormatPostContentWithTemplate(t *testing.T) {
+	posts := []Post{{URI: "at://did:plc:alice/app.bsky.feed.post/1", Author: "alice.bsky.social", Sentiment: "negative"}}
+	classic, _ := FormatPostContentWithSpans(posts, "neutral", 30, 1000, 0.05)
+
+	for _, template := range Templates {
+		content, spans := FormatPostContentWithTemplate(template, posts, "neutral", 30, 1000, 0.05)
+		for _, span := range spans {
+			if span.Handle != "" && content[span.Start:span.End] != "@alice.bsky.social" {
+				t.Errorf("%s: mention span covers %q", template, content[span.Start:span.End])
+			}
+		}
+
+		switch template {
+		case TemplateClassic:
+			if content != classic {
+				t.Errorf("classic template differs from FormatPostContentWithSpans: %q", content)
+			}
+		case TemplateScoreFirst:
+			if !strings.HasPrefix(content, "+5.0% sentiment in the last 30 min\nBluesky is #") {
+				t.Errorf("expected the score first, got %q", content)
+			}
+		case TemplateEmoji:
+			if !strings.Contains(content, "1. @alice.bsky.social 🔴\n") {
+				t.Errorf("expected an emoji sentiment marker, got %q", content)
+			}
+		}
+	}
+
+	if unknown, _ := FormatPostContentWithTemplate("fancy", posts, "neutral", 30, 1000, 0.05); unknown != classic {
+		t.Errorf("expected an unknown template to fall back to classic, got %q", unknown)
+	}
+}
+
+func TestPercentileNote(t *testing.T) {
+	for percentile, want := range map[int]string{
+		1:   "today: 1st percentile of 90 days",
+		12:  "today: 12th percentile of 90 days",
+		22:  "today: 22nd percentile of 90 days",
+		83:  "today: 83rd percentile of 90 days",
+		100: "today: 100th percentile of 90 days",
+	} {
+		if got := PercentileNote(percentile, 90); got != want {
+			t.Errorf("PercentileNote(%d, 90) = %q, want %q", percentile, got, want)
+		}
+	}
+}
+
+func TestTruncatedNote(t *testing.T) {
+	if got := TruncatedNote(96.4); got != "*partial data: fetch stopped at 96% of window" {
+		t.Errorf("TruncatedNote(96.4) = %q", got)
+	}
+}
+
+func TestDomainsNote(t *testing.T) {
+	if got := DomainsNote([]string{"bbc.co.uk", "apnews.com"}); got != "most shared: bbc.co.uk, apnews.com" {
+		t.Errorf("DomainsNote() = %q", got)
+	}
+	if got := DomainsNote(nil); got != "" {
+		t.Errorf("DomainsNote(nil) = %q, want empty", got)
+	}
+}
+
+func TestWeightedSentimentNote(t *testing.T) {
+	if got := WeightedSentimentNote(4.06); got != "engagement-weighted: +4.1%" {
+		t.Errorf("WeightedSentimentNote() = %q", got)
+	}
+	if got := WeightedSentimentNote(-12.5); got != "engagement-weighted: -12.5%" {
+		t.Errorf("WeightedSentimentNote() = %q", got)
+	}
+}
+
+func TestFormatPostContentWithLayoutDegrades(t *testing.T) {
+	var posts []Post
+	for _, author := range []string{"alice", "bob", "carol", "dave", "erin"} {
+		posts = append(posts, Post{URI: "at://did:plc:" + author + "/app.bsky.feed.post/1", Author: author + ".bsky.social", Likes: 12345, Sentiment: "positive"})
+	}
+	layout := Layout{Engagement: true}
+
+	content, _ := FormatPostContentWithLayout(layout, posts, "positive", 60, 1000, 0.25)
+	if !strings.Contains(content, "1. @alice.bsky.social + ♥12,345\n") {
+		t.Errorf("Expected likes in full when they fit, got %q", content)
+	}
+
+	// A long note leaves room for compact likes only, then for no likes
+	content, _ = FormatPostContentWithLayout(layout, posts, "positive", 60, 1000, 0.25, strings.Repeat("x", 90))
+	if !strings.Contains(content, "5. @erin.bsky.social + ♥12.3k\n") {
+		t.Errorf("Expected compact likes, got %q", content)
+	}
+	content, _ = FormatPostContentWithLayout(layout, posts, "positive", 60, 1000, 0.25, strings.Repeat("x", 120))
+	if !strings.Contains(content, "5. @erin.bsky.social +\n") || strings.Contains(content, "♥") {
+		t.Errorf("Expected every top post without likes, got %q", content)
+	}
+
+	// Then top posts are dropped from the last
+	content, spans := FormatPostContentWithLayout(layout, posts, "positive", 60, 1000, 0.25, strings.Repeat("x", 200))
+	if len([]rune(content)) > MaxPostLength || strings.Contains(content, "@erin") || !strings.Contains(content, "1. @alice.bsky.social +\n") {
+		t.Errorf("Expected the last top posts dropped to fit, got %d characters: %q", len([]rune(content)), content)
+	}
+	for _, span := range spans {
+		if span.Handle != "" && content[span.Start:span.End] != "@"+span.Handle {
+			t.Errorf("mention span covers %q, want @%s", content[span.Start:span.End], span.Handle)
+		}
+	}
+
+	de, _ := ParseNumberFormat("de")
+	content, _ = FormatPostContentWithLayout(Layout{Numbers: de, Engagement: true}, posts[:1], "positive", 60, 1000, 0.25)
+	if !strings.Contains(content, "♥12.345\n") {
+		t.Errorf("Expected German digit grouping, got %q", content)
+	}
+}
@@ -2,6 +2,11 @@ package formatter
 
 import "math"
 
+// MoodWord names the mood of a net sentiment percentage, the hashtag a summary leads with
+func MoodWord(netSentiment float64) string {
+	return getMoodWord100(netSentiment)
+}
+
 // getMoodWord100 maps sentiment percentage to one of 100 descriptive words
 // using a normal curve distribution for more realistic sentiment mapping
 func getMoodWord100(netSentiment float64) string {
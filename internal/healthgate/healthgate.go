@@ -0,0 +1,196 @@
+// Package healthgate decides whether a run's posts can be trusted to describe the window.
+// When the Bluesky API was failing during the fetch, or the run found far fewer posts than
+// recent runs did, the summary would describe the outage rather than the mood, so the
+// processor skips it and posts a notice instead.
+package healthgate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// ParameterName holds the optional JSON settings; see Settings
+const ParameterName = "/hourstats/settings/health_gate"
+
+// BaselineWindow is how far back the runs a run's volume is compared with go
+const BaselineWindow = 24 * time.Hour
+
+// minRelativeSpread floors the baseline's standard deviation at this share of its mean, so
+// a run of near-identical volumes doesn't turn an ordinary dip into a huge z-score
+const minRelativeSpread = 0.1
+
+// NoticeText is posted once when an outage starts, in place of the summaries it skips
+const NoticeText = "⚠️ Bluesky data looks unavailable or incomplete right now, so mood summaries are paused. They'll resume once posts are flowing normally again."
+
+// Settings tune the gate
+//
+//	{"maxErrorRate": 0.25, "minRequests": 4, "minVolumeZScore": -3, "minBaselineRuns": 8}
+type Settings struct {
+	// Disabled turns the gate off
+	Disabled bool `json:"disabled,omitempty"`
+	// MaxErrorRate is the largest share of the fetch's API requests that may fail
+	MaxErrorRate float64 `json:"maxErrorRate,omitempty"`
+	// MinRequests is how many requests the fetch must have made for its error rate to count
+	MinRequests int `json:"minRequests,omitempty"`
+	// MinVolumeZScore is the lowest z-score the run's posts per minute may have against
+	// the runs of the last BaselineWindow
+	MinVolumeZScore float64 `json:"minVolumeZScore,omitempty"`
+	// MinBaselineRuns is how many recent runs are needed to judge volume
+	MinBaselineRuns int `json:"minBaselineRuns,omitempty"`
+}
+
+// DefaultSettings apply when the parameter is missing, and fill in the fields it omits
+var DefaultSettings = Settings{
+	MaxErrorRate:    0.25,
+	MinRequests:     4,
+	MinVolumeZScore: -3,
+	MinBaselineRuns: 8,
+}
+
+// Validate checks the settings' bounds
+func (s Settings) Validate() error {
+	if s.MaxErrorRate <= 0 || s.MaxErrorRate > 1 {
+		return fmt.Errorf("maxErrorRate must be above 0 and at most 1, got %v", s.MaxErrorRate)
+	}
+	if s.MinRequests < 1 {
+		return fmt.Errorf("minRequests must be at least 1, got %d", s.MinRequests)
+	}
+	if s.MinVolumeZScore >= 0 {
+		return fmt.Errorf("minVolumeZScore must be negative, got %v", s.MinVolumeZScore)
+	}
+	if s.MinBaselineRuns < 2 {
+		return fmt.Errorf("minBaselineRuns must be at least 2, got %d", s.MinBaselineRuns)
+	}
+	return nil
+}
+
+// ParameterGetter is the subset of the SSM client Load needs
+type ParameterGetter interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Load reads the settings from SSM. A missing parameter returns DefaultSettings, as does
+// an invalid one along with its error.
+func Load(ctx context.Context, ssmClient ParameterGetter) (Settings, error) {
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ParameterName),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return DefaultSettings, nil
+		}
+		return DefaultSettings, fmt.Errorf("failed to get %s: %w", ParameterName, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return DefaultSettings, nil
+	}
+	return Parse(*result.Parameter.Value)
+}
+
+// Parse decodes and validates JSON settings over DefaultSettings
+func Parse(value string) (Settings, error) {
+	settings := DefaultSettings
+	if strings.TrimSpace(value) == "" {
+		return settings, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&settings); err != nil {
+		return DefaultSettings, fmt.Errorf("invalid health gate JSON: %w", err)
+	}
+	if err := settings.Validate(); err != nil {
+		return DefaultSettings, fmt.Errorf("invalid health gate settings: %w", err)
+	}
+	return settings, nil
+}
+
+// Verdict is the gate's judgement of a run
+type Verdict struct {
+	// Outage is set when the run failed the gate; Reason says why
+	Outage bool
+	Reason string
+
+	// ErrorRate is the share of the fetch's requests that failed, 0 when none were counted
+	ErrorRate float64
+
+	// VolumeZScore compares the run's posts per minute with BaselineRuns recent runs; it
+	// is only meaningful when BaselineRuns reached MinBaselineRuns
+	VolumeZScore float64
+	BaselineRuns int
+}
+
+// Check judges run against the channel's recent runs, which may include run itself
+func Check(settings Settings, run state.RunState, recentRuns []state.RunState) Verdict {
+	var verdict Verdict
+	if run.FetchRequests > 0 {
+		verdict.ErrorRate = float64(run.FetchErrors) / float64(run.FetchRequests)
+	}
+
+	rate := postsPerMinute(run)
+	var baseline []float64
+	for _, recent := range recentRuns {
+		if recent.RunID == run.RunID || recent.Manual {
+			continue // Manual runs cover past windows, not the last day's volume
+		}
+		if recent.Status != "analyzed" && recent.Status != "completed" {
+			continue // Skip failed, skipped or in-progress runs, their totals are partial
+		}
+		if recentRate := postsPerMinute(recent); recentRate > 0 {
+			baseline = append(baseline, recentRate)
+		}
+	}
+	verdict.BaselineRuns = len(baseline)
+	if len(baseline) >= settings.MinBaselineRuns {
+		mean, deviation := meanAndDeviation(baseline)
+		deviation = math.Max(deviation, mean*minRelativeSpread)
+		verdict.VolumeZScore = (rate - mean) / deviation
+
+		if verdict.VolumeZScore < settings.MinVolumeZScore {
+			verdict.Outage = true
+			verdict.Reason = fmt.Sprintf("%.0f posts/min against %.0f±%.0f over %d recent runs (z-score %.1f)",
+				rate, mean, deviation, len(baseline), verdict.VolumeZScore)
+		}
+	}
+
+	if run.FetchRequests >= settings.MinRequests && verdict.ErrorRate > settings.MaxErrorRate {
+		verdict.Outage = true
+		verdict.Reason = fmt.Sprintf("%d of %d API requests failed", run.FetchErrors, run.FetchRequests)
+	}
+	return verdict
+}
+
+// postsPerMinute is a run's post volume, 0 when unknown
+func postsPerMinute(run state.RunState) float64 {
+	if run.AnalysisIntervalMinutes <= 0 {
+		return 0
+	}
+	return float64(run.TotalPostsRetrieved) / float64(run.AnalysisIntervalMinutes)
+}
+
+// meanAndDeviation returns the mean and sample standard deviation of values
+func meanAndDeviation(values []float64) (float64, float64) {
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+
+	var squares float64
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(values)-1))
+}
@@ -0,0 +1,76 @@
+package healthgate
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// baselineRuns returns completed 30-minute runs retrieving the given post totals
+func baselineRuns(totals ...int) []state.RunState {
+	runs := make([]state.RunState, len(totals))
+	for i, total := range totals {
+		runs[i] = state.RunState{RunID: fmt.Sprintf("run-%d", i), Status: "completed", AnalysisIntervalMinutes: 30, TotalPostsRetrieved: total}
+	}
+	return runs
+}
+
+func TestCheckVolume(t *testing.T) {
+	recent := baselineRuns(9000, 9600, 8700, 9300, 9900, 9000, 8400, 9600)
+
+	healthy := Check(DefaultSettings, state.RunState{RunID: "now", AnalysisIntervalMinutes: 30, TotalPostsRetrieved: 8100}, recent)
+	if healthy.Outage || healthy.BaselineRuns != 8 {
+		t.Errorf("expected an ordinary run to pass against 8 runs, got %+v", healthy)
+	}
+
+	collapsed := Check(DefaultSettings, state.RunState{RunID: "now", AnalysisIntervalMinutes: 30, TotalPostsRetrieved: 900}, recent)
+	if !collapsed.Outage || !strings.Contains(collapsed.Reason, "30 posts/min") {
+		t.Errorf("expected a tenth of the usual volume to fail, got %+v", collapsed)
+	}
+
+	// Too little history to judge volume
+	if verdict := Check(DefaultSettings, state.RunState{RunID: "now", AnalysisIntervalMinutes: 30, TotalPostsRetrieved: 900}, recent[:3]); verdict.Outage {
+		t.Errorf("expected no volume verdict from 3 runs, got %+v", verdict)
+	}
+}
+
+func TestCheckIgnoresUnusableBaselineRuns(t *testing.T) {
+	recent := baselineRuns(9000, 9000, 9000, 9000, 9000, 9000, 9000, 9000)
+	recent[0].Status = "skipped"
+	recent[1].Manual = true
+	recent[2].RunID = "now"
+
+	verdict := Check(DefaultSettings, state.RunState{RunID: "now", AnalysisIntervalMinutes: 30, TotalPostsRetrieved: 9000}, recent)
+	if verdict.BaselineRuns != 5 {
+		t.Errorf("expected 5 usable baseline runs, got %d", verdict.BaselineRuns)
+	}
+}
+
+func TestCheckErrorRate(t *testing.T) {
+	failing := Check(DefaultSettings, state.RunState{FetchRequests: 10, FetchErrors: 4}, nil)
+	if !failing.Outage || failing.ErrorRate != 0.4 || failing.Reason != "4 of 10 API requests failed" {
+		t.Errorf("expected 4 of 10 failed requests to fail, got %+v", failing)
+	}
+
+	if verdict := Check(DefaultSettings, state.RunState{FetchRequests: 10, FetchErrors: 2}, nil); verdict.Outage {
+		t.Errorf("expected 2 of 10 failed requests to pass, got %+v", verdict)
+	}
+	if verdict := Check(DefaultSettings, state.RunState{FetchRequests: 2, FetchErrors: 2}, nil); verdict.Outage {
+		t.Errorf("expected too few requests to judge, got %+v", verdict)
+	}
+}
+
+func TestParse(t *testing.T) {
+	settings, err := Parse(`{"maxErrorRate": 0.5}`)
+	if err != nil || settings.MaxErrorRate != 0.5 || settings.MinVolumeZScore != DefaultSettings.MinVolumeZScore {
+		t.Errorf("Parse() = %+v, %v, want the defaults with maxErrorRate 0.5", settings, err)
+	}
+
+	for _, value := range []string{`{"maxErrorRate": 2}`, `{"minVolumeZScore": 1}`, `{"minBaselineRuns": 1}`, `{"threshold": 3}`} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("Parse(%s) succeeded, want error", value)
+		}
+	}
+}
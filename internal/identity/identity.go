@@ -0,0 +1,155 @@
+// Package identity resolves which accounts are on the Bluesky moderation lists a deployment
+// subscribes to, so their posts can be left out of runs. Members are cached between runs
+// and refreshed from Bluesky periodically, since lists change far less often than runs.
+package identity
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// RefreshAfter is how old a list's cached members get before they are fetched again
+const RefreshAfter = time.Hour
+
+// Source fetches list members from Bluesky; *client.BlueskyClient implements it
+type Source interface {
+	Authenticate() error
+	GetListMembers(ctx context.Context, listURI string) (string, []client.ListMember, error)
+}
+
+// Cache stores list members between runs; *state.StateManager implements it
+type Cache interface {
+	GetModerationList(ctx context.Context, listURI string) (*state.ModerationList, error)
+	SaveModerationList(ctx context.Context, listURI, name string, members []string) (*state.ModerationList, error)
+}
+
+// ParseListURIs reads a comma-separated setting of list at:// URIs, skipping duplicates
+// and, with a log, anything that isn't a list
+func ParseListURIs(value string) []string {
+	var uris []string
+	seen := make(map[string]bool)
+	for _, uri := range strings.Split(value, ",") {
+		uri = strings.TrimSpace(uri)
+		if uri == "" || seen[uri] {
+			continue
+		}
+		if kind, err := client.FeedKind(uri); err != nil || kind != client.FeedKindList {
+			log.Printf("⚠️ IDENTITY: Ignoring %q, not a list URI", uri)
+			continue
+		}
+		seen[uri] = true
+		uris = append(uris, uri)
+	}
+	return uris
+}
+
+// AuthorDID returns the DID of a post's author from its URI,
+// e.g. at://did:plc:abc123/app.bsky.feed.post/xyz789 -> did:plc:abc123
+func AuthorDID(postURI string) string {
+	repo, _, _ := strings.Cut(strings.TrimPrefix(postURI, "at://"), "/")
+	if !strings.HasPrefix(repo, "did:") {
+		return ""
+	}
+	return repo
+}
+
+// Resolver looks up list members, through the cache and through lists it already holds,
+// which a warm Lambda keeps between invocations
+type Resolver struct {
+	source        Source
+	cache         Cache
+	lists         map[string]*state.ModerationList
+	authenticated bool
+	now           func() time.Time
+}
+
+// NewResolver creates a resolver; a nil cache fetches every list once per RefreshAfter in
+// each Lambda instance
+func NewResolver(source Source, cache Cache) *Resolver {
+	return &Resolver{
+		source: source,
+		cache:  cache,
+		lists:  make(map[string]*state.ModerationList),
+		now:    time.Now,
+	}
+}
+
+// Members returns the DIDs on any of the lists. A list whose members can't be refreshed
+// keeps its cached members, and one never fetched is skipped; failures are logged rather
+// than returned, so a Bluesky outage never holds up a run.
+func (r *Resolver) Members(ctx context.Context, listURIs []string) map[string]bool {
+	members := make(map[string]bool)
+	for _, uri := range listURIs {
+		list := r.list(ctx, uri)
+		if list == nil {
+			continue
+		}
+		for _, did := range list.Members {
+			members[did] = true
+		}
+	}
+	return members
+}
+
+// list returns a list's members, refreshing them once they are older than RefreshAfter
+func (r *Resolver) list(ctx context.Context, uri string) *state.ModerationList {
+	list := r.lists[uri]
+	if list == nil && r.cache != nil {
+		cached, err := r.cache.GetModerationList(ctx, uri)
+		if err != nil {
+			log.Printf("⚠️ IDENTITY: Failed to read cached members of %s: %v", uri, err)
+		}
+		list = cached
+	}
+	if list != nil && r.now().Sub(list.FetchedAt) < RefreshAfter {
+		r.lists[uri] = list
+		return list
+	}
+
+	fetched, err := r.fetch(ctx, uri)
+	if err != nil {
+		if list != nil {
+			log.Printf("⚠️ IDENTITY: Failed to refresh %s, using %d members fetched %s: %v",
+				uri, len(list.Members), list.FetchedAt.Format(time.RFC3339), err)
+		} else {
+			log.Printf("⚠️ IDENTITY: Failed to fetch %s, skipping it: %v", uri, err)
+		}
+		return list
+	}
+	r.lists[uri] = fetched
+	return fetched
+}
+
+// fetch reads a list's members from Bluesky and caches them
+func (r *Resolver) fetch(ctx context.Context, uri string) (*state.ModerationList, error) {
+	if !r.authenticated {
+		if err := r.source.Authenticate(); err != nil {
+			return nil, err
+		}
+		r.authenticated = true
+	}
+
+	name, listMembers, err := r.source.GetListMembers(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	dids := make([]string, 0, len(listMembers))
+	for _, member := range listMembers {
+		dids = append(dids, member.DID)
+	}
+	log.Printf("🛡️ IDENTITY: Fetched %d members of %q (%s)", len(dids), name, uri)
+
+	if r.cache != nil {
+		cached, err := r.cache.SaveModerationList(ctx, uri, name, dids)
+		if err == nil {
+			return cached, nil
+		}
+		log.Printf("⚠️ IDENTITY: Failed to cache members of %s: %v", uri, err)
+	}
+	return &state.ModerationList{PostID: uri, Name: name, Members: dids, FetchedAt: r.now().UTC()}, nil
+}
@@ -0,0 +1,100 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+const (
+	spamList  = "at://did:plc:mods/app.bsky.graph.list/spam"
+	trollList = "at://did:plc:mods/app.bsky.graph.list/trolls"
+)
+
+type fakeSource struct {
+	members map[string][]client.ListMember
+	fetches int
+	err     error
+}
+
+func (s *fakeSource) Authenticate() error { return nil }
+
+func (s *fakeSource) GetListMembers(ctx context.Context, listURI string) (string, []client.ListMember, error) {
+	s.fetches++
+	if s.err != nil {
+		return "", nil, s.err
+	}
+	return "list", s.members[listURI], nil
+}
+
+type fakeCache struct {
+	lists map[string]*state.ModerationList
+	now   time.Time
+}
+
+func (c *fakeCache) GetModerationList(ctx context.Context, listURI string) (*state.ModerationList, error) {
+	return c.lists[listURI], nil
+}
+
+func (c *fakeCache) SaveModerationList(ctx context.Context, listURI, name string, members []string) (*state.ModerationList, error) {
+	list := &state.ModerationList{PostID: listURI, Name: name, Members: members, FetchedAt: c.now}
+	c.lists[listURI] = list
+	return list, nil
+}
+
+func TestParseListURIs(t *testing.T) {
+	uris := ParseListURIs(spamList + ", at://did:plc:mods/app.bsky.feed.generator/science," + spamList + ",," + trollList)
+	if len(uris) != 2 || uris[0] != spamList || uris[1] != trollList {
+		t.Errorf("ParseListURIs() = %v, want the two lists once each", uris)
+	}
+}
+
+func TestAuthorDID(t *testing.T) {
+	if did := AuthorDID("at://did:plc:abc123/app.bsky.feed.post/xyz789"); did != "did:plc:abc123" {
+		t.Errorf("AuthorDID() = %q, want did:plc:abc123", did)
+	}
+	if did := AuthorDID("at://alice.bsky.social/app.bsky.feed.post/xyz789"); did != "" {
+		t.Errorf("AuthorDID() of a handle URI = %q, want empty", did)
+	}
+}
+
+func TestResolverMembers(t *testing.T) {
+	now := time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC)
+	source := &fakeSource{members: map[string][]client.ListMember{
+		spamList: {{DID: "did:plc:spam1"}, {DID: "did:plc:spam2"}},
+	}}
+	cache := &fakeCache{now: now, lists: map[string]*state.ModerationList{
+		trollList: {PostID: trollList, Members: []string{"did:plc:troll"}, FetchedAt: now.Add(-10 * time.Minute)},
+	}}
+	resolver := NewResolver(source, cache)
+	resolver.now = func() time.Time { return now }
+
+	// The troll list is cached and fresh, so only the spam list is fetched
+	members := resolver.Members(context.Background(), []string{spamList, trollList})
+	if len(members) != 3 || !members["did:plc:spam2"] || !members["did:plc:troll"] || source.fetches != 1 {
+		t.Fatalf("Members() = %v after %d fetches, want 3 members from 1 fetch", members, source.fetches)
+	}
+
+	// Within RefreshAfter, the resolver's own copies are used
+	resolver.Members(context.Background(), []string{spamList, trollList})
+	if source.fetches != 1 {
+		t.Errorf("Expected no refetch within RefreshAfter, got %d fetches", source.fetches)
+	}
+
+	// Once stale, a failed refresh keeps the members already fetched
+	now = now.Add(2 * time.Hour)
+	source.err = errors.New("unavailable")
+	members = resolver.Members(context.Background(), []string{spamList, trollList})
+	if len(members) != 3 || source.fetches != 3 {
+		t.Errorf("Members() = %v after %d fetches, want the stale members after 2 refresh attempts", members, source.fetches)
+	}
+
+	// A list never fetched is skipped
+	if members := NewResolver(source, nil).Members(context.Background(), []string{spamList}); len(members) != 0 {
+		t.Errorf("Members() = %v, want none when the list can't be fetched", members)
+	}
+}
@@ -0,0 +1,128 @@
+// Package imagetext collects the text posts carry in their images, so it can be analyzed
+// with the post's own text: the alt text authors write and, optionally, text read from
+// the images themselves.
+package imagetext
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+)
+
+// Modes choosing which image text is collected
+const (
+	ModeOff = "off" // post text only
+	ModeAlt = "alt" // adds alt text
+	ModeOCR = "ocr" // adds alt text, and reads the text in images without alt text
+)
+
+// ParseMode checks a mode setting; empty means ModeOff
+func ParseMode(value string) (string, error) {
+	switch mode := strings.ToLower(strings.TrimSpace(value)); mode {
+	case "":
+		return ModeOff, nil
+	case ModeOff, ModeAlt, ModeOCR:
+		return mode, nil
+	default:
+		return ModeOff, fmt.Errorf("unknown image text mode %q, want %s, %s or %s", value, ModeOff, ModeAlt, ModeOCR)
+	}
+}
+
+// maxImageBytes bounds downloaded images; Bluesky stores images of at most 1 MB
+const maxImageBytes = 5 << 20
+
+// Reader reads the text in an image
+type Reader interface {
+	Read(ctx context.Context, image []byte) (string, error)
+}
+
+// Extractor collects image text in one mode, reading at most a fixed number of images
+// since each read is billed
+type Extractor struct {
+	mode       string
+	reader     Reader
+	httpClient *http.Client
+	remaining  int
+}
+
+// NewExtractor creates an extractor; reader and maxReads are only used in ModeOCR
+func NewExtractor(mode string, reader Reader, maxReads int) *Extractor {
+	return &Extractor{
+		mode:       mode,
+		reader:     reader,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		remaining:  maxReads,
+	}
+}
+
+// Enabled reports whether the extractor collects any image text
+func (e *Extractor) Enabled() bool {
+	return e != nil && e.mode != ModeOff && e.mode != ""
+}
+
+// Reads returns how many more images the extractor will read
+func (e *Extractor) Reads() int {
+	return e.remaining
+}
+
+// Text returns the text of a post's images, one image per line: its alt text, or in
+// ModeOCR the text read from it while reads remain. Images that can't be read are
+// skipped, so a failure only loses that image's text.
+func (e *Extractor) Text(ctx context.Context, images []client.Image) string {
+	if !e.Enabled() {
+		return ""
+	}
+
+	var lines []string
+	for _, image := range images {
+		if image.Alt != "" {
+			lines = append(lines, image.Alt)
+			continue
+		}
+		if e.mode != ModeOCR || e.reader == nil || e.remaining <= 0 || image.URL == "" {
+			continue
+		}
+
+		e.remaining--
+		text, err := e.read(ctx, image.URL)
+		if err != nil {
+			log.Printf("⚠️ IMAGETEXT: Failed to read %s: %v", image.URL, err)
+			continue
+		}
+		if text != "" {
+			lines = append(lines, text)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// read downloads an image and reads its text
+func (e *Extractor) read(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build image request: %w", err)
+	}
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download image: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("image download returned %s", resp.Status)
+	}
+
+	image, err := io.ReadAll(io.LimitReader(resp.Body, maxImageBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to download image: %w", err)
+	}
+	if len(image) > maxImageBytes {
+		return "", fmt.Errorf("image larger than %d bytes", maxImageBytes)
+	}
+	return e.reader.Read(ctx, image)
+}
@@ -0,0 +1,103 @@
+package imagetext
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+)
+
+type fakeReader struct {
+	reads []string
+}
+
+func (r *fakeReader) Read(ctx context.Context, image []byte) (string, error) {
+	r.reads = append(r.reads, string(image))
+	return "text in " + string(image), nil
+}
+
+func TestParseMode(t *testing.T) {
+	for value, want := range map[string]string{"": ModeOff, "off": ModeOff, " ALT ": ModeAlt, "ocr": ModeOCR} {
+		if got, err := ParseMode(value); err != nil || got != want {
+			t.Errorf("ParseMode(%q) = %q, %v, want %q", value, got, err, want)
+		}
+	}
+	if mode, err := ParseMode("textract"); err == nil || mode != ModeOff {
+		t.Errorf("ParseMode(\"textract\") = %q, %v, want off and an error", mode, err)
+	}
+}
+
+func TestExtractorText(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.jpg" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(r.URL.Path[1:]))
+	}))
+	defer server.Close()
+
+	images := []client.Image{
+		{URL: server.URL + "/a.jpg", Alt: "A protest sign"},
+		{URL: server.URL + "/b.jpg"},
+		{URL: server.URL + "/missing.jpg"},
+		{URL: server.URL + "/c.jpg"},
+	}
+
+	if text := NewExtractor(ModeOff, &fakeReader{}, 10).Text(context.Background(), images); text != "" {
+		t.Errorf("Expected no text when off, got %q", text)
+	}
+
+	reader := &fakeReader{}
+	if text := NewExtractor(ModeAlt, reader, 10).Text(context.Background(), images); text != "A protest sign" || len(reader.reads) != 0 {
+		t.Errorf("Expected only alt text without reads, got %q after %d reads", text, len(reader.reads))
+	}
+
+	// The missing image uses up a read, leaving none for the last one
+	extractor := NewExtractor(ModeOCR, reader, 2)
+	if text := extractor.Text(context.Background(), images); text != "A protest sign\ntext in b.jpg" {
+		t.Errorf("Unexpected OCR text %q", text)
+	}
+	if extractor.Reads() != 0 {
+		t.Errorf("Expected no reads left, got %d", extractor.Reads())
+	}
+}
+
+func TestTextractReader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if target := r.Header.Get("X-Amz-Target"); target != "Textract.DetectDocumentText" {
+			t.Errorf("Unexpected target %q", target)
+		}
+		if r.Header.Get("Authorization") == "" {
+			t.Error("Expected a signed request")
+		}
+		var request detectDocumentTextRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || string(request.Document.Bytes) != "png" {
+			t.Errorf("Unexpected request %+v, %v", request, err)
+		}
+		w.Write([]byte(`{"Blocks": [
+			{"BlockType": "PAGE"},
+			{"BlockType": "LINE", "Text": "NO WAR"},
+			{"BlockType": "WORD", "Text": "NO"},
+			{"BlockType": "LINE", "Text": "ON EARTH"}
+		]}`))
+	}))
+	defer server.Close()
+
+	reader := NewTextractReader(aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "secret", ""),
+	})
+	reader.endpoint = server.URL
+
+	text, err := reader.Read(context.Background(), []byte("png"))
+	if err != nil || text != "NO WAR ON EARTH" {
+		t.Errorf("Read() = %q, %v, want the lines joined", text, err)
+	}
+}
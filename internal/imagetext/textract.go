@@ -0,0 +1,99 @@
+package imagetext
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// TextractReader reads image text with Amazon Textract's DetectDocumentText, billed per
+// image. It calls the JSON API directly, signing requests with the Lambda's credentials.
+type TextractReader struct {
+	endpoint    string
+	region      string
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	httpClient  *http.Client
+}
+
+// NewTextractReader creates a Textract client in the config's region
+func NewTextractReader(cfg aws.Config) *TextractReader {
+	return &TextractReader{
+		endpoint:    fmt.Sprintf("https://textract.%s.amazonaws.com/", cfg.Region),
+		region:      cfg.Region,
+		credentials: cfg.Credentials,
+		signer:      v4.NewSigner(),
+		httpClient:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type detectDocumentTextRequest struct {
+	Document struct {
+		Bytes []byte `json:"Bytes"`
+	} `json:"Document"`
+}
+
+type detectDocumentTextResponse struct {
+	Blocks []struct {
+		BlockType string `json:"BlockType"`
+		Text      string `json:"Text"`
+	} `json:"Blocks"`
+}
+
+// Read returns the image's lines of text joined by spaces, or an empty string when it has none
+func (r *TextractReader) Read(ctx context.Context, image []byte) (string, error) {
+	var body detectDocumentTextRequest
+	body.Document.Bytes = image
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode Textract request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to build Textract request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "Textract.DetectDocumentText")
+
+	credentials, err := r.credentials.Retrieve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get AWS credentials: %w", err)
+	}
+	hash := sha256.Sum256(payload)
+	if err := r.signer.SignHTTP(ctx, credentials, req, hex.EncodeToString(hash[:]), "textract", r.region, time.Now()); err != nil {
+		return "", fmt.Errorf("failed to sign Textract request: %w", err)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call Textract: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("Textract returned %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+
+	var parsed detectDocumentTextResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("invalid Textract response: %w", err)
+	}
+	var lines []string
+	for _, block := range parsed.Blocks {
+		if block.BlockType == "LINE" && block.Text != "" {
+			lines = append(lines, block.Text)
+		}
+	}
+	return strings.Join(lines, " "), nil
+}
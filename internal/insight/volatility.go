@@ -0,0 +1,66 @@
+package insight
+
+import (
+	"math"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+const (
+	// VolatilityWindow is how much history rolling volatility is measured over
+	VolatilityWindow = 24 * time.Hour
+
+	// MinVolatilitySamples is the fewest points, the current run included, needed for a
+	// meaningful volatility
+	MinVolatilitySamples = 6
+
+	// ChoppyThreshold is the volatility, in net sentiment percentage points, above which
+	// the mood is called choppy rather than steady
+	ChoppyThreshold = 5.0
+)
+
+// Stability labels
+const (
+	StabilitySteady = "steady"
+	StabilityChoppy = "choppy"
+)
+
+// Volatility computes the standard deviation of net sentiment over the VolatilityWindow
+// before at, including current. ok is false when there are too few points to say.
+func Volatility(points []state.SentimentDataPoint, current float64, at time.Time) (volatility float64, samples int, ok bool) {
+	windowStart := at.Add(-VolatilityWindow)
+
+	values := []float64{current}
+	for _, point := range points {
+		if point.Timestamp.Before(windowStart) || !point.Timestamp.Before(at) {
+			continue
+		}
+		values = append(values, point.NetSentimentPercent)
+	}
+
+	samples = len(values)
+	if samples < MinVolatilitySamples {
+		return 0, samples, false
+	}
+
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(samples)
+
+	var squares float64
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+	return math.Sqrt(squares / float64(samples)), samples, true
+}
+
+// Stability labels a volatility as choppy or steady
+func Stability(volatility float64) string {
+	if volatility > ChoppyThreshold {
+		return StabilityChoppy
+	}
+	return StabilitySteady
+}
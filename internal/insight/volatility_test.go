@@ -0,0 +1,40 @@
+package insight
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func TestVolatility(t *testing.T) {
+	at := time.Date(2025, 1, 10, 18, 30, 0, 0, time.UTC)
+	var points []state.SentimentDataPoint
+	for hour := 1; hour <= 7; hour++ {
+		value := 10.0
+		if hour%2 == 0 {
+			value = 20
+		}
+		points = append(points, state.SentimentDataPoint{Timestamp: at.Add(-time.Duration(hour) * time.Hour), NetSentimentPercent: value})
+	}
+	points = append(points,
+		state.SentimentDataPoint{Timestamp: at.Add(-25 * time.Hour), NetSentimentPercent: 90}, // outside the window
+		state.SentimentDataPoint{Timestamp: at.Add(time.Minute), NetSentimentPercent: 90},     // after the run
+	)
+
+	volatility, samples, ok := Volatility(points, 20, at)
+	if !ok || samples != 8 {
+		t.Fatalf("expected 8 samples, got %d (ok %v)", samples, ok)
+	}
+	if math.Abs(volatility-5) > 0.001 {
+		t.Errorf("expected volatility 5, got %.3f", volatility)
+	}
+	if Stability(volatility) != StabilitySteady || Stability(volatility+0.1) != StabilityChoppy {
+		t.Errorf("expected %.1f to be the edge of steady", volatility)
+	}
+
+	if _, samples, ok := Volatility(points[:3], 20, at); ok {
+		t.Errorf("expected too few samples with %d points", samples)
+	}
+}
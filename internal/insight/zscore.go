@@ -0,0 +1,122 @@
+// Package insight derives context about the current run from sentiment history
+package insight
+
+import (
+	"math"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+const (
+	// BaselineWindow is how much history the hour-of-day baseline is drawn from
+	BaselineWindow = 30 * 24 * time.Hour
+
+	// BadgeThreshold is how many standard deviations from the baseline mean the current
+	// value must be before it is called unusual
+	BadgeThreshold = 2.0
+
+	// MinBaselineSamples is the fewest same-hour points needed for a meaningful baseline
+	MinBaselineSamples = 7
+)
+
+// Badge directions
+const (
+	DirectionPositive = "positive"
+	DirectionNegative = "negative"
+)
+
+// Baseline summarises the net sentiment recorded at one hour of the day
+type Baseline struct {
+	Hour    int
+	Mean    float64
+	StdDev  float64
+	Samples int
+}
+
+// ZScore is how many standard deviations value lies from the baseline mean
+func (b Baseline) ZScore(value float64) float64 {
+	if b.StdDev == 0 {
+		return 0
+	}
+	return (value - b.Mean) / b.StdDev
+}
+
+// HourOfDayBaseline computes the mean and standard deviation of net sentiment for points
+// recorded in the same UTC hour as at, within BaselineWindow before it
+// Points from the current hour itself are excluded so a run is not compared with itself
+func HourOfDayBaseline(points []state.SentimentDataPoint, at time.Time) Baseline {
+	at = at.UTC()
+	baseline := Baseline{Hour: at.Hour()}
+	windowStart := at.Add(-BaselineWindow)
+	currentHour := at.Truncate(time.Hour)
+
+	var values []float64
+	for _, point := range points {
+		timestamp := point.Timestamp.UTC()
+		if timestamp.Hour() != baseline.Hour || timestamp.Before(windowStart) || !timestamp.Before(currentHour) {
+			continue
+		}
+		values = append(values, point.NetSentimentPercent)
+	}
+
+	baseline.Samples = len(values)
+	if baseline.Samples == 0 {
+		return baseline
+	}
+
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	baseline.Mean = sum / float64(baseline.Samples)
+
+	var squares float64
+	for _, value := range values {
+		squares += (value - baseline.Mean) * (value - baseline.Mean)
+	}
+	baseline.StdDev = math.Sqrt(squares / float64(baseline.Samples))
+
+	return baseline
+}
+
+// Badge flags a run whose sentiment is unusual for its hour of the day
+type Badge struct {
+	Direction string
+	ZScore    float64
+	At        time.Time
+}
+
+// EvaluateBadge returns a badge when current lies more than BadgeThreshold standard
+// deviations from the hour-of-day baseline, or nil when it is ordinary or history is thin
+func EvaluateBadge(points []state.SentimentDataPoint, current float64, at time.Time) *Badge {
+	baseline := HourOfDayBaseline(points, at)
+	if baseline.Samples < MinBaselineSamples || baseline.StdDev == 0 {
+		return nil
+	}
+
+	z := baseline.ZScore(current)
+	if math.Abs(z) < BadgeThreshold {
+		return nil
+	}
+
+	direction := DirectionPositive
+	if z < 0 {
+		direction = DirectionNegative
+	}
+	return &Badge{Direction: direction, ZScore: z, At: at.UTC()}
+}
+
+// PartOfDay names the part of the day an hour falls in
+func PartOfDay(hour int) string {
+	switch {
+	case hour >= 5 && hour < 12:
+		return "morning"
+	case hour >= 12 && hour < 17:
+		return "afternoon"
+	case hour >= 17 && hour < 22:
+		return "evening"
+	default:
+		return "night"
+	}
+}
@@ -0,0 +1,84 @@
+package insight
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// history returns one point per day at the given hour, cycling through values
+func history(at time.Time, days int, values ...float64) []state.SentimentDataPoint {
+	var points []state.SentimentDataPoint
+	for day := 1; day <= days; day++ {
+		points = append(points, state.SentimentDataPoint{
+			Timestamp:           at.Add(-time.Duration(day) * 24 * time.Hour),
+			NetSentimentPercent: values[day%len(values)],
+		})
+	}
+	return points
+}
+
+func TestHourOfDayBaseline(t *testing.T) {
+	at := time.Date(2025, 1, 10, 18, 30, 0, 0, time.UTC)
+	points := history(at, 10, 10, 20)
+	points = append(points,
+		state.SentimentDataPoint{Timestamp: at.Add(-time.Hour), NetSentimentPercent: 90},           // different hour
+		state.SentimentDataPoint{Timestamp: at.Add(-40 * 24 * time.Hour), NetSentimentPercent: 90}, // outside the window
+		state.SentimentDataPoint{Timestamp: at.Add(-10 * time.Minute), NetSentimentPercent: 90},    // the current hour
+	)
+
+	baseline := HourOfDayBaseline(points, at)
+	if baseline.Hour != 18 || baseline.Samples != 10 {
+		t.Fatalf("expected 10 samples at hour 18, got %+v", baseline)
+	}
+	if math.Abs(baseline.Mean-15) > 0.001 || math.Abs(baseline.StdDev-5) > 0.001 {
+		t.Errorf("expected mean 15 and stddev 5, got %+v", baseline)
+	}
+	if math.Abs(baseline.ZScore(30)-3) > 0.001 {
+		t.Errorf("expected z-score of 3, got %.3f", baseline.ZScore(30))
+	}
+}
+
+func TestEvaluateBadge(t *testing.T) {
+	at := time.Date(2025, 1, 10, 18, 30, 0, 0, time.UTC)
+	points := history(at, 10, 10, 20)
+
+	tests := []struct {
+		name      string
+		points    []state.SentimentDataPoint
+		current   float64
+		direction string
+	}{
+		{name: "unusually negative", points: points, current: 0, direction: DirectionNegative},
+		{name: "unusually positive", points: points, current: 30, direction: DirectionPositive},
+		{name: "ordinary", points: points, current: 20},
+		{name: "thin history", points: history(at, MinBaselineSamples-1, 10, 20), current: 0},
+		{name: "flat history", points: history(at, 10, 15), current: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			badge := EvaluateBadge(tt.points, tt.current, at)
+			if tt.direction == "" {
+				if badge != nil {
+					t.Errorf("expected no badge, got %+v", badge)
+				}
+				return
+			}
+			if badge == nil || badge.Direction != tt.direction {
+				t.Errorf("expected a %s badge, got %+v", tt.direction, badge)
+			}
+		})
+	}
+}
+
+func TestPartOfDay(t *testing.T) {
+	cases := map[int]string{0: "night", 5: "morning", 12: "afternoon", 17: "evening", 22: "night"}
+	for hour, expected := range cases {
+		if got := PartOfDay(hour); got != expected {
+			t.Errorf("PartOfDay(%d) = %s, want %s", hour, got, expected)
+		}
+	}
+}
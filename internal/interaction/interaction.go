@@ -0,0 +1,110 @@
+// Package interaction decides who may reply to or quote each poster's posts, based on
+// optional settings stored in SSM
+package interaction
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+)
+
+// ParameterName holds the JSON interaction settings; when it is absent posts stay open
+const ParameterName = "/hourstats/settings/interaction"
+
+// Config is the interaction settings for bot posts. Posters are keyed by the schedule
+// poster names ("summary", "sparkline", "yearly") and replace the default entirely.
+//
+//	{
+//	  "default": {"replies": ["followers", "mentioned"]},
+//	  "posters": {"yearly": {"disableQuotes": true, "disableReplies": true}}
+//	}
+type Config struct {
+	Default client.InteractionSettings            `json:"default"`
+	Posters map[string]client.InteractionSettings `json:"posters,omitempty"`
+}
+
+// ParameterGetter is the subset of the SSM client Load needs
+type ParameterGetter interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Load reads the interaction settings from SSM. A missing or empty parameter returns
+// a nil config, which leaves every post open.
+func Load(ctx context.Context, ssmClient ParameterGetter) (*Config, error) {
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ParameterName),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %s: %w", ParameterName, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return nil, nil
+	}
+
+	return Parse(*result.Parameter.Value)
+}
+
+// Parse decodes and validates JSON interaction settings
+func Parse(value string) (*Config, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var config Config
+	if err := json.Unmarshal([]byte(value), &config); err != nil {
+		return nil, fmt.Errorf("invalid interaction settings: %w", err)
+	}
+
+	if err := config.Default.Validate(); err != nil {
+		return nil, err
+	}
+	for poster, settings := range config.Posters {
+		if err := settings.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", poster, err)
+		}
+	}
+
+	return &config, nil
+}
+
+// For returns the settings for poster's posts. A nil config leaves posts open.
+func (c *Config) For(poster string) client.InteractionSettings {
+	if c == nil {
+		return client.InteractionSettings{}
+	}
+	if settings, ok := c.Posters[poster]; ok {
+		return settings
+	}
+	return c.Default
+}
+
+// Apply loads the settings and gates poster's root post at postURI. Failures are logged
+// rather than returned, since the post itself has already been published.
+func Apply(ctx context.Context, ssmClient ParameterGetter, poster client.BskyPoster, posterName, postURI string) {
+	config, err := Load(ctx, ssmClient)
+	if err != nil {
+		log.Printf("Ignoring interaction settings: %v", err)
+		return
+	}
+
+	settings := config.For(posterName)
+	if settings.IsZero() {
+		return
+	}
+	if err := poster.SetInteractionSettings(ctx, postURI, settings); err != nil {
+		log.Printf("Failed to set interaction settings on %s: %v (post was successful)", postURI, err)
+	}
+}
@@ -0,0 +1,91 @@
+package interaction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/christophergentle/hourstats-bsky/internal/client/clienttest"
+)
+
+type fakeParameterGetter struct {
+	value string
+}
+
+func (f fakeParameterGetter) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	if f.value == "" {
+		return nil, &types.ParameterNotFound{}
+	}
+	return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: aws.String(f.value)}}, nil
+}
+
+func TestParseValidates(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"unknown rule", `{"default": {"replies": ["everyone"]}}`},
+		{"bad list", `{"posters": {"yearly": {"replyLists": ["https://bsky.app/lists/1"]}}}`},
+		{"bad json", `{"default": `},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.value); err == nil {
+				t.Errorf("Parse(%s) expected an error", tt.value)
+			}
+		})
+	}
+
+	config, err := Parse("  ")
+	if err != nil || config != nil {
+		t.Errorf("Parse(empty) = %+v, %v; want nil config", config, err)
+	}
+}
+
+func TestForFallsBackToDefault(t *testing.T) {
+	config, err := Parse(`{
+		"default": {"replies": ["followers", "mentioned"]},
+		"posters": {"yearly": {"disableQuotes": true}}
+	}`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if got := config.For("summary"); len(got.Replies) != 2 || got.DisableQuotes {
+		t.Errorf("For(summary) = %+v, want the default", got)
+	}
+	// Poster entries replace the default rather than merging with it
+	if got := config.For("yearly"); !got.DisableQuotes || len(got.Replies) != 0 {
+		t.Errorf("For(yearly) = %+v, want only disableQuotes", got)
+	}
+
+	var none *Config
+	if !none.For("summary").IsZero() {
+		t.Error("expected a nil config to leave posts open")
+	}
+}
+
+func TestApply(t *testing.T) {
+	const uri = "at://did:plc:bot/app.bsky.feed.post/abc"
+	ssmClient := fakeParameterGetter{value: `{"posters": {"yearly": {"disableReplies": true}}}`}
+
+	mock := clienttest.NewMockClient()
+	Apply(context.Background(), ssmClient, mock, "yearly", uri)
+	if got, ok := mock.InteractionSettings(uri); !ok || !got.DisableReplies {
+		t.Errorf("expected replies disabled on the yearly post, got %+v", got)
+	}
+
+	mock = clienttest.NewMockClient()
+	Apply(context.Background(), ssmClient, mock, "summary", uri)
+	if got, ok := mock.InteractionSettings(uri); ok {
+		t.Errorf("expected the summary post left open, got %+v", got)
+	}
+
+	mock = clienttest.NewMockClient()
+	Apply(context.Background(), fakeParameterGetter{}, mock, "yearly", uri)
+	if got, ok := mock.InteractionSettings(uri); ok {
+		t.Errorf("expected no settings without the parameter, got %+v", got)
+	}
+}
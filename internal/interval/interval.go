@@ -0,0 +1,64 @@
+// Package interval validates and describes analysis intervals, the length of the window a
+// run analyzes. Runs may cover anything from a minute to a day; every entry point that
+// accepts an interval checks it here.
+package interval
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// Default is the interval used when a run does not request one
+	Default = 30
+
+	// Min and Max bound an interval in minutes; Max is a daily run
+	Min = 1
+	Max = 24 * 60
+)
+
+// Validate checks an interval in minutes. Intervals up to an hour may be any number of
+// minutes; longer ones must be whole hours, so they read as "the last 6 hours".
+func Validate(minutes int) error {
+	if minutes < Min || minutes > Max {
+		return fmt.Errorf("analysis interval must be between %d and %d minutes, got %d", Min, Max, minutes)
+	}
+	if minutes > 60 && minutes%60 != 0 {
+		return fmt.Errorf("analysis intervals over an hour must be whole hours, got %d minutes", minutes)
+	}
+	return nil
+}
+
+// Resolve returns the interval a run should use: Default when none was requested, or the
+// requested interval once validated
+func Resolve(requested int) (int, error) {
+	if requested == 0 {
+		return Default, nil
+	}
+	if err := Validate(requested); err != nil {
+		return 0, err
+	}
+	return requested, nil
+}
+
+// Duration converts an interval in minutes to a time.Duration
+func Duration(minutes int) time.Duration {
+	return time.Duration(minutes) * time.Minute
+}
+
+// Describe names an interval for post text, e.g. "30 min", "hour", "6 hours" or "day", to
+// follow "the last". Returns an empty string when the interval is unknown.
+func Describe(minutes int) string {
+	switch {
+	case minutes <= 0:
+		return ""
+	case minutes == 60:
+		return "hour"
+	case minutes == Max:
+		return "day"
+	case minutes%60 == 0:
+		return fmt.Sprintf("%d hours", minutes/60)
+	default:
+		return fmt.Sprintf("%d min", minutes)
+	}
+}
@@ -0,0 +1,43 @@
+package interval
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	for _, minutes := range []int{1, 15, 45, 60, 120, 360, 1440} {
+		if err := Validate(minutes); err != nil {
+			t.Errorf("Validate(%d) = %v, want nil", minutes, err)
+		}
+	}
+	for _, minutes := range []int{-5, 0, 90, 1441, 2880} {
+		if err := Validate(minutes); err == nil {
+			t.Errorf("Validate(%d) = nil, want an error", minutes)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	if minutes, err := Resolve(0); err != nil || minutes != Default {
+		t.Errorf("Resolve(0) = %d, %v; want the default", minutes, err)
+	}
+	if minutes, err := Resolve(360); err != nil || minutes != 360 {
+		t.Errorf("Resolve(360) = %d, %v; want 360", minutes, err)
+	}
+	if _, err := Resolve(-1); err == nil {
+		t.Error("Resolve(-1) should fail")
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := map[int]string{
+		0:    "",
+		15:   "15 min",
+		60:   "hour",
+		360:  "6 hours",
+		1440: "day",
+	}
+	for minutes, want := range tests {
+		if got := Describe(minutes); got != want {
+			t.Errorf("Describe(%d) = %q, want %q", minutes, got, want)
+		}
+	}
+}
@@ -6,42 +6,60 @@ import (
 
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
+	"github.com/christophergentle/hourstats-bsky/internal/channel"
 	"github.com/christophergentle/hourstats-bsky/internal/config"
+	"github.com/christophergentle/hourstats-bsky/internal/credentials"
+	"github.com/christophergentle/hourstats-bsky/internal/dryrun"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
 )
 
+// SettingsParameterNames are the SSM settings LoadConfig reads; all must exist
+var SettingsParameterNames = []string{
+	"/hourstats/settings/analysis_interval_minutes",
+	"/hourstats/settings/top_posts_count",
+	"/hourstats/settings/min_engagement_score",
+	dryrun.Parameter,
+}
+
+// OptionalSettingsParameterNames are SSM settings LoadConfig reads when they exist; a
+// missing one keeps its default, so deployments needn't create them
+var OptionalSettingsParameterNames = []string{
+	"/hourstats/settings/min_coverage_percent",
+	"/hourstats/settings/min_post_count",
+	aggregation.ThresholdsParameterName,
+}
+
+// ParameterNames are every SSM parameter LoadConfig reads when the credentials are kept in
+// SSM rather than a Secrets Manager secret
+var ParameterNames = append([]string{channel.DefaultHandleParameter, channel.DefaultPasswordParameter}, SettingsParameterNames...)
+
 // SSMConfigLoader handles loading configuration from SSM Parameter Store
 type SSMConfigLoader struct {
-	client *ssm.Client
+	client      *ssm.Client
+	credentials credentials.Provider
 }
 
 // NewSSMConfigLoader creates a new SSM configuration loader
 func NewSSMConfigLoader(ctx context.Context) (*SSMConfigLoader, error) {
-	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, tracing.WithAWS)
 	if err != nil {
 		return nil, err
 	}
 
+	client := ssm.NewFromConfig(cfg)
 	return &SSMConfigLoader{
-		client: ssm.NewFromConfig(cfg),
+		client:      client,
+		credentials: credentials.Default(cfg, client),
 	}, nil
 }
 
 // LoadConfig loads configuration from SSM Parameter Store
 func (s *SSMConfigLoader) LoadConfig(ctx context.Context) (*config.Config, error) {
-	// Define parameter names
-	parameterNames := []string{
-		"/hourstats/bluesky/handle",
-		"/hourstats/bluesky/password",
-		"/hourstats/settings/analysis_interval_minutes",
-		"/hourstats/settings/top_posts_count",
-		"/hourstats/settings/min_engagement_score",
-		"/hourstats/settings/dry_run",
-	}
-
 	// Get parameters from SSM
 	withDecryption := true
 	result, err := s.client.GetParameters(ctx, &ssm.GetParametersInput{
-		Names:          parameterNames,
+		Names:          SettingsParameterNames,
 		WithDecryption: &withDecryption,
 	})
 	if err != nil {
@@ -56,24 +74,29 @@ func (s *SSMConfigLoader) LoadConfig(ctx context.Context) (*config.Config, error
 		}
 	}
 
+	// Optional settings are fetched on their own, since GetParameters reports missing names
+	// as invalid, and any it doesn't find keep their defaults
+	optional, err := s.client.GetParameters(ctx, &ssm.GetParametersInput{
+		Names:          OptionalSettingsParameterNames,
+		WithDecryption: &withDecryption,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	// Create parameter map
 	params := make(map[string]string)
-	for _, param := range result.Parameters {
+	for _, param := range append(result.Parameters, optional.Parameters...) {
 		if param.Name != nil && param.Value != nil {
 			params[*param.Name] = *param.Value
 		}
 	}
 
-	// Validate required parameters
-	if handle, ok := params["/hourstats/bluesky/handle"]; !ok || handle == "" {
-		return nil, &ConfigError{
-			Message: "Missing required parameter: /hourstats/bluesky/handle",
-		}
-	}
-
-	if password, ok := params["/hourstats/bluesky/password"]; !ok || password == "" {
+	// Find the Bluesky credentials, in the Secrets Manager secret or SSM
+	account, err := s.credentials.Retrieve(ctx)
+	if err != nil {
 		return nil, &ConfigError{
-			Message: "Missing required parameter: /hourstats/bluesky/password",
+			Message: "Missing Bluesky credentials: " + err.Error(),
 		}
 	}
 
@@ -81,23 +104,62 @@ func (s *SSMConfigLoader) LoadConfig(ctx context.Context) (*config.Config, error
 	analysisIntervalMinutes := parseIntWithDefault(params["/hourstats/settings/analysis_interval_minutes"], 60)
 	topPostsCount := parseIntWithDefault(params["/hourstats/settings/top_posts_count"], 5)
 	minEngagementScore := parseIntWithDefault(params["/hourstats/settings/min_engagement_score"], 10)
+	minPostCount := parseIntWithDefault(params["/hourstats/settings/min_post_count"], 100)
 
-	// Parse boolean parameter with default
-	dryRun := parseBoolWithDefault(params["/hourstats/settings/dry_run"], false)
+	minCoveragePercent := parseFloatWithDefault(params["/hourstats/settings/min_coverage_percent"], 80)
 
-	// Create and return config
-	return &config.Config{
+	sentimentThresholds, err := aggregation.ParseThresholds(params[aggregation.ThresholdsParameterName])
+	if err != nil {
+		return nil, &ConfigError{
+			Message: "Invalid sentiment thresholds: " + err.Error(),
+		}
+	}
+
+	// An unknown dry run level is kept as given, for Validate to report. A staging
+	// deployment runs at Staging whatever production's parameter says.
+	dryRun, err := dryrun.Parse(params[dryrun.Parameter])
+	if err != nil {
+		dryRun = dryrun.Level(params[dryrun.Parameter])
+	}
+	dryRun = dryrun.ForDeployment(dryRun)
+
+	// A shadow dry run posts from the shadow account, so it needs its credentials too
+	var shadow credentials.Credentials
+	if dryRun.ShadowAccount() {
+		if shadow, err = dryRun.Credentials(s.credentials, s.client).Retrieve(ctx); err != nil {
+			return nil, &ConfigError{
+				Message: "Missing shadow account credentials: " + err.Error(),
+			}
+		}
+	}
+
+	// Create and validate config
+	cfg := &config.Config{
 		Bluesky: config.BlueskyConfig{
-			Handle:   params["/hourstats/bluesky/handle"],
-			Password: params["/hourstats/bluesky/password"],
+			Handle:   account.Handle,
+			Password: account.Password,
+		},
+		Shadow: config.BlueskyConfig{
+			Handle:   shadow.Handle,
+			Password: shadow.Password,
 		},
 		Settings: config.SettingsConfig{
 			AnalysisIntervalMinutes: analysisIntervalMinutes,
 			TopPostsCount:           topPostsCount,
 			MinEngagementScore:      minEngagementScore,
 			DryRun:                  dryRun,
+			MinCoveragePercent:      minCoveragePercent,
+			MinPostCount:            minPostCount,
+			SentimentThresholds:     sentimentThresholds,
 		},
-	}, nil
+		Tables: config.TablesFromEnv(),
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, &ConfigError{
+			Message: "Invalid configuration: " + err.Error(),
+		}
+	}
+	return cfg, nil
 }
 
 // parseIntWithDefault parses an integer with a default value
@@ -114,13 +176,13 @@ func parseIntWithDefault(value string, defaultValue int) int {
 	return parsed
 }
 
-// parseBoolWithDefault parses a boolean with a default value
-func parseBoolWithDefault(value string, defaultValue bool) bool {
+// parseFloatWithDefault parses a float with a default value
+func parseFloatWithDefault(value string, defaultValue float64) float64 {
 	if value == "" {
 		return defaultValue
 	}
 
-	parsed, err := strconv.ParseBool(value)
+	parsed, err := strconv.ParseFloat(value, 64)
 	if err != nil {
 		return defaultValue
 	}
@@ -20,7 +20,7 @@ type AnalysisResult struct {
 
 // HourStatsAnalyzer handles the main analysis logic for Lambda
 type HourStatsAnalyzer struct {
-	client   *client.BlueskyClient
+	client   client.Client
 	analyzer *analyzer.SentimentAnalyzer
 	config   *config.Config
 }
@@ -86,9 +86,16 @@ func (h *HourStatsAnalyzer) RunAnalysis(ctx context.Context) (*AnalysisResult, e
 	// Convert back to client posts for posting
 	clientTopPosts := h.convertToClientPosts(topPosts)
 
-	// Post the results (skip if dry run)
-	if !h.config.Settings.DryRun {
-		_, _, err := h.client.PostTrendingSummary(clientTopPosts, overallSentiment, h.config.Settings.AnalysisIntervalMinutes, totalPosts, netSentimentPercentage)
+	// Post the results, unless a dry run holds them back
+	if h.config.Settings.DryRun.Posts() {
+		poster, err := h.poster()
+		if err != nil {
+			return &AnalysisResult{
+				Success:      false,
+				ErrorMessage: "Failed to authenticate the shadow account: " + err.Error(),
+			}, err
+		}
+		_, _, err = poster.PostTrendingSummary(clientTopPosts, overallSentiment, h.config.Settings.AnalysisIntervalMinutes, totalPosts, netSentimentPercentage)
 		if err != nil {
 			return &AnalysisResult{
 				Success:      false,
@@ -97,7 +104,7 @@ func (h *HourStatsAnalyzer) RunAnalysis(ctx context.Context) (*AnalysisResult, e
 		}
 		log.Printf("Successfully posted trending summary with %d posts", len(clientTopPosts))
 	} else {
-		log.Println("Dry run mode: Skipping post to Bluesky")
+		log.Printf("Dry run (%s): Skipping post to Bluesky", h.config.Settings.DryRun)
 	}
 
 	return &AnalysisResult{
@@ -108,6 +115,20 @@ func (h *HourStatsAnalyzer) RunAnalysis(ctx context.Context) (*AnalysisResult, e
 	}, nil
 }
 
+// poster returns the client to post with: the shadow account's during a shadow dry run,
+// else the one the posts were fetched with
+func (h *HourStatsAnalyzer) poster() (client.Client, error) {
+	if !h.config.Settings.DryRun.ShadowAccount() {
+		return h.client, nil
+	}
+	shadow := client.New(h.config.Shadow.Handle, h.config.Shadow.Password)
+	if err := shadow.Authenticate(); err != nil {
+		return nil, err
+	}
+	log.Printf("Shadow dry run: posting as %s", h.config.Shadow.Handle)
+	return shadow, nil
+}
+
 // convertToAnalyzerPosts converts client posts to analyzer posts
 func (h *HourStatsAnalyzer) convertToAnalyzerPosts(clientPosts []client.Post) []analyzer.Post {
 	var analyzerPosts []analyzer.Post
@@ -175,14 +196,7 @@ func (h *HourStatsAnalyzer) calculateOverallSentiment(posts []analyzer.AnalyzedP
 	averageCompoundScore := totalCompoundScore / float64(len(posts))
 
 	// Map compound score to category for backward compatibility
-	var sentimentCategory string
-	if averageCompoundScore >= 0.3 {
-		sentimentCategory = "positive"
-	} else if averageCompoundScore <= -0.3 {
-		sentimentCategory = "negative"
-	} else {
-		sentimentCategory = "neutral"
-	}
+	sentimentCategory := h.config.Settings.SentimentThresholds.Categorize(averageCompoundScore, "")
 
 	// Scale to percentage range for 100-word system
 	netSentimentPercentage := averageCompoundScore * 100.0
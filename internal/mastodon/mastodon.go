@@ -0,0 +1,154 @@
+// Package mastodon reads a Mastodon instance's public timeline, so Bluesky sentiment can
+// be compared with another network's over the same window
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+)
+
+// pageSize is the largest page the public timeline API returns
+const pageSize = 40
+
+// requestTimeout bounds each timeline request
+const requestTimeout = 20 * time.Second
+
+// Client reads public timelines from one Mastodon instance; no account is needed
+type Client struct {
+	instance   string
+	local      bool
+	httpClient *http.Client
+}
+
+// NewClient creates a client for instance, e.g. "https://mastodon.social". When local is
+// true only the instance's own posts are read, rather than everything it federates.
+func NewClient(instance string, local bool) *Client {
+	return &Client{
+		instance:   strings.TrimRight(instance, "/"),
+		local:      local,
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// status is the subset of a Mastodon status the fetcher needs
+type status struct {
+	ID               string    `json:"id"`
+	URI              string    `json:"uri"`
+	CreatedAt        time.Time `json:"created_at"`
+	Content          string    `json:"content"`
+	Language         string    `json:"language"`
+	RepliesCount     int       `json:"replies_count"`
+	ReblogsCount     int       `json:"reblogs_count"`
+	FavouritesCount  int       `json:"favourites_count"`
+	Sensitive        bool      `json:"sensitive"`
+	Reblog           *status   `json:"reblog"`
+	MediaAttachments []struct {
+		Type string `json:"type"`
+	} `json:"media_attachments"`
+	Account struct {
+		Acct   string `json:"acct"`
+		Avatar string `json:"avatar"`
+	} `json:"account"`
+}
+
+// TimelineBatch fetches one page of the public timeline, newest first, matching the
+// fetcher's batch signature. The cursor is the ID of the oldest status already read.
+// Boosts are skipped, since the boosted post is not new in the window.
+func (c *Client) TimelineBatch(ctx context.Context, cursor string, cutoffTime time.Time) ([]client.Post, string, bool, error) {
+	query := url.Values{"limit": {fmt.Sprint(pageSize)}}
+	if c.local {
+		query.Set("local", "true")
+	}
+	if cursor != "" {
+		query.Set("max_id", cursor)
+	}
+	endpoint := c.instance + "/api/v1/timelines/public?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("failed to build timeline request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("failed to fetch %s timeline: %w", c.instance, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, "", false, fmt.Errorf("%s timeline returned %s: %s", c.instance, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var statuses []status
+	if err := json.NewDecoder(resp.Body).Decode(&statuses); err != nil {
+		return nil, "", false, fmt.Errorf("invalid %s timeline response: %w", c.instance, err)
+	}
+	if len(statuses) == 0 {
+		return nil, "", false, nil
+	}
+
+	posts := make([]client.Post, 0, len(statuses))
+	for _, s := range statuses {
+		if s.Reblog != nil {
+			continue
+		}
+		posts = append(posts, toPost(s))
+	}
+
+	return posts, statuses[len(statuses)-1].ID, true, nil
+}
+
+// toPost converts a status to the fetcher's post shape, with favourites as likes and
+// boosts as reposts
+func toPost(s status) client.Post {
+	post := client.Post{
+		URI:             s.URI,
+		Text:            PlainText(s.Content),
+		Author:          s.Account.Acct,
+		Likes:           s.FavouritesCount,
+		Reposts:         s.ReblogsCount,
+		Replies:         s.RepliesCount,
+		CreatedAt:       s.CreatedAt.UTC().Format(time.RFC3339),
+		EngagementScore: float64(s.FavouritesCount + s.ReblogsCount + s.RepliesCount),
+		AvatarURL:       s.Account.Avatar,
+	}
+	if s.Language != "" {
+		post.Langs = []string{s.Language}
+	}
+	if len(s.MediaAttachments) > 0 {
+		switch s.MediaAttachments[0].Type {
+		case "video", "gifv":
+			post.Media = client.MediaVideo
+		default:
+			post.Media = client.MediaImage
+		}
+	}
+	return post
+}
+
+var (
+	breakTags = regexp.MustCompile(`(?i)<br\s*/?>|</p>`)
+	tags      = regexp.MustCompile(`<[^>]*>`)
+	spaces    = regexp.MustCompile(`[ \t]+`)
+)
+
+// PlainText converts a status's HTML content to the plain text the analyzer scores
+func PlainText(content string) string {
+	text := breakTags.ReplaceAllString(content, "\n")
+	text = tags.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	text = spaces.ReplaceAllString(text, " ")
+	return strings.TrimSpace(text)
+}
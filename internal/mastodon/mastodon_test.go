@@ -0,0 +1,78 @@
+package mastodon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const timelinePage = `[
+  {"id": "103", "uri": "https://mastodon.example/users/alice/statuses/103", "created_at": "2025-09-02T12:10:00.000Z",
+   "content": "<p>Lovely &amp; sunny</p><p>morning</p>", "language": "en", "replies_count": 1, "reblogs_count": 2, "favourites_count": 3,
+   "account": {"acct": "alice", "avatar": "https://mastodon.example/a.png"}, "media_attachments": [{"type": "image"}]},
+  {"id": "102", "uri": "https://mastodon.example/users/bob/statuses/102", "created_at": "2025-09-02T12:05:00.000Z",
+   "content": "", "reblog": {"id": "9", "uri": "https://other.example/9"}, "account": {"acct": "bob"}},
+  {"id": "101", "uri": "https://other.example/users/carol/statuses/101", "created_at": "2025-09-02T12:00:00.000Z",
+   "content": "<p>Awful commute<br>again</p>", "account": {"acct": "carol@other.example"}}
+]`
+
+func TestTimelineBatch(t *testing.T) {
+	var query map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/timelines/public" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		query = r.URL.Query()
+		w.Write([]byte(timelinePage))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", true)
+	posts, cursor, hasMore, err := c.TimelineBatch(context.Background(), "200", time.Time{})
+	if err != nil {
+		t.Fatalf("TimelineBatch() error = %v", err)
+	}
+
+	if query["max_id"][0] != "200" || query["local"][0] != "true" || query["limit"][0] != "40" {
+		t.Errorf("Unexpected query %v", query)
+	}
+	if cursor != "101" || !hasMore {
+		t.Errorf("Expected cursor 101 with more pages, got %q %v", cursor, hasMore)
+	}
+	if len(posts) != 2 {
+		t.Fatalf("Expected the boost to be skipped, got %d posts", len(posts))
+	}
+
+	alice := posts[0]
+	if alice.Text != "Lovely & sunny\nmorning" || alice.Author != "alice" || alice.CreatedAt != "2025-09-02T12:10:00Z" {
+		t.Errorf("Unexpected post %+v", alice)
+	}
+	if alice.Likes != 3 || alice.Reposts != 2 || alice.Replies != 1 || alice.EngagementScore != 6 || alice.Media != "image" || alice.Langs[0] != "en" {
+		t.Errorf("Unexpected engagement or metadata %+v", alice)
+	}
+	if posts[1].Text != "Awful commute\nagain" {
+		t.Errorf("Expected line breaks preserved, got %q", posts[1].Text)
+	}
+}
+
+func TestTimelineBatchEmptyAndErrors(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, false)
+	posts, cursor, hasMore, err := c.TimelineBatch(context.Background(), "", time.Time{})
+	if err != nil || len(posts) != 0 || cursor != "" || hasMore {
+		t.Errorf("Expected an empty last page, got %v %q %v %v", posts, cursor, hasMore, err)
+	}
+
+	status = http.StatusTooManyRequests
+	if _, _, _, err := c.TimelineBatch(context.Background(), "", time.Time{}); err == nil {
+		t.Error("Expected an error for a rate-limited response")
+	}
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Namespace is the CloudWatch namespace all HourStats metrics are published under
+const Namespace = "HourStats"
+
+// NamespaceEnv overrides Namespace, so a staging deployment's metrics stay out of
+// production's dashboards and alarms
+const NamespaceEnv = "METRICS_NAMESPACE"
+
+// namespace returns the namespace metrics are published under
+func namespace() string {
+	if ns := os.Getenv(NamespaceEnv); ns != "" {
+		return ns
+	}
+	return Namespace
+}
+
+// Common CloudWatch units
+const (
+	UnitCount        = "Count"
+	UnitPercent      = "Percent"
+	UnitMilliseconds = "Milliseconds"
+)
+
+// Metric is a single named measurement
+type Metric struct {
+	Name  string
+	Value float64
+	Unit  string
+}
+
+// Emit writes metrics to stdout in CloudWatch Embedded Metric Format
+// Lambda forwards stdout to CloudWatch Logs, which extracts the metrics without any API calls
+// Dimensions are optional and apply to every metric in the call
+func Emit(dimensions map[string]string, metrics ...Metric) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	definitions := make([]map[string]string, len(metrics))
+	payload := make(map[string]interface{}, len(metrics)+len(dimensions)+1)
+	for i, m := range metrics {
+		definitions[i] = map[string]string{"Name": m.Name, "Unit": m.Unit}
+		payload[m.Name] = m.Value
+	}
+
+	dimensionKeys := make([]string, 0, len(dimensions))
+	for key, value := range dimensions {
+		dimensionKeys = append(dimensionKeys, key)
+		payload[key] = value
+	}
+
+	directive := map[string]interface{}{
+		"Namespace":  namespace(),
+		"Dimensions": [][]string{dimensionKeys},
+		"Metrics":    definitions,
+	}
+	payload["_aws"] = map[string]interface{}{
+		"Timestamp":         time.Now().UnixMilli(),
+		"CloudWatchMetrics": []interface{}{directive},
+	}
+
+	line, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metrics: %w", err)
+	}
+
+	// Write directly rather than through log so the line isn't prefixed with a timestamp
+	_, err = fmt.Fprintln(os.Stdout, string(line))
+	return err
+}
@@ -0,0 +1,233 @@
+// Package pin decides which of the bot's posts stays pinned to its profile, based on
+// optional settings stored in SSM, and records each change in the pin history
+package pin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// ParameterName holds the JSON pin policy; when it is absent only yearly charts are pinned
+const ParameterName = "/hourstats/settings/pinned_post"
+
+// Kinds of post that can be pinned
+const (
+	KindYearly    = "yearly"    // the yearly sentiment chart
+	KindMonthly   = "monthly"   // the 30 day sentiment chart
+	KindQuarterly = "quarterly" // the quarterly sentiment chart
+	KindWeekly    = "weekly"    // the weekly sparkline chart
+	KindMilestone = "milestone" // a summary flagged as unusual for its hour
+)
+
+// Policy decides which posts are pinned. Priority lists the kinds to pin, highest first:
+// a new post replaces the current pin unless the current pin has a higher priority and
+// is still within its hold. Kinds without a hold keep their pin until a post of equal
+// or higher priority replaces it.
+//
+//	{"priority": ["milestone", "yearly", "weekly"], "hold": {"milestone": "3d"}}
+type Policy struct {
+	Priority []string
+	Hold     map[string]time.Duration
+}
+
+// policyJSON is the SSM representation of Policy
+type policyJSON struct {
+	Priority []string          `json:"priority"`
+	Hold     map[string]string `json:"hold,omitempty"`
+}
+
+// Default pins every yearly chart, as the yearly poster always has
+func Default() Policy {
+	return Policy{Priority: []string{KindYearly}}
+}
+
+// ParameterGetter is the subset of the SSM client Load needs
+type ParameterGetter interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Load reads the pin policy from SSM. A missing or empty parameter returns the default policy.
+func Load(ctx context.Context, ssmClient ParameterGetter) (Policy, error) {
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ParameterName),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return Default(), nil
+		}
+		return Default(), fmt.Errorf("failed to get %s: %w", ParameterName, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return Default(), nil
+	}
+	return Parse(*result.Parameter.Value)
+}
+
+// Parse decodes and validates a JSON pin policy. An empty priority list pins nothing.
+func Parse(value string) (Policy, error) {
+	if strings.TrimSpace(value) == "" {
+		return Default(), nil
+	}
+
+	var raw policyJSON
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		return Default(), fmt.Errorf("invalid pin policy JSON: %w", err)
+	}
+
+	policy := Policy{Hold: make(map[string]time.Duration)}
+	seen := make(map[string]bool)
+	for _, kind := range raw.Priority {
+		if err := validateKind(kind); err != nil {
+			return Default(), err
+		}
+		if seen[kind] {
+			return Default(), fmt.Errorf("pin kind %q listed twice", kind)
+		}
+		seen[kind] = true
+		policy.Priority = append(policy.Priority, kind)
+	}
+	for kind, value := range raw.Hold {
+		if err := validateKind(kind); err != nil {
+			return Default(), err
+		}
+		hold, err := parseHold(value)
+		if err != nil {
+			return Default(), fmt.Errorf("invalid %s hold: %w", kind, err)
+		}
+		policy.Hold[kind] = hold
+	}
+
+	return policy, nil
+}
+
+// rank returns kind's position in the priority list, or -1 if it is not pinned
+func (p Policy) rank(kind string) int {
+	for i, k := range p.Priority {
+		if k == kind {
+			return i
+		}
+	}
+	return -1
+}
+
+// ShouldPin reports whether a new post of kind should replace current, with the reason
+func (p Policy) ShouldPin(kind string, current *state.PinRecord, now time.Time) (bool, string) {
+	rank := p.rank(kind)
+	if rank < 0 {
+		return false, fmt.Sprintf("%s posts are not pinned", kind)
+	}
+	if current == nil {
+		return true, "nothing pinned"
+	}
+
+	currentRank := p.rank(current.Kind)
+	switch {
+	case currentRank < 0:
+		return true, fmt.Sprintf("%s posts are no longer pinned", current.Kind)
+	case rank <= currentRank:
+		return true, fmt.Sprintf("%s replaces %s", kind, current.Kind)
+	}
+
+	hold, ok := p.Hold[current.Kind]
+	if ok && now.Sub(current.PinnedAt) >= hold {
+		return true, fmt.Sprintf("%s pin held for %s", current.Kind, hold)
+	}
+	return false, fmt.Sprintf("%s pin takes priority", current.Kind)
+}
+
+// HistoryStore loads and saves the pin history; *state.StateManager satisfies it
+type HistoryStore interface {
+	GetPinHistory(ctx context.Context) (*state.PinHistory, error)
+	SavePinHistory(ctx context.Context, history *state.PinHistory) error
+}
+
+// Pinner pins and unpins posts on the bot's profile; client.BskyPoster satisfies it
+type Pinner interface {
+	PinPost(ctx context.Context, postURI string, postCID string) error
+	UnpinPost(ctx context.Context) error
+}
+
+// Rotate offers a newly published post of kind for pinning. It pins the post when the
+// policy prefers it over the current pin, unpins a current pin whose kind the policy no
+// longer lists, and records either change in the history. Failures are logged rather
+// than returned, since the post itself has already been published.
+func Rotate(ctx context.Context, ssmClient ParameterGetter, store HistoryStore, pinner Pinner, kind, postURI, postCID string) {
+	policy, err := Load(ctx, ssmClient)
+	if err != nil {
+		log.Printf("Ignoring pin policy: %v", err)
+	}
+
+	history, err := store.GetPinHistory(ctx)
+	if err != nil {
+		log.Printf("Failed to load pin history, not pinning %s: %v", postURI, err)
+		return
+	}
+
+	now := time.Now().UTC()
+	pin, reason := policy.ShouldPin(kind, history.Current, now)
+	if !pin {
+		log.Printf("📌 Not pinning %s post %s: %s", kind, postURI, reason)
+		if history.Current == nil || policy.rank(history.Current.Kind) >= 0 {
+			return
+		}
+		if err := pinner.UnpinPost(ctx); err != nil {
+			log.Printf("Failed to unpin %s: %v", history.Current.URI, err)
+			return
+		}
+		log.Printf("📌 Unpinned %s post %s", history.Current.Kind, history.Current.URI)
+		history.Unpin(now)
+	} else {
+		if err := pinner.PinPost(ctx, postURI, postCID); err != nil {
+			log.Printf("Failed to pin %s post: %v (post was successful)", kind, err)
+			return
+		}
+		log.Printf("📌 Pinned %s post %s: %s", kind, postURI, reason)
+		history.Pin(state.PinRecord{URI: postURI, CID: postCID, Kind: kind, PinnedAt: now})
+	}
+
+	if err := store.SavePinHistory(ctx, history); err != nil {
+		log.Printf("Failed to save pin history: %v", err)
+	}
+}
+
+// validateKind checks kind is one of the pinnable kinds
+func validateKind(kind string) error {
+	switch kind {
+	case KindYearly, KindQuarterly, KindMonthly, KindWeekly, KindMilestone:
+		return nil
+	}
+	return fmt.Errorf("unknown pin kind %q: want %s, %s, %s, %s or %s", kind, KindYearly, KindQuarterly, KindMonthly, KindWeekly, KindMilestone)
+}
+
+// parseHold accepts a Go duration ("36h") or a whole number of days ("3d")
+func parseHold(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%q is not a whole number of days", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	hold, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a duration: %w", value, err)
+	}
+	if hold < 0 {
+		return 0, fmt.Errorf("%q is negative", value)
+	}
+	return hold, nil
+}
@@ -0,0 +1,148 @@
+package pin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/christophergentle/hourstats-bsky/internal/client/clienttest"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+type fakeParameterGetter struct {
+	value string
+}
+
+func (f fakeParameterGetter) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	if f.value == "" {
+		return nil, &types.ParameterNotFound{}
+	}
+	return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: aws.String(f.value)}}, nil
+}
+
+type fakeHistoryStore struct {
+	history *state.PinHistory
+	saves   int
+	err     error
+}
+
+func (f *fakeHistoryStore) GetPinHistory(ctx context.Context) (*state.PinHistory, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.history == nil {
+		f.history = &state.PinHistory{}
+	}
+	return f.history, nil
+}
+
+func (f *fakeHistoryStore) SavePinHistory(ctx context.Context, history *state.PinHistory) error {
+	f.history = history
+	f.saves++
+	return nil
+}
+
+func TestParse(t *testing.T) {
+	policy, err := Parse(`{"priority": ["milestone", "yearly"], "hold": {"milestone": "3d", "yearly": "36h"}}`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(policy.Priority) != 2 || policy.Hold[KindMilestone] != 72*time.Hour || policy.Hold[KindYearly] != 36*time.Hour {
+		t.Errorf("unexpected policy %+v", policy)
+	}
+
+	for _, value := range []string{
+		`{"priority": ["daily"]}`,
+		`{"priority": ["weekly", "weekly"]}`,
+		`{"priority": ["weekly"], "hold": {"weekly": "soon"}}`,
+		`{"priority": ["weekly"], "hold": {"summary": "1d"}}`,
+		`{"priority": `,
+	} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("Parse(%s) expected an error", value)
+		}
+	}
+
+	if policy, err := Parse(" "); err != nil || len(policy.Priority) != 1 || policy.Priority[0] != KindYearly {
+		t.Errorf("Parse(empty) = %+v, %v; want the default policy", policy, err)
+	}
+}
+
+func TestShouldPin(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	policy := Policy{
+		Priority: []string{KindMilestone, KindYearly, KindWeekly},
+		Hold:     map[string]time.Duration{KindMilestone: 72 * time.Hour},
+	}
+	pinned := func(kind string, age time.Duration) *state.PinRecord {
+		return &state.PinRecord{URI: "at://current", Kind: kind, PinnedAt: now.Add(-age)}
+	}
+
+	tests := []struct {
+		name    string
+		kind    string
+		current *state.PinRecord
+		want    bool
+	}{
+		{"nothing pinned", KindWeekly, nil, true},
+		{"same kind replaces", KindWeekly, pinned(KindWeekly, time.Hour), true},
+		{"higher priority replaces", KindMilestone, pinned(KindYearly, time.Hour), true},
+		{"lower priority waits for hold", KindWeekly, pinned(KindMilestone, 24*time.Hour), false},
+		{"lower priority after hold", KindWeekly, pinned(KindMilestone, 72*time.Hour), true},
+		{"no hold keeps pin", KindWeekly, pinned(KindYearly, 300*24*time.Hour), false},
+		{"unlisted current is replaced", KindWeekly, pinned("retired", time.Hour), true},
+		{"unlisted kind is never pinned", "summary", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := policy.ShouldPin(tt.kind, tt.current, now)
+			if got != tt.want {
+				t.Errorf("ShouldPin(%s) = %v (%s), want %v", tt.kind, got, reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	ctx := context.Background()
+	ssmClient := fakeParameterGetter{value: `{"priority": ["milestone", "weekly"], "hold": {"milestone": "2d"}}`}
+	store := &fakeHistoryStore{}
+	mock := clienttest.NewMockClient()
+
+	Rotate(ctx, ssmClient, store, mock, KindMilestone, "at://milestone", "cid1")
+	Rotate(ctx, ssmClient, store, mock, KindWeekly, "at://weekly", "cid2")
+
+	pinned := mock.Pinned()
+	if len(pinned) != 1 || pinned[0] != "at://milestone" {
+		t.Fatalf("expected only the milestone pinned while it is held, got %v", pinned)
+	}
+	if store.history.Current == nil || store.history.Current.Kind != KindMilestone {
+		t.Fatalf("expected the milestone recorded as current, got %+v", store.history.Current)
+	}
+
+	// Dropping milestones from the policy unpins the held milestone on the next rotation
+	Rotate(ctx, fakeParameterGetter{value: `{"priority": []}`}, store, mock, KindWeekly, "at://weekly2", "cid3")
+	if mock.Unpins() != 1 {
+		t.Errorf("expected the retired milestone pin to be cleared, got %d unpins", mock.Unpins())
+	}
+	if store.history.Current != nil || len(store.history.History) != 1 || store.history.History[0].URI != "at://milestone" {
+		t.Errorf("expected the milestone moved to the history, got %+v / %+v", store.history.Current, store.history.History)
+	}
+
+	// Without a policy only yearly charts are pinned
+	Rotate(ctx, fakeParameterGetter{}, store, mock, KindYearly, "at://yearly", "cid4")
+	if pinned := mock.Pinned(); pinned[len(pinned)-1] != "at://yearly" {
+		t.Errorf("expected the yearly chart pinned by default, got %v", pinned)
+	}
+
+	// A history that cannot be read leaves the pin alone
+	failing := &fakeHistoryStore{err: errors.New("unavailable")}
+	Rotate(ctx, fakeParameterGetter{}, failing, mock, KindYearly, "at://yearly2", "cid5")
+	if pinned := mock.Pinned(); pinned[len(pinned)-1] != "at://yearly" {
+		t.Errorf("expected no pin without history, got %v", pinned)
+	}
+}
@@ -0,0 +1,374 @@
+// Package pipeline runs the whole hourstats cycle in one process: it orchestrates a run
+// over the last interval, fetches the window's posts and processes them into a summary,
+// sharing the client, analyzer and formatter with the Lambdas. It backs the commands that
+// run without AWS, which keep sentiment history in memory or a file instead of DynamoDB.
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
+	"github.com/christophergentle/hourstats-bsky/internal/analyzer"
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/dryrun"
+	"github.com/christophergentle/hourstats-bsky/internal/formatter"
+	"github.com/christophergentle/hourstats-bsky/internal/insight"
+	"github.com/christophergentle/hourstats-bsky/internal/interval"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+const (
+	// trendSegmentCount and minTrendThirdPosts match the processor's trend note
+	trendSegmentCount  = 6
+	minTrendThirdPosts = 20
+
+	// DefaultTopPostsCount is how many top posts a summary lists unless configured
+	DefaultTopPostsCount = 5
+
+	// historyRetention is how much sentiment history a pipeline keeps; the volatility
+	// note only looks back a day
+	historyRetention = 2 * insight.VolatilityWindow
+
+	// previousCategoryLookback is how recent the previous window must be for the
+	// thresholds' hysteresis to favour its category, as in the processor
+	previousCategoryLookback = 6 * time.Hour
+)
+
+// run is one analysis window, the equivalent of the orchestrator's run state
+type run struct {
+	ID              string
+	IntervalMinutes int
+	CutoffTime      time.Time
+	WindowEnd       time.Time
+}
+
+// Options configure a pipeline
+type Options struct {
+	Handle   string
+	Password string
+	// IntervalMinutes is the length of each run's window, from 1 to 1440
+	IntervalMinutes int
+	// Queries scope the fetch to search queries, merged as OR; none searches all public posts
+	Queries       []string
+	TopPostsCount int
+	// MinPostCount skips the summary of windows with fewer posts
+	MinPostCount int
+	// Thresholds categorize each window's average score, favouring the previous window's
+	// category when they have hysteresis; the zero value is aggregation.DefaultThresholds
+	Thresholds aggregation.Thresholds
+	// DryRun logs summaries instead of posting them at no-post, and also leaves the
+	// history file unsaved at no-write; shadow posts them from the shadow account
+	DryRun dryrun.Level
+	// ShadowHandle and ShadowPassword are the shadow account, needed for a shadow dry run
+	ShadowHandle   string
+	ShadowPassword string
+	// HistoryPath, when set, saves the sentiment history between processes
+	HistoryPath string
+}
+
+// Pipeline runs the orchestrate→fetch→process cycle of the Lambda deployment in a single
+// process, keeping its sentiment history in memory instead of DynamoDB
+type Pipeline struct {
+	options   Options
+	newClient client.Factory
+	analyzer  *analyzer.SentimentAnalyzer
+	history   []state.SentimentDataPoint
+
+	now func() time.Time
+}
+
+// New creates a pipeline, loading the saved sentiment history if there is one
+func New(options Options) (*Pipeline, error) {
+	if err := interval.Validate(options.IntervalMinutes); err != nil {
+		return nil, fmt.Errorf("invalid analysis interval: %w", err)
+	}
+	if options.TopPostsCount <= 0 {
+		options.TopPostsCount = DefaultTopPostsCount
+	}
+	if options.Thresholds == (aggregation.Thresholds{}) {
+		options.Thresholds = aggregation.DefaultThresholds
+	}
+	if err := options.Thresholds.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid sentiment thresholds: %w", err)
+	}
+	if err := options.DryRun.Validate(); err != nil {
+		return nil, err
+	}
+	if options.DryRun.ShadowAccount() && (options.ShadowHandle == "" || options.ShadowPassword == "") {
+		return nil, errors.New("a shadow dry run needs the shadow account's handle and password")
+	}
+
+	p := &Pipeline{
+		options:   options,
+		newClient: client.NewClient,
+		analyzer:  analyzer.New(),
+		now:       time.Now,
+	}
+	if err := p.loadHistory(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// RunOnce orchestrates, fetches and processes one window ending now
+func (p *Pipeline) RunOnce(ctx context.Context) error {
+	r := p.orchestrate()
+	log.Printf("🎯 PIPELINE: Starting run %s (%s to %s UTC)", r.ID,
+		r.CutoffTime.Format("15:04:05"), r.WindowEnd.Format("15:04:05"))
+
+	blueskyClient := p.newClient(p.options.Handle, p.options.Password)
+	if err := blueskyClient.Authenticate(); err != nil {
+		return fmt.Errorf("failed to authenticate with Bluesky: %w", err)
+	}
+
+	posts, err := p.fetch(ctx, blueskyClient, r)
+	if err != nil {
+		return fmt.Errorf("failed to fetch posts: %w", err)
+	}
+
+	var poster client.BskyPoster = blueskyClient
+	if p.options.DryRun.ShadowAccount() {
+		shadow := p.newClient(p.options.ShadowHandle, p.options.ShadowPassword)
+		if err := shadow.Authenticate(); err != nil {
+			return fmt.Errorf("failed to authenticate the shadow account: %w", err)
+		}
+		poster = shadow
+	}
+	return p.process(poster, r, posts)
+}
+
+// orchestrate starts a run over the interval ending now
+func (p *Pipeline) orchestrate() run {
+	now := p.now().UTC()
+	return run{
+		ID:              fmt.Sprintf("run-%d", now.UnixNano()),
+		IntervalMinutes: p.options.IntervalMinutes,
+		CutoffTime:      now.Add(-interval.Duration(p.options.IntervalMinutes)),
+		WindowEnd:       now,
+	}
+}
+
+// fetch pages through the window's posts until it reaches the cutoff. Fetching is given at
+// most one interval, so a busy window can't hold up the next run; the posts collected by
+// then are processed.
+func (p *Pipeline) fetch(ctx context.Context, fetcher client.BskyFetcher, r run) ([]client.Post, error) {
+	fetchBatch := fetcher.GetTrendingPostsBatch
+	if len(p.options.Queries) > 0 {
+		fetchBatch = client.NewMultiSearch(fetcher, p.options.Queries).Batch
+	}
+	deadline := p.now().Add(interval.Duration(r.IntervalMinutes))
+
+	var posts []client.Post
+	seen := make(map[string]bool)
+	cursor := ""
+	for page := 1; ; page++ {
+		batch, nextCursor, hasMore, err := fetchBatch(ctx, cursor, r.CutoffTime)
+		if err != nil {
+			if len(posts) > 0 {
+				log.Printf("⚠️ PIPELINE: Fetch stopped on page %d, processing %d posts: %v", page, len(posts), err)
+				return posts, nil
+			}
+			return nil, err
+		}
+
+		reachedCutoff := false
+		for _, post := range batch {
+			createdAt, err := time.Parse(time.RFC3339, post.CreatedAt)
+			if err == nil && createdAt.Before(r.CutoffTime) {
+				reachedCutoff = true
+				continue
+			}
+			if !seen[post.URI] {
+				seen[post.URI] = true
+				posts = append(posts, post)
+			}
+		}
+
+		if reachedCutoff || !hasMore || nextCursor == "" {
+			break
+		}
+		if p.now().After(deadline) {
+			log.Printf("⏰ PIPELINE: Fetch ran longer than the interval, processing %d posts", len(posts))
+			break
+		}
+		cursor = nextCursor
+	}
+
+	log.Printf("📥 PIPELINE: Fetched %d posts", len(posts))
+	return posts, nil
+}
+
+// process analyzes the posts and posts the summary, or logs it in dry run mode
+func (p *Pipeline) process(poster client.BskyPoster, r run, posts []client.Post) error {
+	if len(posts) < p.options.MinPostCount {
+		log.Printf("⏭️ PIPELINE: Skipping the summary, %d posts is below the minimum of %d", len(posts), p.options.MinPostCount)
+		return nil
+	}
+
+	analyzerPosts := make([]analyzer.Post, len(posts))
+	for i, post := range posts {
+		analyzerPosts[i] = analyzer.Post{
+			URI:       post.URI,
+			CID:       post.CID,
+			Text:      post.Text,
+			Author:    post.Author,
+			Likes:     post.Likes,
+			Reposts:   post.Reposts,
+			Replies:   post.Replies,
+			CreatedAt: post.CreatedAt,
+			Langs:     post.Langs,
+		}
+	}
+	analyzedPosts, err := p.analyzer.AnalyzePosts(analyzerPosts)
+	if err != nil {
+		return fmt.Errorf("failed to analyze posts: %w", err)
+	}
+
+	overallSentiment, netSentimentPercentage := calculateOverallSentiment(analyzedPosts, p.options.Thresholds, p.previousCategory(r.WindowEnd))
+	// Summaries and history take the average compound score alongside the percentage
+	averageCompoundScore := netSentimentPercentage / 100.0
+	topPosts := rankTopPosts(posts, analyzedPosts, p.options.TopPostsCount)
+
+	var notes []string
+	if len(p.options.Queries) > 0 {
+		notes = append(notes, formatter.QueryNote(p.options.Queries))
+	}
+	trend := analyzer.CalculateSentimentTrend(analyzedPosts, r.CutoffTime, r.WindowEnd, trendSegmentCount)
+	if trend.HasComparableThirds(minTrendThirdPosts) {
+		notes = append(notes, formatter.TrendNote(trend.FirstThirdPercent, trend.LastThirdPercent))
+	}
+
+	dataPoint := state.SentimentDataPoint{
+		RunID:                r.ID,
+		Timestamp:            r.WindowEnd,
+		AverageCompoundScore: averageCompoundScore,
+		NetSentimentPercent:  netSentimentPercentage,
+		SentimentCategory:    overallSentiment,
+		TotalPosts:           len(posts),
+	}
+	if volatility, _, ok := insight.Volatility(p.history, netSentimentPercentage, r.WindowEnd); ok {
+		dataPoint.Volatility = &volatility
+		notes = append(notes, formatter.StabilityNote(insight.Stability(volatility), volatility))
+	}
+
+	if !p.options.DryRun.Posts() {
+		formatterPosts := make([]formatter.Post, len(topPosts))
+		for i, post := range topPosts {
+			formatterPosts[i] = formatter.Post{
+				URI:             post.URI,
+				CID:             post.CID,
+				Author:          post.Author,
+				Likes:           post.Likes,
+				Reposts:         post.Reposts,
+				Replies:         post.Replies,
+				Sentiment:       post.Sentiment,
+				EngagementScore: post.EngagementScore,
+			}
+		}
+		content := formatter.FormatPostContent(formatterPosts, overallSentiment, r.IntervalMinutes, len(posts), averageCompoundScore, notes...)
+		log.Printf("📝 PIPELINE: Dry run (%s), not posting:\n%s", p.options.DryRun, content)
+	} else {
+		if _, _, err := poster.PostTrendingSummary(topPosts, overallSentiment, r.IntervalMinutes, len(posts), averageCompoundScore, notes...); err != nil {
+			return fmt.Errorf("failed to post summary: %w", err)
+		}
+		log.Printf("✅ PIPELINE: Posted summary of %d posts, %.1f%% net sentiment", len(posts), netSentimentPercentage)
+	}
+
+	return p.recordHistory(dataPoint)
+}
+
+// previousCategory is the category of the latest window in history before windowEnd, for
+// the thresholds' hysteresis, or "" when there is none recent enough
+func (p *Pipeline) previousCategory(windowEnd time.Time) string {
+	for i := len(p.history) - 1; i >= 0; i-- {
+		point := p.history[i]
+		if point.Timestamp.Before(windowEnd) {
+			if windowEnd.Sub(point.Timestamp) > previousCategoryLookback {
+				return ""
+			}
+			return point.SentimentCategory
+		}
+	}
+	return ""
+}
+
+// calculateOverallSentiment averages the posts' compound scores, clamped to VADER's range, into a
+// category and a net sentiment percentage
+func calculateOverallSentiment(posts []analyzer.AnalyzedPost, thresholds aggregation.Thresholds, previous string) (string, float64) {
+	if len(posts) == 0 {
+		return "neutral", 0.0
+	}
+
+	var total float64
+	for _, post := range posts {
+		total += max(-1.0, min(1.0, post.SentimentScore))
+	}
+	average := total / float64(len(posts))
+
+	return thresholds.Categorize(average, previous), average * 100.0
+}
+
+// rankTopPosts returns the n posts with the highest engagement score, with their sentiment
+func rankTopPosts(posts []client.Post, analyzedPosts []analyzer.AnalyzedPost, n int) []client.Post {
+	ranked := make([]client.Post, len(posts))
+	for i, post := range posts {
+		ranked[i] = post
+		ranked[i].Sentiment = analyzedPosts[i].Sentiment
+		ranked[i].EngagementScore = analyzedPosts[i].EngagementScore
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].EngagementScore > ranked[j].EngagementScore
+	})
+	return ranked[:min(n, len(ranked))]
+}
+
+// recordHistory adds a run's sentiment to the history, dropping points past
+// historyRetention, and saves it when a history file is configured and the dry run level
+// allows writes
+func (p *Pipeline) recordHistory(dataPoint state.SentimentDataPoint) error {
+	since := dataPoint.Timestamp.Add(-historyRetention)
+	kept := p.history[:0]
+	for _, point := range p.history {
+		if !point.Timestamp.Before(since) {
+			kept = append(kept, point)
+		}
+	}
+	p.history = append(kept, dataPoint)
+
+	if p.options.HistoryPath == "" || !p.options.DryRun.Writes() {
+		return nil
+	}
+	data, err := json.Marshal(p.history)
+	if err != nil {
+		return fmt.Errorf("failed to encode sentiment history: %w", err)
+	}
+	if err := os.WriteFile(p.options.HistoryPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to save sentiment history: %w", err)
+	}
+	return nil
+}
+
+// loadHistory reads the saved sentiment history; a missing file starts an empty one
+func (p *Pipeline) loadHistory() error {
+	if p.options.HistoryPath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(p.options.HistoryPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read sentiment history: %w", err)
+	}
+	if err := json.Unmarshal(data, &p.history); err != nil {
+		return fmt.Errorf("invalid sentiment history in %s: %w", p.options.HistoryPath, err)
+	}
+	return nil
+}
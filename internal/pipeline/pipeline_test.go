@@ -0,0 +1,187 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/internal/client/clienttest"
+	"github.com/christophergentle/hourstats-bsky/internal/dryrun"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func testPipeline(t *testing.T, mock *clienttest.MockClient, now time.Time, historyPath string) *Pipeline {
+	t.Helper()
+	p, err := New(Options{IntervalMinutes: 30, TopPostsCount: 2, MinPostCount: 3, HistoryPath: historyPath})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	p.newClient = mock.Factory()
+	p.now = func() time.Time { return now }
+	return p
+}
+
+func windowPosts(now time.Time, likes ...int) []client.Post {
+	posts := make([]client.Post, len(likes))
+	for i, n := range likes {
+		posts[i] = client.Post{
+			URI:       fmt.Sprintf("at://did:plc:test/app.bsky.feed.post/%d", i),
+			Text:      "What a lovely day",
+			Author:    "test.bsky.social",
+			Likes:     n,
+			CreatedAt: now.Add(-time.Duration(i+1) * time.Minute).Format(time.RFC3339),
+		}
+	}
+	return posts
+}
+
+func TestRunOncePostsSummary(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	stale := client.Post{URI: "at://did:plc:test/app.bsky.feed.post/old", Likes: 1000, CreatedAt: now.Add(-2 * time.Hour).Format(time.RFC3339)}
+	mock := clienttest.NewMockClient(
+		clienttest.MockBatch{Posts: windowPosts(now, 5, 50), Cursor: "100", HasMore: true},
+		clienttest.MockBatch{Posts: append(windowPosts(now, 5, 50, 20), stale), Cursor: "200", HasMore: true},
+	)
+	p := testPipeline(t, mock, now, "")
+
+	if err := p.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+
+	posts := mock.Posts()
+	if len(posts) != 1 {
+		t.Fatalf("Expected one summary, got %d posts", len(posts))
+	}
+	if want := fmt.Sprintf("%.1f%% sentiment", p.history[0].NetSentimentPercent); !strings.Contains(posts[0].Text, want) {
+		t.Errorf("Expected the summary to show %q, got %q", want, posts[0].Text)
+	}
+	summary := posts[0].Summary
+	if len(summary) != 2 || summary[0].Likes != 50 || summary[1].Likes != 20 {
+		t.Errorf("Expected the top two posts by engagement, got %+v", summary)
+	}
+	if len(p.history) != 1 || p.history[0].TotalPosts != 3 {
+		t.Errorf("Expected the run's 3 deduplicated posts in history, got %+v", p.history)
+	}
+}
+
+func TestRunOnceSkipsQuietWindow(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	mock := clienttest.NewMockClient(clienttest.MockBatch{Posts: windowPosts(now, 5, 50)})
+	p := testPipeline(t, mock, now, "")
+
+	if err := p.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if len(mock.Posts()) != 0 || len(p.history) != 0 {
+		t.Errorf("Expected no summary below the minimum post count, got %d posts", len(mock.Posts()))
+	}
+}
+
+func TestHistorySurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 6; i++ {
+		at := now.Add(time.Duration(i) * 30 * time.Minute)
+		p := testPipeline(t, clienttest.NewMockClient(clienttest.MockBatch{Posts: windowPosts(at, 1, 2, 3)}), at, path)
+		if err := p.RunOnce(context.Background()); err != nil {
+			t.Fatalf("RunOnce() error = %v", err)
+		}
+		if len(p.history) != i+1 {
+			t.Fatalf("Run %d: expected %d history points, got %d", i+1, i+1, len(p.history))
+		}
+		if volatility := p.history[i].Volatility; (volatility != nil) != (i == 5) {
+			t.Errorf("Run %d: expected volatility only once 6 runs are stored, got %v", i+1, volatility)
+		}
+	}
+}
+
+func TestDryRunLevels(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		level        dryrun.Level
+		posts        int
+		savesHistory bool
+	}{
+		{dryrun.Off, 1, true},
+		{dryrun.NoPost, 0, true},
+		{dryrun.NoWrite, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "history.json")
+			mock := clienttest.NewMockClient(clienttest.MockBatch{Posts: windowPosts(now, 1, 2, 3)})
+			p := testPipeline(t, mock, now, path)
+			p.options.DryRun = tt.level
+
+			if err := p.RunOnce(context.Background()); err != nil {
+				t.Fatalf("RunOnce() error = %v", err)
+			}
+			if len(mock.Posts()) != tt.posts {
+				t.Errorf("Expected %d posts, got %d", tt.posts, len(mock.Posts()))
+			}
+			if _, err := os.Stat(path); (err == nil) != tt.savesHistory {
+				t.Errorf("Expected the history file saved to be %t, got %v", tt.savesHistory, err)
+			}
+		})
+	}
+}
+
+func TestShadowDryRunPostsFromShadowAccount(t *testing.T) {
+	if _, err := New(Options{IntervalMinutes: 30, DryRun: dryrun.Shadow}); err == nil {
+		t.Error("Expected a shadow dry run without a shadow account to be rejected")
+	}
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	account := clienttest.NewMockClient(clienttest.MockBatch{Posts: windowPosts(now, 1, 2, 3)})
+	shadow := clienttest.NewMockClient()
+	p, err := New(Options{IntervalMinutes: 30, MinPostCount: 3, DryRun: dryrun.Shadow, ShadowHandle: "shadow.bsky.social", ShadowPassword: "password"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	p.newClient = func(handle, password string) client.Client {
+		if handle == "shadow.bsky.social" {
+			return shadow
+		}
+		return account
+	}
+	p.now = func() time.Time { return now }
+
+	if err := p.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if len(account.Posts()) != 0 || len(shadow.Posts()) != 1 {
+		t.Errorf("Expected the summary posted only from the shadow account, got %d and %d posts", len(account.Posts()), len(shadow.Posts()))
+	}
+}
+
+func TestPreviousCategory(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	p := testPipeline(t, clienttest.NewMockClient(), now, "")
+	if got := p.previousCategory(now); got != "" {
+		t.Errorf("Expected no previous category without history, got %q", got)
+	}
+
+	p.history = []state.SentimentDataPoint{
+		{Timestamp: now.Add(-time.Hour), SentimentCategory: "negative"},
+		{Timestamp: now.Add(-30 * time.Minute), SentimentCategory: "positive"},
+	}
+	if got := p.previousCategory(now); got != "positive" {
+		t.Errorf("Expected the latest window's category, got %q", got)
+	}
+	if got := p.previousCategory(now.Add(-45 * time.Minute)); got != "negative" {
+		t.Errorf("Expected the latest category before the window, got %q", got)
+	}
+	if got := p.previousCategory(now.Add(7 * time.Hour)); got != "" {
+		t.Errorf("Expected no previous category beyond the lookback, got %q", got)
+	}
+
+	if _, err := New(Options{IntervalMinutes: 30, Thresholds: aggregation.Thresholds{Threshold: 0.2, Hysteresis: 0.2}}); err == nil {
+		t.Error("Expected New() to reject hysteresis as wide as the threshold")
+	}
+}
@@ -0,0 +1,45 @@
+package preview
+
+import (
+	"context"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Bluesky CDN avatars are served as JPEG
+	_ "image/png"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxAvatarBytes caps avatar downloads; CDN avatars are typically well under this
+const maxAvatarBytes = 2 << 20
+
+// avatarTimeout bounds a single avatar download so a slow CDN can't hold up the summary post
+const avatarTimeout = 5 * time.Second
+
+// FetchAvatar downloads and decodes an avatar image
+func FetchAvatar(ctx context.Context, httpClient *http.Client, url string) (image.Image, error) {
+	ctx, cancel := context.WithTimeout(ctx, avatarTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avatar request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch avatar: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch avatar: HTTP %d", resp.StatusCode)
+	}
+
+	img, _, err := image.Decode(io.LimitReader(resp.Body, maxAvatarBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode avatar: %w", err)
+	}
+	return img, nil
+}
@@ -0,0 +1,271 @@
+// Package preview renders the images that can be attached to the summary post: a
+// "top posts card" of the run's top posts, and a summary card of its net sentiment
+package preview
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+	"unicode"
+
+	"github.com/fogleman/gg"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// Card is one top post as shown in the preview
+type Card struct {
+	Handle    string
+	Text      string
+	Likes     int
+	Reposts   int
+	Replies   int
+	Sentiment string      // "positive", "negative", or "neutral"
+	Avatar    image.Image // Optional; initials are drawn when nil
+}
+
+// Config holds layout and colour settings for the preview image
+type Config struct {
+	Width         int
+	Padding       int
+	TitleHeight   int
+	RowHeight     int
+	AvatarSize    int
+	TextLines     int
+	Background    color.RGBA
+	RowBackground color.RGBA
+	TextColor     color.RGBA
+	MutedColor    color.RGBA
+	PositiveColor color.RGBA
+	NegativeColor color.RGBA
+	NeutralColor  color.RGBA
+}
+
+// DefaultConfig returns a preview configuration matching the sparkline palette
+func DefaultConfig() *Config {
+	return &Config{
+		Width:         1200,
+		Padding:       40,
+		TitleHeight:   90,
+		RowHeight:     150,
+		AvatarSize:    80,
+		TextLines:     2,
+		Background:    color.RGBA{248, 249, 250, 255}, // Light gray
+		RowBackground: color.RGBA{255, 255, 255, 255}, // White
+		TextColor:     color.RGBA{33, 37, 41, 255},    // Dark gray
+		MutedColor:    color.RGBA{108, 117, 125, 255}, // Gray
+		PositiveColor: color.RGBA{40, 167, 69, 255},   // Green
+		NegativeColor: color.RGBA{220, 53, 69, 255},   // Red
+		NeutralColor:  color.RGBA{108, 117, 125, 255}, // Gray
+	}
+}
+
+// Renderer draws preview images
+type Renderer struct {
+	config  *Config
+	regular *truetype.Font
+	bold    *truetype.Font
+}
+
+// NewRenderer creates a renderer using the embedded Go fonts
+func NewRenderer(config *Config) (*Renderer, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	regular, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse regular font: %w", err)
+	}
+	bold, err := truetype.Parse(gobold.TTF)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse bold font: %w", err)
+	}
+
+	return &Renderer{config: config, regular: regular, bold: bold}, nil
+}
+
+// Render draws the cards under title and returns the image as PNG
+func (r *Renderer) Render(title string, cards []Card) ([]byte, error) {
+	if len(cards) == 0 {
+		return nil, fmt.Errorf("no cards to render")
+	}
+
+	cfg := r.config
+	height := cfg.TitleHeight + len(cards)*cfg.RowHeight + cfg.Padding
+	dc := gg.NewContext(cfg.Width, height)
+
+	dc.SetColor(cfg.Background)
+	dc.Clear()
+
+	dc.SetFontFace(r.face(r.bold, 34))
+	dc.SetColor(cfg.TextColor)
+	dc.DrawStringAnchored(title, float64(cfg.Padding), float64(cfg.TitleHeight)/2+float64(cfg.Padding)/4, 0, 0.5)
+
+	for i, card := range cards {
+		top := float64(cfg.TitleHeight + i*cfg.RowHeight)
+		r.drawCard(dc, i+1, card, top)
+	}
+
+	var buf bytes.Buffer
+	if err := dc.EncodePNG(&buf); err != nil {
+		return nil, fmt.Errorf("failed to encode preview: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// drawCard draws one row: rank, avatar, handle with sentiment marker, wrapped text, and counts
+func (r *Renderer) drawCard(dc *gg.Context, rank int, card Card, top float64) {
+	cfg := r.config
+	pad := float64(cfg.Padding)
+	rowHeight := float64(cfg.RowHeight) - 12
+	avatarSize := float64(cfg.AvatarSize)
+
+	dc.SetColor(cfg.RowBackground)
+	dc.DrawRoundedRectangle(pad, top, float64(cfg.Width)-2*pad, rowHeight, 12)
+	dc.Fill()
+
+	// Rank
+	dc.SetFontFace(r.face(r.bold, 28))
+	dc.SetColor(cfg.MutedColor)
+	dc.DrawStringAnchored(fmt.Sprintf("%d", rank), pad+28, top+rowHeight/2, 0.5, 0.5)
+
+	// Avatar, clipped to a circle
+	avatarX := pad + 56
+	avatarY := top + (rowHeight-avatarSize)/2
+	r.drawAvatar(dc, card, avatarX, avatarY, avatarSize)
+
+	textX := avatarX + avatarSize + 24
+	textWidth := float64(cfg.Width) - pad - 24 - textX
+
+	// Handle and sentiment marker
+	dc.SetFontFace(r.face(r.bold, 24))
+	dc.SetColor(cfg.TextColor)
+	handle := "@" + card.Handle
+	dc.DrawString(handle, textX, top+36)
+	handleWidth, _ := dc.MeasureString(handle)
+	dc.SetColor(r.sentimentColor(card.Sentiment))
+	dc.DrawCircle(textX+handleWidth+16, top+28, 7)
+	dc.Fill()
+
+	// Post text, wrapped and truncated
+	dc.SetFontFace(r.face(r.regular, 20))
+	dc.SetColor(cfg.TextColor)
+	for i, line := range wrapLines(dc, card.Text, textWidth, cfg.TextLines) {
+		dc.DrawString(line, textX, top+66+float64(i)*26)
+	}
+
+	// Engagement counts
+	dc.SetFontFace(r.face(r.regular, 18))
+	dc.SetColor(cfg.MutedColor)
+	dc.DrawString(fmt.Sprintf("%d likes · %d reposts · %d replies", card.Likes, card.Reposts, card.Replies), textX, top+rowHeight-16)
+}
+
+func (r *Renderer) drawAvatar(dc *gg.Context, card Card, x, y, size float64) {
+	dc.Push()
+	dc.DrawCircle(x+size/2, y+size/2, size/2)
+	dc.Clip()
+	if card.Avatar != nil {
+		scaled := image.NewRGBA(image.Rect(0, 0, int(size), int(size)))
+		draw.CatmullRom.Scale(scaled, scaled.Bounds(), card.Avatar, card.Avatar.Bounds(), draw.Src, nil)
+		dc.DrawImage(scaled, int(x), int(y))
+	} else {
+		dc.SetColor(r.sentimentColor(card.Sentiment))
+		dc.DrawRectangle(x, y, size, size)
+		dc.Fill()
+		dc.SetFontFace(r.face(r.bold, size*0.45))
+		dc.SetColor(color.White)
+		dc.DrawStringAnchored(initial(card.Handle), x+size/2, y+size/2, 0.5, 0.35)
+	}
+	dc.ResetClip()
+	dc.Pop()
+}
+
+func (r *Renderer) sentimentColor(sentiment string) color.RGBA {
+	switch sentiment {
+	case "positive":
+		return r.config.PositiveColor
+	case "negative":
+		return r.config.NegativeColor
+	default:
+		return r.config.NeutralColor
+	}
+}
+
+func (r *Renderer) face(f *truetype.Font, size float64) font.Face {
+	return truetype.NewFace(f, &truetype.Options{Size: size, Hinting: font.HintingFull})
+}
+
+// AltText describes the preview for screen readers
+func AltText(title string, cards []Card) string {
+	var b strings.Builder
+	b.WriteString(title)
+	for i, card := range cards {
+		fmt.Fprintf(&b, "\n%d. @%s (%s): %s", i+1, card.Handle, card.Sentiment, strings.Join(strings.Fields(card.Text), " "))
+	}
+	return b.String()
+}
+
+// wrapLines word-wraps text to width, keeping at most maxLines and marking cut text with an ellipsis
+func wrapLines(dc *gg.Context, text string, width float64, maxLines int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 || maxLines <= 0 {
+		return nil
+	}
+
+	var lines []string
+	current := ""
+	for _, word := range words {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if w, _ := dc.MeasureString(candidate); w <= width || current == "" {
+			current = candidate
+			continue
+		}
+		lines = append(lines, current)
+		current = word
+	}
+	lines = append(lines, current)
+
+	truncated := len(lines) > maxLines
+	if truncated {
+		lines = lines[:maxLines]
+	}
+	for i, line := range lines {
+		// Long unbroken words (e.g. URLs) can overflow on their own line
+		if w, _ := dc.MeasureString(line); w > width || (truncated && i == len(lines)-1) {
+			lines[i] = ellipsize(dc, line, width)
+		}
+	}
+	return lines
+}
+
+// ellipsize trims line until it fits width with a trailing ellipsis
+func ellipsize(dc *gg.Context, line string, width float64) string {
+	runes := []rune(line)
+	for len(runes) > 0 {
+		candidate := strings.TrimRightFunc(string(runes), unicode.IsSpace) + "…"
+		if w, _ := dc.MeasureString(candidate); w <= width {
+			return candidate
+		}
+		runes = runes[:len(runes)-1]
+	}
+	return "…"
+}
+
+// initial returns the first letter of a handle for avatar placeholders
+func initial(handle string) string {
+	for _, r := range handle {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return strings.ToUpper(string(r))
+		}
+	}
+	return "?"
+}
@@ -0,0 +1,121 @@
+package preview
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fogleman/gg"
+)
+
+func testCards() []Card {
+	avatar := image.NewRGBA(image.Rect(0, 0, 40, 40))
+	for x := 0; x < 40; x++ {
+		for y := 0; y < 40; y++ {
+			avatar.Set(x, y, color.RGBA{0, 133, 255, 255})
+		}
+	}
+	return []Card{
+		{Handle: "alice.bsky.social", Text: "What a wonderful morning, I love this community!", Likes: 120, Reposts: 30, Replies: 12, Sentiment: "positive", Avatar: avatar},
+		{Handle: "bob.bsky.social", Text: strings.Repeat("Traffic is terrible today and I hate waiting. ", 10), Likes: 80, Sentiment: "negative"},
+	}
+}
+
+func TestRender(t *testing.T) {
+	renderer, err := NewRenderer(nil)
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+
+	data, err := renderer.Render("Top posts in the last hour", testCards())
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Render() did not produce a PNG: %v", err)
+	}
+	cfg := DefaultConfig()
+	wantHeight := cfg.TitleHeight + 2*cfg.RowHeight + cfg.Padding
+	if img.Bounds().Dx() != cfg.Width || img.Bounds().Dy() != wantHeight {
+		t.Errorf("image size = %v, want %dx%d", img.Bounds().Size(), cfg.Width, wantHeight)
+	}
+}
+
+func TestRenderRequiresCards(t *testing.T) {
+	renderer, err := NewRenderer(nil)
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+	if _, err := renderer.Render("Top posts", nil); err == nil {
+		t.Error("Render() with no cards succeeded, want error")
+	}
+}
+
+func TestWrapLinesTruncates(t *testing.T) {
+	renderer, err := NewRenderer(nil)
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+	dc := gg.NewContext(10, 10)
+	dc.SetFontFace(renderer.face(renderer.regular, 20))
+
+	lines := wrapLines(dc, strings.Repeat("word ", 200), 400, 2)
+	if len(lines) != 2 {
+		t.Fatalf("wrapLines() = %d lines, want 2", len(lines))
+	}
+	if !strings.HasSuffix(lines[1], "…") {
+		t.Errorf("last line %q should end with an ellipsis", lines[1])
+	}
+	for _, line := range lines {
+		if w, _ := dc.MeasureString(line); w > 400 {
+			t.Errorf("line %q is %.0fpx wide, want <= 400", line, w)
+		}
+	}
+
+	if lines := wrapLines(dc, "short text", 400, 2); len(lines) != 1 || lines[0] != "short text" {
+		t.Errorf("wrapLines(short) = %q, want a single unchanged line", lines)
+	}
+}
+
+func TestAltText(t *testing.T) {
+	alt := AltText("Top posts", testCards()[:1])
+	want := "Top posts\n1. @alice.bsky.social (positive): What a wonderful morning, I love this community!"
+	if alt != want {
+		t.Errorf("AltText() = %q, want %q", alt, want)
+	}
+}
+
+func TestFetchAvatar(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 8, 8))); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	img, err := FetchAvatar(context.Background(), server.Client(), server.URL+"/avatar")
+	if err != nil {
+		t.Fatalf("FetchAvatar() error = %v", err)
+	}
+	if img.Bounds().Dx() != 8 {
+		t.Errorf("avatar width = %d, want 8", img.Bounds().Dx())
+	}
+
+	if _, err := FetchAvatar(context.Background(), server.Client(), server.URL+"/missing"); err == nil {
+		t.Error("FetchAvatar() on 404 succeeded, want error")
+	}
+}
@@ -0,0 +1,214 @@
+package preview
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/fogleman/gg"
+)
+
+// Summary card layout; the card uses the 1.91:1 ratio Bluesky shows uncropped
+const (
+	summaryHeight    = 630
+	sparklineHeight  = 150
+	sparklineSamples = 2
+)
+
+// SummaryCard is the data drawn on a run's summary card
+type SummaryCard struct {
+	Title        string
+	NetSentiment float64  // Net sentiment percentage, e.g. 12.3 for +12.3%
+	Previous     *float64 // Net sentiment of the last window; nil hides the arrow
+	TotalPosts   int
+	Mood         string    // Mood word shown under the number, e.g. "upbeat"
+	History      []float64 // Recent net sentiment, oldest first, for the mini-sparkline
+}
+
+// Delta returns the change in net sentiment since the last window, and false when unknown
+func (c SummaryCard) Delta() (float64, bool) {
+	if c.Previous == nil {
+		return 0, false
+	}
+	return c.NetSentiment - *c.Previous, true
+}
+
+// RenderSummary draws a branded summary card and returns it as PNG
+func (r *Renderer) RenderSummary(card SummaryCard) ([]byte, error) {
+	cfg := r.config
+	pad := float64(cfg.Padding)
+	width := float64(cfg.Width)
+	dc := gg.NewContext(cfg.Width, summaryHeight)
+
+	dc.SetColor(cfg.Background)
+	dc.Clear()
+
+	// Brand bar
+	accent := r.netSentimentColor(card.NetSentiment)
+	dc.SetColor(accent)
+	dc.DrawRectangle(0, 0, width, 12)
+	dc.Fill()
+
+	dc.SetFontFace(r.face(r.bold, 28))
+	dc.SetColor(cfg.MutedColor)
+	dc.DrawString("HourStats", pad, pad+40)
+
+	dc.SetFontFace(r.face(r.bold, 34))
+	dc.SetColor(cfg.TextColor)
+	dc.DrawString(card.Title, pad, pad+96)
+
+	// Big sentiment number
+	number := signedPercent(card.NetSentiment)
+	dc.SetFontFace(r.face(r.bold, 150))
+	dc.SetColor(accent)
+	dc.DrawString(number, pad, pad+270)
+	numberWidth, _ := dc.MeasureString(number)
+
+	// Arrow and change since the last window
+	if delta, ok := card.Delta(); ok {
+		x := pad + numberWidth + 40
+		r.drawArrow(dc, delta, x, pad+200)
+		dc.SetFontFace(r.face(r.bold, 34))
+		dc.SetColor(r.netSentimentColor(delta))
+		dc.DrawString(signedPercent(delta), x+56, pad+214)
+		dc.SetFontFace(r.face(r.regular, 22))
+		dc.SetColor(cfg.MutedColor)
+		dc.DrawString("vs last window", x+56, pad+250)
+	}
+
+	// Mood and post count
+	dc.SetFontFace(r.face(r.regular, 30))
+	dc.SetColor(cfg.TextColor)
+	caption := fmt.Sprintf("%s posts analyzed", formatCount(card.TotalPosts))
+	if card.Mood != "" {
+		caption = fmt.Sprintf("Mood: %s · %s", card.Mood, caption)
+	}
+	dc.DrawString(caption, pad, pad+330)
+
+	r.drawSparkline(dc, card.History, pad, summaryHeight-pad-sparklineHeight, width-2*pad, sparklineHeight)
+
+	var buf bytes.Buffer
+	if err := dc.EncodePNG(&buf); err != nil {
+		return nil, fmt.Errorf("failed to encode summary card: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// drawArrow draws an up, down, or flat marker for delta, centred vertically on y
+func (r *Renderer) drawArrow(dc *gg.Context, delta, x, y float64) {
+	const size = 40
+	dc.SetColor(r.netSentimentColor(delta))
+	// Changes that round to 0.0% are shown as flat
+	switch {
+	case delta >= 0.05:
+		dc.MoveTo(x, y+size/2)
+		dc.LineTo(x+size, y+size/2)
+		dc.LineTo(x+size/2, y-size/2)
+	case delta <= -0.05:
+		dc.MoveTo(x, y-size/2)
+		dc.LineTo(x+size, y-size/2)
+		dc.LineTo(x+size/2, y+size/2)
+	default:
+		dc.DrawRectangle(x, y-4, size, 8)
+	}
+	dc.ClosePath()
+	dc.Fill()
+}
+
+// drawSparkline draws values as a line in the given box with a zero baseline and a dot on
+// the latest value; fewer than two values draw nothing
+func (r *Renderer) drawSparkline(dc *gg.Context, values []float64, x, y, width, height float64) {
+	if len(values) < sparklineSamples {
+		return
+	}
+
+	low, high := 0.0, 0.0
+	for _, v := range values {
+		low, high = math.Min(low, v), math.Max(high, v)
+	}
+	if high-low < 1 {
+		high, low = high+0.5, low-0.5
+	}
+	pointAt := func(i int) (float64, float64) {
+		px := x + width*float64(i)/float64(len(values)-1)
+		py := y + height*(high-values[i])/(high-low)
+		return px, py
+	}
+
+	// Zero baseline
+	zero := y + height*high/(high-low)
+	dc.SetColor(r.config.MutedColor)
+	dc.SetLineWidth(1)
+	dc.SetDash(6, 6)
+	dc.DrawLine(x, zero, x+width, zero)
+	dc.Stroke()
+	dc.SetDash()
+
+	dc.SetColor(r.config.TextColor)
+	dc.SetLineWidth(4)
+	for i := range values {
+		px, py := pointAt(i)
+		if i == 0 {
+			dc.MoveTo(px, py)
+		} else {
+			dc.LineTo(px, py)
+		}
+	}
+	dc.Stroke()
+
+	lastX, lastY := pointAt(len(values) - 1)
+	dc.SetColor(r.netSentimentColor(values[len(values)-1]))
+	dc.DrawCircle(lastX, lastY, 9)
+	dc.Fill()
+}
+
+// netSentimentColor colours a net sentiment value or change by its sign
+func (r *Renderer) netSentimentColor(value float64) color.RGBA {
+	switch {
+	case value > 0:
+		return r.config.PositiveColor
+	case value < 0:
+		return r.config.NegativeColor
+	default:
+		return r.config.NeutralColor
+	}
+}
+
+// SummaryAltText describes the summary card for screen readers
+func SummaryAltText(card SummaryCard) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: net sentiment %s", card.Title, signedPercent(card.NetSentiment))
+	if card.Mood != "" {
+		fmt.Fprintf(&b, " (%s)", card.Mood)
+	}
+	if delta, ok := card.Delta(); ok {
+		fmt.Fprintf(&b, ", %s vs last window", signedPercent(delta))
+	}
+	fmt.Fprintf(&b, ", %s posts analyzed.", formatCount(card.TotalPosts))
+	if len(card.History) >= sparklineSamples {
+		fmt.Fprintf(&b, " Sparkline of the last %d windows.", len(card.History))
+	}
+	return b.String()
+}
+
+// signedPercent formats a percentage with its sign, e.g. "+12.3%", without a "-0.0%"
+func signedPercent(value float64) string {
+	if math.Abs(value) < 0.05 {
+		value = 0
+	}
+	return fmt.Sprintf("%+.1f%%", value)
+}
+
+// formatCount adds thousands separators to n
+func formatCount(n int) string {
+	if n < 0 {
+		return "-" + formatCount(-n)
+	}
+	s := fmt.Sprintf("%d", n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
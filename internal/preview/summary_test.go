@@ -0,0 +1,71 @@
+package preview
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+)
+
+func TestRenderSummary(t *testing.T) {
+	renderer, err := NewRenderer(nil)
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+
+	previous := 4.2
+	cards := map[string]SummaryCard{
+		"full": {
+			Title:        "Bluesky mood in the last hour",
+			NetSentiment: 12.3,
+			Previous:     &previous,
+			TotalPosts:   48213,
+			Mood:         "upbeat",
+			History:      []float64{-3, 1.5, 4.2, 8, 12.3},
+		},
+		"no history": {Title: "Bluesky mood", NetSentiment: -7.5, TotalPosts: 12},
+	}
+	for name, card := range cards {
+		data, err := renderer.RenderSummary(card)
+		if err != nil {
+			t.Fatalf("%s: RenderSummary() error = %v", name, err)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: RenderSummary() did not produce a PNG: %v", name, err)
+		}
+		if img.Bounds().Dx() != DefaultConfig().Width || img.Bounds().Dy() != summaryHeight {
+			t.Errorf("%s: image size = %v, want %dx%d", name, img.Bounds().Size(), DefaultConfig().Width, summaryHeight)
+		}
+	}
+}
+
+func TestSummaryAltText(t *testing.T) {
+	previous := 14.0
+	card := SummaryCard{
+		Title:        "Bluesky mood in the last hour",
+		NetSentiment: 12.3,
+		Previous:     &previous,
+		TotalPosts:   48213,
+		Mood:         "upbeat",
+		History:      []float64{14, 12.3},
+	}
+	want := "Bluesky mood in the last hour: net sentiment +12.3% (upbeat), -1.7% vs last window, 48,213 posts analyzed. Sparkline of the last 2 windows."
+	if alt := SummaryAltText(card); alt != want {
+		t.Errorf("SummaryAltText() = %q, want %q", alt, want)
+	}
+
+	card.Previous, card.History, card.Mood = nil, nil, ""
+	want = "Bluesky mood in the last hour: net sentiment +12.3%, 48,213 posts analyzed."
+	if alt := SummaryAltText(card); alt != want {
+		t.Errorf("SummaryAltText() without history = %q, want %q", alt, want)
+	}
+}
+
+func TestSignedPercent(t *testing.T) {
+	tests := map[float64]string{12.34: "+12.3%", -7.5: "-7.5%", 0: "+0.0%", -0.01: "+0.0%"}
+	for value, want := range tests {
+		if got := signedPercent(value); got != want {
+			t.Errorf("signedPercent(%v) = %q, want %q", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+// Package profanity masks profanity and slurs in post text the bot republishes, such as
+// the top posts card and the archive site, so the account doesn't repeat them verbatim.
+// Matching is by whole word and crude: it catches spelled-out terms, not misspellings.
+package profanity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// ParameterName holds the optional JSON settings; when it is absent profanity is masked
+// with the built-in word list
+//
+//	{"mode": "hide", "words": ["frack"], "allow": ["damn"]}
+const ParameterName = "/hourstats/settings/profanity_filter"
+
+// Mode is what the filter does with text containing profanity
+type Mode string
+
+const (
+	// Mask keeps the first letter of each matched word and stars the rest, e.g. "s***"
+	Mask Mode = "mask"
+	// Hide replaces the whole text with HiddenText
+	Hide Mode = "hide"
+	// Off leaves text as it is
+	Off Mode = "off"
+)
+
+// HiddenText stands in for text the Hide mode withholds
+const HiddenText = "[post text hidden]"
+
+// stems match any word starting with them, e.g. "fucking"; they are chosen not to start
+// ordinary words
+var stems = []string{
+	"fuck", "shit", "cunt", "motherfuck", "bullshit", "nigger", "nigga", "faggot",
+}
+
+// words match whole words, their plurals and possessives; short terms that start ordinary
+// words, such as "cock" in "cockpit", are kept here rather than in stems
+var words = []string{
+	"ass", "asshole", "bastard", "bitch", "bitches", "cock", "dickhead", "dyke",
+	"fag", "kike", "piss", "pissed", "prick", "retard", "retarded", "slut", "spic", "twat",
+	"tranny", "whore", "wanker", "chink", "coon", "gook", "wetback",
+}
+
+// Settings adjust the filter
+type Settings struct {
+	Mode Mode `json:"mode"`
+	// Words are masked in addition to the built-in list, as whole words and their plurals
+	Words []string `json:"words,omitempty"`
+	// Allow lists built-in words and stems to leave unmasked
+	Allow []string `json:"allow,omitempty"`
+}
+
+// DefaultSettings mask the built-in word list
+var DefaultSettings = Settings{Mode: Mask}
+
+// ParameterGetter is the subset of the SSM client Load needs
+type ParameterGetter interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Load reads the settings from SSM. A missing or empty parameter returns DefaultSettings.
+func Load(ctx context.Context, ssmClient ParameterGetter) (Settings, error) {
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ParameterName),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return DefaultSettings, nil
+		}
+		return DefaultSettings, fmt.Errorf("failed to get %s: %w", ParameterName, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return DefaultSettings, nil
+	}
+	return Parse(*result.Parameter.Value)
+}
+
+// Parse decodes and validates JSON settings over DefaultSettings
+func Parse(value string) (Settings, error) {
+	settings := DefaultSettings
+	if strings.TrimSpace(value) == "" {
+		return settings, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&settings); err != nil {
+		return DefaultSettings, fmt.Errorf("invalid profanity filter JSON: %w", err)
+	}
+	if err := settings.Validate(); err != nil {
+		return DefaultSettings, fmt.Errorf("invalid profanity filter settings: %w", err)
+	}
+	return settings, nil
+}
+
+// Validate checks the mode and that every extra word is a single word
+func (s Settings) Validate() error {
+	if _, err := ParseMode(string(s.Mode)); err != nil {
+		return err
+	}
+	for _, word := range s.Words {
+		if normalized := strings.ToLower(strings.TrimSpace(word)); normalized == "" || strings.IndexFunc(normalized, isSeparator) >= 0 {
+			return fmt.Errorf("invalid word %q: want a single word", word)
+		}
+	}
+	return nil
+}
+
+// ParseMode parses "mask", "hide" or "off"
+func ParseMode(value string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case Mask, Hide, Off:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown profanity filter mode %q, want mask, hide or off", value)
+}
+
+// Filter masks or hides profanity in text
+type Filter struct {
+	mode  Mode
+	stems []string
+	words map[string]bool
+}
+
+// New compiles settings into a filter
+func New(settings Settings) *Filter {
+	allowed := make(map[string]bool, len(settings.Allow))
+	for _, word := range settings.Allow {
+		allowed[strings.ToLower(strings.TrimSpace(word))] = true
+	}
+
+	mode, err := ParseMode(string(settings.Mode))
+	if err != nil {
+		mode = Mask
+	}
+	f := &Filter{mode: mode, words: make(map[string]bool)}
+	for _, stem := range stems {
+		if !allowed[stem] {
+			f.stems = append(f.stems, stem)
+		}
+	}
+	for _, word := range append(append([]string(nil), words...), settings.Words...) {
+		if word = strings.ToLower(strings.TrimSpace(word)); !allowed[word] {
+			f.words[word] = true
+		}
+	}
+	return f
+}
+
+// Clean returns text with its profanity masked, or HiddenText when it has any and the
+// filter hides. A nil filter masks with the default settings.
+func (f *Filter) Clean(text string) string {
+	if f == nil {
+		f = New(DefaultSettings)
+	}
+	if f.mode == Off {
+		return text
+	}
+
+	var b strings.Builder
+	found := false
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if isSeparator(runes[i]) {
+			b.WriteRune(runes[i])
+			i++
+			continue
+		}
+		end := i
+		for end < len(runes) && !isSeparator(runes[end]) {
+			end++
+		}
+		word := string(runes[i:end])
+		if f.matches(strings.ToLower(word)) {
+			found = true
+			b.WriteString(mask(runes[i:end]))
+		} else {
+			b.WriteString(word)
+		}
+		i = end
+	}
+
+	if found && f.mode == Hide {
+		return HiddenText
+	}
+	return b.String()
+}
+
+// matches reports whether a lower-cased word is profane
+func (f *Filter) matches(word string) bool {
+	word = strings.TrimSuffix(strings.TrimSuffix(word, "'s"), "’s")
+	if f.words[word] || f.words[strings.TrimSuffix(word, "s")] {
+		return true
+	}
+	for _, stem := range f.stems {
+		if strings.HasPrefix(word, stem) {
+			return true
+		}
+	}
+	return false
+}
+
+// mask keeps a word's first letter and stars the rest of its letters, up to a possessive
+func mask(word []rune) string {
+	masked := make([]rune, len(word))
+	possessive := false
+	for i, r := range word {
+		possessive = possessive || r == '\'' || r == '’'
+		if i > 0 && !possessive && unicode.IsLetter(r) {
+			r = '*'
+		}
+		masked[i] = r
+	}
+	return string(masked)
+}
+
+// isSeparator splits words: anything but a letter, digit or apostrophe
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '’'
+}
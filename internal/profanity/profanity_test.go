@@ -0,0 +1,60 @@
+package profanity
+
+import "testing"
+
+func TestClean(t *testing.T) {
+	filter := New(DefaultSettings)
+	tests := []struct {
+		text, want string
+	}{
+		{"What the FUCK is this", "What the F*** is this"},
+		{"absolute bullshit, fucking hell", "absolute b*******, f****** hell"},
+		{"that bastard's plan", "that b******'s plan"},
+		{"Bitches, whores and pricks", "B******, w***** and p*****"},
+		{"the cockpit of a raccoon in Scunthorpe", "the cockpit of a raccoon in Scunthorpe"},
+		{"no profanity here 🎉", "no profanity here 🎉"},
+	}
+	for _, tt := range tests {
+		if got := filter.Clean(tt.text); got != tt.want {
+			t.Errorf("Clean(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+
+	if got := (*Filter)(nil).Clean("oh shit"); got != "oh s***" {
+		t.Errorf("Expected a nil filter to mask, got %q", got)
+	}
+}
+
+func TestCleanModes(t *testing.T) {
+	hide := New(Settings{Mode: Hide})
+	if got := hide.Clean("this is shit"); got != HiddenText {
+		t.Errorf("Expected hidden text, got %q", got)
+	}
+	if got := hide.Clean("this is fine"); got != "this is fine" {
+		t.Errorf("Expected clean text kept, got %q", got)
+	}
+
+	if got := New(Settings{Mode: Off}).Clean("this is shit"); got != "this is shit" {
+		t.Errorf("Expected text unchanged when off, got %q", got)
+	}
+
+	custom := New(Settings{Mode: Mask, Words: []string{"Frack"}, Allow: []string{"piss", "shit"}})
+	if got := custom.Clean("frack, shit and piss"); got != "f****, shit and piss" {
+		t.Errorf("Expected extra words masked and allowed words kept, got %q", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	settings, err := Parse(`{"mode": "hide", "words": ["frack"], "allow": ["damn"]}`)
+	if err != nil || settings.Mode != Hide || len(settings.Words) != 1 || len(settings.Allow) != 1 {
+		t.Errorf("Unexpected settings %+v, %v", settings, err)
+	}
+	if settings, err := Parse(`{"words": ["frack"]}`); err != nil || settings.Mode != Mask {
+		t.Errorf("Expected the mode to default to mask, got %+v, %v", settings, err)
+	}
+	for _, value := range []string{`{"mode": "bleep"}`, `{"words": ["two words"]}`, `{"words": [""]}`, `{"level": 2}`} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("Expected an error for %s", value)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Package provenance records what produced a run's sentiment figures: the analyzer and
+// lexicon versions, the scoring settings, and the build of the code that ran. Runs keep
+// their record, so a shift in historical values can be traced to an algorithm change.
+package provenance
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
+)
+
+// govaderModule is the module path of the VADER implementation the analyzer builds on
+const govaderModule = "github.com/jonreiter/govader"
+
+// Unknown stands in for build details a binary wasn't built with, e.g. under go run
+const Unknown = "unknown"
+
+// Scoring are the settings that turned the posts' scores into the run's figures
+type Scoring struct {
+	Method aggregation.Method `json:"method" dynamodbav:"method"`
+	// Threshold is how far from zero the average score must be to count as positive or negative
+	Threshold float64 `json:"threshold" dynamodbav:"threshold"`
+	// Hysteresis is the band around the threshold in which the previous run's category was
+	// kept, 0 when there was none
+	Hysteresis float64 `json:"hysteresis,omitempty" dynamodbav:"hysteresis,omitempty"`
+	// SampleSize is how many posts were analyzed when the window was sampled, 0 when not
+	SampleSize int `json:"sampleSize,omitempty" dynamodbav:"sampleSize,omitempty"`
+}
+
+// Record is a run's provenance
+type Record struct {
+	// AnalyzerVersion and LexiconHash are analyzer.Version and the analyzer's LexiconHash
+	AnalyzerVersion int     `json:"analyzerVersion" dynamodbav:"analyzerVersion"`
+	LexiconHash     string  `json:"lexiconHash" dynamodbav:"lexiconHash"`
+	Scoring         Scoring `json:"scoring" dynamodbav:"scoring"`
+	// CodeVersion is the commit the binary was built from, suffixed "-dirty" when built with
+	// uncommitted changes, or the module version when it carries no commit
+	CodeVersion    string `json:"codeVersion" dynamodbav:"codeVersion"`
+	GovaderVersion string `json:"govaderVersion" dynamodbav:"govaderVersion"`
+	GoVersion      string `json:"goVersion" dynamodbav:"goVersion"`
+}
+
+// New returns a record of the given analyzer and scoring, filling in the running binary's
+// build details
+func New(analyzerVersion int, lexiconHash string, scoring Scoring) Record {
+	record := Record{
+		AnalyzerVersion: analyzerVersion,
+		LexiconHash:     lexiconHash,
+		Scoring:         scoring,
+		CodeVersion:     Unknown,
+		GovaderVersion:  Unknown,
+		GoVersion:       Unknown,
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		record.CodeVersion = codeVersion(info)
+		record.GovaderVersion = dependencyVersion(info, govaderModule)
+		record.GoVersion = info.GoVersion
+	}
+	return record
+}
+
+// codeVersion returns the VCS revision stamped into the build, or the main module's version
+func codeVersion(info *debug.BuildInfo) string {
+	var revision string
+	var modified bool
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision != "" {
+		if len(revision) > 12 {
+			revision = revision[:12]
+		}
+		if modified {
+			revision += "-dirty"
+		}
+		return revision
+	}
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return Unknown
+}
+
+// dependencyVersion returns the version of the module at path the build used
+func dependencyVersion(info *debug.BuildInfo, path string) string {
+	for _, dep := range info.Deps {
+		if dep.Path != path {
+			continue
+		}
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		if dep.Version != "" {
+			return dep.Version
+		}
+	}
+	return Unknown
+}
+
+// Lines describes the record for printing, one "Label: value" line per field
+func (r Record) Lines() []string {
+	sampling := "whole window"
+	if r.Scoring.SampleSize > 0 {
+		sampling = fmt.Sprintf("%d posts", r.Scoring.SampleSize)
+	}
+	threshold := fmt.Sprintf("%.2f", r.Scoring.Threshold)
+	if r.Scoring.Hysteresis > 0 {
+		threshold += fmt.Sprintf(" ± %.2f", r.Scoring.Hysteresis)
+	}
+	return []string{
+		fmt.Sprintf("Analyzer Version: %d", r.AnalyzerVersion),
+		fmt.Sprintf("Lexicon Hash: %s", r.LexiconHash),
+		fmt.Sprintf("Sentiment Method: %s", r.Scoring.Method),
+		fmt.Sprintf("Sentiment Threshold: %s", threshold),
+		fmt.Sprintf("Sampling: %s", sampling),
+		fmt.Sprintf("Code Version: %s", r.CodeVersion),
+		fmt.Sprintf("govader Version: %s", r.GovaderVersion),
+		fmt.Sprintf("Go Version: %s", r.GoVersion),
+	}
+}
+
+// Changes lists the lines of Lines that differ between r and other, as
+// "Label: r's value → other's value"
+func (r Record) Changes(other Record) []string {
+	var changes []string
+	before, after := r.Lines(), other.Lines()
+	for i := range before {
+		if before[i] == after[i] {
+			continue
+		}
+		label, value, _ := strings.Cut(before[i], ": ")
+		_, otherValue, _ := strings.Cut(after[i], ": ")
+		changes = append(changes, fmt.Sprintf("%s: %s → %s", label, value, otherValue))
+	}
+	return changes
+}
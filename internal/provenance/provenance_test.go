@@ -0,0 +1,71 @@
+package provenance
+
+import (
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
+)
+
+func TestCodeVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		info debug.BuildInfo
+		want string
+	}{
+		{"commit", debug.BuildInfo{Settings: []debug.BuildSetting{{Key: "vcs.revision", Value: "0123456789abcdef0123"}}}, "0123456789ab"},
+		{"dirty", debug.BuildInfo{Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "0123456789abcdef0123"},
+			{Key: "vcs.modified", Value: "true"},
+		}}, "0123456789ab-dirty"},
+		{"module version", debug.BuildInfo{Main: debug.Module{Version: "v1.2.3"}}, "v1.2.3"},
+		{"go run", debug.BuildInfo{Main: debug.Module{Version: "(devel)"}}, Unknown},
+	}
+	for _, tt := range tests {
+		if got := codeVersion(&tt.info); got != tt.want {
+			t.Errorf("%s: codeVersion() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDependencyVersion(t *testing.T) {
+	info := &debug.BuildInfo{Deps: []*debug.Module{
+		{Path: "example.com/other", Version: "v0.1.0"},
+		{Path: govaderModule, Version: "v0.0.0-1", Replace: &debug.Module{Path: "example.com/fork", Version: "v0.0.0-2"}},
+	}}
+	if got := dependencyVersion(info, govaderModule); got != "v0.0.0-2" {
+		t.Errorf("dependencyVersion() = %q, want the replacement's version", got)
+	}
+	if got := dependencyVersion(info, "example.com/missing"); got != Unknown {
+		t.Errorf("dependencyVersion() of a missing module = %q, want %q", got, Unknown)
+	}
+}
+
+func TestChanges(t *testing.T) {
+	recorded := Record{
+		AnalyzerVersion: 1,
+		LexiconHash:     "aaaaaaaaaaaa",
+		Scoring:         Scoring{Method: aggregation.DefaultMethod, Threshold: 0.3},
+		CodeVersion:     "0123456789ab",
+		GovaderVersion:  "v0.0.0-1",
+		GoVersion:       "go1.24.0",
+	}
+	if changes := recorded.Changes(recorded); len(changes) != 0 {
+		t.Errorf("Changes() of an identical record = %v, want none", changes)
+	}
+
+	current := recorded
+	current.LexiconHash = "bbbbbbbbbbbb"
+	current.Scoring.Hysteresis = 0.05
+	current.Scoring.SampleSize = 5000
+	changes := recorded.Changes(current)
+	want := []string{
+		"Lexicon Hash: aaaaaaaaaaaa → bbbbbbbbbbbb",
+		"Sentiment Threshold: 0.30 → 0.30 ± 0.05",
+		"Sampling: whole window → 5000 posts",
+	}
+	if strings.Join(changes, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Changes() = %q, want %q", changes, want)
+	}
+}
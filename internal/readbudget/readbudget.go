@@ -0,0 +1,65 @@
+// Package readbudget configures how much read capacity the lambdas may spend reading a
+// run's posts, so one huge run doesn't throttle everything else sharing hourstats-state
+package readbudget
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// ParameterName holds the JSON read budget; when it is absent posts are read without
+// limits, a page at a time
+//
+//	{"capacityUnits": 200, "segments": 4}
+const ParameterName = "/hourstats/settings/posts_read_budget"
+
+// ParameterGetter is the subset of the SSM client Load needs
+type ParameterGetter interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Load reads the read budget from SSM. A missing or empty parameter returns the zero
+// budget, which reads without limits.
+func Load(ctx context.Context, ssmClient ParameterGetter) (state.ReadBudget, error) {
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ParameterName),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return state.ReadBudget{}, nil
+		}
+		return state.ReadBudget{}, fmt.Errorf("failed to get %s: %w", ParameterName, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return state.ReadBudget{}, nil
+	}
+	return Parse(*result.Parameter.Value)
+}
+
+// Parse decodes and validates a JSON read budget
+func Parse(value string) (state.ReadBudget, error) {
+	var budget state.ReadBudget
+	if strings.TrimSpace(value) == "" {
+		return budget, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&budget); err != nil {
+		return state.ReadBudget{}, fmt.Errorf("invalid read budget JSON: %w", err)
+	}
+	if err := budget.Validate(); err != nil {
+		return state.ReadBudget{}, fmt.Errorf("invalid read budget: %w", err)
+	}
+	return budget, nil
+}
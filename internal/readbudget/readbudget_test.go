@@ -0,0 +1,47 @@
+package readbudget
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+type fakeParameterGetter struct {
+	value string
+}
+
+func (f fakeParameterGetter) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	if f.value == "" {
+		return nil, &types.ParameterNotFound{}
+	}
+	return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: aws.String(f.value)}}, nil
+}
+
+func TestParse(t *testing.T) {
+	budget, err := Parse(`{"capacityUnits": 200, "segments": 4}`)
+	if err != nil || budget != (state.ReadBudget{CapacityUnits: 200, Segments: 4}) {
+		t.Errorf("Parse() = %+v, %v, want 200 units in 4 segments", budget, err)
+	}
+
+	for _, value := range []string{`{"capacityUnits": -5}`, `{"segments": 64}`, `{"rcu": 100}`, `200`} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("Parse(%s) succeeded, want error", value)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	budget, err := Load(context.Background(), fakeParameterGetter{})
+	if err != nil || budget != (state.ReadBudget{}) {
+		t.Errorf("Load(missing) = %+v, %v, want the unlimited budget", budget, err)
+	}
+
+	budget, err = Load(context.Background(), fakeParameterGetter{value: `{"capacityUnits": 50}`})
+	if err != nil || budget.CapacityUnits != 50 {
+		t.Errorf("Load() = %+v, %v, want 50 units", budget, err)
+	}
+}
@@ -0,0 +1,109 @@
+// Package recompute re-scores stored runs with the current analyzer into a separate history
+// series, so a change to the analyzer or its lexicons can be compared against the live
+// history before charts are switched to it. Each run's posts are selected as the processor
+// selects them, and the run keeps the averaging method, threshold and sample it was recorded
+// with. Besides the analyzer, the series can differ where the blocklist has changed since a
+// run, since the current one applies, and for posts by members of moderation lists, which
+// recompute doesn't resolve.
+package recompute
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
+	"github.com/christophergentle/hourstats-bsky/internal/analyzer"
+	"github.com/christophergentle/hourstats-bsky/internal/blocklist"
+	"github.com/christophergentle/hourstats-bsky/internal/sampling"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// DefaultSeries is the series recomputed points are written to unless another is named
+const DefaultSeries = "v2"
+
+// Result is a run re-scored
+type Result struct {
+	RunID string
+	// Previous is the run's net sentiment in the live series
+	Previous float64
+	// Point is the run's point in the recomputed series
+	Point state.SentimentDataPoint
+}
+
+// Delta is the change in net sentiment the re-scoring made
+func (r Result) Delta() float64 {
+	return r.Point.NetSentimentPercent - r.Previous
+}
+
+// Run re-scores a run's stored posts with sa and returns its recomputed point, stamped with
+// timestamp so it lines up with the run's live point. Posts are deduplicated by URI, kept to
+// the run's window and filtered through blocked, as the processor does, before the run's
+// sample is drawn from them.
+func Run(sa *analyzer.SentimentAnalyzer, run state.RunState, posts []state.Post, blocked *blocklist.List, timestamp time.Time) (Result, error) {
+	posts, _ = blocked.Filter(run.WindowPosts(posts))
+	if len(posts) == 0 {
+		return Result{}, fmt.Errorf("no stored posts in the window of run %s", run.RunID)
+	}
+
+	method := aggregation.DefaultMethod
+	if run.SentimentMethod != nil {
+		method = *run.SentimentMethod
+	}
+	// Runs recorded before their provenance used the default threshold. Each run is
+	// categorized on its own, without the hysteresis of its previous run's category.
+	threshold := aggregation.DefaultThreshold
+	analysisPosts := posts
+	if run.Provenance != nil {
+		threshold = run.Provenance.Scoring.Threshold
+		if size := run.Provenance.Scoring.SampleSize; size > 0 && size < len(posts) {
+			analysisPosts = sampling.Reservoir(posts, size, sampling.Seed(run.RunID))
+		}
+	}
+
+	analyzerPosts := make([]analyzer.Post, len(analysisPosts))
+	for i, post := range analysisPosts {
+		analyzerPosts[i] = analyzer.Post{
+			URI:       post.URI,
+			CID:       post.CID,
+			Text:      post.Text,
+			Author:    post.Author,
+			Likes:     post.Likes,
+			Reposts:   post.Reposts,
+			Replies:   post.Replies,
+			CreatedAt: post.CreatedAt,
+			Langs:     post.Langs,
+		}
+	}
+	analyzed, err := sa.AnalyzePosts(analyzerPosts)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to analyze run %s: %w", run.RunID, err)
+	}
+
+	// AnalyzePosts keeps the order of its posts
+	scored := append([]state.Post(nil), analysisPosts...)
+	scores := make([]float64, len(analyzed))
+	for i, post := range analyzed {
+		post.Apply(&scored[i])
+		scores[i] = clamp(post.SentimentScore)
+	}
+
+	average := method.Average(scores)
+	point := state.NewSentimentDataPoint(run.RunID, aggregation.Categorize(average, threshold), average*100, run.TotalPostsRetrieved, timestamp)
+	counts := state.CountSentiments(scored)
+	point.Counts = &counts
+	weighted := state.EngagementWeightedNetSentiment(scored)
+	point.WeightedNetSentimentPercent = &weighted
+
+	return Result{RunID: run.RunID, Previous: run.NetSentimentPercentage, Point: point}, nil
+}
+
+// clamp keeps a compound score within VADER's range of -1 to 1
+func clamp(score float64) float64 {
+	if score > 1 {
+		return 1
+	}
+	if score < -1 {
+		return -1
+	}
+	return score
+}
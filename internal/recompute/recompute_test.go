@@ -0,0 +1,114 @@
+package recompute
+
+import (
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
+	"github.com/christophergentle/hourstats-bsky/internal/analyzer"
+	"github.com/christophergentle/hourstats-bsky/internal/blocklist"
+	"github.com/christophergentle/hourstats-bsky/internal/provenance"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func testRun() state.RunState {
+	return state.RunState{
+		RunID:                   "run-1",
+		CutoffTime:              time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC),
+		AnalysisIntervalMinutes: 60,
+		TotalPostsRetrieved:     4,
+		NetSentimentPercentage:  10,
+	}
+}
+
+func post(uri, text string, minute, likes int) state.Post {
+	return state.Post{
+		URI:       uri,
+		Text:      text,
+		Likes:     likes,
+		CreatedAt: time.Date(2025, 9, 1, 12, minute, 0, 0, time.UTC).Format(time.RFC3339),
+	}
+}
+
+func TestRun(t *testing.T) {
+	posts := []state.Post{
+		post("at://a", "I love this, what a wonderful day", 5, 10),
+		post("at://a", "I love this, what a wonderful day", 5, 3), // Older copy of the same post
+		post("at://b", "This is terrible and I hate it", 20, 0),
+		post("at://c", "Great news, so happy", 40, 1),
+		post("at://d", "Posted before the window", -1, 0),
+	}
+	at := time.Date(2025, 9, 1, 13, 2, 0, 0, time.UTC)
+
+	result, err := Run(analyzer.New(), testRun(), posts, nil, at)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	point := result.Point
+	if point.RunID != "run-1" || !point.Timestamp.Equal(at) || point.TotalPosts != 4 {
+		t.Errorf("Run() point = %+v", point)
+	}
+	if point.Counts == nil || point.Counts.Total() != 3 || point.Counts.Positive != 2 || point.Counts.Negative != 1 {
+		t.Errorf("Counts = %+v, want 2 positive and 1 negative of 3 window posts", point.Counts)
+	}
+	if point.NetSentimentPercent <= 0 || point.WeightedNetSentimentPercent == nil || *point.WeightedNetSentimentPercent <= point.NetSentimentPercent {
+		t.Errorf("Net sentiment %.1f%%, weighted %v: want positive, weighted higher for the liked positive post",
+			point.NetSentimentPercent, point.WeightedNetSentimentPercent)
+	}
+	if result.Delta() != point.NetSentimentPercent-10 {
+		t.Errorf("Delta() = %v, want the change from the live 10%%", result.Delta())
+	}
+}
+
+func TestRunKeepsRecordedScoring(t *testing.T) {
+	posts := []state.Post{
+		post("at://a", "I love this, what a wonderful day", 5, 0),
+		post("at://b", "Great news, so happy", 10, 0),
+		post("at://c", "This is terrible and I hate it", 20, 0),
+	}
+
+	run := testRun()
+	median := aggregation.Method{Name: aggregation.MethodMedian}
+	run.SentimentMethod = &median
+	run.Provenance = &provenance.Record{Scoring: provenance.Scoring{Method: median, Threshold: 0.99}}
+
+	result, err := Run(analyzer.New(), run, posts, nil, run.WindowEnd())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	// The median is a positive post's score, which falls short of the recorded threshold
+	if result.Point.NetSentimentPercent <= 0 || result.Point.SentimentCategory != "neutral" {
+		t.Errorf("Run() = %.1f%% %s, want a positive median categorized neutral",
+			result.Point.NetSentimentPercent, result.Point.SentimentCategory)
+	}
+}
+
+func TestRunSelectsPostsAsTheProcessor(t *testing.T) {
+	posts := []state.Post{
+		post("at://a", "I love this, what a wonderful day", 5, 0),
+		post("at://b", "This is terrible and I hate it", 20, 0),
+		post("at://c", "Great news, so happy", 70, 0), // After the window's end
+	}
+	posts[1].Author = "spam.bsky.social"
+	blocked := blocklist.New([]state.BlocklistEntry{{Kind: state.BlockAuthor, Value: "spam.bsky.social"}})
+
+	run := testRun()
+	result, err := Run(analyzer.New(), run, posts, blocked, run.WindowEnd())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Point.Counts.Total() != 2 || result.Point.Counts.Negative != 0 {
+		t.Errorf("Counts = %+v, want the two unblocked posts of a scheduled run", result.Point.Counts)
+	}
+
+	run.Manual = true
+	if result, err = Run(analyzer.New(), run, posts, blocked, run.WindowEnd()); err != nil || result.Point.Counts.Total() != 1 {
+		t.Errorf("Expected a manual run to drop the post after its window, got %+v, %v", result.Point.Counts, err)
+	}
+}
+
+func TestRunWithoutPosts(t *testing.T) {
+	if _, err := Run(analyzer.New(), testRun(), []state.Post{post("at://a", "Too early", -5, 0)}, nil, time.Now()); err == nil {
+		t.Error("Run() without window posts succeeded, want error")
+	}
+}
@@ -0,0 +1,121 @@
+package reporter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// HourScore is an hour's average net sentiment over the runs stamped in it
+type HourScore struct {
+	Hour                time.Time // start of the hour, UTC
+	Runs                int
+	NetSentimentPercent float64
+}
+
+// DeltaReport compares a day's sentiment and post volume with the day before it
+type DeltaReport struct {
+	Day, Previous state.DailySentimentDataPoint
+	// MostPositive and MostNegative are the day's extreme hours, nil when it has no runs
+	MostPositive, MostNegative *HourScore
+}
+
+// SentimentDelta is the change in average net sentiment, in points
+func (r DeltaReport) SentimentDelta() float64 {
+	return r.Day.AverageSentiment - r.Previous.AverageSentiment
+}
+
+// PostsDeltaPercent is the change in posts analyzed as a percentage of the day before,
+// and false when the day before analyzed none
+func (r DeltaReport) PostsDeltaPercent() (float64, bool) {
+	if r.Previous.TotalPosts == 0 {
+		return 0, false
+	}
+	return float64(r.Day.TotalPosts-r.Previous.TotalPosts) / float64(r.Previous.TotalPosts) * 100, true
+}
+
+// SummarizeDelta compares day with previous, finding day's most positive and most negative
+// hours among the Bluesky runs in points stamped on day's UTC date
+func SummarizeDelta(day, previous state.DailySentimentDataPoint, points []state.SentimentDataPoint) DeltaReport {
+	report := DeltaReport{Day: day, Previous: previous}
+
+	hours := make(map[time.Time]*HourScore)
+	for _, point := range points {
+		if point.Network != "" {
+			continue // a comparison network, not Bluesky
+		}
+		timestamp := point.Timestamp.UTC()
+		if timestamp.Format("2006-01-02") != day.Date {
+			continue
+		}
+		hour := timestamp.Truncate(time.Hour)
+		score, ok := hours[hour]
+		if !ok {
+			score = &HourScore{Hour: hour}
+			hours[hour] = score
+		}
+		score.Runs++
+		score.NetSentimentPercent += point.NetSentimentPercent
+	}
+
+	scores := make([]HourScore, 0, len(hours))
+	for _, score := range hours {
+		score.NetSentimentPercent /= float64(score.Runs)
+		scores = append(scores, *score)
+	}
+	if len(scores) == 0 {
+		return report
+	}
+	// Ties go to the earlier hour
+	sort.Slice(scores, func(i, j int) bool { return scores[i].Hour.Before(scores[j].Hour) })
+	positive, negative := scores[0], scores[0]
+	for _, score := range scores[1:] {
+		if score.NetSentimentPercent > positive.NetSentimentPercent {
+			positive = score
+		}
+		if score.NetSentimentPercent < negative.NetSentimentPercent {
+			negative = score
+		}
+	}
+	report.MostPositive, report.MostNegative = &positive, &negative
+	return report
+}
+
+// FormatDelta renders the day-over-day report as a post, with hours in loc
+func FormatDelta(r DeltaReport, loc *time.Location) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "📆 Day over day on Bluesky, %s vs %s\n\n", formatDate(r.Day.Date), formatDate(r.Previous.Date))
+	fmt.Fprintf(&b, "Sentiment: %+.1f%% (%+.1f points)\n", r.Day.AverageSentiment, r.SentimentDelta())
+	if change, ok := r.PostsDeltaPercent(); ok {
+		fmt.Fprintf(&b, "Posts analyzed: %s (%+.0f%%)\n", formatCount(r.Day.TotalPosts), change)
+	} else {
+		fmt.Fprintf(&b, "Posts analyzed: %s\n", formatCount(r.Day.TotalPosts))
+	}
+	if r.MostPositive != nil && r.MostNegative != nil {
+		fmt.Fprintf(&b, "Most positive hour: %s, %+.1f%%\n", formatHour(r.MostPositive.Hour, loc), r.MostPositive.NetSentimentPercent)
+		fmt.Fprintf(&b, "Most negative hour: %s, %+.1f%%\n", formatHour(r.MostNegative.Hour, loc), r.MostNegative.NetSentimentPercent)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// formatDate formats a YYYY-MM-DD date as "Mon Sep 1", leaving a malformed date as it is
+func formatDate(date string) string {
+	parsed, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return parsed.Format("Mon Jan 2")
+}
+
+// formatHour formats the start of an hour in loc, e.g. "3 PM BST", keeping the minutes
+// for zones offset from UTC by part of an hour
+func formatHour(hour time.Time, loc *time.Location) string {
+	local := hour.In(loc)
+	if local.Minute() != 0 {
+		return local.Format("3:04 PM MST")
+	}
+	return local.Format("3 PM MST")
+}
@@ -0,0 +1,74 @@
+package reporter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func TestSummarizeDelta(t *testing.T) {
+	day := state.DailySentimentDataPoint{Date: "2025-09-07", AverageSentiment: -4, TotalPosts: 11000}
+	previous := state.DailySentimentDataPoint{Date: "2025-09-06", AverageSentiment: 2.5, TotalPosts: 10000}
+	start := time.Date(2025, 9, 7, 0, 0, 0, 0, time.UTC)
+	points := []state.SentimentDataPoint{
+		{Timestamp: start.Add(-time.Minute), NetSentimentPercent: 90}, // the day before
+		{Timestamp: start.Add(3*time.Hour + 5*time.Minute), NetSentimentPercent: 10},
+		{Timestamp: start.Add(3*time.Hour + 35*time.Minute), NetSentimentPercent: 20},
+		{Timestamp: start.Add(9 * time.Hour), NetSentimentPercent: -30},
+		{Timestamp: start.Add(9 * time.Hour), NetSentimentPercent: 60, Network: "mastodon"},
+		{Timestamp: start.Add(14 * time.Hour), NetSentimentPercent: 0},
+	}
+
+	report := SummarizeDelta(day, previous, points)
+
+	if got := report.SentimentDelta(); got != -6.5 {
+		t.Errorf("Expected a sentiment delta of -6.5, got %.1f", got)
+	}
+	if got, ok := report.PostsDeltaPercent(); !ok || got != 10 {
+		t.Errorf("Expected a posts delta of 10%%, got %.1f (%v)", got, ok)
+	}
+	if got := report.MostPositive; got == nil || !got.Hour.Equal(start.Add(3*time.Hour)) || got.Runs != 2 || got.NetSentimentPercent != 15 {
+		t.Errorf("Unexpected most positive hour %+v", got)
+	}
+	if got := report.MostNegative; got == nil || !got.Hour.Equal(start.Add(9*time.Hour)) || got.NetSentimentPercent != -30 {
+		t.Errorf("Unexpected most negative hour %+v", got)
+	}
+
+	if empty := SummarizeDelta(day, previous, nil); empty.MostPositive != nil || empty.MostNegative != nil {
+		t.Errorf("Expected no extreme hours without runs, got %+v", empty)
+	}
+}
+
+func TestFormatDelta(t *testing.T) {
+	start := time.Date(2025, 9, 7, 0, 0, 0, 0, time.UTC)
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skipf("No time zone data: %v", err)
+	}
+	text := FormatDelta(DeltaReport{
+		Day:          state.DailySentimentDataPoint{Date: "2025-09-07", AverageSentiment: -4, TotalPosts: 11000},
+		Previous:     state.DailySentimentDataPoint{Date: "2025-09-06", AverageSentiment: 2.5, TotalPosts: 10000},
+		MostPositive: &HourScore{Hour: start.Add(3 * time.Hour), NetSentimentPercent: 15},
+		MostNegative: &HourScore{Hour: start.Add(14 * time.Hour), NetSentimentPercent: -30},
+	}, london)
+
+	for _, want := range []string{
+		"Sun Sep 7 vs Sat Sep 6",
+		"Sentiment: -4.0% (-6.5 points)",
+		"Posts analyzed: 11,000 (+10%)",
+		"Most positive hour: 4 AM BST, +15.0%",
+		"Most negative hour: 3 PM BST, -30.0%",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+
+	// Without posts the day before there is no change to report
+	text = FormatDelta(DeltaReport{Day: state.DailySentimentDataPoint{Date: "2025-09-07", TotalPosts: 5}}, time.UTC)
+	if !strings.HasSuffix(text, "Posts analyzed: 5") {
+		t.Errorf("Expected posts without a change, got:\n%s", text)
+	}
+}
@@ -0,0 +1,99 @@
+package reporter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// DomainsPerReport is how many domains the most shared domains report lists
+const DomainsPerReport = 5
+
+// maxDomainRunes truncates long domains so the report fits in one post
+const maxDomainRunes = 30
+
+// DomainScore is a link domain's shares and sentiment over the report's runs
+type DomainScore struct {
+	Domain              string
+	Runs                int // runs the domain was among the most shared in
+	Posts               int
+	Shares              int     // posts and their reposts
+	NetSentimentPercent float64 // averaged over the domain's posts, not its runs
+}
+
+// DomainsReport ranks the week's most shared link domains
+type DomainsReport struct {
+	Start, End time.Time // earliest and latest run with domains
+	Runs       int       // runs that stored link domains
+	Domains    []DomainScore
+}
+
+// SummarizeDomains combines each run's most shared domains, weighting sentiment by the
+// domain's posts in each run, and ranks the limit most shared domains
+func SummarizeDomains(points []state.SentimentDataPoint, limit int) DomainsReport {
+	var report DomainsReport
+	totals := make(map[string]*DomainScore)
+	for _, point := range points {
+		if len(point.Domains) == 0 {
+			continue
+		}
+		if report.Runs == 0 || point.Timestamp.Before(report.Start) {
+			report.Start = point.Timestamp
+		}
+		if point.Timestamp.After(report.End) {
+			report.End = point.Timestamp
+		}
+		report.Runs++
+
+		for _, domain := range point.Domains {
+			total, ok := totals[domain.Domain]
+			if !ok {
+				total = &DomainScore{Domain: domain.Domain}
+				totals[domain.Domain] = total
+			}
+			total.Runs++
+			total.Posts += domain.Posts
+			total.Shares += domain.Shares
+			total.NetSentimentPercent += domain.NetSentimentPercent * float64(domain.Posts)
+		}
+	}
+
+	for _, total := range totals {
+		if total.Posts > 0 {
+			total.NetSentimentPercent /= float64(total.Posts)
+		}
+		report.Domains = append(report.Domains, *total)
+	}
+	sort.Slice(report.Domains, func(i, j int) bool {
+		if report.Domains[i].Shares != report.Domains[j].Shares {
+			return report.Domains[i].Shares > report.Domains[j].Shares
+		}
+		return report.Domains[i].Domain < report.Domains[j].Domain
+	})
+	if len(report.Domains) > limit {
+		report.Domains = report.Domains[:limit]
+	}
+	return report
+}
+
+// FormatDomains renders the most shared domains report as a post
+func FormatDomains(r DomainsReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🔗 Most shared sites this week, %s to %s\n\n", r.Start.Format("Jan 2"), r.End.Format("Jan 2"))
+	for i, domain := range r.Domains {
+		fmt.Fprintf(&b, "%d. %s %s shares, %+.1f%%\n", i+1, truncateDomain(domain.Domain), formatCount(domain.Shares), domain.NetSentimentPercent)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// truncateDomain shortens domain to maxDomainRunes, marking the cut with an ellipsis
+func truncateDomain(domain string) string {
+	runes := []rune(domain)
+	if len(runes) <= maxDomainRunes {
+		return domain
+	}
+	return string(runes[:maxDomainRunes-1]) + "…"
+}
@@ -0,0 +1,54 @@
+package reporter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func TestSummarizeDomains(t *testing.T) {
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	points := []state.SentimentDataPoint{
+		{Timestamp: start, Domains: []state.DomainShare{
+			{Domain: "bbc.co.uk", Posts: 10, Shares: 400, NetSentimentPercent: -20},
+			{Domain: "example.com", Posts: 5, Shares: 50, NetSentimentPercent: 30},
+		}},
+		// bbc.co.uk weighted by its posts: (10*-20 + 30*0) / 40 = -5
+		{Timestamp: start.Add(24 * time.Hour), Domains: []state.DomainShare{
+			{Domain: "bbc.co.uk", Posts: 30, Shares: 900, NetSentimentPercent: 0},
+			{Domain: "apnews.com", Posts: 20, Shares: 1000, NetSentimentPercent: -40},
+		}},
+		{Timestamp: start.Add(48 * time.Hour)}, // no links
+	}
+
+	report := SummarizeDomains(points, 2)
+
+	if report.Runs != 2 || !report.Start.Equal(start) || !report.End.Equal(start.Add(24*time.Hour)) {
+		t.Errorf("Unexpected report span %+v", report)
+	}
+	if len(report.Domains) != 2 || report.Domains[0].Domain != "bbc.co.uk" || report.Domains[1].Domain != "apnews.com" {
+		t.Fatalf("Expected bbc.co.uk then apnews.com, got %+v", report.Domains)
+	}
+	if got := report.Domains[0]; got.Runs != 2 || got.Posts != 40 || got.Shares != 1300 || got.NetSentimentPercent != -5 {
+		t.Errorf("Unexpected bbc.co.uk totals %+v", got)
+	}
+}
+
+func TestFormatDomains(t *testing.T) {
+	text := FormatDomains(DomainsReport{
+		Start: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2025, 9, 7, 23, 30, 0, 0, time.UTC),
+		Domains: []DomainScore{
+			{Domain: "bbc.co.uk", Shares: 12400, NetSentimentPercent: -5},
+			{Domain: strings.Repeat("long", 10) + ".com", Shares: 12, NetSentimentPercent: 20},
+		},
+	})
+
+	for _, want := range []string{"Sep 1 to Sep 7", "1. bbc.co.uk 12,400 shares, -5.0%", "2. longlonglonglonglonglonglongl… 12 shares, +20.0%"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+}
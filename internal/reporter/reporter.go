@@ -0,0 +1,166 @@
+// Package reporter assembles the monthly transparency report, summarising how reliably the
+// bot ran from the daily run activity the daily aggregator stores and how its own posts
+// were received from the self stats job's measurements, and the weekly topics
+// report from the topic sentiment each run stores
+package reporter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// ExpectedRunsPerDay is one run every 30 minutes
+const ExpectedRunsPerDay = 48
+
+// Report summarises a calendar month of runs
+type Report struct {
+	Month           time.Time // first day of the month, UTC
+	DaysInMonth     int
+	DaysWithData    int
+	RunsStarted     int // runs the orchestrator created
+	RunsSucceeded   int // runs that stored sentiment
+	PostsAnalyzed   int
+	CoveragePercent float64 // mean window coverage, weighted by runs started
+
+	// Engagement on the bot's own posts made during the month
+	OwnPosts   int
+	OwnLikes   int
+	OwnReposts int
+	OwnReplies int
+	// BestKind is the kind of post with the most engagement per post, when more than one
+	// kind was posted
+	BestKind        string
+	BestKindAverage float64
+}
+
+// ExpectedRuns is how many runs a month of uninterrupted half-hourly runs would produce
+func (r Report) ExpectedRuns() int {
+	return r.DaysInMonth * ExpectedRunsPerDay
+}
+
+// UptimePercent is the share of expected runs that stored sentiment, capped at 100
+func (r Report) UptimePercent() float64 {
+	if r.ExpectedRuns() == 0 {
+		return 0
+	}
+	return min(float64(r.RunsSucceeded)/float64(r.ExpectedRuns())*100, 100)
+}
+
+// Summarize builds the report for the month containing month from daily data points.
+// Points outside the month are ignored. Days aggregated before run activity was recorded
+// count their successful runs as started and do not contribute to coverage.
+func Summarize(month time.Time, days []state.DailySentimentDataPoint) Report {
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	report := Report{
+		Month:       start,
+		DaysInMonth: start.AddDate(0, 1, -1).Day(),
+	}
+
+	prefix := start.Format("2006-01-")
+	seen := make(map[string]bool)
+	var coverageRuns int
+	var coverage float64
+	for _, day := range days {
+		if !strings.HasPrefix(day.Date, prefix) || seen[day.Date] {
+			continue
+		}
+		seen[day.Date] = true
+
+		report.DaysWithData++
+		report.RunsSucceeded += day.TotalRuns
+		report.PostsAnalyzed += day.TotalPosts
+		if day.RunsStarted > 0 {
+			report.RunsStarted += day.RunsStarted
+			coverageRuns += day.RunsStarted
+			coverage += day.AverageCoveragePercent * float64(day.RunsStarted)
+		} else {
+			report.RunsStarted += day.TotalRuns
+		}
+	}
+
+	if coverageRuns > 0 {
+		report.CoveragePercent = coverage / float64(coverageRuns)
+	}
+	return report
+}
+
+// AddOwnPosts adds the engagement on the bot's own posts made during the report's month;
+// posts from other months are ignored
+func (r *Report) AddOwnPosts(posts []state.PostStats) {
+	end := r.Month.AddDate(0, 1, 0)
+	totals := make(map[string]int)
+	counts := make(map[string]int)
+	for _, post := range posts {
+		if post.PostedAt.Before(r.Month) || !post.PostedAt.Before(end) {
+			continue
+		}
+		r.OwnPosts++
+		r.OwnLikes += post.Likes
+		r.OwnReposts += post.Reposts
+		r.OwnReplies += post.Replies
+		totals[post.Kind] += post.Total()
+		counts[post.Kind]++
+	}
+
+	if len(counts) < 2 {
+		return
+	}
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		if average := float64(totals[kind]) / float64(counts[kind]); average > r.BestKindAverage {
+			r.BestKind, r.BestKindAverage = kind, average
+		}
+	}
+}
+
+// Format renders the report as a post
+func Format(r Report) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🤖 Transparency report, %s\n\n", r.Month.Format("January 2006"))
+	fmt.Fprintf(&b, "⏱️ Uptime: %.1f%% (%d of %d expected runs)\n", r.UptimePercent(), r.RunsSucceeded, r.ExpectedRuns())
+	if failed := r.RunsStarted - r.RunsSucceeded; failed > 0 {
+		fmt.Fprintf(&b, "🔁 Runs started: %d (%d skipped or failed)\n", r.RunsStarted, failed)
+	} else {
+		fmt.Fprintf(&b, "🔁 Runs started: %d\n", r.RunsStarted)
+	}
+	fmt.Fprintf(&b, "📝 Posts analyzed: %s\n", formatCount(r.PostsAnalyzed))
+	if r.CoveragePercent > 0 {
+		fmt.Fprintf(&b, "🕰️ Average window coverage: %.1f%%\n", r.CoveragePercent)
+	}
+	if r.OwnPosts > 0 {
+		fmt.Fprintf(&b, "💬 Our %s posts: %s likes, %s reposts, %s replies\n", formatCount(r.OwnPosts),
+			formatCount(r.OwnLikes), formatCount(r.OwnReposts), formatCount(r.OwnReplies))
+	}
+	if r.BestKind != "" {
+		fmt.Fprintf(&b, "🏆 Best format: %s, %.1f interactions per post\n", r.BestKind, r.BestKindAverage)
+	}
+	if r.DaysWithData < r.DaysInMonth {
+		fmt.Fprintf(&b, "📅 Data for %d of %d days\n", r.DaysWithData, r.DaysInMonth)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// formatCount adds thousands separators, e.g. 1234567 -> "1,234,567"
+func formatCount(n int) string {
+	digits := fmt.Sprintf("%d", n)
+	if n < 0 {
+		return "-" + formatCount(-n)
+	}
+
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
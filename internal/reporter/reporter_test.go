@@ -0,0 +1,113 @@
+package reporter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func TestSummarize(t *testing.T) {
+	days := []state.DailySentimentDataPoint{
+		{Date: "2025-09-01", TotalRuns: 46, TotalPosts: 100000, RunsStarted: 48, AverageCoveragePercent: 90},
+		{Date: "2025-09-02", TotalRuns: 48, TotalPosts: 120000, RunsStarted: 48, AverageCoveragePercent: 100},
+		{Date: "2025-09-02", TotalRuns: 48, TotalPosts: 120000, RunsStarted: 48, AverageCoveragePercent: 100}, // duplicate
+		{Date: "2025-09-03", TotalRuns: 40, TotalPosts: 80000},                                                // aggregated before run activity was recorded
+		{Date: "2025-08-31", TotalRuns: 48, TotalPosts: 999999, RunsStarted: 48},
+		{Date: "2025-10-01", TotalRuns: 48, TotalPosts: 999999, RunsStarted: 48},
+	}
+
+	report := Summarize(time.Date(2025, 9, 17, 8, 0, 0, 0, time.UTC), days)
+
+	if report.DaysInMonth != 30 || report.DaysWithData != 3 {
+		t.Errorf("Expected 3 of 30 days, got %d of %d", report.DaysWithData, report.DaysInMonth)
+	}
+	if report.RunsSucceeded != 134 || report.RunsStarted != 136 {
+		t.Errorf("Expected 134 of 136 runs succeeded, got %d of %d", report.RunsSucceeded, report.RunsStarted)
+	}
+	if report.PostsAnalyzed != 300000 {
+		t.Errorf("Expected 300000 posts, got %d", report.PostsAnalyzed)
+	}
+	if report.CoveragePercent != 95 {
+		t.Errorf("Expected coverage averaged over days with run activity, got %.2f", report.CoveragePercent)
+	}
+	if report.ExpectedRuns() != 1440 {
+		t.Errorf("Expected 1440 expected runs, got %d", report.ExpectedRuns())
+	}
+	if uptime := report.UptimePercent(); uptime < 9.30 || uptime > 9.31 {
+		t.Errorf("Expected uptime 134/1440, got %.3f", uptime)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	report := Report{
+		Month:           time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		DaysInMonth:     28,
+		DaysWithData:    28,
+		RunsStarted:     1344,
+		RunsSucceeded:   1300,
+		PostsAnalyzed:   2345678,
+		CoveragePercent: 97.25,
+		OwnPosts:        1402,
+		OwnLikes:        12345,
+		OwnReposts:      1203,
+		OwnReplies:      456,
+		BestKind:        "image",
+		BestKindAverage: 12.25,
+	}
+
+	text := Format(report)
+	for _, want := range []string{
+		"Transparency report, February 2025",
+		"Uptime: 96.7% (1300 of 1344 expected runs)",
+		"Runs started: 1344 (44 skipped or failed)",
+		"Posts analyzed: 2,345,678",
+		"Average window coverage: 97.2%",
+		"Our 1,402 posts: 12,345 likes, 1,203 reposts, 456 replies",
+		"Best format: image, 12.2 interactions per post",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in report:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Data for") {
+		t.Errorf("Expected no missing-days note for a complete month:\n%s", text)
+	}
+	if n := len([]rune(text)); n > 300 {
+		t.Errorf("Report is %d characters, over the post limit", n)
+	}
+}
+
+func TestAddOwnPosts(t *testing.T) {
+	month := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	report := Report{Month: month}
+	report.AddOwnPosts([]state.PostStats{
+		{Kind: "image", PostedAt: month.Add(time.Hour), Likes: 10, Reposts: 2, Replies: 1},
+		{Kind: "image", PostedAt: month.Add(2 * time.Hour), Likes: 6, Quotes: 1},
+		{Kind: "text", PostedAt: month.Add(3 * time.Hour), Likes: 3},
+		{Kind: "text", PostedAt: month.AddDate(0, 1, 0), Likes: 100}, // next month
+	})
+
+	if report.OwnPosts != 3 || report.OwnLikes != 19 || report.OwnReposts != 2 || report.OwnReplies != 1 {
+		t.Errorf("Expected 3 posts with 19 likes, 2 reposts and 1 reply, got %+v", report)
+	}
+	if report.BestKind != "image" || report.BestKindAverage != 10 {
+		t.Errorf("Expected image posts best at 10 interactions each, got %s at %.1f", report.BestKind, report.BestKindAverage)
+	}
+
+	single := Report{Month: month}
+	single.AddOwnPosts([]state.PostStats{{Kind: "image", PostedAt: month, Likes: 5}})
+	if single.BestKind != "" {
+		t.Errorf("Expected no best format with one kind of post, got %q", single.BestKind)
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	tests := map[int]string{0: "0", 999: "999", 1000: "1,000", 1234567: "1,234,567", -4500: "-4,500"}
+	for n, want := range tests {
+		if got := formatCount(n); got != want {
+			t.Errorf("formatCount(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
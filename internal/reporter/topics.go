@@ -0,0 +1,123 @@
+package reporter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// Topics report thresholds: a topic must have trended in MinTopicRuns runs to be ranked,
+// and each list has at most TopicsPerList topics
+const (
+	MinTopicRuns  = 3
+	TopicsPerList = 3
+)
+
+// maxTopicRunes truncates long hashtags so the report fits in one post
+const maxTopicRunes = 25
+
+// TopicScore is a topic's sentiment over the report's runs
+type TopicScore struct {
+	Topic               string
+	Runs                int // runs the topic trended in
+	Posts               int
+	NetSentimentPercent float64 // averaged over the topic's posts, not its runs
+}
+
+// TopicsReport ranks the week's trending topics by sentiment
+type TopicsReport struct {
+	Start, End time.Time    // earliest and latest run with topics
+	Runs       int          // runs that stored topic sentiment
+	Loved      []TopicScore // most positive first, all above zero
+	Hated      []TopicScore // most negative first, all below zero
+}
+
+// SummarizeTopics combines each run's topic sentiment, weighting runs by their posts on
+// the topic, and ranks topics that trended in at least minRuns runs
+func SummarizeTopics(points []state.SentimentDataPoint, minRuns int) TopicsReport {
+	var report TopicsReport
+	totals := make(map[string]*TopicScore)
+	for _, point := range points {
+		if len(point.Topics) == 0 {
+			continue
+		}
+		if report.Runs == 0 || point.Timestamp.Before(report.Start) {
+			report.Start = point.Timestamp
+		}
+		if point.Timestamp.After(report.End) {
+			report.End = point.Timestamp
+		}
+		report.Runs++
+
+		for _, topic := range point.Topics {
+			total, ok := totals[topic.Topic]
+			if !ok {
+				total = &TopicScore{Topic: topic.Topic}
+				totals[topic.Topic] = total
+			}
+			total.Runs++
+			total.Posts += topic.Posts
+			total.NetSentimentPercent += topic.NetSentimentPercent * float64(topic.Posts)
+		}
+	}
+
+	var ranked []TopicScore
+	for _, total := range totals {
+		if total.Runs < minRuns || total.Posts == 0 {
+			continue
+		}
+		total.NetSentimentPercent /= float64(total.Posts)
+		ranked = append(ranked, *total)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].NetSentimentPercent != ranked[j].NetSentimentPercent {
+			return ranked[i].NetSentimentPercent > ranked[j].NetSentimentPercent
+		}
+		return ranked[i].Topic < ranked[j].Topic
+	})
+
+	for _, topic := range ranked {
+		if topic.NetSentimentPercent <= 0 || len(report.Loved) == TopicsPerList {
+			break
+		}
+		report.Loved = append(report.Loved, topic)
+	}
+	for i := len(ranked) - 1; i >= 0; i-- {
+		if ranked[i].NetSentimentPercent >= 0 || len(report.Hated) == TopicsPerList {
+			break
+		}
+		report.Hated = append(report.Hated, ranked[i])
+	}
+	return report
+}
+
+// FormatTopics renders the topics report as a post
+func FormatTopics(r TopicsReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🏷️ Topics this week, %s to %s\n", r.Start.Format("Jan 2"), r.End.Format("Jan 2"))
+	if len(r.Loved) > 0 {
+		b.WriteString("\n❤️ Most loved\n")
+		for _, topic := range r.Loved {
+			fmt.Fprintf(&b, "%s %+.1f%%\n", truncateTopic(topic.Topic), topic.NetSentimentPercent)
+		}
+	}
+	if len(r.Hated) > 0 {
+		b.WriteString("\n💢 Most hated\n")
+		for _, topic := range r.Hated {
+			fmt.Fprintf(&b, "%s %+.1f%%\n", truncateTopic(topic.Topic), topic.NetSentimentPercent)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// truncateTopic shortens topic to maxTopicRunes, marking the cut with an ellipsis
+func truncateTopic(topic string) string {
+	runes := []rune(topic)
+	if len(runes) <= maxTopicRunes {
+		return topic
+	}
+	return string(runes[:maxTopicRunes-1]) + "…"
+}
@@ -0,0 +1,60 @@
+package reporter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func TestSummarizeTopics(t *testing.T) {
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	var points []state.SentimentDataPoint
+	for i := 0; i < 3; i++ {
+		points = append(points, state.SentimentDataPoint{
+			Timestamp: start.Add(time.Duration(i) * 24 * time.Hour),
+			Topics: []state.TopicSentiment{
+				{Topic: "#caturday", Posts: 10, NetSentimentPercent: 40},
+				{Topic: "music", Posts: 10, NetSentimentPercent: 20},
+				{Topic: "politics", Posts: 30, NetSentimentPercent: -30},
+			},
+		})
+	}
+	// A run weighted by its posts: politics averages (3*30*-30 + 90*0) / 180 = -15
+	points = append(points, state.SentimentDataPoint{
+		Timestamp: start.Add(72 * time.Hour),
+		Topics: []state.TopicSentiment{
+			{Topic: "politics", Posts: 90, NetSentimentPercent: 0},
+			{Topic: "#oneoff", Posts: 50, NetSentimentPercent: 90},
+		},
+	})
+	points = append(points, state.SentimentDataPoint{Timestamp: start.Add(96 * time.Hour)}) // no topics
+
+	report := SummarizeTopics(points, MinTopicRuns)
+
+	if report.Runs != 4 || !report.Start.Equal(start) || !report.End.Equal(start.Add(72*time.Hour)) {
+		t.Errorf("Unexpected report span %+v", report)
+	}
+	if len(report.Loved) != 2 || report.Loved[0].Topic != "#caturday" || report.Loved[1].Topic != "music" {
+		t.Errorf("Expected #caturday then music loved, got %+v", report.Loved)
+	}
+	if len(report.Hated) != 1 || report.Hated[0].Topic != "politics" || report.Hated[0].NetSentimentPercent != -15 {
+		t.Errorf("Expected politics hated at -15%%, got %+v", report.Hated)
+	}
+}
+
+func TestFormatTopics(t *testing.T) {
+	text := FormatTopics(TopicsReport{
+		Start: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2025, 9, 7, 23, 30, 0, 0, time.UTC),
+		Loved: []TopicScore{{Topic: "#caturday", NetSentimentPercent: 40}},
+		Hated: []TopicScore{{Topic: "#" + strings.Repeat("long", 10), NetSentimentPercent: -15}},
+	})
+
+	for _, want := range []string{"Sep 1 to Sep 7", "❤️ Most loved\n#caturday +40.0%", "💢 Most hated\n#longlonglonglonglonglon… -15.0%"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+}
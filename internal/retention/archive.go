@@ -0,0 +1,135 @@
+package retention
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
+)
+
+// ArchiveLookahead is how far ahead of TTL expiry runs are archived. The archiver runs
+// daily, so this leaves a margin for a missed or late invocation.
+const ArchiveLookahead = 36 * time.Hour
+
+// Archiver copies a run summary to long-term storage. Archiving the same run twice
+// overwrites the earlier copy.
+type Archiver interface {
+	ArchiveRun(ctx context.Context, run state.RunState) error
+}
+
+// NewArchiver returns the archiver for the configured destination
+func NewArchiver(ctx context.Context, archive ArchiveConfig) (Archiver, error) {
+	if !archive.Enabled() {
+		return nil, fmt.Errorf("no archive table or bucket configured")
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, tracing.WithAWS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	if archive.Table != "" {
+		return &TableArchiver{client: dynamodb.NewFromConfig(cfg), tableName: archive.Table}, nil
+	}
+	return &S3Archiver{client: s3.NewFromConfig(cfg), bucket: archive.Bucket, prefix: archive.Prefix}, nil
+}
+
+// ArchiveRuns archives each run, carrying on past failures
+// Returns how many runs were archived and every error encountered
+func ArchiveRuns(ctx context.Context, archiver Archiver, runs []state.RunState) (int, error) {
+	archived := 0
+	var errs []error
+	for _, run := range runs {
+		if err := archiver.ArchiveRun(ctx, run); err != nil {
+			errs = append(errs, fmt.Errorf("run %s: %w", run.RunID, err))
+			continue
+		}
+		archived++
+	}
+	return archived, errors.Join(errs...)
+}
+
+// summary strips a run state down to what is worth keeping long term
+// The TTL is cleared so the copy never expires
+func summary(run state.RunState) state.RunState {
+	run.TTL = 0
+	run.CurrentCursor = ""
+	return run
+}
+
+// ItemPutter is the subset of the DynamoDB client TableArchiver needs
+type ItemPutter interface {
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+}
+
+// TableArchiver copies run summaries into a DynamoDB table keyed by runId
+type TableArchiver struct {
+	client    ItemPutter
+	tableName string
+}
+
+// ArchiveRun stores the run summary without a TTL attribute
+func (a *TableArchiver) ArchiveRun(ctx context.Context, run state.RunState) error {
+	item, err := attributevalue.MarshalMap(summary(run))
+	if err != nil {
+		return fmt.Errorf("failed to marshal run summary: %w", err)
+	}
+	delete(item, "ttl")
+
+	_, err = a.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(a.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to archive run summary to %s: %w", a.tableName, err)
+	}
+	return nil
+}
+
+// ObjectPutter is the subset of the S3 client S3Archiver needs
+type ObjectPutter interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// S3Archiver writes run summaries as JSON objects, one per run
+type S3Archiver struct {
+	client ObjectPutter
+	bucket string
+	prefix string
+}
+
+// ArchiveRun writes the run summary under the day the run was created
+func (a *S3Archiver) ArchiveRun(ctx context.Context, run state.RunState) error {
+	data, err := json.MarshalIndent(summary(run), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run summary: %w", err)
+	}
+
+	key := ArchiveKey(a.prefix, run)
+	_, err = a.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(a.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/json"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to archive run summary to s3://%s/%s: %w", a.bucket, key, err)
+	}
+	return nil
+}
+
+// ArchiveKey is the S3 key for a run summary, e.g. run-archive/2025/01/05/<runID>.json
+func ArchiveKey(prefix string, run state.RunState) string {
+	return path.Join(prefix, run.CreatedAt.UTC().Format("2006/01/02"), run.RunID+".json")
+}
@@ -0,0 +1,187 @@
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
+)
+
+// ArchiveReader reads archived run summaries back, for tools that browse history
+type ArchiveReader interface {
+	// RunsBetween returns the runs created in [start, end), oldest first
+	RunsBetween(ctx context.Context, start, end time.Time) ([]state.RunState, error)
+}
+
+// NewArchiveReader returns the reader for the configured destination
+func NewArchiveReader(ctx context.Context, archive ArchiveConfig) (ArchiveReader, error) {
+	if !archive.Enabled() {
+		return nil, fmt.Errorf("no archive table or bucket configured")
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, tracing.WithAWS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	if archive.Table != "" {
+		return &TableReader{client: dynamodb.NewFromConfig(cfg), tableName: archive.Table}, nil
+	}
+	return &S3Reader{client: s3.NewFromConfig(cfg), bucket: archive.Bucket, prefix: archive.Prefix}, nil
+}
+
+// ItemScanner is the subset of the DynamoDB client TableReader needs
+type ItemScanner interface {
+	Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+}
+
+// TableReader reads run summaries from the DynamoDB archive table
+type TableReader struct {
+	client    ItemScanner
+	tableName string
+}
+
+// RunsBetween scans the archive table for runs created in [start, end)
+// Handles pagination since the filter is applied after DynamoDB reads each page
+func (r *TableReader) RunsBetween(ctx context.Context, start, end time.Time) ([]state.RunState, error) {
+	var runs []state.RunState
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		scanInput := &dynamodb.ScanInput{
+			TableName:        aws.String(r.tableName),
+			FilterExpression: aws.String("#createdAt >= :start AND #createdAt < :end"),
+			ExpressionAttributeNames: map[string]string{
+				"#createdAt": "createdAt",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":start": &types.AttributeValueMemberS{Value: start.UTC().Format(time.RFC3339)},
+				":end":   &types.AttributeValueMemberS{Value: end.UTC().Format(time.RFC3339)},
+			},
+			ExclusiveStartKey: lastEvaluatedKey,
+		}
+
+		result, err := r.client.Scan(ctx, scanInput)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", r.tableName, err)
+		}
+
+		for _, item := range result.Items {
+			var run state.RunState
+			if err := attributevalue.UnmarshalMap(item, &run); err != nil {
+				continue // Skip invalid items
+			}
+			runs = append(runs, run)
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	sortRuns(runs)
+	return runs, nil
+}
+
+// ObjectReader is the subset of the S3 client S3Reader needs
+type ObjectReader interface {
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+}
+
+// S3Reader reads run summaries written by S3Archiver
+type S3Reader struct {
+	client ObjectReader
+	bucket string
+	prefix string
+}
+
+// RunsBetween lists each day's archive prefix in [start, end) and reads its summaries
+func (r *S3Reader) RunsBetween(ctx context.Context, start, end time.Time) ([]state.RunState, error) {
+	var runs []state.RunState
+	for day := start.UTC().Truncate(24 * time.Hour); day.Before(end); day = day.AddDate(0, 0, 1) {
+		keys, err := r.listDay(ctx, day)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			run, err := r.readRun(ctx, key)
+			if err != nil {
+				return nil, err
+			}
+			if !run.CreatedAt.Before(start) && run.CreatedAt.Before(end) {
+				runs = append(runs, *run)
+			}
+		}
+	}
+
+	sortRuns(runs)
+	return runs, nil
+}
+
+// listDay returns the keys of every summary archived under day
+func (r *S3Reader) listDay(ctx context.Context, day time.Time) ([]string, error) {
+	prefix := path.Join(r.prefix, day.Format("2006/01/02")) + "/"
+
+	var keys []string
+	paginator := s3.NewListObjectsV2Paginator(r.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(r.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list s3://%s/%s: %w", r.bucket, prefix, err)
+		}
+		for _, object := range page.Contents {
+			if key := aws.ToString(object.Key); strings.HasSuffix(key, ".json") {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys, nil
+}
+
+// readRun reads one archived summary
+func (r *S3Reader) readRun(ctx context.Context, key string) (*state.RunState, error) {
+	object, err := r.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(r.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read s3://%s/%s: %w", r.bucket, key, err)
+	}
+	defer object.Body.Close()
+
+	data, err := io.ReadAll(object.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read s3://%s/%s: %w", r.bucket, key, err)
+	}
+
+	var run state.RunState
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("invalid run summary s3://%s/%s: %w", r.bucket, key, err)
+	}
+	return &run, nil
+}
+
+// sortRuns orders runs oldest first
+func sortRuns(runs []state.RunState) {
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].CreatedAt.Before(runs[j].CreatedAt)
+	})
+}
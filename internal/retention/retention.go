@@ -0,0 +1,166 @@
+// Package retention configures how long each table keeps its items and whether run
+// summaries are archived before DynamoDB's TTL deletes them
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// ParameterName holds the JSON retention policy; when it is absent the default TTLs apply
+// and nothing is archived
+const ParameterName = "/hourstats/settings/retention"
+
+// Policy is the retention configuration. TTLs are Go durations, and may also be given
+// in whole days with a "d" suffix.
+//
+//	{
+//	  "state": "3d",
+//	  "sentimentHistory": "45d",
+//	  "dailySentiment": "1095d",
+//	  "archive": {"bucket": "hourstats-backups", "prefix": "run-archive"}
+//	}
+type Policy struct {
+	// StateTTL applies to run states and post batches in hourstats-state
+	StateTTL time.Duration
+	// SentimentHistoryTTL applies to hourstats-sentiment-history
+	SentimentHistoryTTL time.Duration
+	// DailySentimentTTL applies to hourstats-daily-sentiment
+	DailySentimentTTL time.Duration
+	// Archive is where run summaries are copied before they expire
+	Archive ArchiveConfig
+}
+
+// ArchiveConfig names the long-term destination for run summaries; set Table or Bucket
+type ArchiveConfig struct {
+	Table  string `json:"table,omitempty"`
+	Bucket string `json:"bucket,omitempty"`
+	// Prefix is the S3 key prefix, defaulting to DefaultArchivePrefix
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// DefaultArchivePrefix is the S3 prefix run summaries are archived under when none is given
+const DefaultArchivePrefix = "run-archive"
+
+// Enabled reports whether run summaries should be archived
+func (a ArchiveConfig) Enabled() bool {
+	return a.Table != "" || a.Bucket != ""
+}
+
+// policyJSON is the SSM representation of Policy
+type policyJSON struct {
+	State            string        `json:"state,omitempty"`
+	SentimentHistory string        `json:"sentimentHistory,omitempty"`
+	DailySentiment   string        `json:"dailySentiment,omitempty"`
+	Archive          ArchiveConfig `json:"archive"`
+}
+
+// Default returns the TTLs the tables have always used, with archival disabled
+func Default() Policy {
+	return Policy{
+		StateTTL:            state.DefaultStateTTL,
+		SentimentHistoryTTL: state.DefaultSentimentHistoryTTL,
+		DailySentimentTTL:   state.DefaultDailySentimentTTL,
+	}
+}
+
+// ParameterGetter is the subset of the SSM client Load needs
+type ParameterGetter interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Load reads the retention policy from SSM. A missing or empty parameter returns
+// the default policy.
+func Load(ctx context.Context, ssmClient ParameterGetter) (Policy, error) {
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ParameterName),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return Default(), nil
+		}
+		return Default(), fmt.Errorf("failed to get %s: %w", ParameterName, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return Default(), nil
+	}
+	return Parse(*result.Parameter.Value)
+}
+
+// Parse decodes and validates a JSON retention policy; omitted TTLs keep their defaults
+func Parse(value string) (Policy, error) {
+	policy := Default()
+	if strings.TrimSpace(value) == "" {
+		return policy, nil
+	}
+
+	var raw policyJSON
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		return Default(), fmt.Errorf("invalid retention policy JSON: %w", err)
+	}
+
+	fields := []struct {
+		name   string
+		value  string
+		target *time.Duration
+	}{
+		{"state", raw.State, &policy.StateTTL},
+		{"sentimentHistory", raw.SentimentHistory, &policy.SentimentHistoryTTL},
+		{"dailySentiment", raw.DailySentiment, &policy.DailySentimentTTL},
+	}
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		ttl, err := parseTTL(field.value)
+		if err != nil {
+			return Default(), fmt.Errorf("invalid %s TTL: %w", field.name, err)
+		}
+		*field.target = ttl
+	}
+
+	if raw.Archive.Table != "" && raw.Archive.Bucket != "" {
+		return Default(), fmt.Errorf("archive table and bucket cannot both be set")
+	}
+	policy.Archive = raw.Archive
+	if policy.Archive.Bucket != "" && policy.Archive.Prefix == "" {
+		policy.Archive.Prefix = DefaultArchivePrefix
+	}
+
+	return policy, nil
+}
+
+// parseTTL accepts a Go duration ("36h") or a whole number of days ("14d")
+func parseTTL(value string) (time.Duration, error) {
+	var ttl time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a whole number of days", value)
+		}
+		ttl = time.Duration(n) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a duration: %w", value, err)
+		}
+		ttl = parsed
+	}
+
+	if ttl < time.Hour {
+		return 0, fmt.Errorf("%q is shorter than one hour", value)
+	}
+	return ttl, nil
+}
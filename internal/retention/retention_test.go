@@ -0,0 +1,187 @@
+package retention
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func TestParseDefaults(t *testing.T) {
+	policy, err := Parse("")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if policy != Default() {
+		t.Errorf("Parse(\"\") = %+v, want defaults", policy)
+	}
+	if policy.Archive.Enabled() {
+		t.Error("archival should be disabled by default")
+	}
+}
+
+func TestParseTTLs(t *testing.T) {
+	policy, err := Parse(`{"state": "36h", "sentimentHistory": "45d", "archive": {"bucket": "hourstats-backups"}}`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if policy.StateTTL != 36*time.Hour {
+		t.Errorf("StateTTL = %v, want 36h", policy.StateTTL)
+	}
+	if policy.SentimentHistoryTTL != 45*24*time.Hour {
+		t.Errorf("SentimentHistoryTTL = %v, want 45 days", policy.SentimentHistoryTTL)
+	}
+	if policy.DailySentimentTTL != state.DefaultDailySentimentTTL {
+		t.Errorf("DailySentimentTTL = %v, want the default", policy.DailySentimentTTL)
+	}
+	if !policy.Archive.Enabled() || policy.Archive.Prefix != DefaultArchivePrefix {
+		t.Errorf("Archive = %+v, want bucket with the default prefix", policy.Archive)
+	}
+}
+
+func TestParseRejectsInvalidPolicies(t *testing.T) {
+	tests := map[string]string{
+		"bad json":     `{"state":`,
+		"bad duration": `{"state": "two days"}`,
+		"bad days":     `{"dailySentiment": "1.5d"}`,
+		"too short":    `{"sentimentHistory": "10m"}`,
+		"two archives": `{"archive": {"table": "hourstats-run-archive", "bucket": "hourstats-backups"}}`,
+	}
+	for name, value := range tests {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("%s: Parse(%s) expected an error", name, value)
+		}
+	}
+}
+
+type fakeObjectPutter struct {
+	keys   []string
+	bodies []string
+}
+
+func (f *fakeObjectPutter) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	body, _ := io.ReadAll(params.Body)
+	f.keys = append(f.keys, *params.Key)
+	f.bodies = append(f.bodies, string(body))
+	return &s3.PutObjectOutput{}, nil
+}
+
+func TestS3ArchiverClearsTTL(t *testing.T) {
+	putter := &fakeObjectPutter{}
+	archiver := &S3Archiver{client: putter, bucket: "hourstats-backups", prefix: DefaultArchivePrefix}
+	run := state.RunState{
+		RunID:     "run-1",
+		CreatedAt: time.Date(2025, 1, 5, 23, 30, 0, 0, time.UTC),
+		TTL:       1736300000,
+	}
+
+	if err := archiver.ArchiveRun(context.Background(), run); err != nil {
+		t.Fatalf("ArchiveRun() error = %v", err)
+	}
+	if len(putter.keys) != 1 || putter.keys[0] != "run-archive/2025/01/05/run-1.json" {
+		t.Fatalf("unexpected keys %v", putter.keys)
+	}
+	if !strings.Contains(putter.bodies[0], `"ttl": 0`) {
+		t.Errorf("archived summary should not carry the TTL: %s", putter.bodies[0])
+	}
+}
+
+type fakeItemPutter struct {
+	items []*dynamodb.PutItemInput
+	err   error
+}
+
+func (f *fakeItemPutter) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.items = append(f.items, params)
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func TestTableArchiverDropsTTLAttribute(t *testing.T) {
+	putter := &fakeItemPutter{}
+	archiver := &TableArchiver{client: putter, tableName: "hourstats-run-archive"}
+
+	archived, err := ArchiveRuns(context.Background(), archiver, []state.RunState{{RunID: "run-1", TTL: 1736300000}, {RunID: "run-2"}})
+	if err != nil || archived != 2 {
+		t.Fatalf("ArchiveRuns() = %d, %v; want 2, nil", archived, err)
+	}
+	if _, ok := putter.items[0].Item["ttl"]; ok {
+		t.Error("archived item should not have a ttl attribute")
+	}
+	if _, ok := putter.items[0].Item["runId"]; !ok {
+		t.Error("archived item should keep its runId key")
+	}
+}
+
+func TestArchiveRunsReportsFailures(t *testing.T) {
+	archiver := &TableArchiver{client: &fakeItemPutter{err: errors.New("throttled")}, tableName: "hourstats-run-archive"}
+
+	archived, err := ArchiveRuns(context.Background(), archiver, []state.RunState{{RunID: "run-1"}})
+	if archived != 0 || err == nil || !strings.Contains(err.Error(), "run-1") {
+		t.Errorf("ArchiveRuns() = %d, %v; want 0 and an error naming run-1", archived, err)
+	}
+}
+
+// fakeBucket stores objects in memory for archiver and reader round trips
+type fakeBucket struct {
+	objects map[string][]byte
+}
+
+func (f *fakeBucket) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	body, _ := io.ReadAll(params.Body)
+	f.objects[*params.Key] = body
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (f *fakeBucket) ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	output := &s3.ListObjectsV2Output{}
+	for key := range f.objects {
+		if strings.HasPrefix(key, *params.Prefix) {
+			output.Contents = append(output.Contents, s3types.Object{Key: aws.String(key)})
+		}
+	}
+	return output, nil
+}
+
+func (f *fakeBucket) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(f.objects[*params.Key]))}, nil
+}
+
+func TestS3ReaderReadsArchivedRuns(t *testing.T) {
+	bucket := &fakeBucket{objects: make(map[string][]byte)}
+	archiver := &S3Archiver{client: bucket, bucket: "hourstats-backups", prefix: DefaultArchivePrefix}
+	day := time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)
+	for _, run := range []state.RunState{
+		{RunID: "run-late", CreatedAt: day.Add(23 * time.Hour), OverallSentiment: "positive"},
+		{RunID: "run-early", CreatedAt: day.Add(time.Hour)},
+		{RunID: "run-before", CreatedAt: day.Add(-time.Hour)},
+		{RunID: "run-after", CreatedAt: day.Add(25 * time.Hour)},
+	} {
+		if err := archiver.ArchiveRun(context.Background(), run); err != nil {
+			t.Fatalf("ArchiveRun() error = %v", err)
+		}
+	}
+
+	reader := &S3Reader{client: bucket, bucket: "hourstats-backups", prefix: DefaultArchivePrefix}
+	runs, err := reader.RunsBetween(context.Background(), day, day.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("RunsBetween() error = %v", err)
+	}
+	if len(runs) != 2 || runs[0].RunID != "run-early" || runs[1].RunID != "run-late" {
+		t.Fatalf("expected the day's two runs oldest first, got %+v", runs)
+	}
+	if runs[1].OverallSentiment != "positive" {
+		t.Errorf("expected the summary fields to round trip, got %+v", runs[1])
+	}
+}
@@ -0,0 +1,226 @@
+// Package runreport is the JSON report of one run: what was fetched, how it was analyzed
+// and what was posted. The processor writes one to S3 for every run, for dashboards,
+// exports and debugging, and cmd/test-sentiment-analysis writes the same report for dry runs.
+package runreport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// Prefix is the S3 key prefix reports are written under
+const Prefix = "reports"
+
+// BlueskyPostLimit is the most characters a Bluesky post may have
+const BlueskyPostLimit = 300
+
+// TimeBuckets is how many slices of the window reports count posts in
+const TimeBuckets = 6
+
+const (
+	// samplesPerBucket is how many post URIs each time bucket lists
+	samplesPerBucket = 3
+	// textPreviewLength is how many characters of a sample post's text are shown
+	textPreviewLength = 100
+	// closeToLimitMargin is how few remaining characters count as close to the limit
+	closeToLimitMargin = 50
+)
+
+// Report is one run's report. Fields that only the processor knows, such as the run's
+// status, are empty in dry runs.
+type Report struct {
+	RunID                   string `json:"runId"`
+	Timestamp               string `json:"timestamp"`
+	AnalysisIntervalMinutes int    `json:"analysisIntervalMinutes"`
+	CutoffTime              string `json:"cutoffTime"`
+	// CurrentTime is the end of the analysis window
+	CurrentTime string `json:"currentTime"`
+
+	// Status and SkipReason are the run's final state, e.g. "completed" or "skipped"
+	Status     string `json:"status,omitempty"`
+	SkipReason string `json:"skipReason,omitempty"`
+	Error      string `json:"error,omitempty"`
+	ChannelID  string `json:"channelId,omitempty"`
+	// PostURI is the summary post, empty when nothing was posted
+	PostURI string `json:"postUri,omitempty"`
+
+	FetchStats        FetchStats        `json:"fetchStats"`
+	ProcessingStats   ProcessingStats   `json:"processingStats"`
+	SentimentAnalysis SentimentAnalysis `json:"sentimentAnalysis"`
+	GeneratedPost     string            `json:"generatedPost"`
+	PostStatistics    PostStatistics    `json:"postStatistics"`
+	// SamplePosts are the run's top posts
+	SamplePosts []SamplePost `json:"samplePosts"`
+
+	Topics   []state.TopicSentiment `json:"topics,omitempty"`
+	Domains  []state.DomainShare    `json:"domains,omitempty"`
+	Emotions *state.Emotions        `json:"emotions,omitempty"`
+}
+
+// FetchStats counts posts through each filter, in the order they were applied
+type FetchStats struct {
+	TotalAPICalls           int                      `json:"totalApiCalls,omitempty"`
+	TotalPostsFromAPI       int                      `json:"totalPostsFromApi"`
+	PostsAfterTimeFilter    int                      `json:"postsAfterTimeFilter"`
+	PostsAfterDeduplication int                      `json:"postsAfterDeduplication"`
+	PostsAfterBlocklist     int                      `json:"postsAfterBlocklist,omitempty"`
+	CoveragePercent         float64                  `json:"coveragePercent,omitempty"`
+	TimeDistribution        []TimeDistributionBucket `json:"timeDistribution"`
+}
+
+// TimeDistributionBucket counts the posts created in one slice of the window
+type TimeDistributionBucket struct {
+	BucketStart string   `json:"bucketStart"`
+	BucketEnd   string   `json:"bucketEnd"`
+	PostCount   int      `json:"postCount"`
+	SamplePosts []string `json:"samplePosts"`
+}
+
+// ProcessingStats describes the analysis
+type ProcessingStats struct {
+	PostsAnalyzed       int            `json:"postsAnalyzed"`
+	TopPostsSelected    int            `json:"topPostsSelected"`
+	DuplicatesRemoved   int            `json:"duplicatesRemoved"`
+	Sampled             bool           `json:"sampled,omitempty"`
+	BlockedPosts        map[string]int `json:"blockedPosts,omitempty"`
+	ContentWarningPosts int            `json:"contentWarningPosts,omitempty"`
+}
+
+// SentimentAnalysis is the run's overall sentiment and how the posts split
+type SentimentAnalysis struct {
+	OverallSentiment     string  `json:"overallSentiment"`
+	NetSentimentPercent  float64 `json:"netSentimentPercent"`
+	AverageCompoundScore float64 `json:"averageCompoundScore"`
+	PositiveCount        int     `json:"positiveCount"`
+	NeutralCount         int     `json:"neutralCount"`
+	NegativeCount        int     `json:"negativeCount"`
+}
+
+// PostStatistics measures the generated post against Bluesky's limit
+type PostStatistics struct {
+	CharacterCount int    `json:"characterCount"`
+	BlueskyLimit   int    `json:"blueskyLimit"`
+	Remaining      int    `json:"remaining"`
+	Status         string `json:"status"`
+}
+
+// SamplePost is a post shown in the report
+type SamplePost struct {
+	Author          string  `json:"author"`
+	URI             string  `json:"uri"`
+	CreatedAt       string  `json:"createdAt"`
+	Likes           int     `json:"likes"`
+	Reposts         int     `json:"reposts"`
+	Replies         int     `json:"replies"`
+	EngagementScore float64 `json:"engagementScore"`
+	Sentiment       string  `json:"sentiment"`
+	TextPreview     string  `json:"textPreview"`
+}
+
+// PostTime is a post's URI and creation time, RFC 3339, for bucketing
+type PostTime struct {
+	URI       string
+	CreatedAt string
+}
+
+// NewPostStatistics measures text against BlueskyPostLimit
+func NewPostStatistics(text string) PostStatistics {
+	count := len([]rune(text))
+	remaining := BlueskyPostLimit - count
+
+	status := "WITHIN_LIMIT"
+	if remaining < 0 {
+		status = "EXCEEDS_LIMIT"
+	} else if remaining < closeToLimitMargin {
+		status = "CLOSE_TO_LIMIT"
+	}
+
+	return PostStatistics{
+		CharacterCount: count,
+		BlueskyLimit:   BlueskyPostLimit,
+		Remaining:      remaining,
+		Status:         status,
+	}
+}
+
+// TextPreview shortens text to its first 100 characters
+func TextPreview(text string) string {
+	runes := []rune(text)
+	if len(runes) <= textPreviewLength {
+		return text
+	}
+	return string(runes[:textPreviewLength]) + "..."
+}
+
+// TimeDistribution splits the window from start to end into buckets and counts the posts
+// created in each; the last bucket also takes posts created at end
+func TimeDistribution(posts []PostTime, start, end time.Time, buckets int) []TimeDistributionBucket {
+	if buckets < 1 || !end.After(start) {
+		return nil
+	}
+
+	width := end.Sub(start) / time.Duration(buckets)
+	distribution := make([]TimeDistributionBucket, buckets)
+	for i := range distribution {
+		bucketEnd := start.Add(time.Duration(i+1) * width)
+		if i == buckets-1 {
+			bucketEnd = end
+		}
+		distribution[i] = TimeDistributionBucket{
+			BucketStart: start.Add(time.Duration(i) * width).UTC().Format(time.RFC3339),
+			BucketEnd:   bucketEnd.UTC().Format(time.RFC3339),
+		}
+	}
+
+	for _, post := range posts {
+		createdAt, err := time.Parse(time.RFC3339, post.CreatedAt)
+		if err != nil || createdAt.Before(start) || createdAt.After(end) {
+			continue
+		}
+		i := min(int(createdAt.Sub(start)/width), buckets-1)
+		distribution[i].PostCount++
+		if len(distribution[i].SamplePosts) < samplesPerBucket {
+			distribution[i].SamplePosts = append(distribution[i].SamplePosts, post.URI)
+		}
+	}
+	return distribution
+}
+
+// Key is the S3 key of a run's report, under the day the run was created,
+// e.g. reports/2025/01/05/<runID>.json
+func Key(runID string, createdAt time.Time) string {
+	return path.Join(Prefix, createdAt.UTC().Format("2006/01/02"), runID+".json")
+}
+
+// ObjectPutter is the subset of the S3 client Publish needs
+type ObjectPutter interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// Publish writes the report to the bucket under key, replacing any earlier report for the run
+func Publish(ctx context.Context, client ObjectPutter, bucket, key string, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run report: %w", err)
+	}
+
+	_, err = client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/json"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write run report to s3://%s/%s: %w", bucket, key, err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package runreport
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+type fakePutter struct {
+	input *s3.PutObjectInput
+	body  []byte
+}
+
+func (p *fakePutter) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	p.input = params
+	p.body, _ = io.ReadAll(params.Body)
+	return &s3.PutObjectOutput{}, nil
+}
+
+func TestNewPostStatistics(t *testing.T) {
+	for text, want := range map[string]string{
+		"short":                  "WITHIN_LIMIT",
+		strings.Repeat("é", 260): "CLOSE_TO_LIMIT",
+		strings.Repeat("a", 301): "EXCEEDS_LIMIT",
+	} {
+		stats := NewPostStatistics(text)
+		if stats.Status != want || stats.Remaining != BlueskyPostLimit-len([]rune(text)) {
+			t.Errorf("NewPostStatistics(%d chars) = %+v, want %s", len([]rune(text)), stats, want)
+		}
+	}
+}
+
+func TestTextPreview(t *testing.T) {
+	if preview := TextPreview("short"); preview != "short" {
+		t.Errorf("TextPreview() = %q, want the text unchanged", preview)
+	}
+	if preview := TextPreview(strings.Repeat("é", 150)); preview != strings.Repeat("é", 100)+"..." {
+		t.Errorf("TextPreview() = %q, want 100 characters and an ellipsis", preview)
+	}
+}
+
+func TestTimeDistribution(t *testing.T) {
+	start := time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC)
+	end := start.Add(30 * time.Minute)
+	posts := []PostTime{
+		{URI: "a", CreatedAt: "2025-01-05T12:01:00Z"},
+		{URI: "b", CreatedAt: "2025-01-05T12:04:59Z"},
+		{URI: "c", CreatedAt: "2025-01-05T12:29:00Z"},
+		{URI: "d", CreatedAt: "2025-01-05T12:30:00Z"},
+		{URI: "early", CreatedAt: "2025-01-05T11:59:00Z"},
+		{URI: "bad", CreatedAt: "yesterday"},
+	}
+
+	buckets := TimeDistribution(posts, start, end, TimeBuckets)
+	if len(buckets) != TimeBuckets {
+		t.Fatalf("got %d buckets, want %d", len(buckets), TimeBuckets)
+	}
+	if buckets[0].PostCount != 2 || buckets[5].PostCount != 2 || buckets[5].SamplePosts[1] != "d" {
+		t.Errorf("Unexpected buckets %+v", buckets)
+	}
+	if buckets[1].BucketStart != "2025-01-05T12:05:00Z" || buckets[5].BucketEnd != "2025-01-05T12:30:00Z" {
+		t.Errorf("Unexpected bucket bounds %+v", buckets)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	createdAt := time.Date(2025, 1, 5, 23, 30, 0, 0, time.FixedZone("AEDT", 11*3600))
+	key := Key("run-1", createdAt)
+	if key != "reports/2025/01/05/run-1.json" {
+		t.Errorf("Key() = %q, want the run's UTC day", key)
+	}
+
+	putter := &fakePutter{}
+	report := &Report{RunID: "run-1", Status: "completed", SentimentAnalysis: SentimentAnalysis{OverallSentiment: "positive"}}
+	if err := Publish(context.Background(), putter, "hourstats-reports", key, report); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if aws.ToString(putter.input.Bucket) != "hourstats-reports" || aws.ToString(putter.input.Key) != key {
+		t.Errorf("Published to s3://%s/%s", aws.ToString(putter.input.Bucket), aws.ToString(putter.input.Key))
+	}
+
+	var published Report
+	if err := json.Unmarshal(putter.body, &published); err != nil || published.RunID != "run-1" || published.SentimentAnalysis.OverallSentiment != "positive" {
+		t.Errorf("Published %s, %v", putter.body, err)
+	}
+}
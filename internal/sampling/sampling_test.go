@@ -0,0 +1,87 @@
+package sampling
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	settings, err := Parse(`{}`)
+	if err != nil || settings.Threshold != DefaultThreshold || settings.Size != DefaultSize {
+		t.Errorf("Expected defaults, got %+v, %v", settings, err)
+	}
+	if settings, err := Parse(""); err != nil || settings != nil {
+		t.Errorf("Parse(empty) = %+v, %v; want nil", settings, err)
+	}
+	for name, value := range map[string]string{
+		"size over threshold": `{"threshold": 1000, "size": 5000}`,
+		"negative size":       `{"size": -1}`,
+		"invalid JSON":        `{"size": `,
+	} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	var disabled *Settings
+	if disabled.Applies(1e6) || settings.Applies(DefaultThreshold) || !settings.Applies(DefaultThreshold+1) {
+		t.Error("Expected sampling only above the threshold")
+	}
+}
+
+func TestReservoir(t *testing.T) {
+	items := make([]int, 1000)
+	for i := range items {
+		items[i] = i
+	}
+
+	sample := Reservoir(items, 100, Seed("run-1"))
+	if len(sample) != 100 {
+		t.Fatalf("Expected 100 items, got %d", len(sample))
+	}
+	for i := 1; i < len(sample); i++ {
+		if sample[i] <= sample[i-1] {
+			t.Fatalf("Expected distinct items in their original order, got %v", sample)
+		}
+	}
+
+	again := Reservoir(items, 100, Seed("run-1"))
+	other := Reservoir(items, 100, Seed("run-2"))
+	same, differs := true, false
+	for i := range sample {
+		same = same && sample[i] == again[i]
+		differs = differs || sample[i] != other[i]
+	}
+	if !same || !differs {
+		t.Error("Expected the same seed to pick the same items and another seed different ones")
+	}
+
+	if got := Reservoir(items[:10], 100, 1); len(got) != 10 {
+		t.Errorf("Expected every item when the sample is larger, got %d", len(got))
+	}
+}
+
+func TestEstimate95(t *testing.T) {
+	// Scores with a known mean of 10 and standard deviation near 50, like net sentiment
+	rng := rand.New(rand.NewSource(1))
+	population := make([]float64, 200000)
+	for i := range population {
+		population[i] = 10 + rng.NormFloat64()*50
+	}
+
+	estimate := Estimate95(Reservoir(population, DefaultSize, Seed("run")), len(population))
+	if estimate.SampleSize != DefaultSize || estimate.Population != len(population) {
+		t.Errorf("Unexpected sizes %+v", estimate)
+	}
+	if estimate.Margin < 0.6 || estimate.Margin > 0.7 {
+		t.Errorf("Expected a margin near ±0.66, got %.3f", estimate.Margin)
+	}
+	if math.Abs(estimate.Mean-10) > 2*estimate.Margin {
+		t.Errorf("Expected the mean near 10, got %.2f ± %.2f", estimate.Mean, estimate.Margin)
+	}
+
+	if full := Estimate95([]float64{1, 2, 3}, 3); full.Margin != 0 || full.Mean != 2 {
+		t.Errorf("Expected no margin when every item is sampled, got %+v", full)
+	}
+}
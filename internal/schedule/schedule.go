@@ -0,0 +1,197 @@
+// Package schedule decides whether a poster may publish right now, based on
+// optional rules stored in SSM (quiet hours and allowed weekdays)
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// ParameterName holds the JSON posting schedule; when it is absent every post is allowed
+const ParameterName = "/hourstats/settings/posting_schedule"
+
+// Posters that consult the schedule, used as keys in Rules.Posters
+const (
+	PosterSummary   = "summary"
+	PosterSparkline = "sparkline"
+	PosterYearly    = "yearly"
+	PosterMonthly   = "monthly"
+	PosterQuarterly = "quarterly"
+	PosterDelta     = "delta"
+)
+
+// Rules is the posting schedule. All times are UTC.
+//
+//	{
+//	  "quietHours": ["02:00-06:00"],
+//	  "posters": {"sparkline": {"days": ["Sun"]}}
+//	}
+type Rules struct {
+	// QuietHours apply to every poster, as "HH:MM-HH:MM" ranges that may wrap midnight
+	QuietHours []string `json:"quietHours,omitempty"`
+	// Posters adds per-poster restrictions on top of the global quiet hours
+	Posters map[string]PosterRules `json:"posters,omitempty"`
+}
+
+// PosterRules restricts a single poster
+type PosterRules struct {
+	QuietHours []string `json:"quietHours,omitempty"`
+	// Days lists the weekdays the poster may post on ("Sun", "Mon", ...); empty means every day
+	Days []string `json:"days,omitempty"`
+}
+
+// quietRange is a parsed "HH:MM-HH:MM" range in minutes after midnight, end exclusive
+type quietRange struct {
+	label      string
+	start, end int
+}
+
+func (q quietRange) contains(minute int) bool {
+	if q.start <= q.end {
+		return minute >= q.start && minute < q.end
+	}
+	// Wraps midnight, e.g. 22:00-02:00
+	return minute >= q.start || minute < q.end
+}
+
+// ParameterGetter is the subset of the SSM client Load needs
+type ParameterGetter interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Load reads the posting schedule from SSM. A missing or empty parameter returns
+// nil rules, which allow everything.
+func Load(ctx context.Context, ssmClient ParameterGetter) (*Rules, error) {
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ParameterName),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %s: %w", ParameterName, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return nil, nil
+	}
+
+	return Parse(*result.Parameter.Value)
+}
+
+// Parse decodes and validates a JSON posting schedule
+func Parse(value string) (*Rules, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var rules Rules
+	if err := json.Unmarshal([]byte(value), &rules); err != nil {
+		return nil, fmt.Errorf("invalid posting schedule: %w", err)
+	}
+
+	if _, err := parseRanges(rules.QuietHours); err != nil {
+		return nil, err
+	}
+	for poster, posterRules := range rules.Posters {
+		if _, err := parseRanges(posterRules.QuietHours); err != nil {
+			return nil, fmt.Errorf("%s: %w", poster, err)
+		}
+		for _, day := range posterRules.Days {
+			if _, err := parseWeekday(day); err != nil {
+				return nil, fmt.Errorf("%s: %w", poster, err)
+			}
+		}
+	}
+
+	return &rules, nil
+}
+
+// Allows reports whether poster may post at t and, when it may not, why.
+// Nil rules allow everything.
+func (r *Rules) Allows(poster string, t time.Time) (bool, string) {
+	if r == nil {
+		return true, ""
+	}
+
+	t = t.UTC()
+	minute := t.Hour()*60 + t.Minute()
+
+	ranges, _ := parseRanges(r.QuietHours)
+	posterRules, hasPosterRules := r.Posters[poster]
+	if hasPosterRules {
+		posterRanges, _ := parseRanges(posterRules.QuietHours)
+		ranges = append(ranges, posterRanges...)
+	}
+	for _, q := range ranges {
+		if q.contains(minute) {
+			return false, fmt.Sprintf("quiet hours %s UTC", q.label)
+		}
+	}
+
+	if hasPosterRules && len(posterRules.Days) > 0 {
+		for _, day := range posterRules.Days {
+			if weekday, _ := parseWeekday(day); weekday == t.Weekday() {
+				return true, ""
+			}
+		}
+		return false, fmt.Sprintf("%s only posts on %s", poster, strings.Join(posterRules.Days, ", "))
+	}
+
+	return true, ""
+}
+
+func parseRanges(values []string) ([]quietRange, error) {
+	ranges := make([]quietRange, 0, len(values))
+	for _, value := range values {
+		startText, endText, ok := strings.Cut(value, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid quiet hours %q: want HH:MM-HH:MM", value)
+		}
+		start, err := parseClock(startText)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quiet hours %q: %w", value, err)
+		}
+		end, err := parseClock(endText)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quiet hours %q: %w", value, err)
+		}
+		if start == end {
+			return nil, fmt.Errorf("invalid quiet hours %q: start and end are equal", value)
+		}
+		ranges = append(ranges, quietRange{label: strings.TrimSpace(value), start: start, end: end})
+	}
+	return ranges, nil
+}
+
+// parseClock converts "HH:MM" to minutes after midnight; "24:00" is accepted as an end time
+func parseClock(value string) (int, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(strings.TrimSpace(value), "%d:%d", &hour, &minute); err != nil {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	if hour < 0 || minute < 0 || minute > 59 || hour > 24 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	return hour*60 + minute, nil
+}
+
+func parseWeekday(value string) (time.Weekday, error) {
+	name := strings.ToLower(strings.TrimSpace(value))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || name == full[:3] {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid day %q", value)
+}
@@ -0,0 +1,87 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAllowsQuietHours(t *testing.T) {
+	rules, err := Parse(`{"quietHours": ["02:00-06:00", "22:30-00:30"]}`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		at      string
+		allowed bool
+	}{
+		{"01:59", true},
+		{"02:00", false},
+		{"05:59", false},
+		{"06:00", true},
+		{"22:29", true},
+		{"23:45", false},
+		{"00:15", false},
+		{"00:30", true},
+	}
+	for _, tt := range tests {
+		at, _ := time.Parse("2006-01-02 15:04", "2026-03-04 "+tt.at)
+		allowed, reason := rules.Allows(PosterSummary, at)
+		if allowed != tt.allowed {
+			t.Errorf("Allows(%s) = %v (%s), want %v", tt.at, allowed, reason, tt.allowed)
+		}
+		if !allowed && !strings.Contains(reason, "quiet hours") {
+			t.Errorf("Allows(%s) reason = %q, want quiet hours", tt.at, reason)
+		}
+	}
+}
+
+func TestAllowsPosterDays(t *testing.T) {
+	rules, err := Parse(`{"posters": {"sparkline": {"days": ["Sun"]}, "yearly": {"quietHours": ["00:00-12:00"]}}}`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	sunday := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	monday := sunday.AddDate(0, 0, 1)
+
+	if allowed, _ := rules.Allows(PosterSparkline, sunday); !allowed {
+		t.Error("sparkline should post on Sunday")
+	}
+	if allowed, reason := rules.Allows(PosterSparkline, monday); allowed || !strings.Contains(reason, "Sun") {
+		t.Errorf("sparkline on Monday = %v (%q), want blocked", allowed, reason)
+	}
+	if allowed, _ := rules.Allows(PosterSummary, monday); !allowed {
+		t.Error("summary has no rules and should post")
+	}
+	if allowed, _ := rules.Allows(PosterYearly, monday); allowed {
+		t.Error("yearly should be in its own quiet hours at 09:00")
+	}
+}
+
+func TestAllowsNilRules(t *testing.T) {
+	var rules *Rules
+	if allowed, _ := rules.Allows(PosterSummary, time.Now()); !allowed {
+		t.Error("nil rules should allow posting")
+	}
+}
+
+func TestParseRejectsInvalidRules(t *testing.T) {
+	for _, value := range []string{
+		`{"quietHours": ["2am-6am"]}`,
+		`{"quietHours": ["02:00"]}`,
+		`{"quietHours": ["25:00-06:00"]}`,
+		`{"quietHours": ["06:00-06:00"]}`,
+		`{"posters": {"sparkline": {"days": ["Someday"]}}}`,
+		`not json`,
+	} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("Parse(%s) succeeded, want error", value)
+		}
+	}
+
+	if rules, err := Parse("  "); err != nil || rules != nil {
+		t.Errorf("Parse(empty) = %v, %v, want nil rules", rules, err)
+	}
+}
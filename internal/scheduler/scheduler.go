@@ -10,17 +10,28 @@ import (
 )
 
 type Scheduler struct {
-	client   *client.BlueskyClient
+	client client.Client
+	// poster posts the summaries: client, or the shadow account during a shadow dry run
+	poster   client.BskyPoster
 	analyzer *analyzer.SentimentAnalyzer
 	config   *config.Config
+	// previousCategory is the last analysis's category, which the thresholds' hysteresis
+	// favours
+	previousCategory string
 }
 
 func New(handle, password string, cfg *config.Config) *Scheduler {
 	blueskyClient := client.New(handle, password)
 	sentimentAnalyzer := analyzer.New()
 
+	var poster client.BskyPoster = blueskyClient
+	if cfg.Settings.DryRun.ShadowAccount() {
+		poster = client.New(cfg.Shadow.Handle, cfg.Shadow.Password)
+	}
+
 	return &Scheduler{
 		client:   blueskyClient,
+		poster:   poster,
 		analyzer: sentimentAnalyzer,
 		config:   cfg,
 	}
@@ -34,6 +45,13 @@ func (s *Scheduler) Start() error {
 
 	log.Println("Successfully authenticated with Bluesky")
 
+	if s.config.Settings.DryRun.ShadowAccount() {
+		if err := s.poster.Authenticate(); err != nil {
+			return err
+		}
+		log.Printf("Shadow dry run: posting as %s", s.config.Shadow.Handle)
+	}
+
 	// Start the hourly ticker
 	ticker := time.NewTicker(1 * time.Hour)
 	defer ticker.Stop()
@@ -77,13 +95,18 @@ func (s *Scheduler) runAnalysis() error {
 
 	// Calculate overall sentiment from all analyzed posts using compound scores
 	overallSentiment, netSentimentPercentage := s.CalculateOverallSentiment(analyzedPosts)
+	s.previousCategory = overallSentiment
 	totalPosts := len(analyzedPosts)
 
 	// Convert back to client posts for posting
 	clientTopPosts := s.convertToClientPosts(topPosts)
 
-	// Post the results
-	_, _, err = s.client.PostTrendingSummary(clientTopPosts, overallSentiment, s.config.Settings.AnalysisIntervalMinutes, totalPosts, netSentimentPercentage)
+	// Post the results, unless a dry run holds them back
+	if !s.config.Settings.DryRun.Posts() {
+		log.Printf("Dry run (%s): Skipping post to Bluesky", s.config.Settings.DryRun)
+		return nil
+	}
+	_, _, err = s.poster.PostTrendingSummary(clientTopPosts, overallSentiment, s.config.Settings.AnalysisIntervalMinutes, totalPosts, netSentimentPercentage)
 	if err != nil {
 		return err
 	}
@@ -139,14 +162,7 @@ func (s *Scheduler) CalculateOverallSentiment(posts []analyzer.AnalyzedPost) (st
 	averageCompoundScore := totalCompoundScore / float64(len(posts))
 
 	// Map compound score to category for backward compatibility
-	var sentimentCategory string
-	if averageCompoundScore >= 0.3 {
-		sentimentCategory = "positive"
-	} else if averageCompoundScore <= -0.3 {
-		sentimentCategory = "negative"
-	} else {
-		sentimentCategory = "neutral"
-	}
+	sentimentCategory := s.config.Settings.SentimentThresholds.Categorize(averageCompoundScore, s.previousCategory)
 
 	// Scale to percentage range for 100-word system
 	netSentimentPercentage := averageCompoundScore * 100.0
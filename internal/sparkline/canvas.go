@@ -0,0 +1,137 @@
+package sparkline
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"sync"
+
+	"github.com/fogleman/gg"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// embeddedFont parses the embedded Go Regular font once
+var embeddedFont = sync.OnceValues(func() (*truetype.Font, error) {
+	return truetype.Parse(goregular.TTF)
+})
+
+// canvas draws in chart coordinates onto a context scale times larger, so charts can be
+// rendered at 2x and downscaled for crisper lines and text. Coordinates, line widths and
+// font sizes are multiplied here rather than through the gg matrix, which would only
+// stretch glyph bitmaps instead of rasterizing them at the higher resolution.
+type canvas struct {
+	dc     *gg.Context
+	font   *truetype.Font
+	width  int
+	height int
+	scale  float64
+}
+
+// newCanvas creates a width x height canvas rendered at scale that draws text in font;
+// scales below 1 render at 1x
+func newCanvas(width, height int, scale float64, font *truetype.Font) *canvas {
+	if scale < 1 {
+		scale = 1
+	}
+	return &canvas{
+		dc:     gg.NewContext(int(float64(width)*scale), int(float64(height)*scale)),
+		font:   font,
+		width:  width,
+		height: height,
+		scale:  scale,
+	}
+}
+
+// loadFont parses the TrueType or OpenType font at path, or returns the embedded Go
+// Regular font when path is empty
+func loadFont(path string) (*truetype.Font, error) {
+	if path == "" {
+		regular, err := embeddedFont()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse embedded font: %w", err)
+		}
+		return regular, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chart font: %w", err)
+	}
+	font, err := truetype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse chart font %s: %w", path, err)
+	}
+	return font, nil
+}
+
+func (c *canvas) SetColor(col color.Color) { c.dc.SetColor(col) }
+func (c *canvas) Clear()                   { c.dc.Clear() }
+func (c *canvas) Stroke()                  { c.dc.Stroke() }
+func (c *canvas) Fill()                    { c.dc.Fill() }
+func (c *canvas) Push()                    { c.dc.Push() }
+func (c *canvas) Pop()                     { c.dc.Pop() }
+func (c *canvas) Rotate(angle float64)     { c.dc.Rotate(angle) }
+
+func (c *canvas) SetLineWidth(width float64) { c.dc.SetLineWidth(width * c.scale) }
+
+func (c *canvas) SetDash(dashes ...float64) {
+	scaled := make([]float64, len(dashes))
+	for i, dash := range dashes {
+		scaled[i] = dash * c.scale
+	}
+	c.dc.SetDash(scaled...)
+}
+
+func (c *canvas) Translate(x, y float64) { c.dc.Translate(x*c.scale, y*c.scale) }
+
+func (c *canvas) DrawLine(x1, y1, x2, y2 float64) {
+	c.dc.DrawLine(x1*c.scale, y1*c.scale, x2*c.scale, y2*c.scale)
+}
+
+func (c *canvas) DrawCircle(x, y, r float64) {
+	c.dc.DrawCircle(x*c.scale, y*c.scale, r*c.scale)
+}
+
+func (c *canvas) DrawRectangle(x, y, w, h float64) {
+	c.dc.DrawRectangle(x*c.scale, y*c.scale, w*c.scale, h*c.scale)
+}
+
+func (c *canvas) MoveTo(x, y float64) { c.dc.MoveTo(x*c.scale, y*c.scale) }
+func (c *canvas) LineTo(x, y float64) { c.dc.LineTo(x*c.scale, y*c.scale) }
+func (c *canvas) ClosePath()          { c.dc.ClosePath() }
+
+func (c *canvas) DrawStringAnchored(s string, x, y, ax, ay float64) {
+	c.dc.DrawStringAnchored(s, x*c.scale, y*c.scale, ax, ay)
+}
+
+// SetFontSize selects the canvas font at points, scaled to the render resolution
+func (c *canvas) SetFontSize(points float64) {
+	c.dc.SetFontFace(truetype.NewFace(c.font, &truetype.Options{Size: points * c.scale}))
+}
+
+// Image returns the chart at its nominal size, downscaled with a Catmull-Rom filter when
+// it was rendered at a higher resolution
+func (c *canvas) Image() image.Image {
+	rendered := c.dc.Image()
+	if c.scale == 1 {
+		return rendered
+	}
+
+	scaled := image.NewRGBA(image.Rect(0, 0, c.width, c.height))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), rendered, rendered.Bounds(), draw.Src, nil)
+	return scaled
+}
+
+// EncodePNG returns the chart as a PNG at its nominal size
+func (c *canvas) EncodePNG() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, c.Image()); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
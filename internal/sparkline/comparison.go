@@ -0,0 +1,178 @@
+package sparkline
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// Series is one network's sentiment on a comparison chart
+type Series struct {
+	Label  string
+	Color  color.RGBA
+	Points []state.SentimentDataPoint // oldest first
+}
+
+// ComparisonColors are the series colors in order, Bluesky first. They avoid the green,
+// red and gray of the sentiment colors so a line's color always means its network.
+var ComparisonColors = []color.RGBA{
+	{17, 133, 254, 255}, // Bluesky blue
+	{99, 100, 255, 255}, // Mastodon purple
+	{230, 140, 20, 255}, // Orange
+}
+
+// GenerateComparisonSparkline plots several networks' net sentiment on shared axes, each
+// line in its own color, with a legend naming each network and its latest value
+func (sg *SparklineGenerator) GenerateComparisonSparkline(series []Series) ([]byte, error) {
+	return sg.generateSeriesSparkline(series, sg.labels.ComparisonTitle)
+}
+
+// GenerateToxicitySparkline plots net sentiment beside the percentage of toxic posts,
+// from points that measured toxicity, so the two can be told apart on one chart
+func (sg *SparklineGenerator) GenerateToxicitySparkline(dataPoints []state.SentimentDataPoint) ([]byte, error) {
+	var toxic []state.SentimentDataPoint
+	for _, point := range dataPoints {
+		if point.ToxicityPercent != nil {
+			toxic = append(toxic, state.SentimentDataPoint{Timestamp: point.Timestamp, NetSentimentPercent: *point.ToxicityPercent})
+		}
+	}
+	if len(toxic) < 2 {
+		return nil, fmt.Errorf("not enough toxicity data points")
+	}
+	return sg.generateSeriesSparkline([]Series{
+		{Label: sg.labels.NetSentiment, Color: ComparisonColors[0], Points: dataPoints},
+		{Label: sg.labels.ToxicPosts, Color: ComparisonColors[2], Points: toxic},
+	}, sg.labels.ToxicityTitle)
+}
+
+// GenerateWeightingSparkline plots net sentiment with each post counting once beside net
+// sentiment weighted by engagement, from points that recorded the weighted figure
+func (sg *SparklineGenerator) GenerateWeightingSparkline(dataPoints []state.SentimentDataPoint) ([]byte, error) {
+	weighted := state.EngagementWeightedPoints(dataPoints)
+	if len(weighted) < 2 {
+		return nil, fmt.Errorf("not enough engagement-weighted data points")
+	}
+	return sg.generateSeriesSparkline([]Series{
+		{Label: sg.labels.PerPost, Color: ComparisonColors[0], Points: dataPoints},
+		{Label: sg.labels.Weighted, Color: ComparisonColors[2], Points: weighted},
+	}, sg.labels.WeightingTitle)
+}
+
+// GenerateRecomputeSparkline plots the live net sentiment beside a recomputed history series
+// named series, so the effect of an analyzer change can be checked before switching to it
+func (sg *SparklineGenerator) GenerateRecomputeSparkline(live, recomputed []state.SentimentDataPoint, series string) ([]byte, error) {
+	if len(recomputed) < 2 {
+		return nil, fmt.Errorf("not enough recomputed data points")
+	}
+	return sg.generateSeriesSparkline([]Series{
+		{Label: sg.labels.Live, Color: ComparisonColors[0], Points: live},
+		{Label: series, Color: ComparisonColors[2], Points: recomputed},
+	}, sg.labels.RecomputeTitle)
+}
+
+// generateSeriesSparkline plots each series as its own line on shared axes under title
+func (sg *SparklineGenerator) generateSeriesSparkline(series []Series, title string) ([]byte, error) {
+	var all []state.SentimentDataPoint
+	for _, s := range series {
+		all = append(all, s.Points...)
+	}
+	if len(series) == 0 || len(all) < 2 {
+		return nil, fmt.Errorf("not enough data points to chart")
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Timestamp.Before(all[j].Timestamp) })
+
+	font, err := loadFont(sg.config.FontPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart font: %w", err)
+	}
+
+	dc := newCanvas(sg.config.Width, sg.config.Height, sg.config.RenderScale, font)
+	dc.SetColor(sg.config.Background)
+	dc.Clear()
+
+	// Same drawing area as the weekly chart
+	drawX := float64(sg.config.Padding + 50)
+	drawY := float64(sg.config.Padding)
+	drawWidth := float64(sg.config.Width-sg.config.Padding) - drawX
+	drawHeight := float64(sg.config.Height-sg.config.Padding-20) - drawY
+
+	yRange := sg.calculateYRange(all)
+	sg.drawGrid(dc, drawX, drawY, drawWidth, drawHeight, yRange)
+	sg.drawNeutralZone(dc, drawX, drawY, drawWidth, drawHeight, yRange)
+
+	startTime := all[0].Timestamp
+	timeRange := all[len(all)-1].Timestamp.Sub(startTime).Seconds()
+	position := func(point state.SentimentDataPoint) (float64, float64) {
+		px := drawX
+		if timeRange > 0 {
+			px += point.Timestamp.Sub(startTime).Seconds() / timeRange * drawWidth
+		}
+		normalized := (point.NetSentimentPercent - yRange.Center) * yRange.Scale / 100.0
+		return px, drawY + drawHeight/2 - normalized*(drawHeight/2)
+	}
+
+	for _, s := range series {
+		dc.SetColor(s.Color)
+		dc.SetLineWidth(sg.config.LineWidth)
+		for i := 0; i+1 < len(s.Points); i++ {
+			x1, y1 := position(s.Points[i])
+			x2, y2 := position(s.Points[i+1])
+			dc.DrawLine(x1, y1, x2, y2)
+			dc.Stroke()
+		}
+		if len(s.Points) > 0 {
+			lastX, lastY := position(s.Points[len(s.Points)-1])
+			dc.DrawCircle(lastX, lastY, sg.config.LineWidth*1.5)
+			dc.Fill()
+		}
+	}
+
+	// Axis labels
+	dc.SetColor(sg.config.TextColor)
+	dc.SetFontSize(12)
+	for _, level := range []float64{yRange.Max, yRange.Center, yRange.Min} {
+		_, yPos := position(state.SentimentDataPoint{Timestamp: startTime, NetSentimentPercent: level})
+		dc.DrawStringAnchored(fmt.Sprintf("%.1f%%", level), drawX-15, yPos, 1, 0.5)
+	}
+	if timeRange >= 24*60*60 {
+		sg.drawDayMarkers(dc, all, drawX, drawY, drawWidth, drawHeight)
+	} else {
+		dc.SetColor(sg.config.TextColor)
+		dc.DrawStringAnchored(sg.labels.Clock(startTime), drawX, drawY+drawHeight+15, 0, 0)
+		dc.DrawStringAnchored(sg.labels.Clock(all[len(all)-1].Timestamp), drawX+drawWidth, drawY+drawHeight+15, 1, 0)
+	}
+
+	dc.SetColor(sg.config.TextColor)
+	dc.SetFontSize(14)
+	dc.DrawStringAnchored(title, drawX+drawWidth/2, drawY-10, 0.5, 0)
+
+	sg.drawLegend(dc, series, drawX+10, drawY+10)
+	sg.drawBrandingWatermark(dc, drawX, drawY, drawWidth, drawHeight)
+
+	return dc.EncodePNG()
+}
+
+// drawLegend lists each series with its color swatch and latest value, top left of the chart
+func (sg *SparklineGenerator) drawLegend(dc *canvas, series []Series, x, y float64) {
+	const rowHeight, swatch = 22.0, 14.0
+
+	dc.SetFontSize(13)
+	for i, s := range series {
+		rowY := y + float64(i)*rowHeight
+
+		dc.SetColor(s.Color)
+		dc.DrawRectangle(x, rowY, swatch, swatch)
+		dc.Fill()
+
+		label := s.Label
+		if len(s.Points) > 0 {
+			label = fmt.Sprintf("%s: %+.1f%%", s.Label, s.Points[len(s.Points)-1].NetSentimentPercent)
+		} else {
+			label += ": no data"
+		}
+		dc.SetColor(sg.config.TextColor)
+		dc.DrawStringAnchored(label, x+swatch+8, rowY+swatch/2, 0, 0.5)
+	}
+}
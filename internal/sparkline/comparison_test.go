@@ -0,0 +1,130 @@
+package sparkline
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func TestGenerateComparisonSparkline(t *testing.T) {
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	var bluesky, mastodon []state.SentimentDataPoint
+	for i := 0; i < 96; i++ {
+		at := start.Add(time.Duration(i) * time.Hour)
+		bluesky = append(bluesky, state.SentimentDataPoint{Timestamp: at, NetSentimentPercent: float64(i%20) - 5})
+		if i%2 == 0 {
+			mastodon = append(mastodon, state.SentimentDataPoint{Timestamp: at, NetSentimentPercent: float64(i%10) + 2, Network: "mastodon"})
+		}
+	}
+
+	generator := NewSparklineGenerator(nil)
+	imageData, err := generator.GenerateComparisonSparkline([]Series{
+		{Label: "Bluesky", Color: ComparisonColors[0], Points: bluesky},
+		{Label: "Mastodon (mastodon.social)", Color: ComparisonColors[1], Points: mastodon},
+	})
+	if err != nil {
+		t.Fatalf("GenerateComparisonSparkline() error = %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(imageData))
+	if err != nil {
+		t.Fatalf("Generated image is not a PNG: %v", err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != DefaultConfig().Width || bounds.Dy() != DefaultConfig().Height {
+		t.Errorf("Unexpected image size %v", bounds)
+	}
+
+	// A network with no data yet is still listed, so the chart never silently drops it
+	if _, err := generator.GenerateComparisonSparkline([]Series{
+		{Label: "Bluesky", Color: ComparisonColors[0], Points: bluesky},
+		{Label: "Mastodon", Color: ComparisonColors[1]},
+	}); err != nil {
+		t.Errorf("Expected a chart with an empty series, got %v", err)
+	}
+
+	if _, err := generator.GenerateComparisonSparkline([]Series{{Label: "Bluesky", Points: bluesky[:1]}}); err == nil {
+		t.Error("Expected an error with a single data point")
+	}
+}
+
+func TestGenerateToxicitySparkline(t *testing.T) {
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	var points []state.SentimentDataPoint
+	for i := 0; i < 48; i++ {
+		point := state.SentimentDataPoint{Timestamp: start.Add(time.Duration(i) * time.Hour), NetSentimentPercent: float64(i%15) - 5}
+		// Toxicity was only measured for the second day
+		if i >= 24 {
+			toxicity := float64(i % 7)
+			point.ToxicityPercent = &toxicity
+		}
+		points = append(points, point)
+	}
+
+	generator := NewSparklineGenerator(nil)
+	imageData, err := generator.GenerateToxicitySparkline(points)
+	if err != nil {
+		t.Fatalf("GenerateToxicitySparkline() error = %v", err)
+	}
+	if _, err := png.Decode(bytes.NewReader(imageData)); err != nil {
+		t.Fatalf("Generated image is not a PNG: %v", err)
+	}
+
+	if _, err := generator.GenerateToxicitySparkline(points[:24]); err == nil {
+		t.Error("Expected an error without toxicity data")
+	}
+}
+
+func TestGenerateWeightingSparkline(t *testing.T) {
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	var points []state.SentimentDataPoint
+	for i := 0; i < 48; i++ {
+		point := state.SentimentDataPoint{Timestamp: start.Add(time.Duration(i) * time.Hour), NetSentimentPercent: float64(i%15) - 5}
+		// Weighted sentiment was only recorded for the second day
+		if i >= 24 {
+			weighted := point.NetSentimentPercent + 3
+			point.WeightedNetSentimentPercent = &weighted
+		}
+		points = append(points, point)
+	}
+
+	generator := NewSparklineGenerator(nil)
+	imageData, err := generator.GenerateWeightingSparkline(points)
+	if err != nil {
+		t.Fatalf("GenerateWeightingSparkline() error = %v", err)
+	}
+	if _, err := png.Decode(bytes.NewReader(imageData)); err != nil {
+		t.Fatalf("Generated image is not a PNG: %v", err)
+	}
+
+	if _, err := generator.GenerateWeightingSparkline(points[:24]); err == nil {
+		t.Error("Expected an error without weighted data")
+	}
+}
+
+func TestGenerateRecomputeSparkline(t *testing.T) {
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	var live, recomputed []state.SentimentDataPoint
+	for i := 0; i < 48; i++ {
+		point := state.SentimentDataPoint{Timestamp: start.Add(time.Duration(i) * time.Hour), NetSentimentPercent: float64(i%15) - 5}
+		live = append(live, point)
+		point.NetSentimentPercent += 2
+		point.Series = "v2"
+		recomputed = append(recomputed, point)
+	}
+
+	generator := NewSparklineGenerator(nil)
+	imageData, err := generator.GenerateRecomputeSparkline(live, recomputed, "v2")
+	if err != nil {
+		t.Fatalf("GenerateRecomputeSparkline() error = %v", err)
+	}
+	if _, err := png.Decode(bytes.NewReader(imageData)); err != nil {
+		t.Fatalf("Generated image is not a PNG: %v", err)
+	}
+
+	if _, err := generator.GenerateRecomputeSparkline(live, recomputed[:1], "v2"); err == nil {
+		t.Error("Expected an error without recomputed data")
+	}
+}
@@ -0,0 +1,40 @@
+package sparkline
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// EmotionColors fill the emotion chart's bands: joy, anger, sadness and fear
+var EmotionColors = [4]color.RGBA{
+	{245, 190, 40, 255}, // Joy yellow
+	{210, 60, 50, 255},  // Anger red
+	{70, 110, 200, 255}, // Sadness blue
+	{130, 80, 160, 255}, // Fear purple
+}
+
+// GenerateEmotionSparkline draws each run's emotion breakdown as stacked areas from 0 to
+// 100%, joy at the bottom, using the points that recorded emotions
+func (sg *SparklineGenerator) GenerateEmotionSparkline(dataPoints []state.SentimentDataPoint) ([]byte, error) {
+	var points []state.SentimentDataPoint
+	for _, point := range dataPoints {
+		if point.Emotions != nil {
+			points = append(points, point)
+		}
+	}
+	if len(points) < 2 {
+		return nil, fmt.Errorf("not enough emotion data points")
+	}
+
+	return sg.generateStackedSparkline(stackedChart{
+		title:  sg.labels.EmotionTitle,
+		colors: EmotionColors[:],
+		names:  sg.labels.Emotions[:],
+		shares: func(point state.SentimentDataPoint) []float64 {
+			e := point.Emotions
+			return []float64{e.Joy, e.Anger, e.Sadness, e.Fear}
+		},
+	}, points)
+}
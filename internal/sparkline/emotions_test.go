@@ -0,0 +1,36 @@
+package sparkline
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func TestGenerateEmotionSparkline(t *testing.T) {
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	var points []state.SentimentDataPoint
+	for i := 0; i < 72; i++ {
+		point := state.SentimentDataPoint{Timestamp: start.Add(time.Duration(i) * time.Hour)}
+		// Runs before emotions were recorded have none
+		if i >= 12 {
+			joy := 0.3 + float64(i%5)/20
+			point.Emotions = &state.Emotions{Joy: joy, Anger: 0.2, Sadness: 0.3, Fear: 0.5 - joy + 0.2}
+		}
+		points = append(points, point)
+	}
+
+	imageData, err := NewSparklineGenerator(nil).GenerateEmotionSparkline(points)
+	if err != nil {
+		t.Fatalf("GenerateEmotionSparkline() error = %v", err)
+	}
+	if _, err := png.Decode(bytes.NewReader(imageData)); err != nil {
+		t.Fatalf("Generated image is not a PNG: %v", err)
+	}
+
+	if _, err := NewSparklineGenerator(nil).GenerateEmotionSparkline(points[:13]); err == nil {
+		t.Error("Expected an error with a single emotion data point")
+	}
+}
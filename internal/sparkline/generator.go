@@ -1,7 +1,6 @@
 package sparkline
 
 import (
-	"bytes"
 	"fmt"
 	"image/color"
 	"math"
@@ -9,7 +8,6 @@ import (
 	"time"
 
 	"github.com/christophergentle/hourstats-bsky/internal/state"
-	"github.com/fogleman/gg"
 )
 
 // SparklineConfig holds configuration for sparkline generation
@@ -25,6 +23,27 @@ type SparklineConfig struct {
 	NeutralLine  color.RGBA
 	GridColor    color.RGBA
 	TextColor    color.RGBA
+
+	// RenderScale renders the chart this many times larger, then downscales it for
+	// smoother lines and text; 1 (or 0) renders at the output size
+	RenderScale float64
+
+	// FontPath is a TrueType or OpenType font for chart text; empty uses the embedded Go
+	// Regular font. Localized labels need a font that covers their script.
+	FontPath string
+	// Labels are the month, weekday and caption text drawn on the chart; nil uses English
+	Labels *Labels
+
+	// ZeroLine emphasizes the 0% line so readers can tell positive from negative at a glance
+	ZeroLine bool
+	// StdDevBand shades one standard deviation either side of the weekly average, showing
+	// whether the latest value is unusual for the week
+	StdDevBand bool
+	BandColor  color.RGBA
+	// VolatilityBand shades each run's 24-hour volatility either side of the line, so choppy
+	// stretches stand out as a wider envelope; runs without a stored volatility are skipped
+	VolatilityBand  bool
+	VolatilityColor color.RGBA
 }
 
 // YRange represents the Y-axis range for the sparkline
@@ -78,23 +97,27 @@ func (sg *SparklineGenerator) calculateYRange(dataPoints []state.SentimentDataPo
 // DefaultConfig returns a default sparkline configuration
 func DefaultConfig() *SparklineConfig {
 	return &SparklineConfig{
-		Width:        1200,                           // Canvas 1200x800 (3:2 aspect ratio)
-		Height:       800,                            // Canvas 1200x800 (3:2 aspect ratio)
-		Padding:      80,                             // Adjusted padding for 1200x800 canvas
-		LineWidth:    3.0,                            // 50% of original 6.0
-		PointRadius:  0.8,                            // Reduced to 0.8 for very small dots
-		Background:   color.RGBA{248, 249, 250, 255}, // Light gray
-		PositiveLine: color.RGBA{40, 167, 69, 255},   // Green
-		NegativeLine: color.RGBA{220, 53, 69, 255},   // Red
-		NeutralLine:  color.RGBA{108, 117, 125, 255}, // Gray
-		GridColor:    color.RGBA{200, 200, 200, 255}, // Light gray
-		TextColor:    color.RGBA{33, 37, 41, 255},    // Dark gray
+		Width:           1200,                           // Canvas 1200x800 (3:2 aspect ratio)
+		Height:          800,                            // Canvas 1200x800 (3:2 aspect ratio)
+		Padding:         80,                             // Adjusted padding for 1200x800 canvas
+		LineWidth:       3.0,                            // 50% of original 6.0
+		PointRadius:     0.8,                            // Reduced to 0.8 for very small dots
+		Background:      color.RGBA{248, 249, 250, 255}, // Light gray
+		PositiveLine:    color.RGBA{40, 167, 69, 255},   // Green
+		NegativeLine:    color.RGBA{220, 53, 69, 255},   // Red
+		NeutralLine:     color.RGBA{108, 117, 125, 255}, // Gray
+		GridColor:       color.RGBA{200, 200, 200, 255}, // Light gray
+		TextColor:       color.RGBA{33, 37, 41, 255},    // Dark gray
+		RenderScale:     2,                              // Render at 2x for crisp text on high density screens
+		BandColor:       color.RGBA{0, 15, 30, 30},      // Faint blue (premultiplied alpha)
+		VolatilityColor: color.RGBA{30, 20, 0, 30},      // Faint amber (premultiplied alpha)
 	}
 }
 
 // SparklineGenerator generates sentiment sparkline images
 type SparklineGenerator struct {
 	config *SparklineConfig
+	labels *Labels
 }
 
 // NewSparklineGenerator creates a new sparkline generator
@@ -102,7 +125,14 @@ func NewSparklineGenerator(config *SparklineConfig) *SparklineGenerator {
 	if config == nil {
 		config = DefaultConfig()
 	}
-	return &SparklineGenerator{config: config}
+	return &SparklineGenerator{config: config, labels: labelsOrDefault(config.Labels)}
+}
+
+// WithVolatilityBand returns a copy of the generator that also draws the volatility band
+func (sg *SparklineGenerator) WithVolatilityBand() *SparklineGenerator {
+	config := *sg.config
+	config.VolatilityBand = true
+	return &SparklineGenerator{config: &config, labels: sg.labels}
 }
 
 // GenerateSentimentSparkline creates a PNG image of sentiment data over time
@@ -111,8 +141,13 @@ func (sg *SparklineGenerator) GenerateSentimentSparkline(dataPoints []state.Sent
 		return nil, fmt.Errorf("no data points provided")
 	}
 
+	font, err := loadFont(sg.config.FontPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart font: %w", err)
+	}
+
 	// Create image context
-	dc := gg.NewContext(sg.config.Width, sg.config.Height)
+	dc := newCanvas(sg.config.Width, sg.config.Height, sg.config.RenderScale, font)
 
 	// Fill background
 	dc.SetColor(sg.config.Background)
@@ -138,6 +173,9 @@ func (sg *SparklineGenerator) GenerateSentimentSparkline(dataPoints []state.Sent
 	// Draw neutral zone background (this will cover the center line in the neutral zone)
 	sg.drawNeutralZone(dc, drawX, drawY, drawWidth, drawHeight, yRange)
 
+	// Draw optional reference lines beneath the data
+	sg.drawReferenceLines(dc, dataPoints, drawX, drawY, drawWidth, drawHeight, yRange)
+
 	// Draw sentiment line
 	sg.drawSentimentLine(dc, dataPoints, drawX, drawY, drawWidth, drawHeight, yRange)
 
@@ -156,16 +194,12 @@ func (sg *SparklineGenerator) GenerateSentimentSparkline(dataPoints []state.Sent
 	// Draw branding watermark
 	sg.drawBrandingWatermark(dc, drawX, drawY, drawWidth, drawHeight)
 
-	// Encode as PNG
-	var buf bytes.Buffer
-	if err := dc.EncodePNG(&buf); err != nil {
-		return nil, fmt.Errorf("failed to encode PNG: %w", err)
-	}
-	return buf.Bytes(), nil
+	// Encode as PNG, downscaling from the render resolution
+	return dc.EncodePNG()
 }
 
 // drawGrid draws grid lines and axes
-func (sg *SparklineGenerator) drawGrid(dc *gg.Context, x, y, width, height float64, yRange YRange) {
+func (sg *SparklineGenerator) drawGrid(dc *canvas, x, y, width, height float64, yRange YRange) {
 	dc.SetColor(sg.config.GridColor)
 	dc.SetLineWidth(0.5)
 
@@ -200,7 +234,7 @@ func (sg *SparklineGenerator) drawGrid(dc *gg.Context, x, y, width, height float
 }
 
 // drawNeutralZone draws a light gray background area for the neutral sentiment zone (-10% to +10%)
-func (sg *SparklineGenerator) drawNeutralZone(dc *gg.Context, x, y, width, height float64, yRange YRange) {
+func (sg *SparklineGenerator) drawNeutralZone(dc *canvas, x, y, width, height float64, yRange YRange) {
 	// Define neutral zone boundaries
 	neutralMin := -10.0
 	neutralMax := 10.0
@@ -233,7 +267,7 @@ func (sg *SparklineGenerator) drawNeutralZone(dc *gg.Context, x, y, width, heigh
 }
 
 // drawNeutralWatermark draws a "Neutral" watermark in the neutral zone
-func (sg *SparklineGenerator) drawNeutralWatermark(dc *gg.Context, x, y, width, height float64) {
+func (sg *SparklineGenerator) drawNeutralWatermark(dc *canvas, x, y, width, height float64) {
 	// Only draw watermark if the neutral zone is large enough
 	if height < 50 || width < 200 {
 		return
@@ -248,14 +282,8 @@ func (sg *SparklineGenerator) drawNeutralWatermark(dc *gg.Context, x, y, width,
 		fontSize = 20 // Minimum 20px
 	}
 
-	// Load a larger font for the watermark
-	if err := dc.LoadFontFace("/System/Library/Fonts/Geneva.ttf", fontSize); err != nil {
-		// Fallback to default font if Geneva is not available
-		if fallbackErr := dc.LoadFontFace("", fontSize); fallbackErr != nil {
-			// If both fail, try to continue with existing font
-			_ = fallbackErr
-		}
-	}
+	// Use a larger font for the watermark
+	dc.SetFontSize(fontSize)
 
 	// Calculate center position
 	centerX := x + width/2
@@ -265,11 +293,11 @@ func (sg *SparklineGenerator) drawNeutralWatermark(dc *gg.Context, x, y, width,
 	dc.SetColor(color.RGBA{200, 200, 200, 30}) // Light gray with low transparency
 
 	// Draw "Neutral" text centered in the neutral zone
-	dc.DrawStringAnchored("Neutral", centerX, centerY, 0.5, 0.5)
+	dc.DrawStringAnchored(sg.labels.Neutral, centerX, centerY, 0.5, 0.5)
 }
 
 // drawSentimentWatermarks draws "Positive" and "Negative" watermarks in their respective zones
-func (sg *SparklineGenerator) drawSentimentWatermarks(dc *gg.Context, x, y, width, height float64, yRange YRange) {
+func (sg *SparklineGenerator) drawSentimentWatermarks(dc *canvas, x, y, width, height float64, yRange YRange) {
 	// Calculate font size based on chart height
 	fontSize := height * 0.15 // 15% of chart height
 	if fontSize > 40 {
@@ -279,14 +307,8 @@ func (sg *SparklineGenerator) drawSentimentWatermarks(dc *gg.Context, x, y, widt
 		fontSize = 16 // Minimum 16px
 	}
 
-	// Load font for watermarks
-	if err := dc.LoadFontFace("/System/Library/Fonts/Geneva.ttf", fontSize); err != nil {
-		// Fallback to default font if Geneva is not available
-		if fallbackErr := dc.LoadFontFace("", fontSize); fallbackErr != nil {
-			// If both fail, try to continue with existing font
-			_ = fallbackErr
-		}
-	}
+	// Size font for watermarks
+	dc.SetFontSize(fontSize)
 
 	// Draw "Positive" watermark in the positive zone (above +10%)
 	positiveThreshold := 10.0
@@ -299,7 +321,7 @@ func (sg *SparklineGenerator) drawSentimentWatermarks(dc *gg.Context, x, y, widt
 		if positiveY < y+height-50 {
 			positiveCenterY := (positiveY + y) / 2
 			dc.SetColor(color.RGBA{40, 167, 69, 60}) // Green with higher opacity
-			dc.DrawStringAnchored("Positive", x+width/2, positiveCenterY, 0.5, 0.5)
+			dc.DrawStringAnchored(sg.labels.Positive, x+width/2, positiveCenterY, 0.5, 0.5)
 		}
 	}
 
@@ -314,24 +336,18 @@ func (sg *SparklineGenerator) drawSentimentWatermarks(dc *gg.Context, x, y, widt
 		if negativeY > y+50 {
 			negativeCenterY := (negativeY + y + height) / 2
 			dc.SetColor(color.RGBA{220, 53, 69, 60}) // Red with higher opacity
-			dc.DrawStringAnchored("Negative", x+width/2, negativeCenterY, 0.5, 0.5)
+			dc.DrawStringAnchored(sg.labels.Negative, x+width/2, negativeCenterY, 0.5, 0.5)
 		}
 	}
 }
 
 // drawBrandingWatermark draws "@hourstats.bsky.social" in the bottom left corner
-func (sg *SparklineGenerator) drawBrandingWatermark(dc *gg.Context, x, y, width, height float64) {
+func (sg *SparklineGenerator) drawBrandingWatermark(dc *canvas, x, y, width, height float64) {
 	// Calculate font size for branding
 	fontSize := 12.0
 
-	// Load font for branding
-	if err := dc.LoadFontFace("/System/Library/Fonts/Geneva.ttf", fontSize); err != nil {
-		// Fallback to default font if Geneva is not available
-		if fallbackErr := dc.LoadFontFace("", fontSize); fallbackErr != nil {
-			// If both fail, try to continue with existing font
-			_ = fallbackErr
-		}
-	}
+	// Size font for branding
+	dc.SetFontSize(fontSize)
 
 	// Position in bottom left corner with small margin
 	brandX := x + 10
@@ -345,7 +361,7 @@ func (sg *SparklineGenerator) drawBrandingWatermark(dc *gg.Context, x, y, width,
 }
 
 // drawSentimentLine draws the sentiment line with appropriate colors
-func (sg *SparklineGenerator) drawSentimentLine(dc *gg.Context, dataPoints []state.SentimentDataPoint, x, y, width, height float64, yRange YRange) {
+func (sg *SparklineGenerator) drawSentimentLine(dc *canvas, dataPoints []state.SentimentDataPoint, x, y, width, height float64, yRange YRange) {
 	if len(dataPoints) < 2 {
 		return
 	}
@@ -378,16 +394,36 @@ func (sg *SparklineGenerator) drawSentimentLine(dc *gg.Context, dataPoints []sta
 			lineColor = sg.config.NeutralLine
 		}
 
+		// Draw point first, as it would end a dashed path; interpolated points get none,
+		// as nothing was measured there
+		if !current.Synthetic {
+			dc.SetColor(lineColor)
+			dc.DrawCircle(x1, y1, sg.config.PointRadius)
+			dc.Fill()
+		}
+
+		// A stretch of interpolated points is stroked as one grey dashed path, so the dashes
+		// carry on across its short segments
+		if current.Synthetic || next.Synthetic {
+			if !current.Synthetic {
+				dc.MoveTo(x1, y1)
+			}
+			dc.LineTo(x2, y2)
+			if !next.Synthetic {
+				dc.SetColor(sg.config.NeutralLine)
+				dc.SetLineWidth(sg.config.LineWidth)
+				dc.SetDash(8, 6)
+				dc.Stroke()
+				dc.SetDash()
+			}
+			continue
+		}
+
 		// Draw line segment
 		dc.SetColor(lineColor)
 		dc.SetLineWidth(sg.config.LineWidth)
 		dc.DrawLine(x1, y1, x2, y2)
 		dc.Stroke()
-
-		// Draw point
-		dc.SetColor(lineColor)
-		dc.DrawCircle(x1, y1, sg.config.PointRadius)
-		dc.Fill()
 	}
 
 	// Draw final point
@@ -411,17 +447,18 @@ func (sg *SparklineGenerator) drawSentimentLine(dc *gg.Context, dataPoints []sta
 }
 
 // drawAverageLine draws a dark grey dotted horizontal line showing the average sentiment
-func (sg *SparklineGenerator) drawAverageLine(dc *gg.Context, dataPoints []state.SentimentDataPoint, x, y, width, height float64, yRange YRange) {
+func (sg *SparklineGenerator) drawAverageLine(dc *canvas, dataPoints []state.SentimentDataPoint, x, y, width, height float64, yRange YRange) {
 	if len(dataPoints) == 0 {
 		return
 	}
 
-	// Calculate the average sentiment
+	// Calculate the average sentiment of the measured points
+	observed := observedPoints(dataPoints)
 	var sum float64
-	for _, dp := range dataPoints {
+	for _, dp := range observed {
 		sum += dp.NetSentimentPercent
 	}
-	average := sum / float64(len(dataPoints))
+	average := sum / float64(len(observed))
 
 	// Convert average to Y position using the same scaling as the data
 	normalizedAverage := (average - yRange.Center) * yRange.Scale / 100.0
@@ -452,20 +489,113 @@ func (sg *SparklineGenerator) drawAverageLine(dc *gg.Context, dataPoints []state
 	}
 }
 
-// drawLabels draws time and sentiment labels
-func (sg *SparklineGenerator) drawLabels(dc *gg.Context, dataPoints []state.SentimentDataPoint, x, y, width, height float64, yRange YRange) {
-	dc.SetColor(sg.config.TextColor)
+// observedPoints drops the points InterpolateGaps made up, for the average and extremes,
+// which describe only what was measured. The first and last points are always measured.
+func observedPoints(dataPoints []state.SentimentDataPoint) []state.SentimentDataPoint {
+	observed := make([]state.SentimentDataPoint, 0, len(dataPoints))
+	for _, dp := range dataPoints {
+		if !dp.Synthetic {
+			observed = append(observed, dp)
+		}
+	}
+	return observed
+}
+
+// sentimentMeanStdDev returns the mean and population standard deviation of the net sentiment
+func sentimentMeanStdDev(dataPoints []state.SentimentDataPoint) (float64, float64) {
+	if len(dataPoints) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, dp := range dataPoints {
+		sum += dp.NetSentimentPercent
+	}
+	mean := sum / float64(len(dataPoints))
 
-	// Load a system font for text rendering
-	// Try Geneva first, then fall back to Symbol if needed
-	if err := dc.LoadFontFace("/System/Library/Fonts/Geneva.ttf", 12); err != nil {
-		// Fallback to Symbol font
-		if fallbackErr := dc.LoadFontFace("/System/Library/Fonts/Symbol.ttf", 12); fallbackErr != nil {
-			// If both fail, we'll continue without custom font
-			_ = fallbackErr
+	var squares float64
+	for _, dp := range dataPoints {
+		squares += (dp.NetSentimentPercent - mean) * (dp.NetSentimentPercent - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(dataPoints)))
+}
+
+// drawReferenceLines draws the optional ±1σ band around the average, the volatility band
+// around the line and the emphasized zero line
+func (sg *SparklineGenerator) drawReferenceLines(dc *canvas, dataPoints []state.SentimentDataPoint, x, y, width, height float64, yRange YRange) {
+	toY := func(value float64) float64 {
+		normalized := (value - yRange.Center) * yRange.Scale / 100.0
+		return y + height/2 - normalized*(height/2)
+	}
+
+	if sg.config.StdDevBand && len(dataPoints) > 1 {
+		mean, stdDev := sentimentMeanStdDev(observedPoints(dataPoints))
+
+		// Clamp the band to the drawing area
+		top := math.Max(toY(mean+stdDev), y)
+		bottom := math.Min(toY(mean-stdDev), y+height)
+		if stdDev > 0 && bottom > top {
+			dc.SetColor(sg.config.BandColor)
+			dc.DrawRectangle(x, top, width, bottom-top)
+			dc.Fill()
+
+			dc.SetFontSize(10)
+			dc.SetColor(sg.config.TextColor)
+			dc.DrawStringAnchored(fmt.Sprintf("±1σ (%.1f%%)", stdDev), x+5, top+3, 0, 1)
 		}
 	}
 
+	if sg.config.VolatilityBand && len(dataPoints) > 1 {
+		sg.drawVolatilityBand(dc, dataPoints, x, y, width, height, toY)
+	}
+
+	if sg.config.ZeroLine && yRange.Min <= 0.0 && yRange.Max >= 0.0 {
+		yZero := toY(0)
+		dc.SetColor(sg.config.TextColor)
+		dc.SetLineWidth(1.5)
+		dc.DrawLine(x, yZero, x+width, yZero)
+		dc.Stroke()
+	}
+}
+
+// drawVolatilityBand shades net sentiment ± volatility between each pair of neighbouring
+// runs that both stored a volatility
+func (sg *SparklineGenerator) drawVolatilityBand(dc *canvas, dataPoints []state.SentimentDataPoint, x, y, width, height float64, toY func(float64) float64) {
+	startTime := dataPoints[0].Timestamp
+	timeRange := dataPoints[len(dataPoints)-1].Timestamp.Sub(startTime).Seconds()
+	if timeRange <= 0 {
+		return
+	}
+	toX := func(point state.SentimentDataPoint) float64 {
+		return x + (point.Timestamp.Sub(startTime).Seconds()/timeRange)*width
+	}
+	// Clamp the band to the drawing area
+	clampY := func(value float64) float64 {
+		return math.Min(math.Max(toY(value), y), y+height)
+	}
+
+	dc.SetColor(sg.config.VolatilityColor)
+	for i := 0; i < len(dataPoints)-1; i++ {
+		current, next := dataPoints[i], dataPoints[i+1]
+		if current.Volatility == nil || next.Volatility == nil {
+			continue
+		}
+		x1, x2 := toX(current), toX(next)
+		dc.MoveTo(x1, clampY(current.NetSentimentPercent+*current.Volatility))
+		dc.LineTo(x2, clampY(next.NetSentimentPercent+*next.Volatility))
+		dc.LineTo(x2, clampY(next.NetSentimentPercent-*next.Volatility))
+		dc.LineTo(x1, clampY(current.NetSentimentPercent-*current.Volatility))
+		dc.ClosePath()
+		dc.Fill()
+	}
+}
+
+// drawLabels draws time and sentiment labels
+func (sg *SparklineGenerator) drawLabels(dc *canvas, dataPoints []state.SentimentDataPoint, x, y, width, height float64, yRange YRange) {
+	dc.SetColor(sg.config.TextColor)
+
+	dc.SetFontSize(12)
+
 	// Draw sentiment level labels - use compressed range
 	levels := []struct {
 		value float64
@@ -502,8 +632,8 @@ func (sg *SparklineGenerator) drawLabels(dc *gg.Context, dataPoints []state.Sent
 			sg.drawDayMarkers(dc, dataPoints, x, y, width, height)
 		} else {
 			// For same-day data, show time labels
-			startLabel := startTime.Format("15:04")
-			endLabel := endTime.Format("15:04")
+			startLabel := sg.labels.Clock(startTime)
+			endLabel := sg.labels.Clock(endTime)
 
 			dc.DrawStringAnchored(startLabel, x, y+height+15, 0, 0)
 			dc.DrawStringAnchored(endLabel, x+width, y+height+15, 1, 0)
@@ -511,11 +641,8 @@ func (sg *SparklineGenerator) drawLabels(dc *gg.Context, dataPoints []state.Sent
 	}
 
 	// Draw title
-	if err := dc.LoadFontFace("", 14); err != nil {
-		// If font loading fails, continue with default font
-		_ = err
-	}
-	dc.DrawStringAnchored("Compound Bluesky Sentiment (UTC)", x+width/2, y-10, 0.5, 0)
+	dc.SetFontSize(14)
+	dc.DrawStringAnchored(sg.labels.WeeklyTitle, x+width/2, y-10, 0.5, 0)
 
 	// Draw average line label
 	sg.drawAverageLabel(dc, dataPoints, x, y, width, height, yRange)
@@ -528,7 +655,7 @@ func (sg *SparklineGenerator) drawLabels(dc *gg.Context, dataPoints []state.Sent
 }
 
 // drawDayMarkers draws day markers for midnight UTC positions
-func (sg *SparklineGenerator) drawDayMarkers(dc *gg.Context, dataPoints []state.SentimentDataPoint, x, y, width, height float64) {
+func (sg *SparklineGenerator) drawDayMarkers(dc *canvas, dataPoints []state.SentimentDataPoint, x, y, width, height float64) {
 	startTime := dataPoints[0].Timestamp
 	endTime := dataPoints[len(dataPoints)-1].Timestamp
 	timeRange := endTime.Sub(startTime).Seconds()
@@ -550,7 +677,7 @@ func (sg *SparklineGenerator) drawDayMarkers(dc *gg.Context, dataPoints []state.
 			dc.Stroke()
 
 			// Draw day label below the chart
-			dayLabel := midnight.Format("Mon")
+			dayLabel := sg.labels.Weekday(midnight)
 			dc.SetColor(sg.config.TextColor)
 			dc.DrawStringAnchored(dayLabel, xPos, y+height+15, 0.5, 0)
 		}
@@ -562,7 +689,7 @@ func (sg *SparklineGenerator) findMidnightPositions(startTime, endTime time.Time
 	var midnights []time.Time
 
 	// Start from the first midnight after or at startTime
-	firstMidnight := startTime.Truncate(24 * time.Hour)
+	firstMidnight := startTime.UTC().Truncate(24 * time.Hour)
 	if firstMidnight.Before(startTime) {
 		firstMidnight = firstMidnight.Add(24 * time.Hour)
 	}
@@ -576,17 +703,18 @@ func (sg *SparklineGenerator) findMidnightPositions(startTime, endTime time.Time
 }
 
 // drawAverageLabel draws a label for the average line
-func (sg *SparklineGenerator) drawAverageLabel(dc *gg.Context, dataPoints []state.SentimentDataPoint, x, y, width, height float64, yRange YRange) {
+func (sg *SparklineGenerator) drawAverageLabel(dc *canvas, dataPoints []state.SentimentDataPoint, x, y, width, height float64, yRange YRange) {
 	if len(dataPoints) == 0 {
 		return
 	}
 
-	// Calculate the average sentiment
+	// Calculate the average sentiment of the measured points
+	observed := observedPoints(dataPoints)
 	var sum float64
-	for _, dp := range dataPoints {
+	for _, dp := range observed {
 		sum += dp.NetSentimentPercent
 	}
-	average := sum / float64(len(dataPoints))
+	average := sum / float64(len(observed))
 
 	// Convert average to Y position using the same scaling as the data
 	normalizedAverage := (average - yRange.Center) * yRange.Scale / 100.0
@@ -602,7 +730,7 @@ func (sg *SparklineGenerator) drawAverageLabel(dc *gg.Context, dataPoints []stat
 }
 
 // drawMostRecentLabel draws a label for the most recent observation
-func (sg *SparklineGenerator) drawMostRecentLabel(dc *gg.Context, dataPoints []state.SentimentDataPoint, x, y, width, height float64, yRange YRange) {
+func (sg *SparklineGenerator) drawMostRecentLabel(dc *canvas, dataPoints []state.SentimentDataPoint, x, y, width, height float64, yRange YRange) {
 	if len(dataPoints) == 0 {
 		return
 	}
@@ -625,17 +753,17 @@ func (sg *SparklineGenerator) drawMostRecentLabel(dc *gg.Context, dataPoints []s
 }
 
 // drawExtremeLabels draws labels for the lowest and highest observations
-func (sg *SparklineGenerator) drawExtremeLabels(dc *gg.Context, dataPoints []state.SentimentDataPoint, x, y, width, height float64, yRange YRange) {
+func (sg *SparklineGenerator) drawExtremeLabels(dc *canvas, dataPoints []state.SentimentDataPoint, x, y, width, height float64, yRange YRange) {
 	if len(dataPoints) == 0 {
 		return
 	}
 
-	// Find the lowest and highest observations
+	// Find the lowest and highest observations, leaving out interpolated points
 	var lowest, highest state.SentimentDataPoint
 	lowest = dataPoints[0]
 	highest = dataPoints[0]
 
-	for _, dp := range dataPoints {
+	for _, dp := range observedPoints(dataPoints) {
 		if dp.NetSentimentPercent < lowest.NetSentimentPercent {
 			lowest = dp
 		}
@@ -659,7 +787,7 @@ func (sg *SparklineGenerator) drawExtremeLabels(dc *gg.Context, dataPoints []sta
 		lowestYPos := y + height/2 - normalizedLowestY*(height/2)
 
 		// Position label below the point with timestamp on separate line
-		lowestLabel := fmt.Sprintf("Low: %.1f%%\n%s", lowest.NetSentimentPercent, lowest.Timestamp.Format("Mon 15:04"))
+		lowestLabel := fmt.Sprintf("%s: %.1f%%\n%s %s", sg.labels.Low, lowest.NetSentimentPercent, sg.labels.Weekday(lowest.Timestamp), sg.labels.Clock(lowest.Timestamp))
 		dc.SetColor(sg.config.TextColor)
 		sg.drawMultilineStringAnchored(dc, lowestLabel, lowestXPos, lowestYPos+15, 0.5, 0)
 	}
@@ -672,14 +800,14 @@ func (sg *SparklineGenerator) drawExtremeLabels(dc *gg.Context, dataPoints []sta
 		highestYPos := y + height/2 - normalizedHighestY*(height/2)
 
 		// Position label above the point with timestamp on separate line
-		highestLabel := fmt.Sprintf("High: %.1f%%\n%s", highest.NetSentimentPercent, highest.Timestamp.Format("Mon 15:04"))
+		highestLabel := fmt.Sprintf("%s: %.1f%%\n%s %s", sg.labels.High, highest.NetSentimentPercent, sg.labels.Weekday(highest.Timestamp), sg.labels.Clock(highest.Timestamp))
 		dc.SetColor(sg.config.TextColor)
 		sg.drawMultilineStringAnchored(dc, highestLabel, highestXPos, highestYPos-15, 0.5, 1)
 	}
 }
 
 // drawMultilineStringAnchored draws multi-line text with proper anchoring
-func (sg *SparklineGenerator) drawMultilineStringAnchored(dc *gg.Context, text string, x, y, anchorX, anchorY float64) {
+func (sg *SparklineGenerator) drawMultilineStringAnchored(dc *canvas, text string, x, y, anchorX, anchorY float64) {
 	lines := strings.Split(text, "\n")
 	lineHeight := 14.0 // Font height for 12pt font
 
@@ -730,7 +858,7 @@ func gaussianSmoothing(data []float64, sigma float64) []float64 {
 }
 
 // drawGaussianTrendLine draws a thin dashed blue Gaussian smoothed trend line
-func (sg *SparklineGenerator) drawGaussianTrendLine(dc *gg.Context, dataPoints []state.SentimentDataPoint, x, y, width, height float64, yRange YRange) {
+func (sg *SparklineGenerator) drawGaussianTrendLine(dc *canvas, dataPoints []state.SentimentDataPoint, x, y, width, height float64, yRange YRange) {
 	if len(dataPoints) < 2 {
 		return
 	}
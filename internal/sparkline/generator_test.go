@@ -1,6 +1,7 @@
 package sparkline
 
 import (
+	"bytes"
 	"testing"
 	"time"
 
@@ -127,3 +128,73 @@ func TestSparklineConfig(t *testing.T) {
 		t.Fatal("Custom config not applied correctly")
 	}
 }
+
+func TestSentimentMeanStdDev(t *testing.T) {
+	var dataPoints []state.SentimentDataPoint
+	for _, value := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		dataPoints = append(dataPoints, state.SentimentDataPoint{NetSentimentPercent: value})
+	}
+
+	mean, stdDev := sentimentMeanStdDev(dataPoints)
+	if mean != 5 || stdDev != 2 {
+		t.Errorf("expected mean 5 and standard deviation 2, got %.2f and %.2f", mean, stdDev)
+	}
+
+	if mean, stdDev := sentimentMeanStdDev(nil); mean != 0 || stdDev != 0 {
+		t.Errorf("expected zeros for no data, got %.2f and %.2f", mean, stdDev)
+	}
+}
+
+func TestReferenceLinesDrawn(t *testing.T) {
+	config := DefaultConfig()
+	config.RenderScale = 1
+	plain, err := NewSparklineGenerator(config).GenerateSentimentSparkline(goldenWeek())
+	if err != nil {
+		t.Fatalf("failed to generate sparkline: %v", err)
+	}
+
+	config.ZeroLine = true
+	config.StdDevBand = true
+	referenced, err := NewSparklineGenerator(config).GenerateSentimentSparkline(goldenWeek())
+	if err != nil {
+		t.Fatalf("failed to generate sparkline with reference lines: %v", err)
+	}
+
+	if bytes.Equal(plain, referenced) {
+		t.Error("expected reference lines to change the rendered chart")
+	}
+}
+
+func TestVolatilityBandDrawn(t *testing.T) {
+	config := DefaultConfig()
+	config.RenderScale = 1
+	generator := NewSparklineGenerator(config)
+	points := goldenWeek()
+	plain, err := generator.GenerateSentimentSparkline(points)
+	if err != nil {
+		t.Fatalf("failed to generate sparkline: %v", err)
+	}
+
+	unmeasured, err := generator.WithVolatilityBand().GenerateSentimentSparkline(points)
+	if err != nil {
+		t.Fatalf("failed to generate sparkline without volatility: %v", err)
+	}
+	if !bytes.Equal(plain, unmeasured) {
+		t.Error("expected no band when no run stored a volatility")
+	}
+
+	for i := range points {
+		volatility := 4.0
+		points[i].Volatility = &volatility
+	}
+	banded, err := generator.WithVolatilityBand().GenerateSentimentSparkline(points)
+	if err != nil {
+		t.Fatalf("failed to generate sparkline with volatility band: %v", err)
+	}
+	if bytes.Equal(plain, banded) {
+		t.Error("expected the volatility band to change the rendered chart")
+	}
+	if config.VolatilityBand {
+		t.Error("expected WithVolatilityBand to leave the original config unchanged")
+	}
+}
@@ -0,0 +1,294 @@
+package sparkline
+
+import (
+	"bytes"
+	"flag"
+	"image"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// Regenerate the golden images after an intended rendering change with:
+//
+//	go test ./internal/sparkline -run Golden -update
+var update = flag.Bool("update", false, "rewrite golden chart images")
+
+const (
+	// goldenChannelTolerance absorbs rounding differences between platforms
+	goldenChannelTolerance = 8
+	// goldenMaxDiffRatio is the share of pixels allowed to differ beyond the tolerance
+	goldenMaxDiffRatio = 0.001
+)
+
+// goldenWeek is a deterministic week of hourly sentiment crossing all three zones
+func goldenWeek() []state.SentimentDataPoint {
+	start := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	points := make([]state.SentimentDataPoint, 0, 7*24)
+	for hour := 0; hour < 7*24; hour++ {
+		points = append(points, state.SentimentDataPoint{
+			RunID:               "golden",
+			Timestamp:           start.Add(time.Duration(hour) * time.Hour),
+			NetSentimentPercent: 25*math.Sin(float64(hour)/12) + 8*math.Cos(float64(hour)/3),
+			TotalPosts:          1000,
+		})
+	}
+	return points
+}
+
+// goldenYear is a deterministic year of daily averages
+func goldenYear() []state.YearlySparklineDataPoint {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := make([]state.YearlySparklineDataPoint, 0, 366)
+	for day := 0; day < 366; day++ {
+		date := start.AddDate(0, 0, day)
+		average := 15*math.Sin(float64(day)/30) + 5*math.Sin(float64(day)/4)
+		points = append(points, state.YearlySparklineDataPoint{
+			Date:                date.Format("2006-01-02"),
+			Timestamp:           date,
+			AverageSentiment:    average,
+			MinSentiment:        average - 10,
+			MaxSentiment:        average + 10,
+			NetSentimentPercent: average,
+		})
+	}
+	return points
+}
+
+func TestWeeklySparklineGolden(t *testing.T) {
+	for _, scale := range []float64{1, 2} {
+		config := DefaultConfig()
+		config.RenderScale = scale
+
+		imageData, err := NewSparklineGenerator(config).GenerateSentimentSparkline(goldenWeek())
+		if err != nil {
+			t.Fatalf("failed to generate sparkline: %v", err)
+		}
+		checkGolden(t, filepath.Join("testdata", goldenName("weekly", scale)), imageData, config.Width, config.Height)
+	}
+}
+
+func TestYearlySparklineGolden(t *testing.T) {
+	config := DefaultYearlyConfig()
+
+	imageData, err := NewYearlySparklineGenerator(config).GenerateYearlySentimentSparkline(goldenYear())
+	if err != nil {
+		t.Fatalf("failed to generate yearly sparkline: %v", err)
+	}
+	checkGolden(t, filepath.Join("testdata", goldenName("yearly", config.RenderScale)), imageData, config.Width, config.Height)
+}
+
+// TestWeeklySparklineOptionsGolden covers the optional reference lines and bands
+func TestWeeklySparklineOptionsGolden(t *testing.T) {
+	config := DefaultConfig()
+	config.ZeroLine = true
+	config.StdDevBand = true
+	config.VolatilityBand = true
+
+	points := goldenWeek()
+	for i := range points {
+		volatility := 4 + 3*math.Sin(float64(i)/20)
+		points[i].Volatility = &volatility
+	}
+
+	imageData, err := NewSparklineGenerator(config).GenerateSentimentSparkline(points)
+	if err != nil {
+		t.Fatalf("failed to generate sparkline: %v", err)
+	}
+	checkGolden(t, filepath.Join("testdata", goldenName("weekly-bands", config.RenderScale)), imageData, config.Width, config.Height)
+}
+
+// TestGapSparklineGolden covers interpolated points, drawn dashed and left out of the
+// average and extremes
+func TestGapSparklineGolden(t *testing.T) {
+	config := DefaultConfig()
+
+	week := goldenWeek()
+	gappy := append(append([]state.SentimentDataPoint{}, week[:60]...), week[90:]...)
+	imageData, err := NewSparklineGenerator(config).GenerateSentimentSparkline(state.InterpolateGaps(gappy, 0))
+	if err != nil {
+		t.Fatalf("failed to generate sparkline: %v", err)
+	}
+	checkGolden(t, filepath.Join("testdata", goldenName("weekly-gaps", config.RenderScale)), imageData, config.Width, config.Height)
+
+	yearlyConfig := DefaultYearlyConfig()
+	year := goldenYear()
+	gappyYear := append(append([]state.YearlySparklineDataPoint{}, year[:150]...), year[200:]...)
+	imageData, err = NewYearlySparklineGenerator(yearlyConfig).GenerateYearlySentimentSparkline(state.InterpolateDailyGaps(gappyYear))
+	if err != nil {
+		t.Fatalf("failed to generate yearly sparkline: %v", err)
+	}
+	checkGolden(t, filepath.Join("testdata", goldenName("yearly-gaps", yearlyConfig.RenderScale)), imageData, yearlyConfig.Width, yearlyConfig.Height)
+}
+
+func TestComparisonSparklineGolden(t *testing.T) {
+	config := DefaultConfig()
+
+	bluesky := goldenWeek()
+	mastodon := make([]state.SentimentDataPoint, 0, len(bluesky)/2)
+	for i, point := range bluesky {
+		if i%2 == 0 {
+			point.NetSentimentPercent = 10 + 12*math.Cos(float64(i)/15)
+			point.Network = "mastodon"
+			mastodon = append(mastodon, point)
+		}
+	}
+
+	imageData, err := NewSparklineGenerator(config).GenerateComparisonSparkline([]Series{
+		{Label: "Bluesky", Color: ComparisonColors[0], Points: bluesky},
+		{Label: "Mastodon (mastodon.social)", Color: ComparisonColors[1], Points: mastodon},
+	})
+	if err != nil {
+		t.Fatalf("failed to generate comparison sparkline: %v", err)
+	}
+	checkGolden(t, filepath.Join("testdata", goldenName("comparison", config.RenderScale)), imageData, config.Width, config.Height)
+}
+
+func TestToxicitySparklineGolden(t *testing.T) {
+	config := DefaultConfig()
+
+	points := goldenWeek()
+	for i := range points {
+		toxicity := 6 + 4*math.Sin(float64(i)/9)
+		points[i].ToxicityPercent = &toxicity
+	}
+
+	imageData, err := NewSparklineGenerator(config).GenerateToxicitySparkline(points)
+	if err != nil {
+		t.Fatalf("failed to generate toxicity sparkline: %v", err)
+	}
+	checkGolden(t, filepath.Join("testdata", goldenName("toxicity", config.RenderScale)), imageData, config.Width, config.Height)
+}
+
+func TestEmotionSparklineGolden(t *testing.T) {
+	config := DefaultConfig()
+
+	points := goldenWeek()
+	for i := range points {
+		joy := 0.3 + 0.1*math.Sin(float64(i)/10)
+		points[i].Emotions = &state.Emotions{Joy: joy, Anger: 0.2, Sadness: 0.25, Fear: 0.55 - joy}
+	}
+
+	imageData, err := NewSparklineGenerator(config).GenerateEmotionSparkline(points)
+	if err != nil {
+		t.Fatalf("failed to generate emotion sparkline: %v", err)
+	}
+	checkGolden(t, filepath.Join("testdata", goldenName("emotions", config.RenderScale)), imageData, config.Width, config.Height)
+}
+
+// TestSparklinesAreDeterministic renders each chart twice, once with its data in another
+// time zone, and expects identical bytes: charts depend on nothing but their data
+func TestSparklinesAreDeterministic(t *testing.T) {
+	elsewhere := time.FixedZone("UTC-5", -5*60*60)
+
+	week := goldenWeek()
+	shiftedWeek := goldenWeek()
+	for i := range shiftedWeek {
+		shiftedWeek[i].Timestamp = shiftedWeek[i].Timestamp.In(elsewhere)
+	}
+	weekly := NewSparklineGenerator(nil)
+	first, err := weekly.GenerateSentimentSparkline(week)
+	if err != nil {
+		t.Fatalf("failed to generate sparkline: %v", err)
+	}
+	second, err := weekly.GenerateSentimentSparkline(shiftedWeek)
+	if err != nil {
+		t.Fatalf("failed to generate sparkline: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Error("weekly chart changed with the time zone of its data")
+	}
+
+	year := goldenYear()
+	shiftedYear := goldenYear()
+	for i := range shiftedYear {
+		shiftedYear[i].Timestamp = shiftedYear[i].Timestamp.In(elsewhere)
+	}
+	yearly := NewYearlySparklineGenerator(nil)
+	first, err = yearly.GenerateYearlySentimentSparkline(year)
+	if err != nil {
+		t.Fatalf("failed to generate yearly sparkline: %v", err)
+	}
+	second, err = yearly.GenerateYearlySentimentSparkline(shiftedYear)
+	if err != nil {
+		t.Fatalf("failed to generate yearly sparkline: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Error("yearly chart changed with the time zone of its data")
+	}
+}
+
+func goldenName(chart string, scale float64) string {
+	if scale > 1 {
+		return chart + "@2x.png"
+	}
+	return chart + ".png"
+}
+
+// checkGolden compares a rendered chart with its golden image, or rewrites the golden
+// image when -update is set. Charts must come out at their nominal size whatever the
+// render scale.
+func checkGolden(t *testing.T, path string, imageData []byte, width, height int) {
+	t.Helper()
+
+	actual, err := png.Decode(bytes.NewReader(imageData))
+	if err != nil {
+		t.Fatalf("rendered chart is not a PNG: %v", err)
+	}
+	if actual.Bounds().Dx() != width || actual.Bounds().Dy() != height {
+		t.Fatalf("rendered chart is %v, want %dx%d", actual.Bounds().Size(), width, height)
+	}
+
+	if *update {
+		if err := os.WriteFile(path, imageData, 0o644); err != nil {
+			t.Fatalf("failed to write golden image: %v", err)
+		}
+		return
+	}
+
+	goldenData, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("missing golden image (run with -update to create it): %v", err)
+	}
+	golden, err := png.Decode(bytes.NewReader(goldenData))
+	if err != nil {
+		t.Fatalf("golden image %s is not a PNG: %v", path, err)
+	}
+
+	if ratio := diffRatio(golden, actual); ratio > goldenMaxDiffRatio {
+		actualPath := filepath.Join(t.TempDir(), filepath.Base(path))
+		_ = os.WriteFile(actualPath, imageData, 0o644)
+		t.Errorf("%s: %.2f%% of pixels differ from the golden image; rendered chart written to %s",
+			path, ratio*100, actualPath)
+	}
+}
+
+// diffRatio is the share of pixels whose channels differ by more than the tolerance
+func diffRatio(a, b image.Image) float64 {
+	bounds := a.Bounds()
+	if bounds.Size() != b.Bounds().Size() {
+		return 1
+	}
+
+	differing := 0
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			r1, g1, b1, a1 := a.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			r2, g2, b2, a2 := b.At(b.Bounds().Min.X+x, b.Bounds().Min.Y+y).RGBA()
+			if channelDiff(r1, r2) || channelDiff(g1, g2) || channelDiff(b1, b2) || channelDiff(a1, a2) {
+				differing++
+			}
+		}
+	}
+	return float64(differing) / float64(bounds.Dx()*bounds.Dy())
+}
+
+func channelDiff(a, b uint32) bool {
+	diff := int(a>>8) - int(b>>8)
+	return diff > goldenChannelTolerance || diff < -goldenChannelTolerance
+}
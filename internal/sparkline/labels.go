@@ -0,0 +1,107 @@
+package sparkline
+
+import (
+	"fmt"
+	"time"
+)
+
+// Labels holds the text drawn on charts, so deployments can localize month and day
+// names. The chart font must cover every character used; see FontPath in the configs.
+type Labels struct {
+	Months   [12]string // abbreviated month names, January first
+	Weekdays [7]string  // abbreviated weekday names, Sunday first
+
+	Positive string
+	Neutral  string
+	Negative string
+	High     string
+	Low      string
+
+	WeeklyTitle     string
+	YearlyTitle     string
+	MonthlyTitle    string
+	QuarterlyTitle  string
+	ComparisonTitle string
+	ToxicityTitle   string
+	NetSentiment    string // series names on the toxicity chart
+	ToxicPosts      string
+	EmotionTitle    string
+	Emotions        [4]string // joy, anger, sadness and fear on the emotion chart
+	RatioTitle      string
+	WeightingTitle  string
+	PerPost         string // series names on the weighting chart
+	Weighted        string
+	RecomputeTitle  string
+	Live            string // the live series on the recompute chart, beside the recomputed one
+}
+
+// DefaultLabels returns the English labels
+func DefaultLabels() *Labels {
+	return &Labels{
+		Months:      [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		Weekdays:    [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		Positive:    "Positive",
+		Neutral:     "Neutral",
+		Negative:    "Negative",
+		High:        "High",
+		Low:         "Low",
+		WeeklyTitle: "Compound Bluesky Sentiment (UTC)",
+		YearlyTitle: "Bluesky Sentiment",
+
+		MonthlyTitle:    "30 Day Bluesky Sentiment",
+		QuarterlyTitle:  "Quarterly Bluesky Sentiment",
+		ComparisonTitle: "Net Sentiment by Network (UTC)",
+		ToxicityTitle:   "Net Sentiment and Toxic Posts (UTC)",
+		NetSentiment:    "Net sentiment",
+		ToxicPosts:      "Toxic posts",
+		EmotionTitle:    "Emotions in Bluesky Posts (UTC)",
+		Emotions:        [4]string{"Joy", "Anger", "Sadness", "Fear"},
+		RatioTitle:      "Positive, Neutral and Negative Posts (UTC)",
+		WeightingTitle:  "Net Sentiment per Post and by Engagement (UTC)",
+		PerPost:         "Per post",
+		Weighted:        "Engagement-weighted",
+		RecomputeTitle:  "Net Sentiment, Live and Recomputed (UTC)",
+		Live:            "Live",
+	}
+}
+
+// labelsOrDefault returns labels, or the English labels when none are configured
+func labelsOrDefault(labels *Labels) *Labels {
+	if labels == nil {
+		return DefaultLabels()
+	}
+	return labels
+}
+
+// Charts label times in UTC whatever the location of their data, so a chart renders the
+// same on any machine.
+
+// Month returns the abbreviated month name for t in UTC, e.g. "Jan"
+func (l *Labels) Month(t time.Time) string {
+	return l.Months[t.UTC().Month()-1]
+}
+
+// Weekday returns the abbreviated weekday name for t in UTC, e.g. "Mon"
+func (l *Labels) Weekday(t time.Time) string {
+	return l.Weekdays[t.UTC().Weekday()]
+}
+
+// DayMonth formats t in UTC as day then month, e.g. "15 Oct"
+func (l *Labels) DayMonth(t time.Time) string {
+	return fmt.Sprintf("%d %s", t.UTC().Day(), l.Month(t))
+}
+
+// MonthDay formats t in UTC as month then day, e.g. "Oct 15"
+func (l *Labels) MonthDay(t time.Time) string {
+	return fmt.Sprintf("%s %d", l.Month(t), t.UTC().Day())
+}
+
+// Clock formats t's UTC time of day, e.g. "14:05"
+func (l *Labels) Clock(t time.Time) string {
+	return t.UTC().Format("15:04")
+}
+
+// Date formats t's UTC date, e.g. "2025-10-15"
+func (l *Labels) Date(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
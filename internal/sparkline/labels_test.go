@@ -0,0 +1,78 @@
+package sparkline
+
+import (
+	"bytes"
+	"image/png"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func germanLabels() *Labels {
+	labels := DefaultLabels()
+	labels.Months = [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"}
+	labels.Weekdays = [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"}
+	labels.Positive, labels.Neutral, labels.Negative = "Positiv", "Neutral", "Negativ"
+	labels.High, labels.Low = "Hoch", "Tief"
+	labels.WeeklyTitle = "Bluesky-Stimmung (UTC)"
+	labels.YearlyTitle = "Bluesky-Stimmung"
+	return labels
+}
+
+func TestLabelsFormatting(t *testing.T) {
+	labels := germanLabels()
+	date := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	if got := labels.Month(date); got != "Mär" {
+		t.Errorf("Month = %q, want Mär", got)
+	}
+	if got := labels.Weekday(date); got != "Di" {
+		t.Errorf("Weekday = %q, want Di", got)
+	}
+	if got := labels.DayMonth(date); got != "4 Mär" {
+		t.Errorf("DayMonth = %q, want \"4 Mär\"", got)
+	}
+	if got := labels.MonthDay(date); got != "Mär 4" {
+		t.Errorf("MonthDay = %q, want \"Mär 4\"", got)
+	}
+
+	english := labelsOrDefault(nil)
+	if got := english.DayMonth(date); got != date.Format("2 Jan") {
+		t.Errorf("default DayMonth = %q, want %q", got, date.Format("2 Jan"))
+	}
+}
+
+func TestLocalizedLabelsChangeChart(t *testing.T) {
+	english, err := NewYearlySparklineGenerator(DefaultYearlyConfig()).GenerateYearlySentimentSparkline(goldenYear())
+	if err != nil {
+		t.Fatalf("failed to generate yearly sparkline: %v", err)
+	}
+
+	config := DefaultYearlyConfig()
+	config.Labels = germanLabels()
+	german, err := NewYearlySparklineGenerator(config).GenerateYearlySentimentSparkline(goldenYear())
+	if err != nil {
+		t.Fatalf("failed to generate localized yearly sparkline: %v", err)
+	}
+
+	a, err := png.Decode(bytes.NewReader(english))
+	if err != nil {
+		t.Fatalf("english chart is not a PNG: %v", err)
+	}
+	b, err := png.Decode(bytes.NewReader(german))
+	if err != nil {
+		t.Fatalf("localized chart is not a PNG: %v", err)
+	}
+	if diffRatio(a, b) == 0 {
+		t.Error("expected localized labels to change the rendered chart")
+	}
+}
+
+func TestMissingFontPathFails(t *testing.T) {
+	config := DefaultConfig()
+	config.FontPath = filepath.Join(t.TempDir(), "missing.ttf")
+
+	if _, err := NewSparklineGenerator(config).GenerateSentimentSparkline(goldenWeek()); err == nil {
+		t.Fatal("expected an error for a missing font file")
+	}
+}
@@ -0,0 +1,161 @@
+package sparkline
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// Resolution is how much time each point on a range chart covers
+type Resolution string
+
+const (
+	// ResolutionDaily charts each day's average as it is stored
+	ResolutionDaily Resolution = "daily"
+	// ResolutionWeekly averages the days of each week, Monday to Sunday (UTC), into one point
+	ResolutionWeekly Resolution = "weekly"
+)
+
+// ParseResolution parses "daily" or "weekly"
+func ParseResolution(value string) (Resolution, error) {
+	switch resolution := Resolution(value); resolution {
+	case ResolutionDaily, ResolutionWeekly:
+		return resolution, nil
+	}
+	return "", fmt.Errorf("unknown resolution %q: want %s or %s", value, ResolutionDaily, ResolutionWeekly)
+}
+
+// ChartRange is the span a range chart covers, days in [Start, End), and its resolution
+type ChartRange struct {
+	Start      time.Time
+	End        time.Time
+	Resolution Resolution
+}
+
+// Validate checks that the range is not empty and its resolution is known
+func (r ChartRange) Validate() error {
+	if !r.Start.Before(r.End) {
+		return fmt.Errorf("chart range start %s is not before its end %s", r.Start.Format("2006-01-02"), r.End.Format("2006-01-02"))
+	}
+	_, err := ParseResolution(string(r.Resolution))
+	return err
+}
+
+// Points keeps the days of dataPoints within the range and buckets them to its resolution,
+// oldest first. Weekly points leave out days InterpolateDailyGaps made up, so interpolate
+// after bucketing, and only daily points.
+func (r ChartRange) Points(dataPoints []state.YearlySparklineDataPoint) []state.YearlySparklineDataPoint {
+	var kept []state.YearlySparklineDataPoint
+	for _, point := range dataPoints {
+		if !point.Timestamp.Before(r.Start) && point.Timestamp.Before(r.End) {
+			kept = append(kept, point)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Timestamp.Before(kept[j].Timestamp) })
+	if r.Resolution == ResolutionWeekly {
+		return weeklyPoints(kept)
+	}
+	return kept
+}
+
+// weeklyPoints averages sorted daily points into one point per week, stamped with its Monday.
+// A week's low and high are the lowest low and highest high of its days.
+func weeklyPoints(days []state.YearlySparklineDataPoint) []state.YearlySparklineDataPoint {
+	var weeks []state.YearlySparklineDataPoint
+	var count int
+	for _, day := range days {
+		if day.Synthetic {
+			continue
+		}
+		monday := weekStart(day.Timestamp)
+		if len(weeks) == 0 || !weeks[len(weeks)-1].Timestamp.Equal(monday) {
+			if len(weeks) > 0 {
+				finishWeek(&weeks[len(weeks)-1], count)
+			}
+			weeks = append(weeks, state.YearlySparklineDataPoint{
+				Date:         monday.Format("2006-01-02"),
+				Timestamp:    monday,
+				MinSentiment: day.MinSentiment,
+				MaxSentiment: day.MaxSentiment,
+			})
+			count = 0
+		}
+		week := &weeks[len(weeks)-1]
+		week.AverageSentiment += day.AverageSentiment
+		week.MinSentiment = min(week.MinSentiment, day.MinSentiment)
+		week.MaxSentiment = max(week.MaxSentiment, day.MaxSentiment)
+		count++
+	}
+	if len(weeks) > 0 {
+		finishWeek(&weeks[len(weeks)-1], count)
+	}
+	return weeks
+}
+
+// finishWeek turns a week's summed sentiment into the average of its days
+func finishWeek(week *state.YearlySparklineDataPoint, days int) {
+	week.AverageSentiment /= float64(days)
+	week.NetSentimentPercent = week.AverageSentiment
+}
+
+// weekStart returns midnight UTC on the Monday of t's week
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// NewRangeChartGenerator creates a range chart generator headed by title, or by the yearly
+// title when it is empty; a nil config uses DefaultYearlyConfig
+func NewRangeChartGenerator(config *YearlySparklineConfig, title string) *RangeChartGenerator {
+	if config == nil {
+		config = DefaultYearlyConfig()
+	}
+	labels := labelsOrDefault(config.Labels)
+	if title == "" {
+		title = labels.YearlyTitle
+	}
+	return &RangeChartGenerator{config: config, labels: labels, title: title}
+}
+
+// GenerateRangeChart creates a PNG image of the days of dataPoints within chartRange, at
+// its resolution
+func (yg *RangeChartGenerator) GenerateRangeChart(dataPoints []state.YearlySparklineDataPoint, chartRange ChartRange) ([]byte, error) {
+	if err := chartRange.Validate(); err != nil {
+		return nil, err
+	}
+	return yg.GenerateYearlySentimentSparkline(chartRange.Points(dataPoints))
+}
+
+// DefaultMonthlyConfig returns the 30 day chart's configuration: the yearly chart's
+// styling at the weekly chart's size, with a 7 day moving average to show the weekly cycle
+func DefaultMonthlyConfig() *YearlySparklineConfig {
+	config := DefaultYearlyConfig()
+	config.Width = 1200
+	config.Height = 800
+	config.Padding = 80
+	config.LineWidth = 3.0
+	config.PointRadius = 2.0
+	config.MovingAverageDays = 7
+	return config
+}
+
+// NewMonthlySparklineGenerator creates a generator for the 30 day chart. It draws daily
+// aggregates as the yearly chart does, under the monthly title.
+func NewMonthlySparklineGenerator(config *YearlySparklineConfig) *RangeChartGenerator {
+	if config == nil {
+		config = DefaultMonthlyConfig()
+	}
+	return NewRangeChartGenerator(config, labelsOrDefault(config.Labels).MonthlyTitle)
+}
+
+// NewQuarterlySparklineGenerator creates a generator for the quarterly chart, drawn like the
+// 30 day chart under the quarterly title
+func NewQuarterlySparklineGenerator(config *YearlySparklineConfig) *RangeChartGenerator {
+	if config == nil {
+		config = DefaultMonthlyConfig()
+	}
+	return NewRangeChartGenerator(config, labelsOrDefault(config.Labels).QuarterlyTitle)
+}
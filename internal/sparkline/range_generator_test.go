@@ -0,0 +1,116 @@
+package sparkline
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func TestNewMonthlySparklineGenerator(t *testing.T) {
+	generator := NewMonthlySparklineGenerator(nil)
+
+	if generator.config.Width != 1200 || generator.config.Height != 800 {
+		t.Errorf("Expected the weekly chart's 1200x800, got %dx%d", generator.config.Width, generator.config.Height)
+	}
+	if generator.config.MovingAverageDays != 7 {
+		t.Errorf("Expected a 7 day moving average, got %d", generator.config.MovingAverageDays)
+	}
+	if generator.title != DefaultLabels().MonthlyTitle {
+		t.Errorf("Expected the monthly title, got %q", generator.title)
+	}
+	if quarterly := NewQuarterlySparklineGenerator(nil); quarterly.title != DefaultLabels().QuarterlyTitle {
+		t.Errorf("Expected the quarterly title, got %q", quarterly.title)
+	}
+	if yearly := NewYearlySparklineGenerator(nil); yearly.title != DefaultLabels().YearlyTitle {
+		t.Errorf("Expected the yearly generator to keep the yearly title, got %q", yearly.title)
+	}
+}
+
+func TestGenerateMonthlySentimentSparkline(t *testing.T) {
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	var dataPoints []state.YearlySparklineDataPoint
+	for day := 0; day < state.MonthlyDays; day++ {
+		ts := start.AddDate(0, 0, day)
+		sentiment := float64(day%7) - 3
+		dataPoints = append(dataPoints, state.YearlySparklineDataPoint{
+			Date:                ts.Format("2006-01-02"),
+			AverageSentiment:    sentiment,
+			Timestamp:           ts,
+			NetSentimentPercent: sentiment,
+		})
+	}
+
+	imageData, err := NewMonthlySparklineGenerator(nil).GenerateYearlySentimentSparkline(dataPoints)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(imageData))
+	if err != nil {
+		t.Fatalf("Expected a PNG, got %v", err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != 1200 || bounds.Dy() != 800 {
+		t.Errorf("Expected a 1200x800 image, got %dx%d", bounds.Dx(), bounds.Dy())
+	}
+}
+
+func TestParseResolution(t *testing.T) {
+	for _, value := range []string{"daily", "weekly"} {
+		if resolution, err := ParseResolution(value); err != nil || string(resolution) != value {
+			t.Errorf("ParseResolution(%q) = %q, %v", value, resolution, err)
+		}
+	}
+	if _, err := ParseResolution("hourly"); err == nil {
+		t.Error("Expected an error for an unknown resolution")
+	}
+}
+
+func TestChartRangePoints(t *testing.T) {
+	// Wednesday Sep 3 to Tuesday Sep 16, 2025, with Sep 10 made up by interpolation
+	start := time.Date(2025, 9, 3, 0, 0, 0, 0, time.UTC)
+	var days []state.YearlySparklineDataPoint
+	for day := 13; day >= 0; day-- {
+		ts := start.AddDate(0, 0, day)
+		days = append(days, state.YearlySparklineDataPoint{
+			Date:             ts.Format("2006-01-02"),
+			AverageSentiment: float64(day),
+			MinSentiment:     float64(day) - 10,
+			MaxSentiment:     float64(day) + 10,
+			Timestamp:        ts,
+			Synthetic:        day == 7,
+		})
+	}
+
+	daily := ChartRange{Start: start.AddDate(0, 0, 1), End: start.AddDate(0, 0, 4), Resolution: ResolutionDaily}.Points(days)
+	if len(daily) != 3 || daily[0].Date != "2025-09-04" || daily[2].Date != "2025-09-06" {
+		t.Fatalf("Expected Sep 4 to 6 oldest first, got %+v", daily)
+	}
+
+	weekly := ChartRange{Start: start, End: start.AddDate(0, 0, 14), Resolution: ResolutionWeekly}.Points(days)
+	if len(weekly) != 3 {
+		t.Fatalf("Expected 3 weeks, got %+v", weekly)
+	}
+	// Week of Mon Sep 1 has Sep 3-7 (days 0-4); week of Sep 8 has days 5-11 without day 7
+	if weekly[0].Date != "2025-09-01" || weekly[0].AverageSentiment != 2 || weekly[0].MinSentiment != -10 || weekly[0].MaxSentiment != 14 {
+		t.Errorf("Unexpected first week %+v", weekly[0])
+	}
+	if want := (5.0 + 6 + 8 + 9 + 10 + 11) / 6; weekly[1].Date != "2025-09-08" || weekly[1].AverageSentiment != want || weekly[1].NetSentimentPercent != want {
+		t.Errorf("Expected the week of Sep 8 to average %v without the made up day, got %+v", want, weekly[1])
+	}
+	if weekly[2].Date != "2025-09-15" || weekly[2].AverageSentiment != 12.5 {
+		t.Errorf("Unexpected last week %+v", weekly[2])
+	}
+}
+
+func TestGenerateRangeChartInvalidRange(t *testing.T) {
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	generator := NewRangeChartGenerator(nil, "")
+	if _, err := generator.GenerateRangeChart(nil, ChartRange{Start: start, End: start, Resolution: ResolutionDaily}); err == nil {
+		t.Error("Expected an error for an empty range")
+	}
+	if _, err := generator.GenerateRangeChart(nil, ChartRange{Start: start, End: start.AddDate(0, 1, 0), Resolution: "monthly"}); err == nil {
+		t.Error("Expected an error for an unknown resolution")
+	}
+}
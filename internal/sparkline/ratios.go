@@ -0,0 +1,34 @@
+package sparkline
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// GenerateRatioSparkline draws each run's split of negative, neutral and positive posts as
+// stacked areas from 0 to 100%, negative at the bottom, using the points that recorded
+// their counts. It tells a rise in neutral posts apart from a rise in negative ones, which
+// net sentiment alone can't.
+func (sg *SparklineGenerator) GenerateRatioSparkline(dataPoints []state.SentimentDataPoint) ([]byte, error) {
+	var points []state.SentimentDataPoint
+	for _, point := range dataPoints {
+		if point.Counts != nil && point.Counts.Total() > 0 {
+			points = append(points, point)
+		}
+	}
+	if len(points) < 2 {
+		return nil, fmt.Errorf("not enough sentiment count data points")
+	}
+
+	return sg.generateStackedSparkline(stackedChart{
+		title:  sg.labels.RatioTitle,
+		colors: []color.RGBA{sg.config.NegativeLine, sg.config.NeutralLine, sg.config.PositiveLine},
+		names:  []string{sg.labels.Negative, sg.labels.Neutral, sg.labels.Positive},
+		shares: func(point state.SentimentDataPoint) []float64 {
+			c := point.Counts
+			return []float64{float64(c.Negative), float64(c.Neutral), float64(c.Positive)}
+		},
+	}, points)
+}
@@ -0,0 +1,35 @@
+package sparkline
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+func TestGenerateRatioSparkline(t *testing.T) {
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	var points []state.SentimentDataPoint
+	for i := 0; i < 72; i++ {
+		point := state.SentimentDataPoint{Timestamp: start.Add(time.Duration(i) * time.Hour)}
+		// Runs before counts were recorded have none
+		if i >= 12 {
+			point.Counts = &state.SentimentCounts{Positive: 300 - i, Neutral: 400 + 2*i, Negative: 300}
+		}
+		points = append(points, point)
+	}
+
+	imageData, err := NewSparklineGenerator(nil).GenerateRatioSparkline(points)
+	if err != nil {
+		t.Fatalf("GenerateRatioSparkline() error = %v", err)
+	}
+	if _, err := png.Decode(bytes.NewReader(imageData)); err != nil {
+		t.Fatalf("Generated image is not a PNG: %v", err)
+	}
+
+	if _, err := NewSparklineGenerator(nil).GenerateRatioSparkline(points[:13]); err == nil {
+		t.Error("Expected an error with a single counted data point")
+	}
+}
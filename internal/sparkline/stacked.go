@@ -0,0 +1,130 @@
+package sparkline
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// stackedChart describes a stacked share chart: each point's shares are drawn as bands
+// filling 0 to 100%, the first band at the bottom
+type stackedChart struct {
+	title  string
+	colors []color.RGBA
+	names  []string
+	// shares returns a point's band values in stacking order; they are scaled to sum to 1
+	shares func(state.SentimentDataPoint) []float64
+}
+
+// generateStackedSparkline draws chart over points, which must all have shares
+func (sg *SparklineGenerator) generateStackedSparkline(chart stackedChart, points []state.SentimentDataPoint) ([]byte, error) {
+	font, err := loadFont(sg.config.FontPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart font: %w", err)
+	}
+
+	dc := newCanvas(sg.config.Width, sg.config.Height, sg.config.RenderScale, font)
+	dc.SetColor(sg.config.Background)
+	dc.Clear()
+
+	// Same drawing area as the weekly chart
+	drawX := float64(sg.config.Padding + 50)
+	drawY := float64(sg.config.Padding)
+	drawWidth := float64(sg.config.Width-sg.config.Padding) - drawX
+	drawHeight := float64(sg.config.Height-sg.config.Padding-20) - drawY
+
+	startTime := points[0].Timestamp
+	timeRange := points[len(points)-1].Timestamp.Sub(startTime).Seconds()
+	xAt := func(i int) float64 {
+		return drawX + points[i].Timestamp.Sub(startTime).Seconds()/timeRange*drawWidth
+	}
+	// yAt is the top of band for point i: the sum of its shares up to and including band
+	yAt := func(i, band int) float64 {
+		total, cumulative := 0.0, 0.0
+		for b, share := range chart.shares(points[i]) {
+			total += share
+			if b <= band {
+				cumulative += share
+			}
+		}
+		if total > 0 {
+			cumulative /= total
+		}
+		return drawY + drawHeight - cumulative*drawHeight
+	}
+
+	// Each band is filled between its own top and the top of the band below it
+	for band := range chart.colors {
+		dc.SetColor(chart.colors[band])
+		dc.MoveTo(xAt(0), yAt(0, band))
+		for i := 1; i < len(points); i++ {
+			dc.LineTo(xAt(i), yAt(i, band))
+		}
+		for i := len(points) - 1; i >= 0; i-- {
+			if band == 0 {
+				dc.LineTo(xAt(i), drawY+drawHeight)
+			} else {
+				dc.LineTo(xAt(i), yAt(i, band-1))
+			}
+		}
+		dc.ClosePath()
+		dc.Fill()
+	}
+
+	// Share gridlines over the bands
+	dc.SetLineWidth(0.5)
+	for _, level := range []float64{0, 25, 50, 75, 100} {
+		yPos := drawY + drawHeight - level/100*drawHeight
+		dc.SetColor(sg.config.GridColor)
+		dc.DrawLine(drawX, yPos, drawX+drawWidth, yPos)
+		dc.Stroke()
+		dc.SetColor(sg.config.TextColor)
+		dc.SetFontSize(12)
+		dc.DrawStringAnchored(fmt.Sprintf("%.0f%%", level), drawX-15, yPos, 1, 0.5)
+	}
+
+	if timeRange >= 24*60*60 {
+		sg.drawDayMarkers(dc, points, drawX, drawY, drawWidth, drawHeight)
+	}
+
+	dc.SetColor(sg.config.TextColor)
+	dc.SetFontSize(14)
+	dc.DrawStringAnchored(chart.title, drawX+drawWidth/2, drawY-10, 0.5, 0)
+
+	sg.drawStackedLegend(dc, chart, points[len(points)-1], drawX+drawWidth-10, drawY+10)
+	sg.drawBrandingWatermark(dc, drawX, drawY, drawWidth, drawHeight)
+
+	return dc.EncodePNG()
+}
+
+// drawStackedLegend lists each band's swatch and latest share, top right of the chart and
+// top-down in the order the bands stack bottom-up, so the two read the same way
+func (sg *SparklineGenerator) drawStackedLegend(dc *canvas, chart stackedChart, latest state.SentimentDataPoint, right, y float64) {
+	const rowHeight, swatch, width = 20.0, 12.0, 120.0
+
+	shares := chart.shares(latest)
+	total := 0.0
+	for _, share := range shares {
+		total += share
+	}
+	if total == 0 {
+		total = 1
+	}
+	dc.SetFontSize(12)
+	for row := 0; row < len(shares); row++ {
+		band := len(shares) - 1 - row
+		rowY := y + float64(row)*rowHeight
+
+		dc.SetColor(sg.config.Background)
+		dc.DrawRectangle(right-width-4, rowY-3, width+4, rowHeight)
+		dc.Fill()
+
+		dc.SetColor(chart.colors[band])
+		dc.DrawRectangle(right-width, rowY, swatch, swatch)
+		dc.Fill()
+
+		dc.SetColor(sg.config.TextColor)
+		dc.DrawStringAnchored(fmt.Sprintf("%s %.0f%%", chart.names[band], shares[band]/total*100), right-width+swatch+6, rowY+swatch/2, 0, 0.5)
+	}
+}
@@ -1,7 +1,6 @@
 package sparkline
 
 import (
-	"bytes"
 	"fmt"
 	"image/color"
 	"math"
@@ -9,7 +8,6 @@ import (
 	"time"
 
 	"github.com/christophergentle/hourstats-bsky/internal/state"
-	"github.com/fogleman/gg"
 )
 
 // YearlySparklineConfig holds configuration for yearly sparkline generation
@@ -25,6 +23,20 @@ type YearlySparklineConfig struct {
 	NeutralLine  color.RGBA
 	GridColor    color.RGBA
 	TextColor    color.RGBA
+
+	// RenderScale renders the chart this many times larger, then downscales it for
+	// smoother lines and text; 1 (or 0) renders at the output size
+	RenderScale float64
+
+	// FontPath is a TrueType or OpenType font for chart text; empty uses the embedded Go
+	// Regular font. Localized labels need a font that covers their script.
+	FontPath string
+	// Labels are the month, weekday and caption text drawn on the chart; nil uses English
+	Labels *Labels
+
+	// MovingAverageDays draws a trailing moving average over this many days; 0 disables it
+	MovingAverageDays int
+	MovingAverageLine color.RGBA
 }
 
 // YearlyYRange represents the Y-axis range for the yearly sparkline
@@ -36,7 +48,7 @@ type YearlyYRange struct {
 }
 
 // calculateYearlyYRange calculates the Y-axis range based on actual yearly data
-func (yg *YearlySparklineGenerator) calculateYearlyYRange(dataPoints []state.YearlySparklineDataPoint) YearlyYRange {
+func (yg *RangeChartGenerator) calculateYearlyYRange(dataPoints []state.YearlySparklineDataPoint) YearlyYRange {
 	if len(dataPoints) == 0 {
 		return YearlyYRange{Min: -100, Max: 100, Center: 0, Scale: 1.0}
 	}
@@ -89,30 +101,39 @@ func DefaultYearlyConfig() *YearlySparklineConfig {
 		NeutralLine:  color.RGBA{108, 117, 125, 255}, // Gray
 		GridColor:    color.RGBA{200, 200, 200, 255}, // Light gray
 		TextColor:    color.RGBA{33, 37, 41, 255},    // Dark gray
+		RenderScale:  2,                              // Render at 2x for crisp text on high density screens
+
+		MovingAverageLine: color.RGBA{111, 66, 193, 220}, // Purple
 	}
 }
 
-// YearlySparklineGenerator generates yearly sentiment sparkline images
-type YearlySparklineGenerator struct {
+// RangeChartGenerator generates charts of daily or weekly sentiment over a date range, such
+// as the yearly chart
+type RangeChartGenerator struct {
 	config *YearlySparklineConfig
+	labels *Labels
+	// title heads the chart, before its date range
+	title string
 }
 
 // NewYearlySparklineGenerator creates a new yearly sparkline generator
-func NewYearlySparklineGenerator(config *YearlySparklineConfig) *YearlySparklineGenerator {
-	if config == nil {
-		config = DefaultYearlyConfig()
-	}
-	return &YearlySparklineGenerator{config: config}
+func NewYearlySparklineGenerator(config *YearlySparklineConfig) *RangeChartGenerator {
+	return NewRangeChartGenerator(config, "")
 }
 
 // GenerateYearlySentimentSparkline creates a PNG image of yearly sentiment data
-func (yg *YearlySparklineGenerator) GenerateYearlySentimentSparkline(dataPoints []state.YearlySparklineDataPoint) ([]byte, error) {
+func (yg *RangeChartGenerator) GenerateYearlySentimentSparkline(dataPoints []state.YearlySparklineDataPoint) ([]byte, error) {
 	if len(dataPoints) == 0 {
 		return nil, fmt.Errorf("no data points provided")
 	}
 
+	font, err := loadFont(yg.config.FontPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart font: %w", err)
+	}
+
 	// Create image context
-	dc := gg.NewContext(yg.config.Width, yg.config.Height)
+	dc := newCanvas(yg.config.Width, yg.config.Height, yg.config.RenderScale, font)
 
 	// Fill background
 	dc.SetColor(yg.config.Background)
@@ -144,6 +165,9 @@ func (yg *YearlySparklineGenerator) GenerateYearlySentimentSparkline(dataPoints
 	// Draw Gaussian smoothed trend line
 	yg.drawYearlyGaussianTrendLine(dc, dataPoints, drawX, drawY, drawWidth, drawHeight, yRange)
 
+	// Draw moving average line if enabled
+	yg.drawYearlyMovingAverageLine(dc, dataPoints, drawX, drawY, drawWidth, drawHeight, yRange)
+
 	// Draw average line
 	yg.drawYearlyAverageLine(dc, dataPoints, drawX, drawY, drawWidth, drawHeight, yRange)
 
@@ -159,16 +183,12 @@ func (yg *YearlySparklineGenerator) GenerateYearlySentimentSparkline(dataPoints
 	// Draw branding watermark
 	yg.drawYearlyBrandingWatermark(dc, drawX, drawY, drawWidth, drawHeight)
 
-	// Encode as PNG
-	var buf bytes.Buffer
-	if err := dc.EncodePNG(&buf); err != nil {
-		return nil, fmt.Errorf("failed to encode PNG: %w", err)
-	}
-	return buf.Bytes(), nil
+	// Encode as PNG, downscaling from the render resolution
+	return dc.EncodePNG()
 }
 
 // drawYearlyGrid draws grid lines and axes for yearly view
-func (yg *YearlySparklineGenerator) drawYearlyGrid(dc *gg.Context, x, y, width, height float64, yRange YearlyYRange) {
+func (yg *RangeChartGenerator) drawYearlyGrid(dc *canvas, x, y, width, height float64, yRange YearlyYRange) {
 	dc.SetColor(yg.config.GridColor)
 	dc.SetLineWidth(0.5)
 
@@ -203,7 +223,7 @@ func (yg *YearlySparklineGenerator) drawYearlyGrid(dc *gg.Context, x, y, width,
 }
 
 // drawYearlyNeutralZone draws a light gray background area for the neutral sentiment zone
-func (yg *YearlySparklineGenerator) drawYearlyNeutralZone(dc *gg.Context, x, y, width, height float64, yRange YearlyYRange) {
+func (yg *RangeChartGenerator) drawYearlyNeutralZone(dc *canvas, x, y, width, height float64, yRange YearlyYRange) {
 	// Define neutral zone boundaries
 	neutralMin := -10.0
 	neutralMax := 10.0
@@ -236,7 +256,7 @@ func (yg *YearlySparklineGenerator) drawYearlyNeutralZone(dc *gg.Context, x, y,
 }
 
 // drawYearlyNeutralWatermark draws a "Neutral" watermark
-func (yg *YearlySparklineGenerator) drawYearlyNeutralWatermark(dc *gg.Context, x, y, width, height float64) {
+func (yg *RangeChartGenerator) drawYearlyNeutralWatermark(dc *canvas, x, y, width, height float64) {
 	if height < 50 || width < 200 {
 		return
 	}
@@ -249,21 +269,17 @@ func (yg *YearlySparklineGenerator) drawYearlyNeutralWatermark(dc *gg.Context, x
 		fontSize = 20
 	}
 
-	if err := dc.LoadFontFace("/System/Library/Fonts/Geneva.ttf", fontSize); err != nil {
-		if fallbackErr := dc.LoadFontFace("", fontSize); fallbackErr != nil {
-			_ = fallbackErr
-		}
-	}
+	dc.SetFontSize(fontSize)
 
 	centerX := x + width/2
 	centerY := y + height/2
 
 	dc.SetColor(color.RGBA{200, 200, 200, 30})
-	dc.DrawStringAnchored("Neutral", centerX, centerY, 0.5, 0.5)
+	dc.DrawStringAnchored(yg.labels.Neutral, centerX, centerY, 0.5, 0.5)
 }
 
 // drawYearlySentimentWatermarks draws "Positive" and "Negative" watermarks
-func (yg *YearlySparklineGenerator) drawYearlySentimentWatermarks(dc *gg.Context, x, y, width, height float64, yRange YearlyYRange) {
+func (yg *RangeChartGenerator) drawYearlySentimentWatermarks(dc *canvas, x, y, width, height float64, yRange YearlyYRange) {
 	fontSize := height * 0.15
 	if fontSize > 40 {
 		fontSize = 40
@@ -272,11 +288,7 @@ func (yg *YearlySparklineGenerator) drawYearlySentimentWatermarks(dc *gg.Context
 		fontSize = 16
 	}
 
-	if err := dc.LoadFontFace("/System/Library/Fonts/Geneva.ttf", fontSize); err != nil {
-		if fallbackErr := dc.LoadFontFace("", fontSize); fallbackErr != nil {
-			_ = fallbackErr
-		}
-	}
+	dc.SetFontSize(fontSize)
 
 	// Draw "Positive" watermark
 	positiveThreshold := 10.0
@@ -287,7 +299,7 @@ func (yg *YearlySparklineGenerator) drawYearlySentimentWatermarks(dc *gg.Context
 		if positiveY < y+height-50 {
 			positiveCenterY := (positiveY + y) / 2
 			dc.SetColor(color.RGBA{40, 167, 69, 60})
-			dc.DrawStringAnchored("Positive", x+width/2, positiveCenterY, 0.5, 0.5)
+			dc.DrawStringAnchored(yg.labels.Positive, x+width/2, positiveCenterY, 0.5, 0.5)
 		}
 	}
 
@@ -300,20 +312,16 @@ func (yg *YearlySparklineGenerator) drawYearlySentimentWatermarks(dc *gg.Context
 		if negativeY > y+50 {
 			negativeCenterY := (negativeY + y + height) / 2
 			dc.SetColor(color.RGBA{220, 53, 69, 60})
-			dc.DrawStringAnchored("Negative", x+width/2, negativeCenterY, 0.5, 0.5)
+			dc.DrawStringAnchored(yg.labels.Negative, x+width/2, negativeCenterY, 0.5, 0.5)
 		}
 	}
 }
 
 // drawYearlyBrandingWatermark draws "@hourstats.bsky.social" branding
-func (yg *YearlySparklineGenerator) drawYearlyBrandingWatermark(dc *gg.Context, x, y, width, height float64) {
+func (yg *RangeChartGenerator) drawYearlyBrandingWatermark(dc *canvas, x, y, width, height float64) {
 	fontSize := 12.0
 
-	if err := dc.LoadFontFace("/System/Library/Fonts/Geneva.ttf", fontSize); err != nil {
-		if fallbackErr := dc.LoadFontFace("", fontSize); fallbackErr != nil {
-			_ = fallbackErr
-		}
-	}
+	dc.SetFontSize(fontSize)
 
 	brandX := x + 10
 	brandY := y + height - 10
@@ -323,7 +331,7 @@ func (yg *YearlySparklineGenerator) drawYearlyBrandingWatermark(dc *gg.Context,
 }
 
 // drawYearlySentimentLine draws the sentiment line with appropriate colors
-func (yg *YearlySparklineGenerator) drawYearlySentimentLine(dc *gg.Context, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64, yRange YearlyYRange) {
+func (yg *RangeChartGenerator) drawYearlySentimentLine(dc *canvas, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64, yRange YearlyYRange) {
 	if len(dataPoints) < 2 {
 		return
 	}
@@ -356,16 +364,36 @@ func (yg *YearlySparklineGenerator) drawYearlySentimentLine(dc *gg.Context, data
 			lineColor = yg.config.NeutralLine
 		}
 
+		// Draw point first, as it would end a dashed path; interpolated days get none,
+		// as nothing was measured then
+		if !current.Synthetic {
+			dc.SetColor(lineColor)
+			dc.DrawCircle(x1, y1, yg.config.PointRadius)
+			dc.Fill()
+		}
+
+		// A stretch of interpolated days is stroked as one grey dashed path, so the dashes
+		// carry on across its short segments
+		if current.Synthetic || next.Synthetic {
+			if !current.Synthetic {
+				dc.MoveTo(x1, y1)
+			}
+			dc.LineTo(x2, y2)
+			if !next.Synthetic {
+				dc.SetColor(yg.config.NeutralLine)
+				dc.SetLineWidth(yg.config.LineWidth)
+				dc.SetDash(8, 6)
+				dc.Stroke()
+				dc.SetDash()
+			}
+			continue
+		}
+
 		// Draw line segment
 		dc.SetColor(lineColor)
 		dc.SetLineWidth(yg.config.LineWidth)
 		dc.DrawLine(x1, y1, x2, y2)
 		dc.Stroke()
-
-		// Draw point
-		dc.SetColor(lineColor)
-		dc.DrawCircle(x1, y1, yg.config.PointRadius)
-		dc.Fill()
 	}
 
 	// Draw final point
@@ -388,18 +416,31 @@ func (yg *YearlySparklineGenerator) drawYearlySentimentLine(dc *gg.Context, data
 	dc.Fill()
 }
 
+// observedDays drops the days InterpolateDailyGaps made up, for the average and extremes.
+// The first and last days are always measured.
+func observedDays(dataPoints []state.YearlySparklineDataPoint) []state.YearlySparklineDataPoint {
+	observed := make([]state.YearlySparklineDataPoint, 0, len(dataPoints))
+	for _, dp := range dataPoints {
+		if !dp.Synthetic {
+			observed = append(observed, dp)
+		}
+	}
+	return observed
+}
+
 // drawYearlyAverageLine draws a dark grey dotted horizontal line showing the average sentiment
-func (yg *YearlySparklineGenerator) drawYearlyAverageLine(dc *gg.Context, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64, yRange YearlyYRange) {
+func (yg *RangeChartGenerator) drawYearlyAverageLine(dc *canvas, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64, yRange YearlyYRange) {
 	if len(dataPoints) == 0 {
 		return
 	}
 
-	// Calculate the average sentiment
+	// Calculate the average sentiment of the measured days
+	observed := observedDays(dataPoints)
 	var sum float64
-	for _, dp := range dataPoints {
+	for _, dp := range observed {
 		sum += dp.AverageSentiment
 	}
-	average := sum / float64(len(dataPoints))
+	average := sum / float64(len(observed))
 
 	// Convert average to Y position
 	normalizedAverage := (average - yRange.Center) * yRange.Scale / 100.0
@@ -430,14 +471,10 @@ func (yg *YearlySparklineGenerator) drawYearlyAverageLine(dc *gg.Context, dataPo
 }
 
 // drawYearlyLabels draws time and sentiment labels for yearly view
-func (yg *YearlySparklineGenerator) drawYearlyLabels(dc *gg.Context, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64, yRange YearlyYRange) {
+func (yg *RangeChartGenerator) drawYearlyLabels(dc *canvas, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64, yRange YearlyYRange) {
 	dc.SetColor(yg.config.TextColor)
 
-	if err := dc.LoadFontFace("/System/Library/Fonts/Geneva.ttf", 12); err != nil {
-		if fallbackErr := dc.LoadFontFace("/System/Library/Fonts/Symbol.ttf", 12); fallbackErr != nil {
-			_ = fallbackErr
-		}
-	}
+	dc.SetFontSize(12)
 
 	// Draw sentiment level labels
 	levels := []struct {
@@ -471,34 +508,27 @@ func (yg *YearlySparklineGenerator) drawYearlyLabels(dc *gg.Context, dataPoints
 	yg.drawYearlyStartEndLabels(dc, dataPoints, x, y, width, height)
 
 	// Draw title with date range - use large font (32pt, doubled from original 16)
-	// Explicitly load font before drawing to ensure it's applied
 	titleFontSize := 32.0
-	if err := dc.LoadFontFace("/System/Library/Fonts/Geneva.ttf", titleFontSize); err != nil {
-		// Try fallback fonts if Geneva fails
-		if fallbackErr := dc.LoadFontFace("/System/Library/Fonts/Helvetica.ttc", titleFontSize); fallbackErr != nil {
-			if fallbackErr2 := dc.LoadFontFace("", titleFontSize); fallbackErr2 != nil {
-				_ = fallbackErr2
-			}
-		}
-	}
+	dc.SetFontSize(titleFontSize)
 	// Set text color for title
 	dc.SetColor(yg.config.TextColor)
 	if len(dataPoints) > 0 {
-		startDate := dataPoints[0].Timestamp.Format("2006-01-02")
-		endDate := dataPoints[len(dataPoints)-1].Timestamp.Format("2006-01-02")
-		title := fmt.Sprintf("Bluesky Sentiment %s - %s", startDate, endDate)
+		startDate := yg.labels.Date(dataPoints[0].Timestamp)
+		endDate := yg.labels.Date(dataPoints[len(dataPoints)-1].Timestamp)
+		title := fmt.Sprintf("%s %s - %s", yg.title, startDate, endDate)
 		// Position title higher to accommodate larger font
 		dc.DrawStringAnchored(title, x+width/2, y-15, 0.5, 0)
 	} else {
-		dc.DrawStringAnchored("Bluesky Sentiment", x+width/2, y-15, 0.5, 0)
+		dc.DrawStringAnchored(yg.title, x+width/2, y-15, 0.5, 0)
 	}
 
 	// Draw average line label
 	yg.drawYearlyAverageLabel(dc, dataPoints, x, y, width, height, yRange)
 }
 
-// drawYearlyMonthMarkers draws month markers for yearly view
-func (yg *YearlySparklineGenerator) drawYearlyMonthMarkers(dc *gg.Context, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64) {
+// drawYearlyMonthMarkers draws a gridline at each month boundary and labels each month
+// in the middle of its span, so 365 daily points can be read against the calendar
+func (yg *RangeChartGenerator) drawYearlyMonthMarkers(dc *canvas, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64) {
 	if len(dataPoints) == 0 {
 		return
 	}
@@ -506,41 +536,58 @@ func (yg *YearlySparklineGenerator) drawYearlyMonthMarkers(dc *gg.Context, dataP
 	startTime := dataPoints[0].Timestamp
 	endTime := dataPoints[len(dataPoints)-1].Timestamp
 	timeRange := endTime.Sub(startTime).Seconds()
+	if timeRange <= 0 {
+		return
+	}
 
 	// Find all month boundaries within the data range
 	monthPositions := yg.findYearlyMonthPositions(startTime, endTime)
 
-	// Draw vertical lines and labels for each month
-	for _, monthTime := range monthPositions {
-		// Calculate x position for this month
+	dc.SetFontSize(12)
+
+	for i, monthTime := range monthPositions {
+		// Calculate x position for this month boundary
 		xPos := x + (monthTime.Sub(startTime).Seconds()/timeRange)*width
 
-		// Only draw if within the visible range
-		if xPos >= x && xPos <= x+width {
-			// Draw vertical line for month boundary
+		// Draw the boundary gridline if it falls inside the chart
+		if xPos > x && xPos < x+width {
 			dc.SetColor(yg.config.GridColor)
 			dc.SetLineWidth(0.5)
 			dc.DrawLine(xPos, y, xPos, y+height)
 			dc.Stroke()
+		}
+
+		if i == len(monthPositions)-1 {
+			break
+		}
 
-			// Draw month label below the chart
-			monthLabel := monthTime.Format("Jan")
-			dc.SetColor(yg.config.TextColor)
-			dc.DrawStringAnchored(monthLabel, xPos, y+height+15, 0.5, 0)
+		// Label the visible part of the month, skipping slivers too narrow for the name
+		spanStart := math.Max(xPos, x)
+		spanEnd := math.Min(x+(monthPositions[i+1].Sub(startTime).Seconds()/timeRange)*width, x+width)
+		if spanEnd-spanStart < minMonthLabelWidth {
+			continue
 		}
+
+		// Month names sit below the rotated biweekly date labels
+		monthLabel := yg.labels.Month(monthTime)
+		dc.SetColor(yg.config.TextColor)
+		dc.DrawStringAnchored(monthLabel, (spanStart+spanEnd)/2, y+height+60, 0.5, 0)
 	}
 }
 
+// minMonthLabelWidth is the narrowest month span, in pixels, that gets a label
+const minMonthLabelWidth = 30.0
+
 // findYearlyMonthPositions finds all month boundary positions within the given time range
-func (yg *YearlySparklineGenerator) findYearlyMonthPositions(startTime, endTime time.Time) []time.Time {
+func (yg *RangeChartGenerator) findYearlyMonthPositions(startTime, endTime time.Time) []time.Time {
 	var months []time.Time
 
 	// Start from the first day of the month containing startTime
 	// Always include this month marker for chart readability
-	firstMonth := time.Date(startTime.Year(), startTime.Month(), 1, 0, 0, 0, 0, time.UTC)
+	firstMonth := time.Date(startTime.UTC().Year(), startTime.UTC().Month(), 1, 0, 0, 0, 0, time.UTC)
 	
 	// End at the first day of the month after endTime (to include the endTime's month)
-	endMonth := time.Date(endTime.Year(), endTime.Month(), 1, 0, 0, 0, 0, time.UTC)
+	endMonth := time.Date(endTime.UTC().Year(), endTime.UTC().Month(), 1, 0, 0, 0, 0, time.UTC)
 	// Include the month after endTime as well for better context
 	endMonth = endMonth.AddDate(0, 1, 0)
 
@@ -553,7 +600,7 @@ func (yg *YearlySparklineGenerator) findYearlyMonthPositions(startTime, endTime
 }
 
 // drawYearlyBiweeklyTicks draws biweekly (every 2 weeks) date ticks for yearly view
-func (yg *YearlySparklineGenerator) drawYearlyBiweeklyTicks(dc *gg.Context, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64) {
+func (yg *RangeChartGenerator) drawYearlyBiweeklyTicks(dc *canvas, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64) {
 	if len(dataPoints) == 0 {
 		return
 	}
@@ -572,12 +619,8 @@ func (yg *YearlySparklineGenerator) drawYearlyBiweeklyTicks(dc *gg.Context, data
 		monthPosSet[monthTime.Format("2006-01-02")] = true
 	}
 
-	// Load smaller font for date labels
-	if err := dc.LoadFontFace("/System/Library/Fonts/Geneva.ttf", 10); err != nil {
-		if fallbackErr := dc.LoadFontFace("/System/Library/Fonts/Symbol.ttf", 10); fallbackErr != nil {
-			_ = fallbackErr
-		}
-	}
+	// Smaller font for date labels
+	dc.SetFontSize(10)
 
 	// Draw vertical lines and date labels for each biweekly position
 	for _, biweeklyTime := range biweeklyPositions {
@@ -600,7 +643,7 @@ func (yg *YearlySparklineGenerator) drawYearlyBiweeklyTicks(dc *gg.Context, data
 
 			// Draw date label below the chart, rotated 90 degrees clockwise
 			// Format: "15 Oct" (day month)
-			dateLabel := biweeklyTime.Format("2 Jan") // Format: "15 Oct"
+			dateLabel := yg.labels.DayMonth(biweeklyTime) // Format: "15 Oct"
 			dc.SetColor(color.RGBA{120, 120, 120, 255}) // Darker gray for text
 			
 			// Position for the label (below the chart)
@@ -623,12 +666,12 @@ func (yg *YearlySparklineGenerator) drawYearlyBiweeklyTicks(dc *gg.Context, data
 }
 
 // findYearlyBiweeklyPositions finds all biweekly (every 14 days) positions within the given time range
-func (yg *YearlySparklineGenerator) findYearlyBiweeklyPositions(startTime, endTime time.Time) []time.Time {
+func (yg *RangeChartGenerator) findYearlyBiweeklyPositions(startTime, endTime time.Time) []time.Time {
 	var biweeklyPositions []time.Time
 
 	// Start from startTime, then add 14 days for each biweekly tick
 	// We want ticks approximately every 2 weeks from the start
-	firstBiweekly := startTime.Truncate(24 * time.Hour) // Round to midnight
+	firstBiweekly := startTime.UTC().Truncate(24 * time.Hour) // Round to midnight
 	
 	// Find the first biweekly position (could be startTime or up to 14 days later)
 	// We'll align to approximate 14-day intervals
@@ -643,7 +686,7 @@ func (yg *YearlySparklineGenerator) findYearlyBiweeklyPositions(startTime, endTi
 }
 
 // drawYearlyWeeklyTicks draws weekly (every 7 days) date ticks for yearly view
-func (yg *YearlySparklineGenerator) drawYearlyWeeklyTicks(dc *gg.Context, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64) {
+func (yg *RangeChartGenerator) drawYearlyWeeklyTicks(dc *canvas, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64) {
 	if len(dataPoints) == 0 {
 		return
 	}
@@ -689,11 +732,11 @@ func (yg *YearlySparklineGenerator) drawYearlyWeeklyTicks(dc *gg.Context, dataPo
 }
 
 // findYearlyWeeklyPositions finds all weekly (every 7 days) positions within the given time range
-func (yg *YearlySparklineGenerator) findYearlyWeeklyPositions(startTime, endTime time.Time) []time.Time {
+func (yg *RangeChartGenerator) findYearlyWeeklyPositions(startTime, endTime time.Time) []time.Time {
 	var weeklyPositions []time.Time
 
 	// Start from startTime, then add 7 days for each weekly tick
-	firstWeekly := startTime.Truncate(24 * time.Hour) // Round to midnight
+	firstWeekly := startTime.UTC().Truncate(24 * time.Hour) // Round to midnight
 	
 	// Find all weekly positions (every 7 days)
 	for current := firstWeekly; !current.After(endTime); current = current.AddDate(0, 0, 7) {
@@ -707,7 +750,7 @@ func (yg *YearlySparklineGenerator) findYearlyWeeklyPositions(startTime, endTime
 }
 
 // drawYearlyStartEndLabels draws start and end date labels on the x-axis
-func (yg *YearlySparklineGenerator) drawYearlyStartEndLabels(dc *gg.Context, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64) {
+func (yg *RangeChartGenerator) drawYearlyStartEndLabels(dc *canvas, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64) {
 	if len(dataPoints) == 0 {
 		return
 	}
@@ -715,43 +758,35 @@ func (yg *YearlySparklineGenerator) drawYearlyStartEndLabels(dc *gg.Context, dat
 	startTime := dataPoints[0].Timestamp
 	endTime := dataPoints[len(dataPoints)-1].Timestamp
 
-	// Load font for labels
-	if err := dc.LoadFontFace("/System/Library/Fonts/Geneva.ttf", 10); err != nil {
-		if fallbackErr := dc.LoadFontFace("/System/Library/Fonts/Symbol.ttf", 10); fallbackErr != nil {
-			_ = fallbackErr
-		}
-	}
+	// Font for labels
+	dc.SetFontSize(10)
 
-	// Draw start date label at the left edge (50 pixels below chart to avoid overlap)
-	startLabel := startTime.Format("2 Jan") // Format: "18 Sep"
+	// Draw start date label at the left edge (80 pixels below chart, under the month names)
+	startLabel := yg.labels.DayMonth(startTime) // Format: "18 Sep"
 	dc.SetColor(color.RGBA{80, 80, 80, 255}) // Dark gray for start/end labels
-	dc.DrawStringAnchored(startLabel, x, y+height+50, 0, 0)
+	dc.DrawStringAnchored(startLabel, x, y+height+80, 0, 0)
 
-	// Draw end date label at the right edge (50 pixels below chart to avoid overlap)
-	endLabel := endTime.Format("2 Jan") // Format: "31 Oct"
+	// Draw end date label at the right edge (80 pixels below chart, under the month names)
+	endLabel := yg.labels.DayMonth(endTime) // Format: "31 Oct"
 	dc.SetColor(color.RGBA{80, 80, 80, 255})
-	dc.DrawStringAnchored(endLabel, x+width, y+height+50, 1, 0)
+	dc.DrawStringAnchored(endLabel, x+width, y+height+80, 1, 0)
 }
 
 // drawYearlyExtremeLabels draws labels for the highest and lowest sentiment points
-func (yg *YearlySparklineGenerator) drawYearlyExtremeLabels(dc *gg.Context, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64, yRange YearlyYRange) {
+func (yg *RangeChartGenerator) drawYearlyExtremeLabels(dc *canvas, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64, yRange YearlyYRange) {
 	if len(dataPoints) == 0 {
 		return
 	}
 
-	// Load font for labels
-	if err := dc.LoadFontFace("/System/Library/Fonts/Geneva.ttf", 11); err != nil {
-		if fallbackErr := dc.LoadFontFace("/System/Library/Fonts/Symbol.ttf", 11); fallbackErr != nil {
-			_ = fallbackErr
-		}
-	}
+	// Font for labels
+	dc.SetFontSize(11)
 
-	// Find the lowest and highest sentiment points
+	// Find the lowest and highest sentiment points, leaving out interpolated days
 	var lowest, highest state.YearlySparklineDataPoint
 	lowest = dataPoints[0]
 	highest = dataPoints[0]
 
-	for _, dp := range dataPoints {
+	for _, dp := range observedDays(dataPoints) {
 		if dp.AverageSentiment < lowest.AverageSentiment {
 			lowest = dp
 		}
@@ -778,7 +813,7 @@ func (yg *YearlySparklineGenerator) drawYearlyExtremeLabels(dc *gg.Context, data
 		dc.Fill()
 
 		// Format date as "Jan 2"
-		lowestDateLabel := lowest.Timestamp.Format("Jan 2")
+		lowestDateLabel := yg.labels.MonthDay(lowest.Timestamp)
 		lowestLabel := fmt.Sprintf("%.1f%%\n%s", lowest.AverageSentiment, lowestDateLabel)
 		dc.SetColor(color.RGBA{220, 53, 69, 255}) // Use red color for visibility
 		// Draw label below the point with more spacing
@@ -799,7 +834,7 @@ func (yg *YearlySparklineGenerator) drawYearlyExtremeLabels(dc *gg.Context, data
 			dc.Fill()
 
 			// Format date as "Jan 2"
-			highestDateLabel := highest.Timestamp.Format("Jan 2")
+			highestDateLabel := yg.labels.MonthDay(highest.Timestamp)
 			highestLabel := fmt.Sprintf("%.1f%%\n%s", highest.AverageSentiment, highestDateLabel)
 			dc.SetColor(color.RGBA{40, 167, 69, 255}) // Use green color for visibility
 			// Draw label above the point with more spacing
@@ -809,7 +844,7 @@ func (yg *YearlySparklineGenerator) drawYearlyExtremeLabels(dc *gg.Context, data
 }
 
 // drawYearlyMultilineStringAnchored draws multi-line text with proper anchoring for yearly view
-func (yg *YearlySparklineGenerator) drawYearlyMultilineStringAnchored(dc *gg.Context, text string, x, y, anchorX, anchorY float64) {
+func (yg *RangeChartGenerator) drawYearlyMultilineStringAnchored(dc *canvas, text string, x, y, anchorX, anchorY float64) {
 	lines := strings.Split(text, "\n")
 	lineHeight := 13.0 // Font height for 11pt font
 
@@ -834,17 +869,18 @@ func (yg *YearlySparklineGenerator) drawYearlyMultilineStringAnchored(dc *gg.Con
 }
 
 // drawYearlyAverageLabel draws a label for the average line
-func (yg *YearlySparklineGenerator) drawYearlyAverageLabel(dc *gg.Context, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64, yRange YearlyYRange) {
+func (yg *RangeChartGenerator) drawYearlyAverageLabel(dc *canvas, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64, yRange YearlyYRange) {
 	if len(dataPoints) == 0 {
 		return
 	}
 
-	// Calculate the average sentiment
+	// Calculate the average sentiment of the measured days
+	observed := observedDays(dataPoints)
 	var sum float64
-	for _, dp := range dataPoints {
+	for _, dp := range observed {
 		sum += dp.AverageSentiment
 	}
-	average := sum / float64(len(dataPoints))
+	average := sum / float64(len(observed))
 
 	// Convert average to Y position
 	normalizedAverage := (average - yRange.Center) * yRange.Scale / 100.0
@@ -853,11 +889,7 @@ func (yg *YearlySparklineGenerator) drawYearlyAverageLabel(dc *gg.Context, dataP
 	// Only draw if the average line is within the visible range
 	if yPos >= y && yPos <= y+height {
 		// Use same font size as extreme labels (11pt)
-		if err := dc.LoadFontFace("/System/Library/Fonts/Geneva.ttf", 11); err != nil {
-			if fallbackErr := dc.LoadFontFace("/System/Library/Fonts/Symbol.ttf", 11); fallbackErr != nil {
-				_ = fallbackErr
-			}
-		}
+		dc.SetFontSize(11)
 		
 		label := fmt.Sprintf("Avg: %.1f%%", average)
 		dc.SetColor(yg.config.TextColor)
@@ -893,7 +925,7 @@ func yearlyGaussianSmoothing(data []float64, sigma float64) []float64 {
 }
 
 // drawYearlyGaussianTrendLine draws a thin dashed blue Gaussian smoothed trend line
-func (yg *YearlySparklineGenerator) drawYearlyGaussianTrendLine(dc *gg.Context, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64, yRange YearlyYRange) {
+func (yg *RangeChartGenerator) drawYearlyGaussianTrendLine(dc *canvas, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64, yRange YearlyYRange) {
 	if len(dataPoints) < 2 {
 		return
 	}
@@ -932,3 +964,62 @@ func (yg *YearlySparklineGenerator) drawYearlyGaussianTrendLine(dc *gg.Context,
 
 	dc.SetDash() // Reset dash pattern
 }
+
+// yearlyMovingAverage returns the trailing average of each point over the preceding days,
+// including the point itself. The window is measured by timestamp, so missing days shorten
+// it rather than reaching further back.
+func yearlyMovingAverage(dataPoints []state.YearlySparklineDataPoint, days int) []float64 {
+	averages := make([]float64, len(dataPoints))
+	window := time.Duration(days) * 24 * time.Hour
+
+	sum := 0.0
+	first := 0
+	for i, dp := range dataPoints {
+		sum += dp.AverageSentiment
+		for dp.Timestamp.Sub(dataPoints[first].Timestamp) >= window {
+			sum -= dataPoints[first].AverageSentiment
+			first++
+		}
+		averages[i] = sum / float64(i-first+1)
+	}
+
+	return averages
+}
+
+// drawYearlyMovingAverageLine draws the trailing moving average as a solid line once a full
+// window of data is available
+func (yg *RangeChartGenerator) drawYearlyMovingAverageLine(dc *canvas, dataPoints []state.YearlySparklineDataPoint, x, y, width, height float64, yRange YearlyYRange) {
+	days := yg.config.MovingAverageDays
+	if days <= 0 || len(dataPoints) < 2 {
+		return
+	}
+
+	averages := yearlyMovingAverage(dataPoints, days)
+
+	startTime := dataPoints[0].Timestamp
+	endTime := dataPoints[len(dataPoints)-1].Timestamp
+	timeRange := endTime.Sub(startTime).Seconds()
+
+	// Skip the warm-up period, where the average covers less than a full window
+	firstFull := startTime.AddDate(0, 0, days-1)
+
+	dc.SetColor(yg.config.MovingAverageLine)
+	dc.SetLineWidth(2.5)
+
+	for i := 0; i < len(averages)-1; i++ {
+		if dataPoints[i].Timestamp.Before(firstFull) {
+			continue
+		}
+
+		x1 := x + (dataPoints[i].Timestamp.Sub(startTime).Seconds()/timeRange)*width
+		normalizedY1 := (averages[i] - yRange.Center) * yRange.Scale / 100.0
+		y1 := y + height/2 - normalizedY1*(height/2)
+
+		x2 := x + (dataPoints[i+1].Timestamp.Sub(startTime).Seconds()/timeRange)*width
+		normalizedY2 := (averages[i+1] - yRange.Center) * yRange.Scale / 100.0
+		y2 := y + height/2 - normalizedY2*(height/2)
+
+		dc.DrawLine(x1, y1, x2, y2)
+		dc.Stroke()
+	}
+}
@@ -1,6 +1,7 @@
 package sparkline
 
 import (
+	"bytes"
 	"testing"
 	"time"
 
@@ -203,3 +204,46 @@ func TestGenerateYearlySentimentSparkline_ValidData(t *testing.T) {
 		}
 	}
 }
+
+func TestYearlyMovingAverage(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var dataPoints []state.YearlySparklineDataPoint
+	for day, value := range []float64{10, 20, 30, 40} {
+		dataPoints = append(dataPoints, state.YearlySparklineDataPoint{
+			Timestamp:        start.AddDate(0, 0, day),
+			AverageSentiment: value,
+		})
+	}
+	// A missing day shortens the window instead of reaching further back
+	dataPoints = append(dataPoints, state.YearlySparklineDataPoint{
+		Timestamp:        start.AddDate(0, 0, 5),
+		AverageSentiment: 60,
+	})
+
+	averages := yearlyMovingAverage(dataPoints, 3)
+	expected := []float64{10, 15, 20, 30, 50}
+	for i, want := range expected {
+		if averages[i] != want {
+			t.Errorf("average %d: expected %.1f, got %.1f", i, want, averages[i])
+		}
+	}
+}
+
+func TestYearlyMovingAverageLineDrawn(t *testing.T) {
+	config := DefaultYearlyConfig()
+	config.RenderScale = 1
+	plain, err := NewYearlySparklineGenerator(config).GenerateYearlySentimentSparkline(goldenYear())
+	if err != nil {
+		t.Fatalf("failed to generate yearly sparkline: %v", err)
+	}
+
+	config.MovingAverageDays = 30
+	averaged, err := NewYearlySparklineGenerator(config).GenerateYearlySentimentSparkline(goldenYear())
+	if err != nil {
+		t.Fatalf("failed to generate yearly sparkline with moving average: %v", err)
+	}
+
+	if bytes.Equal(plain, averaged) {
+		t.Error("expected the moving average line to change the rendered chart")
+	}
+}
@@ -0,0 +1,147 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Analyzed posts are stored one item per post so a run's cohorts can be queried directly:
+//
+//	postId = analyzed#<runId>#<sentiment>#<engagement, zero padded>#<cid>
+//
+// begins_with on the sentiment prefix returns one sentiment ordered by engagement, and the
+// sparse engagement-index GSI (runId, engagementScore) answers engagement ranges. The
+// "analyzed#" prefix keeps these items out of GetAllPosts, which reads "<runId>#" keys.
+const (
+	analyzedPostPrefix = "analyzed#"
+	engagementIndex    = "engagement-index"
+)
+
+// AnalyzedPostItem is a single analyzed post stored for per-post queries
+type AnalyzedPostItem struct {
+	RunID           string  `json:"runId" dynamodbav:"runId"`
+	PostID          string  `json:"postId" dynamodbav:"postId"`
+	Sentiment       string  `json:"sentiment" dynamodbav:"sentiment"`
+	EngagementScore float64 `json:"engagementScore" dynamodbav:"engagementScore"`
+	Post            Post    `json:"post" dynamodbav:"post"`
+	CreatedAt       string  `json:"createdAt" dynamodbav:"createdAt"`
+	TTL             int64   `json:"ttl" dynamodbav:"ttl"`
+}
+
+// analyzedSentimentPrefix is the key prefix shared by a run's posts of one sentiment
+func analyzedSentimentPrefix(runID, sentiment string) string {
+	return analyzedPostPrefix + runID + "#" + sentiment + "#"
+}
+
+// analyzedPostKey builds the sort key for an analyzed post; engagement is zero padded so
+// keys sort numerically
+func analyzedPostKey(runID string, post Post) string {
+	id := post.CID
+	if id == "" {
+		id = post.URI
+	}
+	return fmt.Sprintf("%s%015.3f#%s", analyzedSentimentPrefix(runID, post.Sentiment), post.EngagementScore, id)
+}
+
+// StoreAnalyzedPosts writes every analyzed post of a run as its own item, using the
+// state table TTL so they expire with the run
+func (sm *StateManager) StoreAnalyzedPosts(ctx context.Context, runID string, posts []Post) error {
+	createdAt := time.Now().Format(time.RFC3339)
+	ttl := time.Now().Add(sm.ttl).Unix()
+
+	var requests []types.WriteRequest
+	for _, post := range posts {
+		item, err := attributevalue.MarshalMap(AnalyzedPostItem{
+			RunID:           runID,
+			PostID:          analyzedPostKey(runID, post),
+			Sentiment:       post.Sentiment,
+			EngagementScore: post.EngagementScore,
+			Post:            post,
+			CreatedAt:       createdAt,
+			TTL:             ttl,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal analyzed post: %w", err)
+		}
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+	}
+
+	for start := 0; start < len(requests); start += batchWriteItemLimit {
+		end := start + batchWriteItemLimit
+		if end > len(requests) {
+			end = len(requests)
+		}
+		if _, err := sm.batchWrite(ctx, requests[start:end]); err != nil {
+			return fmt.Errorf("failed to store analyzed posts %d-%d of %d: %w", start, end, len(requests), err)
+		}
+	}
+
+	return nil
+}
+
+// GetPostsBySentiment returns up to limit analyzed posts of one sentiment ("positive",
+// "negative" or "neutral") from a run, highest engagement first
+func (sm *StateManager) GetPostsBySentiment(ctx context.Context, runID, sentiment string, limit int32) ([]Post, error) {
+	return sm.queryAnalyzedPosts(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(sm.tableName),
+		KeyConditionExpression: aws.String("runId = :runId AND begins_with(postId, :prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":runId":  &types.AttributeValueMemberS{Value: runID},
+			":prefix": &types.AttributeValueMemberS{Value: analyzedSentimentPrefix(runID, strings.ToLower(sentiment))},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}, limit)
+}
+
+// GetPostsByEngagement returns up to limit analyzed posts from a run whose engagement score
+// lies between minScore and maxScore inclusive, highest engagement first
+func (sm *StateManager) GetPostsByEngagement(ctx context.Context, runID string, minScore, maxScore float64, limit int32) ([]Post, error) {
+	return sm.queryAnalyzedPosts(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(sm.tableName),
+		IndexName:              aws.String(engagementIndex),
+		KeyConditionExpression: aws.String("runId = :runId AND engagementScore BETWEEN :min AND :max"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":runId": &types.AttributeValueMemberS{Value: runID},
+			":min":   &types.AttributeValueMemberN{Value: fmt.Sprintf("%g", minScore)},
+			":max":   &types.AttributeValueMemberN{Value: fmt.Sprintf("%g", maxScore)},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}, limit)
+}
+
+// queryAnalyzedPosts pages through a query until limit posts are collected
+// A limit of zero or less returns every match
+func (sm *StateManager) queryAnalyzedPosts(ctx context.Context, queryInput *dynamodb.QueryInput, limit int32) ([]Post, error) {
+	var posts []Post
+	for {
+		if limit > 0 {
+			queryInput.Limit = aws.Int32(limit - int32(len(posts)))
+		}
+
+		result, err := sm.client.Query(ctx, queryInput)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query analyzed posts: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var analyzed AnalyzedPostItem
+			if err := attributevalue.UnmarshalMap(item, &analyzed); err != nil {
+				continue
+			}
+			posts = append(posts, analyzed.Post)
+		}
+
+		if len(result.LastEvaluatedKey) == 0 || (limit > 0 && int32(len(posts)) >= limit) {
+			break
+		}
+		queryInput.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+	return posts, nil
+}
@@ -0,0 +1,46 @@
+package state
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzedPostKeySortsByEngagement(t *testing.T) {
+	posts := []Post{
+		{CID: "c1", Sentiment: "negative", EngagementScore: 9.5},
+		{CID: "c2", Sentiment: "negative", EngagementScore: 120},
+		{CID: "c3", Sentiment: "negative", EngagementScore: 10},
+	}
+
+	keys := make([]string, len(posts))
+	for i, post := range posts {
+		keys[i] = analyzedPostKey("run-1", post)
+	}
+	sort.Strings(keys)
+
+	expected := []string{"c1", "c3", "c2"}
+	for i, key := range keys {
+		if !strings.HasSuffix(key, "#"+expected[i]) {
+			t.Errorf("key %d = %s, want post %s", i, key, expected[i])
+		}
+	}
+}
+
+func TestAnalyzedPostKeyPrefixes(t *testing.T) {
+	key := analyzedPostKey("run-1", Post{URI: "at://did:plc:x/app.bsky.feed.post/1", Sentiment: "positive", EngagementScore: 3})
+
+	if !strings.HasPrefix(key, analyzedSentimentPrefix("run-1", "positive")) {
+		t.Errorf("key %s should start with the positive sentiment prefix", key)
+	}
+	if strings.HasPrefix(key, analyzedSentimentPrefix("run-1", "neutral")) {
+		t.Errorf("key %s should not match another sentiment", key)
+	}
+	// GetAllPosts reads "<runId>#" keys; analyzed posts must not be counted as fetched posts
+	if strings.HasPrefix(key, "run-1#") {
+		t.Errorf("key %s would be read back by GetAllPosts", key)
+	}
+	if !strings.HasSuffix(key, "#at://did:plc:x/app.bsky.feed.post/1") {
+		t.Errorf("key %s should fall back to the URI when the CID is empty", key)
+	}
+}
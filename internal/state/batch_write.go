@@ -0,0 +1,113 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	// postsPerBatch caps the posts stored in one PostBatch item
+	postsPerBatch = 100
+
+	// maxBatchItemBytes keeps a PostBatch well under DynamoDB's 400KB item limit,
+	// leaving room for attribute names and the estimate's error
+	maxBatchItemBytes = 300 * 1024
+
+	// batchWriteItemLimit is the most requests a single BatchWriteItem call accepts
+	batchWriteItemLimit = 25
+
+	// maxBatchWriteAttempts bounds the retries of unprocessed items
+	maxBatchWriteAttempts = 6
+
+	// batchWriteBaseDelay is the first retry delay; later retries back off exponentially
+	batchWriteBaseDelay = 50 * time.Millisecond
+)
+
+// PartialWriteError reports that some posts could not be stored
+// Stored posts are already counted on the run; callers may retry the failed ones
+type PartialWriteError struct {
+	Stored int
+	Failed int
+	Err    error
+}
+
+func (e *PartialWriteError) Error() string {
+	return fmt.Sprintf("stored %d posts, failed to store %d: %v", e.Stored, e.Failed, e.Err)
+}
+
+func (e *PartialWriteError) Unwrap() error {
+	return e.Err
+}
+
+// splitPostBatches groups posts into batches of at most maxPosts posts whose estimated
+// stored size stays under maxBytes. A single post larger than maxBytes gets its own batch.
+func splitPostBatches(posts []Post, maxPosts, maxBytes int) [][]Post {
+	var batches [][]Post
+	var current []Post
+	currentBytes := 0
+
+	for _, post := range posts {
+		size := estimatePostSize(post)
+		if len(current) > 0 && (len(current) >= maxPosts || currentBytes+size > maxBytes) {
+			batches = append(batches, current)
+			current, currentBytes = nil, 0
+		}
+		current = append(current, post)
+		currentBytes += size
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+
+	return batches
+}
+
+// estimatePostSize approximates the bytes a post occupies in DynamoDB from its JSON size,
+// which carries the same attribute names and values
+func estimatePostSize(post Post) int {
+	data, err := json.Marshal(post)
+	if err != nil {
+		return maxBatchItemBytes
+	}
+	return len(data)
+}
+
+// batchWriteBackoff is the jittered delay before retry attempt n (n >= 1)
+// Full jitter spreads retries from concurrent writers hitting the same throttled table
+func batchWriteBackoff(attempt int) time.Duration {
+	ceiling := batchWriteBaseDelay << (attempt - 1)
+	return ceiling/2 + rand.N(ceiling/2+1)
+}
+
+// batchWrite sends up to 25 write requests, retrying any DynamoDB leaves unprocessed
+// with jittered exponential backoff. It returns the requests that were never written.
+func (sm *StateManager) batchWrite(ctx context.Context, requests []types.WriteRequest) ([]types.WriteRequest, error) {
+	pending := requests
+	for attempt := 0; len(pending) > 0; attempt++ {
+		if attempt >= maxBatchWriteAttempts {
+			return pending, fmt.Errorf("%d items unprocessed after %d attempts", len(pending), attempt)
+		}
+		if attempt > 0 {
+			select {
+			case <-time.After(batchWriteBackoff(attempt)):
+			case <-ctx.Done():
+				return pending, ctx.Err()
+			}
+		}
+
+		result, err := sm.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{sm.tableName: pending},
+		})
+		if err != nil {
+			return pending, err
+		}
+		pending = result.UnprocessedItems[sm.tableName]
+	}
+	return nil, nil
+}
@@ -0,0 +1,103 @@
+package state
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestSplitPostBatchesByCount(t *testing.T) {
+	posts := make([]Post, 250)
+	batches := splitPostBatches(posts, 100, maxBatchItemBytes)
+
+	if len(batches) != 3 {
+		t.Fatalf("expected 3 batches, got %d", len(batches))
+	}
+	for i, want := range []int{100, 100, 50} {
+		if len(batches[i]) != want {
+			t.Errorf("batch %d has %d posts, want %d", i, len(batches[i]), want)
+		}
+	}
+}
+
+func TestSplitPostBatchesBySize(t *testing.T) {
+	text := strings.Repeat("x", 1000)
+	posts := make([]Post, 10)
+	for i := range posts {
+		posts[i] = Post{Text: text}
+	}
+
+	size := estimatePostSize(posts[0])
+	batches := splitPostBatches(posts, 100, 3*size)
+
+	if len(batches) != 4 {
+		t.Fatalf("expected 4 batches of at most 3 posts, got %d", len(batches))
+	}
+	total := 0
+	for i, batch := range batches {
+		if len(batch) > 3 {
+			t.Errorf("batch %d has %d posts, exceeding the size limit", i, len(batch))
+		}
+		total += len(batch)
+	}
+	if total != len(posts) {
+		t.Errorf("expected every post to be batched, got %d of %d", total, len(posts))
+	}
+
+	// A post larger than the limit still gets a batch of its own
+	if batches := splitPostBatches(posts[:2], 100, 10); len(batches) != 2 {
+		t.Errorf("expected oversized posts in separate batches, got %d batches", len(batches))
+	}
+
+	if batches := splitPostBatches(nil, 100, maxBatchItemBytes); len(batches) != 0 {
+		t.Errorf("expected no batches for no posts, got %d", len(batches))
+	}
+}
+
+func TestBatchWriteBackoff(t *testing.T) {
+	for attempt := 1; attempt < maxBatchWriteAttempts; attempt++ {
+		ceiling := batchWriteBaseDelay << (attempt - 1)
+		for range 20 {
+			delay := batchWriteBackoff(attempt)
+			if delay < ceiling/2 || delay > ceiling {
+				t.Fatalf("attempt %d: delay %v outside [%v, %v]", attempt, delay, ceiling/2, ceiling)
+			}
+		}
+	}
+	if batchWriteBackoff(maxBatchWriteAttempts-1) > 2*time.Second {
+		t.Error("expected the longest backoff to stay within a couple of seconds")
+	}
+}
+
+func TestCountBatchedPosts(t *testing.T) {
+	batch := func(n int) types.WriteRequest {
+		posts := make([]types.AttributeValue, n)
+		return types.WriteRequest{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+			"posts": &types.AttributeValueMemberL{Value: posts},
+		}}}
+	}
+
+	requests := []types.WriteRequest{batch(100), batch(37), {DeleteRequest: &types.DeleteRequest{}}}
+	if got := countBatchedPosts(requests); got != 137 {
+		t.Errorf("expected 137 posts, got %d", got)
+	}
+}
+
+func TestPartialWriteError(t *testing.T) {
+	cause := errors.New("throttled")
+	var err error = &PartialWriteError{Stored: 300, Failed: 100, Err: cause}
+
+	if !errors.Is(err, cause) {
+		t.Error("expected PartialWriteError to unwrap to its cause")
+	}
+	var partial *PartialWriteError
+	if !errors.As(err, &partial) || partial.Failed != 100 {
+		t.Errorf("expected errors.As to find the partial failure, got %+v", partial)
+	}
+	if !strings.Contains(err.Error(), "stored 300 posts, failed to store 100") {
+		t.Errorf("unexpected message: %s", err.Error())
+	}
+}
@@ -0,0 +1,164 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
+)
+
+// Blocklist entry kinds
+const (
+	BlockAuthor  = "author"  // a handle, e.g. spammer.bsky.social
+	BlockDomain  = "domain"  // a link card's domain and its subdomains, e.g. example.com
+	BlockKeyword = "keyword" // a word or phrase in the post's text, matched as whole words
+
+	// BlockListMember counts posts whose author is on a subscribed moderation list; list
+	// members aren't stored as entries
+	BlockListMember = "list"
+)
+
+// BlocklistEntry is an author, link domain or keyword whose posts are left out of runs or,
+// when Allow is set, an author or domain whose posts are kept even if they match a block
+//
+// The blocklist table is keyed by kind and value, so an entry is either a block or an allow.
+type BlocklistEntry struct {
+	Kind    string    `json:"kind" dynamodbav:"kind"`
+	Value   string    `json:"value" dynamodbav:"value"`
+	Allow   bool      `json:"allow,omitempty" dynamodbav:"allow,omitempty"`
+	Reason  string    `json:"reason,omitempty" dynamodbav:"reason,omitempty"`
+	AddedAt time.Time `json:"addedAt" dynamodbav:"addedAt"`
+}
+
+// NormalizeBlocklistEntry lower-cases an entry's value the way posts are matched: handles
+// without "@", domains without a scheme, path or "www.", and keywords with single spaces
+func NormalizeBlocklistEntry(entry BlocklistEntry) (BlocklistEntry, error) {
+	value := strings.ToLower(strings.TrimSpace(entry.Value))
+	switch entry.Kind {
+	case BlockAuthor:
+		value = strings.TrimPrefix(value, "@")
+		if strings.ContainsAny(value, " /") {
+			return entry, fmt.Errorf("invalid author %q, want a handle", entry.Value)
+		}
+	case BlockDomain:
+		if !strings.Contains(value, "://") {
+			value = "https://" + value
+		}
+		value = strings.TrimSuffix(LinkDomain(value), ".")
+	case BlockKeyword:
+		if entry.Allow {
+			return entry, fmt.Errorf("keywords can only be blocked")
+		}
+		value = strings.Join(strings.Fields(value), " ")
+	default:
+		return entry, fmt.Errorf("unknown blocklist kind %q, want %s, %s or %s", entry.Kind, BlockAuthor, BlockDomain, BlockKeyword)
+	}
+	if value == "" {
+		return entry, fmt.Errorf("empty %s", entry.Kind)
+	}
+
+	entry.Value = value
+	return entry, nil
+}
+
+// BlocklistManager stores the blocklist
+type BlocklistManager struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewBlocklistManager creates a new blocklist manager
+func NewBlocklistManager(ctx context.Context, tableName string) (*BlocklistManager, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, tracing.WithAWS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return &BlocklistManager{
+		client:    dynamodb.NewFromConfig(cfg),
+		tableName: tableName,
+	}, nil
+}
+
+// AddEntry normalizes and stores an entry, replacing any entry with the same kind and value
+func (bm *BlocklistManager) AddEntry(ctx context.Context, entry BlocklistEntry) (BlocklistEntry, error) {
+	entry, err := NormalizeBlocklistEntry(entry)
+	if err != nil {
+		return entry, err
+	}
+	if entry.AddedAt.IsZero() {
+		entry.AddedAt = time.Now().UTC()
+	}
+
+	item, err := attributevalue.MarshalMap(entry)
+	if err != nil {
+		return entry, fmt.Errorf("failed to marshal blocklist entry: %w", err)
+	}
+	_, err = bm.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(bm.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return entry, fmt.Errorf("failed to store blocklist entry %s %s: %w", entry.Kind, entry.Value, err)
+	}
+	return entry, nil
+}
+
+// RemoveEntry deletes the entry with the kind and value, if there is one
+func (bm *BlocklistManager) RemoveEntry(ctx context.Context, kind, value string) error {
+	entry, err := NormalizeBlocklistEntry(BlocklistEntry{Kind: kind, Value: value})
+	if err != nil {
+		return err
+	}
+
+	_, err = bm.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(bm.tableName),
+		Key: map[string]types.AttributeValue{
+			"kind":  &types.AttributeValueMemberS{Value: entry.Kind},
+			"value": &types.AttributeValueMemberS{Value: entry.Value},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove blocklist entry %s %s: %w", entry.Kind, entry.Value, err)
+	}
+	return nil
+}
+
+// ListEntries returns every entry, sorted by kind and value
+func (bm *BlocklistManager) ListEntries(ctx context.Context) ([]BlocklistEntry, error) {
+	var entries []BlocklistEntry
+	scanInput := &dynamodb.ScanInput{TableName: aws.String(bm.tableName)}
+	for {
+		output, err := bm.client.Scan(ctx, scanInput)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan blocklist: %w", err)
+		}
+
+		var page []BlocklistEntry
+		if err := attributevalue.UnmarshalListOfMaps(output.Items, &page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal blocklist: %w", err)
+		}
+		entries = append(entries, page...)
+
+		if output.LastEvaluatedKey == nil {
+			break
+		}
+		scanInput.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Kind != entries[j].Kind {
+			return entries[i].Kind < entries[j].Kind
+		}
+		return entries[i].Value < entries[j].Value
+	})
+	return entries, nil
+}
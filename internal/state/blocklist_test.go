@@ -0,0 +1,31 @@
+package state
+
+import "testing"
+
+func TestNormalizeBlocklistEntry(t *testing.T) {
+	for _, tc := range []struct {
+		entry BlocklistEntry
+		want  string
+	}{
+		{BlocklistEntry{Kind: BlockAuthor, Value: " @Spammer.BSKY.social "}, "spammer.bsky.social"},
+		{BlocklistEntry{Kind: BlockDomain, Value: "https://www.Example.com/path?q=1"}, "example.com"},
+		{BlocklistEntry{Kind: BlockDomain, Value: "news.example.com"}, "news.example.com"},
+		{BlocklistEntry{Kind: BlockKeyword, Value: "  Free   Crypto "}, "free crypto"},
+	} {
+		got, err := NormalizeBlocklistEntry(tc.entry)
+		if err != nil || got.Value != tc.want {
+			t.Errorf("NormalizeBlocklistEntry(%+v) = %q, %v, want %q", tc.entry, got.Value, err, tc.want)
+		}
+	}
+
+	for _, entry := range []BlocklistEntry{
+		{Kind: "mood", Value: "sad"},
+		{Kind: BlockAuthor, Value: "two words"},
+		{Kind: BlockDomain, Value: "   "},
+		{Kind: BlockKeyword, Value: "crypto", Allow: true},
+	} {
+		if _, err := NormalizeBlocklistEntry(entry); err == nil {
+			t.Errorf("Expected NormalizeBlocklistEntry(%+v) to fail", entry)
+		}
+	}
+}
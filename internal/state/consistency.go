@@ -0,0 +1,20 @@
+package state
+
+// ReadConsistency chooses between DynamoDB's eventually and strongly consistent reads of
+// run state. Every GetRun, GetRunStats and GetAllPosts caller picks one explicitly.
+//
+// A Lambda reading state another Lambda has just written, the hand-offs of the pipeline
+// (orchestrator to fetcher to processor to posters), must read StronglyConsistent: an
+// eventually consistent read can miss the fetcher's last batches and make the processor
+// see 0 posts. Read-modify-write updates of a run's state read it StronglyConsistent too,
+// so they never write back a stale copy over a newer one. Tools reading finished runs
+// (query-runs, diagnostics, exports) can read EventuallyConsistent at half the cost.
+type ReadConsistency bool
+
+const (
+	// EventuallyConsistent reads may miss writes from the last second or so
+	EventuallyConsistent ReadConsistency = false
+	// StronglyConsistent reads see every write acknowledged before the read. Posts are then
+	// queried from the table itself, as its indexes only support eventually consistent reads.
+	StronglyConsistent ReadConsistency = true
+)
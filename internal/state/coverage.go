@@ -0,0 +1,62 @@
+package state
+
+import (
+	"time"
+)
+
+// WindowCoverage describes how much of the requested analysis window the fetched posts span
+type WindowCoverage struct {
+	WindowStart     time.Time `json:"windowStart"`
+	WindowEnd       time.Time `json:"windowEnd"`
+	EarliestPostAt  time.Time `json:"earliestPostAt,omitempty"`
+	LatestPostAt    time.Time `json:"latestPostAt,omitempty"`
+	CoveragePercent float64   `json:"coveragePercent"`
+}
+
+// CalculateCoverage measures the span between the earliest and latest post as a percentage
+// of the requested window, clamped to the window bounds
+// Posts with invalid timestamps are ignored; no valid posts means 0% coverage
+func CalculateCoverage(posts []Post, windowStart, windowEnd time.Time) WindowCoverage {
+	coverage := WindowCoverage{
+		WindowStart: windowStart,
+		WindowEnd:   windowEnd,
+	}
+
+	window := windowEnd.Sub(windowStart)
+	if window <= 0 {
+		return coverage
+	}
+
+	for _, post := range posts {
+		postTime, err := time.Parse(time.RFC3339, post.CreatedAt)
+		if err != nil {
+			continue
+		}
+		if coverage.EarliestPostAt.IsZero() || postTime.Before(coverage.EarliestPostAt) {
+			coverage.EarliestPostAt = postTime
+		}
+		if coverage.LatestPostAt.IsZero() || postTime.After(coverage.LatestPostAt) {
+			coverage.LatestPostAt = postTime
+		}
+	}
+
+	if coverage.EarliestPostAt.IsZero() {
+		return coverage
+	}
+
+	// Clamp to the window so posts slightly outside it don't inflate coverage
+	earliest := coverage.EarliestPostAt
+	if earliest.Before(windowStart) {
+		earliest = windowStart
+	}
+	latest := coverage.LatestPostAt
+	if latest.After(windowEnd) {
+		latest = windowEnd
+	}
+
+	if latest.After(earliest) {
+		coverage.CoveragePercent = float64(latest.Sub(earliest)) / float64(window) * 100.0
+	}
+
+	return coverage
+}
@@ -0,0 +1,58 @@
+package state
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCalculateCoverage(t *testing.T) {
+	windowStart := time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC)
+	windowEnd := windowStart.Add(30 * time.Minute)
+
+	tests := []struct {
+		name     string
+		posts    []Post
+		expected float64
+	}{
+		{
+			name:     "no posts",
+			posts:    nil,
+			expected: 0,
+		},
+		{
+			name: "full window",
+			posts: []Post{
+				{CreatedAt: "2025-01-05T12:00:00Z"},
+				{CreatedAt: "2025-01-05T12:30:00Z"},
+			},
+			expected: 100,
+		},
+		{
+			name: "most recent third only",
+			posts: []Post{
+				{CreatedAt: "2025-01-05T12:20:00Z"},
+				{CreatedAt: "2025-01-05T12:30:00Z"},
+				{CreatedAt: "not-a-time"},
+			},
+			expected: 33.3,
+		},
+		{
+			name: "posts outside window are clamped",
+			posts: []Post{
+				{CreatedAt: "2025-01-05T11:50:00Z"},
+				{CreatedAt: "2025-01-05T12:15:00Z"},
+			},
+			expected: 50,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coverage := CalculateCoverage(tt.posts, windowStart, windowEnd)
+			if math.Abs(coverage.CoveragePercent-tt.expected) > 0.1 {
+				t.Errorf("CalculateCoverage() = %.1f%%, expected %.1f%%", coverage.CoveragePercent, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Partition of the cached current events headlines, one item per date; it is not a run,
+// so run listings never see it
+const currentEventsRunID = "current-events"
+
+// CurrentEventsTTL is how long a cached headline is kept
+const CurrentEventsTTL = 400 * 24 * time.Hour
+
+// CurrentEventsHeadline is a cached headline from Wikipedia's current events portal for
+// one UTC date. An empty headline records that the date had no usable entry.
+type CurrentEventsHeadline struct {
+	RunID     string    `json:"runId" dynamodbav:"runId"`
+	PostID    string    `json:"postId" dynamodbav:"postId"` // the date, "2006-01-02"
+	Headline  string    `json:"headline,omitempty" dynamodbav:"headline,omitempty"`
+	FetchedAt time.Time `json:"fetchedAt" dynamodbav:"fetchedAt"`
+	TTL       int64     `json:"ttl" dynamodbav:"ttl"`
+}
+
+// GetCurrentEventsHeadline retrieves the cached headline for a date, nil if none is cached
+func (sm *StateManager) GetCurrentEventsHeadline(ctx context.Context, date string) (*CurrentEventsHeadline, error) {
+	result, err := sm.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(sm.tableName),
+		Key: map[string]types.AttributeValue{
+			"runId":  &types.AttributeValueMemberS{Value: currentEventsRunID},
+			"postId": &types.AttributeValueMemberS{Value: date},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current events headline: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var headline CurrentEventsHeadline
+	if err := attributevalue.UnmarshalMap(result.Item, &headline); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal current events headline: %w", err)
+	}
+	return &headline, nil
+}
+
+// SaveCurrentEventsHeadline caches the headline for a date
+func (sm *StateManager) SaveCurrentEventsHeadline(ctx context.Context, date, headline string) error {
+	now := time.Now().UTC()
+	item, err := attributevalue.MarshalMap(CurrentEventsHeadline{
+		RunID:     currentEventsRunID,
+		PostID:    date,
+		Headline:  headline,
+		FetchedAt: now,
+		TTL:       now.Add(CurrentEventsTTL).Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal current events headline: %w", err)
+	}
+
+	_, err = sm.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(sm.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save current events headline: %w", err)
+	}
+	return nil
+}
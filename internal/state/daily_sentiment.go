@@ -3,6 +3,7 @@ package state
 import (
 	"context"
 	"fmt"
+	"math"
 	"sort"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
 )
 
 // DailySentimentDataPoint represents a single daily sentiment measurement
@@ -24,6 +26,31 @@ type DailySentimentDataPoint struct {
 	TotalPosts       int       `json:"totalPosts" dynamodbav:"totalPosts"`
 	CreatedAt        time.Time `json:"createdAt" dynamodbav:"createdAt"`
 	TTL              int64     `json:"ttl" dynamodbav:"ttl"`
+	// RunsStarted counts every run the orchestrator created that day, including runs that
+	// failed or were skipped; TotalRuns only counts runs that stored sentiment
+	RunsStarted int `json:"runsStarted,omitempty" dynamodbav:"runsStarted,omitempty"`
+	// AverageCoveragePercent is the mean window coverage of the runs started that day
+	AverageCoveragePercent float64 `json:"averageCoveragePercent,omitempty" dynamodbav:"averageCoveragePercent,omitempty"`
+}
+
+// AddRunActivity records how many of runs were created on the data point's date and their
+// mean window coverage. Runs from other days are ignored.
+func (dp *DailySentimentDataPoint) AddRunActivity(runs []RunState) {
+	var started int
+	var coverage float64
+	for _, run := range runs {
+		if run.CreatedAt.UTC().Format("2006-01-02") != dp.Date {
+			continue
+		}
+		started++
+		coverage += run.CoveragePercent
+	}
+
+	dp.RunsStarted = started
+	dp.AverageCoveragePercent = 0
+	if started > 0 {
+		dp.AverageCoveragePercent = coverage / float64(started)
+	}
 }
 
 // YearlySparklineDataPoint represents a data point for yearly sparkline visualization
@@ -34,17 +61,20 @@ type YearlySparklineDataPoint struct {
 	MaxSentiment        float64   `json:"maxSentiment"`
 	Timestamp           time.Time `json:"timestamp"`
 	NetSentimentPercent float64   `json:"netSentimentPercent"` // Alias for AverageSentiment
+	// Synthetic marks a day InterpolateDailyGaps made up to bridge missing days
+	Synthetic bool `json:"synthetic,omitempty"`
 }
 
 // DailySentimentManager handles daily sentiment operations
 type DailySentimentManager struct {
 	client    *dynamodb.Client
 	tableName string
+	ttl       time.Duration
 }
 
 // NewDailySentimentManager creates a new daily sentiment manager
 func NewDailySentimentManager(ctx context.Context, tableName string) (*DailySentimentManager, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := config.LoadDefaultConfig(ctx, tracing.WithAWS)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
@@ -54,15 +84,20 @@ func NewDailySentimentManager(ctx context.Context, tableName string) (*DailySent
 	return &DailySentimentManager{
 		client:    client,
 		tableName: tableName,
+		ttl:       DefaultDailySentimentTTL,
 	}, nil
 }
 
+// SetTTL sets how long new daily sentiment data points are kept before DynamoDB expires them
+func (dsm *DailySentimentManager) SetTTL(ttl time.Duration) {
+	dsm.ttl = ttl
+}
+
 // StoreDailySentiment stores a daily sentiment data point
 func (dsm *DailySentimentManager) StoreDailySentiment(ctx context.Context, dataPoint DailySentimentDataPoint) error {
 	// Set CreatedAt and TTL
 	dataPoint.CreatedAt = time.Now()
-	// Set TTL to 3 years from creation time
-	dataPoint.TTL = dataPoint.CreatedAt.Add(3 * 365 * 24 * time.Hour).Unix()
+	dataPoint.TTL = dataPoint.CreatedAt.Add(dsm.ttl).Unix()
 
 	item, err := attributevalue.MarshalMap(dataPoint)
 	if err != nil {
@@ -115,10 +150,33 @@ func (dsm *DailySentimentManager) GetDailySentimentHistory(ctx context.Context,
 	return dataPoints, nil
 }
 
+// MonthlyDays is how many days of daily sentiment the 30 day chart covers
+const MonthlyDays = 30
+
 // GetYearlySentimentData retrieves 365 days of daily sentiment data for yearly sparkline
 func (dsm *DailySentimentManager) GetYearlySentimentData(ctx context.Context) ([]YearlySparklineDataPoint, error) {
-	// Get 365 days of data
-	dailyData, err := dsm.GetDailySentimentHistory(ctx, 365)
+	return dsm.getSparklineData(ctx, 365)
+}
+
+// GetSentimentDataBetween retrieves the daily sentiment data for the days in [start, end)
+// for range charts, oldest first
+func (dsm *DailySentimentManager) GetSentimentDataBetween(ctx context.Context, start, end time.Time) ([]YearlySparklineDataPoint, error) {
+	data, err := dsm.getSparklineData(ctx, int(time.Since(start).Hours()/24)+1)
+	if err != nil {
+		return nil, err
+	}
+	var kept []YearlySparklineDataPoint
+	for _, point := range data {
+		if !point.Timestamp.Before(start) && point.Timestamp.Before(end) {
+			kept = append(kept, point)
+		}
+	}
+	return kept, nil
+}
+
+// getSparklineData retrieves days of daily sentiment data as chart points, oldest first
+func (dsm *DailySentimentManager) getSparklineData(ctx context.Context, days int) ([]YearlySparklineDataPoint, error) {
+	dailyData, err := dsm.GetDailySentimentHistory(ctx, days)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get daily sentiment history: %w", err)
 	}
@@ -177,6 +235,77 @@ func (dsm *DailySentimentManager) GetDailySentimentForDate(ctx context.Context,
 	return &dataPoint, nil
 }
 
+// PercentileDays is how many past days a day's sentiment is ranked against by default
+const PercentileDays = 90
+
+// MinPercentileDays is the fewest past days needed for a meaningful percentile
+const MinPercentileDays = 14
+
+// SentimentPercentile ranks a day's average sentiment among the daily averages before it
+type SentimentPercentile struct {
+	Date             string  `json:"date"`
+	AverageSentiment float64 `json:"averageSentiment"`
+	// Percentile is the share of past days, 0 to 100, with lower average sentiment; ties count half
+	Percentile int `json:"percentile"`
+	// Days is how many past days were ranked against
+	Days int `json:"days"`
+}
+
+// GetSentimentPercentile ranks average, the average sentiment of date, among the daily
+// averages of up to days days before it. It returns nil when fewer than MinPercentileDays
+// of them are stored.
+func (dsm *DailySentimentManager) GetSentimentPercentile(ctx context.Context, date string, average float64, days int) (*SentimentPercentile, error) {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date format: %w", err)
+	}
+
+	// The daily history is read back from today, so reach past date's own look-back
+	lookback := days + 1
+	if age := int(time.Since(day).Hours() / 24); age > 0 {
+		lookback += age
+	}
+	history, err := dsm.GetDailySentimentHistory(ctx, lookback)
+	if err != nil {
+		return nil, err
+	}
+	return RankSentiment(history, date, average, days), nil
+}
+
+// RankSentiment ranks average, the average sentiment of date, among the daily averages of
+// up to days days before it, or returns nil when fewer than MinPercentileDays are in history
+func RankSentiment(history []DailySentimentDataPoint, date string, average float64, days int) *SentimentPercentile {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil
+	}
+	first := day.AddDate(0, 0, -days).Format("2006-01-02")
+
+	var below, ties, ranked int
+	for _, point := range history {
+		if point.Date < first || point.Date >= date {
+			continue
+		}
+		ranked++
+		switch {
+		case point.AverageSentiment < average:
+			below++
+		case point.AverageSentiment == average:
+			ties++
+		}
+	}
+	if ranked < MinPercentileDays {
+		return nil
+	}
+
+	return &SentimentPercentile{
+		Date:             date,
+		AverageSentiment: average,
+		Percentile:       int(math.Round(100 * (float64(below) + float64(ties)/2) / float64(ranked))),
+		Days:             ranked,
+	}
+}
+
 // CalculateDailySentimentFromHistory calculates daily sentiment from 24 hours of sentiment history
 func (dsm *DailySentimentManager) CalculateDailySentimentFromHistory(ctx context.Context, sentimentHistoryManager *SentimentHistoryManager, targetDate string) (*DailySentimentDataPoint, error) {
 	// Parse target date
@@ -243,6 +372,6 @@ func (dsm *DailySentimentManager) CalculateDailySentimentFromHistory(ctx context
 		TotalRuns:        len(dayData),
 		TotalPosts:       totalPosts,
 		CreatedAt:        time.Now(),
-		TTL:              time.Now().Add(3 * 365 * 24 * time.Hour).Unix(),
+		TTL:              time.Now().Add(dsm.ttl).Unix(),
 	}, nil
 }
@@ -109,6 +109,32 @@ func TestCalculateDailySentimentFromHistory(t *testing.T) {
 	}
 }
 
+func TestRankSentiment(t *testing.T) {
+	var history []DailySentimentDataPoint
+	for day := 1; day <= 20; day++ {
+		history = append(history, DailySentimentDataPoint{
+			Date:             time.Date(2025, 1, day, 0, 0, 0, 0, time.UTC).Format("2006-01-02"),
+			AverageSentiment: float64(day),
+		})
+	}
+
+	// Days 1 to 20 are before the 21st; 15 lie below 15.5
+	rank := RankSentiment(history, "2025-01-21", 15.5, PercentileDays)
+	if rank == nil || rank.Days != 20 || rank.Percentile != 75 {
+		t.Fatalf("Expected the 75th percentile of 20 days, got %+v", rank)
+	}
+
+	// The day itself and later days are not ranked against, and ties count half
+	rank = RankSentiment(history, "2025-01-19", 9, PercentileDays)
+	if rank == nil || rank.Days != 18 || rank.Percentile != 47 {
+		t.Errorf("Expected the 47th percentile of 18 days, got %+v", rank)
+	}
+
+	if rank := RankSentiment(history, "2025-01-21", 10, 10); rank != nil {
+		t.Errorf("Expected no percentile from fewer than %d days, got %+v", MinPercentileDays, rank)
+	}
+}
+
 func TestDailySentimentManager_NewDailySentimentManager(t *testing.T) {
 	// Test with valid table name (will fail on AWS config, but that's expected)
 	_, err := NewDailySentimentManager(context.Background(), "test-table")
@@ -116,3 +142,29 @@ func TestDailySentimentManager_NewDailySentimentManager(t *testing.T) {
 		t.Errorf("Expected AWS config error, got: %v", err)
 	}
 }
+
+func TestAddRunActivity(t *testing.T) {
+	day := time.Date(2025, 9, 2, 0, 0, 0, 0, time.UTC)
+	runs := []RunState{
+		{RunID: "a", CreatedAt: day.Add(30 * time.Minute), CoveragePercent: 100},
+		{RunID: "b", CreatedAt: day.Add(23 * time.Hour), CoveragePercent: 80},
+		{RunID: "c", CreatedAt: day.Add(12 * time.Hour), Status: "failed"}, // never fetched
+		{RunID: "d", CreatedAt: day.Add(-time.Minute), CoveragePercent: 100},
+		{RunID: "e", CreatedAt: day.Add(24 * time.Hour), CoveragePercent: 100},
+	}
+
+	dataPoint := DailySentimentDataPoint{Date: "2025-09-02", TotalRuns: 2}
+	dataPoint.AddRunActivity(runs)
+
+	if dataPoint.RunsStarted != 3 {
+		t.Errorf("Expected 3 runs started on the day, got %d", dataPoint.RunsStarted)
+	}
+	if dataPoint.AverageCoveragePercent != 60 {
+		t.Errorf("Expected mean coverage 60%%, got %.2f", dataPoint.AverageCoveragePercent)
+	}
+
+	dataPoint.AddRunActivity(nil)
+	if dataPoint.RunsStarted != 0 || dataPoint.AverageCoveragePercent != 0 {
+		t.Errorf("Expected no activity without runs, got %+v", dataPoint)
+	}
+}
@@ -0,0 +1,64 @@
+package state
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// DomainShare is how often one website's links were shared within a run
+type DomainShare struct {
+	Domain string `json:"domain" dynamodbav:"domain"`
+	// Posts is the number of posts linking to the domain
+	Posts int `json:"posts" dynamodbav:"posts"`
+	// Shares counts those posts and their reposts
+	Shares              int     `json:"shares" dynamodbav:"shares"`
+	NetSentimentPercent float64 `json:"netSentimentPercent" dynamodbav:"netSentimentPercent"`
+}
+
+// LinkDomain returns the lower-cased host of a link without a leading "www.", or an empty
+// string when the link has no host
+func LinkDomain(link string) string {
+	parsed, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+}
+
+// CalculateDomainShares groups posts by the domain of their link card and returns the limit
+// most-shared domains, counting each post and its reposts, with the average compound score
+// of their posts as a percentage. Ties are broken by domain name.
+func CalculateDomainShares(posts []Post, limit int) []DomainShare {
+	totals := make(map[string]*DomainShare)
+	for _, post := range posts {
+		domain := LinkDomain(post.LinkURL)
+		if domain == "" {
+			continue
+		}
+		total, ok := totals[domain]
+		if !ok {
+			total = &DomainShare{Domain: domain}
+			totals[domain] = total
+		}
+		total.Posts++
+		total.Shares += 1 + post.Reposts
+		total.NetSentimentPercent += post.SentimentScore
+	}
+
+	domains := make([]DomainShare, 0, len(totals))
+	for _, total := range totals {
+		total.NetSentimentPercent = total.NetSentimentPercent / float64(total.Posts) * 100
+		domains = append(domains, *total)
+	}
+	sort.Slice(domains, func(i, j int) bool {
+		if domains[i].Shares != domains[j].Shares {
+			return domains[i].Shares > domains[j].Shares
+		}
+		return domains[i].Domain < domains[j].Domain
+	})
+	if len(domains) > limit {
+		domains = domains[:limit]
+	}
+	return domains
+}
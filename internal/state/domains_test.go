@@ -0,0 +1,44 @@
+package state
+
+import "testing"
+
+func TestLinkDomain(t *testing.T) {
+	tests := map[string]string{
+		"https://www.BBC.co.uk/news/world-1": "bbc.co.uk",
+		"http://example.com:8080/a?b=c":      "example.com",
+		"https://apnews.com":                 "apnews.com",
+		"":                                   "",
+		"not a link":                         "",
+	}
+	for link, want := range tests {
+		if got := LinkDomain(link); got != want {
+			t.Errorf("LinkDomain(%q) = %q, want %q", link, got, want)
+		}
+	}
+}
+
+func TestCalculateDomainShares(t *testing.T) {
+	posts := []Post{
+		{URI: "a", SentimentScore: -0.5, Reposts: 3, LinkURL: "https://www.bbc.co.uk/news/1"},
+		{URI: "b", SentimentScore: -0.1, LinkURL: "https://bbc.co.uk/news/2"},
+		{URI: "c", SentimentScore: 0.4, Reposts: 5, LinkURL: "https://example.com/cats"},
+		{URI: "d", SentimentScore: 0.2, LinkURL: "https://apnews.com/article"},
+		{URI: "e", SentimentScore: 0.9},
+	}
+
+	domains := CalculateDomainShares(posts, 10)
+	if len(domains) != 3 {
+		t.Fatalf("Expected three linked domains, got %+v", domains)
+	}
+	if domains[0].Domain != "example.com" || domains[0].Posts != 1 || domains[0].Shares != 6 {
+		t.Errorf("Unexpected first domain %+v", domains[0])
+	}
+	if domains[1].Domain != "bbc.co.uk" || domains[1].Posts != 2 || domains[1].Shares != 5 ||
+		domains[1].NetSentimentPercent > -29.9 || domains[1].NetSentimentPercent < -30.1 {
+		t.Errorf("Unexpected second domain %+v", domains[1])
+	}
+
+	if domains := CalculateDomainShares(posts, 1); len(domains) != 1 || domains[0].Domain != "example.com" {
+		t.Errorf("Expected the limit to keep the most-shared domain, got %+v", domains)
+	}
+}
@@ -0,0 +1,47 @@
+package state
+
+// Emotions is the share of emotion words expressing each emotion, per post or averaged
+// over a run's posts; the shares sum to 1 when any emotion words were found
+type Emotions struct {
+	Joy     float64 `json:"joy" dynamodbav:"joy"`
+	Anger   float64 `json:"anger" dynamodbav:"anger"`
+	Sadness float64 `json:"sadness" dynamodbav:"sadness"`
+	Fear    float64 `json:"fear" dynamodbav:"fear"`
+}
+
+// Dominant returns the largest emotion and its share, or "" when there were no emotion words
+// Ties go to the emotion listed first: joy, anger, sadness, fear.
+func (e Emotions) Dominant() (string, float64) {
+	name, share := "", 0.0
+	for _, emotion := range []struct {
+		name  string
+		share float64
+	}{{"joy", e.Joy}, {"anger", e.Anger}, {"sadness", e.Sadness}, {"fear", e.Fear}} {
+		if emotion.share > share {
+			name, share = emotion.name, emotion.share
+		}
+	}
+	return name, share
+}
+
+// CalculateEmotions averages the emotion vectors of the posts that have one, and returns
+// how many did. Posts without emotion words are left out rather than diluting the shares.
+func CalculateEmotions(posts []Post) (Emotions, int) {
+	var sum Emotions
+	count := 0
+	for _, post := range posts {
+		if post.Emotions == nil {
+			continue
+		}
+		sum.Joy += post.Emotions.Joy
+		sum.Anger += post.Emotions.Anger
+		sum.Sadness += post.Emotions.Sadness
+		sum.Fear += post.Emotions.Fear
+		count++
+	}
+	if count == 0 {
+		return Emotions{}, 0
+	}
+	n := float64(count)
+	return Emotions{Joy: sum.Joy / n, Anger: sum.Anger / n, Sadness: sum.Sadness / n, Fear: sum.Fear / n}, count
+}
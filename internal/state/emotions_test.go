@@ -0,0 +1,29 @@
+package state
+
+import "testing"
+
+func TestCalculateEmotions(t *testing.T) {
+	posts := []Post{
+		{URI: "a", Emotions: &Emotions{Joy: 1}},
+		{URI: "b", Emotions: &Emotions{Anger: 0.5, Fear: 0.5}},
+		{URI: "c"},
+	}
+
+	emotions, count := CalculateEmotions(posts)
+	if count != 2 {
+		t.Errorf("Expected 2 posts with emotions, got %d", count)
+	}
+	if emotions != (Emotions{Joy: 0.5, Anger: 0.25, Fear: 0.25}) {
+		t.Errorf("Unexpected emotions %+v", emotions)
+	}
+	if name, share := emotions.Dominant(); name != "joy" || share != 0.5 {
+		t.Errorf("Dominant() = %s %.2f, want joy 0.50", name, share)
+	}
+
+	if emotions, count := CalculateEmotions([]Post{{URI: "c"}}); count != 0 || emotions != (Emotions{}) {
+		t.Errorf("Expected no emotions, got %+v from %d posts", emotions, count)
+	}
+	if name, _ := (Emotions{}).Dominant(); name != "" {
+		t.Errorf("Expected no dominant emotion, got %q", name)
+	}
+}
@@ -0,0 +1,104 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Summaries posted in an experiment outlive their runs, so the weekly report can look
+// up their engagement. They share one partition, which is not a run, ordered by time:
+//
+//	runId = experiments, postId = post#<experiment>#<postedAt, RFC3339 UTC>#<runId>
+const (
+	experimentsRunID     = "experiments"
+	experimentPostPrefix = "post#"
+)
+
+// ExperimentPostTTL keeps experiment posts for several weekly reports
+const ExperimentPostTTL = 90 * 24 * time.Hour
+
+// ExperimentPost is a summary posted in one variant of an experiment
+type ExperimentPost struct {
+	RunID      string    `json:"runId" dynamodbav:"runId"`
+	PostID     string    `json:"postId" dynamodbav:"postId"`
+	Experiment string    `json:"experiment" dynamodbav:"experiment"`
+	Variant    string    `json:"variant" dynamodbav:"variant"`
+	SummaryRun string    `json:"summaryRun" dynamodbav:"summaryRun"` // the run that posted the summary
+	URI        string    `json:"uri" dynamodbav:"uri"`
+	CID        string    `json:"cid" dynamodbav:"cid"`
+	PostedAt   time.Time `json:"postedAt" dynamodbav:"postedAt"`
+	TTL        int64     `json:"ttl" dynamodbav:"ttl"`
+}
+
+// experimentPostKey builds the sort key for a post; times sort as text in RFC3339 UTC
+func experimentPostKey(experiment string, postedAt time.Time, runID string) string {
+	return experimentPostPrefix + experiment + "#" + postedAt.UTC().Format(time.RFC3339) + "#" + runID
+}
+
+// RecordExperimentPost stores a summary posted in an experiment and marks its run's
+// state with the experiment and variant
+func (sm *StateManager) RecordExperimentPost(ctx context.Context, post ExperimentPost) error {
+	post.RunID = experimentsRunID
+	post.PostID = experimentPostKey(post.Experiment, post.PostedAt, post.SummaryRun)
+	post.TTL = post.PostedAt.Add(ExperimentPostTTL).Unix()
+
+	item, err := attributevalue.MarshalMap(post)
+	if err != nil {
+		return fmt.Errorf("failed to marshal experiment post: %w", err)
+	}
+	if _, err := sm.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(sm.tableName),
+		Item:      item,
+	}); err != nil {
+		return fmt.Errorf("failed to store experiment post: %w", err)
+	}
+
+	state, err := sm.GetLatestRun(ctx, post.SummaryRun)
+	if err != nil {
+		return fmt.Errorf("failed to get current state: %w", err)
+	}
+	state.Experiment = post.Experiment
+	state.Variant = post.Variant
+	return sm.UpdateRun(ctx, state)
+}
+
+// GetExperimentPosts returns the summaries posted in an experiment since the given time,
+// oldest first
+func (sm *StateManager) GetExperimentPosts(ctx context.Context, experiment string, since time.Time) ([]ExperimentPost, error) {
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(sm.tableName),
+		KeyConditionExpression: aws.String("runId = :runId AND postId BETWEEN :from AND :to"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":runId": &types.AttributeValueMemberS{Value: experimentsRunID},
+			":from":  &types.AttributeValueMemberS{Value: experimentPostKey(experiment, since, "")},
+			// "~" sorts after every character of an RFC3339 time
+			":to": &types.AttributeValueMemberS{Value: experimentPostPrefix + experiment + "#~"},
+		},
+	}
+
+	var posts []ExperimentPost
+	for {
+		output, err := sm.client.Query(ctx, queryInput)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query experiment posts: %w", err)
+		}
+		for _, item := range output.Items {
+			var post ExperimentPost
+			if err := attributevalue.UnmarshalMap(item, &post); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal experiment post: %w", err)
+			}
+			posts = append(posts, post)
+		}
+		if len(output.LastEvaluatedKey) == 0 {
+			break
+		}
+		queryInput.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+	return posts, nil
+}
@@ -0,0 +1,30 @@
+package state
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestExperimentPostKeySortsByTime(t *testing.T) {
+	since := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	keys := []string{
+		experimentPostKey("headline", since.Add(48*time.Hour), "run-b"),
+		experimentPostKey("headline", since.Add(-time.Hour), "run-old"),
+		experimentPostKey("headline", since.Add(time.Hour).In(time.FixedZone("AEST", 10*3600)), "run-a"),
+		experimentPostKey("headline-2", since.Add(time.Hour), "run-other"),
+	}
+	sort.Strings(keys)
+
+	// GetExperimentPosts reads keys between these bounds
+	from, to := experimentPostKey("headline", since, ""), experimentPostPrefix+"headline#~"
+	var inRange []string
+	for _, key := range keys {
+		if key >= from && key <= to {
+			inRange = append(inRange, key)
+		}
+	}
+	if len(inRange) != 2 || inRange[0] != experimentPostKey("headline", since.Add(time.Hour), "run-a") {
+		t.Errorf("Expected the two later posts of the experiment in time order, got %v", inRange)
+	}
+}
@@ -0,0 +1,93 @@
+package state
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// InterpolateGaps bridges each gap in points wider than twice interval with synthetic
+// points spaced about interval apart on the straight line between the gap's ends, so a
+// chart stays continuous without pretending the runs happened. points must be in time
+// order; interval <= 0 uses the median spacing between them.
+func InterpolateGaps(points []SentimentDataPoint, interval time.Duration) []SentimentDataPoint {
+	if len(points) < 2 {
+		return points
+	}
+	if interval <= 0 {
+		interval = medianSpacing(points)
+		if interval <= 0 {
+			return points
+		}
+	}
+
+	filled := make([]SentimentDataPoint, 0, len(points))
+	for i, point := range points {
+		if i > 0 {
+			previous := points[i-1]
+			if spacing := point.Timestamp.Sub(previous.Timestamp); spacing > 2*interval {
+				steps := int(math.Round(float64(spacing) / float64(interval)))
+				for step := 1; step < steps; step++ {
+					fraction := float64(step) / float64(steps)
+					filled = append(filled, SentimentDataPoint{
+						Timestamp:            previous.Timestamp.Add(time.Duration(fraction * float64(spacing))),
+						AverageCompoundScore: lerp(previous.AverageCompoundScore, point.AverageCompoundScore, fraction),
+						NetSentimentPercent:  lerp(previous.NetSentimentPercent, point.NetSentimentPercent, fraction),
+						Network:              point.Network,
+						Channel:              point.Channel,
+						Synthetic:            true,
+					})
+				}
+			}
+		}
+		filled = append(filled, point)
+	}
+	return filled
+}
+
+// InterpolateDailyGaps adds a synthetic day for every date missing between days, on the
+// straight line between the days either side. days must be in date order.
+func InterpolateDailyGaps(days []YearlySparklineDataPoint) []YearlySparklineDataPoint {
+	if len(days) < 2 {
+		return days
+	}
+
+	filled := make([]YearlySparklineDataPoint, 0, len(days))
+	for i, day := range days {
+		if i > 0 {
+			previous := days[i-1]
+			steps := int(math.Round(day.Timestamp.Sub(previous.Timestamp).Hours() / 24))
+			for step := 1; step < steps; step++ {
+				fraction := float64(step) / float64(steps)
+				date := previous.Timestamp.AddDate(0, 0, step)
+				average := lerp(previous.AverageSentiment, day.AverageSentiment, fraction)
+				filled = append(filled, YearlySparklineDataPoint{
+					Date:                date.Format("2006-01-02"),
+					AverageSentiment:    average,
+					MinSentiment:        average,
+					MaxSentiment:        average,
+					Timestamp:           date,
+					NetSentimentPercent: average,
+					Synthetic:           true,
+				})
+			}
+		}
+		filled = append(filled, day)
+	}
+	return filled
+}
+
+// medianSpacing returns the median time between neighbouring points, the lower of the
+// middle two for an even count
+func medianSpacing(points []SentimentDataPoint) time.Duration {
+	spacings := make([]time.Duration, 0, len(points)-1)
+	for i := 1; i < len(points); i++ {
+		spacings = append(spacings, points[i].Timestamp.Sub(points[i-1].Timestamp))
+	}
+	sort.Slice(spacings, func(i, j int) bool { return spacings[i] < spacings[j] })
+	return spacings[(len(spacings)-1)/2]
+}
+
+func lerp(from, to, fraction float64) float64 {
+	return from + (to-from)*fraction
+}
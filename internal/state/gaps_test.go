@@ -0,0 +1,65 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInterpolateGaps(t *testing.T) {
+	start := time.Date(2025, 1, 7, 12, 0, 0, 0, time.UTC)
+	points := []SentimentDataPoint{
+		{RunID: "a", Timestamp: start, NetSentimentPercent: 10},
+		{RunID: "b", Timestamp: start.Add(30 * time.Minute), NetSentimentPercent: 20},
+		{RunID: "c", Timestamp: start.Add(60 * time.Minute), NetSentimentPercent: 20},
+		// Two hours missing
+		{RunID: "d", Timestamp: start.Add(3 * time.Hour), NetSentimentPercent: 50},
+		// An hour apart is not a gap at a 30-minute interval
+		{RunID: "e", Timestamp: start.Add(4 * time.Hour), NetSentimentPercent: 40},
+	}
+
+	filled := InterpolateGaps(points, 0)
+	if len(filled) != len(points)+3 {
+		t.Fatalf("InterpolateGaps() returned %d points, want %d", len(filled), len(points)+3)
+	}
+	for i, want := range []float64{27.5, 35, 42.5} {
+		point := filled[3+i]
+		if !point.Synthetic || point.NetSentimentPercent != want || point.RunID != "" {
+			t.Errorf("filled[%d] = %+v, want a synthetic point at %.1f%%", 3+i, point, want)
+		}
+		if wantTime := start.Add(time.Hour + time.Duration(i+1)*30*time.Minute); !point.Timestamp.Equal(wantTime) {
+			t.Errorf("filled[%d] at %s, want %s", 3+i, point.Timestamp, wantTime)
+		}
+	}
+	if filled[6].RunID != "d" || filled[7].RunID != "e" {
+		t.Errorf("expected the real points after the gap, got %+v", filled[6:])
+	}
+
+	if unchanged := InterpolateGaps(points[:3], 0); len(unchanged) != 3 {
+		t.Errorf("expected no points added without a gap, got %d", len(unchanged))
+	}
+}
+
+func TestInterpolateDailyGaps(t *testing.T) {
+	day := func(date string, average float64) YearlySparklineDataPoint {
+		timestamp, _ := time.Parse("2006-01-02", date)
+		return YearlySparklineDataPoint{Date: date, AverageSentiment: average, Timestamp: timestamp, NetSentimentPercent: average}
+	}
+	days := []YearlySparklineDataPoint{day("2025-02-27", 0), day("2025-03-02", -30), day("2025-03-03", 5)}
+
+	filled := InterpolateDailyGaps(days)
+	if len(filled) != 5 {
+		t.Fatalf("InterpolateDailyGaps() returned %d days, want 5", len(filled))
+	}
+	for i, want := range []struct {
+		date    string
+		average float64
+	}{{"2025-02-28", -10}, {"2025-03-01", -20}} {
+		got := filled[1+i]
+		if !got.Synthetic || got.Date != want.date || got.AverageSentiment != want.average || got.NetSentimentPercent != want.average {
+			t.Errorf("filled[%d] = %+v, want a synthetic %s at %.0f%%", 1+i, got, want.date, want.average)
+		}
+	}
+	if filled[3].Synthetic || filled[4].Synthetic {
+		t.Errorf("expected the stored days to stay real, got %+v", filled[3:])
+	}
+}
@@ -0,0 +1,36 @@
+package state
+
+// MediaStats describes how many of a run's posts embedded images, video, or link cards
+type MediaStats struct {
+	PostsWithMedia           int            `json:"postsWithMedia" dynamodbav:"postsWithMedia"`
+	PostsWithMediaPercent    float64        `json:"postsWithMediaPercent" dynamodbav:"postsWithMediaPercent"`
+	TopPostsWithMedia        int            `json:"topPostsWithMedia" dynamodbav:"topPostsWithMedia"`
+	TopPostsWithMediaPercent float64        `json:"topPostsWithMediaPercent" dynamodbav:"topPostsWithMediaPercent"`
+	ByKind                   map[string]int `json:"byKind,omitempty" dynamodbav:"byKind,omitempty"`
+}
+
+// CalculateMediaStats counts media posts across all analyzed posts and among the top posts
+// Percentages are 0 when there are no posts
+func CalculateMediaStats(posts, topPosts []Post) MediaStats {
+	stats := MediaStats{ByKind: make(map[string]int)}
+	for _, post := range posts {
+		if post.Media == "" {
+			continue
+		}
+		stats.PostsWithMedia++
+		stats.ByKind[post.Media]++
+	}
+	for _, post := range topPosts {
+		if post.Media != "" {
+			stats.TopPostsWithMedia++
+		}
+	}
+
+	if len(posts) > 0 {
+		stats.PostsWithMediaPercent = float64(stats.PostsWithMedia) / float64(len(posts)) * 100
+	}
+	if len(topPosts) > 0 {
+		stats.TopPostsWithMediaPercent = float64(stats.TopPostsWithMedia) / float64(len(topPosts)) * 100
+	}
+	return stats
+}
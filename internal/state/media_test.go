@@ -0,0 +1,32 @@
+package state
+
+import "testing"
+
+func TestCalculateMediaStats(t *testing.T) {
+	posts := []Post{
+		{URI: "a", Media: "image"},
+		{URI: "b", Media: "video"},
+		{URI: "c", Media: "image"},
+		{URI: "d"},
+	}
+	top := []Post{posts[0], posts[3]}
+
+	stats := CalculateMediaStats(posts, top)
+
+	if stats.PostsWithMedia != 3 || stats.PostsWithMediaPercent != 75 {
+		t.Errorf("posts with media = %d (%.1f%%), want 3 (75%%)", stats.PostsWithMedia, stats.PostsWithMediaPercent)
+	}
+	if stats.TopPostsWithMedia != 1 || stats.TopPostsWithMediaPercent != 50 {
+		t.Errorf("top posts with media = %d (%.1f%%), want 1 (50%%)", stats.TopPostsWithMedia, stats.TopPostsWithMediaPercent)
+	}
+	if stats.ByKind["image"] != 2 || stats.ByKind["video"] != 1 {
+		t.Errorf("ByKind = %v, want 2 images and 1 video", stats.ByKind)
+	}
+}
+
+func TestCalculateMediaStatsEmpty(t *testing.T) {
+	stats := CalculateMediaStats(nil, nil)
+	if stats.PostsWithMediaPercent != 0 || stats.TopPostsWithMediaPercent != 0 {
+		t.Errorf("empty stats = %+v, want zero percentages", stats)
+	}
+}
@@ -0,0 +1,79 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Partition of the cached moderation list members, one item per list URI; it is not a
+// run, so run listings never see it
+const moderationListRunID = "moderation-lists"
+
+// ModerationListTTL is how long cached members are kept, and so how long a list that can
+// no longer be fetched keeps filtering posts
+const ModerationListTTL = 30 * 24 * time.Hour
+
+// ModerationList is the cached membership of a subscribed Bluesky list
+type ModerationList struct {
+	RunID     string    `json:"runId" dynamodbav:"runId"`
+	PostID    string    `json:"postId" dynamodbav:"postId"` // the list's at:// URI
+	Name      string    `json:"name,omitempty" dynamodbav:"name,omitempty"`
+	Members   []string  `json:"members,omitempty" dynamodbav:"members,omitempty"` // member DIDs
+	FetchedAt time.Time `json:"fetchedAt" dynamodbav:"fetchedAt"`
+	TTL       int64     `json:"ttl" dynamodbav:"ttl"`
+}
+
+// GetModerationList retrieves a list's cached members, nil if none are cached
+func (sm *StateManager) GetModerationList(ctx context.Context, listURI string) (*ModerationList, error) {
+	result, err := sm.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(sm.tableName),
+		Key: map[string]types.AttributeValue{
+			"runId":  &types.AttributeValueMemberS{Value: moderationListRunID},
+			"postId": &types.AttributeValueMemberS{Value: listURI},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get moderation list: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var list ModerationList
+	if err := attributevalue.UnmarshalMap(result.Item, &list); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal moderation list: %w", err)
+	}
+	return &list, nil
+}
+
+// SaveModerationList caches a list's members, returning the cached list
+func (sm *StateManager) SaveModerationList(ctx context.Context, listURI, name string, members []string) (*ModerationList, error) {
+	now := time.Now().UTC()
+	list := ModerationList{
+		RunID:     moderationListRunID,
+		PostID:    listURI,
+		Name:      name,
+		Members:   members,
+		FetchedAt: now,
+		TTL:       now.Add(ModerationListTTL).Unix(),
+	}
+	item, err := attributevalue.MarshalMap(list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal moderation list: %w", err)
+	}
+
+	_, err = sm.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(sm.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save moderation list: %w", err)
+	}
+	return &list, nil
+}
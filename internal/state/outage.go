@@ -0,0 +1,94 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// outageRunID keys the outage items, one per channel; it is not a run, so run listings
+// never see them
+const outageRunID = "outage"
+
+// outageTTL expires an outage record a week after it was last saved, so one that never
+// saw a healthy run again doesn't linger
+const outageTTL = 7 * 24 * time.Hour
+
+// Outage records a Bluesky outage the processor's health gate detected for a channel, so
+// its notice is posted once rather than every run
+type Outage struct {
+	RunID  string `json:"runId" dynamodbav:"runId"`
+	PostID string `json:"postId" dynamodbav:"postId"` // The channel, "default" for the default channel
+	// Reason is why the run that started the outage failed the gate
+	Reason         string    `json:"reason" dynamodbav:"reason"`
+	StartedAt      time.Time `json:"startedAt" dynamodbav:"startedAt"`
+	NoticePostedAt time.Time `json:"noticePostedAt,omitempty" dynamodbav:"noticePostedAt,omitempty"`
+	// AdminMessagedAt is when the operator was sent a direct message about the outage
+	AdminMessagedAt time.Time `json:"adminMessagedAt,omitempty" dynamodbav:"adminMessagedAt,omitempty"`
+	// EndedAt is set by the first run to pass the gate again
+	EndedAt time.Time `json:"endedAt,omitempty" dynamodbav:"endedAt,omitempty"`
+	TTL     int64     `json:"ttl" dynamodbav:"ttl"`
+}
+
+// Ongoing reports whether the outage hasn't ended yet
+func (o *Outage) Ongoing() bool {
+	return o != nil && o.EndedAt.IsZero()
+}
+
+// outagePostID is the sort key of a channel's outage item
+func outagePostID(channelID string) string {
+	if channelID == "" {
+		return "default"
+	}
+	return channelID
+}
+
+// GetOutage retrieves the channel's last outage, nil if none is recorded
+func (sm *StateManager) GetOutage(ctx context.Context, channelID string) (*Outage, error) {
+	result, err := sm.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(sm.tableName),
+		Key: map[string]types.AttributeValue{
+			"runId":  &types.AttributeValueMemberS{Value: outageRunID},
+			"postId": &types.AttributeValueMemberS{Value: outagePostID(channelID)},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outage: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var outage Outage
+	if err := attributevalue.UnmarshalMap(result.Item, &outage); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal outage: %w", err)
+	}
+	return &outage, nil
+}
+
+// SaveOutage stores the channel's outage
+func (sm *StateManager) SaveOutage(ctx context.Context, channelID string, outage *Outage) error {
+	outage.RunID = outageRunID
+	outage.PostID = outagePostID(channelID)
+	outage.TTL = time.Now().Add(outageTTL).Unix()
+
+	item, err := attributevalue.MarshalMap(outage)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outage: %w", err)
+	}
+
+	_, err = sm.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(sm.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save outage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Key of the pin history item; it is not a run, so run listings never see it
+const (
+	pinHistoryRunID  = "pinned-posts"
+	pinHistoryPostID = "history"
+)
+
+// MaxPinHistory is how many past pins the history keeps
+const MaxPinHistory = 50
+
+// PinRecord is a post the bot pinned to its profile
+type PinRecord struct {
+	URI        string    `json:"uri" dynamodbav:"uri"`
+	CID        string    `json:"cid" dynamodbav:"cid"`
+	Kind       string    `json:"kind" dynamodbav:"kind"` // yearly, weekly or milestone
+	PinnedAt   time.Time `json:"pinnedAt" dynamodbav:"pinnedAt"`
+	UnpinnedAt time.Time `json:"unpinnedAt,omitempty" dynamodbav:"unpinnedAt,omitempty"`
+}
+
+// PinHistory is the currently pinned post and the posts pinned before it, most recent first
+// The item has no TTL, since the current pin must outlive any run.
+type PinHistory struct {
+	RunID     string      `json:"runId" dynamodbav:"runId"`
+	PostID    string      `json:"postId" dynamodbav:"postId"`
+	Current   *PinRecord  `json:"current,omitempty" dynamodbav:"current,omitempty"`
+	History   []PinRecord `json:"history,omitempty" dynamodbav:"history,omitempty"`
+	UpdatedAt time.Time   `json:"updatedAt" dynamodbav:"updatedAt"`
+}
+
+// Pin makes pin the current pin, moving the previous one into the history
+func (h *PinHistory) Pin(pin PinRecord) {
+	h.Unpin(pin.PinnedAt)
+	h.Current = &pin
+}
+
+// Unpin moves the current pin, if any, into the history as unpinned at the given time
+func (h *PinHistory) Unpin(at time.Time) {
+	if h.Current == nil {
+		return
+	}
+	previous := *h.Current
+	previous.UnpinnedAt = at
+	h.History = append([]PinRecord{previous}, h.History...)
+	if len(h.History) > MaxPinHistory {
+		h.History = h.History[:MaxPinHistory]
+	}
+	h.Current = nil
+}
+
+// GetPinHistory retrieves the pin history, empty if nothing has been pinned yet
+func (sm *StateManager) GetPinHistory(ctx context.Context) (*PinHistory, error) {
+	result, err := sm.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(sm.tableName),
+		Key: map[string]types.AttributeValue{
+			"runId":  &types.AttributeValueMemberS{Value: pinHistoryRunID},
+			"postId": &types.AttributeValueMemberS{Value: pinHistoryPostID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pin history: %w", err)
+	}
+
+	history := &PinHistory{RunID: pinHistoryRunID, PostID: pinHistoryPostID}
+	if result.Item == nil {
+		return history, nil
+	}
+	if err := attributevalue.UnmarshalMap(result.Item, history); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pin history: %w", err)
+	}
+	return history, nil
+}
+
+// SavePinHistory stores the pin history
+func (sm *StateManager) SavePinHistory(ctx context.Context, history *PinHistory) error {
+	history.RunID = pinHistoryRunID
+	history.PostID = pinHistoryPostID
+	history.UpdatedAt = time.Now().UTC()
+
+	item, err := attributevalue.MarshalMap(history)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pin history: %w", err)
+	}
+
+	_, err = sm.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(sm.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save pin history: %w", err)
+	}
+	return nil
+}
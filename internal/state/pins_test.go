@@ -0,0 +1,41 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPinHistoryPinMovesPreviousToHistory(t *testing.T) {
+	start := time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC)
+	history := &PinHistory{}
+
+	history.Pin(PinRecord{URI: "at://a", Kind: "yearly", PinnedAt: start})
+	history.Pin(PinRecord{URI: "at://b", Kind: "milestone", PinnedAt: start.Add(time.Hour)})
+
+	if history.Current == nil || history.Current.URI != "at://b" {
+		t.Fatalf("Expected at://b pinned, got %+v", history.Current)
+	}
+	if len(history.History) != 1 || history.History[0].URI != "at://a" {
+		t.Fatalf("Expected at://a in the history, got %+v", history.History)
+	}
+	if !history.History[0].UnpinnedAt.Equal(start.Add(time.Hour)) {
+		t.Errorf("Expected at://a unpinned when at://b was pinned, got %v", history.History[0].UnpinnedAt)
+	}
+
+	history.Unpin(start.Add(2 * time.Hour))
+	if history.Current != nil || len(history.History) != 2 || history.History[0].URI != "at://b" {
+		t.Errorf("Expected nothing pinned and at://b most recent in the history, got %+v / %+v", history.Current, history.History)
+	}
+}
+
+func TestPinHistoryIsCapped(t *testing.T) {
+	start := time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC)
+	history := &PinHistory{}
+	for i := 0; i < MaxPinHistory+5; i++ {
+		history.Pin(PinRecord{URI: "at://post", Kind: "weekly", PinnedAt: start.Add(time.Duration(i) * time.Hour)})
+	}
+
+	if len(history.History) != MaxPinHistory {
+		t.Errorf("Expected %d entries, got %d", MaxPinHistory, len(history.History))
+	}
+}
@@ -0,0 +1,239 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	// maxReadSegments caps ReadBudget.Segments
+	maxReadSegments = 16
+
+	// postWriteWindow is how long after a run starts its post batches are usually written,
+	// the span GetAllPosts divides between segments. Batches written later still land in
+	// the last segment.
+	postWriteWindow = time.Hour
+
+	// maxThrottledPageAttempts bounds the retries of a throttled page, after the SDK's own
+	maxThrottledPageAttempts = 6
+)
+
+// ReadBudget limits the read capacity GetAllPosts consumes, so reading a huge run doesn't
+// throttle the other lambdas sharing the table. The zero value reads without limits, a
+// page at a time, as GetAllPosts always has.
+type ReadBudget struct {
+	// CapacityUnits caps the read capacity units per second one GetAllPosts call
+	// consumes, across its segments; 0 leaves it unlimited
+	CapacityUnits float64 `json:"capacityUnits,omitempty"`
+	// Segments is how many ranges of a run's post batches GetAllPosts queries at once;
+	// 0 or 1 reads them in turn. Runs whose IDs don't carry their start time are read in
+	// turn whatever it says.
+	Segments int `json:"segments,omitempty"`
+}
+
+// Validate checks the budget's bounds
+func (b ReadBudget) Validate() error {
+	if b.CapacityUnits < 0 || math.IsNaN(b.CapacityUnits) || math.IsInf(b.CapacityUnits, 0) {
+		return fmt.Errorf("capacityUnits must be a positive number or 0, got %v", b.CapacityUnits)
+	}
+	if b.Segments < 0 || b.Segments > maxReadSegments {
+		return fmt.Errorf("segments must be between 0 and %d, got %d", maxReadSegments, b.Segments)
+	}
+	return nil
+}
+
+// SetReadBudget sets the read budget of later GetAllPosts calls
+func (sm *StateManager) SetReadBudget(budget ReadBudget) {
+	sm.readBudget = budget
+}
+
+// postKeyRange is an inclusive range of postId sort keys within a run's partition
+type postKeyRange struct {
+	from, to string
+}
+
+// postKeyRanges divides a run's post keys into segments ranges that together cover every
+// key starting "<runID>#". Batch keys embed the nanosecond time they were written after
+// "#batch", so the ranges split the hour after the run started, which run IDs of the
+// form "run-<nanoseconds>" give. Other run IDs get a single range.
+func postKeyRanges(runID string, segments int) []postKeyRange {
+	all := []postKeyRange{{from: runID + "#", to: runID + "$"}}
+	if segments <= 1 {
+		return all
+	}
+	started, err := strconv.ParseInt(strings.TrimPrefix(runID, "run-"), 10, 64)
+	if err != nil || !strings.HasPrefix(runID, "run-") || started <= 0 {
+		return all
+	}
+	segments = min(segments, maxReadSegments)
+
+	// Nanosecond times have 19 digits until 2286, so the keys sort in time order.
+	// Boundaries are never keys themselves, as keys carry a suffix after the time, so
+	// neighbouring inclusive ranges don't share items.
+	step := int64(postWriteWindow) / int64(segments)
+	ranges := make([]postKeyRange, segments)
+	from := all[0].from
+	for i := 0; i < segments-1; i++ {
+		boundary := fmt.Sprintf("%s#batch%d", runID, started+int64(i+1)*step)
+		ranges[i] = postKeyRange{from: from, to: boundary}
+		from = boundary
+	}
+	ranges[segments-1] = postKeyRange{from: from, to: all[0].to}
+	return ranges
+}
+
+// capacityLimiter paces the pages of one GetAllPosts call to a read capacity budget,
+// shared by its segments
+type capacityLimiter struct {
+	rate     float64
+	mu       sync.Mutex
+	started  time.Time
+	consumed float64
+}
+
+func newCapacityLimiter(rate float64) *capacityLimiter {
+	return &capacityLimiter{rate: rate, started: time.Now()}
+}
+
+// consume records units read and waits until the total fits the budget
+func (l *capacityLimiter) consume(ctx context.Context, units float64) error {
+	if l.rate <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	l.consumed += units
+	delay := capacityDelay(l.consumed, l.rate, time.Since(l.started))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// capacityDelay is how long to wait after consuming units in elapsed to keep to rate
+// units per second
+func capacityDelay(consumed, rate float64, elapsed time.Duration) time.Duration {
+	if rate <= 0 {
+		return 0
+	}
+	return time.Duration(consumed/rate*float64(time.Second)) - elapsed
+}
+
+// pageLimit sizes the next page to about a second of a segment's share of rate, from the
+// units per item the last page consumed, so one large page can't blow through the
+// budget. It returns 0, no limit, without a rate.
+func pageLimit(rate float64, segments int, unitsPerItem float64) int32 {
+	if rate <= 0 {
+		return 0
+	}
+	if unitsPerItem <= 0 {
+		// Nothing measured yet: read one item to learn its cost
+		return 1
+	}
+	share := rate / float64(max(segments, 1))
+	return int32(max(1, min(math.Floor(share/unitsPerItem), math.MaxInt32)))
+}
+
+// postRangeResult is what queryPostRange read from one range
+type postRangeResult struct {
+	posts []Post
+	pages int
+	units float64
+	err   error
+}
+
+// queryPostRange reads the posts in one key range page by page, paced by limiter. A
+// throttled page is retried with backoff at half the size. Strongly consistent reads query
+// the table rather than posts-index, which has the same keys.
+func (sm *StateManager) queryPostRange(ctx context.Context, runID string, keyRange postKeyRange, consistency ReadConsistency, limiter *capacityLimiter, segments int) postRangeResult {
+	var result postRangeResult
+	var lastEvaluatedKey map[string]types.AttributeValue
+	var unitsPerItem float64
+	throttled := 0
+
+	for {
+		queryInput := &dynamodb.QueryInput{
+			TableName:              aws.String(sm.tableName),
+			IndexName:              aws.String("posts-index"),
+			KeyConditionExpression: aws.String("runId = :runId AND postId BETWEEN :from AND :to"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":runId": &types.AttributeValueMemberS{Value: runID},
+				":from":  &types.AttributeValueMemberS{Value: keyRange.from},
+				":to":    &types.AttributeValueMemberS{Value: keyRange.to},
+			},
+			ExclusiveStartKey:      lastEvaluatedKey,
+			ReturnConsumedCapacity: types.ReturnConsumedCapacityTotal,
+		}
+		if consistency == StronglyConsistent {
+			queryInput.IndexName = nil
+			queryInput.ConsistentRead = aws.Bool(true)
+		}
+		limit := pageLimit(limiter.rate, segments, unitsPerItem)
+		if limit > 0 && throttled > 0 {
+			limit = max(1, limit>>throttled)
+		}
+		if limit > 0 {
+			queryInput.Limit = aws.Int32(limit)
+		}
+
+		page, err := sm.client.Query(ctx, queryInput)
+		if err != nil {
+			if !isThrottled(err) || throttled+1 >= maxThrottledPageAttempts {
+				result.err = fmt.Errorf("failed to query posts: %w", err)
+				return result
+			}
+			throttled++
+			select {
+			case <-time.After(batchWriteBackoff(throttled)):
+				continue
+			case <-ctx.Done():
+				result.err = ctx.Err()
+				return result
+			}
+		}
+		throttled = 0
+		result.pages++
+		result.posts = append(result.posts, decodePostItems(page.Items)...)
+
+		if page.ConsumedCapacity != nil && page.ConsumedCapacity.CapacityUnits != nil {
+			units := *page.ConsumedCapacity.CapacityUnits
+			result.units += units
+			if len(page.Items) > 0 {
+				unitsPerItem = units / float64(len(page.Items))
+			}
+			if err := limiter.consume(ctx, units); err != nil {
+				result.err = err
+				return result
+			}
+		}
+
+		if len(page.LastEvaluatedKey) == 0 {
+			return result
+		}
+		lastEvaluatedKey = page.LastEvaluatedKey
+	}
+}
+
+// isThrottled reports whether DynamoDB refused a request for exceeding capacity, after
+// the SDK ran out of retries
+func isThrottled(err error) bool {
+	var exceeded *types.ProvisionedThroughputExceededException
+	var limited *types.RequestLimitExceeded
+	return errors.As(err, &exceeded) || errors.As(err, &limited)
+}
@@ -0,0 +1,94 @@
+package state
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPostKeyRanges(t *testing.T) {
+	runID := "run-1760000000000000000"
+	ranges := postKeyRanges(runID, 4)
+	if len(ranges) != 4 {
+		t.Fatalf("postKeyRanges() returned %d ranges, want 4", len(ranges))
+	}
+	if ranges[0].from != runID+"#" || ranges[3].to != runID+"$" {
+		t.Errorf("ranges %+v don't cover every %s# key", ranges, runID)
+	}
+	for i := 1; i < len(ranges); i++ {
+		if ranges[i].from != ranges[i-1].to || ranges[i].from <= ranges[i-1].from {
+			t.Errorf("range %d %+v doesn't follow %+v", i, ranges[i], ranges[i-1])
+		}
+	}
+
+	// Each batch key lands in exactly one range, by when it was written
+	for _, written := range []time.Duration{0, 10 * time.Minute, 40 * time.Minute, 3 * time.Hour} {
+		key := fmt.Sprintf("%s#batch%d%04x-%d", runID, 1760000000000000000+int64(written), 0xbeef, 7)
+		matches := 0
+		for _, r := range ranges {
+			if key >= r.from && key <= r.to {
+				matches++
+			}
+		}
+		if matches != 1 {
+			t.Errorf("key written after %s is in %d ranges, want 1", written, matches)
+		}
+	}
+
+	for _, tt := range []struct {
+		runID    string
+		segments int
+	}{
+		{runID, 1},
+		{runID, 0},
+		{"replay-run-17", 4},
+		{"run-abc", 4},
+	} {
+		if got := postKeyRanges(tt.runID, tt.segments); len(got) != 1 {
+			t.Errorf("postKeyRanges(%q, %d) returned %d ranges, want 1", tt.runID, tt.segments, len(got))
+		}
+	}
+}
+
+func TestCapacityDelay(t *testing.T) {
+	if got := capacityDelay(50, 100, 0); got != 500*time.Millisecond {
+		t.Errorf("capacityDelay(50 units at 100/s) = %s, want 500ms", got)
+	}
+	if got := capacityDelay(50, 100, time.Second); got > 0 {
+		t.Errorf("expected no delay once the budget has caught up, got %s", got)
+	}
+	if got := capacityDelay(1000, 0, 0); got != 0 {
+		t.Errorf("expected no delay without a budget, got %s", got)
+	}
+}
+
+func TestPageLimit(t *testing.T) {
+	tests := []struct {
+		rate         float64
+		segments     int
+		unitsPerItem float64
+		want         int32
+	}{
+		{0, 1, 40, 0},
+		{100, 1, 0, 1},
+		{100, 1, 10, 10},
+		{100, 4, 10, 2},
+		{100, 1, 500, 1},
+	}
+	for _, tt := range tests {
+		if got := pageLimit(tt.rate, tt.segments, tt.unitsPerItem); got != tt.want {
+			t.Errorf("pageLimit(%v, %d, %v) = %d, want %d", tt.rate, tt.segments, tt.unitsPerItem, got, tt.want)
+		}
+	}
+}
+
+func TestReadBudgetValidate(t *testing.T) {
+	if err := (ReadBudget{CapacityUnits: 200, Segments: 4}).Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	for _, budget := range []ReadBudget{{CapacityUnits: -1}, {Segments: 17}, {Segments: -2}} {
+		if err := budget.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded, want error", budget)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
+)
+
+// Reprocessed results are stored beside the run they were computed from, one item per label:
+//
+//	postId = reprocess#<label>
+//
+// The run's own "orchestrator" item is never touched, and the "reprocess#" prefix keeps
+// these items out of GetAllPosts, which reads "<runId>#" keys.
+const reprocessPrefix = "reprocess#"
+
+// ReprocessParameters are the analysis parameters a reprocessed result was computed with
+type ReprocessParameters struct {
+	TopPosts           int     `json:"topPosts" dynamodbav:"topPosts"`
+	MediaRanking       string  `json:"mediaRanking" dynamodbav:"mediaRanking"`
+	ReplyRanking       string  `json:"replyRanking,omitempty" dynamodbav:"replyRanking,omitempty"`
+	ContentWarnings    string  `json:"contentWarnings,omitempty" dynamodbav:"contentWarnings,omitempty"`
+	TopPostsRanking    string  `json:"topPostsRanking,omitempty" dynamodbav:"topPostsRanking,omitempty"`
+	SentimentThreshold float64 `json:"sentimentThreshold" dynamodbav:"sentimentThreshold"`
+	MinPostCount       int     `json:"minPostCount" dynamodbav:"minPostCount"`
+	MinTopicPosts      int     `json:"minTopicPosts" dynamodbav:"minTopicPosts"`
+	Reanalyze          bool    `json:"reanalyze" dynamodbav:"reanalyze"`
+	// SentimentMethod is how scores were averaged; results from before it was recorded
+	// leave it zero, which is the mean
+	SentimentMethod aggregation.Method `json:"sentimentMethod" dynamodbav:"sentimentMethod"`
+}
+
+// ReprocessResult is a run re-analyzed from its stored posts with different parameters
+type ReprocessResult struct {
+	RunID                  string              `json:"runId" dynamodbav:"runId"`
+	PostID                 string              `json:"postId" dynamodbav:"postId"`
+	Label                  string              `json:"label" dynamodbav:"label"`
+	Parameters             ReprocessParameters `json:"parameters" dynamodbav:"parameters"`
+	TotalPosts             int                 `json:"totalPosts" dynamodbav:"totalPosts"`
+	OverallSentiment       string              `json:"overallSentiment,omitempty" dynamodbav:"overallSentiment,omitempty"`
+	NetSentimentPercentage float64             `json:"netSentimentPercentage" dynamodbav:"netSentimentPercentage"`
+	TopPosts               []Post              `json:"topPosts,omitempty" dynamodbav:"topPosts,omitempty"`
+	Topics                 []TopicSentiment    `json:"topics,omitempty" dynamodbav:"topics,omitempty"`
+	Emotions               *Emotions           `json:"emotions,omitempty" dynamodbav:"emotions,omitempty"`
+	SkipReason             string              `json:"skipReason,omitempty" dynamodbav:"skipReason,omitempty"`
+	CreatedAt              time.Time           `json:"createdAt" dynamodbav:"createdAt"`
+	TTL                    int64               `json:"ttl" dynamodbav:"ttl"`
+}
+
+// reprocessKey builds the sort key for a run's result under label
+func reprocessKey(label string) string {
+	return reprocessPrefix + label
+}
+
+// StoreReprocessResult writes a reprocessed result, replacing any earlier result with the
+// same label. It expires with the run it was computed from.
+func (sm *StateManager) StoreReprocessResult(ctx context.Context, run *RunState, result *ReprocessResult) error {
+	result.RunID = run.RunID
+	result.PostID = reprocessKey(result.Label)
+	result.CreatedAt = time.Now().UTC()
+	result.TTL = run.TTL
+
+	item, err := attributevalue.MarshalMap(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reprocess result: %w", err)
+	}
+
+	_, err = sm.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(sm.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store reprocess result: %w", err)
+	}
+	return nil
+}
+
+// GetReprocessResults returns every reprocessed result of a run, ordered by label
+func (sm *StateManager) GetReprocessResults(ctx context.Context, runID string) ([]ReprocessResult, error) {
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(sm.tableName),
+		KeyConditionExpression: aws.String("runId = :runId AND begins_with(postId, :prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":runId":  &types.AttributeValueMemberS{Value: runID},
+			":prefix": &types.AttributeValueMemberS{Value: reprocessPrefix},
+		},
+	}
+
+	var results []ReprocessResult
+	for {
+		output, err := sm.client.Query(ctx, queryInput)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query reprocess results: %w", err)
+		}
+		for _, item := range output.Items {
+			var result ReprocessResult
+			if err := attributevalue.UnmarshalMap(item, &result); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal reprocess result: %w", err)
+			}
+			results = append(results, result)
+		}
+		if len(output.LastEvaluatedKey) == 0 {
+			break
+		}
+		queryInput.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+	return results, nil
+}
@@ -0,0 +1,17 @@
+package state
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReprocessKey(t *testing.T) {
+	key := reprocessKey("threshold-0.2")
+	if key != "reprocess#threshold-0.2" {
+		t.Errorf("reprocessKey() = %s, want reprocess#threshold-0.2", key)
+	}
+	// GetAllPosts reads "<runId>#" keys; a result labelled like a run must not be read as posts
+	if strings.HasPrefix(reprocessKey("run-1"), "run-1#") {
+		t.Errorf("key %s would be read back by GetAllPosts", reprocessKey("run-1"))
+	}
+}
@@ -0,0 +1,81 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// BackfillRunIndex writes runIndex on the run states created before CreateRun set it;
+// created-index leaves them out, so until then ListRuns and GetRecentRuns miss them.
+// Runs that already have it are left alone, so it can be run again. Returns the number
+// of runs updated.
+func (sm *StateManager) BackfillRunIndex(ctx context.Context) (int, error) {
+	updated := 0
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		scanInput := &dynamodb.ScanInput{
+			TableName:            aws.String(sm.tableName),
+			FilterExpression:     aws.String("#postId = :postId AND attribute_not_exists(runIndex)"),
+			ProjectionExpression: aws.String("runId, channelId"),
+			ExpressionAttributeNames: map[string]string{
+				"#postId": "postId",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":postId": &types.AttributeValueMemberS{Value: "orchestrator"},
+			},
+		}
+
+		if lastEvaluatedKey != nil {
+			scanInput.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := sm.client.Scan(ctx, scanInput)
+		if err != nil {
+			return updated, fmt.Errorf("failed to scan runs without %s: %w", runsCreatedIndex, err)
+		}
+
+		for _, item := range result.Items {
+			var state RunState
+			if err := attributevalue.UnmarshalMap(item, &state); err != nil {
+				log.Printf("Warning: failed to unmarshal run state: %v", err)
+				continue
+			}
+
+			_, err := sm.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+				TableName: aws.String(sm.tableName),
+				Key: map[string]types.AttributeValue{
+					"runId":  &types.AttributeValueMemberS{Value: state.RunID},
+					"postId": &types.AttributeValueMemberS{Value: "orchestrator"},
+				},
+				UpdateExpression:    aws.String("SET runIndex = :runIndex"),
+				ConditionExpression: aws.String("attribute_exists(runId)"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":runIndex": &types.AttributeValueMemberS{Value: runIndexFor(state.ChannelID)},
+				},
+			})
+			var expired *types.ConditionalCheckFailedException
+			if errors.As(err, &expired) {
+				continue // Expired since the scan read it
+			}
+			if err != nil {
+				return updated, fmt.Errorf("failed to set runIndex on run %s: %w", state.RunID, err)
+			}
+			updated++
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,201 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
+)
+
+// DefaultSelfStatsTTL keeps a little over a year of the bot's own post engagement, enough
+// for month-on-month comparisons
+const DefaultSelfStatsTTL = 400 * 24 * time.Hour
+
+// The self stats table is partitioned by the month a post was made and sorted by its time:
+//
+//	month = 2025-01, postKey = 2025-01-05T12:30:00Z#at://did:plc:.../app.bsky.feed.post/...
+//
+// so a month's posts are read with one query. Measuring a post again replaces its item.
+const selfStatsMonthFormat = "2006-01"
+
+// PostStats is the engagement one of the bot's posts had when it was last measured
+type PostStats struct {
+	Month      string    `json:"month" dynamodbav:"month"`
+	PostKey    string    `json:"postKey" dynamodbav:"postKey"`
+	URI        string    `json:"uri" dynamodbav:"uri"`
+	Kind       string    `json:"kind" dynamodbav:"kind"`
+	PostedAt   time.Time `json:"postedAt" dynamodbav:"postedAt"`
+	Likes      int       `json:"likes" dynamodbav:"likes"`
+	Reposts    int       `json:"reposts" dynamodbav:"reposts"`
+	Replies    int       `json:"replies" dynamodbav:"replies"`
+	Quotes     int       `json:"quotes" dynamodbav:"quotes"`
+	MeasuredAt time.Time `json:"measuredAt" dynamodbav:"measuredAt"`
+	TTL        int64     `json:"ttl" dynamodbav:"ttl"`
+}
+
+// Total is the sum of every kind of engagement
+func (p PostStats) Total() int {
+	return p.Likes + p.Reposts + p.Replies + p.Quotes
+}
+
+// SelfStatsManager stores the engagement on the bot's own posts
+type SelfStatsManager struct {
+	client    *dynamodb.Client
+	tableName string
+	ttl       time.Duration
+}
+
+// NewSelfStatsManager creates a new self stats manager
+func NewSelfStatsManager(ctx context.Context, tableName string) (*SelfStatsManager, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, tracing.WithAWS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return &SelfStatsManager{
+		client:    dynamodb.NewFromConfig(cfg),
+		tableName: tableName,
+		ttl:       DefaultSelfStatsTTL,
+	}, nil
+}
+
+// SetTTL sets how long measured posts are kept after they were posted
+func (ssm *SelfStatsManager) SetTTL(ttl time.Duration) {
+	ssm.ttl = ttl
+}
+
+// StorePostStats writes the latest measurement of a post, replacing any earlier one
+func (ssm *SelfStatsManager) StorePostStats(ctx context.Context, stats PostStats) error {
+	postedAt := stats.PostedAt.UTC()
+	stats.Month = postedAt.Format(selfStatsMonthFormat)
+	stats.PostKey = postedAt.Format(time.RFC3339) + "#" + stats.URI
+	if stats.MeasuredAt.IsZero() {
+		stats.MeasuredAt = time.Now().UTC()
+	}
+	stats.TTL = postedAt.Add(ssm.ttl).Unix()
+
+	item, err := attributevalue.MarshalMap(stats)
+	if err != nil {
+		return fmt.Errorf("failed to marshal post stats: %w", err)
+	}
+
+	_, err = ssm.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(ssm.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store post stats for %s: %w", stats.URI, err)
+	}
+	return nil
+}
+
+// GetPostStats returns the stats of the posts made in [from, to), oldest first
+func (ssm *SelfStatsManager) GetPostStats(ctx context.Context, from, to time.Time) ([]PostStats, error) {
+	from, to = from.UTC(), to.UTC()
+
+	var posts []PostStats
+	for _, month := range selfStatsMonths(from, to) {
+		queryInput := &dynamodb.QueryInput{
+			TableName:              aws.String(ssm.tableName),
+			KeyConditionExpression: aws.String("#month = :month AND postKey BETWEEN :from AND :to"),
+			ExpressionAttributeNames: map[string]string{
+				"#month": "month",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":month": &types.AttributeValueMemberS{Value: month},
+				":from":  &types.AttributeValueMemberS{Value: from.Format(time.RFC3339)},
+				":to":    &types.AttributeValueMemberS{Value: to.Format(time.RFC3339)},
+			},
+		}
+
+		for {
+			output, err := ssm.client.Query(ctx, queryInput)
+			if err != nil {
+				return nil, fmt.Errorf("failed to query post stats for %s: %w", month, err)
+			}
+			for _, item := range output.Items {
+				var stats PostStats
+				if err := attributevalue.UnmarshalMap(item, &stats); err != nil {
+					return nil, fmt.Errorf("failed to unmarshal post stats: %w", err)
+				}
+				posts = append(posts, stats)
+			}
+			if len(output.LastEvaluatedKey) == 0 {
+				break
+			}
+			queryInput.ExclusiveStartKey = output.LastEvaluatedKey
+		}
+	}
+	return posts, nil
+}
+
+// selfStatsMonths lists the month partitions that hold posts made in [from, to)
+func selfStatsMonths(from, to time.Time) []string {
+	var months []string
+	month := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for month.Before(to) {
+		months = append(months, month.Format(selfStatsMonthFormat))
+		month = month.AddDate(0, 1, 0)
+	}
+	return months
+}
+
+// EngagementTrendPoint is the mean engagement per post of one kind of post on one day
+type EngagementTrendPoint struct {
+	Date    string  `json:"date"` // "2025-01-05"
+	Kind    string  `json:"kind"`
+	Posts   int     `json:"posts"`
+	Likes   float64 `json:"likes"`
+	Reposts float64 `json:"reposts"`
+	Replies float64 `json:"replies"`
+	Quotes  float64 `json:"quotes"`
+	Total   float64 `json:"total"`
+}
+
+// EngagementTrend groups posts by the day they were made and their kind, ordered by date
+// then kind
+func EngagementTrend(posts []PostStats) []EngagementTrendPoint {
+	type key struct{ date, kind string }
+	index := make(map[key]int)
+	var points []EngagementTrendPoint
+	for _, post := range posts {
+		k := key{post.PostedAt.UTC().Format("2006-01-02"), post.Kind}
+		i, ok := index[k]
+		if !ok {
+			i = len(points)
+			index[k] = i
+			points = append(points, EngagementTrendPoint{Date: k.date, Kind: k.kind})
+		}
+		p := &points[i]
+		p.Posts++
+		p.Likes += float64(post.Likes)
+		p.Reposts += float64(post.Reposts)
+		p.Replies += float64(post.Replies)
+		p.Quotes += float64(post.Quotes)
+	}
+
+	for i := range points {
+		p := &points[i]
+		n := float64(p.Posts)
+		p.Likes /= n
+		p.Reposts /= n
+		p.Replies /= n
+		p.Quotes /= n
+		p.Total = p.Likes + p.Reposts + p.Replies + p.Quotes
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].Date != points[j].Date {
+			return points[i].Date < points[j].Date
+		}
+		return points[i].Kind < points[j].Kind
+	})
+	return points
+}
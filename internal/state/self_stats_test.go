@@ -0,0 +1,43 @@
+package state
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSelfStatsMonths(t *testing.T) {
+	from := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	want := []string{"2024-12", "2025-01"}
+	if got := selfStatsMonths(from, to); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected months %v, got %v", want, got)
+	}
+}
+
+func TestEngagementTrend(t *testing.T) {
+	day := time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC)
+	posts := []PostStats{
+		{Kind: "text", PostedAt: day.Add(24 * time.Hour), Likes: 4},
+		{Kind: "image", PostedAt: day, Likes: 10, Reposts: 2},
+		{Kind: "image", PostedAt: day.Add(time.Hour), Likes: 20, Replies: 2},
+		{Kind: "reply", PostedAt: day, Quotes: 1},
+	}
+
+	points := EngagementTrend(posts)
+	if len(points) != 3 {
+		t.Fatalf("Expected 3 points, got %+v", points)
+	}
+
+	image := points[0]
+	if image.Date != "2025-01-05" || image.Kind != "image" || image.Posts != 2 {
+		t.Errorf("Expected two image posts on 2025-01-05 first, got %+v", image)
+	}
+	if image.Likes != 15 || image.Reposts != 1 || image.Replies != 1 || image.Total != 17 {
+		t.Errorf("Expected mean engagement 15/1/1 (17 total), got %+v", image)
+	}
+	if points[1].Kind != "reply" || points[2].Date != "2025-01-06" {
+		t.Errorf("Expected points ordered by date then kind, got %+v", points)
+	}
+}
@@ -0,0 +1,42 @@
+package state
+
+// SentimentCounts is how many of a run's posts were positive, neutral and negative. Net
+// sentiment nets positive against negative, so it can't tell a rise in neutral posts
+// from a fall in both; the counts can.
+type SentimentCounts struct {
+	Positive int `json:"positive" dynamodbav:"positive"`
+	Neutral  int `json:"neutral" dynamodbav:"neutral"`
+	Negative int `json:"negative" dynamodbav:"negative"`
+}
+
+// Total returns the number of posts counted
+func (c SentimentCounts) Total() int {
+	return c.Positive + c.Neutral + c.Negative
+}
+
+// Percentages returns each category's share of the posts counted, as percentages, or
+// zeros when none were
+func (c SentimentCounts) Percentages() (positive, neutral, negative float64) {
+	total := float64(c.Total())
+	if total == 0 {
+		return 0, 0, 0
+	}
+	return float64(c.Positive) / total * 100, float64(c.Neutral) / total * 100, float64(c.Negative) / total * 100
+}
+
+// CountSentiments counts posts by sentiment; posts that are neither positive nor negative
+// count as neutral, as in the net sentiment calculation
+func CountSentiments(posts []Post) SentimentCounts {
+	var counts SentimentCounts
+	for _, post := range posts {
+		switch post.Sentiment {
+		case "positive":
+			counts.Positive++
+		case "negative":
+			counts.Negative++
+		default:
+			counts.Neutral++
+		}
+	}
+	return counts
+}
@@ -0,0 +1,27 @@
+package state
+
+import "testing"
+
+func TestCountSentiments(t *testing.T) {
+	posts := []Post{
+		{URI: "a", Sentiment: "positive"},
+		{URI: "b", Sentiment: "negative"},
+		{URI: "c", Sentiment: "neutral"},
+		{URI: "d"},
+	}
+
+	counts := CountSentiments(posts)
+	if counts != (SentimentCounts{Positive: 1, Neutral: 2, Negative: 1}) {
+		t.Errorf("Unexpected counts %+v", counts)
+	}
+	if counts.Total() != 4 {
+		t.Errorf("Total() = %d, want 4", counts.Total())
+	}
+	if positive, neutral, negative := counts.Percentages(); positive != 25 || neutral != 50 || negative != 25 {
+		t.Errorf("Percentages() = %.1f, %.1f, %.1f, want 25, 50, 25", positive, neutral, negative)
+	}
+
+	if positive, neutral, negative := (SentimentCounts{}).Percentages(); positive != 0 || neutral != 0 || negative != 0 {
+		t.Errorf("Expected zero percentages with no posts, got %.1f, %.1f, %.1f", positive, neutral, negative)
+	}
+}
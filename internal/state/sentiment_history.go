@@ -13,6 +13,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
 )
 
 // SentimentDataPoint represents a single sentiment measurement at a point in time
@@ -25,17 +26,71 @@ type SentimentDataPoint struct {
 	TotalPosts           int       `json:"totalPosts" dynamodbav:"totalPosts"`
 	CreatedAt            time.Time `json:"createdAt" dynamodbav:"createdAt"`
 	TTL                  int64     `json:"ttl" dynamodbav:"ttl"`
+	// Network names the comparison network the point measures; empty means Bluesky
+	Network string `json:"network,omitempty" dynamodbav:"network,omitempty"`
+	// ToxicityPercent is the share of scored posts that were toxic, nil when not measured
+	ToxicityPercent *float64 `json:"toxicityPercent,omitempty" dynamodbav:"toxicityPercent,omitempty"`
+	// Emotions is the run's average emotion breakdown, nil when no post had emotion words
+	Emotions *Emotions `json:"emotions,omitempty" dynamodbav:"emotions,omitempty"`
+	// Counts are the run's positive, neutral and negative posts, nil for points stored
+	// before they were recorded
+	Counts *SentimentCounts `json:"counts,omitempty" dynamodbav:"counts,omitempty"`
+	// WeightedNetSentimentPercent is net sentiment with posts weighted by engagement, nil
+	// for points stored before it was recorded; NetSentimentPercent weighs posts equally
+	WeightedNetSentimentPercent *float64 `json:"weightedNetSentimentPercent,omitempty" dynamodbav:"weightedNetSentimentPercent,omitempty"`
+	// Topics is the sentiment of the run's trending topics, most-posted first
+	Topics []TopicSentiment `json:"topics,omitempty" dynamodbav:"topics,omitempty"`
+	// Domains are the run's most shared link domains, most shared first
+	Domains []DomainShare `json:"domains,omitempty" dynamodbav:"domains,omitempty"`
+	// Channel names the channel whose run the point measures; empty means the default channel
+	Channel string `json:"channel,omitempty" dynamodbav:"channel,omitempty"`
+	// Volatility is the standard deviation of net sentiment over the 24 hours up to the run,
+	// nil when history was too thin to measure it
+	Volatility *float64 `json:"volatility,omitempty" dynamodbav:"volatility,omitempty"`
+	// Series names the recomputed history series the point belongs to, e.g. "v2"; empty
+	// means the live series the pipeline records and the charts read
+	Series string `json:"series,omitempty" dynamodbav:"series,omitempty"`
+	// Synthetic marks a point InterpolateGaps made up to bridge a gap; it is never stored
+	Synthetic bool `json:"synthetic,omitempty" dynamodbav:"-"`
+}
+
+// NewSentimentDataPoint builds the sentiment history point for a run
+func NewSentimentDataPoint(runID, overallSentiment string, netSentimentPercentage float64, totalPosts int, timestamp time.Time) SentimentDataPoint {
+	// Convert sentiment category to compound score for storage
+	var averageCompoundScore float64
+	switch overallSentiment {
+	case "positive":
+		averageCompoundScore = 0.5 + (netSentimentPercentage / 200.0) // Scale to 0.5-1.0
+	case "negative":
+		averageCompoundScore = -0.5 - (netSentimentPercentage / 200.0) // Scale to -1.0 to -0.5
+	default: // neutral
+		averageCompoundScore = netSentimentPercentage / 100.0 // Scale to -1.0 to 1.0
+	}
+
+	return SentimentDataPoint{
+		RunID:                runID,
+		Timestamp:            timestamp,
+		AverageCompoundScore: averageCompoundScore,
+		NetSentimentPercent:  netSentimentPercentage,
+		SentimentCategory:    overallSentiment,
+		TotalPosts:           totalPosts,
+	}
 }
 
 // SentimentHistoryManager handles sentiment history operations
 type SentimentHistoryManager struct {
 	client    *dynamodb.Client
 	tableName string
+	ttl       time.Duration
+	// channel is the channel points are stored for and read from; empty is the default channel
+	channel string
+	// series is the history series points are stored in and read from; empty is the live series
+	series string
 }
 
 // NewSentimentHistoryManager creates a new sentiment history manager
 func NewSentimentHistoryManager(ctx context.Context, tableName string) (*SentimentHistoryManager, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := config.LoadDefaultConfig(ctx, tracing.WithAWS)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
@@ -45,15 +100,47 @@ func NewSentimentHistoryManager(ctx context.Context, tableName string) (*Sentime
 	return &SentimentHistoryManager{
 		client:    client,
 		tableName: tableName,
+		ttl:       DefaultSentimentHistoryTTL,
 	}, nil
 }
 
+// SetTTL sets how long new sentiment data points are kept before DynamoDB expires them
+func (shm *SentimentHistoryManager) SetTTL(ttl time.Duration) {
+	shm.ttl = ttl
+}
+
+// SetChannel scopes the manager to a channel's history: points are stored for it, and only
+// its points are read back. Each channel's history stays separate, so baselines and charts
+// of one channel never mix in another's runs.
+func (shm *SentimentHistoryManager) SetChannel(channelID string) {
+	shm.channel = channelID
+}
+
+// SetSeries scopes the manager to a recomputed history series: points are stored in it,
+// under their own run IDs so they never replace the live series' points, and only its
+// points are read back
+func (shm *SentimentHistoryManager) SetSeries(series string) {
+	shm.series = series
+}
+
+// SeriesRunID is the run ID a run's point is stored under in series
+func SeriesRunID(runID, series string) string {
+	if series == "" {
+		return runID
+	}
+	return runID + "#" + series
+}
+
 // StoreSentimentData stores a sentiment data point
 func (shm *SentimentHistoryManager) StoreSentimentData(ctx context.Context, dataPoint SentimentDataPoint) error {
+	dataPoint.Channel = shm.channel
+	if shm.series != "" && dataPoint.Series != shm.series {
+		dataPoint.RunID = SeriesRunID(dataPoint.RunID, shm.series)
+		dataPoint.Series = shm.series
+	}
 	// Set CreatedAt first, then TTL based on CreatedAt to ensure consistency
 	dataPoint.CreatedAt = time.Now()
-	// Set TTL to 14 days from creation time
-	dataPoint.TTL = dataPoint.CreatedAt.Add(14 * 24 * time.Hour).Unix()
+	dataPoint.TTL = dataPoint.CreatedAt.Add(shm.ttl).Unix()
 
 	item, err := attributevalue.MarshalMap(dataPoint)
 	if err != nil {
@@ -71,9 +158,19 @@ func (shm *SentimentHistoryManager) StoreSentimentData(ctx context.Context, data
 	return nil
 }
 
-// GetSentimentHistory retrieves sentiment data for a given time range
+// GetSentimentHistory retrieves Bluesky sentiment data for a given time range
 // Handles pagination to retrieve all data points across multiple DynamoDB pages
 func (shm *SentimentHistoryManager) GetSentimentHistory(ctx context.Context, duration time.Duration) ([]SentimentDataPoint, error) {
+	return shm.scanSentimentHistory(ctx, duration, "")
+}
+
+// GetNetworkSentimentHistory retrieves a comparison network's sentiment data for a given time range
+func (shm *SentimentHistoryManager) GetNetworkSentimentHistory(ctx context.Context, network string, duration time.Duration) ([]SentimentDataPoint, error) {
+	return shm.scanSentimentHistory(ctx, duration, network)
+}
+
+// scanSentimentHistory reads the points of one network ("" for Bluesky) since duration ago
+func (shm *SentimentHistoryManager) scanSentimentHistory(ctx context.Context, duration time.Duration, network string) ([]SentimentDataPoint, error) {
 	// Calculate the start time for the query
 	startTime := time.Now().Add(-duration)
 
@@ -115,6 +212,9 @@ func (shm *SentimentHistoryManager) GetSentimentHistory(ctx context.Context, dur
 			if err != nil {
 				continue // Skip invalid items
 			}
+			if dataPoint.Network != network || dataPoint.Channel != shm.channel || dataPoint.Series != shm.series {
+				continue
+			}
 			allDataPoints = append(allDataPoints, dataPoint)
 		}
 
@@ -281,3 +381,84 @@ func (shm *SentimentHistoryManager) DeleteSentimentData(ctx context.Context, run
 
 	return dataPoint, nil
 }
+
+// GetPointNearTime returns the data point closest to target within tolerance either side,
+// or nil when history has no point that close
+func (shm *SentimentHistoryManager) GetPointNearTime(ctx context.Context, target time.Time, tolerance time.Duration) (*SentimentDataPoint, error) {
+	var candidates []SentimentDataPoint
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		scanInput := &dynamodb.ScanInput{
+			TableName:        aws.String(shm.tableName),
+			FilterExpression: aws.String("#timestamp BETWEEN :startTime AND :endTime"),
+			ExpressionAttributeNames: map[string]string{
+				"#timestamp": "timestamp",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":startTime": &types.AttributeValueMemberS{Value: target.Add(-tolerance).UTC().Format(time.RFC3339)},
+				":endTime":   &types.AttributeValueMemberS{Value: target.Add(tolerance).UTC().Format(time.RFC3339)},
+			},
+		}
+
+		if lastEvaluatedKey != nil {
+			scanInput.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := shm.client.Scan(ctx, scanInput)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query sentiment point near %s: %w", target.Format(time.RFC3339), err)
+		}
+
+		for _, item := range result.Items {
+			var dataPoint SentimentDataPoint
+			if err := attributevalue.UnmarshalMap(item, &dataPoint); err != nil {
+				continue
+			}
+			if dataPoint.Network != "" || dataPoint.Channel != shm.channel || dataPoint.Series != shm.series {
+				continue // Comparison networks, other channels and other series never stand in for this channel
+			}
+			candidates = append(candidates, dataPoint)
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return closestPoint(candidates, target, tolerance), nil
+}
+
+// closestPoint picks the data point nearest to target, ignoring points outside tolerance
+func closestPoint(points []SentimentDataPoint, target time.Time, tolerance time.Duration) *SentimentDataPoint {
+	var closest *SentimentDataPoint
+	for i := range points {
+		distance := abs(points[i].Timestamp.Sub(target))
+		if distance > tolerance {
+			continue
+		}
+		if closest == nil || distance < abs(closest.Timestamp.Sub(target)) {
+			closest = &points[i]
+		}
+	}
+	return closest
+}
+
+// DayAverage averages the net sentiment of the Bluesky points recorded on day's UTC date
+func DayAverage(points []SentimentDataPoint, day time.Time) (average float64, runs int) {
+	date := day.UTC().Format("2006-01-02")
+	var sum float64
+	for _, point := range points {
+		if point.Network != "" || point.Timestamp.UTC().Format("2006-01-02") != date {
+			continue
+		}
+		sum += point.NetSentimentPercent
+		runs++
+	}
+	if runs == 0 {
+		return 0, 0
+	}
+	return sum / float64(runs), runs
+}
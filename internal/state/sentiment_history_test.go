@@ -0,0 +1,65 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClosestPoint(t *testing.T) {
+	target := time.Date(2025, 1, 7, 14, 0, 0, 0, time.UTC)
+	points := []SentimentDataPoint{
+		{RunID: "early", Timestamp: target.Add(-20 * time.Minute)},
+		{RunID: "near", Timestamp: target.Add(5 * time.Minute)},
+		{RunID: "far", Timestamp: target.Add(2 * time.Hour)},
+	}
+
+	closest := closestPoint(points, target, 30*time.Minute)
+	if closest == nil || closest.RunID != "near" {
+		t.Fatalf("expected the point 5 minutes away, got %+v", closest)
+	}
+
+	if closest := closestPoint(points, target.Add(-90*time.Minute), 30*time.Minute); closest != nil {
+		t.Errorf("expected no point within tolerance, got %+v", closest)
+	}
+
+	if closest := closestPoint(nil, target, time.Hour); closest != nil {
+		t.Errorf("expected nil for empty history, got %+v", closest)
+	}
+}
+
+func TestDayAverage(t *testing.T) {
+	day := time.Date(2025, 1, 7, 14, 0, 0, 0, time.UTC)
+	points := []SentimentDataPoint{
+		{Timestamp: day.Add(-time.Hour), NetSentimentPercent: 10},
+		{Timestamp: day.Add(-3 * time.Hour), NetSentimentPercent: 20},
+		{Timestamp: day.Add(-15 * time.Hour), NetSentimentPercent: 90},                     // the day before
+		{Timestamp: day.Add(-2 * time.Hour), NetSentimentPercent: 90, Network: "mastodon"}, // comparison network
+	}
+
+	if average, runs := DayAverage(points, day); runs != 2 || average != 15 {
+		t.Errorf("DayAverage() = %.1f over %d runs, want 15.0 over 2", average, runs)
+	}
+	if _, runs := DayAverage(points, day.AddDate(0, 0, 2)); runs != 0 {
+		t.Errorf("Expected no runs two days later, got %d", runs)
+	}
+}
+
+func TestSeriesRunID(t *testing.T) {
+	if got := SeriesRunID("run-1", ""); got != "run-1" {
+		t.Errorf("SeriesRunID() of the live series = %q, want the run ID", got)
+	}
+	if got := SeriesRunID("run-1", "v2"); got != "run-1#v2" {
+		t.Errorf("SeriesRunID() = %q, want %q", got, "run-1#v2")
+	}
+}
+
+func TestNewSentimentDataPoint(t *testing.T) {
+	at := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	point := NewSentimentDataPoint("run-1", "positive", 40, 1200, at)
+	if point.RunID != "run-1" || !point.Timestamp.Equal(at) || point.TotalPosts != 1200 || point.SentimentCategory != "positive" {
+		t.Errorf("NewSentimentDataPoint() = %+v", point)
+	}
+	if point.AverageCompoundScore != 0.7 {
+		t.Errorf("AverageCompoundScore = %v, want 0.7", point.AverageCompoundScore)
+	}
+}
@@ -2,9 +2,14 @@ package state
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math/rand/v2"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -12,6 +17,9 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
+	"github.com/christophergentle/hourstats-bsky/internal/provenance"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
 )
 
 // RunState represents the state of a single analysis run
@@ -22,17 +30,80 @@ type RunState struct {
 	Status                  string    `json:"status" dynamodbav:"status"`
 	AnalysisIntervalMinutes int       `json:"analysisIntervalMinutes" dynamodbav:"analysisIntervalMinutes"`
 	CutoffTime              time.Time `json:"cutoffTime" dynamodbav:"cutoffTime"`
-	CurrentCursor           string    `json:"currentCursor,omitempty" dynamodbav:"currentCursor,omitempty"`
-	TotalPostsRetrieved     int       `json:"totalPostsRetrieved" dynamodbav:"totalPostsRetrieved"`
-	HasMorePosts            bool      `json:"hasMorePosts" dynamodbav:"hasMorePosts"`
-	OverallSentiment        string    `json:"overallSentiment,omitempty" dynamodbav:"overallSentiment,omitempty"`
-	NetSentimentPercentage  float64   `json:"netSentimentPercentage,omitempty" dynamodbav:"netSentimentPercentage,omitempty"`
-	TopPosts                []Post    `json:"topPosts,omitempty" dynamodbav:"topPosts,omitempty"`
-	TopPostURI              string    `json:"topPostURI,omitempty" dynamodbav:"topPostURI,omitempty"`
-	TopPostCID              string    `json:"topPostCID,omitempty" dynamodbav:"topPostCID,omitempty"`
-	CreatedAt               time.Time `json:"createdAt" dynamodbav:"createdAt"`
-	UpdatedAt               time.Time `json:"updatedAt" dynamodbav:"updatedAt"`
-	TTL                     int64     `json:"ttl" dynamodbav:"ttl"`
+	// RequestedIntervalMinutes is the interval the schedule asked for; AnalysisIntervalMinutes
+	// may differ when the orchestrator adapts the window to recent post volume
+	RequestedIntervalMinutes int       `json:"requestedIntervalMinutes,omitempty" dynamodbav:"requestedIntervalMinutes,omitempty"`
+	IntervalReason           string    `json:"intervalReason,omitempty" dynamodbav:"intervalReason,omitempty"`
+	CurrentCursor            string    `json:"currentCursor,omitempty" dynamodbav:"currentCursor,omitempty"`
+	TotalPostsRetrieved      int       `json:"totalPostsRetrieved" dynamodbav:"totalPostsRetrieved"`
+	FetchCheckpoints         int       `json:"fetchCheckpoints,omitempty" dynamodbav:"fetchCheckpoints,omitempty"`
+	FetchElapsedMs           int64     `json:"fetchElapsedMs,omitempty" dynamodbav:"fetchElapsedMs,omitempty"`
+	HasMorePosts             bool      `json:"hasMorePosts" dynamodbav:"hasMorePosts"`
+	// FetchTruncated is set when the fetcher ran out of invocations before reaching the
+	// cutoff, so the run holds only the newest part of its window
+	FetchTruncated bool `json:"fetchTruncated,omitempty" dynamodbav:"fetchTruncated,omitempty"`
+	// FetchRequests and FetchErrors count the API requests the fetcher made for the run and
+	// how many of them failed, across its invocations
+	FetchRequests int `json:"fetchRequests,omitempty" dynamodbav:"fetchRequests,omitempty"`
+	FetchErrors   int `json:"fetchErrors,omitempty" dynamodbav:"fetchErrors,omitempty"`
+	CoveragePercent          float64   `json:"coveragePercent,omitempty" dynamodbav:"coveragePercent,omitempty"`
+	EarliestPostAt           time.Time `json:"earliestPostAt,omitempty" dynamodbav:"earliestPostAt,omitempty"`
+	LatestPostAt             time.Time `json:"latestPostAt,omitempty" dynamodbav:"latestPostAt,omitempty"`
+	OverallSentiment         string    `json:"overallSentiment,omitempty" dynamodbav:"overallSentiment,omitempty"`
+	NetSentimentPercentage   float64   `json:"netSentimentPercentage,omitempty" dynamodbav:"netSentimentPercentage,omitempty"`
+	TopPosts                 []Post    `json:"topPosts,omitempty" dynamodbav:"topPosts,omitempty"`
+	TopPostURI               string    `json:"topPostURI,omitempty" dynamodbav:"topPostURI,omitempty"`
+	TopPostCID               string    `json:"topPostCID,omitempty" dynamodbav:"topPostCID,omitempty"`
+	CreatedAt                time.Time `json:"createdAt" dynamodbav:"createdAt"`
+	UpdatedAt                time.Time `json:"updatedAt" dynamodbav:"updatedAt"`
+	TTL                      int64     `json:"ttl" dynamodbav:"ttl"`
+
+	// RunIndex is the created-index partition key; every run of a channel shares the same value
+	RunIndex string `json:"runIndex,omitempty" dynamodbav:"runIndex,omitempty"`
+
+	// ChannelID names the channel the run belongs to; empty means the default channel
+	ChannelID string `json:"channelId,omitempty" dynamodbav:"channelId,omitempty"`
+
+	// FeedURI and FeedLabel name the custom feed or list the run analyzed; empty means global search
+	FeedURI   string `json:"feedUri,omitempty" dynamodbav:"feedUri,omitempty"`
+	FeedLabel string `json:"feedLabel,omitempty" dynamodbav:"feedLabel,omitempty"`
+	// SearchQueries scope the run to posts matching any of these queries; empty means all posts
+	SearchQueries []string `json:"searchQueries,omitempty" dynamodbav:"searchQueries,omitempty"`
+
+	// Manual marks a run an operator started over an explicit past window with the
+	// orchestrator's manualRun action; SkipPosting analyzes and stores it without posting
+	Manual      bool `json:"manual,omitempty" dynamodbav:"manual,omitempty"`
+	SkipPosting bool `json:"skipPosting,omitempty" dynamodbav:"skipPosting,omitempty"`
+
+	// SentimentMethod is how the run's scores were averaged into NetSentimentPercentage,
+	// nil for runs from before it was recorded, which used the mean
+	SentimentMethod *aggregation.Method `json:"sentimentMethod,omitempty" dynamodbav:"sentimentMethod,omitempty"`
+
+	// Provenance records the analyzer, lexicons, scoring and build that produced the run's
+	// figures, nil for runs from before it was recorded
+	Provenance *provenance.Record `json:"provenance,omitempty" dynamodbav:"provenance,omitempty"`
+
+	// Media summarises how many posts, and how many top posts, carried media
+	Media *MediaStats `json:"media,omitempty" dynamodbav:"media,omitempty"`
+
+	// BlockedPosts counts the posts the blocklist left out of the run, by entry kind
+	BlockedPosts map[string]int `json:"blockedPosts,omitempty" dynamodbav:"blockedPosts,omitempty"`
+
+	// ContentWarningPosts counts the run's posts whose authors put content warnings on them
+	ContentWarningPosts int `json:"contentWarningPosts,omitempty" dynamodbav:"contentWarningPosts,omitempty"`
+
+	// SkipReason explains why a run finished without posting (e.g. too few posts)
+	SkipReason string `json:"skipReason,omitempty" dynamodbav:"skipReason,omitempty"`
+
+	// SkippedPosts records posts the posting schedule withheld for this run
+	SkippedPosts []SkippedPost `json:"skippedPosts,omitempty" dynamodbav:"skippedPosts,omitempty"`
+
+	// Experiment and Variant name the A/B experiment the summary was posted in and its layout
+	Experiment string `json:"experiment,omitempty" dynamodbav:"experiment,omitempty"`
+	Variant    string `json:"variant,omitempty" dynamodbav:"variant,omitempty"`
+
+	// StepTimings is the run's audit trail, one entry per pipeline step
+	StepTimings []StepTiming `json:"stepTimings,omitempty" dynamodbav:"stepTimings,omitempty"`
 
 	// Error tracking fields
 	ErrorMessage  string    `json:"errorMessage,omitempty" dynamodbav:"errorMessage,omitempty"`
@@ -46,6 +117,18 @@ type RunState struct {
 	MemoryUsageMB    int64   `json:"memoryUsageMB" dynamodbav:"memoryUsageMB"`
 }
 
+// WindowEnd returns when the run's analysis window ends
+func (r *RunState) WindowEnd() time.Time {
+	return r.CutoffTime.Add(time.Duration(r.AnalysisIntervalMinutes) * time.Minute)
+}
+
+// SkippedPost records a post that a poster chose not to publish
+type SkippedPost struct {
+	Poster string    `json:"poster" dynamodbav:"poster"`
+	Reason string    `json:"reason" dynamodbav:"reason"`
+	At     time.Time `json:"at" dynamodbav:"at"`
+}
+
 // Post represents a single post in the state
 type Post struct {
 	URI             string  `json:"uri" dynamodbav:"uri"`
@@ -58,6 +141,53 @@ type Post struct {
 	Sentiment       string  `json:"sentiment" dynamodbav:"sentiment"`
 	EngagementScore float64 `json:"engagementScore" dynamodbav:"engagementScore"`
 	CreatedAt       string  `json:"createdAt" dynamodbav:"createdAt"`
+	// SentimentScore is the post's VADER compound score, set once the post is analyzed
+	SentimentScore float64 `json:"sentimentScore,omitempty" dynamodbav:"sentimentScore,omitempty"`
+	// Langs are the language tags the author declared on the post
+	Langs []string `json:"langs,omitempty" dynamodbav:"langs,omitempty"`
+	// Media is the kind of embedded media ("image", "video", "external"), empty for text-only posts
+	Media string `json:"media,omitempty" dynamodbav:"media,omitempty"`
+	// LinkURL is the URL of the post's external link card, empty when it has none
+	LinkURL string `json:"linkUrl,omitempty" dynamodbav:"linkUrl,omitempty"`
+	// ImageText is the alt text, or text read from, the post's images when the image text
+	// setting collects it; it is analyzed with the post's text but never shown
+	ImageText string `json:"imageText,omitempty" dynamodbav:"imageText,omitempty"`
+	// ReplyRoot is the URI of the thread's root post when the post is a reply, empty for root posts
+	ReplyRoot string `json:"replyRoot,omitempty" dynamodbav:"replyRoot,omitempty"`
+	// ContentWarnings are the content warnings the author applied to the post, e.g.
+	// "graphic-media"; such posts count toward sentiment but are kept out of the top posts
+	// unless the content warnings setting shows them
+	ContentWarnings []string `json:"contentWarnings,omitempty" dynamodbav:"contentWarnings,omitempty"`
+	// AgeMinutes is how old the post was when it was fetched, the age its engagement counts
+	// were measured at; zero for posts fetched before it was recorded
+	AgeMinutes float64 `json:"ageMinutes,omitempty" dynamodbav:"ageMinutes,omitempty"`
+	// AvatarURL is the author's avatar, used to draw the top posts card
+	AvatarURL string `json:"avatarUrl,omitempty" dynamodbav:"avatarUrl,omitempty"`
+	// Emotions is the post's emotion vector, set once the post is analyzed if it has emotion words
+	Emotions *Emotions `json:"emotions,omitempty" dynamodbav:"emotions,omitempty"`
+	// Topics are the post's hashtags and keyword topics, set once the post is analyzed
+	Topics []string `json:"topics,omitempty" dynamodbav:"topics,omitempty"`
+	// Analyzed marks a post analyzed when it was fetched; the processor then uses its
+	// stored sentiment, emotions and topics rather than analyzing it again
+	Analyzed bool `json:"analyzed,omitempty" dynamodbav:"analyzed,omitempty"`
+}
+
+// IsReply reports whether the post replies to another post
+func (p Post) IsReply() bool {
+	return p.ReplyRoot != ""
+}
+
+// HasContentWarning reports whether the author put a content warning on the post
+func (p Post) HasContentWarning() bool {
+	return len(p.ContentWarnings) > 0
+}
+
+// AnalysisText is the text analyzed for the post: its own text followed by its image text
+func (p Post) AnalysisText() string {
+	if p.ImageText == "" {
+		return p.Text
+	}
+	return p.Text + "\n" + p.ImageText
 }
 
 // PostItem represents a post stored separately in DynamoDB
@@ -80,15 +210,25 @@ type PostBatch struct {
 	TTL       int64     `json:"ttl" dynamodbav:"ttl"`
 }
 
+// Default TTLs per table; each manager's SetTTL overrides them
+const (
+	DefaultStateTTL            = 2 * 24 * time.Hour
+	DefaultSentimentHistoryTTL = 14 * 24 * time.Hour
+	DefaultDailySentimentTTL   = 3 * 365 * 24 * time.Hour
+)
+
 // StateManager handles DynamoDB state operations
 type StateManager struct {
 	client    *dynamodb.Client
 	tableName string
+	ttl       time.Duration
+	// readBudget paces and splits GetAllPosts; the zero value reads without limits
+	readBudget ReadBudget
 }
 
 // NewStateManager creates a new state manager
 func NewStateManager(ctx context.Context, tableName string) (*StateManager, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := config.LoadDefaultConfig(ctx, tracing.WithAWS)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
@@ -96,15 +236,27 @@ func NewStateManager(ctx context.Context, tableName string) (*StateManager, erro
 	return &StateManager{
 		client:    dynamodb.NewFromConfig(cfg),
 		tableName: tableName,
+		ttl:       DefaultStateTTL,
 	}, nil
 }
 
+// SetTTL sets how long new run states and post batches are kept before DynamoDB expires them
+func (sm *StateManager) SetTTL(ttl time.Duration) {
+	sm.ttl = ttl
+}
+
 // CreateRun creates a new analysis run state
 // cutoffTime should be the cutoff time calculated at the start of the workflow
 // If cutoffTime is zero, it will be calculated from analysisIntervalMinutes
 func (sm *StateManager) CreateRun(ctx context.Context, runID string, analysisIntervalMinutes int, cutoffTime time.Time) (*RunState, error) {
+	return sm.CreateChannelRun(ctx, "", runID, analysisIntervalMinutes, cutoffTime)
+}
+
+// CreateChannelRun creates a new analysis run state for a channel, listed under the
+// channel's own runIndex partition; an empty channelID is the default channel
+func (sm *StateManager) CreateChannelRun(ctx context.Context, channelID, runID string, analysisIntervalMinutes int, cutoffTime time.Time) (*RunState, error) {
 	now := time.Now().UTC() // Use UTC to match API timestamps
-	ttl := now.Add(2 * 24 * time.Hour).Unix() // 2 days TTL
+	ttl := now.Add(sm.ttl).Unix()
 
 	// Use provided cutoffTime, or calculate it if not provided (for backward compatibility)
 	if cutoffTime.IsZero() {
@@ -123,6 +275,8 @@ func (sm *StateManager) CreateRun(ctx context.Context, runID string, analysisInt
 		CreatedAt:               now,
 		UpdatedAt:               now,
 		TTL:                     ttl,
+		RunIndex:                runIndexFor(channelID),
+		ChannelID:               channelID,
 	}
 
 	item, err := attributevalue.MarshalMap(state)
@@ -161,14 +315,15 @@ func (sm *StateManager) UpdateRun(ctx context.Context, state *RunState) error {
 	return nil
 }
 
-// GetRun retrieves a run state by runID and step
-func (sm *StateManager) GetRun(ctx context.Context, runID, step string) (*RunState, error) {
+// GetRun retrieves a run state by runID and step, read with the given consistency
+func (sm *StateManager) GetRun(ctx context.Context, runID, step string, consistency ReadConsistency) (*RunState, error) {
 	result, err := sm.client.GetItem(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(sm.tableName),
 		Key: map[string]types.AttributeValue{
 			"runId":  &types.AttributeValueMemberS{Value: runID},
 			"postId": &types.AttributeValueMemberS{Value: step}, // For RunState, postId = step
 		},
+		ConsistentRead: aws.Bool(bool(consistency)),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get run state: %w", err)
@@ -188,203 +343,215 @@ func (sm *StateManager) GetRun(ctx context.Context, runID, step string) (*RunSta
 }
 
 // GetLatestRun retrieves the latest run state for a given runID
+// It reads strongly consistently, as the run state setters update what it returns
 func (sm *StateManager) GetLatestRun(ctx context.Context, runID string) (*RunState, error) {
 	// Get the run state (orchestrator step)
-	return sm.GetRun(ctx, runID, "orchestrator")
+	return sm.GetRun(ctx, runID, "orchestrator", StronglyConsistent)
 }
 
 // AddPosts adds posts to the run state by storing them in batches for cost efficiency
+// Batches are split to stay under DynamoDB's item and request limits, and each call writes
+// uniquely keyed batches so concurrent callers never overwrite each other. If some batches
+// cannot be written, the stored posts are still counted and a *PartialWriteError is returned.
 func (sm *StateManager) AddPosts(ctx context.Context, runID string, posts []Post) error {
 	// Try to get fetcher step first, fall back to orchestrator step
-	state, err := sm.GetRun(ctx, runID, "fetcher")
+	state, err := sm.GetRun(ctx, runID, "fetcher", StronglyConsistent)
 	if err != nil {
 		// If fetcher step doesn't exist, get orchestrator step
-		state, err = sm.GetRun(ctx, runID, "orchestrator")
+		state, err = sm.GetRun(ctx, runID, "orchestrator", StronglyConsistent)
 		if err != nil {
 			return fmt.Errorf("failed to get current state: %w", err)
 		}
 	}
 
-	// Store posts in batches of 100 for cost efficiency
+	// Store posts in batches of up to 100 for cost efficiency
 	// This reduces the number of DynamoDB items by 99% (100 posts per item vs 1 post per item)
-	const postsPerBatch = 100
-	
-	// CRITICAL FIX: Calculate starting batchIndex from existing batches to avoid overwriting
-	// Query existing batches to find the highest batch index (handle pagination)
-	maxBatchIndex := -1
-	var lastEvaluatedKey map[string]types.AttributeValue
-	
-	for {
-		existingBatchesQuery := &dynamodb.QueryInput{
-			TableName:              aws.String(sm.tableName),
-			IndexName:              aws.String("posts-index"),
-			KeyConditionExpression: aws.String("runId = :runId AND begins_with(postId, :postIdPrefix)"),
-			ExpressionAttributeValues: map[string]types.AttributeValue{
-				":runId":        &types.AttributeValueMemberS{Value: runID},
-				":postIdPrefix": &types.AttributeValueMemberS{Value: runID + "#batch"},
-			},
-			ProjectionExpression: aws.String("postId"),
-		}
-		
-		if lastEvaluatedKey != nil {
-			existingBatchesQuery.ExclusiveStartKey = lastEvaluatedKey
-		}
-		
-		result, err := sm.client.Query(ctx, existingBatchesQuery)
-		if err != nil {
-			log.Printf("Warning: Failed to query existing batches: %v, starting from batch 0", err)
-			break
-		}
-		
-		// Parse batch indices from existing PostIDs
-		for _, item := range result.Items {
-			var batch PostBatch
-			if err := attributevalue.UnmarshalMap(item, &batch); err == nil {
-				// Extract batch number from PostID like "runId#batch42"
-				var batchNum int
-				if _, parseErr := fmt.Sscanf(batch.PostID, runID+"#batch%d", &batchNum); parseErr == nil {
-					if batchNum > maxBatchIndex {
-						maxBatchIndex = batchNum
-					}
-				}
-			}
-		}
-		
-		// Check if there are more pages
-		if len(result.LastEvaluatedKey) == 0 {
-			break
-		}
-		lastEvaluatedKey = result.LastEvaluatedKey
-	}
-	
-	// Start from the next batch index after the highest existing one
-	batchIndex := maxBatchIndex + 1
-	if maxBatchIndex >= 0 {
-		log.Printf("AddPosts: Found existing batches up to index %d, starting from batch %d", maxBatchIndex, batchIndex)
-	} else {
-		log.Printf("AddPosts: No existing batches found, starting from batch 0")
-	}
-	
-	for i := 0; i < len(posts); i += postsPerBatch {
-		end := i + postsPerBatch
-		if end > len(posts) {
-			end = len(posts)
-		}
+	batches := splitPostBatches(posts, postsPerBatch, maxBatchItemBytes)
+
+	// A per-call prefix keeps batch keys unique without reading existing batches first
+	batchPrefix := fmt.Sprintf("%s#batch%d%04x-", runID, time.Now().UnixNano(), rand.IntN(0x10000))
+	createdAt := time.Now().Format(time.RFC3339)
+	ttl := time.Now().Add(sm.ttl).Unix()
 
-		// Create a batch of posts
+	requests := make([]types.WriteRequest, 0, len(batches))
+	for i, batch := range batches {
 		postBatch := PostBatch{
 			RunID:     runID,
 			Step:      "fetcher", // All posts are stored under the fetcher step
-			PostID:    fmt.Sprintf("%s#batch%d", runID, batchIndex),
-			Posts:     posts[i:end],
-			CreatedAt: time.Now().Format(time.RFC3339),
-			TTL:       time.Now().Add(2 * 24 * time.Hour).Unix(), // 2 days TTL
+			PostID:    fmt.Sprintf("%s%d", batchPrefix, i),
+			Posts:     batch,
+			CreatedAt: createdAt,
+			TTL:       ttl,
 		}
 
 		item, err := attributevalue.MarshalMap(postBatch)
 		if err != nil {
 			return fmt.Errorf("failed to marshal post batch: %w", err)
 		}
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+	}
 
-		// Store the batch as a single item
-		_, err = sm.client.PutItem(ctx, &dynamodb.PutItemInput{
-			TableName: aws.String(sm.tableName),
-			Item:      item,
-		})
+	failed := 0
+	var errs []error
+	for start := 0; start < len(requests); start += batchWriteItemLimit {
+		end := min(start+batchWriteItemLimit, len(requests))
+		unprocessed, err := sm.batchWrite(ctx, requests[start:end])
 		if err != nil {
-			return fmt.Errorf("failed to store post batch: %w", err)
+			errs = append(errs, err)
+			failed += countBatchedPosts(unprocessed)
 		}
+	}
+	stored := len(posts) - failed
 
-		batchIndex++
+	// Update the run totals atomically so concurrent callers don't lose each other's counts
+	if stored > 0 {
+		if err := sm.addRetrievedPosts(ctx, runID, state.PostID, stored); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
-	// Update the run state with new totals
-	state.TotalPostsRetrieved += len(posts)
-	state.Step = "fetcher"
-	state.Status = "fetching"
+	if failed > 0 {
+		return &PartialWriteError{Stored: stored, Failed: failed, Err: errors.Join(errs...)}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
 
-	err = sm.UpdateRun(ctx, state)
+// addRetrievedPosts increments a run's post total and marks it as fetching
+func (sm *StateManager) addRetrievedPosts(ctx context.Context, runID, step string, count int) error {
+	_, err := sm.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(sm.tableName),
+		Key: map[string]types.AttributeValue{
+			"runId":  &types.AttributeValueMemberS{Value: runID},
+			"postId": &types.AttributeValueMemberS{Value: step},
+		},
+		UpdateExpression:    aws.String("ADD totalPostsRetrieved :count SET #step = :step, #status = :status, updatedAt = :now"),
+		ConditionExpression: aws.String("attribute_exists(runId)"),
+		ExpressionAttributeNames: map[string]string{
+			"#step":   "step",
+			"#status": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":count":  &types.AttributeValueMemberN{Value: strconv.Itoa(count)},
+			":step":   &types.AttributeValueMemberS{Value: "fetcher"},
+			":status": &types.AttributeValueMemberS{Value: "fetching"},
+			":now":    &types.AttributeValueMemberS{Value: time.Now().Format(time.RFC3339Nano)},
+		},
+	})
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to update run totals: %w", err)
 	}
 	return nil
 }
 
-// GetAllPosts retrieves all posts for a run
-// Handles pagination to retrieve all posts across multiple DynamoDB pages
-func (sm *StateManager) GetAllPosts(ctx context.Context, runID string) ([]Post, error) {
-	var allPosts []Post
-	var lastEvaluatedKey map[string]types.AttributeValue
-	pageCount := 0
+// AddFetchRequests adds to the counts of API requests the fetcher made for a run and of
+// those that failed
+func (sm *StateManager) AddFetchRequests(ctx context.Context, runID string, requests, failed int) error {
+	_, err := sm.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(sm.tableName),
+		Key: map[string]types.AttributeValue{
+			"runId":  &types.AttributeValueMemberS{Value: runID},
+			"postId": &types.AttributeValueMemberS{Value: "orchestrator"},
+		},
+		UpdateExpression:    aws.String("ADD fetchRequests :requests, fetchErrors :failed SET updatedAt = :now"),
+		ConditionExpression: aws.String("attribute_exists(runId)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":requests": &types.AttributeValueMemberN{Value: strconv.Itoa(requests)},
+			":failed":   &types.AttributeValueMemberN{Value: strconv.Itoa(failed)},
+			":now":      &types.AttributeValueMemberS{Value: time.Now().Format(time.RFC3339Nano)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update fetch request counts: %w", err)
+	}
+	return nil
+}
 
-	for {
-		// Query all posts for this run using the posts-index GSI
-		queryInput := &dynamodb.QueryInput{
-			TableName:              aws.String(sm.tableName),
-			IndexName:              aws.String("posts-index"),
-			KeyConditionExpression: aws.String("runId = :runId AND begins_with(postId, :postIdPrefix)"),
-			ExpressionAttributeValues: map[string]types.AttributeValue{
-				":runId":        &types.AttributeValueMemberS{Value: runID},
-				":postIdPrefix": &types.AttributeValueMemberS{Value: runID + "#"},
-			},
+// countBatchedPosts counts the posts carried by unwritten PostBatch put requests
+func countBatchedPosts(requests []types.WriteRequest) int {
+	count := 0
+	for _, request := range requests {
+		if request.PutRequest == nil {
+			continue
 		}
-
-		// Add pagination token if we're continuing from a previous page
-		if lastEvaluatedKey != nil {
-			queryInput.ExclusiveStartKey = lastEvaluatedKey
-		}
-
-		result, err := sm.client.Query(ctx, queryInput)
-		if err != nil {
-			return nil, fmt.Errorf("failed to query posts: %w", err)
+		if posts, ok := request.PutRequest.Item["posts"].(*types.AttributeValueMemberL); ok {
+			count += len(posts.Value)
 		}
+	}
+	return count
+}
 
-		pageCount++
-		log.Printf("GetAllPosts: Retrieved page %d with %d items", pageCount, len(result.Items))
+// GetAllPosts retrieves all posts for a run, read with the given consistency
+// Handles pagination to retrieve all posts across multiple DynamoDB pages, paced to the
+// read budget and split between its segments (see SetReadBudget)
+func (sm *StateManager) GetAllPosts(ctx context.Context, runID string, consistency ReadConsistency) ([]Post, error) {
+	ranges := postKeyRanges(runID, sm.readBudget.Segments)
+	limiter := newCapacityLimiter(sm.readBudget.CapacityUnits)
 
-		// Process items from this page
-		for _, item := range result.Items {
-			// Try to unmarshal as PostBatch first (new format)
-			var postBatch PostBatch
-			err := attributevalue.UnmarshalMap(item, &postBatch)
-			if err == nil && strings.Contains(postBatch.PostID, "#batch") {
-				// This is a batched post item
-				allPosts = append(allPosts, postBatch.Posts...)
-				continue
-			}
+	results := make([]postRangeResult, len(ranges))
+	var wg sync.WaitGroup
+	for i, keyRange := range ranges {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = sm.queryPostRange(ctx, runID, keyRange, consistency, limiter, len(ranges))
+		}()
+	}
+	wg.Wait()
 
-			// Fallback to individual PostItem (legacy format)
-			var postItem PostItem
-			err = attributevalue.UnmarshalMap(item, &postItem)
-			if err != nil {
-				log.Printf("Warning: failed to unmarshal post item: %v", err)
-				continue
-			}
-			// Only include posts that have a postId with # (filter out run state items)
-			if strings.Contains(postItem.PostID, "#") && !strings.Contains(postItem.PostID, "#batch") {
-				allPosts = append(allPosts, postItem.Post)
-			}
+	var allPosts []Post
+	pageCount := 0
+	var units float64
+	for _, result := range results {
+		if result.err != nil {
+			return nil, result.err
 		}
+		allPosts = append(allPosts, result.posts...)
+		pageCount += result.pages
+		units += result.units
+	}
 
-		// Check if there are more pages to retrieve
-		if len(result.LastEvaluatedKey) == 0 {
-			break
+	log.Printf("GetAllPosts: Retrieved %d total posts across %d pages in %d segments (%.1f read capacity units)", len(allPosts), pageCount, len(ranges), units)
+	return allPosts, nil
+}
+
+// decodePostItems reads the posts out of post batch items, and legacy single post items
+func decodePostItems(items []map[string]types.AttributeValue) []Post {
+	var posts []Post
+	for _, item := range items {
+		// Try to unmarshal as PostBatch first (new format)
+		var postBatch PostBatch
+		err := attributevalue.UnmarshalMap(item, &postBatch)
+		if err == nil && strings.Contains(postBatch.PostID, "#batch") {
+			// This is a batched post item
+			posts = append(posts, postBatch.Posts...)
+			continue
 		}
 
-		lastEvaluatedKey = result.LastEvaluatedKey
+		// Fallback to individual PostItem (legacy format)
+		var postItem PostItem
+		err = attributevalue.UnmarshalMap(item, &postItem)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal post item: %v", err)
+			continue
+		}
+		// Only include posts that have a postId with # (filter out run state items)
+		if strings.Contains(postItem.PostID, "#") && !strings.Contains(postItem.PostID, "#batch") {
+			posts = append(posts, postItem.Post)
+		}
 	}
-
-	log.Printf("GetAllPosts: Retrieved %d total posts across %d pages", len(allPosts), pageCount)
-	return allPosts, nil
+	return posts
 }
 
 // UpdateCursor updates the cursor for the next fetch
 func (sm *StateManager) UpdateCursor(ctx context.Context, runID, cursor string, hasMorePosts bool) error {
 	// Try to get fetcher step first, fall back to orchestrator step
-	state, err := sm.GetRun(ctx, runID, "fetcher")
+	state, err := sm.GetRun(ctx, runID, "fetcher", StronglyConsistent)
 	if err != nil {
 		// If fetcher step doesn't exist, get orchestrator step
-		state, err = sm.GetRun(ctx, runID, "orchestrator")
+		state, err = sm.GetRun(ctx, runID, "orchestrator", StronglyConsistent)
 		if err != nil {
 			return fmt.Errorf("failed to get current state: %w", err)
 		}
@@ -399,14 +566,125 @@ func (sm *StateManager) UpdateCursor(ctx context.Context, runID, cursor string,
 	return sm.UpdateRun(ctx, state)
 }
 
-// SetAnalysisComplete marks the analysis as complete
-func (sm *StateManager) SetAnalysisComplete(ctx context.Context, runID string, overallSentiment string, topPosts []Post) error {
+// CheckpointFetch records the cursor and cumulative fetch time so a later fetcher invocation
+// can resume where this one stopped
+func (sm *StateManager) CheckpointFetch(ctx context.Context, runID, cursor string, elapsed time.Duration) error {
+	state, err := sm.GetLatestRun(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("failed to get current state: %w", err)
+	}
+
+	state.CurrentCursor = cursor
+	state.HasMorePosts = true
+	state.FetchCheckpoints++
+	state.FetchElapsedMs += elapsed.Milliseconds()
+	state.Step = "fetcher"
+	state.Status = "fetching"
+
+	return sm.UpdateRun(ctx, state)
+}
+
+// MarkFetchTruncated records that fetching stopped before the run's cutoff
+func (sm *StateManager) MarkFetchTruncated(ctx context.Context, runID string) error {
+	state, err := sm.GetLatestRun(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("failed to get current state: %w", err)
+	}
+
+	state.FetchTruncated = true
+
+	return sm.UpdateRun(ctx, state)
+}
+
+// SetFeedSource records the custom feed or list a run fetches from, so resumed
+// fetches and the summary post use the same source
+func (sm *StateManager) SetFeedSource(ctx context.Context, runID, feedURI, feedLabel string) error {
+	state, err := sm.GetLatestRun(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("failed to get current state: %w", err)
+	}
+
+	state.FeedURI = feedURI
+	state.FeedLabel = feedLabel
+
+	return sm.UpdateRun(ctx, state)
+}
+
+// SetSearchQueries records the search queries a run fetches, so resumed fetches and the
+// summary post use the same queries
+func (sm *StateManager) SetSearchQueries(ctx context.Context, runID string, queries []string) error {
+	state, err := sm.GetLatestRun(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("failed to get current state: %w", err)
+	}
+
+	state.SearchQueries = queries
+
+	return sm.UpdateRun(ctx, state)
+}
+
+// SetBlockedPosts stores how many of the run's posts the blocklist left out, by entry kind
+func (sm *StateManager) SetBlockedPosts(ctx context.Context, runID string, blocked map[string]int) error {
+	state, err := sm.GetLatestRun(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("failed to get current state: %w", err)
+	}
+
+	state.BlockedPosts = blocked
+
+	return sm.UpdateRun(ctx, state)
+}
+
+// SetContentWarningPosts stores how many of the run's posts carried content warnings
+func (sm *StateManager) SetContentWarningPosts(ctx context.Context, runID string, count int) error {
+	state, err := sm.GetLatestRun(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("failed to get current state: %w", err)
+	}
+
+	state.ContentWarningPosts = count
+
+	return sm.UpdateRun(ctx, state)
+}
+
+// SetMediaStats stores how many of the run's posts carried media
+func (sm *StateManager) SetMediaStats(ctx context.Context, runID string, stats MediaStats) error {
+	state, err := sm.GetLatestRun(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("failed to get current state: %w", err)
+	}
+
+	state.Media = &stats
+
+	return sm.UpdateRun(ctx, state)
+}
+
+// SetCoverage stores how much of the analysis window the fetched posts covered
+func (sm *StateManager) SetCoverage(ctx context.Context, runID string, coverage WindowCoverage) error {
+	state, err := sm.GetLatestRun(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("failed to get current state: %w", err)
+	}
+
+	state.CoveragePercent = coverage.CoveragePercent
+	state.EarliestPostAt = coverage.EarliestPostAt
+	state.LatestPostAt = coverage.LatestPostAt
+
+	return sm.UpdateRun(ctx, state)
+}
+
+// SetAnalysisComplete marks the analysis as complete, recording the averaging method the
+// net sentiment came from and the provenance of the analysis
+func (sm *StateManager) SetAnalysisComplete(ctx context.Context, runID string, overallSentiment string, netSentimentPercentage float64, method aggregation.Method, record provenance.Record, topPosts []Post) error {
 	state, err := sm.GetLatestRun(ctx, runID)
 	if err != nil {
 		return fmt.Errorf("failed to get current state: %w", err)
 	}
 
 	state.OverallSentiment = overallSentiment
+	state.NetSentimentPercentage = netSentimentPercentage
+	state.SentimentMethod = &method
+	state.Provenance = &record
 	state.TopPosts = topPosts
 	state.Step = "aggregator"
 	state.Status = "analyzed"
@@ -414,6 +692,36 @@ func (sm *StateManager) SetAnalysisComplete(ctx context.Context, runID string, o
 	return sm.UpdateRun(ctx, state)
 }
 
+// SetRunSkipped marks a run as finished without posting and records why
+func (sm *StateManager) SetRunSkipped(ctx context.Context, runID, reason string) error {
+	state, err := sm.GetLatestRun(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("failed to get current state: %w", err)
+	}
+
+	state.Step = "processor"
+	state.Status = "skipped"
+	state.SkipReason = reason
+
+	return sm.UpdateRun(ctx, state)
+}
+
+// RecordSkippedPost notes that a poster withheld its post for this run
+func (sm *StateManager) RecordSkippedPost(ctx context.Context, runID, poster, reason string) error {
+	state, err := sm.GetLatestRun(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("failed to get current state: %w", err)
+	}
+
+	state.SkippedPosts = append(state.SkippedPosts, SkippedPost{
+		Poster: poster,
+		Reason: reason,
+		At:     time.Now().UTC(),
+	})
+
+	return sm.UpdateRun(ctx, state)
+}
+
 // SetPostingComplete marks the posting as complete
 func (sm *StateManager) SetPostingComplete(ctx context.Context, runID string) error {
 	state, err := sm.GetLatestRun(ctx, runID)
@@ -440,8 +748,60 @@ func (sm *StateManager) SetTopPostURI(ctx context.Context, runID, postURI, postC
 	return sm.UpdateRun(ctx, state)
 }
 
-// ListRuns retrieves all run IDs from DynamoDB
+// Run listing index: CreateRun writes every run of a channel under the same runIndex
+// partition so created-index returns its runs ordered by createdAt with a single Query
+const (
+	runsCreatedIndex  = "created-index"
+	runIndexPartition = "run"
+)
+
+// runIndexFor is a channel's runIndex partition; the default channel keeps the original one
+func runIndexFor(channelID string) string {
+	if channelID == "" {
+		return runIndexPartition
+	}
+	return runIndexPartition + "#" + channelID
+}
+
+// ListRuns retrieves the IDs of the default channel's most recent runs, most recent first
 func (sm *StateManager) ListRuns(ctx context.Context, limit int32) ([]string, error) {
+	return sm.ListChannelRuns(ctx, "", limit)
+}
+
+// ListChannelRuns is ListRuns for one channel; an empty channelID is the default channel
+func (sm *StateManager) ListChannelRuns(ctx context.Context, channelID string, limit int32) ([]string, error) {
+	result, err := sm.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(sm.tableName),
+		IndexName:              aws.String(runsCreatedIndex),
+		KeyConditionExpression: aws.String("runIndex = :runIndex"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":runIndex": &types.AttributeValueMemberS{Value: runIndexFor(channelID)},
+		},
+		ProjectionExpression: aws.String("runId"),
+		ScanIndexForward:     aws.Bool(false),
+		Limit:                aws.Int32(limit),
+	})
+	if err != nil {
+		log.Printf("Warning: failed to query %s, falling back to a scan: %v", runsCreatedIndex, err)
+		return sm.scanRuns(ctx, channelID, limit)
+	}
+
+	runIDs := make([]string, 0, len(result.Items))
+	for _, item := range result.Items {
+		var state RunState
+		if err := attributevalue.UnmarshalMap(item, &state); err != nil {
+			log.Printf("Warning: failed to unmarshal run state: %v", err)
+			continue
+		}
+		runIDs = append(runIDs, state.RunID)
+	}
+
+	return runIDs, nil
+}
+
+// scanRuns lists a channel's run IDs with a table scan, for tables without created-index
+// The order is unspecified
+func (sm *StateManager) scanRuns(ctx context.Context, channelID string, limit int32) ([]string, error) {
 	// Use scan to get all run states (RunState items have postId = "orchestrator")
 	result, err := sm.client.Scan(ctx, &dynamodb.ScanInput{
 		TableName:        aws.String(sm.tableName),
@@ -466,29 +826,311 @@ func (sm *StateManager) ListRuns(ctx context.Context, limit int32) ([]string, er
 			log.Printf("Warning: failed to unmarshal run state: %v", err)
 			continue
 		}
+		if state.ChannelID != channelID {
+			continue
+		}
 		runIDs = append(runIDs, state.RunID)
 	}
 
-	// Sort by creation time (most recent first)
-	// Note: This is a simple approach - for better performance with large datasets,
-	// consider using a different GSI or query strategy
 	return runIDs, nil
 }
 
-// GetRunStats returns statistics about a run
-func (sm *StateManager) GetRunStats(ctx context.Context, runID string) (*RunStats, error) {
+// GetRecentRuns retrieves the orchestrator state of runs created within the given duration,
+// sorted most recent first
+// Handles pagination across Query pages
+func (sm *StateManager) GetRecentRuns(ctx context.Context, since time.Duration) ([]RunState, error) {
+	return sm.GetRecentChannelRuns(ctx, "", since)
+}
+
+// GetRecentChannelRuns is GetRecentRuns for one channel; an empty channelID is the default channel
+func (sm *StateManager) GetRecentChannelRuns(ctx context.Context, channelID string, since time.Duration) ([]RunState, error) {
+	startTime := time.Now().UTC().Add(-since)
+
+	var runs []RunState
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		queryInput := &dynamodb.QueryInput{
+			TableName:              aws.String(sm.tableName),
+			IndexName:              aws.String(runsCreatedIndex),
+			KeyConditionExpression: aws.String("runIndex = :runIndex AND #createdAt >= :startTime"),
+			ExpressionAttributeNames: map[string]string{
+				"#createdAt": "createdAt",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":runIndex":  &types.AttributeValueMemberS{Value: runIndexFor(channelID)},
+				":startTime": &types.AttributeValueMemberS{Value: startTime.Format(time.RFC3339)},
+			},
+			ScanIndexForward: aws.Bool(false),
+		}
+
+		if lastEvaluatedKey != nil {
+			queryInput.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := sm.client.Query(ctx, queryInput)
+		if err != nil {
+			log.Printf("Warning: failed to query %s, falling back to a scan: %v", runsCreatedIndex, err)
+			return sm.scanRecentRuns(ctx, channelID, startTime)
+		}
+
+		for _, item := range result.Items {
+			var state RunState
+			if err := attributevalue.UnmarshalMap(item, &state); err != nil {
+				log.Printf("Warning: failed to unmarshal run state: %v", err)
+				continue
+			}
+			runs = append(runs, state)
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return runs, nil
+}
+
+// scanRecentRuns is GetRecentChannelRuns for tables without created-index
+// Handles pagination since the filter is applied after DynamoDB reads each page
+func (sm *StateManager) scanRecentRuns(ctx context.Context, channelID string, startTime time.Time) ([]RunState, error) {
+	var runs []RunState
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		scanInput := &dynamodb.ScanInput{
+			TableName:        aws.String(sm.tableName),
+			FilterExpression: aws.String("#postId = :postId AND #createdAt >= :startTime"),
+			ExpressionAttributeNames: map[string]string{
+				"#postId":    "postId",
+				"#createdAt": "createdAt",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":postId":    &types.AttributeValueMemberS{Value: "orchestrator"},
+				":startTime": &types.AttributeValueMemberS{Value: startTime.Format(time.RFC3339)},
+			},
+		}
+
+		if lastEvaluatedKey != nil {
+			scanInput.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := sm.client.Scan(ctx, scanInput)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan recent runs: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var state RunState
+			if err := attributevalue.UnmarshalMap(item, &state); err != nil {
+				log.Printf("Warning: failed to unmarshal run state: %v", err)
+				continue
+			}
+			if state.ChannelID != channelID {
+				continue
+			}
+			runs = append(runs, state)
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].CreatedAt.After(runs[j].CreatedAt)
+	})
+
+	return runs, nil
+}
+
+// GetExpiringRuns retrieves the orchestrator state of runs whose TTL falls within the given
+// duration from now, so their summaries can be archived before DynamoDB deletes them
+// Handles pagination since the filter is applied after DynamoDB reads each page
+func (sm *StateManager) GetExpiringRuns(ctx context.Context, within time.Duration) ([]RunState, error) {
+	expiresBefore := time.Now().Add(within).Unix()
+
+	var runs []RunState
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		scanInput := &dynamodb.ScanInput{
+			TableName:        aws.String(sm.tableName),
+			FilterExpression: aws.String("#postId = :postId AND #ttl <= :expiresBefore"),
+			ExpressionAttributeNames: map[string]string{
+				"#postId": "postId",
+				"#ttl":    "ttl",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":postId":        &types.AttributeValueMemberS{Value: "orchestrator"},
+				":expiresBefore": &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresBefore, 10)},
+			},
+		}
+
+		if lastEvaluatedKey != nil {
+			scanInput.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := sm.client.Scan(ctx, scanInput)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan expiring runs: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var state RunState
+			if err := attributevalue.UnmarshalMap(item, &state); err != nil {
+				log.Printf("Warning: failed to unmarshal run state: %v", err)
+				continue
+			}
+			runs = append(runs, state)
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].CreatedAt.Before(runs[j].CreatedAt)
+	})
+
+	return runs, nil
+}
+
+// GetRunStats returns statistics about a run, read with the given consistency
+func (sm *StateManager) GetRunStats(ctx context.Context, runID string, consistency ReadConsistency) (*RunStats, error) {
 	// Get the run state
-	state, err := sm.GetLatestRun(ctx, runID)
+	state, err := sm.GetRun(ctx, runID, "orchestrator", consistency)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get run state: %w", err)
 	}
 
 	// Get all posts for this run
-	posts, err := sm.GetAllPosts(ctx, runID)
+	posts, err := sm.GetAllPosts(ctx, runID, consistency)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get posts: %w", err)
 	}
 
+	return newRunStats(state, posts), nil
+}
+
+// runStatsConcurrency bounds the post queries BatchGetRunStats runs in parallel
+const runStatsConcurrency = 8
+
+// batchGetItemLimit is the most keys a single BatchGetItem request accepts
+const batchGetItemLimit = 100
+
+// BatchGetRunStats returns statistics for many runs at once, keyed by run ID
+// Run states are read with BatchGetItem and post batches are queried in parallel;
+// runs that are missing or whose posts cannot be read are left out of the result
+func (sm *StateManager) BatchGetRunStats(ctx context.Context, runIDs []string) (map[string]*RunStats, error) {
+	states, err := sm.batchGetRuns(ctx, runIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]*RunStats, len(states))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, runStatsConcurrency)
+
+	for _, state := range states {
+		wg.Add(1)
+		go func(state *RunState) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			posts, err := sm.GetAllPosts(ctx, state.RunID, EventuallyConsistent)
+			if err != nil {
+				log.Printf("Warning: failed to get posts for run %s: %v", state.RunID, err)
+				return
+			}
+
+			runStats := newRunStats(state, posts)
+			mu.Lock()
+			stats[state.RunID] = runStats
+			mu.Unlock()
+		}(state)
+	}
+	wg.Wait()
+
+	return stats, nil
+}
+
+// batchGetRuns reads the orchestrator state of each run, retrying unprocessed keys
+func (sm *StateManager) batchGetRuns(ctx context.Context, runIDs []string) ([]*RunState, error) {
+	var states []*RunState
+
+	// BatchGetItem rejects requests that repeat a key
+	seen := make(map[string]bool, len(runIDs))
+	unique := make([]string, 0, len(runIDs))
+	for _, runID := range runIDs {
+		if !seen[runID] {
+			seen[runID] = true
+			unique = append(unique, runID)
+		}
+	}
+	runIDs = unique
+
+	for start := 0; start < len(runIDs); start += batchGetItemLimit {
+		end := start + batchGetItemLimit
+		if end > len(runIDs) {
+			end = len(runIDs)
+		}
+
+		keys := make([]map[string]types.AttributeValue, 0, end-start)
+		for _, runID := range runIDs[start:end] {
+			keys = append(keys, map[string]types.AttributeValue{
+				"runId":  &types.AttributeValueMemberS{Value: runID},
+				"postId": &types.AttributeValueMemberS{Value: "orchestrator"},
+			})
+		}
+
+		requestItems := map[string]types.KeysAndAttributes{
+			sm.tableName: {Keys: keys},
+		}
+		for attempt := 0; len(requestItems) > 0; attempt++ {
+			if attempt > 5 {
+				return nil, fmt.Errorf("failed to get run states: unprocessed keys remain after %d attempts", attempt)
+			}
+			if attempt > 0 {
+				// Back off before retrying keys DynamoDB did not process (throttling)
+				time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+			}
+
+			result, err := sm.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+				RequestItems: requestItems,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get run states: %w", err)
+			}
+
+			for _, item := range result.Responses[sm.tableName] {
+				var state RunState
+				if err := attributevalue.UnmarshalMap(item, &state); err != nil {
+					log.Printf("Warning: failed to unmarshal run state: %v", err)
+					continue
+				}
+				states = append(states, &state)
+			}
+
+			requestItems = result.UnprocessedKeys
+		}
+	}
+
+	return states, nil
+}
+
+// newRunStats summarises a run state and the posts stored for it
+func newRunStats(state *RunState, posts []Post) *RunStats {
+	// Coverage is measured against the window the run was asked to analyze
+	windowEnd := state.CutoffTime.Add(time.Duration(state.AnalysisIntervalMinutes) * time.Minute)
+	coverage := CalculateCoverage(posts, state.CutoffTime, windowEnd)
+
 	return &RunStats{
 		RunID:                   state.RunID,
 		Status:                  state.Status,
@@ -501,20 +1143,34 @@ func (sm *StateManager) GetRunStats(ctx context.Context, runID string) (*RunStat
 		UpdatedAt:               state.UpdatedAt,
 		OverallSentiment:        state.OverallSentiment,
 		TopPostsCount:           len(state.TopPosts),
-	}, nil
+		CoveragePercent:         coverage.CoveragePercent,
+		EarliestPostAt:          coverage.EarliestPostAt,
+		LatestPostAt:            coverage.LatestPostAt,
+		StepTimings:             state.Timeline(),
+		Media:                   state.Media,
+		SkipReason:              state.SkipReason,
+		SkippedPosts:            state.SkippedPosts,
+	}
 }
 
 // RunStats represents statistics about a run
 type RunStats struct {
-	RunID                   string    `json:"runId"`
-	Status                  string    `json:"status"`
-	Step                    string    `json:"step"`
-	AnalysisIntervalMinutes int       `json:"analysisIntervalMinutes"`
-	CutoffTime              time.Time `json:"cutoffTime"`
-	TotalPostsRetrieved     int       `json:"totalPostsRetrieved"`
-	ActualPostsCount        int       `json:"actualPostsCount"`
-	CreatedAt               time.Time `json:"createdAt"`
-	UpdatedAt               time.Time `json:"updatedAt"`
-	OverallSentiment        string    `json:"overallSentiment,omitempty"`
-	TopPostsCount           int       `json:"topPostsCount"`
+	RunID                   string        `json:"runId"`
+	Status                  string        `json:"status"`
+	Step                    string        `json:"step"`
+	AnalysisIntervalMinutes int           `json:"analysisIntervalMinutes"`
+	CutoffTime              time.Time     `json:"cutoffTime"`
+	TotalPostsRetrieved     int           `json:"totalPostsRetrieved"`
+	ActualPostsCount        int           `json:"actualPostsCount"`
+	CreatedAt               time.Time     `json:"createdAt"`
+	UpdatedAt               time.Time     `json:"updatedAt"`
+	OverallSentiment        string        `json:"overallSentiment,omitempty"`
+	TopPostsCount           int           `json:"topPostsCount"`
+	CoveragePercent         float64       `json:"coveragePercent"`
+	EarliestPostAt          time.Time     `json:"earliestPostAt,omitempty"`
+	LatestPostAt            time.Time     `json:"latestPostAt,omitempty"`
+	StepTimings             []StepTiming  `json:"stepTimings,omitempty"`
+	Media                   *MediaStats   `json:"media,omitempty"`
+	SkipReason              string        `json:"skipReason,omitempty"`
+	SkippedPosts            []SkippedPost `json:"skippedPosts,omitempty"`
 }
@@ -0,0 +1,94 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Pipeline steps recorded in a run's audit trail
+const (
+	StepFetch     = "fetch"
+	StepAnalyze   = "analyze"
+	StepAggregate = "aggregate"
+	StepPost      = "post"
+)
+
+// Step timing statuses
+const (
+	StepStatusRunning   = "running"
+	StepStatusCompleted = "completed"
+	StepStatusFailed    = "failed"
+)
+
+// StepTiming records when a pipeline step started and finished
+type StepTiming struct {
+	Step       string    `json:"step" dynamodbav:"step"`
+	Status     string    `json:"status" dynamodbav:"status"`
+	StartedAt  time.Time `json:"startedAt" dynamodbav:"startedAt"`
+	EndedAt    time.Time `json:"endedAt" dynamodbav:"endedAt"`
+	DurationMs int64     `json:"durationMs" dynamodbav:"durationMs"`
+}
+
+// NewStepTiming creates a timing for a step that started at startedAt and ends now
+func NewStepTiming(step string, startedAt time.Time, status string) StepTiming {
+	endedAt := time.Now().UTC()
+	return StepTiming{
+		Step:       step,
+		Status:     status,
+		StartedAt:  startedAt.UTC(),
+		EndedAt:    endedAt,
+		DurationMs: endedAt.Sub(startedAt).Milliseconds(),
+	}
+}
+
+// Duration returns the step's duration
+func (t StepTiming) Duration() time.Duration {
+	return time.Duration(t.DurationMs) * time.Millisecond
+}
+
+// AddStepTiming adds a timing to the run's audit trail
+// A step recorded more than once (e.g. a fetch resumed across invocations) is merged into a
+// single entry spanning the earliest start to the latest end, taking the latest status
+func (s *RunState) AddStepTiming(timing StepTiming) {
+	for i, existing := range s.StepTimings {
+		if existing.Step != timing.Step {
+			continue
+		}
+		if timing.StartedAt.After(existing.StartedAt) {
+			timing.StartedAt = existing.StartedAt
+		}
+		if existing.EndedAt.After(timing.EndedAt) {
+			timing.EndedAt = existing.EndedAt
+			timing.Status = existing.Status
+		}
+		timing.DurationMs = timing.EndedAt.Sub(timing.StartedAt).Milliseconds()
+		s.StepTimings[i] = timing
+		return
+	}
+	s.StepTimings = append(s.StepTimings, timing)
+}
+
+// Timeline returns the run's step timings ordered by start time
+func (s *RunState) Timeline() []StepTiming {
+	timeline := append([]StepTiming(nil), s.StepTimings...)
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].StartedAt.Before(timeline[j].StartedAt)
+	})
+	return timeline
+}
+
+// RecordStepTimings adds timings to a run's audit trail
+func (sm *StateManager) RecordStepTimings(ctx context.Context, runID string, timings ...StepTiming) error {
+	state, err := sm.GetLatestRun(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("failed to get current state: %w", err)
+	}
+
+	for _, timing := range timings {
+		state.AddStepTiming(timing)
+	}
+
+	return sm.UpdateRun(ctx, state)
+}
@@ -0,0 +1,48 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddStepTimingMergesResumedSteps(t *testing.T) {
+	start := time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC)
+	run := &RunState{}
+
+	run.AddStepTiming(StepTiming{Step: StepFetch, Status: StepStatusRunning, StartedAt: start, EndedAt: start.Add(13 * time.Minute)})
+	run.AddStepTiming(StepTiming{Step: StepFetch, Status: StepStatusCompleted, StartedAt: start.Add(13 * time.Minute), EndedAt: start.Add(20 * time.Minute)})
+
+	if len(run.StepTimings) != 1 {
+		t.Fatalf("Expected 1 merged timing, got %d", len(run.StepTimings))
+	}
+	fetch := run.StepTimings[0]
+	if !fetch.StartedAt.Equal(start) {
+		t.Errorf("Expected merged start %v, got %v", start, fetch.StartedAt)
+	}
+	if fetch.Duration() != 20*time.Minute {
+		t.Errorf("Expected merged duration 20m, got %v", fetch.Duration())
+	}
+	if fetch.Status != StepStatusCompleted {
+		t.Errorf("Expected latest status %q, got %q", StepStatusCompleted, fetch.Status)
+	}
+}
+
+func TestTimelineOrdersByStart(t *testing.T) {
+	start := time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC)
+	run := &RunState{}
+
+	run.AddStepTiming(StepTiming{Step: StepPost, StartedAt: start.Add(25 * time.Minute), EndedAt: start.Add(26 * time.Minute)})
+	run.AddStepTiming(StepTiming{Step: StepFetch, StartedAt: start, EndedAt: start.Add(20 * time.Minute)})
+	run.AddStepTiming(StepTiming{Step: StepAnalyze, StartedAt: start.Add(21 * time.Minute), EndedAt: start.Add(24 * time.Minute)})
+
+	timeline := run.Timeline()
+	expected := []string{StepFetch, StepAnalyze, StepPost}
+	for i, step := range expected {
+		if timeline[i].Step != step {
+			t.Errorf("Timeline[%d]: expected %s, got %s", i, step, timeline[i].Step)
+		}
+	}
+	if run.StepTimings[0].Step != StepPost {
+		t.Error("Timeline should not reorder the run's own timings")
+	}
+}
@@ -0,0 +1,47 @@
+package state
+
+import "sort"
+
+// TopicSentiment is the sentiment of one trending topic's posts within a run
+type TopicSentiment struct {
+	Topic               string  `json:"topic" dynamodbav:"topic"`
+	Posts               int     `json:"posts" dynamodbav:"posts"`
+	NetSentimentPercent float64 `json:"netSentimentPercent" dynamodbav:"netSentimentPercent"`
+}
+
+// CalculateTopicSentiment groups posts by topic and returns the limit most-posted topics
+// with at least minPosts posts, each with the average compound score of its posts as a
+// percentage, the same scale as the run's net sentiment. Ties are broken by topic name.
+func CalculateTopicSentiment(posts []Post, limit, minPosts int) []TopicSentiment {
+	totals := make(map[string]*TopicSentiment)
+	for _, post := range posts {
+		for _, topic := range post.Topics {
+			total, ok := totals[topic]
+			if !ok {
+				total = &TopicSentiment{Topic: topic}
+				totals[topic] = total
+			}
+			total.Posts++
+			total.NetSentimentPercent += post.SentimentScore
+		}
+	}
+
+	var topics []TopicSentiment
+	for _, total := range totals {
+		if total.Posts < minPosts {
+			continue
+		}
+		total.NetSentimentPercent = total.NetSentimentPercent / float64(total.Posts) * 100
+		topics = append(topics, *total)
+	}
+	sort.Slice(topics, func(i, j int) bool {
+		if topics[i].Posts != topics[j].Posts {
+			return topics[i].Posts > topics[j].Posts
+		}
+		return topics[i].Topic < topics[j].Topic
+	})
+	if len(topics) > limit {
+		topics = topics[:limit]
+	}
+	return topics
+}
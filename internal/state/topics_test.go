@@ -0,0 +1,28 @@
+package state
+
+import "testing"
+
+func TestCalculateTopicSentiment(t *testing.T) {
+	posts := []Post{
+		{URI: "a", SentimentScore: 0.5, Topics: []string{"#caturday", "music"}},
+		{URI: "b", SentimentScore: 0.3, Topics: []string{"#caturday"}},
+		{URI: "c", SentimentScore: -0.4, Topics: []string{"politics"}},
+		{URI: "d", SentimentScore: -0.2, Topics: []string{"politics", "news"}},
+		{URI: "e", SentimentScore: 0.1},
+	}
+
+	topics := CalculateTopicSentiment(posts, 10, 2)
+	if len(topics) != 2 {
+		t.Fatalf("Expected the two topics with at least 2 posts, got %+v", topics)
+	}
+	if topics[0].Topic != "#caturday" || topics[0].Posts != 2 || topics[0].NetSentimentPercent != 40 {
+		t.Errorf("Unexpected first topic %+v", topics[0])
+	}
+	if topics[1].Topic != "politics" || topics[1].NetSentimentPercent > -29.9 || topics[1].NetSentimentPercent < -30.1 {
+		t.Errorf("Unexpected second topic %+v", topics[1])
+	}
+
+	if topics := CalculateTopicSentiment(posts, 1, 1); len(topics) != 1 || topics[0].Topic != "#caturday" {
+		t.Errorf("Expected the limit to keep the most-posted topic, got %+v", topics)
+	}
+}
@@ -0,0 +1,38 @@
+package state
+
+// EngagementWeightedNetSentiment returns the net sentiment percentage of posts with each
+// post's compound score weighted by its engagement, so widely seen posts count for more.
+// Each post weighs one plus its likes, reposts and replies, so unseen posts still count
+// once. Scores are clamped to the VADER range to match the per-post average.
+func EngagementWeightedNetSentiment(posts []Post) float64 {
+	var weightedSum, totalWeight float64
+	for _, post := range posts {
+		score := post.SentimentScore
+		if score > 1.0 {
+			score = 1.0
+		} else if score < -1.0 {
+			score = -1.0
+		}
+		weight := float64(1 + post.Likes + post.Reposts + post.Replies)
+		weightedSum += score * weight
+		totalWeight += weight
+	}
+	if totalWeight == 0 {
+		return 0
+	}
+	return weightedSum / totalWeight * 100
+}
+
+// EngagementWeightedPoints returns the points that recorded engagement-weighted sentiment,
+// with it in place of net sentiment, so charts and descriptions of net sentiment show it
+func EngagementWeightedPoints(points []SentimentDataPoint) []SentimentDataPoint {
+	var weighted []SentimentDataPoint
+	for _, point := range points {
+		if point.WeightedNetSentimentPercent == nil {
+			continue
+		}
+		point.NetSentimentPercent = *point.WeightedNetSentimentPercent
+		weighted = append(weighted, point)
+	}
+	return weighted
+}
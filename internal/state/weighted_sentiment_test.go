@@ -0,0 +1,36 @@
+package state
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEngagementWeightedNetSentiment(t *testing.T) {
+	posts := []Post{
+		{URI: "a", SentimentScore: 0.5, Likes: 7, Reposts: 1, Replies: 1},
+		{URI: "b", SentimentScore: -1.5},
+	}
+	// (0.5*10 + -1*1) / 11
+	if got, want := EngagementWeightedNetSentiment(posts), 4.0/11*100; math.Abs(got-want) > 1e-9 {
+		t.Errorf("EngagementWeightedNetSentiment() = %.3f, want %.3f", got, want)
+	}
+	if got := EngagementWeightedNetSentiment(nil); got != 0 {
+		t.Errorf("EngagementWeightedNetSentiment(nil) = %.3f, want 0", got)
+	}
+}
+
+func TestEngagementWeightedPoints(t *testing.T) {
+	weighted := 7.5
+	points := []SentimentDataPoint{
+		{RunID: "old", NetSentimentPercent: 3},
+		{RunID: "new", NetSentimentPercent: 4, WeightedNetSentimentPercent: &weighted},
+	}
+
+	got := EngagementWeightedPoints(points)
+	if len(got) != 1 || got[0].RunID != "new" || got[0].NetSentimentPercent != 7.5 {
+		t.Errorf("EngagementWeightedPoints() = %+v, want the new run at 7.5", got)
+	}
+	if points[1].NetSentimentPercent != 4 {
+		t.Error("EngagementWeightedPoints() modified its input")
+	}
+}
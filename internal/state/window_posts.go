@@ -0,0 +1,48 @@
+package state
+
+import (
+	"strings"
+	"time"
+)
+
+// DedupePosts keeps one post per URI, the copy with the most engagement, in the order each
+// URI was first seen so a seeded sample of the result is reproducible. Posts without a
+// valid URI, empty or in the legacy at://post- form that has no DID, are dropped.
+func DedupePosts(posts []Post) []Post {
+	index := make(map[string]int, len(posts))
+	var kept []Post
+	for _, post := range posts {
+		if post.URI == "" || strings.HasPrefix(post.URI, "at://post-") {
+			continue
+		}
+		i, seen := index[post.URI]
+		if !seen {
+			index[post.URI] = len(kept)
+			kept = append(kept, post)
+			continue
+		}
+		if post.Likes+post.Reposts+post.Replies > kept[i].Likes+kept[i].Reposts+kept[i].Replies {
+			kept[i] = post
+		}
+	}
+	return kept
+}
+
+// WindowPosts selects the run's posts the processor analyzes before its blocklist: the
+// deduplicated posts created from the cutoff on. Manual runs also drop posts from after
+// the window's end, since feeds can't be searched up to a past window's end; scheduled
+// runs keep them, as the fetcher only sees posts up to when it ran.
+func (r *RunState) WindowPosts(posts []Post) []Post {
+	var kept []Post
+	for _, post := range DedupePosts(posts) {
+		createdAt, err := time.Parse(time.RFC3339, post.CreatedAt)
+		if err != nil || createdAt.Before(r.CutoffTime) {
+			continue
+		}
+		if r.Manual && !createdAt.Before(r.WindowEnd()) {
+			continue
+		}
+		kept = append(kept, post)
+	}
+	return kept
+}
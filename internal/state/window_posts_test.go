@@ -0,0 +1,39 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDedupePosts(t *testing.T) {
+	posts := []Post{
+		{URI: "at://did:plc:a/app.bsky.feed.post/1", Likes: 1},
+		{URI: "at://did:plc:b/app.bsky.feed.post/2", Likes: 5},
+		{URI: "at://did:plc:a/app.bsky.feed.post/1", Likes: 3},
+		{URI: "at://post-123"},
+		{URI: ""},
+	}
+	kept := DedupePosts(posts)
+	if len(kept) != 2 || kept[0].URI != posts[0].URI || kept[0].Likes != 3 || kept[1].URI != posts[1].URI {
+		t.Errorf("Expected the most engaged copy of each valid URI in first-seen order, got %+v", kept)
+	}
+}
+
+func TestWindowPosts(t *testing.T) {
+	cutoff := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	posts := []Post{
+		{URI: "at://did:plc:a/app.bsky.feed.post/before", CreatedAt: "2025-06-01T11:59:00Z"},
+		{URI: "at://did:plc:a/app.bsky.feed.post/start", CreatedAt: "2025-06-01T12:00:00Z"},
+		{URI: "at://did:plc:a/app.bsky.feed.post/after", CreatedAt: "2025-06-01T12:31:00Z"},
+		{URI: "at://did:plc:a/app.bsky.feed.post/invalid", CreatedAt: "yesterday"},
+	}
+
+	run := RunState{CutoffTime: cutoff, AnalysisIntervalMinutes: 30}
+	if kept := run.WindowPosts(posts); len(kept) != 2 {
+		t.Errorf("Expected a scheduled run to keep posts after its window's end, got %+v", kept)
+	}
+	run.Manual = true
+	if kept := run.WindowPosts(posts); len(kept) != 1 || kept[0].CreatedAt != "2025-06-01T12:00:00Z" {
+		t.Errorf("Expected a manual run to keep only its window, got %+v", kept)
+	}
+}
@@ -0,0 +1,198 @@
+// Package status publishes a small status.json describing the latest runs to S3, so status
+// pages and dashboards can poll the bot's health without DynamoDB access
+package status
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+	"github.com/christophergentle/hourstats-bsky/internal/tracing"
+)
+
+// ParameterName holds the JSON status destination; when it is absent no status is written
+const ParameterName = "/hourstats/settings/status_page"
+
+// DefaultKey is the object key used when the destination names only a bucket
+const DefaultKey = "status.json"
+
+// ScheduleInterval matches the orchestrator's EventBridge rate
+const ScheduleInterval = 30 * time.Minute
+
+// LookbackWindow is how far back runs are read to find the last success
+const LookbackWindow = 24 * time.Hour
+
+// cacheControl lets pollers and CDNs cache the object for a minute
+const cacheControl = "public, max-age=60"
+
+// Destination is where status.json is written, e.g. {"bucket": "hourstats-status"}
+// The bucket's own policy decides whether the object is public.
+type Destination struct {
+	Bucket string `json:"bucket"`
+	Key    string `json:"key,omitempty"`
+}
+
+// Status is the published status document
+type Status struct {
+	UpdatedAt        time.Time  `json:"updatedAt"`
+	LastRun          *Run       `json:"lastRun,omitempty"`
+	LastSuccess      *Run       `json:"lastSuccess,omitempty"`
+	CurrentSentiment *Sentiment `json:"currentSentiment,omitempty"`
+	NextScheduledRun time.Time  `json:"nextScheduledRun"`
+}
+
+// Run summarises one run
+type Run struct {
+	RunID      string    `json:"runId"`
+	Status     string    `json:"status"`
+	StartedAt  time.Time `json:"startedAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	SkipReason string    `json:"skipReason,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Sentiment is the most recently measured sentiment
+type Sentiment struct {
+	Overall             string    `json:"overall"`
+	NetSentimentPercent float64   `json:"netSentimentPercent"`
+	MeasuredAt          time.Time `json:"measuredAt"`
+	RunID               string    `json:"runId"`
+}
+
+// ParameterGetter is the subset of the SSM client Load needs
+type ParameterGetter interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Load reads the status destination from SSM. A missing or empty parameter returns nil,
+// which disables the status page.
+func Load(ctx context.Context, ssmClient ParameterGetter) (*Destination, error) {
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ParameterName),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %s: %w", ParameterName, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return nil, nil
+	}
+	return Parse(*result.Parameter.Value)
+}
+
+// Parse decodes and validates a JSON status destination
+func Parse(value string) (*Destination, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var destination Destination
+	if err := json.Unmarshal([]byte(value), &destination); err != nil {
+		return nil, fmt.Errorf("invalid status page JSON: %w", err)
+	}
+	if destination.Bucket == "" {
+		return nil, fmt.Errorf("status page bucket is required")
+	}
+	if destination.Key == "" {
+		destination.Key = DefaultKey
+	}
+	return &destination, nil
+}
+
+// Build summarises runs, sorted most recent first as state.GetRecentRuns returns them
+// A run succeeded when it completed; sentiment comes from the latest analyzed run, even
+// if its summary was withheld. The next run is projected from the latest run on the
+// ScheduleInterval grid, or from now if there are no runs.
+func Build(runs []state.RunState, now time.Time) Status {
+	status := Status{UpdatedAt: now.UTC()}
+
+	for i := range runs {
+		run := &runs[i]
+		if status.LastRun == nil {
+			status.LastRun = summarize(run)
+		}
+		if status.LastSuccess == nil && run.Status == "completed" {
+			status.LastSuccess = summarize(run)
+		}
+		if status.CurrentSentiment == nil && run.OverallSentiment != "" {
+			status.CurrentSentiment = &Sentiment{
+				Overall:             run.OverallSentiment,
+				NetSentimentPercent: run.NetSentimentPercentage,
+				MeasuredAt:          run.UpdatedAt.UTC(),
+				RunID:               run.RunID,
+			}
+		}
+	}
+
+	next := now.UTC().Add(ScheduleInterval)
+	if status.LastRun != nil {
+		next = status.LastRun.StartedAt.Add(ScheduleInterval)
+		if next.Before(now) {
+			missed := now.Sub(next)/ScheduleInterval + 1
+			next = next.Add(missed * ScheduleInterval)
+		}
+	}
+	status.NextScheduledRun = next.UTC().Truncate(time.Second)
+
+	return status
+}
+
+// summarize copies the fields status pages need from a run
+func summarize(run *state.RunState) *Run {
+	return &Run{
+		RunID:      run.RunID,
+		Status:     run.Status,
+		StartedAt:  run.CreatedAt.UTC(),
+		UpdatedAt:  run.UpdatedAt.UTC(),
+		SkipReason: run.SkipReason,
+		Error:      run.ErrorMessage,
+	}
+}
+
+// ObjectPutter is the subset of the S3 client Publish needs
+type ObjectPutter interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// NewS3Client returns an S3 client for Publish
+func NewS3Client(ctx context.Context) (*s3.Client, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, tracing.WithAWS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return s3.NewFromConfig(cfg), nil
+}
+
+// Publish writes status as JSON to the destination, replacing the previous status
+func Publish(ctx context.Context, client ObjectPutter, destination Destination, status Status) error {
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal status: %w", err)
+	}
+
+	_, err = client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:       aws.String(destination.Bucket),
+		Key:          aws.String(destination.Key),
+		Body:         bytes.NewReader(data),
+		ContentType:  aws.String("application/json"),
+		CacheControl: aws.String(cacheControl),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write status to s3://%s/%s: %w", destination.Bucket, destination.Key, err)
+	}
+	return nil
+}
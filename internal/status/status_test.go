@@ -0,0 +1,107 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+type fakeObjectPutter struct {
+	input *s3.PutObjectInput
+	body  []byte
+}
+
+func (f *fakeObjectPutter) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	f.input = params
+	body, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.body = body
+	return &s3.PutObjectOutput{}, nil
+}
+
+func TestParse(t *testing.T) {
+	destination, err := Parse(`{"bucket": "hourstats-status"}`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if destination.Bucket != "hourstats-status" || destination.Key != DefaultKey {
+		t.Errorf("unexpected destination %+v", destination)
+	}
+
+	if _, err := Parse(`{"key": "status.json"}`); err == nil {
+		t.Error("expected an error without a bucket")
+	}
+	if destination, err := Parse(""); err != nil || destination != nil {
+		t.Errorf("Parse(empty) = %+v, %v; want nil", destination, err)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	start := time.Date(2025, 9, 2, 12, 0, 0, 0, time.UTC)
+	runs := []state.RunState{
+		{RunID: "run-3", Status: "fetching", CreatedAt: start.Add(time.Hour), UpdatedAt: start.Add(time.Hour)},
+		{RunID: "run-2", Status: "skipped", SkipReason: "posting schedule: quiet hours", OverallSentiment: "positive", NetSentimentPercentage: 12.5, CreatedAt: start.Add(30 * time.Minute), UpdatedAt: start.Add(45 * time.Minute)},
+		{RunID: "run-1", Status: "completed", OverallSentiment: "negative", NetSentimentPercentage: -3, CreatedAt: start, UpdatedAt: start.Add(15 * time.Minute)},
+	}
+
+	status := Build(runs, start.Add(70*time.Minute))
+
+	if status.LastRun == nil || status.LastRun.RunID != "run-3" {
+		t.Errorf("Expected run-3 as the last run, got %+v", status.LastRun)
+	}
+	if status.LastSuccess == nil || status.LastSuccess.RunID != "run-1" {
+		t.Errorf("Expected run-1 as the last success, got %+v", status.LastSuccess)
+	}
+	if status.CurrentSentiment == nil || status.CurrentSentiment.RunID != "run-2" || status.CurrentSentiment.NetSentimentPercent != 12.5 {
+		t.Errorf("Expected the withheld run's sentiment, got %+v", status.CurrentSentiment)
+	}
+	if want := start.Add(90 * time.Minute); !status.NextScheduledRun.Equal(want) {
+		t.Errorf("Expected next run at %v, got %v", want, status.NextScheduledRun)
+	}
+
+	// A stalled schedule projects forward onto the grid rather than into the past
+	status = Build(runs, start.Add(5*time.Hour+10*time.Minute))
+	if want := start.Add(5*time.Hour + 30*time.Minute); !status.NextScheduledRun.Equal(want) {
+		t.Errorf("Expected next run at %v, got %v", want, status.NextScheduledRun)
+	}
+
+	empty := Build(nil, start)
+	if empty.LastRun != nil || empty.LastSuccess != nil || empty.CurrentSentiment != nil {
+		t.Errorf("Expected an empty status without runs, got %+v", empty)
+	}
+	if !empty.NextScheduledRun.Equal(start.Add(ScheduleInterval)) {
+		t.Errorf("Expected next run one interval from now, got %v", empty.NextScheduledRun)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	putter := &fakeObjectPutter{}
+	status := Status{UpdatedAt: time.Date(2025, 9, 2, 12, 0, 0, 0, time.UTC), LastRun: &Run{RunID: "run-1", Status: "completed"}}
+
+	if err := Publish(context.Background(), putter, Destination{Bucket: "hourstats-status", Key: "bot/status.json"}, status); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if aws.ToString(putter.input.Bucket) != "hourstats-status" || aws.ToString(putter.input.Key) != "bot/status.json" {
+		t.Errorf("Unexpected destination s3://%s/%s", aws.ToString(putter.input.Bucket), aws.ToString(putter.input.Key))
+	}
+	if aws.ToString(putter.input.ContentType) != "application/json" || aws.ToString(putter.input.CacheControl) == "" {
+		t.Errorf("Expected JSON content type and cache control, got %+v", putter.input)
+	}
+
+	var decoded Status
+	if err := json.Unmarshal(putter.body, &decoded); err != nil {
+		t.Fatalf("status.json is not valid JSON: %v", err)
+	}
+	if decoded.LastRun == nil || decoded.LastRun.RunID != "run-1" {
+		t.Errorf("Expected run-1 in status.json, got %s", putter.body)
+	}
+}
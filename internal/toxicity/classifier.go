@@ -0,0 +1,205 @@
+package toxicity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Classifier scores one post's toxicity from 0 (not toxic) to 1
+type Classifier interface {
+	Score(ctx context.Context, text string) (float64, error)
+}
+
+// ErrUnscorable marks a post the backend can't score, such as one in an unsupported
+// language; Measure skips it rather than stopping
+var ErrUnscorable = errors.New("post can't be scored")
+
+// LexiconClassifier scores posts by insult, abuse and threat terms. It is crude: it
+// misses sarcasm and context, and reclaimed or quoted terms count the same as meant ones.
+type LexiconClassifier struct {
+	terms   map[string]float64
+	phrases map[string]float64
+}
+
+// Lexicon weights: a single strong term makes a post toxic at the default threshold, a
+// mild one only when it is aimed at someone or repeated
+const (
+	weightStrong = 0.6
+	weightMild   = 0.3
+	// weightTargeted is added when a post with a toxic term addresses its reader
+	weightTargeted = 0.2
+)
+
+var strongTerms = []string{
+	"idiot", "idiots", "moron", "morons", "imbecile", "scum", "scumbag", "subhuman",
+	"degenerate", "degenerates", "vermin", "parasite", "parasites", "retard", "retarded",
+	"bastard", "bastards", "asshole", "assholes", "dickhead", "cunt", "fuckwit", "shithead",
+	"piece of shit", "kill yourself", "kys", "go die", "hope you die", "drop dead",
+	"shut the fuck up", "i will kill you", "deserve to die",
+}
+
+var mildTerms = []string{
+	"stupid", "dumb", "dumbass", "pathetic", "loser", "losers", "clown", "clowns",
+	"trash", "garbage", "disgusting", "worthless", "useless", "ignorant", "jerk", "creep",
+	"fuck you", "screw you", "shut up", "stfu", "get lost", "bitch", "sucks",
+}
+
+// secondPerson marks a post as addressing its reader
+var secondPerson = map[string]bool{"you": true, "you're": true, "youre": true, "your": true, "u": true, "ur": true}
+
+// NewLexiconClassifier creates the built-in keyword classifier
+func NewLexiconClassifier() *LexiconClassifier {
+	c := &LexiconClassifier{terms: make(map[string]float64), phrases: make(map[string]float64)}
+	add := func(terms []string, weight float64) {
+		for _, term := range terms {
+			if strings.Contains(term, " ") {
+				c.phrases[term] = weight
+			} else {
+				c.terms[term] = weight
+			}
+		}
+	}
+	add(mildTerms, weightMild)
+	add(strongTerms, weightStrong)
+	return c
+}
+
+// Score sums the weights of the terms in text, capped at 1
+func (c *LexiconClassifier) Score(ctx context.Context, text string) (float64, error) {
+	lower := strings.ToLower(text)
+	words := strings.FieldsFunc(lower, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '\'')
+	})
+
+	var score float64
+	targeted := false
+	for _, word := range words {
+		score += c.terms[word]
+		targeted = targeted || secondPerson[word]
+	}
+	normalized := " " + strings.Join(words, " ") + " "
+	for phrase, weight := range c.phrases {
+		if strings.Contains(normalized, " "+phrase+" ") {
+			score += weight
+		}
+	}
+
+	if score > 0 && targeted {
+		score += weightTargeted
+	}
+	if score > 1 {
+		score = 1
+	}
+	return score, nil
+}
+
+// DefaultPerspectiveEndpoint is the Perspective API comment analyzer
+const DefaultPerspectiveEndpoint = "https://commentanalyzer.googleapis.com/v1alpha1/comments:analyze"
+
+// perspectiveInterval spaces requests to stay within the default quota of one a second
+const perspectiveInterval = time.Second
+
+// PerspectiveClassifier scores posts with the Perspective API's TOXICITY attribute
+type PerspectiveClassifier struct {
+	endpoint   string
+	apiKey     string
+	httpClient *http.Client
+	interval   time.Duration
+	last       time.Time
+}
+
+// NewPerspectiveClassifier creates a Perspective client; endpoint defaults to
+// DefaultPerspectiveEndpoint
+func NewPerspectiveClassifier(endpoint, apiKey string) *PerspectiveClassifier {
+	if endpoint == "" {
+		endpoint = DefaultPerspectiveEndpoint
+	}
+	return &PerspectiveClassifier{
+		endpoint:   endpoint,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		interval:   perspectiveInterval,
+	}
+}
+
+type perspectiveRequest struct {
+	Comment struct {
+		Text string `json:"text"`
+	} `json:"comment"`
+	RequestedAttributes map[string]struct{} `json:"requestedAttributes"`
+	DoNotStore          bool                `json:"doNotStore"`
+}
+
+type perspectiveResponse struct {
+	AttributeScores map[string]struct {
+		SummaryScore struct {
+			Value float64 `json:"value"`
+		} `json:"summaryScore"`
+	} `json:"attributeScores"`
+}
+
+// Score asks Perspective for the post's toxicity probability. Posts are sent with
+// doNotStore, so Perspective doesn't keep them.
+func (c *PerspectiveClassifier) Score(ctx context.Context, text string) (float64, error) {
+	if wait := c.interval - time.Since(c.last); wait > 0 {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	c.last = time.Now()
+
+	var body perspectiveRequest
+	body.Comment.Text = text
+	body.RequestedAttributes = map[string]struct{}{"TOXICITY": {}}
+	body.DoNotStore = true
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode Perspective request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"?key="+url.QueryEscape(c.apiKey), bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build Perspective request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// The URL carries the key; report only the failure
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return 0, fmt.Errorf("failed to call Perspective: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Perspective rejects text it can't score, such as unsupported languages, with a 400
+	if resp.StatusCode == http.StatusBadRequest {
+		return 0, ErrUnscorable
+	}
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return 0, fmt.Errorf("Perspective returned %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+
+	var parsed perspectiveResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return 0, fmt.Errorf("invalid Perspective response: %w", err)
+	}
+	toxicity, ok := parsed.AttributeScores["TOXICITY"]
+	if !ok {
+		return 0, ErrUnscorable
+	}
+	return toxicity.SummaryScore.Value, nil
+}
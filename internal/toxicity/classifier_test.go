@@ -0,0 +1,67 @@
+package toxicity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLexiconClassifier(t *testing.T) {
+	classifier := NewLexiconClassifier()
+	for _, tc := range []struct {
+		text  string
+		toxic bool
+	}{
+		{"I'm heartbroken about the news today", false},
+		{"This weather is awful and I hate Mondays", false},
+		{"What an idiot", true},
+		{"That take is dumb", false},
+		{"You're so dumb", true},
+		{"Honestly just kill yourself", true},
+		{"Skills like killing time", false},
+	} {
+		score, err := classifier.Score(context.Background(), tc.text)
+		if err != nil {
+			t.Fatalf("Score(%q) error = %v", tc.text, err)
+		}
+		if got := score >= DefaultThreshold; got != tc.toxic {
+			t.Errorf("Score(%q) = %.2f, want toxic %v", tc.text, score, tc.toxic)
+		}
+	}
+}
+
+func TestPerspectiveClassifier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") != "secret" {
+			t.Errorf("Expected the API key in the query, got %q", r.URL.RawQuery)
+		}
+		var request perspectiveRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("Invalid request body: %v", err)
+		}
+		if !request.DoNotStore {
+			t.Error("Expected doNotStore")
+		}
+		if request.Comment.Text == "bonjour" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"message": "Attribute TOXICITY does not support request languages: fr"}}`))
+			return
+		}
+		w.Write([]byte(`{"attributeScores": {"TOXICITY": {"summaryScore": {"value": 0.82, "type": "PROBABILITY"}}}}`))
+	}))
+	defer server.Close()
+
+	classifier := NewPerspectiveClassifier(server.URL, "secret")
+	classifier.interval = 0
+
+	score, err := classifier.Score(context.Background(), "you absolute clown")
+	if err != nil || score != 0.82 {
+		t.Errorf("Score() = %v, %v; want 0.82", score, err)
+	}
+	if _, err := classifier.Score(context.Background(), "bonjour"); !errors.Is(err, ErrUnscorable) {
+		t.Errorf("Expected an unsupported language to be unscorable, got %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+// Package toxicity measures how many of a run's posts are toxic (insulting, abusive or
+// threatening), which is not the same as negative: "I'm heartbroken" is negative but
+// not toxic. It is configured by optional settings stored in SSM.
+package toxicity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// ParameterName holds the JSON toxicity settings; when it is absent toxicity isn't measured
+const ParameterName = "/hourstats/settings/toxicity"
+
+// PerspectiveKeyParameter holds the Perspective API key, as a SecureString
+const PerspectiveKeyParameter = "/hourstats/perspective/api_key"
+
+// Backends that score posts
+const (
+	BackendLexicon     = "lexicon"     // built-in keyword lexicon, scores every post
+	BackendPerspective = "perspective" // Perspective API, scores a sample
+)
+
+// Defaults for omitted settings
+const (
+	DefaultThreshold = 0.5
+	// DefaultPerspectiveSample keeps a run within Perspective's default quota of one
+	// request a second; the lexicon scores every post
+	DefaultPerspectiveSample = 50
+)
+
+// Settings chooses the backend. A post scoring at least Threshold (0 to 1) counts as toxic.
+// SampleSize caps how many posts are scored, spread evenly across the window; 0 scores
+// all of them. Chart plots toxicity beside net sentiment on the weekly chart.
+type Settings struct {
+	Backend    string  `json:"backend"`
+	Threshold  float64 `json:"threshold,omitempty"`
+	SampleSize int     `json:"sampleSize,omitempty"`
+	Chart      bool    `json:"chart,omitempty"`
+}
+
+// ParameterGetter is the subset of the SSM client Load needs
+type ParameterGetter interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Load reads the toxicity settings from SSM. A missing or empty parameter returns nil,
+// which disables toxicity scoring.
+func Load(ctx context.Context, ssmClient ParameterGetter) (*Settings, error) {
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ParameterName),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %s: %w", ParameterName, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return nil, nil
+	}
+	return Parse(*result.Parameter.Value)
+}
+
+// Parse decodes and validates JSON toxicity settings
+func Parse(value string) (*Settings, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var settings Settings
+	if err := json.Unmarshal([]byte(value), &settings); err != nil {
+		return nil, fmt.Errorf("invalid toxicity JSON: %w", err)
+	}
+
+	switch settings.Backend {
+	case "", BackendLexicon:
+		settings.Backend = BackendLexicon
+	case BackendPerspective:
+		if settings.SampleSize == 0 {
+			settings.SampleSize = DefaultPerspectiveSample
+		}
+	default:
+		return nil, fmt.Errorf("unknown toxicity backend %q (want %q or %q)", settings.Backend, BackendLexicon, BackendPerspective)
+	}
+
+	if settings.Threshold == 0 {
+		settings.Threshold = DefaultThreshold
+	}
+	if settings.Threshold < 0 || settings.Threshold > 1 {
+		return nil, fmt.Errorf("toxicity threshold must be between 0 and 1, got %g", settings.Threshold)
+	}
+	if settings.SampleSize < 0 {
+		return nil, fmt.Errorf("toxicity sampleSize must not be negative, got %d", settings.SampleSize)
+	}
+	return &settings, nil
+}
+
+// Classifier returns the configured backend. The Perspective backend reads its API key
+// from PerspectiveKeyParameter.
+func (s *Settings) Classifier(ctx context.Context, ssmClient ParameterGetter) (Classifier, error) {
+	if s.Backend != BackendPerspective {
+		return NewLexiconClassifier(), nil
+	}
+
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(PerspectiveKeyParameter),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", PerspectiveKeyParameter, err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil || *result.Parameter.Value == "" {
+		return nil, fmt.Errorf("%s is empty", PerspectiveKeyParameter)
+	}
+	return NewPerspectiveClassifier("", *result.Parameter.Value), nil
+}
+
+// Result is a run's toxicity
+type Result struct {
+	Scored       int     // posts scored, at most the sample size
+	Toxic        int     // posts scoring at least the threshold
+	Percent      float64 // Toxic as a percentage of Scored
+	AverageScore float64
+}
+
+// Measure scores a sample of texts and counts those at or above threshold, skipping
+// posts the backend can't score. If the backend fails part way, or ctx ends, the posts
+// scored so far are the result; it is only an error when none were scored.
+func Measure(ctx context.Context, classifier Classifier, texts []string, threshold float64, sampleSize int) (Result, error) {
+	var result Result
+	var total float64
+	var lastErr error
+	for _, text := range sample(texts, sampleSize) {
+		if ctx.Err() != nil {
+			lastErr = ctx.Err()
+			break
+		}
+		score, err := classifier.Score(ctx, text)
+		if errors.Is(err, ErrUnscorable) {
+			continue
+		}
+		if err != nil {
+			lastErr = err
+			break
+		}
+		result.Scored++
+		total += score
+		if score >= threshold {
+			result.Toxic++
+		}
+	}
+
+	if result.Scored == 0 {
+		if lastErr == nil {
+			lastErr = errors.New("no posts to score")
+		}
+		return result, fmt.Errorf("failed to score toxicity: %w", lastErr)
+	}
+	result.Percent = float64(result.Toxic) / float64(result.Scored) * 100
+	result.AverageScore = total / float64(result.Scored)
+	return result, nil
+}
+
+// sample picks size texts spread evenly through texts, or all of them when size is 0
+// or at least len(texts)
+func sample(texts []string, size int) []string {
+	if size <= 0 || size >= len(texts) {
+		return texts
+	}
+	picked := make([]string, size)
+	for i := range picked {
+		picked[i] = texts[i*len(texts)/size]
+	}
+	return picked
+}
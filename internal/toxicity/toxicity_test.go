@@ -0,0 +1,93 @@
+package toxicity
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	settings, err := Parse(`{"backend": "perspective", "chart": true}`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if settings.Threshold != DefaultThreshold || settings.SampleSize != DefaultPerspectiveSample || !settings.Chart {
+		t.Errorf("Unexpected perspective defaults %+v", settings)
+	}
+
+	settings, err = Parse(`{}`)
+	if err != nil || settings.Backend != BackendLexicon || settings.SampleSize != 0 {
+		t.Errorf("Expected the lexicon scoring every post by default, got %+v, %v", settings, err)
+	}
+
+	if settings, err := Parse(""); err != nil || settings != nil {
+		t.Errorf("Parse(empty) = %+v, %v; want nil", settings, err)
+	}
+
+	for name, value := range map[string]string{
+		"unknown backend":  `{"backend": "comprehend"}`,
+		"threshold over 1": `{"threshold": 1.5}`,
+		"negative sample":  `{"sampleSize": -1}`,
+		"invalid JSON":     `{"backend": `,
+	} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// scriptedClassifier returns fixed scores in order, then fails
+type scriptedClassifier struct {
+	scores []float64
+	errs   []error
+	calls  int
+}
+
+func (c *scriptedClassifier) Score(ctx context.Context, text string) (float64, error) {
+	i := c.calls
+	c.calls++
+	if i < len(c.errs) && c.errs[i] != nil {
+		return 0, c.errs[i]
+	}
+	if i >= len(c.scores) {
+		return 0, errors.New("quota exceeded")
+	}
+	return c.scores[i], nil
+}
+
+func TestMeasure(t *testing.T) {
+	texts := []string{"a", "b", "c", "d", "e"}
+
+	classifier := &scriptedClassifier{scores: []float64{0.9, 0.1, 0, 0.5, 0.2}, errs: []error{nil, nil, ErrUnscorable}}
+	result, err := Measure(context.Background(), classifier, texts, 0.5, 0)
+	if err != nil {
+		t.Fatalf("Measure() error = %v", err)
+	}
+	// The third post is skipped, and 0.5 meets the threshold
+	if result.Scored != 4 || result.Toxic != 2 || result.Percent != 50 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	// A backend failing part way keeps what was scored
+	classifier = &scriptedClassifier{scores: []float64{0.9}}
+	result, err = Measure(context.Background(), classifier, texts, 0.5, 0)
+	if err != nil || result.Scored != 1 || result.Percent != 100 {
+		t.Errorf("Expected the scored post kept, got %+v, %v", result, err)
+	}
+
+	if _, err := Measure(context.Background(), &scriptedClassifier{}, texts, 0.5, 0); err == nil {
+		t.Error("Expected an error when nothing could be scored")
+	}
+
+	classifier = &scriptedClassifier{scores: []float64{0, 0, 0, 0, 0}}
+	if result, _ := Measure(context.Background(), classifier, texts, 0.5, 2); result.Scored != 2 {
+		t.Errorf("Expected the sample size to cap scoring, got %d", result.Scored)
+	}
+}
+
+func TestSample(t *testing.T) {
+	got := sample([]string{"0", "1", "2", "3", "4", "5"}, 3)
+	if len(got) != 3 || got[0] != "0" || got[1] != "2" || got[2] != "4" {
+		t.Errorf("sample() = %v, want evenly spread [0 2 4]", got)
+	}
+}
@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"context"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span attributes of AWS SDK calls, following the OpenTelemetry RPC conventions
+const (
+	rpcSystemKey  = attribute.Key("rpc.system")
+	rpcServiceKey = attribute.Key("rpc.service")
+	rpcMethodKey  = attribute.Key("rpc.method")
+)
+
+// traceHeader carries the trace to the service called; a lambda invoked with it continues
+// the caller's trace
+const traceHeader = "X-Amzn-Trace-Id"
+
+// WithAWS traces every call made by clients built from the loaded AWS config:
+//
+//	cfg, err := config.LoadDefaultConfig(ctx, tracing.WithAWS)
+//
+// Calls are traced only within a span, such as a traced lambda invocation.
+func WithAWS(o *config.LoadOptions) error {
+	o.APIOptions = append(o.APIOptions, addAWSMiddleware)
+	return nil
+}
+
+func addAWSMiddleware(stack *middleware.Stack) error {
+	// After the SDK's own initialization, which names the service and operation
+	if err := stack.Initialize.Add(middleware.InitializeMiddlewareFunc("TraceCall", traceCall), middleware.After); err != nil {
+		return err
+	}
+	// Before the SDK's recursion detection, which otherwise sets the header to the
+	// invocation's segment rather than this call's span
+	return stack.Build.Add(middleware.BuildMiddlewareFunc("TraceHeader", setTraceHeader), middleware.Before)
+}
+
+// traceCall wraps the call, including its retries, in a span named Service.Operation
+func traceCall(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return next.HandleInitialize(ctx, in)
+	}
+
+	serviceID, operation := awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx)
+	ctx, span := Start(ctx, serviceID+"."+operation,
+		rpcSystemKey.String("aws-api"), rpcServiceKey.String(serviceID), rpcMethodKey.String(operation))
+	out, metadata, err := next.HandleInitialize(ctx, in)
+	End(span, err)
+	return out, metadata, err
+}
+
+func setTraceHeader(ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler) (middleware.BuildOutput, middleware.Metadata, error) {
+	span := trace.SpanContextFromContext(ctx)
+	if req, ok := in.Request.(*smithyhttp.Request); ok && span.IsValid() {
+		req.Header.Set(traceHeader, formatTraceHeader(span))
+	}
+	return next.HandleBuild(ctx, in)
+}
@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span attributes of HTTP requests, following the OpenTelemetry HTTP conventions
+const (
+	httpMethodKey = attribute.Key("http.method")
+	httpURLKey    = attribute.Key("http.url")
+	httpStatusKey = attribute.Key("http.status_code")
+)
+
+// Transport traces requests made through base, or http.DefaultTransport if it is nil.
+// Requests are traced only within a span, and the trace isn't sent to the remote host.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+func (t transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return t.base.RoundTrip(req)
+	}
+
+	// The query is left out: it can hold search terms and cursors
+	url := req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+	ctx, span := Start(ctx, "HTTP "+req.Method+" "+req.URL.Host,
+		httpMethodKey.String(req.Method), httpURLKey.String(url))
+	defer span.End()
+
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(httpStatusKey.Int(resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", resp.StatusCode))
+	}
+	return resp, nil
+}
@@ -0,0 +1,104 @@
+// Package tracing traces a run across the lambdas with OpenTelemetry. On Lambda, spans are
+// sent to the X-Ray daemon as subsegments of each invocation's segment; elsewhere they are
+// written to stdout. AWS SDK calls carry the trace to the lambdas they invoke, so a run can
+// be followed from the orchestrator through the fetcher, processor and sparkline poster.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// daemonAddressEnv is set by Lambda when active tracing is enabled
+const daemonAddressEnv = "AWS_XRAY_DAEMON_ADDRESS"
+
+// RunIDKey is the span attribute holding a run's ID; X-Ray indexes it as an annotation, so
+// traces can be filtered with annotation.run_id = "<run ID>"
+const RunIDKey = attribute.Key("run_id")
+
+var (
+	provider *sdktrace.TracerProvider
+	service  string
+)
+
+// Init installs the tracer provider for the named service. Spans are exported to the X-Ray
+// daemon when Lambda provides one and to stdout otherwise. Until Init is called, spans are
+// discarded.
+func Init(name string) error {
+	var exporter sdktrace.SpanExporter
+	var err error
+	if address := os.Getenv(daemonAddressEnv); address != "" {
+		exporter, err = newXRayExporter(address)
+	} else {
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create span exporter: %w", err)
+	}
+
+	install(name, sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithIDGenerator(xrayIDGenerator{}),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.AlwaysSample())),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", name))),
+	))
+	return nil
+}
+
+// install makes tp the provider spans are started from
+func install(name string, tp *sdktrace.TracerProvider) {
+	provider, service = tp, name
+	otel.SetTracerProvider(tp)
+}
+
+// Start starts a span as a child of the span in ctx, or as a new trace without one
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer("github.com/christophergentle/hourstats-bsky").Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on span, if there was one, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// SetRunID tags the invocation's span with the run it is working on
+func SetRunID(ctx context.Context, runID string) {
+	trace.SpanFromContext(ctx).SetAttributes(RunIDKey.String(runID))
+}
+
+// Handler wraps a lambda handler so each invocation is traced as a span named after the
+// service, continuing the trace Lambda started for it. Spans are flushed before the
+// invocation returns, since Lambda may freeze the function as soon as it does.
+func Handler[E, R any](handle func(context.Context, E) (R, error)) func(context.Context, E) (R, error) {
+	return func(ctx context.Context, event E) (R, error) {
+		ctx, span := Start(withLambdaParent(ctx), service)
+		response, err := handle(ctx, event)
+		End(span, err)
+		Flush(ctx)
+		return response, err
+	}
+}
+
+// Flush exports any spans still buffered
+func Flush(ctx context.Context) {
+	if provider == nil {
+		return
+	}
+	if err := provider.ForceFlush(ctx); err != nil {
+		log.Printf("Failed to flush trace spans: %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	awslambda "github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/smithy-go/middleware"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const lambdaHeader = "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1"
+
+// recordSpans installs a provider that keeps finished spans in memory
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	install("hourstats-test", sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter), sdktrace.WithIDGenerator(xrayIDGenerator{})))
+	t.Cleanup(func() { install("", sdktrace.NewTracerProvider()) })
+	return exporter
+}
+
+func TestTraceHeader(t *testing.T) {
+	parent, ok := parseTraceHeader(lambdaHeader)
+	if !ok {
+		t.Fatalf("Failed to parse %q", lambdaHeader)
+	}
+	if !parent.IsSampled() || !parent.IsRemote() {
+		t.Errorf("Expected a sampled remote parent, got %+v", parent)
+	}
+	if got := formatTraceHeader(parent); got != lambdaHeader {
+		t.Errorf("formatTraceHeader() = %q, want %q", got, lambdaHeader)
+	}
+
+	for _, header := range []string{"", "Root=2-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8", "Root=1-5759e988-bd862e3fe1be46a994272793"} {
+		if _, ok := parseTraceHeader(header); ok {
+			t.Errorf("Expected %q to be rejected", header)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	exporter := recordSpans(t)
+	failure := errors.New("no posts")
+	handle := Handler(func(ctx context.Context, runID string) (string, error) {
+		SetRunID(ctx, runID)
+		return "done", failure
+	})
+
+	ctx := context.WithValue(context.Background(), lambdaTraceKey, lambdaHeader)
+	if response, err := handle(ctx, "run-1"); response != "done" || err != failure {
+		t.Fatalf("handle() = %q, %v; want the handler's response and error", response, err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	doc := toSegment(spans[0].Snapshot())
+	if doc.Name != "hourstats-test" || doc.TraceID != "1-5759e988-bd862e3fe1be46a994272793" || doc.ParentID != "53995c3f42cd8ad8" {
+		t.Errorf("Expected a subsegment of the Lambda segment, got %+v", doc)
+	}
+	if !doc.Fault || doc.Cause.Exceptions[0].Message != "no posts" {
+		t.Errorf("Expected the error as the fault's cause, got %+v", doc)
+	}
+	if doc.Annotations["run_id"] != "run-1" {
+		t.Errorf("Expected the run ID annotation, got %v", doc.Annotations)
+	}
+}
+
+func TestWithAWS(t *testing.T) {
+	exporter := recordSpans(t)
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(traceHeader)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := awslambda.New(awslambda.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+		APIOptions:   []func(*middleware.Stack) error{addAWSMiddleware},
+	})
+
+	// Outside a span nothing is traced
+	if _, err := client.Invoke(context.Background(), &awslambda.InvokeInput{FunctionName: aws.String("hourstats-processor")}); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if header != "" || len(exporter.GetSpans()) != 0 {
+		t.Fatalf("Expected an untraced call, got header %q and %d spans", header, len(exporter.GetSpans()))
+	}
+
+	ctx, span := Start(context.Background(), "fetcher")
+	if _, err := client.Invoke(ctx, &awslambda.InvokeInput{FunctionName: aws.String("hourstats-processor")}); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 || spans[0].Name != "Lambda.Invoke" {
+		t.Fatalf("Expected a Lambda.Invoke span under the fetcher's, got %v", spans)
+	}
+	invoke := spans[0]
+	if invoke.Parent.SpanID() != span.SpanContext().SpanID() || invoke.Status.Code == codes.Error {
+		t.Errorf("Expected a successful child of the fetcher's span, got %+v", invoke)
+	}
+	if want := formatTraceHeader(invoke.SpanContext); header != want {
+		t.Errorf("Expected the invoked lambda to continue the trace with %q, got %q", want, header)
+	}
+	if doc := toSegment(invoke.Snapshot()); doc.Namespace != "aws" {
+		t.Errorf("Expected an AWS subsegment, got namespace %q", doc.Namespace)
+	}
+}
+
+func TestXRayIDGenerator(t *testing.T) {
+	before := time.Now().Unix()
+	traceID, _ := xrayIDGenerator{}.NewIDs(context.Background())
+	epoch, err := strconv.ParseInt(xrayTraceID(traceID)[2:10], 16, 64)
+	if err != nil || epoch < before || epoch > time.Now().Unix() {
+		t.Errorf("Expected the trace ID %s to start with the current time", xrayTraceID(traceID))
+	}
+}
@@ -0,0 +1,212 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceHeaderEnv holds the X-Ray trace header of the current Lambda invocation
+const traceHeaderEnv = "_X_AMZN_TRACE_ID"
+
+// lambdaTraceKey is the context key the Lambda runtime stores the trace header under
+const lambdaTraceKey = "x-amzn-trace-id"
+
+// daemonHeader precedes every segment document sent to the X-Ray daemon
+const daemonHeader = `{"format": "json", "version": 1}` + "\n"
+
+// withLambdaParent makes the invocation's X-Ray segment the remote parent of spans started
+// from ctx, so they appear under it in X-Ray
+func withLambdaParent(ctx context.Context) context.Context {
+	header, _ := ctx.Value(lambdaTraceKey).(string)
+	if header == "" {
+		header = os.Getenv(traceHeaderEnv)
+	}
+	if parent, ok := parseTraceHeader(header); ok {
+		return trace.ContextWithRemoteSpanContext(ctx, parent)
+	}
+	return ctx
+}
+
+// parseTraceHeader reads an X-Ray trace header: Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1
+func parseTraceHeader(header string) (trace.SpanContext, bool) {
+	var config trace.SpanContextConfig
+	for _, field := range strings.Split(header, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+		switch key {
+		case "Root":
+			parts := strings.Split(value, "-")
+			if len(parts) != 3 || parts[0] != "1" {
+				return trace.SpanContext{}, false
+			}
+			traceID, err := trace.TraceIDFromHex(parts[1] + parts[2])
+			if err != nil {
+				return trace.SpanContext{}, false
+			}
+			config.TraceID = traceID
+		case "Parent":
+			spanID, err := trace.SpanIDFromHex(value)
+			if err != nil {
+				return trace.SpanContext{}, false
+			}
+			config.SpanID = spanID
+		case "Sampled":
+			if value == "1" {
+				config.TraceFlags = trace.FlagsSampled
+			}
+		}
+	}
+	config.Remote = true
+	parent := trace.NewSpanContext(config)
+	return parent, parent.IsValid()
+}
+
+// formatTraceHeader writes span as the parent in an X-Ray trace header
+func formatTraceHeader(span trace.SpanContext) string {
+	sampled := "0"
+	if span.IsSampled() {
+		sampled = "1"
+	}
+	return fmt.Sprintf("Root=%s;Parent=%s;Sampled=%s", xrayTraceID(span.TraceID()), span.SpanID(), sampled)
+}
+
+// xrayTraceID formats a trace ID the way X-Ray does: 1-<start epoch seconds>-<random>
+func xrayTraceID(id trace.TraceID) string {
+	hexID := id.String()
+	return "1-" + hexID[:8] + "-" + hexID[8:]
+}
+
+// xrayIDGenerator starts trace IDs with the current time, which X-Ray requires
+type xrayIDGenerator struct{}
+
+func (xrayIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	var traceID trace.TraceID
+	rand.Read(traceID[4:])
+	binary.BigEndian.PutUint32(traceID[:4], uint32(time.Now().Unix()))
+	return traceID, xrayIDGenerator{}.NewSpanID(ctx, traceID)
+}
+
+func (xrayIDGenerator) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	var spanID trace.SpanID
+	rand.Read(spanID[:])
+	return spanID
+}
+
+// segment is an X-Ray subsegment document, sent on its own rather than nested in its parent
+type segment struct {
+	Name        string                       `json:"name"`
+	ID          string                       `json:"id"`
+	TraceID     string                       `json:"trace_id"`
+	ParentID    string                       `json:"parent_id,omitempty"`
+	Type        string                       `json:"type"`
+	Namespace   string                       `json:"namespace,omitempty"`
+	StartTime   float64                      `json:"start_time"`
+	EndTime     float64                      `json:"end_time"`
+	Fault       bool                         `json:"fault,omitempty"`
+	Cause       *cause                       `json:"cause,omitempty"`
+	Annotations map[string]string            `json:"annotations,omitempty"`
+	Metadata    map[string]map[string]string `json:"metadata,omitempty"`
+}
+
+type cause struct {
+	Exceptions []exception `json:"exceptions"`
+}
+
+type exception struct {
+	Message string `json:"message"`
+}
+
+// toSegment converts a finished span to a subsegment of its parent
+func toSegment(span sdktrace.ReadOnlySpan) segment {
+	doc := segment{
+		Name:      span.Name(),
+		ID:        span.SpanContext().SpanID().String(),
+		TraceID:   xrayTraceID(span.SpanContext().TraceID()),
+		Type:      "subsegment",
+		StartTime: seconds(span.StartTime()),
+		EndTime:   seconds(span.EndTime()),
+	}
+	if span.Parent().IsValid() {
+		doc.ParentID = span.Parent().SpanID().String()
+	}
+	if span.Status().Code == codes.Error {
+		doc.Fault = true
+		doc.Cause = &cause{Exceptions: []exception{{Message: span.Status().Description}}}
+	}
+
+	for _, attr := range span.Attributes() {
+		switch attr.Key {
+		case RunIDKey:
+			if doc.Annotations == nil {
+				doc.Annotations = make(map[string]string)
+			}
+			doc.Annotations[string(attr.Key)] = attr.Value.Emit()
+		case rpcSystemKey:
+			doc.Namespace = "aws"
+		case httpMethodKey:
+			doc.Namespace = "remote"
+		}
+		if doc.Metadata == nil {
+			doc.Metadata = map[string]map[string]string{"default": {}}
+		}
+		doc.Metadata["default"][string(attr.Key)] = attr.Value.Emit()
+	}
+	return doc
+}
+
+// seconds is t as fractional Unix seconds, X-Ray's timestamp format
+func seconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Second)
+}
+
+// xrayExporter sends spans to the X-Ray daemon over UDP, one document per datagram
+type xrayExporter struct {
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// newXRayExporter connects to the daemon at address, either host:port or Lambda's
+// "udp:host:port tcp:host:port" form
+func newXRayExporter(address string) (*xrayExporter, error) {
+	for _, field := range strings.Fields(address) {
+		if udp, ok := strings.CutPrefix(field, "udp:"); ok {
+			address = udp
+		}
+	}
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the X-Ray daemon at %s: %w", address, err)
+	}
+	return &xrayExporter{conn: conn}, nil
+}
+
+func (e *xrayExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, span := range spans {
+		doc, err := json.Marshal(toSegment(span))
+		if err != nil {
+			return fmt.Errorf("failed to marshal segment: %w", err)
+		}
+		if _, err := e.conn.Write(append([]byte(daemonHeader), doc...)); err != nil {
+			return fmt.Errorf("failed to send segment: %w", err)
+		}
+	}
+	return nil
+}
+
+func (e *xrayExporter) Shutdown(ctx context.Context) error {
+	return e.conn.Close()
+}
@@ -0,0 +1,91 @@
+// Package bluesky is a simplified Bluesky client for collecting posts to score with the
+// sentiment package and posting the results. It searches public posts the way HourStats
+// does, leaving out posts with adult content labels.
+//
+// This package is a stable public API; the internals behind it may change between releases.
+package bluesky
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/client"
+	"github.com/christophergentle/hourstats-bsky/pkg/sentiment"
+)
+
+// AllPosts searches every public post
+const AllPosts = client.GlobalSearchQuery
+
+// Client is a Bluesky account's session
+type Client struct {
+	client *client.BlueskyClient
+}
+
+// New creates a client for a handle and app password; call Login before using it
+func New(handle, appPassword string) *Client {
+	return &Client{client: client.New(handle, appPassword)}
+}
+
+// Login starts a session
+func (c *Client) Login() error {
+	return c.client.Authenticate()
+}
+
+// Search returns posts matching query created since the given time, newest first, up to
+// limit posts; a limit of 0 collects every post back to since. AllPosts searches every
+// public post.
+func (c *Client) Search(ctx context.Context, query string, since time.Time, limit int) ([]sentiment.Post, error) {
+	var posts []sentiment.Post
+	seen := make(map[string]bool)
+	cursor := ""
+	for {
+		batch, nextCursor, hasMore, err := c.client.SearchPostsBatch(ctx, query, cursor, since)
+		if err != nil {
+			return posts, fmt.Errorf("failed to search posts: %w", err)
+		}
+
+		reachedSince := false
+		for _, post := range batch {
+			createdAt, err := time.Parse(time.RFC3339, post.CreatedAt)
+			if err == nil && createdAt.Before(since) {
+				reachedSince = true
+				continue
+			}
+			if seen[post.URI] {
+				continue
+			}
+			seen[post.URI] = true
+			posts = append(posts, sentiment.Post{
+				URI:       post.URI,
+				Text:      post.Text,
+				Author:    post.Author,
+				Likes:     post.Likes,
+				Reposts:   post.Reposts,
+				Replies:   post.Replies,
+				CreatedAt: createdAt,
+				Langs:     post.Langs,
+			})
+			if limit > 0 && len(posts) >= limit {
+				return posts, nil
+			}
+		}
+
+		if reachedSince || !hasMore || nextCursor == "" {
+			return posts, nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// Post publishes a text post
+func (c *Client) Post(ctx context.Context, text string) error {
+	return c.client.PostText(ctx, text)
+}
+
+// PostImage publishes a text post with an image, such as a sparkline PNG, and returns the
+// post's URI
+func (c *Client) PostImage(ctx context.Context, text string, image []byte, altText string) (string, error) {
+	uri, _, err := c.client.PostWithImage(ctx, text, image, altText)
+	return uri, err
+}
@@ -0,0 +1,36 @@
+package bluesky_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/pkg/bluesky"
+	"github.com/christophergentle/hourstats-bsky/pkg/sentiment"
+	"github.com/christophergentle/hourstats-bsky/pkg/summary"
+)
+
+// Score the last hour of posts about Go and post the summary
+func Example() {
+	ctx := context.Background()
+	client := bluesky.New("your-handle.bsky.social", "your-app-password")
+	if err := client.Login(); err != nil {
+		log.Fatal(err)
+	}
+
+	posts, err := client.Search(ctx, "golang", time.Now().Add(-time.Hour), 1000)
+	if err != nil {
+		log.Fatal(err)
+	}
+	results, err := sentiment.New().Analyze(posts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	text := summary.Format(sentiment.Summarize(results), summary.TopPosts(results, 5), summary.Options{IntervalMinutes: 60})
+	if err := client.Post(ctx, text); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(text)
+}
@@ -0,0 +1,38 @@
+package sentiment_test
+
+import (
+	"fmt"
+
+	"github.com/christophergentle/hourstats-bsky/pkg/sentiment"
+)
+
+func ExampleAnalyzer_Text() {
+	analyzer := sentiment.New()
+
+	result := analyzer.Text("What a wonderful, happy day!")
+	fmt.Printf("%s %.2f\n", result.Label, result.Score)
+
+	// Declared languages choose the lexicon
+	result = analyzer.Text("¡Qué día tan maravilloso!", "es")
+	fmt.Println(result.Label)
+	// Output:
+	// positive 0.83
+	// positive
+}
+
+func ExampleSummarize() {
+	analyzer := sentiment.New()
+	results, err := analyzer.Analyze([]sentiment.Post{
+		{Author: "alice.bsky.social", Text: "What a wonderful, happy day!"},
+		{Author: "bob.bsky.social", Text: "So glad to see you all again"},
+		{Author: "carol.bsky.social", Text: "The train leaves at nine."},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	overall := sentiment.Summarize(results)
+	fmt.Printf("%s, %+.1f%% net sentiment over %d posts\n", overall.Label, overall.NetPercent, overall.Posts)
+	// Output:
+	// positive, +44.5% net sentiment over 3 posts
+}
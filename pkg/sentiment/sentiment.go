@@ -0,0 +1,145 @@
+// Package sentiment scores the sentiment of Bluesky posts the way HourStats does: VADER
+// compound scores, with lexicons for Spanish, Portuguese and Japanese posts chosen by their
+// declared language, and a keyword fallback for English posts VADER finds neutral.
+//
+// This package is a stable public API; the internals behind it may change between releases.
+package sentiment
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/aggregation"
+	"github.com/christophergentle/hourstats-bsky/internal/analyzer"
+)
+
+// Label is the sentiment category of a post or a set of posts
+type Label string
+
+// Sentiment labels
+const (
+	Positive Label = "positive"
+	Neutral  Label = "neutral"
+	Negative Label = "negative"
+)
+
+// Threshold is the average compound score beyond which a set of posts is positive or
+// negative; it is HourStats' default category threshold
+const Threshold = aggregation.DefaultThreshold
+
+// Post is a post to score
+type Post struct {
+	URI     string
+	Text    string
+	Author  string
+	Likes   int
+	Reposts int
+	Replies int
+	// CreatedAt places the post in its window; it is optional for scoring
+	CreatedAt time.Time
+	// Langs are the language tags the author declared, e.g. "es"; they choose the lexicon
+	Langs []string
+}
+
+// Result is a scored post
+type Result struct {
+	Post
+	Label Label
+	// Score is the VADER compound score, from -1 to +1
+	Score float64
+	// Engagement is replies + likes + reposts, the ranking HourStats uses for top posts
+	Engagement int
+	Emotions   Emotions
+}
+
+// Emotions are the shares of a post's emotion words expressing each emotion. They sum to 1
+// when the post has any emotion words and are all 0 otherwise.
+type Emotions struct {
+	Joy     float64
+	Anger   float64
+	Sadness float64
+	Fear    float64
+}
+
+// Analyzer scores posts; it is safe for concurrent use
+type Analyzer struct {
+	analyzer *analyzer.SentimentAnalyzer
+}
+
+// New creates an analyzer; creating one loads the lexicons, so reuse it
+func New() *Analyzer {
+	return &Analyzer{analyzer: analyzer.New()}
+}
+
+// Text scores a single piece of text, optionally in the declared languages
+func (a *Analyzer) Text(text string, langs ...string) Result {
+	results, _ := a.Analyze([]Post{{Text: text, Langs: langs}})
+	return results[0]
+}
+
+// Analyze scores posts, returning results in the same order
+func (a *Analyzer) Analyze(posts []Post) ([]Result, error) {
+	analyzerPosts := make([]analyzer.Post, len(posts))
+	for i, post := range posts {
+		analyzerPosts[i] = analyzer.Post{
+			URI:     post.URI,
+			Text:    post.Text,
+			Author:  post.Author,
+			Likes:   post.Likes,
+			Reposts: post.Reposts,
+			Replies: post.Replies,
+			Langs:   post.Langs,
+		}
+		if !post.CreatedAt.IsZero() {
+			analyzerPosts[i].CreatedAt = post.CreatedAt.UTC().Format(time.RFC3339)
+		}
+	}
+
+	analyzed, err := a.analyzer.AnalyzePosts(analyzerPosts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze posts: %w", err)
+	}
+
+	results := make([]Result, len(posts))
+	for i, post := range posts {
+		results[i] = Result{
+			Post:       post,
+			Label:      Label(analyzed[i].Sentiment),
+			Score:      analyzed[i].SentimentScore,
+			Engagement: post.Replies + post.Likes + post.Reposts,
+			Emotions: Emotions{
+				Joy:     analyzed[i].Emotions.Joy,
+				Anger:   analyzed[i].Emotions.Anger,
+				Sadness: analyzed[i].Emotions.Sadness,
+				Fear:    analyzed[i].Emotions.Fear,
+			},
+		}
+	}
+	return results, nil
+}
+
+// Summary is the overall sentiment of a set of posts
+type Summary struct {
+	Label Label
+	// AverageScore is the mean compound score, each clamped to -1 to +1
+	AverageScore float64
+	// NetPercent is AverageScore as a percentage, the figure HourStats posts
+	NetPercent float64
+	Posts      int
+}
+
+// Summarize averages the results' compound scores into an overall sentiment
+func Summarize(results []Result) Summary {
+	if len(results) == 0 {
+		return Summary{Label: Neutral}
+	}
+
+	var total float64
+	for _, result := range results {
+		total += max(-1.0, min(1.0, result.Score))
+	}
+	average := total / float64(len(results))
+
+	label := Label(aggregation.Categorize(average, Threshold))
+	return Summary{Label: label, AverageScore: average, NetPercent: average * 100.0, Posts: len(results)}
+}
@@ -0,0 +1,59 @@
+package sparkline_test
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/pkg/sparkline"
+)
+
+func ExampleWeekly() {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	var points []sparkline.Point
+	for i := 0; i < 7*48; i++ {
+		points = append(points, sparkline.Point{
+			Time:       start.Add(time.Duration(i) * 30 * time.Minute),
+			NetPercent: 10 * math.Sin(float64(i)/20),
+		})
+	}
+
+	png, err := sparkline.Weekly(points, &sparkline.Options{Title: "Sentiment this week", ZeroLine: true})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(bytes.HasPrefix(png, []byte("\x89PNG")))
+	// Output: true
+}
+
+func ExampleYearly() {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var days []sparkline.Day
+	for i := 0; i < 365; i++ {
+		days = append(days, sparkline.Day{Date: start.AddDate(0, 0, i), NetPercent: 5 * math.Cos(float64(i)/30)})
+	}
+
+	png, err := sparkline.Yearly(days, &sparkline.Options{MovingAverageDays: 7})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(bytes.HasPrefix(png, []byte("\x89PNG")))
+	// Output: true
+}
+
+func ExampleRange() {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var days []sparkline.Day
+	for i := 0; i < 365; i++ {
+		days = append(days, sparkline.Day{Date: start.AddDate(0, 0, i), NetPercent: 5 * math.Cos(float64(i)/30)})
+	}
+
+	// The second quarter of 2025, one point per week
+	png, err := sparkline.Range(days, start.AddDate(0, 3, 0), start.AddDate(0, 6, 0), sparkline.WeeklyResolution, &sparkline.Options{Title: "Q2 2025"})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(bytes.HasPrefix(png, []byte("\x89PNG")))
+	// Output: true
+}
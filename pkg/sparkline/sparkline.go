@@ -0,0 +1,173 @@
+// Package sparkline renders sentiment charts as PNG images, as HourStats posts them: a
+// weekly chart of net sentiment per run with its smoothed trend and average, a yearly
+// chart of daily averages, and the same chart over any range of days, daily or weekly.
+//
+// This package is a stable public API; the internals behind it may change between releases.
+package sparkline
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/christophergentle/hourstats-bsky/internal/sparkline"
+	"github.com/christophergentle/hourstats-bsky/internal/state"
+)
+
+// Point is one measurement of net sentiment on the weekly chart
+type Point struct {
+	Time time.Time
+	// NetPercent is the net sentiment percentage, from -100 to +100
+	NetPercent float64
+	// Volatility is the standard deviation of net sentiment around Time, drawn when
+	// Options.VolatilityBand is set; nil leaves a gap in the band
+	Volatility *float64
+}
+
+// Day is one day's average net sentiment on the yearly chart
+type Day struct {
+	Date       time.Time
+	NetPercent float64
+}
+
+// Resolution is how much time each point of a range chart covers
+type Resolution string
+
+const (
+	// DailyResolution charts each day as given
+	DailyResolution Resolution = "daily"
+	// WeeklyResolution averages the days of each week, Monday to Sunday (UTC), into one point
+	WeeklyResolution Resolution = "weekly"
+)
+
+// Options adjust a chart; nil or zero values keep the defaults, 1200×800 for the weekly
+// chart and 1500×1000 for the yearly and range ones
+type Options struct {
+	Width  int
+	Height int
+	// Title replaces the chart's caption
+	Title string
+	// FontPath is a TrueType or OpenType font for chart text; empty uses Go Regular
+	FontPath string
+
+	// ZeroLine emphasizes the 0% line on the weekly chart
+	ZeroLine bool
+	// StdDevBand shades one standard deviation either side of the weekly average
+	StdDevBand bool
+	// VolatilityBand shades each point's volatility either side of the weekly line
+	VolatilityBand bool
+	// MovingAverageDays draws a trailing moving average on the yearly and range charts; 0
+	// disables it
+	MovingAverageDays int
+	// InterpolateGaps bridges gaps wider than twice the usual spacing, or missing days on
+	// the yearly chart, with a dashed line; the average and extremes leave the gaps out
+	InterpolateGaps bool
+}
+
+// Weekly renders points as the weekly sentiment chart
+func Weekly(points []Point, options *Options) ([]byte, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	config := sparkline.DefaultConfig()
+	config.Width, config.Height = sizeOr(options.Width, config.Width), sizeOr(options.Height, config.Height)
+	config.FontPath = options.FontPath
+	config.ZeroLine = options.ZeroLine
+	config.StdDevBand = options.StdDevBand
+	config.VolatilityBand = options.VolatilityBand
+	if options.Title != "" {
+		labels := sparkline.DefaultLabels()
+		labels.WeeklyTitle = options.Title
+		config.Labels = labels
+	}
+
+	dataPoints := make([]state.SentimentDataPoint, len(points))
+	for i, point := range points {
+		dataPoints[i] = state.SentimentDataPoint{
+			Timestamp:            point.Time,
+			NetSentimentPercent:  point.NetPercent,
+			AverageCompoundScore: point.NetPercent / 100.0,
+			Volatility:           point.Volatility,
+		}
+	}
+
+	if options.InterpolateGaps {
+		dataPoints = state.InterpolateGaps(dataPoints, 0)
+	}
+
+	chart, err := sparkline.NewSparklineGenerator(config).GenerateSentimentSparkline(dataPoints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render weekly chart: %w", err)
+	}
+	return chart, nil
+}
+
+// Yearly renders days as the yearly sentiment chart
+func Yearly(days []Day, options *Options) ([]byte, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	dataPoints := dailyPoints(days)
+	if options.InterpolateGaps {
+		dataPoints = state.InterpolateDailyGaps(dataPoints)
+	}
+
+	chart, err := rangeGenerator(options).GenerateYearlySentimentSparkline(dataPoints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render yearly chart: %w", err)
+	}
+	return chart, nil
+}
+
+// Range renders the days in [start, end) as the yearly chart does, one point per day or,
+// at WeeklyResolution, per week. InterpolateGaps applies to daily charts only.
+func Range(days []Day, start, end time.Time, resolution Resolution, options *Options) ([]byte, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	chartRange := sparkline.ChartRange{Start: start, End: end, Resolution: sparkline.Resolution(resolution)}
+	if err := chartRange.Validate(); err != nil {
+		return nil, err
+	}
+	dataPoints := chartRange.Points(dailyPoints(days))
+	if options.InterpolateGaps && resolution == DailyResolution {
+		dataPoints = state.InterpolateDailyGaps(dataPoints)
+	}
+
+	chart, err := rangeGenerator(options).GenerateYearlySentimentSparkline(dataPoints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render range chart: %w", err)
+	}
+	return chart, nil
+}
+
+// rangeGenerator returns a generator for the yearly and range charts with options applied
+func rangeGenerator(options *Options) *sparkline.RangeChartGenerator {
+	config := sparkline.DefaultYearlyConfig()
+	config.Width, config.Height = sizeOr(options.Width, config.Width), sizeOr(options.Height, config.Height)
+	config.FontPath = options.FontPath
+	config.MovingAverageDays = options.MovingAverageDays
+	return sparkline.NewRangeChartGenerator(config, options.Title)
+}
+
+// dailyPoints converts days to the chart's daily points
+func dailyPoints(days []Day) []state.YearlySparklineDataPoint {
+	dataPoints := make([]state.YearlySparklineDataPoint, len(days))
+	for i, day := range days {
+		dataPoints[i] = state.YearlySparklineDataPoint{
+			Date:                day.Date.Format("2006-01-02"),
+			AverageSentiment:    day.NetPercent,
+			MinSentiment:        day.NetPercent,
+			MaxSentiment:        day.NetPercent,
+			Timestamp:           day.Date,
+			NetSentimentPercent: day.NetPercent,
+		}
+	}
+	return dataPoints
+}
+
+func sizeOr(size, fallback int) int {
+	if size > 0 {
+		return size
+	}
+	return fallback
+}
@@ -0,0 +1,38 @@
+package summary_test
+
+import (
+	"fmt"
+
+	"github.com/christophergentle/hourstats-bsky/pkg/sentiment"
+	"github.com/christophergentle/hourstats-bsky/pkg/summary"
+)
+
+func ExampleFormat() {
+	results, err := sentiment.New().Analyze([]sentiment.Post{
+		{URI: "at://did:plc:alice/app.bsky.feed.post/1", Author: "alice.bsky.social", Text: "What a wonderful, happy day!", Likes: 40},
+		{URI: "at://did:plc:bob/app.bsky.feed.post/1", Author: "bob.bsky.social", Text: "This is terrible and I hate it.", Likes: 12},
+		{URI: "at://did:plc:carol/app.bsky.feed.post/1", Author: "carol.bsky.social", Text: "The train leaves at nine.", Likes: 25},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	text := summary.Format(sentiment.Summarize(results), summary.TopPosts(results, 2), summary.Options{
+		IntervalMinutes: 60,
+		Notes:           []string{"from a three post sample"},
+	})
+	fmt.Println(text)
+	// Output:
+	// Bluesky is #hesitant
+	// +1.6% sentiment in the last hour
+	//
+	// 1. @alice.bsky.social +
+	// 2. @carol.bsky.social x
+	// from a three post sample
+	// #BlueskySentiment #hourstats
+}
+
+func ExampleMood() {
+	fmt.Println(summary.Mood(-40), summary.Mood(0), summary.Mood(40))
+	// Output: worried reserved merry
+}
@@ -0,0 +1,80 @@
+// Package summary lays out sentiment summaries as HourStats posts them to Bluesky: the
+// mood word as a hashtag, the net sentiment, the top posts marked with their sentiment
+// and optional notes, within Bluesky's 300 character limit.
+//
+// This package is a stable public API; the internals behind it may change between releases.
+package summary
+
+import (
+	"sort"
+
+	"github.com/christophergentle/hourstats-bsky/internal/formatter"
+	"github.com/christophergentle/hourstats-bsky/pkg/sentiment"
+)
+
+// MaxLength is Bluesky's post length limit, in characters
+const MaxLength = 300
+
+// Template is a layout of the summary
+type Template string
+
+// Summary templates
+const (
+	// Classic leads with the mood hashtag and marks top posts +, - or x
+	Classic Template = Template(formatter.TemplateClassic)
+	// ScoreFirst leads with the net sentiment and follows it with the mood hashtag
+	ScoreFirst Template = Template(formatter.TemplateScoreFirst)
+	// Emoji marks top posts with coloured circles instead of +, - or x
+	Emoji Template = Template(formatter.TemplateEmoji)
+)
+
+// TopPost is a post listed in the summary
+type TopPost struct {
+	Author string // handle, without the @
+	URI    string
+	Label  sentiment.Label
+}
+
+// Options adjust a summary; the zero value is a classic summary without a window
+type Options struct {
+	Template Template
+	// IntervalMinutes describes the window, e.g. 30 adds "in the last 30 min"; 0 leaves it out
+	IntervalMinutes int
+	// Notes are appended as lines after the top posts; empty notes are skipped
+	Notes []string
+}
+
+// Format lays out a summary of the overall sentiment and its top posts
+func Format(overall sentiment.Summary, topPosts []TopPost, options Options) string {
+	posts := make([]formatter.Post, len(topPosts))
+	for i, post := range topPosts {
+		posts[i] = formatter.Post{URI: post.URI, Author: post.Author, Sentiment: string(post.Label)}
+	}
+
+	template := options.Template
+	if template == "" {
+		template = Classic
+	}
+	content, _ := formatter.FormatPostContentWithTemplate(formatter.Template(template), posts, string(overall.Label),
+		options.IntervalMinutes, overall.Posts, overall.AverageScore, options.Notes...)
+	return content
+}
+
+// Mood names the mood of a net sentiment percentage, e.g. "content"
+func Mood(netPercent float64) string {
+	return formatter.MoodWord(netPercent)
+}
+
+// TopPosts ranks results by engagement and returns the top n as summary posts
+func TopPosts(results []sentiment.Result, n int) []TopPost {
+	ranked := append([]sentiment.Result(nil), results...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Engagement > ranked[j].Engagement
+	})
+
+	topPosts := make([]TopPost, 0, min(n, len(ranked)))
+	for _, result := range ranked[:min(n, len(ranked))] {
+		topPosts = append(topPosts, TopPost{Author: result.Author, URI: result.URI, Label: result.Label})
+	}
+	return topPosts
+}
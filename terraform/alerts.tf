@@ -0,0 +1,28 @@
+# Outage alert: the processor emits OutageDetected (1 when its health gate finds the Bluesky
+# API erroring or post volume collapsed, 0 otherwise) for every run it checks
+variable "alert_topic_arn" {
+  description = "SNS topic notified when the processor detects a Bluesky outage; empty records the alarm without notifying anyone"
+  type        = string
+  default     = ""
+}
+
+resource "aws_cloudwatch_metric_alarm" "outage_detected" {
+  alarm_name          = "${var.function_name}-outage-detected"
+  alarm_description   = "The processor skipped a summary because Bluesky looked down"
+  namespace           = "HourStats"
+  metric_name         = "OutageDetected"
+  dimensions          = { Function = "processor" }
+  statistic           = "Maximum"
+  period              = 3600
+  evaluation_periods  = 1
+  threshold           = 1
+  comparison_operator = "GreaterThanOrEqualToThreshold"
+  treat_missing_data  = "notBreaching"
+  alarm_actions       = var.alert_topic_arn == "" ? [] : [var.alert_topic_arn]
+  ok_actions          = var.alert_topic_arn == "" ? [] : [var.alert_topic_arn]
+
+  tags = {
+    Name        = "${var.function_name}-outage-detected"
+    Environment = "production"
+  }
+}
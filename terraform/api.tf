@@ -0,0 +1,151 @@
+# Read-only query API over run data, for third-party dashboards
+
+# The API gets its own role so it can only read the tables
+resource "aws_iam_role" "api_role" {
+  name = "${var.function_name}-api-role"
+
+  assume_role_policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Action = "sts:AssumeRole"
+        Effect = "Allow"
+        Principal = {
+          Service = "lambda.amazonaws.com"
+        }
+      }
+    ]
+  })
+
+  tags = {
+    Name        = "${var.function_name}-api-role"
+    Environment = "production"
+  }
+}
+
+# IAM policy for the API Lambda to read the tables
+resource "aws_iam_policy" "api_read_access" {
+  name        = "HourStatsAPIReadAccess"
+  description = "Read-only access to the HourStats tables for the query API"
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect = "Allow"
+        Action = [
+          "dynamodb:GetItem",
+          "dynamodb:Query",
+          "dynamodb:Scan"
+        ]
+        Resource = [
+          aws_dynamodb_table.hourstats_state.arn,
+          "${aws_dynamodb_table.hourstats_state.arn}/index/*",
+          aws_dynamodb_table.sentiment_history.arn,
+          aws_dynamodb_table.daily_sentiment.arn,
+          aws_dynamodb_table.self_stats.arn
+        ]
+      }
+    ]
+  })
+}
+
+# Attach read policy to the API role
+resource "aws_iam_role_policy_attachment" "api_read_access" {
+  role       = aws_iam_role.api_role.name
+  policy_arn = aws_iam_policy.api_read_access.arn
+}
+
+# Attach CloudWatch Logs access to the API role
+resource "aws_iam_role_policy_attachment" "api_logs" {
+  role       = aws_iam_role.api_role.name
+  policy_arn = "arn:aws:iam::aws:policy/service-role/AWSLambdaBasicExecutionRole"
+}
+
+# Allow the API to send trace segments to X-Ray
+resource "aws_iam_role_policy_attachment" "api_xray_write" {
+  role       = aws_iam_role.api_role.name
+  policy_arn = "arn:aws:iam::aws:policy/AWSXRayDaemonWriteAccess"
+}
+
+# API Lambda Function
+resource "aws_lambda_function" "hourstats_api" {
+  filename         = "lambda-api.zip"
+  function_name    = "hourstats-api"
+  role            = aws_iam_role.api_role.arn
+  handler         = "bootstrap"
+  source_code_hash = filebase64sha256("lambda-api.zip")
+  runtime         = "provided.al2023"
+  timeout         = 29  # API Gateway's integration limit
+  memory_size     = 256
+
+  tracing_config {
+    mode = "Active"
+  }
+
+  tags = {
+    Name        = "hourstats-api"
+    Environment = "production"
+  }
+}
+
+# HTTP API in front of the Lambda
+resource "aws_apigatewayv2_api" "hourstats_api" {
+  name          = "hourstats-api"
+  protocol_type = "HTTP"
+
+  cors_configuration {
+    allow_origins = ["*"]
+    allow_methods = ["GET"]
+    max_age       = 3600
+  }
+
+  tags = {
+    Name        = "hourstats-api"
+    Environment = "production"
+  }
+}
+
+resource "aws_apigatewayv2_integration" "hourstats_api" {
+  api_id                 = aws_apigatewayv2_api.hourstats_api.id
+  integration_type       = "AWS_PROXY"
+  integration_uri        = aws_lambda_function.hourstats_api.invoke_arn
+  payload_format_version = "2.0"
+}
+
+resource "aws_apigatewayv2_route" "hourstats_api" {
+  api_id    = aws_apigatewayv2_api.hourstats_api.id
+  route_key = "GET /{proxy+}"
+  target    = "integrations/${aws_apigatewayv2_integration.hourstats_api.id}"
+}
+
+# Default stage, throttled so a busy dashboard cannot run up DynamoDB reads
+resource "aws_apigatewayv2_stage" "hourstats_api" {
+  api_id      = aws_apigatewayv2_api.hourstats_api.id
+  name        = "$default"
+  auto_deploy = true
+
+  default_route_settings {
+    throttling_burst_limit = 20
+    throttling_rate_limit  = 10
+  }
+
+  tags = {
+    Name        = "hourstats-api"
+    Environment = "production"
+  }
+}
+
+# Permission for API Gateway to invoke the API Lambda
+resource "aws_lambda_permission" "allow_api_gateway" {
+  statement_id  = "AllowExecutionFromAPIGateway"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.hourstats_api.function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_apigatewayv2_api.hourstats_api.execution_arn}/*/*"
+}
+
+output "api_endpoint" {
+  description = "Base URL of the read-only query API"
+  value       = aws_apigatewayv2_api.hourstats_api.api_endpoint
+}
@@ -0,0 +1,145 @@
+# S3 bucket for nightly DynamoDB backups
+resource "aws_s3_bucket" "backups" {
+  bucket = "hourstats-backups"
+
+  tags = {
+    Name        = "HourStats Backups"
+    Environment = "production"
+    Purpose     = "dynamodb-backups"
+  }
+}
+
+# S3 Bucket Server-Side Encryption
+resource "aws_s3_bucket_server_side_encryption_configuration" "backups" {
+  bucket = aws_s3_bucket.backups.id
+
+  rule {
+    apply_server_side_encryption_by_default {
+      sse_algorithm = "AES256"
+    }
+  }
+}
+
+# S3 Bucket Public Access Block
+resource "aws_s3_bucket_public_access_block" "backups" {
+  bucket = aws_s3_bucket.backups.id
+
+  block_public_acls       = true
+  block_public_policy     = true
+  ignore_public_acls      = true
+  restrict_public_buckets = true
+}
+
+# IAM policy for the backup Lambda to read tables and manage backup objects
+resource "aws_iam_policy" "backup_access" {
+  name        = "HourStatsBackupAccess"
+  description = "Policy for the HourStats backup Lambda to snapshot tables to S3"
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect = "Allow"
+        Action = [
+          "dynamodb:DescribeTable",
+          "dynamodb:Scan"
+        ]
+        Resource = [
+          aws_dynamodb_table.hourstats_state.arn,
+          aws_dynamodb_table.sentiment_history.arn,
+          aws_dynamodb_table.daily_sentiment.arn,
+          aws_dynamodb_table.blocklist.arn
+        ]
+      },
+      {
+        Effect = "Allow"
+        Action = [
+          "s3:ListBucket"
+        ]
+        Resource = aws_s3_bucket.backups.arn
+      },
+      {
+        Effect = "Allow"
+        Action = [
+          "s3:GetObject",
+          "s3:PutObject",
+          "s3:DeleteObject"
+        ]
+        Resource = "${aws_s3_bucket.backups.arn}/*"
+      }
+    ]
+  })
+}
+
+# Attach backup policy to role
+resource "aws_iam_role_policy_attachment" "backup_policy" {
+  role       = aws_iam_role.lambda_role.name
+  policy_arn = aws_iam_policy.backup_access.arn
+}
+
+# Backup Lambda Function
+resource "aws_lambda_function" "hourstats_backup" {
+  filename         = "lambda-backup.zip"
+  function_name    = "hourstats-backup"
+  role            = aws_iam_role.lambda_role.arn
+  handler         = "bootstrap"
+  source_code_hash = filebase64sha256("lambda-backup.zip")
+  runtime         = "provided.al2023"
+  timeout         = 900  # 15 minutes
+  memory_size     = 512
+
+  tracing_config {
+    mode = "Active"
+  }
+
+  ephemeral_storage {
+    size = 2048  # Snapshots are staged in /tmp before upload
+  }
+
+  environment {
+    variables = {
+      BACKUP_BUCKET      = aws_s3_bucket.backups.bucket
+      BACKUP_PREFIX      = "hourstats-backup"
+      BACKUP_KEEP_DAILY  = "7"
+      BACKUP_KEEP_WEEKLY = "4"
+    }
+  }
+
+  tags = {
+    Name        = "hourstats-backup"
+    Environment = "production"
+  }
+}
+
+# EventBridge Rule for Backups (runs nightly at 3:00 AM UTC, after daily aggregation)
+resource "aws_cloudwatch_event_rule" "backup_schedule" {
+  name                = "hourstats-backup-schedule"
+  description         = "Trigger nightly DynamoDB backup at 3:00 AM UTC"
+  schedule_expression = "cron(0 3 * * ? *)"
+
+  tags = {
+    Name        = "hourstats-backup-schedule"
+    Environment = "production"
+  }
+}
+
+# EventBridge Target for Backups
+resource "aws_cloudwatch_event_target" "backup_target" {
+  rule      = aws_cloudwatch_event_rule.backup_schedule.name
+  target_id = "BackupTarget"
+  arn       = aws_lambda_function.hourstats_backup.arn
+
+  input = jsonencode({
+    source = "aws.events"
+    time   = "$.time"
+  })
+}
+
+# Permission for EventBridge to invoke Backup Lambda
+resource "aws_lambda_permission" "allow_eventbridge_backup" {
+  statement_id  = "AllowExecutionFromEventBridgeBackup"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.hourstats_backup.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.backup_schedule.arn
+}
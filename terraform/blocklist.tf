@@ -0,0 +1,47 @@
+# DynamoDB table for blocked (and allowed) authors, link domains and keywords
+resource "aws_dynamodb_table" "blocklist" {
+  name         = "hourstats-blocklist"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "kind"
+  range_key    = "value"
+
+  attribute {
+    name = "kind"
+    type = "S"
+  }
+
+  attribute {
+    name = "value"
+    type = "S"
+  }
+
+  tags = {
+    Name        = "HourStats Blocklist"
+    Environment = "production"
+  }
+}
+
+# IAM policy for the processor to read the blocklist; entries are managed with cmd/blocklist
+resource "aws_iam_policy" "blocklist_access" {
+  name        = "HourStatsBlocklistAccess"
+  description = "Policy for HourStats Lambda functions to read the blocklist"
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect = "Allow"
+        Action = [
+          "dynamodb:Scan"
+        ]
+        Resource = aws_dynamodb_table.blocklist.arn
+      }
+    ]
+  })
+}
+
+# Attach blocklist policy to role
+resource "aws_iam_role_policy_attachment" "blocklist_policy" {
+  role       = aws_iam_role.lambda_role.name
+  policy_arn = aws_iam_policy.blocklist_access.arn
+}
@@ -0,0 +1,145 @@
+# Bluesky credentials in Secrets Manager. When use_bluesky_secret is set, the Lambdas read
+# the handle and app password from this secret instead of the SSM parameters.
+variable "use_bluesky_secret" {
+  description = "Read the Bluesky credentials from the hourstats/bluesky secret instead of the SSM parameters"
+  type        = bool
+  default     = false
+}
+
+locals {
+  bluesky_secret_id = var.use_bluesky_secret ? aws_secretsmanager_secret.bluesky.name : ""
+}
+
+# Secret holding {"handle": "...", "password": "..."}
+resource "aws_secretsmanager_secret" "bluesky" {
+  name        = "hourstats/bluesky"
+  description = "Bluesky handle and app password for HourStats"
+
+  tags = {
+    Name        = "HourStats Bluesky Credentials"
+    Environment = "production"
+  }
+}
+
+# IAM policy for the Lambdas to read the secret
+resource "aws_iam_policy" "bluesky_secret_access" {
+  name        = "HourStatsBlueskySecretAccess"
+  description = "Policy for HourStats Lambda functions to read the Bluesky credentials secret"
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect = "Allow"
+        Action = [
+          "secretsmanager:GetSecretValue"
+        ]
+        Resource = aws_secretsmanager_secret.bluesky.arn
+      }
+    ]
+  })
+}
+
+# Attach secret policy to role
+resource "aws_iam_role_policy_attachment" "bluesky_secret_policy" {
+  role       = aws_iam_role.lambda_role.name
+  policy_arn = aws_iam_policy.bluesky_secret_access.arn
+}
+
+# The staging Lambdas are given the same secret, so they can read it too
+resource "aws_iam_role_policy_attachment" "staging_bluesky_secret_policy" {
+  role       = aws_iam_role.staging_lambda_role.name
+  policy_arn = aws_iam_policy.bluesky_secret_access.arn
+}
+
+# The rotation Lambda gets its own role so only it can promote a secret version
+resource "aws_iam_role" "credential_rotation_role" {
+  name = "${var.function_name}-credential-rotation-role"
+
+  assume_role_policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Action = "sts:AssumeRole"
+        Effect = "Allow"
+        Principal = {
+          Service = "lambda.amazonaws.com"
+        }
+      }
+    ]
+  })
+
+  tags = {
+    Name        = "${var.function_name}-credential-rotation-role"
+    Environment = "production"
+  }
+}
+
+# IAM policy for the rotation Lambda to rotate the secret
+resource "aws_iam_policy" "bluesky_secret_rotation" {
+  name        = "HourStatsBlueskySecretRotation"
+  description = "Policy for the HourStats rotation Lambda to rotate the Bluesky credentials secret"
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect = "Allow"
+        Action = [
+          "secretsmanager:GetSecretValue",
+          "secretsmanager:DescribeSecret",
+          "secretsmanager:UpdateSecretVersionStage"
+        ]
+        Resource = aws_secretsmanager_secret.bluesky.arn
+      }
+    ]
+  })
+}
+
+# Attach rotation policy to the rotation role
+resource "aws_iam_role_policy_attachment" "bluesky_secret_rotation" {
+  role       = aws_iam_role.credential_rotation_role.name
+  policy_arn = aws_iam_policy.bluesky_secret_rotation.arn
+}
+
+# Attach CloudWatch Logs access to the rotation role
+resource "aws_iam_role_policy_attachment" "credential_rotation_logs" {
+  role       = aws_iam_role.credential_rotation_role.name
+  policy_arn = "arn:aws:iam::aws:policy/service-role/AWSLambdaBasicExecutionRole"
+}
+
+# Allow the rotation Lambda to send trace segments to X-Ray
+resource "aws_iam_role_policy_attachment" "credential_rotation_xray_write" {
+  role       = aws_iam_role.credential_rotation_role.name
+  policy_arn = "arn:aws:iam::aws:policy/AWSXRayDaemonWriteAccess"
+}
+
+# Rotation Lambda: makes a staged app password current once Bluesky accepts it
+resource "aws_lambda_function" "hourstats_credential_rotation" {
+  filename         = "lambda-credential-rotation.zip"
+  function_name    = "hourstats-credential-rotation"
+  role            = aws_iam_role.credential_rotation_role.arn
+  handler         = "bootstrap"
+  source_code_hash = filebase64sha256("lambda-credential-rotation.zip")
+  runtime         = "provided.al2023"
+  timeout         = 60
+  memory_size     = 128
+
+  tracing_config {
+    mode = "Active"
+  }
+
+  tags = {
+    Name        = "hourstats-credential-rotation"
+    Environment = "production"
+  }
+}
+
+# Permission for Secrets Manager to invoke the rotation Lambda
+resource "aws_lambda_permission" "allow_secretsmanager_rotation" {
+  statement_id  = "AllowExecutionFromSecretsManager"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.hourstats_credential_rotation.function_name
+  principal     = "secretsmanager.amazonaws.com"
+  source_arn    = aws_secretsmanager_secret.bluesky.arn
+}
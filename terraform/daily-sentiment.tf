@@ -74,6 +74,10 @@ resource "aws_lambda_function" "hourstats_daily_aggregator" {
   timeout         = 300  # 5 minutes
   memory_size     = 256
 
+  tracing_config {
+    mode = "Active"
+  }
+
   environment {
     variables = {
       DAILY_SENTIMENT_TABLE = aws_dynamodb_table.daily_sentiment.name
@@ -98,10 +102,15 @@ resource "aws_lambda_function" "hourstats_yearly_poster" {
   timeout         = 600  # 10 minutes
   memory_size     = 512
 
+  tracing_config {
+    mode = "Active"
+  }
+
   environment {
     variables = {
       DAILY_SENTIMENT_TABLE = aws_dynamodb_table.daily_sentiment.name
       SENTIMENT_HISTORY_TABLE = aws_dynamodb_table.sentiment_history.name
+      BLUESKY_SECRET_ID = local.bluesky_secret_id
     }
   }
 
@@ -159,6 +168,177 @@ resource "aws_cloudwatch_event_target" "yearly_posting_target" {
   })
 }
 
+# EventBridge Rule for the 30 day sentiment chart (1st of the month at 1:30 AM UTC)
+resource "aws_cloudwatch_event_rule" "monthly_chart_schedule" {
+  name                = "hourstats-monthly-chart-schedule"
+  description         = "Trigger the 30 day sentiment chart on the 1st at 1:30 AM UTC"
+  schedule_expression = "cron(30 1 1 * ? *)"
+
+  tags = {
+    Name        = "hourstats-monthly-chart-schedule"
+    Environment = "production"
+  }
+}
+
+# EventBridge Target for the 30 day chart, posted by the yearly poster
+resource "aws_cloudwatch_event_target" "monthly_chart_target" {
+  rule      = aws_cloudwatch_event_rule.monthly_chart_schedule.name
+  target_id = "MonthlyChartTarget"
+  arn       = aws_lambda_function.hourstats_yearly_poster.arn
+
+  input = jsonencode({
+    source = "aws.events"
+    action = "monthly_chart"
+  })
+}
+
+# EventBridge Rule for the quarterly sentiment chart (1st of Jan, Apr, Jul and Oct at 1:45 AM UTC)
+resource "aws_cloudwatch_event_rule" "quarterly_chart_schedule" {
+  name                = "hourstats-quarterly-chart-schedule"
+  description         = "Trigger the previous quarter's sentiment chart on the 1st of each quarter at 1:45 AM UTC"
+  schedule_expression = "cron(45 1 1 1,4,7,10 ? *)"
+
+  tags = {
+    Name        = "hourstats-quarterly-chart-schedule"
+    Environment = "production"
+  }
+}
+
+# EventBridge Target for the quarterly chart, posted by the yearly poster
+resource "aws_cloudwatch_event_target" "quarterly_chart_target" {
+  rule      = aws_cloudwatch_event_rule.quarterly_chart_schedule.name
+  target_id = "QuarterlyChartTarget"
+  arn       = aws_lambda_function.hourstats_yearly_poster.arn
+
+  input = jsonencode({
+    source = "aws.events"
+    action = "quarterly_chart"
+  })
+}
+
+# EventBridge Rule for the monthly transparency report (1st of the month at 2:00 AM UTC)
+resource "aws_cloudwatch_event_rule" "transparency_report_schedule" {
+  name                = "hourstats-transparency-report-schedule"
+  description         = "Trigger the monthly transparency report on the 1st at 2:00 AM UTC"
+  schedule_expression = "cron(0 2 1 * ? *)"
+
+  tags = {
+    Name        = "hourstats-transparency-report-schedule"
+    Environment = "production"
+  }
+}
+
+# EventBridge Target for the transparency report, posted by the yearly poster
+resource "aws_cloudwatch_event_target" "transparency_report_target" {
+  rule      = aws_cloudwatch_event_rule.transparency_report_schedule.name
+  target_id = "TransparencyReportTarget"
+  arn       = aws_lambda_function.hourstats_yearly_poster.arn
+
+  input = jsonencode({
+    source = "aws.events"
+    action = "transparency_report"
+  })
+}
+
+# EventBridge Rule for the weekly topics report (Mondays at 2:30 AM UTC)
+resource "aws_cloudwatch_event_rule" "topic_report_schedule" {
+  name                = "hourstats-topic-report-schedule"
+  description         = "Trigger the weekly topics report on Mondays at 2:30 AM UTC"
+  schedule_expression = "cron(30 2 ? * MON *)"
+
+  tags = {
+    Name        = "hourstats-topic-report-schedule"
+    Environment = "production"
+  }
+}
+
+# EventBridge Target for the topics report, posted by the yearly poster
+resource "aws_cloudwatch_event_target" "topic_report_target" {
+  rule      = aws_cloudwatch_event_rule.topic_report_schedule.name
+  target_id = "TopicReportTarget"
+  arn       = aws_lambda_function.hourstats_yearly_poster.arn
+
+  input = jsonencode({
+    source = "aws.events"
+    action = "topic_report"
+  })
+}
+
+# EventBridge Rule for the optional weekly most shared domains report (Mondays at 2:45 AM UTC)
+resource "aws_cloudwatch_event_rule" "domain_report_schedule" {
+  name                = "hourstats-domain-report-schedule"
+  description         = "Trigger the weekly most shared domains report on Mondays at 2:45 AM UTC"
+  schedule_expression = "cron(45 2 ? * MON *)"
+  state               = var.domain_report_enabled ? "ENABLED" : "DISABLED"
+
+  tags = {
+    Name        = "hourstats-domain-report-schedule"
+    Environment = "production"
+  }
+}
+
+# EventBridge Target for the domains report, posted by the yearly poster
+resource "aws_cloudwatch_event_target" "domain_report_target" {
+  rule      = aws_cloudwatch_event_rule.domain_report_schedule.name
+  target_id = "DomainReportTarget"
+  arn       = aws_lambda_function.hourstats_yearly_poster.arn
+
+  input = jsonencode({
+    source = "aws.events"
+    action = "domain_report"
+  })
+}
+
+# EventBridge Rule for the optional day-over-day post, checked hourly; the yearly poster
+# only posts in the hour of /hourstats/settings/daily_delta's local time
+resource "aws_cloudwatch_event_rule" "daily_delta_schedule" {
+  name                = "hourstats-daily-delta-schedule"
+  description         = "Check hourly whether the day-over-day post is due"
+  schedule_expression = "cron(10 * * * ? *)"
+  state               = var.daily_delta_enabled ? "ENABLED" : "DISABLED"
+
+  tags = {
+    Name        = "hourstats-daily-delta-schedule"
+    Environment = "production"
+  }
+}
+
+# EventBridge Target for the day-over-day post, posted by the yearly poster
+resource "aws_cloudwatch_event_target" "daily_delta_target" {
+  rule      = aws_cloudwatch_event_rule.daily_delta_schedule.name
+  target_id = "DailyDeltaTarget"
+  arn       = aws_lambda_function.hourstats_yearly_poster.arn
+
+  input = jsonencode({
+    source = "aws.events"
+    action = "daily_delta"
+  })
+}
+
+# EventBridge Rule for the weekly post format experiment report (Mondays at 3:00 AM UTC)
+resource "aws_cloudwatch_event_rule" "experiment_report_schedule" {
+  name                = "hourstats-experiment-report-schedule"
+  description         = "Trigger the weekly post format experiment report on Mondays at 3:00 AM UTC"
+  schedule_expression = "cron(0 3 ? * MON *)"
+
+  tags = {
+    Name        = "hourstats-experiment-report-schedule"
+    Environment = "production"
+  }
+}
+
+# EventBridge Target for the experiment report, compared by the yearly poster
+resource "aws_cloudwatch_event_target" "experiment_report_target" {
+  rule      = aws_cloudwatch_event_rule.experiment_report_schedule.name
+  target_id = "ExperimentReportTarget"
+  arn       = aws_lambda_function.hourstats_yearly_poster.arn
+
+  input = jsonencode({
+    source = "aws.events"
+    action = "experiment_report"
+  })
+}
+
 # Permission for EventBridge to invoke Daily Aggregator Lambda
 resource "aws_lambda_permission" "allow_eventbridge_daily_aggregator" {
   statement_id  = "AllowExecutionFromEventBridgeDailyAggregator"
@@ -176,3 +356,66 @@ resource "aws_lambda_permission" "allow_eventbridge_yearly_poster" {
   principal     = "events.amazonaws.com"
   source_arn    = aws_cloudwatch_event_rule.yearly_posting_schedule.arn
 }
+
+# Permission for EventBridge to invoke Yearly Poster Lambda for the 30 day chart
+resource "aws_lambda_permission" "allow_eventbridge_monthly_chart" {
+  statement_id  = "AllowExecutionFromEventBridgeMonthlyChart"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.hourstats_yearly_poster.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.monthly_chart_schedule.arn
+}
+
+# Permission for EventBridge to invoke Yearly Poster Lambda for the quarterly chart
+resource "aws_lambda_permission" "allow_eventbridge_quarterly_chart" {
+  statement_id  = "AllowExecutionFromEventBridgeQuarterlyChart"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.hourstats_yearly_poster.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.quarterly_chart_schedule.arn
+}
+
+# Permission for EventBridge to invoke Yearly Poster Lambda for the transparency report
+resource "aws_lambda_permission" "allow_eventbridge_transparency_report" {
+  statement_id  = "AllowExecutionFromEventBridgeTransparencyReport"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.hourstats_yearly_poster.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.transparency_report_schedule.arn
+}
+
+# Permission for EventBridge to invoke Yearly Poster Lambda for the topics report
+resource "aws_lambda_permission" "allow_eventbridge_topic_report" {
+  statement_id  = "AllowExecutionFromEventBridgeTopicReport"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.hourstats_yearly_poster.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.topic_report_schedule.arn
+}
+
+# Permission for EventBridge to invoke Yearly Poster Lambda for the domains report
+resource "aws_lambda_permission" "allow_eventbridge_domain_report" {
+  statement_id  = "AllowExecutionFromEventBridgeDomainReport"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.hourstats_yearly_poster.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.domain_report_schedule.arn
+}
+
+# Permission for EventBridge to invoke Yearly Poster Lambda for the day-over-day post
+resource "aws_lambda_permission" "allow_eventbridge_daily_delta" {
+  statement_id  = "AllowExecutionFromEventBridgeDailyDelta"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.hourstats_yearly_poster.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.daily_delta_schedule.arn
+}
+
+# Permission for EventBridge to invoke Yearly Poster Lambda for the experiment report
+resource "aws_lambda_permission" "allow_eventbridge_experiment_report" {
+  statement_id  = "AllowExecutionFromEventBridgeExperimentReport"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.hourstats_yearly_poster.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.experiment_report_schedule.arn
+}
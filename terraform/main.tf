@@ -32,6 +32,33 @@ variable "schedule_expression" {
   default     = "rate(30 minutes)"
 }
 
+variable "analysis_interval_minutes" {
+  description = "Minutes each run analyzes, from 1 to 1440; over an hour it must be whole hours. Usually matches schedule_expression."
+  type        = number
+  default     = 30
+}
+
+variable "channels" {
+  description = "Extra channels to run on their own schedules, keyed by channel ID from /hourstats/settings/channels"
+  type = map(object({
+    schedule_expression       = string
+    analysis_interval_minutes = number
+  }))
+  default = {}
+}
+
+variable "domain_report_enabled" {
+  description = "Post the weekly most shared link domains report"
+  type        = bool
+  default     = false
+}
+
+variable "daily_delta_enabled" {
+  description = "Post yesterday's sentiment and post volume against the day before each morning"
+  type        = bool
+  default     = false
+}
+
 # Data sources
 data "aws_caller_identity" "current" {}
 
@@ -82,7 +109,9 @@ resource "aws_iam_policy" "lambda_policy" {
           "dynamodb:PutItem",
           "dynamodb:Query",
           "dynamodb:UpdateItem",
-          "dynamodb:BatchWriteItem"
+          "dynamodb:BatchWriteItem",
+          "dynamodb:BatchGetItem",
+          "dynamodb:Scan"
         ]
         Resource = aws_dynamodb_table.hourstats_state.arn
       },
@@ -139,6 +168,14 @@ resource "aws_iam_policy" "lambda_policy" {
           "arn:aws:ssm:${var.aws_region}:${data.aws_caller_identity.current.account_id}:parameter/hourstats/*"
         ]
       },
+      {
+        # Reads text in images when /hourstats/settings/image_text is "ocr"
+        Effect = "Allow"
+        Action = [
+          "textract:DetectDocumentText"
+        ]
+        Resource = "*"
+      },
       {
         Effect = "Allow"
         Action = [
@@ -180,6 +217,12 @@ resource "aws_iam_role_policy_attachment" "daily_sentiment_policy" {
   policy_arn = aws_iam_policy.daily_sentiment_access.arn
 }
 
+# Allow the lambdas to send trace segments to X-Ray
+resource "aws_iam_role_policy_attachment" "xray_write" {
+  role       = aws_iam_role.lambda_role.name
+  policy_arn = "arn:aws:iam::aws:policy/AWSXRayDaemonWriteAccess"
+}
+
 # Note: S3 sparkline policy attachment removed - using embedded images instead
 
 # DynamoDB Table for Multi-Lambda State Management
@@ -210,6 +253,16 @@ resource "aws_dynamodb_table" "hourstats_state" {
     type = "S"
   }
 
+  attribute {
+    name = "runIndex"
+    type = "S"
+  }
+
+  attribute {
+    name = "engagementScore"
+    type = "N"
+  }
+
   # Global Secondary Index for querying by status
   global_secondary_index {
     name            = "status-index"
@@ -235,6 +288,24 @@ resource "aws_dynamodb_table" "hourstats_state" {
     projection_type = "ALL"
   }
 
+  # Global Secondary Index for listing runs by creation time with a single Query;
+  # every run state is written with runIndex = "run"
+  global_secondary_index {
+    name            = "created-index"
+    hash_key        = "runIndex"
+    range_key       = "createdAt"
+    projection_type = "ALL"
+  }
+
+  # Sparse Global Secondary Index over individually stored analyzed posts, for
+  # engagement range queries within a run
+  global_secondary_index {
+    name            = "engagement-index"
+    hash_key        = "runId"
+    range_key       = "engagementScore"
+    projection_type = "ALL"
+  }
+
   ttl {
     attribute_name = "ttl"
     enabled        = true
@@ -257,6 +328,10 @@ resource "aws_lambda_function" "hourstats_orchestrator" {
   timeout         = 900  # 15 minutes (AWS Lambda maximum)
   memory_size     = 128
 
+  tracing_config {
+    mode = "Active"
+  }
+
   environment {
     variables = {
       DYNAMODB_TABLE = aws_dynamodb_table.hourstats_state.name
@@ -280,9 +355,14 @@ resource "aws_lambda_function" "hourstats_fetcher" {
   timeout         = 900  # 15 minutes (AWS Lambda maximum)
   memory_size     = 128
 
+  tracing_config {
+    mode = "Active"
+  }
+
   environment {
     variables = {
-      DYNAMODB_TABLE = aws_dynamodb_table.hourstats_state.name
+      DYNAMODB_TABLE    = aws_dynamodb_table.hourstats_state.name
+      BLUESKY_SECRET_ID = local.bluesky_secret_id
     }
   }
 
@@ -303,9 +383,15 @@ resource "aws_lambda_function" "hourstats_processor" {
   timeout         = 300  # 5 minutes
   memory_size     = 128
 
+  tracing_config {
+    mode = "Active"
+  }
+
   environment {
     variables = {
-      DYNAMODB_TABLE = aws_dynamodb_table.hourstats_state.name
+      DYNAMODB_TABLE    = aws_dynamodb_table.hourstats_state.name
+      REPORTS_BUCKET    = aws_s3_bucket.reports.bucket
+      BLUESKY_SECRET_ID = local.bluesky_secret_id
     }
   }
 
@@ -326,10 +412,15 @@ resource "aws_lambda_function" "hourstats_sparkline_poster" {
   timeout         = 300  # 5 minutes
   memory_size     = 256
 
+  tracing_config {
+    mode = "Active"
+  }
+
   environment {
     variables = {
       DYNAMODB_TABLE = aws_dynamodb_table.hourstats_state.name
       SENTIMENT_HISTORY_TABLE = aws_dynamodb_table.sentiment_history.name
+      BLUESKY_SECRET_ID = local.bluesky_secret_id
     }
   }
 
@@ -360,7 +451,8 @@ resource "aws_cloudwatch_event_target" "hourstats_target" {
   input = jsonencode({
     source                  = "aws.events"
     time                    = "$.time"
-    analysisIntervalMinutes = 30
+    analysisIntervalMinutes = var.analysis_interval_minutes
+    adaptiveInterval        = true
   })
 }
 
@@ -373,6 +465,45 @@ resource "aws_lambda_permission" "allow_eventbridge_orchestrator" {
   source_arn    = aws_cloudwatch_event_rule.hourstats_schedule.arn
 }
 
+# EventBridge Rules for extra channels, each running the orchestrator with its channel ID
+resource "aws_cloudwatch_event_rule" "channel_schedule" {
+  for_each = var.channels
+
+  name                = "${var.function_name}-channel-${each.key}"
+  description         = "Trigger ${var.function_name} channel ${each.key} on schedule"
+  schedule_expression = each.value.schedule_expression
+
+  tags = {
+    Name        = "${var.function_name}-channel-${each.key}"
+    Environment = "production"
+  }
+}
+
+resource "aws_cloudwatch_event_target" "channel_target" {
+  for_each = var.channels
+
+  rule      = aws_cloudwatch_event_rule.channel_schedule[each.key].name
+  target_id = "HourStatsChannel-${each.key}"
+  arn       = aws_lambda_function.hourstats_orchestrator.arn
+
+  input = jsonencode({
+    source                  = "aws.events"
+    time                    = "$.time"
+    analysisIntervalMinutes = each.value.analysis_interval_minutes
+    channelId               = each.key
+  })
+}
+
+resource "aws_lambda_permission" "allow_eventbridge_channel" {
+  for_each = var.channels
+
+  statement_id  = "AllowExecutionFromEventBridgeChannel-${each.key}"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.hourstats_orchestrator.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.channel_schedule[each.key].arn
+}
+
 # SSM Parameters
 resource "aws_ssm_parameter" "bluesky_handle" {
   name  = "/hourstats/bluesky/handle"
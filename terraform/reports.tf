@@ -0,0 +1,57 @@
+# S3 bucket for per-run JSON reports, written by the processor under
+# reports/YYYY/MM/DD/<runId>.json
+resource "aws_s3_bucket" "reports" {
+  bucket = "hourstats-reports"
+
+  tags = {
+    Name        = "HourStats Run Reports"
+    Environment = "production"
+    Purpose     = "run-reports"
+  }
+}
+
+# S3 Bucket Server-Side Encryption
+resource "aws_s3_bucket_server_side_encryption_configuration" "reports" {
+  bucket = aws_s3_bucket.reports.id
+
+  rule {
+    apply_server_side_encryption_by_default {
+      sse_algorithm = "AES256"
+    }
+  }
+}
+
+# S3 Bucket Public Access Block
+resource "aws_s3_bucket_public_access_block" "reports" {
+  bucket = aws_s3_bucket.reports.id
+
+  block_public_acls       = true
+  block_public_policy     = true
+  ignore_public_acls      = true
+  restrict_public_buckets = true
+}
+
+# IAM policy for the processor to write run reports
+resource "aws_iam_policy" "reports_access" {
+  name        = "HourStatsReportsAccess"
+  description = "Policy for the HourStats processor to write run reports to S3"
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect = "Allow"
+        Action = [
+          "s3:PutObject"
+        ]
+        Resource = "${aws_s3_bucket.reports.arn}/reports/*"
+      }
+    ]
+  })
+}
+
+# Attach reports policy to role
+resource "aws_iam_role_policy_attachment" "reports_policy" {
+  role       = aws_iam_role.lambda_role.name
+  policy_arn = aws_iam_policy.reports_access.arn
+}
@@ -0,0 +1,44 @@
+# DynamoDB table for archived run summaries (used when the retention policy's
+# archive.table is set to "hourstats-run-archive"); items never expire
+resource "aws_dynamodb_table" "run_archive" {
+  name         = "hourstats-run-archive"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "runId"
+
+  attribute {
+    name = "runId"
+    type = "S"
+  }
+
+  tags = {
+    Name        = "HourStats Run Archive"
+    Environment = "production"
+  }
+}
+
+# IAM policy for the daily aggregator to archive run summaries
+resource "aws_iam_policy" "run_archive_access" {
+  name        = "HourStatsRunArchiveAccess"
+  description = "Policy for HourStats Lambda functions to archive run summaries"
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect = "Allow"
+        Action = [
+          "dynamodb:GetItem",
+          "dynamodb:PutItem",
+          "dynamodb:Query"
+        ]
+        Resource = aws_dynamodb_table.run_archive.arn
+      }
+    ]
+  })
+}
+
+# Attach run archive policy to role
+resource "aws_iam_role_policy_attachment" "run_archive_policy" {
+  role       = aws_iam_role.lambda_role.name
+  policy_arn = aws_iam_policy.run_archive_access.arn
+}
@@ -0,0 +1,114 @@
+# DynamoDB table for engagement on the bot's own posts
+resource "aws_dynamodb_table" "self_stats" {
+  name           = "hourstats-self-stats"
+  billing_mode   = "PAY_PER_REQUEST"
+  hash_key       = "month"
+  range_key      = "postKey"
+
+  attribute {
+    name = "month"
+    type = "S"
+  }
+
+  attribute {
+    name = "postKey"
+    type = "S"
+  }
+
+  ttl {
+    attribute_name = "ttl"
+    enabled        = true
+  }
+
+  tags = {
+    Name        = "HourStats Self Stats"
+    Environment = "production"
+  }
+}
+
+# IAM policy for Lambda functions to access the self stats table
+resource "aws_iam_policy" "self_stats_access" {
+  name        = "HourStatsSelfStatsAccess"
+  description = "Policy for HourStats Lambda functions to access the self stats table"
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect = "Allow"
+        Action = [
+          "dynamodb:GetItem",
+          "dynamodb:PutItem",
+          "dynamodb:Query"
+        ]
+        Resource = aws_dynamodb_table.self_stats.arn
+      }
+    ]
+  })
+}
+
+# Attach self stats policy to role
+resource "aws_iam_role_policy_attachment" "self_stats_policy" {
+  role       = aws_iam_role.lambda_role.name
+  policy_arn = aws_iam_policy.self_stats_access.arn
+}
+
+# Self Stats Lambda Function
+resource "aws_lambda_function" "hourstats_selfstats" {
+  filename         = "lambda-selfstats.zip"
+  function_name    = "hourstats-selfstats"
+  role            = aws_iam_role.lambda_role.arn
+  handler         = "bootstrap"
+  source_code_hash = filebase64sha256("lambda-selfstats.zip")
+  runtime         = "provided.al2023"
+  timeout         = 300  # 5 minutes
+  memory_size     = 256
+
+  tracing_config {
+    mode = "Active"
+  }
+
+  environment {
+    variables = {
+      BLUESKY_SECRET_ID = local.bluesky_secret_id
+    }
+  }
+
+  tags = {
+    Name        = "hourstats-selfstats"
+    Environment = "production"
+  }
+}
+
+# EventBridge Rule for Self Stats (runs daily at 4:00 AM UTC, after backups)
+resource "aws_cloudwatch_event_rule" "selfstats_schedule" {
+  name                = "hourstats-selfstats-schedule"
+  description         = "Trigger daily measurement of engagement on our own posts at 4:00 AM UTC"
+  schedule_expression = "cron(0 4 * * ? *)"
+
+  tags = {
+    Name        = "hourstats-selfstats-schedule"
+    Environment = "production"
+  }
+}
+
+# EventBridge Target for Self Stats
+resource "aws_cloudwatch_event_target" "selfstats_target" {
+  rule      = aws_cloudwatch_event_rule.selfstats_schedule.name
+  target_id = "SelfStatsTarget"
+  arn       = aws_lambda_function.hourstats_selfstats.arn
+
+  input = jsonencode({
+    source = "aws.events"
+    time   = "$.time"
+  })
+}
+
+# Permission for EventBridge to invoke Self Stats Lambda
+resource "aws_lambda_permission" "allow_eventbridge_selfstats" {
+  statement_id  = "AllowExecutionFromEventBridgeSelfStats"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.hourstats_selfstats.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.selfstats_schedule.arn
+}
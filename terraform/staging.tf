@@ -0,0 +1,371 @@
+# Staging deployment: a second orchestrator, fetcher, processor and sparkline poster that
+# read production's data without changing it and post from the shadow account
+# (/hourstats/shadow/handle and /hourstats/shadow/password), so format and chart changes
+# can be previewed live before they reach the main account. HOURSTATS_STAGE makes every
+# run a staging dry run whatever /hourstats/settings/dry_run says; run state goes to a
+# table of its own, and the role can only read production's tables.
+variable "staging_enabled" {
+  description = "Run the staging deployment on schedule_expression, posting from the shadow account"
+  type        = bool
+  default     = false
+}
+
+locals {
+  staging_environment = {
+    HOURSTATS_STAGE           = "staging"
+    DYNAMODB_TABLE            = aws_dynamodb_table.hourstats_staging_state.name
+    FETCHER_FUNCTION          = "hourstats-staging-fetcher"
+    PROCESSOR_FUNCTION        = "hourstats-staging-processor"
+    SPARKLINE_POSTER_FUNCTION = "hourstats-staging-sparkline-poster"
+    METRICS_NAMESPACE         = "HourStatsStaging"
+    BLUESKY_SECRET_ID         = local.bluesky_secret_id
+  }
+}
+
+# Run state for staging runs, with the same keys and indexes as hourstats-state
+resource "aws_dynamodb_table" "hourstats_staging_state" {
+  name         = "hourstats-staging-state"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "runId"
+  range_key    = "postId"
+
+  attribute {
+    name = "runId"
+    type = "S"
+  }
+
+  attribute {
+    name = "postId"
+    type = "S"
+  }
+
+  attribute {
+    name = "status"
+    type = "S"
+  }
+
+  attribute {
+    name = "createdAt"
+    type = "S"
+  }
+
+  attribute {
+    name = "runIndex"
+    type = "S"
+  }
+
+  attribute {
+    name = "engagementScore"
+    type = "N"
+  }
+
+  global_secondary_index {
+    name            = "status-index"
+    hash_key        = "status"
+    range_key       = "createdAt"
+    projection_type = "ALL"
+  }
+
+  global_secondary_index {
+    name               = "posts-index"
+    hash_key           = "runId"
+    range_key          = "postId"
+    projection_type    = "INCLUDE"
+    non_key_attributes = ["post", "posts", "createdAt", "ttl"]
+  }
+
+  global_secondary_index {
+    name            = "runs-index"
+    hash_key        = "runId"
+    range_key       = "createdAt"
+    projection_type = "ALL"
+  }
+
+  global_secondary_index {
+    name            = "created-index"
+    hash_key        = "runIndex"
+    range_key       = "createdAt"
+    projection_type = "ALL"
+  }
+
+  global_secondary_index {
+    name            = "engagement-index"
+    hash_key        = "runId"
+    range_key       = "engagementScore"
+    projection_type = "ALL"
+  }
+
+  ttl {
+    attribute_name = "ttl"
+    enabled        = true
+  }
+
+  tags = {
+    Name        = "${var.function_name}-staging-state"
+    Environment = "staging"
+  }
+}
+
+# IAM Role for the staging Lambdas
+resource "aws_iam_role" "staging_lambda_role" {
+  name = "${var.function_name}-staging-lambda-role"
+
+  assume_role_policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Action = "sts:AssumeRole"
+        Effect = "Allow"
+        Principal = {
+          Service = "lambda.amazonaws.com"
+        }
+      }
+    ]
+  })
+
+  tags = {
+    Name        = "${var.function_name}-staging-lambda-role"
+    Environment = "staging"
+  }
+}
+
+# Read-only access to production's tables, full access to the staging state table
+resource "aws_iam_policy" "staging_lambda_policy" {
+  name        = "${var.function_name}-staging-lambda-policy"
+  description = "Policy for the staging Lambda functions"
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect = "Allow"
+        Action = [
+          "logs:CreateLogGroup",
+          "logs:CreateLogStream",
+          "logs:PutLogEvents"
+        ]
+        Resource = "arn:aws:logs:*:*:*"
+      },
+      {
+        Effect = "Allow"
+        Action = [
+          "dynamodb:GetItem",
+          "dynamodb:PutItem",
+          "dynamodb:Query",
+          "dynamodb:UpdateItem",
+          "dynamodb:BatchWriteItem",
+          "dynamodb:BatchGetItem",
+          "dynamodb:Scan"
+        ]
+        Resource = [
+          aws_dynamodb_table.hourstats_staging_state.arn,
+          "${aws_dynamodb_table.hourstats_staging_state.arn}/index/*"
+        ]
+      },
+      {
+        Effect = "Allow"
+        Action = [
+          "dynamodb:GetItem",
+          "dynamodb:BatchGetItem",
+          "dynamodb:Query",
+          "dynamodb:Scan"
+        ]
+        Resource = [
+          aws_dynamodb_table.sentiment_history.arn,
+          "${aws_dynamodb_table.sentiment_history.arn}/index/*",
+          aws_dynamodb_table.daily_sentiment.arn,
+          "${aws_dynamodb_table.daily_sentiment.arn}/index/*",
+          aws_dynamodb_table.blocklist.arn
+        ]
+      },
+      {
+        Effect = "Allow"
+        Action = [
+          "ssm:GetParameter",
+          "ssm:GetParameters"
+        ]
+        Resource = [
+          "arn:aws:ssm:${var.aws_region}:${data.aws_caller_identity.current.account_id}:parameter/hourstats/*"
+        ]
+      },
+      {
+        Effect = "Allow"
+        Action = [
+          "secretsmanager:GetSecretValue",
+          "secretsmanager:DescribeSecret"
+        ]
+        Resource = aws_secretsmanager_secret.bluesky.arn
+      },
+      {
+        # Reads text in images when /hourstats/settings/image_text is "ocr"
+        Effect = "Allow"
+        Action = [
+          "textract:DetectDocumentText"
+        ]
+        Resource = "*"
+      },
+      {
+        Effect = "Allow"
+        Action = [
+          "lambda:InvokeFunction"
+        ]
+        Resource = [
+          aws_lambda_function.hourstats_staging_fetcher.arn,
+          aws_lambda_function.hourstats_staging_processor.arn,
+          aws_lambda_function.hourstats_staging_sparkline_poster.arn
+        ]
+      }
+    ]
+  })
+
+  tags = {
+    Name        = "${var.function_name}-staging-lambda-policy"
+    Environment = "staging"
+  }
+}
+
+resource "aws_iam_role_policy_attachment" "staging_lambda_policy" {
+  role       = aws_iam_role.staging_lambda_role.name
+  policy_arn = aws_iam_policy.staging_lambda_policy.arn
+}
+
+resource "aws_iam_role_policy_attachment" "staging_xray_write" {
+  role       = aws_iam_role.staging_lambda_role.name
+  policy_arn = "arn:aws:iam::aws:policy/AWSXRayDaemonWriteAccess"
+}
+
+# Staging Orchestrator Lambda Function
+resource "aws_lambda_function" "hourstats_staging_orchestrator" {
+  filename         = "lambda-orchestrator.zip"
+  function_name    = "hourstats-staging-orchestrator"
+  role             = aws_iam_role.staging_lambda_role.arn
+  handler          = "bootstrap"
+  source_code_hash = filebase64sha256("lambda-orchestrator.zip")
+  runtime          = "provided.al2023"
+  timeout          = 900
+  memory_size      = 128
+
+  tracing_config {
+    mode = "Active"
+  }
+
+  environment {
+    variables = local.staging_environment
+  }
+
+  tags = {
+    Name        = "${var.function_name}-staging-orchestrator"
+    Environment = "staging"
+  }
+}
+
+# Staging Fetcher Lambda Function
+resource "aws_lambda_function" "hourstats_staging_fetcher" {
+  filename         = "lambda-fetcher.zip"
+  function_name    = "hourstats-staging-fetcher"
+  role             = aws_iam_role.staging_lambda_role.arn
+  handler          = "bootstrap"
+  source_code_hash = filebase64sha256("lambda-fetcher.zip")
+  runtime          = "provided.al2023"
+  timeout          = 900
+  memory_size      = 128
+
+  tracing_config {
+    mode = "Active"
+  }
+
+  environment {
+    variables = local.staging_environment
+  }
+
+  tags = {
+    Name        = "${var.function_name}-staging-fetcher"
+    Environment = "staging"
+  }
+}
+
+# Staging Processor Lambda Function; without REPORTS_BUCKET it writes no run reports
+resource "aws_lambda_function" "hourstats_staging_processor" {
+  filename         = "lambda-processor.zip"
+  function_name    = "hourstats-staging-processor"
+  role             = aws_iam_role.staging_lambda_role.arn
+  handler          = "bootstrap"
+  source_code_hash = filebase64sha256("lambda-processor.zip")
+  runtime          = "provided.al2023"
+  timeout          = 300
+  memory_size      = 128
+
+  tracing_config {
+    mode = "Active"
+  }
+
+  environment {
+    variables = local.staging_environment
+  }
+
+  tags = {
+    Name        = "${var.function_name}-staging-processor"
+    Environment = "staging"
+  }
+}
+
+# Staging Sparkline Poster Lambda Function
+resource "aws_lambda_function" "hourstats_staging_sparkline_poster" {
+  filename         = "lambda-sparkline-poster.zip"
+  function_name    = "hourstats-staging-sparkline-poster"
+  role             = aws_iam_role.staging_lambda_role.arn
+  handler          = "bootstrap"
+  source_code_hash = filebase64sha256("lambda-sparkline-poster.zip")
+  runtime          = "provided.al2023"
+  timeout          = 300
+  memory_size      = 256
+
+  tracing_config {
+    mode = "Active"
+  }
+
+  environment {
+    variables = merge(local.staging_environment, {
+      SENTIMENT_HISTORY_TABLE = aws_dynamodb_table.sentiment_history.name
+    })
+  }
+
+  tags = {
+    Name        = "${var.function_name}-staging-sparkline-poster"
+    Environment = "staging"
+  }
+}
+
+# EventBridge Rule running staging alongside production; disabled unless staging_enabled
+resource "aws_cloudwatch_event_rule" "staging_schedule" {
+  name                = "${var.function_name}-staging-schedule"
+  description         = "Trigger ${var.function_name} staging on schedule"
+  schedule_expression = var.schedule_expression
+  state               = var.staging_enabled ? "ENABLED" : "DISABLED"
+
+  tags = {
+    Name        = "${var.function_name}-staging-schedule"
+    Environment = "staging"
+  }
+}
+
+resource "aws_cloudwatch_event_target" "staging_target" {
+  rule      = aws_cloudwatch_event_rule.staging_schedule.name
+  target_id = "HourStatsStagingTarget"
+  arn       = aws_lambda_function.hourstats_staging_orchestrator.arn
+
+  input = jsonencode({
+    source                  = "aws.events"
+    time                    = "$.time"
+    analysisIntervalMinutes = var.analysis_interval_minutes
+    adaptiveInterval        = true
+  })
+}
+
+resource "aws_lambda_permission" "allow_eventbridge_staging_orchestrator" {
+  statement_id  = "AllowExecutionFromEventBridge"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.hourstats_staging_orchestrator.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.staging_schedule.arn
+}